@@ -39,10 +39,17 @@ import (
 
 	"github.com/muesli/beehive/api/context"
 	"github.com/muesli/beehive/api/resources/actions"
+	"github.com/muesli/beehive/api/resources/audit"
 	"github.com/muesli/beehive/api/resources/bees"
 	"github.com/muesli/beehive/api/resources/chains"
+	"github.com/muesli/beehive/api/resources/deadletters"
+	"github.com/muesli/beehive/api/resources/events"
+	"github.com/muesli/beehive/api/resources/filters"
 	"github.com/muesli/beehive/api/resources/hives"
 	"github.com/muesli/beehive/api/resources/logs"
+	"github.com/muesli/beehive/api/resources/status"
+	"github.com/muesli/beehive/api/resources/timers"
+	"github.com/muesli/beehive/api/resources/traces"
 	"github.com/muesli/beehive/app"
 )
 
@@ -91,6 +98,11 @@ func assetHandler(req *restful.Request, resp *restful.Response) {
 
 	subpath := req.PathParameter("subpath")
 	sourceFile, b, err := readAssetOrIndex(path.Join(rootdir, subpath))
+	if err != nil && rootdir == "./config" {
+		// the admin interface isn't installed, fall back to the built-in one
+		http.Redirect(resp.ResponseWriter, req.Request, "/ui/", http.StatusFound)
+		return
+	}
 	if err != nil {
 		log.Errorln("Failed reading", sourceFile)
 		http.Error(resp.ResponseWriter, "Failed reading file", http.StatusInternalServerError)
@@ -168,6 +180,14 @@ func Run() {
 	// to see what happens in the package, uncomment the following
 	// restful.TraceLogger(log.New(os.Stdout, "[restful] ", log.LstdFlags|log.Lshortfile))
 
+	server := &http.Server{Addr: bind, Handler: newContainer()}
+	go func() {
+		log.Fatal(server.ListenAndServe())
+	}()
+}
+
+// newContainer sets up the restful API container serving all resources
+func newContainer() *restful.Container {
 	// Setup web-service
 	smolderConfig := smolder.APIConfig{
 		BaseURL:    canonicalURL,
@@ -179,9 +199,17 @@ func Run() {
 
 	wsContainer := smolder.NewSmolderContainer(smolderConfig, nil, nil)
 	wsContainer.Router(restful.CurlyRouter{})
+	wsContainer.Filter(authFilter)
+	wsContainer.Filter(auditFilter)
+	wsContainer.Filter(profileFilter)
 	ws := new(restful.WebService)
 	ws.Route(ws.GET("/images/{subpath:*}").To(assetHandler))
 	ws.Route(ws.GET("/oauth2/{subpath:*}").To(oauth2Handler))
+	ws.Route(ws.GET("/metrics").To(metricsHandler))
+	ws.Route(ws.GET("/schema").To(schemaHandler))
+	ws.Route(ws.GET("/stream").To(streamHandler))
+	ws.Route(ws.GET("/ui").To(uiHandler))
+	ws.Route(ws.GET("/ui/{subpath:*}").To(uiHandler))
 	ws.Route(ws.GET("/{subpath:*}").To(assetHandler))
 	ws.Route(ws.GET("/").To(assetHandler))
 	wsContainer.Add(ws)
@@ -195,13 +223,17 @@ func Run() {
 		&bees.BeeResource{},
 		&chains.ChainResource{},
 		&actions.ActionResource{},
+		&filters.FilterResource{},
 		&logs.LogResource{},
+		&events.EventResource{},
+		&timers.TimerResource{},
+		&status.StatusResource{},
+		&audit.AuditResource{},
+		&traces.TraceResource{},
+		&deadletters.DeadLetterResource{},
 	)
 
-	server := &http.Server{Addr: bind, Handler: wsContainer}
-	go func() {
-		log.Fatal(server.ListenAndServe())
-	}()
+	return wsContainer
 }
 
 func init() {
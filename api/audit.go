@@ -0,0 +1,79 @@
+/*
+ *    Copyright (C) 2026 Christian Muehlhaeuser
+ *
+ *    This program is free software: you can redistribute it and/or modify
+ *    it under the terms of the GNU Affero General Public License as published
+ *    by the Free Software Foundation, either version 3 of the License, or
+ *    (at your option) any later version.
+ *
+ *    This program is distributed in the hope that it will be useful,
+ *    but WITHOUT ANY WARRANTY; without even the implied warranty of
+ *    MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ *    GNU Affero General Public License for more details.
+ *
+ *    You should have received a copy of the GNU Affero General Public License
+ *    along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ *    Authors:
+ *      Christian Muehlhaeuser <muesli@gmail.com>
+ */
+
+package api
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+
+	restful "github.com/emicklei/go-restful"
+
+	"github.com/muesli/beehive/api/context"
+	"github.com/muesli/beehive/bees"
+)
+
+// auditedResources are the API endpoints whose changes get recorded in the
+// audit log.
+var auditedResources = []string{"/v1/bees", "/v1/chains", "/v1/actions"}
+
+// auditFilter records all requests changing bees, chains or actions in the
+// audit log, see bees.AuditChange.
+func auditFilter(req *restful.Request, resp *restful.Response, chain *restful.FilterChain) {
+	chain.ProcessFilter(req, resp)
+
+	if req.Request.Method == http.MethodGet || req.Request.Method == http.MethodHead {
+		return
+	}
+	path := req.Request.URL.Path
+	audited := false
+	for _, prefix := range auditedResources {
+		if path == prefix || strings.HasPrefix(path, prefix+"/") {
+			audited = true
+			break
+		}
+	}
+	if !audited {
+		return
+	}
+
+	var err error
+	if status := resp.StatusCode(); status >= http.StatusBadRequest {
+		err = fmt.Errorf("Request failed with status %d", status)
+	}
+	bees.AuditScopedChange(context.Profile(req), requestActor(req), req.Request.Method, path, err)
+}
+
+// requestActor returns who made an API request: the name of the credential
+// it got authenticated with, or else its remote address.
+func requestActor(req *restful.Request) string {
+	if c := context.CredentialOf(req); c != nil {
+		return c.Name
+	}
+
+	r := req.Request
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
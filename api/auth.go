@@ -0,0 +1,54 @@
+/*
+ *    Copyright (C) 2026 Christian Muehlhaeuser
+ *
+ *    This program is free software: you can redistribute it and/or modify
+ *    it under the terms of the GNU Affero General Public License as published
+ *    by the Free Software Foundation, either version 3 of the License, or
+ *    (at your option) any later version.
+ *
+ *    This program is distributed in the hope that it will be useful,
+ *    but WITHOUT ANY WARRANTY; without even the implied warranty of
+ *    MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ *    GNU Affero General Public License for more details.
+ *
+ *    You should have received a copy of the GNU Affero General Public License
+ *    along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ *    Authors:
+ *      Christian Muehlhaeuser <muesli@gmail.com>
+ */
+
+package api
+
+import (
+	"net/http"
+	"strings"
+
+	restful "github.com/emicklei/go-restful"
+
+	"github.com/muesli/beehive/api/context"
+)
+
+// authFilter authenticates all requests once credentials are configured, see
+// context.SetCredentials, and rejects those their credential's role doesn't
+// permit. OAuth2 callbacks come from third parties and remain accessible.
+func authFilter(req *restful.Request, resp *restful.Response, chain *restful.FilterChain) {
+	if !context.AuthEnabled() || strings.HasPrefix(req.Request.URL.Path, "/oauth2/") {
+		chain.ProcessFilter(req, resp)
+		return
+	}
+
+	c := context.Authenticate(req)
+	if c == nil {
+		resp.AddHeader("WWW-Authenticate", `Basic realm="Beehive"`)
+		resp.WriteErrorString(http.StatusUnauthorized, "Authentication required")
+		return
+	}
+	if !c.Allows(req.Request.Method, req.Request.URL.Path) {
+		resp.WriteErrorString(http.StatusForbidden, "Access denied")
+		return
+	}
+
+	req.SetAttribute(context.CredentialAttribute, c)
+	chain.ProcessFilter(req, resp)
+}
@@ -0,0 +1,201 @@
+/*
+ *    Copyright (C) 2026 Christian Muehlhaeuser
+ *
+ *    This program is free software: you can redistribute it and/or modify
+ *    it under the terms of the GNU Affero General Public License as published
+ *    by the Free Software Foundation, either version 3 of the License, or
+ *    (at your option) any later version.
+ *
+ *    This program is distributed in the hope that it will be useful,
+ *    but WITHOUT ANY WARRANTY; without even the implied warranty of
+ *    MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ *    GNU Affero General Public License for more details.
+ *
+ *    You should have received a copy of the GNU Affero General Public License
+ *    along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ *    Authors:
+ *      Christian Muehlhaeuser <muesli@gmail.com>
+ */
+
+package context
+
+import (
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+
+	restful "github.com/emicklei/go-restful"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// Roles of API credentials.
+const (
+	// RoleAdmin grants full access, including changing the configuration.
+	RoleAdmin = "admin"
+	// RoleMonitor grants read-only access.
+	RoleMonitor = "monitor"
+	// RoleInject only grants injecting events, see Credential.Bees.
+	RoleInject = "inject"
+)
+
+// CredentialAttribute is the request attribute holding the Credential a
+// request got authenticated with.
+const CredentialAttribute = "credential"
+
+// A Credential grants access to the API, either with a Token sent as bearer
+// token or "accesstoken" query parameter, or with a Username and Password
+// sent using basic auth.
+type Credential struct {
+	Name     string
+	Token    string `json:",omitempty" yaml:",omitempty"`
+	Username string `json:",omitempty" yaml:",omitempty"`
+	// Password is either a bcrypt hash or the plain password.
+	Password string `json:",omitempty" yaml:",omitempty"`
+
+	// Role is one of the Role* constants.
+	Role string
+
+	// Bees, if set, restricts the events the credential may inject to those
+	// of the listed bees.
+	Bees []string `json:",omitempty" yaml:",omitempty"`
+}
+
+var (
+	credentials      []Credential
+	credentialsMutex sync.RWMutex
+)
+
+// SetCredentials replaces the credentials granting access to the API. Without
+// any credentials, the API is accessible without authentication.
+func SetCredentials(cs []Credential) error {
+	tokens := make(map[string]struct{})
+	users := make(map[string]struct{})
+	for _, c := range cs {
+		switch c.Role {
+		case RoleAdmin, RoleMonitor, RoleInject:
+		default:
+			return fmt.Errorf("Credential %s has an invalid role %q", c.Name, c.Role)
+		}
+
+		if len(c.Token) == 0 && (len(c.Username) == 0 || len(c.Password) == 0) {
+			return fmt.Errorf("Credential %s needs either a token or a username and password", c.Name)
+		}
+		if len(c.Token) > 0 {
+			if _, ok := tokens[c.Token]; ok {
+				return errors.New("Duplicate credential token")
+			}
+			tokens[c.Token] = struct{}{}
+		}
+		if len(c.Username) > 0 {
+			if _, ok := users[c.Username]; ok {
+				return fmt.Errorf("Duplicate credential username %s", c.Username)
+			}
+			users[c.Username] = struct{}{}
+		}
+	}
+
+	credentialsMutex.Lock()
+	defer credentialsMutex.Unlock()
+	credentials = append([]Credential(nil), cs...)
+	return nil
+}
+
+// AuthEnabled returns whether API requests need to be authenticated.
+func AuthEnabled() bool {
+	credentialsMutex.RLock()
+	defer credentialsMutex.RUnlock()
+
+	return len(credentials) > 0
+}
+
+// Authenticate returns the credential a request got sent with, or nil if it
+// doesn't match any.
+func Authenticate(request *restful.Request) *Credential {
+	if user, pass, ok := request.Request.BasicAuth(); ok {
+		return AuthenticateUser(user, pass)
+	}
+
+	return AuthenticateToken(AccessToken(request))
+}
+
+// AuthenticateUser returns the credential of a username and password, or nil
+// if they don't match any.
+func AuthenticateUser(user, pass string) *Credential {
+	credentialsMutex.RLock()
+	defer credentialsMutex.RUnlock()
+
+	for _, c := range credentials {
+		if len(c.Username) > 0 && c.Username == user && checkPassword(c.Password, pass) {
+			c := c
+			return &c
+		}
+	}
+	return nil
+}
+
+// AuthenticateToken returns the credential with a specific token, or nil if
+// it doesn't match any.
+func AuthenticateToken(t string) *Credential {
+	if len(t) == 0 {
+		return nil
+	}
+
+	credentialsMutex.RLock()
+	defer credentialsMutex.RUnlock()
+
+	for _, c := range credentials {
+		if len(c.Token) > 0 && subtle.ConstantTimeCompare([]byte(c.Token), []byte(t)) == 1 {
+			c := c
+			return &c
+		}
+	}
+	return nil
+}
+
+// checkPassword returns whether pass matches password, which is either a
+// bcrypt hash or a plain password.
+func checkPassword(password, pass string) bool {
+	if strings.HasPrefix(password, "$2") {
+		return bcrypt.CompareHashAndPassword([]byte(password), []byte(pass)) == nil
+	}
+	return subtle.ConstantTimeCompare([]byte(password), []byte(pass)) == 1
+}
+
+// CredentialOf returns the credential a request got authenticated with, or
+// nil if authentication is disabled.
+func CredentialOf(request *restful.Request) *Credential {
+	c, _ := request.Attribute(CredentialAttribute).(*Credential)
+	return c
+}
+
+// Allows returns whether the credential may send a request with method to
+// path.
+func (c *Credential) Allows(method, path string) bool {
+	switch c.Role {
+	case RoleAdmin:
+		return true
+	case RoleMonitor:
+		return method == http.MethodGet || method == http.MethodHead
+	case RoleInject:
+		return method == http.MethodPost && strings.TrimSuffix(path, "/") == "/v1/events"
+	}
+	return false
+}
+
+// AllowsBee returns whether the credential may inject events of the bee
+// identified by bee.
+func (c *Credential) AllowsBee(bee string) bool {
+	if len(c.Bees) == 0 {
+		return true
+	}
+	for _, b := range c.Bees {
+		if b == bee {
+			return true
+		}
+	}
+	return false
+}
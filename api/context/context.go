@@ -24,6 +24,7 @@ import (
 	"strings"
 
 	restful "github.com/emicklei/go-restful"
+	"github.com/muesli/beehive/bees"
 	"github.com/muesli/smolder"
 )
 
@@ -40,10 +41,33 @@ func (context *APIContext) NewAPIContext() smolder.APIContext {
 	return ctx
 }
 
-// Authentication parses the request for an access-/authtoken and returns the matching user
+// Authentication parses the request for an access-/authtoken and returns the matching credential
 func (context *APIContext) Authentication(request *restful.Request) (interface{}, error) {
-	//FIXME: implement this properly
+	if c := Authenticate(request); c != nil {
+		return c, nil
+	}
+	return nil, nil
+}
+
+// LogSummary logs out the current context stats
+func (context *APIContext) LogSummary() {
+}
+
+// ProfileHeader is the HTTP header selecting the profile a request operates
+// on. Alternatively the profile can be selected with the "profile" query
+// parameter.
+const ProfileHeader = "X-Beehive-Profile"
+
+// Profile returns the name of the profile selected by a request, if any.
+func Profile(request *restful.Request) string {
+	if p := request.QueryParameter("profile"); len(p) > 0 {
+		return p
+	}
+	return request.HeaderParameter(ProfileHeader)
+}
 
+// AccessToken returns the access token sent with a request, if any.
+func AccessToken(request *restful.Request) string {
 	t := request.QueryParameter("accesstoken")
 	if len(t) == 0 {
 		t = request.HeaderParameter("authorization")
@@ -51,10 +75,38 @@ func (context *APIContext) Authentication(request *restful.Request) (interface{}
 			t = strings.TrimSpace(strings.Split(t, " ")[1])
 		}
 	}
+	return t
+}
 
-	return nil, nil // context.GetUserByAccessToken(t)
+// Visible returns whether a request may see the bees and chains of scope.
+// Requests selecting a profile only see that profile's, all others see the
+// global ones and those of profiles not protected by a token.
+func Visible(request *restful.Request, scope string) bool {
+	if p := Profile(request); len(p) > 0 {
+		return scope == p
+	}
+	if len(scope) == 0 {
+		return true
+	}
+	p := bees.GetProfile(scope)
+	return p == nil || len(p.Token) == 0
 }
 
-// LogSummary logs out the current context stats
-func (context *APIContext) LogSummary() {
+// VisibleBee returns whether a request may see the bee ref refers to, see
+// Visible. Refs to unknown bees are judged by the scope they name.
+func VisibleBee(request *restful.Request, ref string) bool {
+	scope, _ := bees.SplitBeeName(ref)
+	if bee := bees.GetBee(ref); bee != nil {
+		scope, _ = bees.SplitBeeName((*bee).Name())
+	}
+	return Visible(request, scope)
+}
+
+// VisibleChain returns whether a request may see the chain called name, see
+// Visible. Unknown chains, e.g. deleted ones, count as global.
+func VisibleChain(request *restful.Request, name string) bool {
+	if c := bees.GetChain(name); c != nil {
+		return Visible(request, c.Scope)
+	}
+	return Visible(request, "")
 }
@@ -0,0 +1,145 @@
+/*
+ *    Copyright (C) 2026 Christian Muehlhaeuser
+ *
+ *    This program is free software: you can redistribute it and/or modify
+ *    it under the terms of the GNU Affero General Public License as published
+ *    by the Free Software Foundation, either version 3 of the License, or
+ *    (at your option) any later version.
+ *
+ *    This program is distributed in the hope that it will be useful,
+ *    but WITHOUT ANY WARRANTY; without even the implied warranty of
+ *    MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ *    GNU Affero General Public License for more details.
+ *
+ *    You should have received a copy of the GNU Affero General Public License
+ *    along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ *    Authors:
+ *      Christian Muehlhaeuser <muesli@gmail.com>
+ */
+
+package api
+
+import (
+	restful "github.com/emicklei/go-restful"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+
+	"github.com/muesli/beehive/api/context"
+	"github.com/muesli/beehive/bees"
+)
+
+var (
+	beeLabels   = []string{"bee"}
+	chainLabels = []string{"chain"}
+
+	eventsReceivedDesc = prometheus.NewDesc("beehive_bee_events_received_total",
+		"Number of events received from a bee.", beeLabels, nil)
+	beeActionsDesc = prometheus.NewDesc("beehive_bee_actions_executed_total",
+		"Number of actions executed by a bee.", beeLabels, nil)
+	beeActionErrorsDesc = prometheus.NewDesc("beehive_bee_action_errors_total",
+		"Number of failed actions of a bee.", beeLabels, nil)
+	beePanicsDesc = prometheus.NewDesc("beehive_bee_panics_total",
+		"Number of times a bee panicked.", beeLabels, nil)
+	beeRestartsDesc = prometheus.NewDesc("beehive_bee_restarts_total",
+		"Number of times a bee got restarted after crashing.", beeLabels, nil)
+	beeActionTimeoutsDesc = prometheus.NewDesc("beehive_bee_action_timeouts_total",
+		"Number of actions of a bee abandoned after their timeout.", beeLabels, nil)
+	beeActionSecondsDesc = prometheus.NewDesc("beehive_bee_action_seconds_total",
+		"Time spent executing actions of a bee.", beeLabels, nil)
+
+	chainTriggeredDesc = prometheus.NewDesc("beehive_chain_triggered_total",
+		"Number of times a chain got executed.", chainLabels, nil)
+	chainActionsDesc = prometheus.NewDesc("beehive_chain_actions_executed_total",
+		"Number of actions executed by a chain.", chainLabels, nil)
+	chainFailuresDesc = prometheus.NewDesc("beehive_chain_failures_total",
+		"Number of failed executions of a chain.", chainLabels, nil)
+	chainFilteredDesc = prometheus.NewDesc("beehive_chain_filtered_total",
+		"Number of matching events rejected by a chain's filters.", chainLabels, nil)
+
+	queueLengthDesc = prometheus.NewDesc("beehive_event_queue_length",
+		"Number of events waiting to be handled.", nil, nil)
+	queueDroppedDesc = prometheus.NewDesc("beehive_event_queue_dropped_total",
+		"Number of events dropped due to a full event queue.", nil, nil)
+	queueLaneLengthDesc = prometheus.NewDesc("beehive_event_queue_lane_length",
+		"Number of events of a priority waiting to be handled.", []string{"lane"}, nil)
+	queueSaturatedDesc = prometheus.NewDesc("beehive_event_queue_saturated_total",
+		"Number of times the event queue ran full.", nil, nil)
+	chainWorkersDesc = prometheus.NewDesc("beehive_chain_workers",
+		"Number of goroutines executing chains.", nil, nil)
+	queuedChainsDesc = prometheus.NewDesc("beehive_queued_chains",
+		"Number of events waiting for a free chain worker.", nil, nil)
+	inFlightActionsDesc = prometheus.NewDesc("beehive_inflight_actions",
+		"Number of actions currently being executed.", nil, nil)
+)
+
+// hiveCollector exports the statistics of the hive's bees and chains to
+// Prometheus, limited to the bees and chains visible to request.
+type hiveCollector struct {
+	request *restful.Request
+}
+
+// Describe sends the descriptors of all metrics the collector exports.
+func (hiveCollector) Describe(ch chan<- *prometheus.Desc) {
+	for _, d := range []*prometheus.Desc{
+		eventsReceivedDesc, beeActionsDesc, beeActionErrorsDesc, beePanicsDesc, beeRestartsDesc,
+		beeActionTimeoutsDesc, beeActionSecondsDesc,
+		chainTriggeredDesc, chainActionsDesc, chainFailuresDesc, chainFilteredDesc,
+		queueLengthDesc, queueDroppedDesc, queueLaneLengthDesc, queueSaturatedDesc, chainWorkersDesc, queuedChainsDesc, inFlightActionsDesc,
+	} {
+		ch <- d
+	}
+}
+
+// Collect sends the current values of all metrics.
+func (c hiveCollector) Collect(ch chan<- prometheus.Metric) {
+	counter := func(d *prometheus.Desc, v int64, labels ...string) {
+		ch <- prometheus.MustNewConstMetric(d, prometheus.CounterValue, float64(v), labels...)
+	}
+	gauge := func(d *prometheus.Desc, v int64) {
+		ch <- prometheus.MustNewConstMetric(d, prometheus.GaugeValue, float64(v))
+	}
+
+	for name, s := range bees.GetAllStats() {
+		if !context.VisibleBee(c.request, name) {
+			continue
+		}
+		counter(eventsReceivedDesc, s.EventsReceived, name)
+		counter(beeActionsDesc, s.ActionsExecuted, name)
+		counter(beeActionErrorsDesc, s.ActionErrors, name)
+		counter(beePanicsDesc, s.Panics, name)
+		counter(beeRestartsDesc, s.Restarts, name)
+		counter(beeActionTimeoutsDesc, s.ActionTimeouts, name)
+		ch <- prometheus.MustNewConstMetric(beeActionSecondsDesc, prometheus.CounterValue, s.ActionTime.Seconds(), name)
+	}
+	for name, s := range bees.AllChainStats() {
+		if !context.VisibleChain(c.request, name) {
+			continue
+		}
+		counter(chainTriggeredDesc, s.Triggered, name)
+		counter(chainActionsDesc, s.ActionsExecuted, name)
+		counter(chainFailuresDesc, s.ActionErrors, name)
+		counter(chainFilteredDesc, s.FilteredOut, name)
+	}
+
+	q := bees.EventQueueStats()
+	gauge(queueLengthDesc, int64(q.Length))
+	counter(queueDroppedDesc, q.Dropped)
+	counter(queueSaturatedDesc, q.Saturated)
+	for lane, n := range q.Lanes {
+		ch <- prometheus.MustNewConstMetric(queueLaneLengthDesc, prometheus.GaugeValue, float64(n), lane)
+	}
+
+	cs := bees.ConcurrencyStats()
+	gauge(chainWorkersDesc, cs.ChainWorkers)
+	gauge(queuedChainsDesc, int64(cs.QueuedChains))
+	gauge(inFlightActionsDesc, cs.InFlightActions)
+}
+
+// metricsHandler exports the metrics of the bees and chains visible to the
+// request.
+func metricsHandler(req *restful.Request, resp *restful.Response) {
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(hiveCollector{request: req})
+	promhttp.HandlerFor(registry, promhttp.HandlerOpts{}).ServeHTTP(resp.ResponseWriter, req.Request)
+}
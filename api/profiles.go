@@ -0,0 +1,58 @@
+/*
+ *    Copyright (C) 2026 Christian Muehlhaeuser
+ *
+ *    This program is free software: you can redistribute it and/or modify
+ *    it under the terms of the GNU Affero General Public License as published
+ *    by the Free Software Foundation, either version 3 of the License, or
+ *    (at your option) any later version.
+ *
+ *    This program is distributed in the hope that it will be useful,
+ *    but WITHOUT ANY WARRANTY; without even the implied warranty of
+ *    MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ *    GNU Affero General Public License for more details.
+ *
+ *    You should have received a copy of the GNU Affero General Public License
+ *    along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ *    Authors:
+ *      Christian Muehlhaeuser <muesli@gmail.com>
+ */
+
+package api
+
+import (
+	"crypto/subtle"
+	"net/http"
+
+	restful "github.com/emicklei/go-restful"
+
+	"github.com/muesli/beehive/api/context"
+	"github.com/muesli/beehive/bees"
+)
+
+// profileFilter rejects requests selecting an unknown profile, or one
+// protected by a token they don't present, see context.Profile. Requests
+// authenticated as admin may access all profiles.
+func profileFilter(req *restful.Request, resp *restful.Response, chain *restful.FilterChain) {
+	name := context.Profile(req)
+	if len(name) == 0 {
+		chain.ProcessFilter(req, resp)
+		return
+	}
+
+	p := bees.GetProfile(name)
+	if p == nil {
+		resp.WriteErrorString(http.StatusNotFound, "Unknown profile")
+		return
+	}
+	if c := context.CredentialOf(req); c != nil && c.Role == context.RoleAdmin {
+		chain.ProcessFilter(req, resp)
+		return
+	}
+	if len(p.Token) > 0 && subtle.ConstantTimeCompare([]byte(context.AccessToken(req)), []byte(p.Token)) != 1 {
+		resp.WriteErrorString(http.StatusUnauthorized, "Invalid access token for profile")
+		return
+	}
+
+	chain.ProcessFilter(req, resp)
+}
@@ -0,0 +1,209 @@
+package api
+
+import (
+	"bufio"
+	gocontext "context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/muesli/beehive/api/context"
+	bee "github.com/muesli/beehive/bees"
+)
+
+// serve sends a request to the API and returns the recorded response. A
+// non-empty profile gets selected along with its token.
+func serve(method, path, profile, token, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	if len(profile) > 0 {
+		req.Header.Set(context.ProfileHeader, profile)
+	}
+	if len(token) > 0 {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	rec := httptest.NewRecorder()
+	newContainer().ServeHTTP(rec, req)
+	return rec
+}
+
+// actionIDs returns the IDs of the actions listed in a response.
+func actionIDs(t *testing.T, rec *httptest.ResponseRecorder) []string {
+	var resp struct {
+		Actions []struct {
+			ID string `json:"id"`
+		} `json:"actions"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("Failed to decode response %q: %v", rec.Body.String(), err)
+	}
+
+	ids := []string{}
+	for _, a := range resp.Actions {
+		ids = append(ids, a.ID)
+	}
+	return ids
+}
+
+func TestProfileIsolation(t *testing.T) {
+	defer bee.SetProfiles(nil)
+	if err := bee.SetProfiles([]bee.Profile{{Name: "work", Token: "secret"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	oldActions := bee.GetActions()
+	defer bee.SetActions(oldActions)
+	bee.SetActions([]bee.Action{
+		{ID: "global-post", Bee: "irc", Name: "post"},
+		{ID: "work-send", Bee: "work/mailer", Name: "send"},
+	})
+
+	if ids := actionIDs(t, serve("GET", "/v1/actions", "", "", "")); len(ids) != 1 || ids[0] != "global-post" {
+		t.Errorf("Expected only the global action, got %v", ids)
+	}
+	if ids := actionIDs(t, serve("GET", "/v1/actions", "work", "secret", "")); len(ids) != 1 || ids[0] != "work-send" {
+		t.Errorf("Expected only the profile's action, got %v", ids)
+	}
+	if rec := serve("GET", "/v1/actions", "work", "", ""); rec.Code != http.StatusUnauthorized {
+		t.Errorf("Expected the profile to require its token, got status %d", rec.Code)
+	}
+
+	for _, tt := range []struct {
+		method string
+		path   string
+		body   string
+		status int
+	}{
+		{"GET", "/v1/actions/work-send", "", http.StatusNotFound},
+		{"PUT", "/v1/actions/work-send", `{"action":{"bee":"work/mailer","name":"spam"}}`, http.StatusNotFound},
+		{"PUT", "/v1/actions/global-post", `{"action":{"bee":"work/mailer","name":"send"}}`, http.StatusNotFound},
+		{"DELETE", "/v1/actions/work-send", "", http.StatusNotFound},
+		{"POST", "/v1/actions", `{"action":{"bee":"work/mailer","name":"send"}}`, http.StatusForbidden},
+		{"POST", "/v1/events", `{"event":{"bee":"work/mailer","name":"message"}}`, http.StatusForbidden},
+	} {
+		if rec := serve(tt.method, tt.path, "", "", tt.body); rec.Code != tt.status {
+			t.Errorf("%s %s: expected status %d, got %d: %s", tt.method, tt.path, tt.status, rec.Code, rec.Body.String())
+		}
+	}
+
+	if a := bee.GetAction("work-send"); a == nil || a.Name != "send" {
+		t.Errorf("Expected the profile's action to be left alone, got %+v", a)
+	}
+	if a := bee.GetAction("global-post"); a == nil || a.Bee != "irc" {
+		t.Errorf("Expected the global action to be left alone, got %+v", a)
+	}
+	if n := len(bee.GetActions()); n != 2 {
+		t.Errorf("Expected no actions to be created, got %d actions", n)
+	}
+
+	if rec := serve("DELETE", "/v1/actions/work-send", "work", "secret", ""); rec.Code != http.StatusOK {
+		t.Errorf("Expected the profile to delete its action, got status %d", rec.Code)
+	}
+	if bee.GetAction("work-send") != nil {
+		t.Error("Expected the profile's action to be deleted")
+	}
+}
+
+func TestProfileLogs(t *testing.T) {
+	defer bee.SetProfiles(nil)
+	if err := bee.SetProfiles([]bee.Profile{{Name: "work", Token: "secret"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	bee.Log("irc", "Connected", bee.LogInfo)
+	bee.Log("work/mailer", "Sent the quarterly report", bee.LogInfo)
+
+	logBees := func(rec *httptest.ResponseRecorder) map[string]bool {
+		var resp struct {
+			Logs []struct {
+				Bee string `json:"bee"`
+			} `json:"logs"`
+		}
+		if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("Failed to decode response %q: %v", rec.Body.String(), err)
+		}
+
+		bs := make(map[string]bool)
+		for _, l := range resp.Logs {
+			bs[l.Bee] = true
+		}
+		return bs
+	}
+
+	if bs := logBees(serve("GET", "/v1/logs", "", "", "")); !bs["irc"] || bs["work/mailer"] {
+		t.Errorf("Expected only the global bee's logs, got logs of %v", bs)
+	}
+	if bs := logBees(serve("GET", "/v1/logs", "work", "secret", "")); bs["irc"] || !bs["work/mailer"] {
+		t.Errorf("Expected only the profile's logs, got logs of %v", bs)
+	}
+}
+
+func TestProfileStream(t *testing.T) {
+	defer bee.SetProfiles(nil)
+	if err := bee.SetProfiles([]bee.Profile{{Name: "work", Token: "secret"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	bee.StartBees(nil)
+	defer bee.StopBees()
+
+	srv := httptest.NewServer(newContainer())
+	defer srv.Close()
+	ctx, cancel := gocontext.WithTimeout(gocontext.Background(), 5*time.Second)
+	defer cancel()
+
+	// firstEvent opens the stream and returns a channel receiving the bee of
+	// the first event streamed
+	firstEvent := func(profile, token string) <-chan string {
+		req, _ := http.NewRequest("GET", srv.URL+"/stream?types=event", nil)
+		req = req.WithContext(ctx)
+		if len(profile) > 0 {
+			req.Header.Set(context.ProfileHeader, profile)
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		ch := make(chan string, 1)
+		go func() {
+			defer resp.Body.Close()
+			defer close(ch)
+
+			scanner := bufio.NewScanner(resp.Body)
+			for scanner.Scan() {
+				if !strings.HasPrefix(scanner.Text(), "data: ") {
+					continue
+				}
+				var msg struct {
+					Event bee.Event `json:"event"`
+				}
+				if err := json.Unmarshal([]byte(strings.TrimPrefix(scanner.Text(), "data: ")), &msg); err == nil {
+					ch <- msg.Event.Bee
+					return
+				}
+			}
+		}()
+		return ch
+	}
+	global := firstEvent("", "")
+	scoped := firstEvent("work", "secret")
+
+	for _, name := range []string{"irc", "work/mailer"} {
+		if _, err := bee.InjectEvent(ctx, bee.Event{Bee: name, Name: "message"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if b := <-global; b != "irc" {
+		t.Errorf("Expected the global caller to only see the global bee's event, got an event of %q", b)
+	}
+	if b := <-scoped; b != "work/mailer" {
+		t.Errorf("Expected the profile to only see its bee's event, got an event of %q", b)
+	}
+}
@@ -31,9 +31,11 @@ type ActionResource struct {
 }
 
 var (
-	_ smolder.GetIDSupported = &ActionResource{}
-	_ smolder.GetSupported   = &ActionResource{}
-	_ smolder.PostSupported  = &ActionResource{}
+	_ smolder.GetIDSupported  = &ActionResource{}
+	_ smolder.GetSupported    = &ActionResource{}
+	_ smolder.PostSupported   = &ActionResource{}
+	_ smolder.PutSupported    = &ActionResource{}
+	_ smolder.DeleteSupported = &ActionResource{}
 )
 
 // Register this resource with the container to setup all the routes
@@ -0,0 +1,61 @@
+/*
+ *    Copyright (C) 2026 Christian Muehlhaeuser
+ *
+ *    This program is free software: you can redistribute it and/or modify
+ *    it under the terms of the GNU Affero General Public License as published
+ *    by the Free Software Foundation, either version 3 of the License, or
+ *    (at your option) any later version.
+ *
+ *    This program is distributed in the hope that it will be useful,
+ *    but WITHOUT ANY WARRANTY; without even the implied warranty of
+ *    MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ *    GNU Affero General Public License for more details.
+ *
+ *    You should have received a copy of the GNU Affero General Public License
+ *    along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ *    Authors:
+ *      Christian Muehlhaeuser <muesli@gmail.com>
+ */
+
+package actions
+
+import (
+	"github.com/emicklei/go-restful"
+	apicontext "github.com/muesli/beehive/api/context"
+	"github.com/muesli/beehive/bees"
+	"github.com/muesli/smolder"
+)
+
+// DeleteAuthRequired returns true because all requests need authentication
+func (r *ActionResource) DeleteAuthRequired() bool {
+	return false
+}
+
+// DeleteDoc returns the description of this API endpoint
+func (r *ActionResource) DeleteDoc() string {
+	return "delete an action"
+}
+
+// DeleteParams returns the parameters supported by this API endpoint
+func (r *ActionResource) DeleteParams() []*restful.Parameter {
+	return nil
+}
+
+// Delete processes an incoming DELETE request
+func (r *ActionResource) Delete(context smolder.APIContext, request *restful.Request, response *restful.Response) {
+	resp := ActionResponse{}
+	resp.Init(context)
+
+	id := request.PathParameter("action-id")
+	if action := bees.GetAction(id); action == nil || !apicontext.VisibleBee(request, action.Bee) {
+		r.NotFound(request, response)
+		return
+	}
+
+	if bees.RemoveAction(id) {
+		resp.Send(response)
+	} else {
+		r.NotFound(request, response)
+	}
+}
@@ -21,6 +21,7 @@
 package actions
 
 import (
+	apicontext "github.com/muesli/beehive/api/context"
 	"github.com/muesli/beehive/bees"
 
 	"github.com/emicklei/go-restful"
@@ -57,7 +58,7 @@ func (r *ActionResource) GetByIDs(ctx smolder.APIContext, request *restful.Reque
 
 	for _, id := range ids {
 		action := bees.GetAction(id)
-		if action == nil {
+		if action == nil || !apicontext.VisibleBee(request, action.Bee) {
 			r.NotFound(request, response)
 			return
 		}
@@ -71,7 +72,12 @@ func (r *ActionResource) GetByIDs(ctx smolder.APIContext, request *restful.Reque
 // Get sends out items matching the query parameters
 func (r *ActionResource) Get(ctx smolder.APIContext, request *restful.Request, response *restful.Response, params map[string][]string) {
 	//	ctxapi := ctx.(*context.APIContext)
-	actions := bees.GetActions()
+	actions := []bees.Action{}
+	for _, action := range bees.GetActions() {
+		if apicontext.VisibleBee(request, action.Bee) {
+			actions = append(actions, action)
+		}
+	}
 	if len(actions) == 0 {
 		r.NotFound(request, response)
 		return
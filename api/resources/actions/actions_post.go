@@ -21,7 +21,11 @@
 package actions
 
 import (
+	"errors"
+	"net/http"
+
 	"github.com/emicklei/go-restful"
+	apicontext "github.com/muesli/beehive/api/context"
 	"github.com/muesli/beehive/bees"
 	"github.com/muesli/smolder"
 )
@@ -56,6 +60,14 @@ func (r *ActionResource) Post(context smolder.APIContext, data interface{}, requ
 	resp.Init(context)
 
 	pps := data.(*ActionPostStruct)
+	if !apicontext.VisibleBee(request, pps.Action.Bee) {
+		smolder.ErrorResponseHandler(request, response, nil, smolder.NewErrorResponse(
+			http.StatusForbidden,
+			errors.New("Not allowed to create actions for this bee"),
+			"ActionResource POST"))
+		return
+	}
+
 	action := bees.Action{
 		ID:      bees.UUID(),
 		Bee:     pps.Action.Bee,
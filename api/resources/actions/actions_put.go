@@ -0,0 +1,68 @@
+/*
+ *    Copyright (C) 2026 Christian Muehlhaeuser
+ *
+ *    This program is free software: you can redistribute it and/or modify
+ *    it under the terms of the GNU Affero General Public License as published
+ *    by the Free Software Foundation, either version 3 of the License, or
+ *    (at your option) any later version.
+ *
+ *    This program is distributed in the hope that it will be useful,
+ *    but WITHOUT ANY WARRANTY; without even the implied warranty of
+ *    MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ *    GNU Affero General Public License for more details.
+ *
+ *    You should have received a copy of the GNU Affero General Public License
+ *    along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ *    Authors:
+ *      Christian Muehlhaeuser <muesli@gmail.com>
+ */
+
+package actions
+
+import (
+	"github.com/emicklei/go-restful"
+	apicontext "github.com/muesli/beehive/api/context"
+	"github.com/muesli/beehive/bees"
+	"github.com/muesli/smolder"
+)
+
+// PutAuthRequired returns true because all requests need authentication
+func (r *ActionResource) PutAuthRequired() bool {
+	return false
+}
+
+// PutDoc returns the description of this API endpoint
+func (r *ActionResource) PutDoc() string {
+	return "update an existing action"
+}
+
+// PutParams returns the parameters supported by this API endpoint
+func (r *ActionResource) PutParams() []*restful.Parameter {
+	return nil
+}
+
+// Put processes an incoming PUT (update) request
+func (r *ActionResource) Put(context smolder.APIContext, data interface{}, request *restful.Request, response *restful.Response) {
+	resp := ActionResponse{}
+	resp.Init(context)
+
+	pps := data.(*ActionPostStruct)
+	id := request.PathParameter("action-id")
+	action := bees.GetAction(id)
+	if action == nil || !apicontext.VisibleBee(request, action.Bee) || !apicontext.VisibleBee(request, pps.Action.Bee) {
+		r.NotFound(request, response)
+		return
+	}
+
+	action.Bee = pps.Action.Bee
+	action.Name = pps.Action.Name
+	action.Options = pps.Action.Options
+	if !bees.UpdateAction(*action) {
+		r.NotFound(request, response)
+		return
+	}
+
+	resp.AddAction(action)
+	resp.Send(response)
+}
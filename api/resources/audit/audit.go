@@ -0,0 +1,53 @@
+/*
+ *    Copyright (C) 2026 Christian Muehlhaeuser
+ *
+ *    This program is free software: you can redistribute it and/or modify
+ *    it under the terms of the GNU Affero General Public License as published
+ *    by the Free Software Foundation, either version 3 of the License, or
+ *    (at your option) any later version.
+ *
+ *    This program is distributed in the hope that it will be useful,
+ *    but WITHOUT ANY WARRANTY; without even the implied warranty of
+ *    MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ *    GNU Affero General Public License for more details.
+ *
+ *    You should have received a copy of the GNU Affero General Public License
+ *    along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ *    Authors:
+ *      Christian Muehlhaeuser <muesli@gmail.com>
+ */
+
+package audit
+
+import (
+	"github.com/emicklei/go-restful"
+	"github.com/muesli/smolder"
+)
+
+// AuditResource is the resource responsible for /audit
+type AuditResource struct {
+	smolder.Resource
+}
+
+var (
+	_ smolder.GetSupported = &AuditResource{}
+)
+
+// Register this resource with the container to setup all the routes
+func (r *AuditResource) Register(container *restful.Container, config smolder.APIConfig, context smolder.APIContextFactory) {
+	r.Name = "AuditResource"
+	r.TypeName = "audit"
+	r.Endpoint = "audit"
+	r.Doc = "Query the audit log"
+
+	r.Config = config
+	r.Context = context
+
+	r.Init(container, r)
+}
+
+// Returns returns the model that will be returned
+func (r *AuditResource) Returns() interface{} {
+	return AuditResponse{}
+}
@@ -0,0 +1,76 @@
+/*
+ *    Copyright (C) 2026 Christian Muehlhaeuser
+ *
+ *    This program is free software: you can redistribute it and/or modify
+ *    it under the terms of the GNU Affero General Public License as published
+ *    by the Free Software Foundation, either version 3 of the License, or
+ *    (at your option) any later version.
+ *
+ *    This program is distributed in the hope that it will be useful,
+ *    but WITHOUT ANY WARRANTY; without even the implied warranty of
+ *    MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ *    GNU Affero General Public License for more details.
+ *
+ *    You should have received a copy of the GNU Affero General Public License
+ *    along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ *    Authors:
+ *      Christian Muehlhaeuser <muesli@gmail.com>
+ */
+
+package audit
+
+import (
+	"errors"
+	"time"
+
+	"github.com/emicklei/go-restful"
+	apicontext "github.com/muesli/beehive/api/context"
+	"github.com/muesli/beehive/bees"
+	"github.com/muesli/smolder"
+)
+
+// GetAuthRequired returns true because all requests need authentication
+func (r *AuditResource) GetAuthRequired() bool {
+	return false
+}
+
+// GetDoc returns the description of this API endpoint
+func (r *AuditResource) GetDoc() string {
+	return "retrieve audit log entries"
+}
+
+// GetParams returns the parameters supported by this API endpoint
+func (r *AuditResource) GetParams() []*restful.Parameter {
+	params := []*restful.Parameter{}
+	params = append(params, restful.QueryParameter("kind", "kind of entries, either config or action").DataType("string"))
+	params = append(params, restful.QueryParameter("since", "only entries recorded after this RFC 3339 timestamp").DataType("string"))
+
+	return params
+}
+
+// Get sends out items matching the query parameters
+func (r *AuditResource) Get(ctx smolder.APIContext, request *restful.Request, response *restful.Response, params map[string][]string) {
+	var since time.Time
+	if s := request.QueryParameter("since"); len(s) > 0 {
+		var err error
+		if since, err = time.Parse(time.RFC3339, s); err != nil {
+			smolder.ErrorResponseHandler(request, response, err, smolder.NewErrorResponse(
+				422, // Go 1.7+: http.StatusUnprocessableEntity,
+				errors.New("Invalid since timestamp"),
+				"AuditResource GET"))
+			return
+		}
+	}
+
+	resp := AuditResponse{}
+	resp.Init(ctx)
+
+	for _, entry := range bees.AuditEntries(request.QueryParameter("kind"), since) {
+		if apicontext.Visible(request, entry.Scope) {
+			resp.AddEntry(entry)
+		}
+	}
+
+	resp.Send(response)
+}
@@ -0,0 +1,89 @@
+/*
+ *    Copyright (C) 2026 Christian Muehlhaeuser
+ *
+ *    This program is free software: you can redistribute it and/or modify
+ *    it under the terms of the GNU Affero General Public License as published
+ *    by the Free Software Foundation, either version 3 of the License, or
+ *    (at your option) any later version.
+ *
+ *    This program is distributed in the hope that it will be useful,
+ *    but WITHOUT ANY WARRANTY; without even the implied warranty of
+ *    MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ *    GNU Affero General Public License for more details.
+ *
+ *    You should have received a copy of the GNU Affero General Public License
+ *    along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ *    Authors:
+ *      Christian Muehlhaeuser <muesli@gmail.com>
+ */
+
+package audit
+
+import (
+	"time"
+
+	"github.com/muesli/beehive/bees"
+
+	"github.com/muesli/smolder"
+)
+
+// AuditResponse is the common response to 'audit' requests
+type AuditResponse struct {
+	smolder.Response
+
+	Entries []auditInfoResponse `json:"audit,omitempty"`
+	entries []bees.AuditEntry
+}
+
+type auditInfoResponse struct {
+	ID        string            `json:"id"`
+	Timestamp time.Time         `json:"timestamp"`
+	Kind      string            `json:"kind"`
+	Actor     string            `json:"actor"`
+	Operation string            `json:"operation"`
+	Target    string            `json:"target"`
+	Options   bees.Placeholders `json:"options,omitempty"`
+	Error     string            `json:"error,omitempty"`
+	Duration  time.Duration     `json:"duration,omitempty"`
+}
+
+// Init a new response
+func (r *AuditResponse) Init(context smolder.APIContext) {
+	r.Parent = r
+	r.Context = context
+
+	r.Entries = []auditInfoResponse{}
+}
+
+// AddEntry adds an audit entry to the response
+func (r *AuditResponse) AddEntry(entry bees.AuditEntry) {
+	r.entries = append(r.entries, entry)
+	r.Entries = append(r.Entries, prepareAuditResponse(r.Context, entry))
+}
+
+// EmptyResponse returns an empty API response for this endpoint if there's no data to respond with
+func (r *AuditResponse) EmptyResponse() interface{} {
+	if len(r.entries) == 0 {
+		var out struct {
+			Entries interface{} `json:"audit"`
+		}
+		out.Entries = []auditInfoResponse{}
+		return out
+	}
+	return nil
+}
+
+func prepareAuditResponse(context smolder.APIContext, entry bees.AuditEntry) auditInfoResponse {
+	return auditInfoResponse{
+		ID:        entry.ID,
+		Timestamp: entry.Time,
+		Kind:      entry.Kind,
+		Actor:     entry.Actor,
+		Operation: entry.Operation,
+		Target:    entry.Target,
+		Options:   entry.Options,
+		Error:     entry.Error,
+		Duration:  entry.Duration,
+	}
+}
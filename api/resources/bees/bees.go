@@ -21,8 +21,13 @@
 package bees
 
 import (
+	"errors"
+
 	"github.com/emicklei/go-restful"
 	"github.com/muesli/smolder"
+
+	apicontext "github.com/muesli/beehive/api/context"
+	"github.com/muesli/beehive/bees"
 )
 
 // BeeResource is the resource responsible for /bees
@@ -67,3 +72,36 @@ func (r *BeeResource) Validate(context smolder.APIContext, data interface{}, req
 	// FIXME
 	return nil
 }
+
+// optionsErrorResponse turns an error creating or updating a bee into an
+// error response. Failed option validations result in one error for each
+// problem, with problems of single options pointing at them.
+func optionsErrorResponse(err error, context string) *smolder.ErrorResponse {
+	var ve *bees.ValidationError
+	if !errors.As(err, &ve) {
+		return smolder.NewErrorResponse(
+			422, // Go 1.7+: http.StatusUnprocessableEntity,
+			err,
+			context)
+	}
+
+	resp := &smolder.ErrorResponse{}
+	for _, e := range ve.Errors {
+		r := smolder.NewErrorResponse(
+			422, // Go 1.7+: http.StatusUnprocessableEntity,
+			e,
+			context)
+		var oe *bees.OptionError
+		if errors.As(e, &oe) {
+			r.Err[0].Source.Pointer = "/bee/options/" + oe.Option
+		}
+		resp.Err = append(resp.Err, r.Err...)
+	}
+	return resp
+}
+
+// visible returns whether a request may see bee, see apicontext.Visible.
+func visible(request *restful.Request, bee *bees.BeeInterface) bool {
+	scope, _ := bees.SplitBeeName((*bee).Name())
+	return apicontext.Visible(request, scope)
+}
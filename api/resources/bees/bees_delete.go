@@ -48,7 +48,7 @@ func (r *BeeResource) Delete(context smolder.APIContext, request *restful.Reques
 
 	id := request.PathParameter("bee-id")
 	bee := bees.GetBee(id)
-	if bee == nil {
+	if bee == nil || !visible(request, bee) {
 		r.NotFound(request, response)
 		return
 	}
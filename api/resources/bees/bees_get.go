@@ -57,7 +57,7 @@ func (r *BeeResource) GetByIDs(ctx smolder.APIContext, request *restful.Request,
 
 	for _, id := range ids {
 		bee := bees.GetBee(id)
-		if bee == nil {
+		if bee == nil || !visible(request, bee) {
 			r.NotFound(request, response)
 			return
 		}
@@ -76,7 +76,9 @@ func (r *BeeResource) Get(ctx smolder.APIContext, request *restful.Request, resp
 	resp.Init(ctx)
 
 	for _, bee := range bees {
-		resp.AddBee(bee)
+		if visible(request, bee) {
+			resp.AddBee(bee)
+		}
 	}
 
 	resp.Send(response)
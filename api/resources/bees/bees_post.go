@@ -22,6 +22,7 @@ package bees
 
 import (
 	"github.com/emicklei/go-restful"
+	apicontext "github.com/muesli/beehive/api/context"
 	"github.com/muesli/beehive/bees"
 	"github.com/muesli/smolder"
 )
@@ -33,6 +34,7 @@ type BeePostStruct struct {
 		Namespace   string          `json:"namespace"`
 		Description string          `json:"description"`
 		Active      bool            `json:"active"`
+		Paused      *bool           `json:"paused,omitempty"`
 		Options     bees.BeeOptions `json:"options"`
 	} `json:"bee"`
 }
@@ -60,14 +62,17 @@ func (r *BeeResource) Post(context smolder.APIContext, data interface{}, request
 	pps := data.(*BeePostStruct)
 	c, err := bees.NewBeeConfig(pps.Bee.Name, pps.Bee.Namespace, pps.Bee.Description, pps.Bee.Options)
 	if err != nil {
-		smolder.ErrorResponseHandler(request, response, err, smolder.NewErrorResponse(
-			422, // Go 1.7+: http.StatusUnprocessableEntity,
-			err,
-			"BeeResource POST"))
+		smolder.ErrorResponseHandler(request, response, err, optionsErrorResponse(err, "BeeResource POST"))
 		return
 	}
+	c.Scope = apicontext.Profile(request)
+	c.Paused = pps.Bee.Paused != nil && *pps.Bee.Paused
 
-	bee := bees.StartBee(c)
+	bee, err := bees.StartBee(c)
+	if err != nil {
+		smolder.ErrorResponseHandler(request, response, err, optionsErrorResponse(err, "BeeResource POST"))
+		return
+	}
 	resp.AddBee(bee)
 
 	resp.Send(response)
@@ -49,18 +49,51 @@ func (r *BeeResource) Put(context smolder.APIContext, data interface{}, request
 	pps := data.(*BeePostStruct)
 	id := request.PathParameter("bee-id")
 	bee := bees.GetBee(id)
-	if bee == nil {
+	if bee == nil || !visible(request, bee) {
 		r.NotFound(request, response)
 		return
 	}
 
+	if pps.Bee.Name != "" && pps.Bee.Name != id {
+		if err := bees.RenameBee(id, pps.Bee.Name); err != nil {
+			smolder.ErrorResponseHandler(request, response, err, smolder.NewErrorResponse(
+				422, // Go 1.7+: http.StatusUnprocessableEntity,
+				err,
+				"BeeResource PUT"))
+			return
+		}
+		id = pps.Bee.Name
+	}
+
 	(*bee).SetDescription(pps.Bee.Description)
-	(*bee).ReloadOptions(pps.Bee.Options)
+	err := bees.UpdateBeeOptions(id, bees.UnmaskOptions(id, pps.Bee.Options))
+	if err != nil {
+		smolder.ErrorResponseHandler(request, response, err, optionsErrorResponse(err, "BeeResource PUT"))
+		return
+	}
+
+	// leave the paused state alone unless it's part of the request
+	if pps.Bee.Paused != nil {
+		if *pps.Bee.Paused {
+			err = bees.PauseBee(id)
+		} else {
+			err = bees.ResumeBee(id)
+		}
+		if err != nil {
+			smolder.ErrorResponseHandler(request, response, err, smolder.NewErrorResponse(
+				422, // Go 1.7+: http.StatusUnprocessableEntity,
+				err,
+				"BeeResource PUT"))
+			return
+		}
+	}
 
-	if pps.Bee.Active {
+	// running bees already picked up their new options, live or by getting
+	// restarted
+	if !pps.Bee.Active {
+		bees.StopBee(bee)
+	} else if !(*bee).IsRunning() {
 		bees.RestartBee(bee)
-	} else {
-		(*bee).Stop()
 	}
 
 	resp.AddBee(bee)
@@ -43,14 +43,19 @@ type BeeResponse struct {
 }
 
 type beeInfoResponse struct {
-	ID          string           `json:"id"`
-	Name        string           `json:"name"`
-	Namespace   string           `json:"namespace"`
-	Description string           `json:"description"`
-	LastAction  time.Time        `json:"lastaction"`
-	LastEvent   time.Time        `json:"lastevent"`
-	Active      bool             `json:"active"`
-	Options     []bees.BeeOption `json:"options"`
+	ID          string                  `json:"id"`
+	Instance    string                  `json:"instance,omitempty"`
+	Name        string                  `json:"name"`
+	Namespace   string                  `json:"namespace"`
+	Description string                  `json:"description"`
+	LastAction  time.Time               `json:"lastaction"`
+	LastEvent   time.Time               `json:"lastevent"`
+	Active      bool                    `json:"active"`
+	Paused      bool                    `json:"paused"`
+	State       string                  `json:"state"`
+	Options     []bees.BeeOption        `json:"options"`
+	Events      []bees.EventDescriptor  `json:"events"`
+	Actions     []bees.ActionDescriptor `json:"actions"`
 }
 
 // Init a new response
@@ -71,6 +76,9 @@ func (r *BeeResponse) AddBee(bee *bees.BeeInterface) {
 		panic("Hive for Bee not found")
 	}
 
+	if _, ok := r.hives[(*hive).Name()]; ok {
+		return
+	}
 	r.hives[(*hive).Name()] = hive
 	r.Hives = append(r.Hives, hives.PrepareHiveResponse(r.Context, hive))
 }
@@ -106,13 +114,18 @@ func prepareBeeResponse(context smolder.APIContext, bee *bees.BeeInterface) beeI
 	//	ctx := context.(*context.APIContext)
 	resp := beeInfoResponse{
 		ID:          (*bee).Name(),
+		Instance:    bees.BeeInstanceID(bee),
 		Name:        (*bee).Name(),
 		Namespace:   (*bee).Namespace(),
 		Description: (*bee).Description(),
 		LastAction:  (*bee).LastAction(),
 		LastEvent:   (*bee).LastEvent(),
 		Active:      (*bee).IsRunning(),
-		Options:     (*bee).Options(),
+		Paused:      bees.BeePaused((*bee).Name()),
+		State:       (*bee).State().String(),
+		Options:     bees.MaskOptions((*bee).Namespace(), (*bee).Options()),
+		Events:      bees.BeeEvents((*bee).Name()),
+		Actions:     bees.BeeActions((*bee).Name()),
 	}
 
 	return resp
@@ -34,6 +34,7 @@ var (
 	_ smolder.GetIDSupported  = &ChainResource{}
 	_ smolder.GetSupported    = &ChainResource{}
 	_ smolder.PostSupported   = &ChainResource{}
+	_ smolder.PutSupported    = &ChainResource{}
 	_ smolder.DeleteSupported = &ChainResource{}
 )
 
@@ -60,6 +61,19 @@ func (r *ChainResource) Returns() interface{} {
 	return ChainResponse{}
 }
 
+// validationErrorResponse returns a response listing the problems
+// bees.ValidateChain found with a chain.
+func validationErrorResponse(errs []error, context string) *smolder.ErrorResponse {
+	resp := &smolder.ErrorResponse{}
+	for _, err := range errs {
+		resp.Err = append(resp.Err, smolder.NewErrorResponse(
+			422, // Go 1.7+: http.StatusUnprocessableEntity,
+			err,
+			context).Err...)
+	}
+	return resp
+}
+
 // Validate checks an incoming request for data errors
 func (r *ChainResource) Validate(context smolder.APIContext, data interface{}, request *restful.Request) error {
 	//	ps := data.(*ChainPostStruct)
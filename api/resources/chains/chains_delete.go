@@ -22,6 +22,7 @@ package chains
 
 import (
 	"github.com/emicklei/go-restful"
+	apicontext "github.com/muesli/beehive/api/context"
 	"github.com/muesli/beehive/bees"
 	"github.com/muesli/smolder"
 )
@@ -47,19 +48,12 @@ func (r *ChainResource) Delete(context smolder.APIContext, request *restful.Requ
 	resp.Init(context)
 
 	id := request.PathParameter("chain-id")
-
-	found := false
-	chains := []bees.Chain{}
-	for _, v := range bees.GetChains() {
-		if v.Name == id {
-			found = true
-		} else {
-			chains = append(chains, v)
-		}
+	if chain := bees.GetChain(id); chain == nil || !apicontext.Visible(request, chain.Scope) {
+		r.NotFound(request, response)
+		return
 	}
 
-	if found {
-		bees.SetChains(chains)
+	if bees.RemoveChain(id) {
 		resp.Send(response)
 	} else {
 		r.NotFound(request, response)
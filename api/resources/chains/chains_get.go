@@ -21,6 +21,7 @@
 package chains
 
 import (
+	apicontext "github.com/muesli/beehive/api/context"
 	"github.com/muesli/beehive/bees"
 
 	"github.com/emicklei/go-restful"
@@ -57,7 +58,7 @@ func (r *ChainResource) GetByIDs(ctx smolder.APIContext, request *restful.Reques
 
 	for _, id := range ids {
 		chain := bees.GetChain(id)
-		if chain == nil {
+		if chain == nil || !apicontext.Visible(request, chain.Scope) {
 			r.NotFound(request, response)
 			return
 		}
@@ -76,7 +77,9 @@ func (r *ChainResource) Get(ctx smolder.APIContext, request *restful.Request, re
 	resp.Init(ctx)
 
 	for _, chain := range chains {
-		resp.AddChain(chain)
+		if apicontext.Visible(request, chain.Scope) {
+			resp.AddChain(chain)
+		}
 	}
 
 	resp.Send(response)
@@ -24,6 +24,7 @@ import (
 	"errors"
 
 	"github.com/emicklei/go-restful"
+	apicontext "github.com/muesli/beehive/api/context"
 	"github.com/muesli/beehive/bees"
 	"github.com/muesli/smolder"
 )
@@ -36,6 +37,7 @@ type ChainPostStruct struct {
 		Event       bees.Event `json:"event"`
 		Filters     []string   `json:"filters"`
 		Actions     []string   `json:"actions"`
+		OnError     []string   `json:"onerror,omitempty"`
 	} `json:"chain"`
 }
 
@@ -74,10 +76,15 @@ func (r *ChainResource) Post(context smolder.APIContext, data interface{}, reque
 		Description: pps.Chain.Description,
 		Event:       &pps.Chain.Event,
 		Actions:     pps.Chain.Actions,
+		OnError:     pps.Chain.OnError,
 		Filters:     pps.Chain.Filters,
+		Scope:       apicontext.Profile(request),
 	}
-	chains := append(bees.GetChains(), chain)
-	bees.SetChains(chains)
+	if errs := bees.ValidateChain(chain); len(errs) > 0 {
+		smolder.ErrorResponseHandler(request, response, errs[0], validationErrorResponse(errs, "ChainResource POST"))
+		return
+	}
+	bees.AddChain(chain)
 
 	resp.AddChain(chain)
 	resp.Send(response)
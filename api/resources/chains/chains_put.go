@@ -0,0 +1,87 @@
+/*
+ *    Copyright (C) 2026 Christian Muehlhaeuser
+ *
+ *    This program is free software: you can redistribute it and/or modify
+ *    it under the terms of the GNU Affero General Public License as published
+ *    by the Free Software Foundation, either version 3 of the License, or
+ *    (at your option) any later version.
+ *
+ *    This program is distributed in the hope that it will be useful,
+ *    but WITHOUT ANY WARRANTY; without even the implied warranty of
+ *    MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ *    GNU Affero General Public License for more details.
+ *
+ *    You should have received a copy of the GNU Affero General Public License
+ *    along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ *    Authors:
+ *      Christian Muehlhaeuser <muesli@gmail.com>
+ */
+
+package chains
+
+import (
+	"errors"
+
+	"github.com/emicklei/go-restful"
+	apicontext "github.com/muesli/beehive/api/context"
+	"github.com/muesli/beehive/bees"
+	"github.com/muesli/smolder"
+)
+
+// PutAuthRequired returns true because all requests need authentication
+func (r *ChainResource) PutAuthRequired() bool {
+	return false
+}
+
+// PutDoc returns the description of this API endpoint
+func (r *ChainResource) PutDoc() string {
+	return "update an existing chain"
+}
+
+// PutParams returns the parameters supported by this API endpoint
+func (r *ChainResource) PutParams() []*restful.Parameter {
+	return nil
+}
+
+// Put processes an incoming PUT (update) request
+func (r *ChainResource) Put(context smolder.APIContext, data interface{}, request *restful.Request, response *restful.Response) {
+	resp := ChainResponse{}
+	resp.Init(context)
+
+	pps := data.(*ChainPostStruct)
+	id := request.PathParameter("chain-id")
+	chain := bees.GetChain(id)
+	if chain == nil || !apicontext.Visible(request, chain.Scope) {
+		r.NotFound(request, response)
+		return
+	}
+
+	if pps.Chain.Name != "" && pps.Chain.Name != id {
+		if bees.GetChain(pps.Chain.Name) != nil {
+			smolder.ErrorResponseHandler(request, response, nil, smolder.NewErrorResponse(
+				422, // Go 1.7+: http.StatusUnprocessableEntity,
+				errors.New("A Chain with that name exists already"),
+				"ChainResource PUT"))
+			return
+		}
+		chain.Name = pps.Chain.Name
+	}
+	chain.Description = pps.Chain.Description
+	chain.Event = &pps.Chain.Event
+	chain.Actions = pps.Chain.Actions
+	chain.OnError = pps.Chain.OnError
+	chain.Filters = pps.Chain.Filters
+	if errs := bees.ValidateChain(*chain); len(errs) > 0 {
+		smolder.ErrorResponseHandler(request, response, errs[0], validationErrorResponse(errs, "ChainResource PUT"))
+		return
+	}
+
+	if !bees.UpdateChain(id, *chain) {
+		r.NotFound(request, response)
+		return
+	}
+
+	resp.AddChain(*chain)
+	resp.Send(response)
+}
@@ -38,12 +38,17 @@ type ChainResponse struct {
 }
 
 type chainInfoResponse struct {
-	ID          string      `json:"id"`
-	Name        string      `json:"name"`
-	Description string      `json:"description"`
-	Event       *bees.Event `json:"event"`
-	Filters     []string    `json:"filters,omitempty"`
-	Actions     []string    `json:"actions"`
+	ID          string        `json:"id"`
+	Name        string        `json:"name"`
+	Description string        `json:"description"`
+	Event       *bees.Event   `json:"event"`
+	Events      []*bees.Event `json:"events,omitempty"`
+	Filters     []string      `json:"filters,omitempty"`
+	Actions     []string      `json:"actions"`
+	OnError     []string      `json:"onerror,omitempty"`
+	Enabled     bool          `json:"enabled"`
+
+	Stats *bees.ChainStatistics `json:"stats,omitempty"`
 }
 
 // Init a new response
@@ -93,8 +98,12 @@ func prepareChainResponse(context smolder.APIContext, chain *bees.Chain) chainIn
 		Name:        (*chain).Name,
 		Description: (*chain).Description,
 		Event:       (*chain).Event,
+		Events:      (*chain).Events,
 		Actions:     (*chain).Actions,
+		OnError:     (*chain).OnError,
 		Filters:     (*chain).Filters,
+		Enabled:     chain.IsEnabled(),
+		Stats:       bees.ChainStats(chain.Name),
 	}
 
 	return resp
@@ -0,0 +1,99 @@
+/*
+ *    Copyright (C) 2026 Christian Muehlhaeuser
+ *
+ *    This program is free software: you can redistribute it and/or modify
+ *    it under the terms of the GNU Affero General Public License as published
+ *    by the Free Software Foundation, either version 3 of the License, or
+ *    (at your option) any later version.
+ *
+ *    This program is distributed in the hope that it will be useful,
+ *    but WITHOUT ANY WARRANTY; without even the implied warranty of
+ *    MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ *    GNU Affero General Public License for more details.
+ *
+ *    You should have received a copy of the GNU Affero General Public License
+ *    along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ *    Authors:
+ *      Christian Muehlhaeuser <muesli@gmail.com>
+ */
+
+package deadletters
+
+import (
+	apicontext "github.com/muesli/beehive/api/context"
+	"github.com/muesli/beehive/bees"
+
+	"github.com/emicklei/go-restful"
+	"github.com/muesli/smolder"
+)
+
+// GetAuthRequired returns true because all requests need authentication
+func (r *DeadLetterResource) GetAuthRequired() bool {
+	return false
+}
+
+// GetByIDsAuthRequired returns true because all requests need authentication
+func (r *DeadLetterResource) GetByIDsAuthRequired() bool {
+	return false
+}
+
+// GetDoc returns the description of this API endpoint
+func (r *DeadLetterResource) GetDoc() string {
+	return "retrieve failed events and actions"
+}
+
+// GetParams returns the parameters supported by this API endpoint
+func (r *DeadLetterResource) GetParams() []*restful.Parameter {
+	return nil
+}
+
+// GetByIDs sends out all items matching a set of IDs
+func (r *DeadLetterResource) GetByIDs(ctx smolder.APIContext, request *restful.Request, response *restful.Response, ids []string) {
+	resp := DeadLetterResponse{}
+	resp.Init(ctx)
+
+	q := bees.GetDeadLetterQueue()
+	for _, id := range ids {
+		if q == nil {
+			r.NotFound(request, response)
+			return
+		}
+		entry, ok := q.Entry(id)
+		if !ok || !visible(request, entry) {
+			r.NotFound(request, response)
+			return
+		}
+
+		resp.AddDeadLetter(entry)
+	}
+
+	resp.Send(response)
+}
+
+// Get sends out items matching the query parameters
+func (r *DeadLetterResource) Get(ctx smolder.APIContext, request *restful.Request, response *restful.Response, params map[string][]string) {
+	resp := DeadLetterResponse{}
+	resp.Init(ctx)
+
+	if q := bees.GetDeadLetterQueue(); q != nil {
+		for _, entry := range q.Entries() {
+			if visible(request, entry) {
+				resp.AddDeadLetter(entry)
+			}
+		}
+	}
+
+	resp.Send(response)
+}
+
+// visible returns whether the bee that failed an entry is visible to the
+// profile of a request.
+func visible(request *restful.Request, entry bees.DeadLetterEntry) bool {
+	bee := entry.Event.Bee
+	if entry.Action != nil {
+		bee = entry.Action.Bee
+	}
+	scope, _ := bees.SplitBeeName(bee)
+	return apicontext.Visible(request, scope)
+}
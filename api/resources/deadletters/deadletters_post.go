@@ -0,0 +1,82 @@
+/*
+ *    Copyright (C) 2026 Christian Muehlhaeuser
+ *
+ *    This program is free software: you can redistribute it and/or modify
+ *    it under the terms of the GNU Affero General Public License as published
+ *    by the Free Software Foundation, either version 3 of the License, or
+ *    (at your option) any later version.
+ *
+ *    This program is distributed in the hope that it will be useful,
+ *    but WITHOUT ANY WARRANTY; without even the implied warranty of
+ *    MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ *    GNU Affero General Public License for more details.
+ *
+ *    You should have received a copy of the GNU Affero General Public License
+ *    along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ *    Authors:
+ *      Christian Muehlhaeuser <muesli@gmail.com>
+ */
+
+package deadletters
+
+import (
+	"errors"
+
+	"github.com/emicklei/go-restful"
+	"github.com/muesli/beehive/bees"
+	"github.com/muesli/smolder"
+)
+
+// DeadLetterPostStruct holds all values of an incoming POST request
+type DeadLetterPostStruct struct {
+	DeadLetter struct {
+		ID string `json:"id"`
+	} `json:"deadletter"`
+}
+
+// PostAuthRequired returns true because all requests need authentication
+func (r *DeadLetterResource) PostAuthRequired() bool {
+	return false
+}
+
+// PostDoc returns the description of this API endpoint
+func (r *DeadLetterResource) PostDoc() string {
+	return "re-drive a failed event or action"
+}
+
+// PostParams returns the parameters supported by this API endpoint
+func (r *DeadLetterResource) PostParams() []*restful.Parameter {
+	return nil
+}
+
+// Post processes an incoming POST request, re-driving a dead letter: failed
+// events get handled once more, failed actions get executed again. The
+// re-driven entry gets removed from the dead-letter queue and returned.
+func (r *DeadLetterResource) Post(context smolder.APIContext, data interface{}, request *restful.Request, response *restful.Response) {
+	resp := DeadLetterResponse{}
+	resp.Init(context)
+
+	pps := data.(*DeadLetterPostStruct)
+	if len(pps.DeadLetter.ID) == 0 {
+		smolder.ErrorResponseHandler(request, response, nil, smolder.NewErrorResponse(
+			422, // Go 1.7+: http.StatusUnprocessableEntity,
+			errors.New("Missing dead letter id"),
+			"DeadLetterResource POST"))
+		return
+	}
+
+	q := bees.GetDeadLetterQueue()
+	if q == nil {
+		r.NotFound(request, response)
+		return
+	}
+	entry, ok := q.Entry(pps.DeadLetter.ID)
+	if !ok || !visible(request, entry) || !q.Redrive(entry.ID) {
+		r.NotFound(request, response)
+		return
+	}
+
+	resp.AddDeadLetter(entry)
+	resp.Send(response)
+}
@@ -0,0 +1,118 @@
+/*
+ *    Copyright (C) 2026 Christian Muehlhaeuser
+ *
+ *    This program is free software: you can redistribute it and/or modify
+ *    it under the terms of the GNU Affero General Public License as published
+ *    by the Free Software Foundation, either version 3 of the License, or
+ *    (at your option) any later version.
+ *
+ *    This program is distributed in the hope that it will be useful,
+ *    but WITHOUT ANY WARRANTY; without even the implied warranty of
+ *    MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ *    GNU Affero General Public License for more details.
+ *
+ *    You should have received a copy of the GNU Affero General Public License
+ *    along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ *    Authors:
+ *      Christian Muehlhaeuser <muesli@gmail.com>
+ */
+
+package deadletters
+
+import (
+	"time"
+
+	"github.com/muesli/beehive/bees"
+
+	"github.com/muesli/smolder"
+)
+
+// DeadLetterResponse is the common response to 'deadletter' requests
+type DeadLetterResponse struct {
+	smolder.Response
+
+	DeadLetters []deadLetterInfoResponse `json:"deadletters,omitempty"`
+	deadLetters []bees.DeadLetterEntry
+}
+
+type deadLetterInfoResponse struct {
+	ID        string              `json:"id"`
+	Timestamp time.Time           `json:"timestamp"`
+	Kind      string              `json:"kind"`
+	Error     string              `json:"error,omitempty"`
+	Event     eventInfoResponse   `json:"event"`
+	Action    *actionInfoResponse `json:"action,omitempty"`
+	Chain     string              `json:"chain,omitempty"`
+	Attempts  int                 `json:"attempts,omitempty"`
+}
+
+type eventInfoResponse struct {
+	ID      string            `json:"id,omitempty"`
+	Bee     string            `json:"bee"`
+	Name    string            `json:"name"`
+	Options bees.Placeholders `json:"options,omitempty"`
+}
+
+type actionInfoResponse struct {
+	ID      string            `json:"id"`
+	Bee     string            `json:"bee"`
+	Name    string            `json:"name"`
+	Options bees.Placeholders `json:"options,omitempty"`
+}
+
+// Init a new response
+func (r *DeadLetterResponse) Init(context smolder.APIContext) {
+	r.Parent = r
+	r.Context = context
+
+	r.DeadLetters = []deadLetterInfoResponse{}
+}
+
+// AddDeadLetter adds a dead letter to the response
+func (r *DeadLetterResponse) AddDeadLetter(entry bees.DeadLetterEntry) {
+	r.deadLetters = append(r.deadLetters, entry)
+	r.DeadLetters = append(r.DeadLetters, prepareDeadLetterResponse(r.Context, entry))
+}
+
+// EmptyResponse returns an empty API response for this endpoint if there's no data to respond with
+func (r *DeadLetterResponse) EmptyResponse() interface{} {
+	if len(r.deadLetters) == 0 {
+		var out struct {
+			DeadLetters interface{} `json:"deadletters"`
+		}
+		out.DeadLetters = []deadLetterInfoResponse{}
+		return out
+	}
+	return nil
+}
+
+func prepareDeadLetterResponse(context smolder.APIContext, entry bees.DeadLetterEntry) deadLetterInfoResponse {
+	resp := deadLetterInfoResponse{
+		ID:        entry.ID,
+		Timestamp: entry.Time,
+		Kind:      "event",
+		Event: eventInfoResponse{
+			ID:      entry.Event.ID,
+			Bee:     entry.Event.Bee,
+			Name:    entry.Event.Name,
+			Options: entry.Event.Options,
+		},
+		Chain:    entry.Chain,
+		Attempts: entry.Attempts,
+	}
+	if entry.Err != nil {
+		resp.Error = entry.Err.Error()
+	}
+	if a := entry.Action; a != nil {
+		resp.Kind = "action"
+		resp.Action = &actionInfoResponse{
+			ID:      a.ID,
+			Bee:     a.Bee,
+			Name:    a.Name,
+			Options: a.Options,
+		}
+	}
+
+	return resp
+}
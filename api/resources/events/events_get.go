@@ -0,0 +1,78 @@
+/*
+ *    Copyright (C) 2026 Christian Muehlhaeuser
+ *
+ *    This program is free software: you can redistribute it and/or modify
+ *    it under the terms of the GNU Affero General Public License as published
+ *    by the Free Software Foundation, either version 3 of the License, or
+ *    (at your option) any later version.
+ *
+ *    This program is distributed in the hope that it will be useful,
+ *    but WITHOUT ANY WARRANTY; without even the implied warranty of
+ *    MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ *    GNU Affero General Public License for more details.
+ *
+ *    You should have received a copy of the GNU Affero General Public License
+ *    along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ *    Authors:
+ *      Christian Muehlhaeuser <muesli@gmail.com>
+ */
+
+package events
+
+import (
+	"strconv"
+
+	apicontext "github.com/muesli/beehive/api/context"
+	"github.com/muesli/beehive/bees"
+
+	"github.com/emicklei/go-restful"
+	"github.com/muesli/smolder"
+)
+
+// GetAuthRequired returns true because all requests need authentication
+func (r *EventResource) GetAuthRequired() bool {
+	return false
+}
+
+// GetByIDsAuthRequired returns true because all requests need authentication
+func (r *EventResource) GetByIDsAuthRequired() bool {
+	return false
+}
+
+// GetDoc returns the description of this API endpoint
+func (r *EventResource) GetDoc() string {
+	return "retrieve recent events and the chains they triggered"
+}
+
+// GetParams returns the parameters supported by this API endpoint
+func (r *EventResource) GetParams() []*restful.Parameter {
+	params := []*restful.Parameter{}
+	params = append(params, restful.QueryParameter("bee", "id of a bee").DataType("string"))
+	params = append(params, restful.QueryParameter("limit", "maximum number of events").DataType("int"))
+
+	return params
+}
+
+// Get sends out items matching the query parameters
+func (r *EventResource) Get(ctx smolder.APIContext, request *restful.Request, response *restful.Response, params map[string][]string) {
+	bee := request.QueryParameter("bee")
+	limit, _ := strconv.Atoi(request.QueryParameter("limit"))
+
+	resp := EventResponse{}
+	resp.Init(ctx)
+
+	var events []bees.LoggedEvent
+	if bee != "" {
+		events = bees.EventHistory(bee, limit)
+	} else {
+		events = bees.GetRecentEvents(limit)
+	}
+	for _, event := range events {
+		if apicontext.VisibleBee(request, event.Event.Bee) {
+			resp.AddEvent(event)
+		}
+	}
+
+	resp.Send(response)
+}
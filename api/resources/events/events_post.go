@@ -0,0 +1,90 @@
+/*
+ *    Copyright (C) 2026 Christian Muehlhaeuser
+ *
+ *    This program is free software: you can redistribute it and/or modify
+ *    it under the terms of the GNU Affero General Public License as published
+ *    by the Free Software Foundation, either version 3 of the License, or
+ *    (at your option) any later version.
+ *
+ *    This program is distributed in the hope that it will be useful,
+ *    but WITHOUT ANY WARRANTY; without even the implied warranty of
+ *    MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ *    GNU Affero General Public License for more details.
+ *
+ *    You should have received a copy of the GNU Affero General Public License
+ *    along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ *    Authors:
+ *      Christian Muehlhaeuser <muesli@gmail.com>
+ */
+
+package events
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/emicklei/go-restful"
+	apicontext "github.com/muesli/beehive/api/context"
+	"github.com/muesli/beehive/bees"
+	"github.com/muesli/smolder"
+)
+
+// EventPostStruct holds all values of an incoming POST request
+type EventPostStruct struct {
+	Event struct {
+		Bee      string            `json:"bee"`
+		Name     string            `json:"name"`
+		Options  bees.Placeholders `json:"options"`
+		Priority int               `json:"priority"`
+	} `json:"event"`
+	DryRun bool `json:"dryrun"`
+}
+
+// PostAuthRequired returns true because all requests need authentication
+func (r *EventResource) PostAuthRequired() bool {
+	return false
+}
+
+// PostDoc returns the description of this API endpoint
+func (r *EventResource) PostDoc() string {
+	return "inject a synthetic event, optionally in dry-run mode"
+}
+
+// PostParams returns the parameters supported by this API endpoint
+func (r *EventResource) PostParams() []*restful.Parameter {
+	return nil
+}
+
+// Post processes an incoming POST (create) request
+func (r *EventResource) Post(context smolder.APIContext, data interface{}, request *restful.Request, response *restful.Response) {
+	resp := EventResponse{}
+	resp.Init(context)
+
+	pps := data.(*EventPostStruct)
+	if c := apicontext.CredentialOf(request); (c != nil && !c.AllowsBee(pps.Event.Bee)) || !apicontext.VisibleBee(request, pps.Event.Bee) {
+		smolder.ErrorResponseHandler(request, response, nil, smolder.NewErrorResponse(
+			http.StatusForbidden,
+			errors.New("Not allowed to inject events for this bee"),
+			"EventResource POST"))
+		return
+	}
+
+	res, err := bees.InjectEvent(request.Request.Context(), bees.Event{
+		Bee:      pps.Event.Bee,
+		Name:     pps.Event.Name,
+		Options:  pps.Event.Options,
+		Priority: pps.Event.Priority,
+		DryRun:   pps.DryRun,
+	})
+	if err != nil {
+		smolder.ErrorResponseHandler(request, response, err, smolder.NewErrorResponse(
+			422, // Go 1.7+: http.StatusUnprocessableEntity,
+			err,
+			"EventResource POST"))
+		return
+	}
+	resp.SetInjected(res)
+
+	resp.Send(response)
+}
@@ -0,0 +1,114 @@
+/*
+ *    Copyright (C) 2026 Christian Muehlhaeuser
+ *
+ *    This program is free software: you can redistribute it and/or modify
+ *    it under the terms of the GNU Affero General Public License as published
+ *    by the Free Software Foundation, either version 3 of the License, or
+ *    (at your option) any later version.
+ *
+ *    This program is distributed in the hope that it will be useful,
+ *    but WITHOUT ANY WARRANTY; without even the implied warranty of
+ *    MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ *    GNU Affero General Public License for more details.
+ *
+ *    You should have received a copy of the GNU Affero General Public License
+ *    along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ *    Authors:
+ *      Christian Muehlhaeuser <muesli@gmail.com>
+ */
+
+package events
+
+import (
+	"time"
+
+	"github.com/muesli/beehive/bees"
+
+	"github.com/muesli/smolder"
+)
+
+// EventResponse is the common response to 'event' requests
+type EventResponse struct {
+	smolder.Response
+
+	Events   []eventInfoResponse    `json:"events,omitempty"`
+	Injected *injectedEventResponse `json:"injected,omitempty"`
+	events   []bees.LoggedEvent
+}
+
+type eventInfoResponse struct {
+	ID       string            `json:"id"`
+	Bee      string            `json:"bee"`
+	Name     string            `json:"name"`
+	Options  bees.Placeholders `json:"options"`
+	Received time.Time         `json:"received"`
+	Chains   []string          `json:"chains"`
+}
+
+type injectedEventResponse struct {
+	ID      string               `json:"id"`
+	Chains  []string             `json:"chains"`
+	Actions []dryRunInfoResponse `json:"actions,omitempty"`
+}
+
+type dryRunInfoResponse struct {
+	Chain   string            `json:"chain"`
+	Bee     string            `json:"bee"`
+	Name    string            `json:"name"`
+	Options bees.Placeholders `json:"options"`
+}
+
+// Init a new response
+func (r *EventResponse) Init(context smolder.APIContext) {
+	r.Parent = r
+	r.Context = context
+
+	r.Events = []eventInfoResponse{}
+}
+
+// AddEvent adds an event to the response
+func (r *EventResponse) AddEvent(event bees.LoggedEvent) {
+	r.events = append(r.events, event)
+	r.Events = append(r.Events, prepareEventResponse(r.Context, event))
+}
+
+// SetInjected adds the outcome of an injected event to the response
+func (r *EventResponse) SetInjected(res bees.InjectResult) {
+	injected := &injectedEventResponse{
+		ID:     res.EventID,
+		Chains: append([]string{}, res.Chains...),
+	}
+	for _, a := range res.Actions {
+		injected.Actions = append(injected.Actions, dryRunInfoResponse{
+			Chain:   a.Chain,
+			Bee:     a.Action.Bee,
+			Name:    a.Action.Name,
+			Options: a.Action.Options,
+		})
+	}
+	r.Injected = injected
+}
+
+// EmptyResponse returns an empty API response for this endpoint if there's no data to respond with
+func (r *EventResponse) EmptyResponse() interface{} {
+	if len(r.events) == 0 && r.Injected == nil {
+		var out struct {
+			Events interface{} `json:"events"`
+		}
+		out.Events = []eventInfoResponse{}
+		return out
+	}
+	return nil
+}
+
+func prepareEventResponse(context smolder.APIContext, event bees.LoggedEvent) eventInfoResponse {
+	return eventInfoResponse{
+		ID:       event.Event.ID,
+		Bee:      event.Event.Bee,
+		Name:     event.Event.Name,
+		Options:  event.Event.Options,
+		Received: event.Received,
+		Chains:   event.Chains,
+	}
+}
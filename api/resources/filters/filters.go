@@ -0,0 +1,56 @@
+/*
+ *    Copyright (C) 2026 Christian Muehlhaeuser
+ *
+ *    This program is free software: you can redistribute it and/or modify
+ *    it under the terms of the GNU Affero General Public License as published
+ *    by the Free Software Foundation, either version 3 of the License, or
+ *    (at your option) any later version.
+ *
+ *    This program is distributed in the hope that it will be useful,
+ *    but WITHOUT ANY WARRANTY; without even the implied warranty of
+ *    MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ *    GNU Affero General Public License for more details.
+ *
+ *    You should have received a copy of the GNU Affero General Public License
+ *    along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ *    Authors:
+ *      Christian Muehlhaeuser <muesli@gmail.com>
+ */
+
+package filters
+
+import (
+	"github.com/emicklei/go-restful"
+	"github.com/muesli/smolder"
+)
+
+// FilterResource is the resource responsible for /filters. Filters are
+// compiled into Beehive, so they can only be listed here; a chain's filter
+// expressions get changed by updating the chain.
+type FilterResource struct {
+	smolder.Resource
+}
+
+var (
+	_ smolder.GetIDSupported = &FilterResource{}
+	_ smolder.GetSupported   = &FilterResource{}
+)
+
+// Register this resource with the container to setup all the routes
+func (r *FilterResource) Register(container *restful.Container, config smolder.APIConfig, context smolder.APIContextFactory) {
+	r.Name = "FilterResource"
+	r.TypeName = "filter"
+	r.Endpoint = "filters"
+	r.Doc = "List filters"
+
+	r.Config = config
+	r.Context = context
+
+	r.Init(container, r)
+}
+
+// Returns returns the model that will be returned
+func (r *FilterResource) Returns() interface{} {
+	return FilterResponse{}
+}
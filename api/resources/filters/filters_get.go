@@ -0,0 +1,78 @@
+/*
+ *    Copyright (C) 2026 Christian Muehlhaeuser
+ *
+ *    This program is free software: you can redistribute it and/or modify
+ *    it under the terms of the GNU Affero General Public License as published
+ *    by the Free Software Foundation, either version 3 of the License, or
+ *    (at your option) any later version.
+ *
+ *    This program is distributed in the hope that it will be useful,
+ *    but WITHOUT ANY WARRANTY; without even the implied warranty of
+ *    MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ *    GNU Affero General Public License for more details.
+ *
+ *    You should have received a copy of the GNU Affero General Public License
+ *    along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ *    Authors:
+ *      Christian Muehlhaeuser <muesli@gmail.com>
+ */
+
+package filters
+
+import (
+	"github.com/muesli/beehive/filters"
+
+	"github.com/emicklei/go-restful"
+	"github.com/muesli/smolder"
+)
+
+// GetAuthRequired returns true because all requests need authentication
+func (r *FilterResource) GetAuthRequired() bool {
+	return false
+}
+
+// GetByIDsAuthRequired returns true because all requests need authentication
+func (r *FilterResource) GetByIDsAuthRequired() bool {
+	return false
+}
+
+// GetDoc returns the description of this API endpoint
+func (r *FilterResource) GetDoc() string {
+	return "retrieve filters"
+}
+
+// GetParams returns the parameters supported by this API endpoint
+func (r *FilterResource) GetParams() []*restful.Parameter {
+	return nil
+}
+
+// GetByIDs sends out all items matching a set of IDs
+func (r *FilterResource) GetByIDs(ctx smolder.APIContext, request *restful.Request, response *restful.Response, ids []string) {
+	resp := FilterResponse{}
+	resp.Init(ctx)
+
+	for _, id := range ids {
+		filter := filters.GetFilter(id)
+		if filter == nil {
+			r.NotFound(request, response)
+			return
+		}
+
+		resp.AddFilter(filter)
+	}
+
+	resp.Send(response)
+}
+
+// Get sends out items matching the query parameters
+func (r *FilterResource) Get(ctx smolder.APIContext, request *restful.Request, response *restful.Response, params map[string][]string) {
+	resp := FilterResponse{}
+	resp.Init(ctx)
+
+	for _, filter := range filters.GetFilters() {
+		resp.AddFilter(filter)
+	}
+
+	resp.Send(response)
+}
@@ -0,0 +1,92 @@
+/*
+ *    Copyright (C) 2026 Christian Muehlhaeuser
+ *
+ *    This program is free software: you can redistribute it and/or modify
+ *    it under the terms of the GNU Affero General Public License as published
+ *    by the Free Software Foundation, either version 3 of the License, or
+ *    (at your option) any later version.
+ *
+ *    This program is distributed in the hope that it will be useful,
+ *    but WITHOUT ANY WARRANTY; without even the implied warranty of
+ *    MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ *    GNU Affero General Public License for more details.
+ *
+ *    You should have received a copy of the GNU Affero General Public License
+ *    along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ *    Authors:
+ *      Christian Muehlhaeuser <muesli@gmail.com>
+ */
+
+package filters
+
+import (
+	"sort"
+
+	restful "github.com/emicklei/go-restful"
+	"github.com/muesli/beehive/filters"
+
+	"github.com/muesli/smolder"
+)
+
+// FilterResponse is the common response to 'filter' requests
+type FilterResponse struct {
+	smolder.Response
+
+	Filters []filterInfoResponse `json:"filters,omitempty"`
+	filters map[string]*filters.FilterInterface
+}
+
+type filterInfoResponse struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+// Init a new response
+func (r *FilterResponse) Init(context smolder.APIContext) {
+	r.Parent = r
+	r.Context = context
+
+	r.filters = make(map[string]*filters.FilterInterface)
+}
+
+// AddFilter adds a filter to the response
+func (r *FilterResponse) AddFilter(filter *filters.FilterInterface) {
+	r.filters[(*filter).Name()] = filter
+}
+
+// Send responds to a request with http.StatusOK
+func (r *FilterResponse) Send(response *restful.Response) {
+	var keys []string
+	for k := range r.filters {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		r.Filters = append(r.Filters, prepareFilterResponse(r.Context, r.filters[k]))
+	}
+
+	r.Response.Send(response)
+}
+
+// EmptyResponse returns an empty API response for this endpoint if there's no data to respond with
+func (r *FilterResponse) EmptyResponse() interface{} {
+	if len(r.filters) == 0 {
+		var out struct {
+			Filters interface{} `json:"filters"`
+		}
+		out.Filters = []filterInfoResponse{}
+		return out
+	}
+	return nil
+}
+
+func prepareFilterResponse(context smolder.APIContext, filter *filters.FilterInterface) filterInfoResponse {
+	return filterInfoResponse{
+		ID:          (*filter).Name(),
+		Name:        (*filter).Name(),
+		Description: (*filter).Description(),
+	}
+}
@@ -50,6 +50,8 @@ type HiveInfoResponse struct {
 	States      []bees.StateDescriptor     `json:"states"`
 	Events      []bees.EventDescriptor     `json:"events"`
 	Actions     []bees.ActionDescriptor    `json:"actions"`
+	Doc         string                     `json:"doc,omitempty"`
+	Examples    []bees.ConfigExample       `json:"examples,omitempty"`
 }
 
 // Init a new response
@@ -106,6 +108,8 @@ func PrepareHiveResponse(ctx smolder.APIContext, hive *bees.BeeFactoryInterface)
 		States:      (*hive).States(),
 		Events:      (*hive).Events(),
 		Actions:     (*hive).Actions(),
+		Doc:         (*hive).Doc(),
+		Examples:    (*hive).Examples(),
 	}
 
 	return resp
@@ -21,6 +21,7 @@
 package logs
 
 import (
+	apicontext "github.com/muesli/beehive/api/context"
 	"github.com/muesli/beehive/bees"
 
 	"github.com/emicklei/go-restful"
@@ -70,7 +71,9 @@ func (r *LogResource) Get(ctx smolder.APIContext, request *restful.Request, resp
 
 	logs := bees.GetLogs(bee)
 	for _, log := range logs {
-		resp.AddLog(&log)
+		if apicontext.VisibleBee(request, log.Bee) {
+			resp.AddLog(&log)
+		}
 	}
 
 	resp.Send(response)
@@ -37,11 +37,12 @@ type LogResponse struct {
 }
 
 type logInfoResponse struct {
-	ID        string    `json:"id"`
-	Bee       string    `json:"bee"`
-	Level     int64     `json:"level"`
-	Message   string    `json:"message"`
-	Timestamp time.Time `json:"timestamp"`
+	ID        string         `json:"id"`
+	Bee       string         `json:"bee"`
+	Level     int64          `json:"level"`
+	Message   string         `json:"message"`
+	Timestamp time.Time      `json:"timestamp"`
+	Fields    bees.LogFields `json:"fields,omitempty"`
 }
 
 // Init a new response
@@ -78,6 +79,7 @@ func prepareLogResponse(context smolder.APIContext, log *bees.LogMessage) logInf
 		Level:     int64((*log).MessageType),
 		Message:   (*log).Message,
 		Timestamp: (*log).Timestamp,
+		Fields:    (*log).Fields,
 	}
 
 	return resp
@@ -0,0 +1,81 @@
+/*
+ *    Copyright (C) 2026 Christian Muehlhaeuser
+ *
+ *    This program is free software: you can redistribute it and/or modify
+ *    it under the terms of the GNU Affero General Public License as published
+ *    by the Free Software Foundation, either version 3 of the License, or
+ *    (at your option) any later version.
+ *
+ *    This program is distributed in the hope that it will be useful,
+ *    but WITHOUT ANY WARRANTY; without even the implied warranty of
+ *    MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ *    GNU Affero General Public License for more details.
+ *
+ *    You should have received a copy of the GNU Affero General Public License
+ *    along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ *    Authors:
+ *      Christian Muehlhaeuser <muesli@gmail.com>
+ */
+
+package status
+
+import (
+	"github.com/emicklei/go-restful"
+	"github.com/muesli/smolder"
+
+	apicontext "github.com/muesli/beehive/api/context"
+	"github.com/muesli/beehive/bees"
+)
+
+// GetAuthRequired returns true because all requests need authentication
+func (r *StatusResource) GetAuthRequired() bool {
+	return false
+}
+
+// GetByIDsAuthRequired returns true because all requests need authentication
+func (r *StatusResource) GetByIDsAuthRequired() bool {
+	return false
+}
+
+// GetDoc returns the description of this API endpoint
+func (r *StatusResource) GetDoc() string {
+	return "retrieve the status of bees"
+}
+
+// GetParams returns the parameters supported by this API endpoint
+func (r *StatusResource) GetParams() []*restful.Parameter {
+	return nil
+}
+
+// GetByIDs sends out the status of all bees matching a set of IDs
+func (r *StatusResource) GetByIDs(ctx smolder.APIContext, request *restful.Request, response *restful.Response, ids []string) {
+	resp := StatusResponse{}
+	resp.Init(ctx)
+
+	for _, id := range ids {
+		status, err := bees.GetBeeStatus(id)
+		if err != nil || !apicontext.VisibleBee(request, status.Name) {
+			r.NotFound(request, response)
+			return
+		}
+
+		resp.AddStatus(status)
+	}
+
+	resp.Send(response)
+}
+
+// Get sends out the status of all bees
+func (r *StatusResource) Get(ctx smolder.APIContext, request *restful.Request, response *restful.Response, params map[string][]string) {
+	resp := StatusResponse{}
+	resp.Init(ctx)
+
+	for _, status := range bees.GetBeeStatuses() {
+		if apicontext.VisibleBee(request, status.Name) {
+			resp.AddStatus(status)
+		}
+	}
+
+	resp.Send(response)
+}
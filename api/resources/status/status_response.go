@@ -0,0 +1,102 @@
+/*
+ *    Copyright (C) 2026 Christian Muehlhaeuser
+ *
+ *    This program is free software: you can redistribute it and/or modify
+ *    it under the terms of the GNU Affero General Public License as published
+ *    by the Free Software Foundation, either version 3 of the License, or
+ *    (at your option) any later version.
+ *
+ *    This program is distributed in the hope that it will be useful,
+ *    but WITHOUT ANY WARRANTY; without even the implied warranty of
+ *    MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ *    GNU Affero General Public License for more details.
+ *
+ *    You should have received a copy of the GNU Affero General Public License
+ *    along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ *    Authors:
+ *      Christian Muehlhaeuser <muesli@gmail.com>
+ */
+
+package status
+
+import (
+	"time"
+
+	restful "github.com/emicklei/go-restful"
+	"github.com/muesli/smolder"
+
+	"github.com/muesli/beehive/bees"
+)
+
+// StatusResponse is the common response to 'status' requests
+type StatusResponse struct {
+	smolder.Response
+
+	Statuses []statusInfoResponse `json:"status,omitempty"`
+	statuses []bees.BeeStatus
+}
+
+type statusInfoResponse struct {
+	ID          string    `json:"id"`
+	State       string    `json:"state"`
+	Paused      bool      `json:"paused"`
+	Healthy     bool      `json:"healthy"`
+	HealthError string    `json:"healtherror,omitempty"`
+	Idle        bool      `json:"idle"`
+	LastEvent   time.Time `json:"lastevent"`
+	LastAction  time.Time `json:"lastaction"`
+	Restarts    int64     `json:"restarts"`
+	Panics      int64     `json:"panics"`
+	LastError   string    `json:"lasterror,omitempty"`
+	Uptime      string    `json:"uptime"`
+}
+
+// Init a new response
+func (r *StatusResponse) Init(context smolder.APIContext) {
+	r.Parent = r
+	r.Context = context
+}
+
+// AddStatus adds the status of a bee to the response
+func (r *StatusResponse) AddStatus(status bees.BeeStatus) {
+	r.statuses = append(r.statuses, status)
+}
+
+// Send responds to a request with http.StatusOK
+func (r *StatusResponse) Send(response *restful.Response) {
+	for _, s := range r.statuses {
+		r.Statuses = append(r.Statuses, prepareStatusResponse(r.Context, s))
+	}
+
+	r.Response.Send(response)
+}
+
+// EmptyResponse returns an empty API response for this endpoint if there's no data to respond with
+func (r *StatusResponse) EmptyResponse() interface{} {
+	if len(r.statuses) == 0 {
+		var out struct {
+			Statuses interface{} `json:"status"`
+		}
+		out.Statuses = []statusInfoResponse{}
+		return out
+	}
+	return nil
+}
+
+func prepareStatusResponse(context smolder.APIContext, s bees.BeeStatus) statusInfoResponse {
+	return statusInfoResponse{
+		ID:          s.Name,
+		State:       s.State.String(),
+		Paused:      s.Paused,
+		Healthy:     s.Healthy,
+		HealthError: s.HealthError,
+		Idle:        s.Idle,
+		LastEvent:   s.LastEvent,
+		LastAction:  s.LastAction,
+		Restarts:    s.Restarts,
+		Panics:      s.Panics,
+		LastError:   s.LastError,
+		Uptime:      s.Uptime.String(),
+	}
+}
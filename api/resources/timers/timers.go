@@ -0,0 +1,76 @@
+/*
+ *    Copyright (C) 2026 Christian Muehlhaeuser
+ *
+ *    This program is free software: you can redistribute it and/or modify
+ *    it under the terms of the GNU Affero General Public License as published
+ *    by the Free Software Foundation, either version 3 of the License, or
+ *    (at your option) any later version.
+ *
+ *    This program is distributed in the hope that it will be useful,
+ *    but WITHOUT ANY WARRANTY; without even the implied warranty of
+ *    MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ *    GNU Affero General Public License for more details.
+ *
+ *    You should have received a copy of the GNU Affero General Public License
+ *    along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ *    Authors:
+ *      Christian Muehlhaeuser <muesli@gmail.com>
+ */
+
+package timers
+
+import (
+	"github.com/emicklei/go-restful"
+	"github.com/muesli/smolder"
+
+	apicontext "github.com/muesli/beehive/api/context"
+)
+
+// TimerResource is the resource responsible for /timers
+type TimerResource struct {
+	smolder.Resource
+}
+
+var (
+	_ smolder.GetIDSupported  = &TimerResource{}
+	_ smolder.GetSupported    = &TimerResource{}
+	_ smolder.PostSupported   = &TimerResource{}
+	_ smolder.PutSupported    = &TimerResource{}
+	_ smolder.DeleteSupported = &TimerResource{}
+)
+
+// Register this resource with the container to setup all the routes
+func (r *TimerResource) Register(container *restful.Container, config smolder.APIConfig, context smolder.APIContextFactory) {
+	r.Name = "TimerResource"
+	r.TypeName = "timer"
+	r.Endpoint = "timers"
+	r.Doc = "Manage timers"
+
+	r.Config = config
+	r.Context = context
+
+	r.Init(container, r)
+}
+
+// Reads returns the model that will be read by POST, PUT & PATCH operations
+func (r *TimerResource) Reads() interface{} {
+	return &TimerPostStruct{}
+}
+
+// Returns returns the model that will be returned
+func (r *TimerResource) Returns() interface{} {
+	return TimerResponse{}
+}
+
+// Validate checks an incoming request for data errors
+func (r *TimerResource) Validate(context smolder.APIContext, data interface{}, request *restful.Request) error {
+	return nil
+}
+
+// visible returns whether a request may see and manage the timers. Timers
+// belong to the hive itself, so requests selecting a profile can't, see
+// apicontext.Visible.
+func visible(request *restful.Request) bool {
+	return apicontext.Visible(request, "")
+}
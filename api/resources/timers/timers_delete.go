@@ -0,0 +1,60 @@
+/*
+ *    Copyright (C) 2026 Christian Muehlhaeuser
+ *
+ *    This program is free software: you can redistribute it and/or modify
+ *    it under the terms of the GNU Affero General Public License as published
+ *    by the Free Software Foundation, either version 3 of the License, or
+ *    (at your option) any later version.
+ *
+ *    This program is distributed in the hope that it will be useful,
+ *    but WITHOUT ANY WARRANTY; without even the implied warranty of
+ *    MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ *    GNU Affero General Public License for more details.
+ *
+ *    You should have received a copy of the GNU Affero General Public License
+ *    along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ *    Authors:
+ *      Christian Muehlhaeuser <muesli@gmail.com>
+ */
+
+package timers
+
+import (
+	"github.com/emicklei/go-restful"
+	"github.com/muesli/beehive/bees"
+	"github.com/muesli/smolder"
+)
+
+// DeleteAuthRequired returns true because all requests need authentication
+func (r *TimerResource) DeleteAuthRequired() bool {
+	return false
+}
+
+// DeleteDoc returns the description of this API endpoint
+func (r *TimerResource) DeleteDoc() string {
+	return "delete a timer"
+}
+
+// DeleteParams returns the parameters supported by this API endpoint
+func (r *TimerResource) DeleteParams() []*restful.Parameter {
+	return nil
+}
+
+// Delete processes an incoming DELETE request
+func (r *TimerResource) Delete(context smolder.APIContext, request *restful.Request, response *restful.Response) {
+	resp := TimerResponse{}
+	resp.Init(context)
+
+	id := request.PathParameter("timer-id")
+	if !visible(request) {
+		r.NotFound(request, response)
+		return
+	}
+
+	if err := bees.RemoveTimer(id); err == nil {
+		resp.Send(response)
+	} else {
+		r.NotFound(request, response)
+	}
+}
@@ -0,0 +1,80 @@
+/*
+ *    Copyright (C) 2026 Christian Muehlhaeuser
+ *
+ *    This program is free software: you can redistribute it and/or modify
+ *    it under the terms of the GNU Affero General Public License as published
+ *    by the Free Software Foundation, either version 3 of the License, or
+ *    (at your option) any later version.
+ *
+ *    This program is distributed in the hope that it will be useful,
+ *    but WITHOUT ANY WARRANTY; without even the implied warranty of
+ *    MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ *    GNU Affero General Public License for more details.
+ *
+ *    You should have received a copy of the GNU Affero General Public License
+ *    along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ *    Authors:
+ *      Christian Muehlhaeuser <muesli@gmail.com>
+ */
+
+package timers
+
+import (
+	"github.com/muesli/beehive/bees"
+
+	"github.com/emicklei/go-restful"
+	"github.com/muesli/smolder"
+)
+
+// GetAuthRequired returns true because all requests need authentication
+func (r *TimerResource) GetAuthRequired() bool {
+	return false
+}
+
+// GetByIDsAuthRequired returns true because all requests need authentication
+func (r *TimerResource) GetByIDsAuthRequired() bool {
+	return false
+}
+
+// GetDoc returns the description of this API endpoint
+func (r *TimerResource) GetDoc() string {
+	return "retrieve timers"
+}
+
+// GetParams returns the parameters supported by this API endpoint
+func (r *TimerResource) GetParams() []*restful.Parameter {
+	return nil
+}
+
+// GetByIDs sends out all items matching a set of IDs
+func (r *TimerResource) GetByIDs(ctx smolder.APIContext, request *restful.Request, response *restful.Response, ids []string) {
+	resp := TimerResponse{}
+	resp.Init(ctx)
+
+	for _, id := range ids {
+		timer := bees.GetTimer(id)
+		if timer == nil || !visible(request) {
+			r.NotFound(request, response)
+			return
+		}
+
+		resp.AddTimer(*timer)
+	}
+
+	resp.Send(response)
+}
+
+// Get sends out items matching the query parameters
+func (r *TimerResource) Get(ctx smolder.APIContext, request *restful.Request, response *restful.Response, params map[string][]string) {
+	resp := TimerResponse{}
+	resp.Init(ctx)
+
+	if visible(request) {
+		for _, timer := range bees.GetTimers() {
+			resp.AddTimer(timer)
+		}
+	}
+
+	resp.Send(response)
+}
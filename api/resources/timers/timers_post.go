@@ -0,0 +1,97 @@
+/*
+ *    Copyright (C) 2026 Christian Muehlhaeuser
+ *
+ *    This program is free software: you can redistribute it and/or modify
+ *    it under the terms of the GNU Affero General Public License as published
+ *    by the Free Software Foundation, either version 3 of the License, or
+ *    (at your option) any later version.
+ *
+ *    This program is distributed in the hope that it will be useful,
+ *    but WITHOUT ANY WARRANTY; without even the implied warranty of
+ *    MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ *    GNU Affero General Public License for more details.
+ *
+ *    You should have received a copy of the GNU Affero General Public License
+ *    along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ *    Authors:
+ *      Christian Muehlhaeuser <muesli@gmail.com>
+ */
+
+package timers
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/emicklei/go-restful"
+	"github.com/muesli/beehive/bees"
+	"github.com/muesli/smolder"
+)
+
+// TimerPostStruct holds all values of an incoming POST or PUT request. The
+// interval is a duration string, like "5m".
+type TimerPostStruct struct {
+	Timer struct {
+		Name     string    `json:"name"`
+		Cron     string    `json:"cron"`
+		Interval string    `json:"interval"`
+		At       time.Time `json:"at"`
+		Paused   bool      `json:"paused"`
+	} `json:"timer"`
+}
+
+// PostAuthRequired returns true because all requests need authentication
+func (r *TimerResource) PostAuthRequired() bool {
+	return false
+}
+
+// PostDoc returns the description of this API endpoint
+func (r *TimerResource) PostDoc() string {
+	return "create a new timer"
+}
+
+// PostParams returns the parameters supported by this API endpoint
+func (r *TimerResource) PostParams() []*restful.Parameter {
+	return nil
+}
+
+// Post processes an incoming POST (create) request
+func (r *TimerResource) Post(context smolder.APIContext, data interface{}, request *restful.Request, response *restful.Response) {
+	resp := TimerResponse{}
+	resp.Init(context)
+
+	if !visible(request) {
+		smolder.ErrorResponseHandler(request, response, nil, smolder.NewErrorResponse(
+			http.StatusForbidden,
+			errors.New("Not allowed to create timers"),
+			"TimerResource POST"))
+		return
+	}
+
+	pps := data.(*TimerPostStruct)
+	timer := bees.Timer{
+		Name:   pps.Timer.Name,
+		Cron:   pps.Timer.Cron,
+		At:     pps.Timer.At,
+		Paused: pps.Timer.Paused,
+	}
+	var err error
+	if pps.Timer.Interval != "" {
+		timer.Interval, err = time.ParseDuration(pps.Timer.Interval)
+	}
+	if err == nil {
+		err = bees.AddTimer(timer)
+	}
+	if err != nil {
+		smolder.ErrorResponseHandler(request, response, err, smolder.NewErrorResponse(
+			422, // Go 1.7+: http.StatusUnprocessableEntity,
+			err,
+			"TimerResource POST"))
+		return
+	}
+
+	resp.AddTimer(*bees.GetTimer(timer.Name))
+	resp.Send(response)
+}
@@ -0,0 +1,71 @@
+/*
+ *    Copyright (C) 2026 Christian Muehlhaeuser
+ *
+ *    This program is free software: you can redistribute it and/or modify
+ *    it under the terms of the GNU Affero General Public License as published
+ *    by the Free Software Foundation, either version 3 of the License, or
+ *    (at your option) any later version.
+ *
+ *    This program is distributed in the hope that it will be useful,
+ *    but WITHOUT ANY WARRANTY; without even the implied warranty of
+ *    MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ *    GNU Affero General Public License for more details.
+ *
+ *    You should have received a copy of the GNU Affero General Public License
+ *    along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ *    Authors:
+ *      Christian Muehlhaeuser <muesli@gmail.com>
+ */
+
+package timers
+
+import (
+	"github.com/emicklei/go-restful"
+	"github.com/muesli/beehive/bees"
+	"github.com/muesli/smolder"
+)
+
+// PutAuthRequired returns true because all requests need authentication
+func (r *TimerResource) PutAuthRequired() bool {
+	return false
+}
+
+// PutDoc returns the description of this API endpoint
+func (r *TimerResource) PutDoc() string {
+	return "pause or resume a timer"
+}
+
+// PutParams returns the parameters supported by this API endpoint
+func (r *TimerResource) PutParams() []*restful.Parameter {
+	return nil
+}
+
+// Put processes an incoming PUT (update) request. Only the paused state of
+// a timer can be changed.
+func (r *TimerResource) Put(context smolder.APIContext, data interface{}, request *restful.Request, response *restful.Response) {
+	resp := TimerResponse{}
+	resp.Init(context)
+
+	pps := data.(*TimerPostStruct)
+	id := request.PathParameter("timer-id")
+
+	if !visible(request) {
+		r.NotFound(request, response)
+		return
+	}
+
+	var err error
+	if pps.Timer.Paused {
+		err = bees.PauseTimer(id)
+	} else {
+		err = bees.ResumeTimer(id)
+	}
+	if err != nil {
+		r.NotFound(request, response)
+		return
+	}
+
+	resp.AddTimer(*bees.GetTimer(id))
+	resp.Send(response)
+}
@@ -0,0 +1,111 @@
+/*
+ *    Copyright (C) 2026 Christian Muehlhaeuser
+ *
+ *    This program is free software: you can redistribute it and/or modify
+ *    it under the terms of the GNU Affero General Public License as published
+ *    by the Free Software Foundation, either version 3 of the License, or
+ *    (at your option) any later version.
+ *
+ *    This program is distributed in the hope that it will be useful,
+ *    but WITHOUT ANY WARRANTY; without even the implied warranty of
+ *    MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ *    GNU Affero General Public License for more details.
+ *
+ *    You should have received a copy of the GNU Affero General Public License
+ *    along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ *    Authors:
+ *      Christian Muehlhaeuser <muesli@gmail.com>
+ */
+
+package timers
+
+import (
+	"sort"
+	"time"
+
+	restful "github.com/emicklei/go-restful"
+	"github.com/muesli/beehive/bees"
+
+	"github.com/muesli/smolder"
+)
+
+// TimerResponse is the common response to 'timer' requests
+type TimerResponse struct {
+	smolder.Response
+
+	Timers []timerInfoResponse `json:"timers,omitempty"`
+	timers map[string]*bees.Timer
+}
+
+type timerInfoResponse struct {
+	ID       string     `json:"id"`
+	Name     string     `json:"name"`
+	Cron     string     `json:"cron,omitempty"`
+	Interval string     `json:"interval,omitempty"`
+	At       *time.Time `json:"at,omitempty"`
+	Paused   bool       `json:"paused"`
+	Next     *time.Time `json:"next,omitempty"`
+	Fired    int64      `json:"fired"`
+}
+
+// Init a new response
+func (r *TimerResponse) Init(context smolder.APIContext) {
+	r.Parent = r
+	r.Context = context
+
+	r.timers = make(map[string]*bees.Timer)
+}
+
+// AddTimer adds a timer to the response
+func (r *TimerResponse) AddTimer(timer bees.Timer) {
+	r.timers[timer.Name] = &timer
+}
+
+// Send responds to a request with http.StatusOK
+func (r *TimerResponse) Send(response *restful.Response) {
+	var keys []string
+	for k := range r.timers {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		r.Timers = append(r.Timers, prepareTimerResponse(r.Context, r.timers[k]))
+	}
+
+	r.Response.Send(response)
+}
+
+// EmptyResponse returns an empty API response for this endpoint if there's no data to respond with
+func (r *TimerResponse) EmptyResponse() interface{} {
+	if len(r.timers) == 0 {
+		var out struct {
+			Timers interface{} `json:"timers"`
+		}
+		out.Timers = []timerInfoResponse{}
+		return out
+	}
+	return nil
+}
+
+func prepareTimerResponse(context smolder.APIContext, timer *bees.Timer) timerInfoResponse {
+	resp := timerInfoResponse{
+		ID:     timer.Name,
+		Name:   timer.Name,
+		Cron:   timer.Cron,
+		Paused: timer.Paused,
+		Fired:  timer.Fired,
+	}
+	if timer.Interval > 0 {
+		resp.Interval = timer.Interval.String()
+	}
+	if !timer.At.IsZero() {
+		resp.At = &timer.At
+	}
+	if !timer.Next.IsZero() {
+		resp.Next = &timer.Next
+	}
+
+	return resp
+}
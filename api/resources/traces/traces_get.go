@@ -0,0 +1,91 @@
+/*
+ *    Copyright (C) 2026 Christian Muehlhaeuser
+ *
+ *    This program is free software: you can redistribute it and/or modify
+ *    it under the terms of the GNU Affero General Public License as published
+ *    by the Free Software Foundation, either version 3 of the License, or
+ *    (at your option) any later version.
+ *
+ *    This program is distributed in the hope that it will be useful,
+ *    but WITHOUT ANY WARRANTY; without even the implied warranty of
+ *    MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ *    GNU Affero General Public License for more details.
+ *
+ *    You should have received a copy of the GNU Affero General Public License
+ *    along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ *    Authors:
+ *      Christian Muehlhaeuser <muesli@gmail.com>
+ */
+
+package traces
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/emicklei/go-restful"
+	apicontext "github.com/muesli/beehive/api/context"
+	"github.com/muesli/beehive/bees"
+	"github.com/muesli/smolder"
+)
+
+// GetAuthRequired returns true because all requests need authentication
+func (r *TraceResource) GetAuthRequired() bool {
+	return false
+}
+
+// GetDoc returns the description of this API endpoint
+func (r *TraceResource) GetDoc() string {
+	return "retrieve the most recent traces of a chain"
+}
+
+// GetParams returns the parameters supported by this API endpoint
+func (r *TraceResource) GetParams() []*restful.Parameter {
+	params := []*restful.Parameter{}
+	params = append(params, restful.QueryParameter("chain", "name of the chain").DataType("string").Required(true))
+	params = append(params, restful.QueryParameter("limit", "maximum number of traces to return").DataType("int"))
+
+	return params
+}
+
+// Get sends out items matching the query parameters
+func (r *TraceResource) Get(ctx smolder.APIContext, request *restful.Request, response *restful.Response, params map[string][]string) {
+	chain := request.QueryParameter("chain")
+	if len(chain) == 0 {
+		smolder.ErrorResponseHandler(request, response, nil, smolder.NewErrorResponse(
+			422, // Go 1.7+: http.StatusUnprocessableEntity,
+			errors.New("Missing chain name"),
+			"TraceResource GET"))
+		return
+	}
+
+	limit := 0
+	if s := request.QueryParameter("limit"); len(s) > 0 {
+		var err error
+		if limit, err = strconv.Atoi(s); err != nil || limit < 0 {
+			smolder.ErrorResponseHandler(request, response, err, smolder.NewErrorResponse(
+				422, // Go 1.7+: http.StatusUnprocessableEntity,
+				errors.New("Invalid limit"),
+				"TraceResource GET"))
+			return
+		}
+	}
+
+	if c := bees.GetChain(chain); c != nil && !apicontext.Visible(request, c.Scope) {
+		r.NotFound(request, response)
+		return
+	}
+
+	resp := TraceResponse{}
+	resp.Init(ctx)
+
+	for _, trace := range bees.ChainTraces(chain, limit) {
+		// traces of deleted chains are judged by the bee of their event
+		if apicontext.VisibleBee(request, trace.Event.Bee) {
+			resp.AddTrace(trace)
+		}
+	}
+
+	resp.Send(response)
+}
@@ -0,0 +1,61 @@
+/*
+ *    Copyright (C) 2026 Christian Muehlhaeuser
+ *
+ *    This program is free software: you can redistribute it and/or modify
+ *    it under the terms of the GNU Affero General Public License as published
+ *    by the Free Software Foundation, either version 3 of the License, or
+ *    (at your option) any later version.
+ *
+ *    This program is distributed in the hope that it will be useful,
+ *    but WITHOUT ANY WARRANTY; without even the implied warranty of
+ *    MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ *    GNU Affero General Public License for more details.
+ *
+ *    You should have received a copy of the GNU Affero General Public License
+ *    along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ *    Authors:
+ *      Christian Muehlhaeuser <muesli@gmail.com>
+ */
+
+package traces
+
+import (
+	"github.com/muesli/beehive/bees"
+
+	"github.com/muesli/smolder"
+)
+
+// TraceResponse is the common response to 'trace' requests
+type TraceResponse struct {
+	smolder.Response
+
+	Traces []bees.ChainTrace `json:"traces,omitempty"`
+	traces []bees.ChainTrace
+}
+
+// Init a new response
+func (r *TraceResponse) Init(context smolder.APIContext) {
+	r.Parent = r
+	r.Context = context
+
+	r.Traces = []bees.ChainTrace{}
+}
+
+// AddTrace adds a chain trace to the response
+func (r *TraceResponse) AddTrace(trace bees.ChainTrace) {
+	r.traces = append(r.traces, trace)
+	r.Traces = append(r.Traces, trace)
+}
+
+// EmptyResponse returns an empty API response for this endpoint if there's no data to respond with
+func (r *TraceResponse) EmptyResponse() interface{} {
+	if len(r.traces) == 0 {
+		var out struct {
+			Traces interface{} `json:"traces"`
+		}
+		out.Traces = []bees.ChainTrace{}
+		return out
+	}
+	return nil
+}
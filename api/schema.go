@@ -0,0 +1,52 @@
+/*
+ *    Copyright (C) 2026 Christian Muehlhaeuser
+ *
+ *    This program is free software: you can redistribute it and/or modify
+ *    it under the terms of the GNU Affero General Public License as published
+ *    by the Free Software Foundation, either version 3 of the License, or
+ *    (at your option) any later version.
+ *
+ *    This program is distributed in the hope that it will be useful,
+ *    but WITHOUT ANY WARRANTY; without even the implied warranty of
+ *    MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ *    GNU Affero General Public License for more details.
+ *
+ *    You should have received a copy of the GNU Affero General Public License
+ *    along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ *    Authors:
+ *      Christian Muehlhaeuser <muesli@gmail.com>
+ */
+
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+
+	restful "github.com/emicklei/go-restful"
+
+	"github.com/muesli/beehive/bees"
+)
+
+// schemaHandler serves the catalog of all hives, their options, events and
+// actions as JSON, or as Markdown documentation with format=markdown.
+func schemaHandler(req *restful.Request, resp *restful.Response) {
+	catalog := bees.GetCatalog()
+
+	switch req.QueryParameter("format") {
+	case "markdown":
+		resp.Header().Set("Content-Type", "text/markdown; charset=utf-8")
+		catalog.WriteMarkdown(resp.ResponseWriter)
+	case "", "json":
+		b, err := json.MarshalIndent(catalog, "", "  ")
+		if err != nil {
+			http.Error(resp.ResponseWriter, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		resp.Header().Set("Content-Type", "application/json")
+		resp.Write(b)
+	default:
+		http.Error(resp.ResponseWriter, "Unknown format", http.StatusBadRequest)
+	}
+}
@@ -0,0 +1,148 @@
+/*
+ *    Copyright (C) 2026 Christian Muehlhaeuser
+ *
+ *    This program is free software: you can redistribute it and/or modify
+ *    it under the terms of the GNU Affero General Public License as published
+ *    by the Free Software Foundation, either version 3 of the License, or
+ *    (at your option) any later version.
+ *
+ *    This program is distributed in the hope that it will be useful,
+ *    but WITHOUT ANY WARRANTY; without even the implied warranty of
+ *    MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ *    GNU Affero General Public License for more details.
+ *
+ *    You should have received a copy of the GNU Affero General Public License
+ *    along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ *    Authors:
+ *      Christian Muehlhaeuser <muesli@gmail.com>
+ */
+
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	restful "github.com/emicklei/go-restful"
+
+	"github.com/muesli/beehive/api/context"
+	"github.com/muesli/beehive/bees"
+)
+
+const (
+	// streamBufferSize is the number of messages buffered for each client
+	// of the stream. Messages for clients falling further behind get dropped.
+	streamBufferSize = 64
+	// streamKeepAlive is the interval in which idle streams get a comment
+	// sent, to stop proxies from closing the connection.
+	streamKeepAlive = 30 * time.Second
+)
+
+// streamMessage is a single message of the live activity stream.
+type streamMessage struct {
+	Type     string             `json:"type"`
+	Time     time.Time          `json:"time"`
+	Event    *bees.Event        `json:"event,omitempty"`
+	Chain    string             `json:"chain,omitempty"`
+	Action   *bees.Action       `json:"action,omitempty"`
+	Results  []bees.Placeholder `json:"results,omitempty"`
+	Duration time.Duration      `json:"duration,omitempty"`
+	Error    string             `json:"error,omitempty"`
+}
+
+// streamHandler streams the events, chain executions and action results of
+// the hive as server-sent events. The optional types parameter limits the
+// stream to a comma-separated list of "event", "chain" and "action". Only
+// the activity of the bees and chains visible to the request gets streamed.
+func streamHandler(req *restful.Request, resp *restful.Response) {
+	flusher, ok := resp.ResponseWriter.(http.Flusher)
+	if !ok {
+		http.Error(resp.ResponseWriter, "Streaming is not supported", http.StatusInternalServerError)
+		return
+	}
+
+	types := map[string]bool{"event": true, "chain": true, "action": true}
+	if t := req.QueryParameter("types"); t != "" {
+		types = make(map[string]bool)
+		for _, s := range strings.Split(t, ",") {
+			types[strings.TrimSpace(s)] = true
+		}
+	}
+
+	msgs := make(chan streamMessage, streamBufferSize)
+	send := func(msg streamMessage) {
+		if !types[msg.Type] {
+			return
+		}
+		msg.Time = time.Now()
+		select {
+		case msgs <- msg:
+		default:
+			// the client can't keep up, drop the message
+		}
+	}
+
+	hooks := []*bees.Hook{
+		bees.OnEvent(func(ev bees.Event) {
+			if !context.VisibleBee(req, ev.Bee) {
+				return
+			}
+			send(streamMessage{Type: "event", Event: &ev})
+		}),
+		bees.OnChainExecuted(func(exec bees.ChainExecution) {
+			if !context.VisibleChain(req, exec.ChainName) {
+				return
+			}
+			msg := streamMessage{Type: "chain", Chain: exec.ChainName, Event: &exec.TriggerEvent, Duration: exec.Duration}
+			if exec.Err != nil {
+				msg.Error = exec.Err.Error()
+			}
+			send(msg)
+		}),
+		bees.OnActionExecuted(func(chain string, action bees.Action, res []bees.Placeholder) {
+			if !context.VisibleBee(req, action.Bee) || !context.VisibleChain(req, chain) {
+				return
+			}
+			send(streamMessage{Type: "action", Chain: chain, Action: &action, Results: res})
+		}),
+	}
+	defer func() {
+		for _, h := range hooks {
+			h.Remove()
+		}
+	}()
+
+	w := resp.ResponseWriter
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	keepAlive := time.NewTicker(streamKeepAlive)
+	defer keepAlive.Stop()
+
+	for {
+		select {
+		case <-req.Request.Context().Done():
+			return
+		case <-keepAlive.C:
+			if _, err := fmt.Fprint(w, ": keep-alive\n\n"); err != nil {
+				return
+			}
+		case msg := <-msgs:
+			b, err := json.Marshal(msg)
+			if err != nil {
+				continue
+			}
+			if _, err := fmt.Fprintf(w, "event: %s\ndata: %s\n\n", msg.Type, b); err != nil {
+				return
+			}
+		}
+		flusher.Flush()
+	}
+}
@@ -0,0 +1,56 @@
+/*
+ *    Copyright (C) 2026 Christian Muehlhaeuser
+ *
+ *    This program is free software: you can redistribute it and/or modify
+ *    it under the terms of the GNU Affero General Public License as published
+ *    by the Free Software Foundation, either version 3 of the License, or
+ *    (at your option) any later version.
+ *
+ *    This program is distributed in the hope that it will be useful,
+ *    but WITHOUT ANY WARRANTY; without even the implied warranty of
+ *    MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ *    GNU Affero General Public License for more details.
+ *
+ *    You should have received a copy of the GNU Affero General Public License
+ *    along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ *    Authors:
+ *      Christian Muehlhaeuser <muesli@gmail.com>
+ */
+
+package api
+
+import (
+	"bytes"
+	"net/http"
+	"path"
+	"time"
+
+	restful "github.com/emicklei/go-restful"
+)
+
+// uiDir holds the files of the built-in admin interface.
+const uiDir = "ui"
+
+// uiHandler serves the built-in admin interface. It is a lightweight
+// alternative to the full admin interface, which needs to be installed
+// separately.
+func uiHandler(req *restful.Request, resp *restful.Response) {
+	subpath := path.Clean("/" + req.PathParameter("subpath"))
+	if subpath == "/" {
+		if req.Request.URL.Path == "/ui" {
+			// relative references only resolve below /ui/
+			http.Redirect(resp.ResponseWriter, req.Request, "ui/", http.StatusMovedPermanently)
+			return
+		}
+		subpath = "/index.html"
+	}
+
+	b, err := Asset(uiDir + subpath)
+	if err != nil {
+		http.NotFound(resp.ResponseWriter, req.Request)
+		return
+	}
+
+	http.ServeContent(resp.ResponseWriter, req.Request, subpath, time.Now(), bytes.NewReader(b))
+}
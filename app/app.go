@@ -58,3 +58,8 @@ func Run() {
 
 	flag.Parse()
 }
+
+// Args returns the command-line arguments remaining after parsing the flags
+func Args() []string {
+	return flag.Args()
+}
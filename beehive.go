@@ -26,21 +26,34 @@ package main
 import (
 	"encoding/json"
 	"fmt"
+	"net"
 	"net/url"
 	"os"
 	"os/signal"
+	"path/filepath"
+	"strings"
 	"syscall"
+	"time"
 
+	"github.com/fsnotify/fsnotify"
 	"github.com/mattn/go-colorable"
 	log "github.com/sirupsen/logrus"
 
 	"github.com/muesli/beehive/api"
+	apicontext "github.com/muesli/beehive/api/context"
 	"github.com/muesli/beehive/app"
 	"github.com/muesli/beehive/cfg"
+	"github.com/muesli/beehive/cli"
+	_ "github.com/muesli/beehive/enrichers/dns"
+	_ "github.com/muesli/beehive/enrichers/geo"
+	_ "github.com/muesli/beehive/enrichers/lookup"
 	_ "github.com/muesli/beehive/filters"
+	_ "github.com/muesli/beehive/filters/expr"
 	_ "github.com/muesli/beehive/filters/template"
+	_ "github.com/muesli/beehive/transformers/jq"
 
 	"github.com/muesli/beehive/bees"
+	"github.com/muesli/beehive/rpc"
 )
 
 var (
@@ -48,6 +61,17 @@ var (
 	versionFlag bool
 	debugFlag   bool
 	decryptFlag bool
+	logJSONFlag bool
+	logSinkFlag string
+	pluginsFlag string
+	journalFlag string
+	historyFlag string
+	dryRunFlag  bool
+	watchFlag   bool
+	remoteFlag  string
+	auditFlag   string
+	dataDirFlag string
+	grpcFlag    string
 )
 
 func main() {
@@ -70,6 +94,72 @@ func main() {
 			Value: false,
 			Desc:  "Turn on debugging",
 		},
+		{
+			V:     &logJSONFlag,
+			Name:  "logjson",
+			Value: false,
+			Desc:  "Write logs as JSON",
+		},
+		{
+			V:     &logSinkFlag,
+			Name:  "logsink",
+			Value: "",
+			Desc:  "Additionally send logs to syslog, syslog://host:port or journald",
+		},
+		{
+			V:     &pluginsFlag,
+			Name:  "plugins",
+			Value: "",
+			Desc:  "Comma-separated list of bee factory plugins to load",
+		},
+		{
+			V:     &journalFlag,
+			Name:  "journal",
+			Value: "",
+			Desc:  "File to journal events to while their bees aren't running",
+		},
+		{
+			V:     &historyFlag,
+			Name:  "eventhistory",
+			Value: "",
+			Desc:  "File to persist the event history of each bee to",
+		},
+		{
+			V:     &dryRunFlag,
+			Name:  "dryrun",
+			Value: false,
+			Desc:  "Only log the actions chains would execute",
+		},
+		{
+			V:     &watchFlag,
+			Name:  "watchconfig",
+			Value: false,
+			Desc:  "Reload the configuration whenever its file changes",
+		},
+		{
+			V:     &remoteFlag,
+			Name:  "remotesocket",
+			Value: "",
+			Desc:  "Unix socket to accept bee factories of remote processes on",
+		},
+		{
+			V:     &auditFlag,
+			Name:  "auditlog",
+			Value: "",
+			Desc:  "File to append the audit log of configuration changes and executed actions to",
+		},
+		{
+			V:     &dataDirFlag,
+			Name:  "datadir",
+			Value: "",
+			Desc:  "Directory to persist the state of bees in",
+		},
+		{
+			V:     &grpcFlag,
+			Name:  "grpc",
+			Value: "",
+			Desc:  "Address to serve the gRPC API on, e.g. localhost:8182",
+		},
 		{
 			V:     &decryptFlag,
 			Name:  "decrypt",
@@ -90,17 +180,84 @@ func main() {
 		decryptConfig(configURL)
 	}
 
-	api.Run()
+	if args := app.Args(); len(args) > 0 {
+		// client mode: manage the hive running at the canonical URL
+		if err := cli.Run(api.CanonicalURL(), args, os.Stdout); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
 
 	if debugFlag {
 		log.SetLevel(log.DebugLevel)
+		bees.SetLogLevel(bees.LogDebug)
 	} else {
 		log.SetLevel(log.InfoLevel)
+		bees.SetLogLevel(bees.LogInfo)
+	}
+	if logJSONFlag {
+		log.SetFormatter(&log.JSONFormatter{})
+		bees.SetLogJSON(true)
+	}
+	if len(logSinkFlag) > 0 {
+		hook, err := newLogSinkHook(logSinkFlag)
+		if err != nil {
+			log.Fatalf("Error setting up the log sink: %v", err)
+		}
+		log.AddHook(hook)
+		bees.AddLogHook(hook)
 	}
 
 	log.Println()
 	log.Println("Beehive is buzzing...")
 
+	if pluginsFlag != "" {
+		for _, path := range strings.Split(pluginsFlag, ",") {
+			if err := bees.RegisterFactoryPlugin(strings.TrimSpace(path)); err != nil {
+				log.Fatalf("Error loading plugin: %v", err)
+			}
+		}
+	}
+
+	if remoteFlag != "" {
+		os.Remove(remoteFlag)
+		l, err := net.Listen("unix", remoteFlag)
+		if err != nil {
+			log.Fatalf("Error listening for remote bees: %v", err)
+		}
+		go bees.ServeRemoteBees(l)
+	}
+
+	if key := os.Getenv(bees.SecretKeyEnvVar); key != "" {
+		if err := bees.SetSecretKey(key); err != nil {
+			log.Fatalf("Error setting up the secret key: %v", err)
+		}
+	}
+
+	if dryRunFlag {
+		log.Println("Dry-run mode: actions will only get logged")
+	}
+
+	if dataDirFlag != "" {
+		bees.SetDataDir(dataDirFlag)
+	}
+	if journalFlag != "" {
+		if err := bees.SetEventJournal(journalFlag, bees.DefaultJournalRetention); err != nil {
+			log.Fatalf("Error opening event journal: %v", err)
+		}
+	}
+	if historyFlag != "" {
+		if err := bees.SetEventHistoryFile(historyFlag); err != nil {
+			log.Fatalf("Error opening event history: %v", err)
+		}
+	}
+	if auditFlag != "" {
+		if err := bees.SetAuditLog(auditFlag, bees.DefaultAuditMaxFileSize, bees.DefaultAuditBackups); err != nil {
+			log.Fatalf("Error opening audit log: %v", err)
+		}
+	}
+
 	config, err := cfg.New(configURL)
 	if err != nil {
 		log.Fatalf("Error creating the configuration %s", err)
@@ -126,16 +283,44 @@ func main() {
 		}
 	}
 
-	// Load actions from config
-	bees.SetActions(config.Actions)
-	// Load chains from config
-	bees.SetChains(config.Chains)
-	// Initialize bees
-	bees.StartBees(config.Bees)
+	// Load profiles & API credentials from config
+	if err := bees.SetProfiles(config.Profiles); err != nil {
+		log.Fatalf("Error loading profiles: %v", err)
+	}
+	if err := apicontext.SetCredentials(config.Credentials); err != nil {
+		log.Fatalf("Error loading API credentials: %v", err)
+	}
+	hive := bees.NewHive(bees.HiveOptions{
+		Bees:        config.Bees,
+		Actions:     config.Actions,
+		Chains:      config.Chains,
+		Profiles:    config.Profiles,
+		References:  config.References,
+		Enrichments: config.Enrichments,
+		DryRun:      dryRunFlag,
+	})
+
+	// Serve the APIs only once access to them is restricted
+	api.Run()
+	if grpcFlag != "" {
+		l, err := net.Listen("tcp", grpcFlag)
+		if err != nil {
+			log.Fatalf("Error listening for gRPC clients: %v", err)
+		}
+		go rpc.NewServer(hive).Serve(l)
+	}
+
+	// Initialize bees, skipping misconfigured ones
+	if err := hive.Start(); err != nil {
+		log.Warnf("Not all bees could be started: %v", err)
+	}
 
 	// Wait for signals
 	ch := make(chan os.Signal, 1)
 	signal.Notify(ch, os.Interrupt, syscall.SIGHUP, syscall.SIGTERM, syscall.SIGKILL)
+	if watchFlag {
+		watchConfig(config, ch)
+	}
 
 	for s := range ch {
 		log.Println("Got signal:", s)
@@ -143,14 +328,7 @@ func main() {
 		abort := false
 		switch s {
 		case syscall.SIGHUP:
-			err := config.Load()
-			if err != nil {
-				log.Panicf("Error loading config from %s: %v", config.URL(), err)
-			}
-			bees.StopBees()
-			bees.SetActions(config.Actions)
-			bees.SetChains(config.Chains)
-			bees.StartBees(config.Bees)
+			reloadConfig(config)
 
 		case syscall.SIGTERM:
 			fallthrough
@@ -168,15 +346,95 @@ func main() {
 
 	// Save actions & chains to config
 	log.Printf("Saving config to %s", config.URL())
-	config.Bees = bees.BeeConfigs()
-	config.Chains = bees.GetChains()
-	config.Actions = bees.GetActions()
+	opts := hive.Options()
+	config.Bees = opts.Bees
+	config.Chains = opts.Chains
+	config.Actions = opts.Actions
 	err = config.Save()
 	if err != nil {
 		log.Printf("Error saving config file to %s! %v", config.URL(), err)
 	}
 }
 
+// reloadConfig reloads the configuration and applies it to the running hive.
+// Only bees whose config changed get restarted, see bees.ApplyBees. A broken
+// configuration gets logged and leaves the hive untouched.
+func reloadConfig(config *cfg.Config) {
+	err := config.Load()
+	if err != nil {
+		log.Errorf("Error loading config from %s: %v", config.URL(), err)
+		return
+	}
+	if err := bees.SetProfiles(config.Profiles); err != nil {
+		log.Errorf("Error loading profiles: %v", err)
+		return
+	}
+	if err := apicontext.SetCredentials(config.Credentials); err != nil {
+		log.Errorf("Error loading API credentials: %v", err)
+		return
+	}
+	bees.SetReferences(config.References)
+	bees.SetEnrichments(config.Enrichments)
+	bees.SetActions(config.Actions)
+	bees.SetChains(config.Chains)
+
+	changes, errs := bees.ApplyBees(config.Bees)
+	for _, err := range errs {
+		log.Errorf("Error applying config: %v", err)
+	}
+	log.Infof("Reloaded config: %d bees started, %d stopped, %d reconfigured, %d restarted",
+		len(changes.Started), len(changes.Stopped), len(changes.Reconfigured), len(changes.Restarted))
+}
+
+// watchConfig watches the configuration file and sends a SIGHUP to ch
+// whenever it changed. Editors often write files in several steps, so
+// changes get reported once the file stayed unchanged for a moment.
+func watchConfig(config *cfg.Config, ch chan os.Signal) {
+	switch config.URL().Scheme {
+	case "", "file", "crypto":
+	default:
+		log.Warnf("Can't watch the configuration at %s, only files can be watched", config.URL())
+		return
+	}
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		log.Errorf("Error watching the configuration: %v", err)
+		return
+	}
+	// watch the directory, so files replaced by a rename are noticed as well
+	path := config.URL().Path
+	if err := watcher.Add(filepath.Dir(path)); err != nil {
+		log.Errorf("Error watching the configuration: %v", err)
+		watcher.Close()
+		return
+	}
+
+	go func() {
+		var settle <-chan time.Time
+		for {
+			select {
+			case ev, ok := <-watcher.Events:
+				if !ok {
+					return
+				}
+				if filepath.Clean(ev.Name) == filepath.Clean(path) && ev.Op&(fsnotify.Write|fsnotify.Create|fsnotify.Rename) != 0 {
+					settle = time.After(500 * time.Millisecond)
+				}
+			case err, ok := <-watcher.Errors:
+				if !ok {
+					return
+				}
+				log.Errorf("Error watching the configuration: %v", err)
+			case <-settle:
+				settle = nil
+				log.Infof("Configuration file %s changed", path)
+				ch <- syscall.SIGHUP
+			}
+		}
+	}()
+}
+
 func decryptConfig(u string) {
 	b := cfg.AESBackend{}
 
@@ -206,4 +464,5 @@ func decryptConfig(u string) {
 func init() {
 	log.SetFormatter(&log.TextFormatter{ForceColors: true})
 	log.SetOutput(colorable.NewColorableStdout())
+	bees.SetLogOutput(colorable.NewColorableStdout())
 }
@@ -0,0 +1,102 @@
+/*
+ *    Copyright (C) 2026 Christian Muehlhaeuser
+ *
+ *    This program is free software: you can redistribute it and/or modify
+ *    it under the terms of the GNU Affero General Public License as published
+ *    by the Free Software Foundation, either version 3 of the License, or
+ *    (at your option) any later version.
+ *
+ *    This program is distributed in the hope that it will be useful,
+ *    but WITHOUT ANY WARRANTY; without even the implied warranty of
+ *    MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ *    GNU Affero General Public License for more details.
+ *
+ *    You should have received a copy of the GNU Affero General Public License
+ *    along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ *    Authors:
+ *      Christian Muehlhaeuser <muesli@gmail.com>
+ */
+
+// Package bees is Beehive's central module system.
+package bees
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+type actionCacheEntry struct {
+	result  []Placeholder
+	expires time.Time
+}
+
+var (
+	actionCache      = make(map[string]actionCacheEntry)
+	actionCacheMutex sync.Mutex
+
+	actionCacheHits   int64
+	actionCacheMisses int64
+)
+
+// actionCacheKey returns the key an action's result gets cached under.
+func actionCacheKey(action Action) string {
+	opts, err := json.Marshal(action.Options)
+	if err != nil {
+		opts = []byte(fmt.Sprintf("%v", action.Options))
+	}
+
+	return action.Bee + "/" + action.Name + "/" + string(opts)
+}
+
+// cachedActionResult returns the cached result for an action, if there is
+// one that hasn't expired yet.
+func cachedActionResult(action Action) ([]Placeholder, bool) {
+	key := actionCacheKey(action)
+
+	actionCacheMutex.Lock()
+	defer actionCacheMutex.Unlock()
+
+	e, ok := actionCache[key]
+	if !ok || clock.Now().After(e.expires) {
+		delete(actionCache, key)
+		atomic.AddInt64(&actionCacheMisses, 1)
+		return nil, false
+	}
+
+	atomic.AddInt64(&actionCacheHits, 1)
+	return e.result, true
+}
+
+// cacheActionResult stores an action's result for ttl.
+func cacheActionResult(action Action, result []Placeholder, ttl time.Duration) {
+	actionCacheMutex.Lock()
+	defer actionCacheMutex.Unlock()
+
+	actionCache[actionCacheKey(action)] = actionCacheEntry{
+		result:  result,
+		expires: clock.Now().Add(ttl),
+	}
+}
+
+// ActionCacheStats returns the number of action cache hits and misses.
+func ActionCacheStats() (hits int64, misses int64) {
+	return atomic.LoadInt64(&actionCacheHits), atomic.LoadInt64(&actionCacheMisses)
+}
+
+// InvalidateActionCache drops all cached action results. If bee is not
+// empty, only the results of actions executed by that bee are dropped.
+func InvalidateActionCache(bee string) {
+	actionCacheMutex.Lock()
+	defer actionCacheMutex.Unlock()
+
+	for k := range actionCache {
+		if len(bee) == 0 || strings.HasPrefix(k, bee+"/") {
+			delete(actionCache, k)
+		}
+	}
+}
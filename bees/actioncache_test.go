@@ -0,0 +1,49 @@
+package bees
+
+import (
+	"testing"
+	"time"
+)
+
+func TestActionCache(t *testing.T) {
+	fc := &fakeClock{now: time.Now()}
+	SetClock(fc)
+	defer SetClock(nil)
+	defer InvalidateActionCache("")
+
+	a := Action{Bee: "weatherbee", Name: "current", Options: Placeholders{{Name: "city", Value: "Berlin"}}}
+	result := []Placeholder{{Name: "temperature", Value: 21}}
+
+	hits, misses := ActionCacheStats()
+	if _, ok := cachedActionResult(a); ok {
+		t.Fatal("Expected cache miss for uncached action")
+	}
+	cacheActionResult(a, result, time.Minute)
+
+	r, ok := cachedActionResult(a)
+	if !ok || len(r) != 1 || r[0].Value != 21 {
+		t.Fatal("Expected cache hit with the stored result")
+	}
+
+	b := a
+	b.Options = Placeholders{{Name: "city", Value: "Paris"}}
+	if _, ok := cachedActionResult(b); ok {
+		t.Error("Actions with different options must not share cache entries")
+	}
+
+	fc.now = fc.now.Add(2 * time.Minute)
+	if _, ok := cachedActionResult(a); ok {
+		t.Error("Expected cache entry to expire")
+	}
+
+	h, m := ActionCacheStats()
+	if h-hits != 1 || m-misses != 3 {
+		t.Errorf("Unexpected cache stats: %d hits, %d misses", h-hits, m-misses)
+	}
+
+	cacheActionResult(a, result, time.Minute)
+	InvalidateActionCache("weatherbee")
+	if _, ok := cachedActionResult(a); ok {
+		t.Error("Expected cache entry to be invalidated")
+	}
+}
@@ -0,0 +1,207 @@
+/*
+ *    Copyright (C) 2026 Christian Muehlhaeuser
+ *
+ *    This program is free software: you can redistribute it and/or modify
+ *    it under the terms of the GNU Affero General Public License as published
+ *    by the Free Software Foundation, either version 3 of the License, or
+ *    (at your option) any later version.
+ *
+ *    This program is distributed in the hope that it will be useful,
+ *    but WITHOUT ANY WARRANTY; without even the implied warranty of
+ *    MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ *    GNU Affero General Public License for more details.
+ *
+ *    You should have received a copy of the GNU Affero General Public License
+ *    along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ *    Authors:
+ *      Christian Muehlhaeuser <muesli@gmail.com>
+ */
+
+// Package bees is Beehive's central module system.
+package bees
+
+import (
+	"sync"
+	"time"
+)
+
+// actionAgingInterval is the time after which a queued action's priority gets
+// raised by one, so low priority actions can't be starved indefinitely by a
+// steady stream of more urgent ones.
+const actionAgingInterval = 10 * time.Second
+
+type queuedAction struct {
+	priority int
+	seq      uint64
+	enqueued time.Time
+	run      func()
+	done     chan interface{}
+}
+
+// effectivePriority returns the action's priority, raised by its age.
+func (qa *queuedAction) effectivePriority(now time.Time) int {
+	return qa.priority + int(now.Sub(qa.enqueued)/actionAgingInterval)
+}
+
+// actionQueue executes a bee's actions one at a time, by priority.
+type actionQueue struct {
+	sync.Mutex
+	items []*queuedAction
+	seq   uint64
+	wake  chan struct{}
+	quit  chan struct{}
+
+	// closed gets set once the queue stopped accepting actions
+	closed bool
+}
+
+var (
+	actionQueues     = make(map[string]*actionQueue)
+	actionQueueMutex sync.Mutex
+)
+
+// SerializeActions makes a bee execute its actions one at a time instead of
+// concurrently. Queued actions are executed by priority, highest first, and
+// in order of arrival for equal priorities. To avoid starvation, the priority
+// of a waiting action rises by one every ten seconds.
+//
+// Disabling serialization executes the remaining queued actions before new
+// ones are dispatched concurrently again.
+func SerializeActions(bee string, enabled bool) {
+	actionQueueMutex.Lock()
+	defer actionQueueMutex.Unlock()
+
+	q, ok := actionQueues[bee]
+	if enabled && !ok {
+		q = &actionQueue{
+			wake: make(chan struct{}, 1),
+			quit: make(chan struct{}),
+		}
+		actionQueues[bee] = q
+		go q.work()
+	}
+	if !enabled && ok {
+		q.Lock()
+		q.closed = true
+		q.Unlock()
+
+		close(q.quit)
+		delete(actionQueues, bee)
+	}
+}
+
+// renameActionQueue moves the action queue of a renamed bee to its new name.
+func renameActionQueue(old, name string) {
+	actionQueueMutex.Lock()
+	defer actionQueueMutex.Unlock()
+
+	if q, ok := actionQueues[old]; ok {
+		actionQueues[name] = q
+		delete(actionQueues, old)
+	}
+}
+
+// runAction executes f, in the bee's action queue if it has one. It blocks
+// until f has been executed and re-panics if f panicked.
+func runAction(bee string, priority int, f func()) {
+	actionQueueMutex.Lock()
+	q, ok := actionQueues[bee]
+	actionQueueMutex.Unlock()
+
+	if !ok {
+		f()
+		return
+	}
+
+	if e := <-q.push(priority, f); e != nil {
+		panic(e)
+	}
+}
+
+// push adds f to the queue and returns a channel that receives f's panic
+// value, or nil, once it has been executed. Once the queue got closed, f gets
+// executed right away instead, as its worker might already be gone.
+func (q *actionQueue) push(priority int, f func()) chan interface{} {
+	q.Lock()
+	if q.closed {
+		q.Unlock()
+
+		done := make(chan interface{}, 1)
+		func() {
+			defer func() {
+				done <- recover()
+			}()
+
+			f()
+		}()
+		return done
+	}
+
+	q.seq++
+	qa := &queuedAction{
+		priority: priority,
+		seq:      q.seq,
+		enqueued: clock.Now(),
+		run:      f,
+		done:     make(chan interface{}, 1),
+	}
+	q.items = append(q.items, qa)
+	q.Unlock()
+
+	select {
+	case q.wake <- struct{}{}:
+	default:
+	}
+
+	return qa.done
+}
+
+// pop removes and returns the most urgent queued action, or nil.
+func (q *actionQueue) pop() *queuedAction {
+	q.Lock()
+	defer q.Unlock()
+
+	if len(q.items) == 0 {
+		return nil
+	}
+
+	now := clock.Now()
+	best := 0
+	for i, qa := range q.items {
+		p, bp := qa.effectivePriority(now), q.items[best].effectivePriority(now)
+		if p > bp || (p == bp && qa.seq < q.items[best].seq) {
+			best = i
+		}
+	}
+
+	qa := q.items[best]
+	q.items = append(q.items[:best], q.items[best+1:]...)
+	return qa
+}
+
+// drain executes all queued actions.
+func (q *actionQueue) drain() {
+	for qa := q.pop(); qa != nil; qa = q.pop() {
+		func() {
+			defer func() {
+				qa.done <- recover()
+			}()
+
+			qa.run()
+		}()
+	}
+}
+
+// work executes queued actions until the queue gets closed.
+func (q *actionQueue) work() {
+	for {
+		select {
+		case <-q.wake:
+			q.drain()
+		case <-q.quit:
+			q.drain()
+			return
+		}
+	}
+}
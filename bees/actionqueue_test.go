@@ -0,0 +1,121 @@
+package bees
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestActionQueuePriority(t *testing.T) {
+	SerializeActions("notifybee", true)
+	defer SerializeActions("notifybee", false)
+
+	block := make(chan struct{})
+	started := make(chan struct{})
+	go runAction("notifybee", 0, func() {
+		close(started)
+		<-block
+	})
+	<-started
+
+	actionQueueMutex.Lock()
+	q := actionQueues["notifybee"]
+	actionQueueMutex.Unlock()
+
+	// the queue is blocked, so the actions get queued in order of arrival
+	var order []int
+	var mutex sync.Mutex
+	var done []chan interface{}
+	for _, p := range []int{0, 1, 10, 1} {
+		p := p
+		done = append(done, q.push(p, func() {
+			mutex.Lock()
+			order = append(order, p)
+			mutex.Unlock()
+		}))
+	}
+
+	close(block)
+	for _, d := range done {
+		<-d
+	}
+
+	expected := []int{10, 1, 1, 0}
+	for i := range expected {
+		if order[i] != expected[i] {
+			t.Fatalf("Expected actions to run in order %v, got %v", expected, order)
+		}
+	}
+}
+
+func TestActionQueueAging(t *testing.T) {
+	fc := &fakeClock{now: time.Now()}
+	SetClock(fc)
+	defer SetClock(nil)
+
+	q := &actionQueue{wake: make(chan struct{}, 1)}
+	q.push(0, func() {})
+	fc.now = fc.now.Add(3 * actionAgingInterval)
+	q.push(2, func() {})
+
+	if qa := q.pop(); qa.priority != 0 {
+		t.Error("Expected aged low priority action to be executed first")
+	}
+}
+
+func TestActionQueueClosed(t *testing.T) {
+	SerializeActions("closedbee", true)
+	actionQueueMutex.Lock()
+	q := actionQueues["closedbee"]
+	actionQueueMutex.Unlock()
+	SerializeActions("closedbee", false)
+
+	// the queue's worker is gone, so the action must not wait for it
+	ran := false
+	select {
+	case e := <-q.push(0, func() { ran = true }):
+		if e != nil || !ran {
+			t.Errorf("Expected the action to be executed, got panic %v", e)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Expected actions pushed to a closed queue to be executed")
+	}
+}
+
+func TestActionQueuePanic(t *testing.T) {
+	SerializeActions("panicbee", true)
+	defer SerializeActions("panicbee", false)
+
+	defer func() {
+		if e := recover(); e != "boom" {
+			t.Errorf("Expected panic to be propagated, got %v", e)
+		}
+	}()
+	runAction("panicbee", 0, func() {
+		panic("boom")
+	})
+}
+
+func TestActionQueueRemovedBee(t *testing.T) {
+	newRecordingBee("queuedbee")
+	SerializeActions("queuedbee", true)
+	if err := RenameBee("queuedbee", "renamedqueuedbee"); err != nil {
+		t.Fatal(err)
+	}
+
+	actionQueueMutex.Lock()
+	_, old := actionQueues["queuedbee"]
+	_, renamed := actionQueues["renamedqueuedbee"]
+	actionQueueMutex.Unlock()
+	if old || !renamed {
+		t.Error("Expected the action queue to follow the renamed bee")
+	}
+
+	DeleteBee(GetBee("renamedqueuedbee"))
+	actionQueueMutex.Lock()
+	_, renamed = actionQueues["renamedqueuedbee"]
+	actionQueueMutex.Unlock()
+	if renamed {
+		t.Error("Expected the action queue of a deleted bee to be removed")
+	}
+}
@@ -22,12 +22,12 @@
 package bees
 
 import (
-	"bytes"
-	"text/template"
-
-	log "github.com/sirupsen/logrus"
-
-	"github.com/muesli/beehive/templatehelper"
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
 )
 
 // Action describes an action.
@@ -36,21 +36,139 @@ type Action struct {
 	Bee     string
 	Name    string
 	Options Placeholders
+
+	// BroadcastPolicy decides how failures are handled when Bee is a
+	// pattern matching several bees: "best-effort" (the default) ignores
+	// failing bees, while "fail-fast" aborts the chain as soon as any bee
+	// fails, cancelling the action on the remaining bees.
+	BroadcastPolicy string `json:"BroadcastPolicy,omitempty"`
+
+	// Priority decides the order in which queued actions get executed by
+	// bees that serialize their actions, see SerializeActions.
+	Priority int `json:"Priority,omitempty"`
+
+	// CacheTTL enables caching the action's results for the given duration.
+	// Only actions whose descriptor is marked as Cacheable get cached.
+	CacheTTL time.Duration `json:"CacheTTL,omitempty"`
+
+	// Compensate references the ID of an action undoing this action's
+	// effects. When a later action of the same chain fails, the compensations
+	// of all actions executed so far get run in reverse order. Compensations
+	// are best-effort: a failing compensation gets logged and skipped.
+	Compensate string `json:"Compensate,omitempty"`
+
+	// Delay postpones executing the action when its chain fires. Delayed
+	// actions don't hold up the chain's subsequent actions, and their results
+	// aren't available to them. Pending delayed actions get cancelled when
+	// the hive stops.
+	Delay time.Duration `json:"Delay,omitempty"`
+	// CancelOn names an event which cancels the delayed action if it occurs
+	// before the action got executed, e.g. to debounce noisy sensors.
+	CancelOn *Event `json:"CancelOn,omitempty"`
+
+	// Timeout is the time after which the action gets abandoned, overriding
+	// the default action timeout. A negative timeout disables it.
+	Timeout time.Duration `json:"Timeout,omitempty"`
+
+	// ResultEvent names an event which gets emitted by the action's bee with
+	// the action's results as its options, so other chains can process them.
+	// Nothing gets emitted if the action didn't return any results.
+	ResultEvent string `json:"ResultEvent,omitempty"`
+
+	// Retries is how often a failed action gets retried, e.g. to survive
+	// network blips. Failures of such actions don't fail their chain: the
+	// action gets queued for retrying in the background, and the chain
+	// carries on without its results. Retries back off exponentially,
+	// starting at RetryBackoff or DefaultRetryBackoff if it's unset. Actions
+	// still failing after their last retry end up in the dead-letter queue.
+	Retries      int           `json:"Retries,omitempty"`
+	RetryBackoff time.Duration `json:"RetryBackoff,omitempty"`
+}
+
+// DefaultActionTimeout is the default time after which actions get abandoned.
+const DefaultActionTimeout = 30 * time.Second
+
+// ActionFailedEvent gets emitted by the SystemBee when an action panicked,
+// returned an error or got abandoned because it exceeded its timeout.
+const ActionFailedEvent = "action_failed"
+
+// StreamingBee is an optional interface for bees whose actions produce large
+// or continuous results, like a log tail or a big download. Instead of
+// returning all placeholders at once, the bee sends them in chunks to stream,
+// each of which gets emitted as a separate "<action>_chunk" event.
+//
+// The stream is unbuffered: sending a chunk blocks until it has been handed
+// to the event handler, which throttles fast producers. Chunks sent after the
+// bee has been stopped or ctx is done are discarded, so implementations should
+// return from StreamAction once their SigChan gets closed or ctx is done.
+type StreamingBee interface {
+	StreamAction(ctx context.Context, action Action, stream chan<- Placeholders) error
+}
+
+// FallibleBee is an optional interface for bees that report failing actions
+// by returning an error, instead of panicking. The hive calls ActionE instead
+// of Action for these bees. A returned error fails the chain executing the
+// action, just like a panic would, see Chain.OnError.
+type FallibleBee interface {
+	ActionE(ctx context.Context, action Action) ([]Placeholder, error)
 }
 
 var (
-	actions []Action
+	actions      []Action
+	actionsMutex sync.RWMutex
+
+	defaultActionTimeout = int64(DefaultActionTimeout)
 )
 
-// GetActions returns all configured actions.
+// SetDefaultActionTimeout sets the time after which actions without a
+// Timeout of their own get abandoned. 0 disables the default timeout.
+func SetDefaultActionTimeout(d time.Duration) {
+	atomic.StoreInt64(&defaultActionTimeout, int64(d))
+}
+
+// timeout returns the time after which the action gets abandoned, 0 if never.
+// The action's own Timeout takes precedence over the ActionTimeout of its
+// bee, which takes precedence over the default timeout.
+func (a *Action) timeout() time.Duration {
+	d := a.Timeout
+	if d == 0 {
+		if c, ok := instanceConfig(a.Bee); ok {
+			d = c.ActionTimeout
+		}
+	}
+	if d != 0 {
+		if d < 0 {
+			return 0
+		}
+		return d
+	}
+
+	return time.Duration(atomic.LoadInt64(&defaultActionTimeout))
+}
+
+type chainNameKey struct{}
+
+// withChainName returns a context remembering the chain executing actions.
+func withChainName(ctx context.Context, name string) context.Context {
+	return context.WithValue(ctx, chainNameKey{}, name)
+}
+
+// GetActions returns a copy of all configured actions.
 func GetActions() []Action {
-	return actions
+	actionsMutex.RLock()
+	defer actionsMutex.RUnlock()
+
+	return append([]Action{}, actions...)
 }
 
-// GetAction returns one action with a specific ID.
+// GetAction returns a copy of the action with a specific ID.
 func GetAction(id string) *Action {
+	actionsMutex.RLock()
+	defer actionsMutex.RUnlock()
+
 	for _, a := range actions {
 		if a.ID == id {
+			a.Options = append(Placeholders{}, a.Options...)
 			return &a
 		}
 	}
@@ -60,11 +178,61 @@ func GetAction(id string) *Action {
 
 // SetActions sets the currently configured actions.
 func SetActions(as []Action) {
+	actionsMutex.Lock()
+	defer actionsMutex.Unlock()
+
+	actions = append([]Action{}, as...)
+}
+
+// addActions appends to the currently configured actions.
+func addActions(as ...Action) {
+	actionsMutex.Lock()
+	defer actionsMutex.Unlock()
+
+	actions = append(append([]Action{}, actions...), as...)
+}
+
+// UpdateAction replaces the configured action sharing a's ID. Returns whether
+// such an action existed.
+func UpdateAction(a Action) bool {
+	actionsMutex.Lock()
+	defer actionsMutex.Unlock()
+
+	as := append([]Action{}, actions...)
+	for i := range as {
+		if as[i].ID == a.ID {
+			as[i] = a
+			actions = as
+			return true
+		}
+	}
+
+	return false
+}
+
+// RemoveAction removes the action with a specific ID. Returns whether such an
+// action existed.
+func RemoveAction(id string) bool {
+	actionsMutex.Lock()
+	defer actionsMutex.Unlock()
+
+	as := make([]Action, 0, len(actions))
+	for _, a := range actions {
+		if a.ID != id {
+			as = append(as, a)
+		}
+	}
+	if len(as) == len(actions) {
+		return false
+	}
+
 	actions = as
+	return true
 }
 
-// execAction executes an action and map its ins & outs.
-func execAction(action Action, opts map[string]interface{}) bool {
+// resolveAction returns a copy of action with its options' templates
+// executed against opts.
+func resolveAction(action Action, opts map[string]interface{}) Action {
 	a := Action{
 		Bee:  action.Bee,
 		Name: action.Name,
@@ -77,18 +245,18 @@ func execAction(action Action, opts map[string]interface{}) bool {
 
 		switch opt.Value.(type) {
 		case string:
-			var value bytes.Buffer
-
-			tmpl, err := template.New(action.Bee + "_" + action.Name + "_" + opt.Name).Funcs(templatehelper.FuncMap).Parse(opt.Value.(string))
-			if err == nil {
-				err = tmpl.Execute(&value, opts)
+			for _, name := range placeholderRefs(opt.Value.(string)) {
+				if _, ok := opts[name]; !ok {
+					logger.Debugf("\t\tMissing placeholder %v in option %v of action %v / %v", name, opt.Name, action.Bee, action.Name)
+				}
 			}
+			value, err := renderTemplate(action.Bee+"_"+action.Name+"_"+opt.Name, opt.Value.(string), opts)
 			if err != nil {
 				panic(err)
 			}
 
 			ph.Type = "string"
-			ph.Value = value.String()
+			ph.Value = value
 
 		default:
 			ph.Type = opt.Type
@@ -97,22 +265,300 @@ func execAction(action Action, opts map[string]interface{}) bool {
 		a.Options = append(a.Options, ph)
 	}
 
-	bee := GetBee(a.Bee)
-	if (*bee).IsRunning() {
-		(*bee).LogAction()
+	return a
+}
 
-		log.Debugln("\tExecuting action:", a.Bee, "/", a.Name, "-", GetActionDescriptor(&a).Description)
-		for _, v := range a.Options {
-			log.Debugln("\t\tOptions:", v)
+// execAction executes an action and map its ins & outs. The event that
+// triggered the action is passed as cause. Returns the action's results.
+func execAction(ctx context.Context, action Action, opts map[string]interface{}, cause *Event) []Placeholder {
+	a := resolveAction(action, opts)
+	bee := resolveBee(a.Bee, chainScopeOf(ctx))
+	if bee != nil {
+		a.Bee = (*bee).Name()
+	}
+	trace := traceOf(ctx).with(traceStep{Bee: a.Bee, Action: a.Name})
+	ctx = withTrace(ctx, trace)
+	defer beginTrace(a.Bee, trace)()
+
+	var res []Placeholder
+	traced := traceAction(ctx, a)
+	defer func() {
+		e := recover()
+		traced(res, e)
+		if e != nil {
+			panic(e)
 		}
+	}()
+	if bee == nil {
+		panic(fmt.Errorf("%w: %s", ErrUnknownBee, a.Bee))
+	}
+	if !profileReachable(beeScope(a.Bee), chainScopeOf(ctx)) {
+		panic(fmt.Errorf("Bee %s belongs to another profile", a.Bee))
+	}
+	if BeePaused(a.Bee) {
+		logger.Debugf("\tNot executing action on paused bee: %v / %v - %v", a.Bee, a.Name, GetActionDescriptor(&a).Description)
+	} else if (*bee).IsRunning() {
+		beginWork(a.Bee)
+		defer endWork(a.Bee)
+
+		runAction(a.Bee, action.Priority, func() {
+			defer acquireActionSlot()()
+			defer func() {
+				if e := recover(); e != nil {
+					if s := statsOf(bee); s != nil {
+						s.recordActionError(e)
+					}
+					panic(e)
+				}
+			}()
+			(*bee).LogAction()
+
+			logger.Debugf("\tExecuting action: %v / %v - %v", a.Bee, a.Name, GetActionDescriptor(&a).Description)
+			for _, v := range a.Options {
+				logger.Debugf("\t\tOptions: %v", v)
+			}
 
-		(*bee).Action(a)
+			ad := GetActionDescriptor(&a)
+			if sb, ok := (*bee).(StreamingBee); ok {
+				streamAction(ctx, bee, sb, a, cause)
+			} else if action.CacheTTL > 0 && ad.Cacheable {
+				var ok bool
+				if res, ok = cachedActionResult(a); ok {
+					logger.Debugf("\t\tUsing cached result")
+				} else {
+					res = callAction(ctx, bee, a, action.timeout(), cause)
+					cacheActionResult(a, res, action.CacheTTL)
+				}
+			} else {
+				res = callAction(ctx, bee, a, action.timeout(), cause)
+			}
+		})
 	} else {
-		log.Debugln("\tNot executing action on stopped bee:", a.Bee, "/", a.Name, "-", GetActionDescriptor(&a).Description)
+		logger.Debugf("\tNot executing action on stopped bee: %v / %v - %v", a.Bee, a.Name, GetActionDescriptor(&a).Description)
 		for _, v := range a.Options {
-			log.Debugln("\t\tOptions:", v)
+			logger.Debugf("\t\tOptions: %v", v)
 		}
 	}
 
-	return true
+	return res
+}
+
+// callAction executes an action on a bee, abandoning it once timeout passed
+// or ctx got cancelled. When the action panics, returns an error or times
+// out, an ActionFailedEvent gets emitted. All failures get raised as a panic, which
+// fails the chain executing the action.
+func callAction(ctx context.Context, bee *BeeInterface, a Action, timeout time.Duration, cause *Event) []Placeholder {
+	parent := ctx
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	type result struct {
+		res []Placeholder
+		err error
+	}
+	done := make(chan result, 1)
+	started := time.Now()
+	go func() {
+		defer func() {
+			if e := recover(); e != nil {
+				done <- result{err: fmt.Errorf("%v", e)}
+			}
+		}()
+
+		if fb, ok := (*bee).(FallibleBee); ok {
+			res, err := fb.ActionE(ctx, a)
+			done <- result{res: res, err: err}
+			return
+		}
+		done <- result{res: (*bee).Action(ctx, a)}
+	}()
+
+	var err error
+	stats := statsOf(bee)
+	select {
+	case r := <-done:
+		if stats != nil {
+			stats.recordActionTime(time.Since(started))
+		}
+		auditAction(ctx, a, started, r.err)
+		if r.err == nil {
+			return r.res
+		}
+		err = r.err
+	case <-ctx.Done():
+		err = fmt.Errorf("Action abandoned: %v", ctx.Err())
+		auditAction(ctx, a, started, err)
+		if parent.Err() != nil {
+			// the chain got cancelled, which gets reported by the chain
+			panic(err)
+		}
+		if stats != nil {
+			stats.recordActionTimeout(time.Since(started))
+		}
+	}
+
+	chain, _ := ctx.Value(chainNameKey{}).(string)
+	logger.Errorf("\tAction failed: %v / %v - %v", a.Bee, a.Name, err)
+	go injectEvent(deriveEvent(cause, Event{
+		Bee:  SystemBee,
+		Name: ActionFailedEvent,
+		Options: Placeholders{
+			{Name: "chain", Type: "string", Value: chain},
+			{Name: "bee", Type: "string", Value: a.Bee},
+			{Name: "action", Type: "string", Value: a.Name},
+			{Name: "error", Type: "string", Value: err.Error()},
+		},
+	}))
+	panic(err)
+}
+
+// compensate runs the compensating actions of executed, in reverse order.
+func compensate(ctx context.Context, executed []Action, opts map[string]interface{}, cause *Event) {
+	for i := len(executed) - 1; i >= 0; i-- {
+		if len(executed[i].Compensate) == 0 {
+			continue
+		}
+		comp := GetAction(executed[i].Compensate)
+		if comp == nil {
+			logger.Errorf("\t\tERROR: Unknown compensating action referenced by %v", executed[i].ID)
+			continue
+		}
+
+		logger.Infof("\tCompensating action: %v / %v with %v / %v", executed[i].Bee, executed[i].Name, comp.Bee, comp.Name)
+		func() {
+			defer func() {
+				if e := recover(); e != nil {
+					logger.Errorf("\tCompensating action failed: %v / %v - %v", comp.Bee, comp.Name, e)
+				}
+			}()
+
+			if isBroadcast(*comp) {
+				execBroadcast(ctx, *comp, opts, cause)
+			} else {
+				execAction(ctx, *comp, opts, cause)
+			}
+		}()
+	}
+}
+
+// isBroadcast returns whether an action targets all bees matching a pattern,
+// e.g. "sensor.*", rather than a single bee.
+func isBroadcast(action Action) bool {
+	return strings.ContainsAny(action.Bee, "*?[")
+}
+
+// placeholderMap converts placeholders to a map.
+func placeholderMap(ph []Placeholder) map[string]interface{} {
+	m := make(map[string]interface{})
+	for _, p := range ph {
+		m[p.Name] = p.Value
+	}
+
+	return m
+}
+
+// execBroadcast executes an action concurrently on all bees matching the
+// action's bee pattern, see matchesBeePattern. Returns the results of all
+// succeeding bees, keyed by bee name. With the "fail-fast" policy, the first
+// failure cancels the remaining bees' actions and fails the chain right away.
+func execBroadcast(ctx context.Context, action Action, opts map[string]interface{}, cause *Event) map[string]interface{} {
+	scope := chainScopeOf(ctx)
+	var targets []string
+	for _, bee := range GetBees() {
+		name := (*bee).Name()
+		if matchesBeePattern(action.Bee, name, scope) {
+			targets = append(targets, name)
+		}
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	type outcome struct {
+		name string
+		res  []Placeholder
+		err  error
+	}
+	// buffered, so abandoned bees can still deliver their outcome
+	done := make(chan outcome, len(targets))
+	for _, name := range targets {
+		go func(name string) {
+			a := action
+			a.Bee = name
+
+			chain, _ := ctx.Value(chainNameKey{}).(string)
+			if err := checkCycle(ctx, chain, name, a.Name, cause); err != nil {
+				done <- outcome{name: name, err: err}
+				return
+			}
+
+			o := outcome{name: name}
+			defer func() {
+				if e := recover(); e != nil {
+					o.err = fmt.Errorf("%v", e)
+				}
+				done <- o
+			}()
+			o.res = execAction(ctx, a, opts, cause)
+		}(name)
+	}
+
+	results := make(map[string]interface{})
+	for range targets {
+		o := <-done
+		if o.err == nil {
+			results[o.name] = placeholderMap(o.res)
+			continue
+		}
+
+		logger.Errorf("\tBroadcast action failed on bee %v: %v", o.name, o.err)
+		if action.BroadcastPolicy == "fail-fast" {
+			cancel()
+			panic(fmt.Sprintf("Broadcast action %s failed on bee %s: %v", action.Name, o.name, o.err))
+		}
+	}
+
+	return results
+}
+
+// streamAction executes a streaming action and emits an event for each chunk
+// of results it produces. Handing a chunk to the event handler gives up once
+// ctx is done: the handler might be waiting for a free chain worker, which
+// could be the very worker executing the streaming action.
+func streamAction(ctx context.Context, bee *BeeInterface, sb StreamingBee, action Action, cause *Event) {
+	stream := make(chan Placeholders)
+	done := make(chan error, 1)
+
+	go func() {
+		defer close(stream)
+		defer func() {
+			if e := recover(); e != nil {
+				done <- fmt.Errorf("%v", e)
+			}
+		}()
+
+		done <- sb.StreamAction(ctx, action, stream)
+	}()
+
+	for chunk := range stream {
+		if !(*bee).IsRunning() || ctx.Err() != nil {
+			// keep draining, so the bee doesn't block forever
+			continue
+		}
+
+		if !deliverEventContext(ctx, deriveEvent(cause, Event{
+			Bee:     action.Bee,
+			Name:    action.Name + "_chunk",
+			Options: chunk,
+		})) {
+			logger.Warnf("\tDiscarding chunk of streaming action: %v / %v", action.Bee, action.Name)
+		}
+	}
+
+	if err := <-done; err != nil {
+		logger.Errorf("\tStreaming action failed: %v / %v - %v", action.Bee, action.Name, err)
+	}
 }
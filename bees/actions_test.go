@@ -0,0 +1,224 @@
+package bees
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+// streamingBee streams the values of its "chunks" option, one per chunk.
+type streamingBee struct {
+	recordingBee
+}
+
+func (mod *streamingBee) StreamAction(ctx context.Context, action Action, stream chan<- Placeholders) error {
+	mod.recordingBee.Action(ctx, action)
+
+	var chunks []string
+	action.Options.Bind("chunks", &chunks)
+	for _, c := range chunks {
+		select {
+		case stream <- Placeholders{{Name: "line", Type: "string", Value: c}}:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return nil
+}
+
+// newStreamingBee registers and starts a new streamingBee.
+func newStreamingBee(name string) *streamingBee {
+	mod := &streamingBee{recordingBee{Bee: NewBee(name, "recordingbee", "", BeeOptions{})}}
+	RegisterBee(mod)
+	mod.Start()
+
+	return mod
+}
+
+func TestStreamAction(t *testing.T) {
+	events := make(chan Event)
+	defer useEventChannel(events)()
+
+	mod := newStreamingBee("streambee")
+	defer DeleteBee(GetBee("streambee"))
+	bee := GetBee("streambee")
+
+	action := Action{Bee: "streambee", Name: "tail", Options: Placeholders{
+		{Name: "chunks", Type: "[]string", Value: []string{"one", "two", "three"}},
+	}}
+	cause := &Event{ID: "cause", CorrelationID: "correlation"}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		streamAction(context.Background(), bee, mod, action, cause)
+	}()
+
+	for _, exp := range []string{"one", "two", "three"} {
+		var ev Event
+		for ev.Bee != "streambee" {
+			select {
+			case ev = <-events:
+			case <-time.After(time.Second):
+				t.Fatalf("Expected chunk %s to be emitted", exp)
+			}
+		}
+
+		if ev.Name != "tail_chunk" || ev.Options.Value("line") != exp {
+			t.Errorf("Expected chunk %s, got %v: %v", exp, ev.Name, ev.Options)
+		}
+		if ev.CausationID != cause.ID || ev.CorrelationID != cause.CorrelationID {
+			t.Errorf("Expected the chunk to be derived from its cause, got %+v", ev)
+		}
+	}
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Expected the streaming action to finish")
+	}
+	if got := mod.executed(); len(got) != 1 || got[0] != "tail" {
+		t.Errorf("Expected the streaming action to be executed once, got %v", got)
+	}
+}
+
+func TestStreamActionCancel(t *testing.T) {
+	// nobody handles events, like while the event handler is waiting for
+	// a free chain worker
+	defer useEventChannel(make(chan Event))()
+
+	mod := newStreamingBee("blockedstreambee")
+	defer DeleteBee(GetBee("blockedstreambee"))
+	bee := GetBee("blockedstreambee")
+
+	action := Action{Bee: "blockedstreambee", Name: "tail", Options: Placeholders{
+		{Name: "chunks", Type: "[]string", Value: []string{"one", "two"}},
+	}}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		streamAction(ctx, bee, mod, action, nil)
+	}()
+
+	select {
+	case <-done:
+		t.Fatal("Expected the streaming action to wait for the event handler")
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Expected the streaming action to give up once its context is done")
+	}
+}
+
+func TestExecActionUnknownBee(t *testing.T) {
+	defer func() {
+		err, _ := recover().(error)
+		if !errors.Is(err, ErrUnknownBee) {
+			t.Errorf("Expected ErrUnknownBee, got %v", err)
+		}
+	}()
+
+	execAction(context.Background(), Action{Bee: "nosuchbee", Name: "record"}, nil, nil)
+}
+
+func TestExecBroadcastScopes(t *testing.T) {
+	for _, c := range []BeeConfig{
+		{Name: "rss-global", Class: "recordingbee"},
+		{Name: "rss-home", Class: "recordingbee", Scope: "home"},
+		{Name: "irc-home", Class: "recordingbee", Scope: "home"},
+		{Name: "rss-work", Class: "recordingbee", Scope: "work"},
+	} {
+		mod, err := StartBee(c)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer DeleteBee(mod)
+	}
+
+	tests := []struct {
+		pattern string
+		scope   string
+		bees    []string
+	}{
+		{"rss-*", "home", []string{"home/rss-home"}},
+		{"rss-*", "", []string{"rss-global"}},
+		{"work/rss-*", "home", []string{"work/rss-work"}},
+		{"*/rss-*", "", []string{"home/rss-home", "work/rss-work"}},
+	}
+	for _, tt := range tests {
+		ctx := withChainScope(context.Background(), tt.scope)
+		rs := execBroadcast(ctx, Action{Bee: tt.pattern, Name: "post"}, nil, nil)
+		if len(rs) != len(tt.bees) {
+			t.Errorf("Expected %s in scope %q to reach %v, got %v", tt.pattern, tt.scope, tt.bees, rs)
+			continue
+		}
+		for _, name := range tt.bees {
+			if _, ok := rs[name]; !ok {
+				t.Errorf("Expected %s in scope %q to reach %s, got %v", tt.pattern, tt.scope, name, rs)
+			}
+		}
+	}
+}
+
+// sluggishBee fails its actions if its name says so, and otherwise keeps
+// working on them until they get cancelled.
+type sluggishBee struct {
+	recordingBee
+	cancelled chan struct{}
+}
+
+func (mod *sluggishBee) Action(ctx context.Context, action Action) []Placeholder {
+	if strings.HasSuffix(mod.Name(), "-failing") {
+		panic("failed")
+	}
+
+	select {
+	case <-ctx.Done():
+		close(mod.cancelled)
+	case <-time.After(5 * time.Second):
+	}
+	return nil
+}
+
+func TestExecBroadcastFailFast(t *testing.T) {
+	defer useEventChannel(make(chan Event, 10))()
+
+	var slow *sluggishBee
+	for _, name := range []string{"sluggish-failing", "sluggish-slow"} {
+		mod := &sluggishBee{
+			recordingBee: recordingBee{Bee: NewBee(name, "recordingbee", "", BeeOptions{})},
+			cancelled:    make(chan struct{}),
+		}
+		RegisterBee(mod)
+		mod.Start()
+		defer DeleteBee(GetBee(name))
+		slow = mod
+	}
+
+	started := time.Now()
+	func() {
+		defer func() {
+			if e := recover(); e == nil {
+				t.Error("Expected the failing bee to fail the broadcast")
+			}
+		}()
+		execBroadcast(context.Background(), Action{Bee: "sluggish-*", Name: "work", BroadcastPolicy: "fail-fast"}, nil, nil)
+	}()
+	if time.Since(started) > time.Second {
+		t.Error("Expected the broadcast to fail without waiting for the slow bee")
+	}
+
+	select {
+	case <-slow.cancelled:
+	case <-time.After(time.Second):
+		t.Error("Expected the slow bee's action to be cancelled")
+	}
+}
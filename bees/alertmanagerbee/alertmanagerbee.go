@@ -0,0 +1,348 @@
+/*
+ *    Copyright (C) 2026 Christian Muehlhaeuser
+ *
+ *    This program is free software: you can redistribute it and/or modify
+ *    it under the terms of the GNU Affero General Public License as published
+ *    by the Free Software Foundation, either version 3 of the License, or
+ *    (at your option) any later version.
+ *
+ *    This program is distributed in the hope that it will be useful,
+ *    but WITHOUT ANY WARRANTY; without even the implied warranty of
+ *    MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ *    GNU Affero General Public License for more details.
+ *
+ *    You should have received a copy of the GNU Affero General Public License
+ *    along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ *    Authors:
+ *      Christian Muehlhaeuser <muesli@gmail.com>
+ */
+
+// Package alertmanagerbee is a Bee that receives notifications from the
+// Prometheus Alertmanager webhook receiver.
+package alertmanagerbee
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"net"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/muesli/beehive/bees"
+)
+
+// AlertmanagerBee is a Bee that receives Alertmanager notifications.
+type AlertmanagerBee struct {
+	bees.Bee
+
+	addr         string
+	path         string
+	token        string
+	sendResolved bool
+
+	eventChan chan bees.Event
+}
+
+// notification is the payload Alertmanager posts to webhook receivers.
+type notification struct {
+	Version           string            `json:"version"`
+	GroupKey          string            `json:"groupKey"`
+	TruncatedAlerts   int               `json:"truncatedAlerts"`
+	Status            string            `json:"status"`
+	Receiver          string            `json:"receiver"`
+	GroupLabels       map[string]string `json:"groupLabels"`
+	CommonLabels      map[string]string `json:"commonLabels"`
+	CommonAnnotations map[string]string `json:"commonAnnotations"`
+	ExternalURL       string            `json:"externalURL"`
+	Alerts            []alert           `json:"alerts"`
+}
+
+type alert struct {
+	Status       string            `json:"status"`
+	Labels       map[string]string `json:"labels"`
+	Annotations  map[string]string `json:"annotations"`
+	StartsAt     time.Time         `json:"startsAt"`
+	EndsAt       time.Time         `json:"endsAt"`
+	GeneratorURL string            `json:"generatorURL"`
+	Fingerprint  string            `json:"fingerprint"`
+}
+
+// Run executes the Bee's event loop.
+func (mod *AlertmanagerBee) Run(ctx context.Context, cin chan bees.Event) {
+	mod.eventChan = cin
+
+	mux := http.NewServeMux()
+	mux.Handle(mod.path, mod)
+	srv := &http.Server{Addr: mod.addr, Handler: mux}
+
+	l, err := net.Listen("tcp", mod.addr)
+	if err != nil {
+		mod.LogErrorf("Can't listen on %s", mod.addr)
+		return
+	}
+
+	go func() {
+		err := srv.Serve(l)
+		if err != nil && err != http.ErrServerClosed {
+			mod.LogErrorf("Server error: %v", err)
+		}
+	}()
+
+	select {
+	case <-mod.SigChan:
+	case <-ctx.Done():
+	}
+	srv.Close()
+}
+
+func (mod *AlertmanagerBee) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	if mod.token != "" {
+		auth := strings.TrimPrefix(req.Header.Get("Authorization"), "Bearer ")
+		if subtle.ConstantTimeCompare([]byte(auth), []byte(mod.token)) != 1 {
+			mod.LogErrorf("Rejected notification from %s: invalid token", req.RemoteAddr)
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+	}
+
+	defer req.Body.Close()
+	var n notification
+	if err := json.NewDecoder(http.MaxBytesReader(w, req.Body, 4*1024*1024)).Decode(&n); err != nil {
+		http.Error(w, "Invalid payload", http.StatusBadRequest)
+		return
+	}
+
+	for _, ev := range mod.events(n) {
+		select {
+		case mod.eventChan <- ev:
+		case <-mod.Context().Done():
+			http.Error(w, "Bee is stopping", http.StatusServiceUnavailable)
+			return
+		case <-req.Context().Done():
+			return
+		}
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+// events maps a notification to a group event, followed by an alert or
+// resolved event for every alert in the group.
+func (mod *AlertmanagerBee) events(n notification) []bees.Event {
+	var firing, resolved int
+	names := []string{}
+	seen := map[string]bool{}
+	for _, a := range n.Alerts {
+		if a.Status == "resolved" {
+			resolved++
+		} else {
+			firing++
+		}
+		if name := a.Labels["alertname"]; name != "" && !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+
+	group := bees.Event{
+		Bee:  mod.Name(),
+		Name: "group",
+		Options: []bees.Placeholder{
+			{
+				Name:  "status",
+				Type:  "string",
+				Value: n.Status,
+			},
+			{
+				Name:  "receiver",
+				Type:  "string",
+				Value: n.Receiver,
+			},
+			{
+				Name:  "group_key",
+				Type:  "string",
+				Value: n.GroupKey,
+			},
+			{
+				Name:  "group_labels",
+				Type:  "map",
+				Value: stringMap(n.GroupLabels),
+			},
+			{
+				Name:  "common_labels",
+				Type:  "map",
+				Value: stringMap(n.CommonLabels),
+			},
+			{
+				Name:  "common_annotations",
+				Type:  "map",
+				Value: stringMap(n.CommonAnnotations),
+			},
+			{
+				Name:  "external_url",
+				Type:  "url",
+				Value: n.ExternalURL,
+			},
+			{
+				Name:  "alertnames",
+				Type:  "[]string",
+				Value: names,
+			},
+			{
+				Name:  "firing",
+				Type:  "int",
+				Value: firing,
+			},
+			{
+				Name:  "resolved",
+				Type:  "int",
+				Value: resolved,
+			},
+			{
+				Name:  "truncated",
+				Type:  "int",
+				Value: n.TruncatedAlerts,
+			},
+		},
+	}
+	events := []bees.Event{group}
+
+	for _, a := range n.Alerts {
+		name := "alert"
+		if a.Status == "resolved" {
+			if !mod.sendResolved {
+				continue
+			}
+			name = "resolved"
+		}
+
+		ev := bees.Event{
+			Bee:  mod.Name(),
+			Name: name,
+			Options: []bees.Placeholder{
+				{
+					Name:  "status",
+					Type:  "string",
+					Value: a.Status,
+				},
+				{
+					Name:  "alertname",
+					Type:  "string",
+					Value: a.Labels["alertname"],
+				},
+				{
+					Name:  "severity",
+					Type:  "string",
+					Value: a.Labels["severity"],
+				},
+				{
+					Name:  "summary",
+					Type:  "string",
+					Value: a.Annotations["summary"],
+				},
+				{
+					Name:  "description",
+					Type:  "string",
+					Value: a.Annotations["description"],
+				},
+				{
+					Name:  "labels",
+					Type:  "map",
+					Value: stringMap(a.Labels),
+				},
+				{
+					Name:  "annotations",
+					Type:  "map",
+					Value: stringMap(a.Annotations),
+				},
+				{
+					Name:  "starts_at",
+					Type:  "timestamp",
+					Value: a.StartsAt,
+				},
+				{
+					Name:  "ends_at",
+					Type:  "timestamp",
+					Value: a.EndsAt,
+				},
+				{
+					Name:  "generator_url",
+					Type:  "url",
+					Value: a.GeneratorURL,
+				},
+				{
+					Name:  "fingerprint",
+					Type:  "string",
+					Value: a.Fingerprint,
+				},
+				{
+					Name:  "receiver",
+					Type:  "string",
+					Value: n.Receiver,
+				},
+				{
+					Name:  "group_key",
+					Type:  "string",
+					Value: n.GroupKey,
+				},
+			},
+		}
+
+		// every label and annotation is also available as its own
+		// placeholder, e.g. label_instance or annotation_runbook_url
+		for _, k := range sortedKeys(a.Labels) {
+			ev.Options.SetValue("label_"+k, "string", a.Labels[k])
+		}
+		for _, k := range sortedKeys(a.Annotations) {
+			ev.Options.SetValue("annotation_"+k, "string", a.Annotations[k])
+		}
+
+		events = append(events, ev)
+	}
+
+	return events
+}
+
+func stringMap(m map[string]string) map[string]interface{} {
+	r := make(map[string]interface{}, len(m))
+	for k, v := range m {
+		r[k] = v
+	}
+	return r
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// ReloadOptions parses the config options and initializes the Bee.
+func (mod *AlertmanagerBee) ReloadOptions(options bees.BeeOptions) {
+	mod.SetOptions(options)
+
+	options.Bind("address", &mod.addr)
+
+	mod.path = "/"
+	options.Bind("path", &mod.path)
+	if !strings.HasPrefix(mod.path, "/") {
+		mod.path = "/" + mod.path
+	}
+
+	mod.token = ""
+	options.Bind("token", &mod.token)
+
+	mod.sendResolved = true
+	options.Bind("send_resolved", &mod.sendResolved)
+}
@@ -0,0 +1,249 @@
+/*
+ *    Copyright (C) 2026 Christian Muehlhaeuser
+ *
+ *    This program is free software: you can redistribute it and/or modify
+ *    it under the terms of the GNU Affero General Public License as published
+ *    by the Free Software Foundation, either version 3 of the License, or
+ *    (at your option) any later version.
+ *
+ *    This program is distributed in the hope that it will be useful,
+ *    but WITHOUT ANY WARRANTY; without even the implied warranty of
+ *    MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ *    GNU Affero General Public License for more details.
+ *
+ *    You should have received a copy of the GNU Affero General Public License
+ *    along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ *    Authors:
+ *      Christian Muehlhaeuser <muesli@gmail.com>
+ */
+
+package alertmanagerbee
+
+import (
+	"github.com/muesli/beehive/bees"
+)
+
+// AlertmanagerBeeFactory is a factory for AlertmanagerBees.
+type AlertmanagerBeeFactory struct {
+	bees.BeeFactory
+}
+
+// New returns a new Bee instance configured with the supplied options.
+func (factory *AlertmanagerBeeFactory) New(name, description string, options bees.BeeOptions) bees.BeeInterface {
+	bee := AlertmanagerBee{
+		Bee: bees.NewBee(name, factory.ID(), description, options),
+	}
+	bee.ReloadOptions(options)
+
+	return &bee
+}
+
+// ID returns the ID of this Bee.
+func (factory *AlertmanagerBeeFactory) ID() string {
+	return "alertmanagerbee"
+}
+
+// Name returns the name of this Bee.
+func (factory *AlertmanagerBeeFactory) Name() string {
+	return "Alertmanager"
+}
+
+// Description returns the description of this Bee.
+func (factory *AlertmanagerBeeFactory) Description() string {
+	return "Receives alerts from the Prometheus Alertmanager"
+}
+
+// Image returns the filename of an image for this Bee.
+func (factory *AlertmanagerBeeFactory) Image() string {
+	return "prometheusbee.png"
+}
+
+// LogoColor returns the preferred logo background color (used by the admin interface).
+func (factory *AlertmanagerBeeFactory) LogoColor() string {
+	return "#e6522c"
+}
+
+// Options returns the options available to configure this Bee.
+func (factory *AlertmanagerBeeFactory) Options() []bees.BeeOptionDescriptor {
+	opts := []bees.BeeOptionDescriptor{
+		{
+			Name:        "address",
+			Description: "Which addr to listen on, eg: 0.0.0.0:9097",
+			Type:        "address",
+			Mandatory:   true,
+		},
+		{
+			Name:        "path",
+			Description: "Path of the webhook endpoint, eg: /alerts",
+			Type:        "string",
+			Default:     "/",
+		},
+		{
+			Name:        "token",
+			Description: "Bearer token Alertmanager has to send (optional)",
+			Type:        "password",
+		},
+		{
+			Name:        "send_resolved",
+			Description: "Whether to emit events for resolved alerts",
+			Type:        "bool",
+			Default:     true,
+		},
+	}
+	return opts
+}
+
+// Events describes the available events provided by this Bee.
+func (factory *AlertmanagerBeeFactory) Events() []bees.EventDescriptor {
+	alert := []bees.PlaceholderDescriptor{
+		{
+			Name:        "status",
+			Description: "Status of the alert, firing or resolved",
+			Type:        "string",
+		},
+		{
+			Name:        "alertname",
+			Description: "Name of the alert",
+			Type:        "string",
+		},
+		{
+			Name:        "severity",
+			Description: "Value of the severity label",
+			Type:        "string",
+		},
+		{
+			Name:        "summary",
+			Description: "Value of the summary annotation",
+			Type:        "string",
+		},
+		{
+			Name:        "description",
+			Description: "Value of the description annotation",
+			Type:        "string",
+		},
+		{
+			Name:        "labels",
+			Description: "Map of all labels. Each label is also available as label_<name>",
+			Type:        "map",
+		},
+		{
+			Name:        "annotations",
+			Description: "Map of all annotations. Each annotation is also available as annotation_<name>",
+			Type:        "map",
+		},
+		{
+			Name:        "starts_at",
+			Description: "Time the alert started firing",
+			Type:        "timestamp",
+		},
+		{
+			Name:        "ends_at",
+			Description: "Time the alert was resolved",
+			Type:        "timestamp",
+		},
+		{
+			Name:        "generator_url",
+			Description: "URL of the entity that caused the alert",
+			Type:        "url",
+		},
+		{
+			Name:        "fingerprint",
+			Description: "Fingerprint identifying the alert",
+			Type:        "string",
+		},
+		{
+			Name:        "receiver",
+			Description: "Name of the Alertmanager receiver",
+			Type:        "string",
+		},
+		{
+			Name:        "group_key",
+			Description: "Key identifying the group of the alert",
+			Type:        "string",
+		},
+	}
+
+	events := []bees.EventDescriptor{
+		{
+			Namespace:   factory.Name(),
+			Name:        "group",
+			Description: "A notification for a group of alerts was received",
+			Options: []bees.PlaceholderDescriptor{
+				{
+					Name:        "status",
+					Description: "Status of the group, firing if any alert is firing",
+					Type:        "string",
+				},
+				{
+					Name:        "receiver",
+					Description: "Name of the Alertmanager receiver",
+					Type:        "string",
+				},
+				{
+					Name:        "group_key",
+					Description: "Key identifying the group",
+					Type:        "string",
+				},
+				{
+					Name:        "group_labels",
+					Description: "Labels the alerts are grouped by",
+					Type:        "map",
+				},
+				{
+					Name:        "common_labels",
+					Description: "Labels shared by all alerts of the group",
+					Type:        "map",
+				},
+				{
+					Name:        "common_annotations",
+					Description: "Annotations shared by all alerts of the group",
+					Type:        "map",
+				},
+				{
+					Name:        "external_url",
+					Description: "URL of the Alertmanager",
+					Type:        "url",
+				},
+				{
+					Name:        "alertnames",
+					Description: "Names of the alerts in the group",
+					Type:        "[]string",
+				},
+				{
+					Name:        "firing",
+					Description: "Number of firing alerts",
+					Type:        "int",
+				},
+				{
+					Name:        "resolved",
+					Description: "Number of resolved alerts",
+					Type:        "int",
+				},
+				{
+					Name:        "truncated",
+					Description: "Number of alerts Alertmanager left out of the notification",
+					Type:        "int",
+				},
+			},
+		},
+		{
+			Namespace:   factory.Name(),
+			Name:        "alert",
+			Description: "An alert is firing",
+			Options:     alert,
+		},
+		{
+			Namespace:   factory.Name(),
+			Name:        "resolved",
+			Description: "An alert has been resolved",
+			Options:     alert,
+		},
+	}
+	return events
+}
+
+func init() {
+	f := AlertmanagerBeeFactory{}
+	bees.RegisterFactory(&f)
+}
@@ -24,6 +24,7 @@
 package alertoverbee
 
 import (
+	"context"
 	"net/http"
 	"net/url"
 	"regexp"
@@ -38,7 +39,7 @@ type AlertOverBee struct {
 }
 
 // Action triggers the action passed to it.
-func (mod *AlertOverBee) Action(action bees.Action) []bees.Placeholder {
+func (mod *AlertOverBee) Action(ctx context.Context, action bees.Action) []bees.Placeholder {
 	outs := []bees.Placeholder{}
 
 	switch action.Name {
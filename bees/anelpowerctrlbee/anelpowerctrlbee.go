@@ -23,6 +23,7 @@
 package anelpowerctrlbee
 
 import (
+	"context"
 	"net"
 	"strconv"
 	"time"
@@ -67,7 +68,7 @@ func (mod *AnelPowerCtrlBee) anelSwitch(socket int, state bool) bool {
 }
 
 // Action triggers the action passed to it.
-func (mod *AnelPowerCtrlBee) Action(action bees.Action) []bees.Placeholder {
+func (mod *AnelPowerCtrlBee) Action(ctx context.Context, action bees.Action) []bees.Placeholder {
 	outs := []bees.Placeholder{}
 
 	switch action.Name {
@@ -0,0 +1,322 @@
+/*
+ *    Copyright (C) 2026 Christian Muehlhaeuser
+ *
+ *    This program is free software: you can redistribute it and/or modify
+ *    it under the terms of the GNU Affero General Public License as published
+ *    by the Free Software Foundation, either version 3 of the License, or
+ *    (at your option) any later version.
+ *
+ *    This program is distributed in the hope that it will be useful,
+ *    but WITHOUT ANY WARRANTY; without even the implied warranty of
+ *    MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ *    GNU Affero General Public License for more details.
+ *
+ *    You should have received a copy of the GNU Affero General Public License
+ *    along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ *    Authors:
+ *      Christian Muehlhaeuser <muesli@gmail.com>
+ */
+
+// Package bees is Beehive's central module system.
+package bees
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+const (
+	// AuditConfig is the kind of audit entries recording configuration
+	// changes, see AuditChange.
+	AuditConfig = "config"
+	// AuditAction is the kind of audit entries recording executed actions.
+	AuditAction = "action"
+
+	// DefaultAuditSize is the default number of audit entries kept in
+	// memory, see SetAuditSize.
+	DefaultAuditSize = 1000
+	// DefaultAuditMaxFileSize is the default size in bytes after which the
+	// audit log file gets rotated, see SetAuditLog.
+	DefaultAuditMaxFileSize = 10 << 20
+	// DefaultAuditBackups is the default number of rotated audit log files
+	// kept around.
+	DefaultAuditBackups = 5
+)
+
+// An AuditEntry records a change to the hive's configuration, or an action
+// that got executed.
+type AuditEntry struct {
+	ID   string
+	Time time.Time
+	// Kind is either AuditConfig or AuditAction
+	Kind string
+	// Actor is who made a change, e.g. the address of an API client, or the
+	// chain that executed an action
+	Actor string
+	// Operation is what happened, e.g. "DELETE" for changes made through
+	// the API, or the name of the executed action
+	Operation string
+	// Target is what was affected, e.g. the path of an API request, or the
+	// bee executing an action
+	Target string
+	// Options are the rendered options of an executed action, with the
+	// values of password options redacted
+	Options Placeholders `json:",omitempty"`
+	// Scope is the profile a change was made to or an action was executed
+	// for, empty for global ones
+	Scope string `json:",omitempty"`
+	// Error is empty when the change or action succeeded
+	Error    string        `json:",omitempty"`
+	Duration time.Duration `json:",omitempty"`
+}
+
+var (
+	auditEntries     []AuditEntry
+	auditSize        = DefaultAuditSize
+	auditFile        *os.File
+	auditPath        string
+	auditWritten     int64
+	auditMaxFileSize int64 = DefaultAuditMaxFileSize
+	auditBackups           = DefaultAuditBackups
+	auditMutex       sync.RWMutex
+)
+
+// SetAuditSize sets the number of audit entries kept in memory and returned
+// by AuditEntries.
+func SetAuditSize(n int) {
+	if n < 1 {
+		n = 1
+	}
+
+	auditMutex.Lock()
+	defer auditMutex.Unlock()
+
+	auditSize = n
+	if len(auditEntries) > n {
+		auditEntries = append([]AuditEntry{}, auditEntries[len(auditEntries)-n:]...)
+	}
+}
+
+// SetAuditLog additionally appends all audit entries to the file at path,
+// one JSON document per line. Once the file grows beyond maxSize bytes, it
+// gets rotated: path becomes path.1, path.1 becomes path.2 and so on, keeping
+// up to backups old files. Entries already in the file get loaded, so they
+// remain queryable after a restart. An empty path disables the file, zero
+// values use DefaultAuditMaxFileSize and DefaultAuditBackups.
+func SetAuditLog(path string, maxSize int64, backups int) error {
+	if maxSize <= 0 {
+		maxSize = DefaultAuditMaxFileSize
+	}
+	if backups <= 0 {
+		backups = DefaultAuditBackups
+	}
+
+	auditMutex.Lock()
+	defer auditMutex.Unlock()
+
+	if auditFile != nil {
+		auditFile.Close()
+		auditFile = nil
+	}
+	auditPath = path
+	auditMaxFileSize = maxSize
+	auditBackups = backups
+	if len(path) == 0 {
+		return nil
+	}
+
+	if err := loadAuditLog(path); err != nil {
+		return err
+	}
+	return openAuditLog()
+}
+
+// loadAuditLog reads the entries of an existing audit log file. The caller
+// must hold auditMutex.
+func loadAuditLog(path string) error {
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	var loaded []AuditEntry
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(nil, 1<<20)
+	for scanner.Scan() {
+		var entry AuditEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			logger.Warnf("Skipping invalid audit log entry: %v", err)
+			continue
+		}
+		loaded = append(loaded, entry)
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+
+	auditEntries = trimAudit(append(loaded, auditEntries...), auditSize)
+	return nil
+}
+
+// openAuditLog opens the audit log file for appending. The caller must hold
+// auditMutex.
+func openAuditLog() error {
+	f, err := os.OpenFile(auditPath, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return err
+	}
+	fi, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return err
+	}
+
+	auditFile = f
+	auditWritten = fi.Size()
+	return nil
+}
+
+// rotateAuditLog moves the audit log file out of the way and starts a new
+// one. The caller must hold auditMutex.
+func rotateAuditLog() error {
+	auditFile.Close()
+	auditFile = nil
+
+	os.Remove(fmt.Sprintf("%s.%d", auditPath, auditBackups))
+	for i := auditBackups - 1; i > 0; i-- {
+		os.Rename(fmt.Sprintf("%s.%d", auditPath, i), fmt.Sprintf("%s.%d", auditPath, i+1))
+	}
+	if err := os.Rename(auditPath, auditPath+".1"); err != nil {
+		return err
+	}
+	return openAuditLog()
+}
+
+// trimAudit drops the oldest entries exceeding size.
+func trimAudit(entries []AuditEntry, size int) []AuditEntry {
+	if len(entries) <= size {
+		return entries
+	}
+	return append([]AuditEntry{}, entries[len(entries)-size:]...)
+}
+
+// audit records an entry.
+func audit(entry AuditEntry) {
+	entry.ID = UUID()
+	entry.Time = clock.Now()
+
+	auditMutex.Lock()
+	defer auditMutex.Unlock()
+
+	auditEntries = trimAudit(append(auditEntries, entry), auditSize)
+	if auditFile == nil {
+		return
+	}
+
+	b, err := json.Marshal(entry)
+	if err != nil {
+		logger.Errorf("Failed to encode audit entry: %v", err)
+		return
+	}
+	b = append(b, '\n')
+	if auditWritten > 0 && auditWritten+int64(len(b)) > auditMaxFileSize {
+		if err := rotateAuditLog(); err != nil {
+			logger.Errorf("Failed to rotate audit log: %v", err)
+			if auditFile == nil {
+				return
+			}
+		}
+	}
+	n, err := auditFile.Write(b)
+	auditWritten += int64(n)
+	if err != nil {
+		logger.Errorf("Failed to write audit log: %v", err)
+	}
+}
+
+// AuditChange records a change to the hive's configuration, e.g. made
+// through the API: actor made the change, operation describes it and target
+// is what got changed. A non-nil err means the change failed.
+func AuditChange(actor, operation, target string, err error) {
+	AuditScopedChange("", actor, operation, target, err)
+}
+
+// AuditScopedChange records a change to the configuration of a profile, see
+// AuditChange. An empty scope stands for the global configuration.
+func AuditScopedChange(scope, actor, operation, target string, err error) {
+	entry := AuditEntry{
+		Kind:      AuditConfig,
+		Scope:     scope,
+		Actor:     actor,
+		Operation: operation,
+		Target:    target,
+	}
+	if err != nil {
+		entry.Error = err.Error()
+	}
+	audit(entry)
+}
+
+// auditAction records an executed action along with its outcome.
+func auditAction(ctx context.Context, a Action, started time.Time, err error) {
+	chain, _ := ctx.Value(chainNameKey{}).(string)
+	entry := AuditEntry{
+		Kind:      AuditAction,
+		Scope:     chainScopeOf(ctx),
+		Actor:     chain,
+		Operation: a.Name,
+		Target:    a.Bee,
+		Options:   redactOptions(a),
+		Duration:  time.Since(started),
+	}
+	if err != nil {
+		entry.Error = err.Error()
+	}
+	audit(entry)
+}
+
+// redactOptions returns a copy of the action's options, with the values of
+// options described as passwords replaced by PasswordMask.
+func redactOptions(a Action) Placeholders {
+	secrets := make(map[string]bool)
+	for _, opt := range GetActionDescriptor(&a).Options {
+		if opt.Type == "password" {
+			secrets[opt.Name] = true
+		}
+	}
+
+	opts := Placeholders{}
+	for _, opt := range a.Options {
+		if secrets[opt.Name] {
+			opt.Value = PasswordMask
+		}
+		opts = append(opts, opt)
+	}
+	return opts
+}
+
+// AuditEntries returns the recorded audit entries of a kind, or of all kinds
+// if kind is empty, that were recorded after since. Entries are returned in
+// the order they got recorded.
+func AuditEntries(kind string, since time.Time) []AuditEntry {
+	auditMutex.RLock()
+	defer auditMutex.RUnlock()
+
+	entries := []AuditEntry{}
+	for _, entry := range auditEntries {
+		if (len(kind) == 0 || entry.Kind == kind) && entry.Time.After(since) {
+			entries = append(entries, entry)
+		}
+	}
+	return entries
+}
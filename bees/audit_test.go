@@ -0,0 +1,87 @@
+package bees
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func init() {
+	RegisterFactory(&testFactory{
+		id: "auditedbee",
+		actions: []ActionDescriptor{
+			{Namespace: "auditedbee", Name: "login", Options: []PlaceholderDescriptor{
+				{Name: "user", Type: "string"},
+				{Name: "password", Type: "password"},
+			}},
+			{Namespace: "auditedbee", Name: "fail"},
+		},
+	})
+}
+
+func TestAuditLog(t *testing.T) {
+	dir, err := ioutil.TempDir("", "beehive-audit")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "audit.log")
+	if err := SetAuditLog(path, 300, 2); err != nil {
+		t.Fatal(err)
+	}
+	defer SetAuditLog("", 0, 0)
+
+	mod, err := StartBee(BeeConfig{Name: "audited", Class: "auditedbee"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer DeleteBee(mod)
+
+	oldActions := GetActions()
+	defer SetActions(oldActions)
+	SetActions([]Action{
+		{ID: "login", Bee: "audited", Name: "login", Options: Placeholders{
+			{Name: "user", Value: "{{.user}}"},
+			{Name: "password", Value: "hunter2"},
+		}},
+		{ID: "fail", Bee: "audited", Name: "fail"},
+	})
+
+	since := time.Now()
+	c := Chain{Name: "audit-chain", Event: &Event{Bee: "audited", Name: "message"}, Actions: []string{"login", "fail"}}
+	execChain(context.Background(), c, &Event{Bee: "audited", Name: "message", Options: Placeholders{{Name: "user", Value: "muesli"}}}, nil, false)
+	AuditChange("127.0.0.1", "DELETE", "/v1/chains/audit-chain", nil)
+	AuditScopedChange("work", "127.0.0.1", "POST", "/v1/chains", nil)
+
+	entries := AuditEntries(AuditAction, since)
+	if len(entries) != 2 {
+		t.Fatalf("Expected 2 audited actions, got %+v", entries)
+	}
+	login := entries[0]
+	if login.Actor != "audit-chain" || login.Target != "audited" || login.Operation != "login" || len(login.Error) > 0 {
+		t.Errorf("Unexpected audit entry %+v", login)
+	}
+	if login.Options.Value("user") != "muesli" || login.Options.Value("password") != PasswordMask {
+		t.Errorf("Expected rendered options with a redacted password, got %v", login.Options)
+	}
+	if len(entries[1].Error) == 0 {
+		t.Error("Expected the failed action's error to be recorded")
+	}
+	if changes := AuditEntries(AuditConfig, since); len(changes) != 2 || changes[0].Actor != "127.0.0.1" || changes[0].Scope != "" || changes[1].Scope != "work" {
+		t.Errorf("Expected the configuration changes to be recorded, got %+v", changes)
+	}
+
+	if _, err := os.Stat(path + ".1"); err != nil {
+		t.Errorf("Expected the audit log to be rotated: %v", err)
+	}
+	auditEntries = nil
+	if err := SetAuditLog(path, 300, 2); err != nil {
+		t.Fatal(err)
+	}
+	if entries := AuditEntries("", since); len(entries) == 0 {
+		t.Error("Expected entries to be loaded from the audit log")
+	}
+}
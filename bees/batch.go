@@ -0,0 +1,152 @@
+/*
+ *    Copyright (C) 2026 Christian Muehlhaeuser
+ *
+ *    This program is free software: you can redistribute it and/or modify
+ *    it under the terms of the GNU Affero General Public License as published
+ *    by the Free Software Foundation, either version 3 of the License, or
+ *    (at your option) any later version.
+ *
+ *    This program is distributed in the hope that it will be useful,
+ *    but WITHOUT ANY WARRANTY; without even the implied warranty of
+ *    MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ *    GNU Affero General Public License for more details.
+ *
+ *    You should have received a copy of the GNU Affero General Public License
+ *    along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ *    Authors:
+ *      Christian Muehlhaeuser <muesli@gmail.com>
+ */
+
+// Package bees is Beehive's central module system.
+package bees
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/robfig/cron/v3"
+)
+
+// chainBatch buffers the events triggering a batched chain.
+type chainBatch struct {
+	chain  Chain
+	events []Event
+	timer  *time.Timer
+}
+
+var (
+	batches    = make(map[string]*chainBatch)
+	batchMutex sync.Mutex
+)
+
+// batched returns whether the chain collects its triggering events into
+// batches.
+func (c *Chain) batched() bool {
+	return c.BatchSize > 0 || c.BatchWindow > 0 || len(c.BatchCron) > 0
+}
+
+// batchDelay returns how long a batch started at now collects events before
+// it fires. 0 means the batch only fires once it is full.
+func (c *Chain) batchDelay(now time.Time) (time.Duration, error) {
+	d := c.BatchWindow
+	if len(c.BatchCron) > 0 {
+		sched, err := cron.ParseStandard(c.BatchCron)
+		if err != nil {
+			return 0, err
+		}
+		if next := sched.Next(now).Sub(now); d == 0 || next < d {
+			d = next
+		}
+	}
+	return d, nil
+}
+
+// addToBatch adds an event to the chain's current batch and fires the batch
+// once it is full, using ctx for the chain's actions.
+func addToBatch(ctx context.Context, c Chain, event Event) {
+	batchMutex.Lock()
+	b, ok := batches[c.Name]
+	if !ok {
+		delay, err := c.batchDelay(clock.Now())
+		if err != nil {
+			batchMutex.Unlock()
+			logger.Errorf("Invalid batch schedule for chain %v: %v", c.Name, err)
+			fireBatch(ctx, c, []Event{event})
+			return
+		}
+
+		b = &chainBatch{chain: c}
+		batches[c.Name] = b
+
+		if delay > 0 {
+			atomic.AddInt64(&scheduledTimers, 1)
+			b.timer = time.AfterFunc(delay, func() {
+				flushBatch(context.Background(), b)
+			})
+		}
+	}
+	b.events = append(b.events, event)
+
+	full := c.BatchSize > 0 && len(b.events) >= c.BatchSize
+	batchMutex.Unlock()
+
+	if full {
+		flushBatch(ctx, b)
+	}
+}
+
+// flushBatch fires a batch, unless it has already been fired.
+func flushBatch(ctx context.Context, b *chainBatch) {
+	batchMutex.Lock()
+	if batches[b.chain.Name] != b {
+		batchMutex.Unlock()
+		return
+	}
+	delete(batches, b.chain.Name)
+	if b.timer != nil {
+		b.timer.Stop()
+		atomic.AddInt64(&scheduledTimers, -1)
+	}
+	batchMutex.Unlock()
+
+	fireBatch(ctx, b.chain, b.events)
+}
+
+// FlushBatches immediately fires all partial batches.
+func FlushBatches() {
+	batchMutex.Lock()
+	bs := []*chainBatch{}
+	for _, b := range batches {
+		bs = append(bs, b)
+	}
+	batchMutex.Unlock()
+
+	for _, b := range bs {
+		flushBatch(context.Background(), b)
+	}
+}
+
+// fireBatch executes a chain's actions once for a batch of events. The
+// placeholders of all events are available to the actions as "batch", while
+// the placeholders of the last event remain accessible directly.
+func fireBatch(ctx context.Context, c Chain, events []Event) {
+	if len(events) == 0 {
+		return
+	}
+	logger.Debugf("Firing batch of %v events for chain: %v", len(events), c.Name)
+
+	batch := []map[string]interface{}{}
+	for _, ev := range events {
+		batch = append(batch, placeholderMap(ev.Options))
+	}
+
+	last := events[len(events)-1]
+	m := eventMap(&last)
+	m["batch"] = batch
+	m["batch_size"] = len(batch)
+
+	runChain(ctx, c, &last, m)
+}
@@ -0,0 +1,91 @@
+/*
+ *    Copyright (C) 2026 Christian Muehlhaeuser
+ *
+ *    This program is free software: you can redistribute it and/or modify
+ *    it under the terms of the GNU Affero General Public License as published
+ *    by the Free Software Foundation, either version 3 of the License, or
+ *    (at your option) any later version.
+ *
+ *    This program is distributed in the hope that it will be useful,
+ *    but WITHOUT ANY WARRANTY; without even the implied warranty of
+ *    MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ *    GNU Affero General Public License for more details.
+ *
+ *    You should have received a copy of the GNU Affero General Public License
+ *    along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ *    Authors:
+ *      Christian Muehlhaeuser <muesli@gmail.com>
+ */
+// Package bees is Beehive's central module system.
+package bees
+
+import (
+	"sync"
+
+	"golang.org/x/time/rate"
+)
+
+var (
+	beeLimiters      = make(map[string]*rate.Limiter)
+	beeLimitedEvents = make(map[string]int64)
+	beeLimitersMutex sync.Mutex
+)
+
+// SetBeeRateLimit limits the amount of events the hive accepts from a bee.
+// Events exceeding the limit get dropped.
+func SetBeeRateLimit(beeName string, eventsPerSecond float64) {
+	burst := int(eventsPerSecond)
+	if burst < 1 {
+		burst = 1
+	}
+
+	beeLimitersMutex.Lock()
+	defer beeLimitersMutex.Unlock()
+
+	beeLimiters[beeName] = rate.NewLimiter(rate.Limit(eventsPerSecond), burst)
+}
+
+// RemoveBeeRateLimit removes a bee's rate limit.
+func RemoveBeeRateLimit(beeName string) {
+	beeLimitersMutex.Lock()
+	defer beeLimitersMutex.Unlock()
+
+	delete(beeLimiters, beeName)
+}
+
+// RateLimitedEvents returns how many events got dropped per bee, because they
+// exceeded the bee's rate limit.
+func RateLimitedEvents() map[string]int64 {
+	beeLimitersMutex.Lock()
+	defer beeLimitersMutex.Unlock()
+
+	r := make(map[string]int64, len(beeLimitedEvents))
+	for k, v := range beeLimitedEvents {
+		r[k] = v
+	}
+	return r
+}
+
+// allowEvent returns whether an event is within its bee's rate limit.
+func allowEvent(event Event) bool {
+	beeLimitersMutex.Lock()
+	defer beeLimitersMutex.Unlock()
+
+	l, ok := beeLimiters[event.Bee]
+	if !ok || l.AllowN(clock.Now(), 1) {
+		return true
+	}
+
+	beeLimitedEvents[event.Bee]++
+	return false
+}
+
+// deleteBeeRateLimit removes all rate limiting state of a bee.
+func deleteBeeRateLimit(beeName string) {
+	beeLimitersMutex.Lock()
+	defer beeLimitersMutex.Unlock()
+
+	delete(beeLimiters, beeName)
+	delete(beeLimitedEvents, beeName)
+}
@@ -0,0 +1,43 @@
+package bees
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBeeRateLimit(t *testing.T) {
+	fc := &fakeClock{now: time.Now()}
+	SetClock(fc)
+	defer SetClock(nil)
+
+	SetBeeRateLimit("spammy", 2)
+	defer deleteBeeRateLimit("spammy")
+
+	e := Event{Bee: "spammy", Name: "line"}
+	for i := 0; i < 2; i++ {
+		if !allowEvent(e) {
+			t.Fatalf("Expected event %d to be allowed", i)
+		}
+	}
+	if allowEvent(e) {
+		t.Error("Expected event exceeding the limit to be dropped")
+	}
+	if !allowEvent(Event{Bee: "quiet", Name: "line"}) {
+		t.Error("Expected events of other bees to be allowed")
+	}
+	if n := RateLimitedEvents()["spammy"]; n != 1 {
+		t.Errorf("Expected 1 rate limited event, got %d", n)
+	}
+
+	fc.now = fc.now.Add(time.Second)
+	if !allowEvent(e) {
+		t.Error("Expected event to be allowed after the limit recovered")
+	}
+
+	RemoveBeeRateLimit("spammy")
+	for i := 0; i < 5; i++ {
+		if !allowEvent(e) {
+			t.Fatal("Expected events to be allowed after removing the limit")
+		}
+	}
+}
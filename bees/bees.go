@@ -22,12 +22,16 @@
 package bees
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"sort"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	uuid "github.com/nu7hatch/gouuid"
-	log "github.com/sirupsen/logrus"
 )
 
 // BeeInterface is an interface all bees implement.
@@ -41,6 +45,8 @@ type BeeInterface interface {
 	Description() string
 	// SetDescription sets a description
 	SetDescription(s string)
+	// SetName renames the bee. Use RenameBee to rename registered bees
+	SetName(s string)
 
 	// Config returns this bees config
 	Config() BeeConfig
@@ -51,11 +57,28 @@ type BeeInterface interface {
 
 	// ReloadOptions gets called after a bee's options get updated
 	ReloadOptions(options BeeOptions)
+	// OnOptionsReload gets called by ReloadOptions after the running bee's
+	// options got updated. Bees which can't apply them without a restart
+	// return ErrRestartRequired
+	OnOptionsReload() error
 
 	// Activates the bee
-	Run(eventChannel chan Event)
+	Run(ctx context.Context, eventChannel chan Event)
+	// Context returns the bee's context, which gets cancelled when the bee
+	// stops
+	Context() context.Context
 	// Running returns the current state of the bee
 	IsRunning() bool
+	// State returns the bee's health
+	State() BeeState
+	// LastError returns the error that made the bee panic most recently
+	LastError() error
+	// HealthCheck returns an error if the running bee is impaired, e.g. lost
+	// its connection to a service. Unhealthy bees get restarted
+	HealthCheck() error
+	// Dependencies returns the names or namespaces of the bees which need
+	// to be running before this bee gets started
+	Dependencies() []string
 	// Start the bee
 	Start()
 	// Stop the bee
@@ -65,145 +88,807 @@ type BeeInterface interface {
 	LogEvent()
 	LastAction() time.Time
 	LogAction()
+	// Stats returns a snapshot of the bee's runtime statistics
+	Stats() *BeeStats
 
 	Logln(args ...interface{})
 	Logf(format string, args ...interface{})
 	LogErrorf(format string, args ...interface{})
+	LogWarnf(format string, args ...interface{})
 	LogFatal(args ...interface{})
 
 	SetSigChan(c chan bool)
 	WaitGroup() *sync.WaitGroup
 
 	// Handles an action
-	Action(action Action) []Placeholder
+	Action(ctx context.Context, action Action) []Placeholder
 }
 
 // Bee is the base-struct to be embedded by bee implementations.
 type Bee struct {
+	id     string
 	config BeeConfig
-
-	lastEvent  time.Time
-	lastAction time.Time
-
-	Running   bool
+	// configMutex guards config, as bees can get renamed or reconfigured
+	// while they're running.
+	configMutex *sync.RWMutex
+
+	stats   *BeeStats
+	health  *beeHealth
+	storage *beeStorage
+
+	Running bool
+	// SigChan gets closed when the bee is stopped.
+	//
+	// Deprecated: use Context, which gets cancelled at the same time.
 	SigChan   chan bool
 	waitGroup *sync.WaitGroup
+
+	// runMutex guards Running, SigChan, ctx and cancel, as the bee can get
+	// stopped from several goroutines at once and ctx and cancel get
+	// replaced whenever it gets started again.
+	runMutex *sync.Mutex
+	ctx      context.Context
+	cancel   context.CancelFunc
 }
 
 var (
-	bees      = make(map[string]*BeeInterface)
-	factories = make(map[string]*BeeFactoryInterface)
+	criticalFailureHandler = defaultCriticalFailureHandler
+	criticalFailureMutex   sync.RWMutex
 )
 
-// RegisterBee gets called by Bees to register themselves.
-func RegisterBee(bee BeeInterface) {
-	log.Println("Worker bee ready:", bee.Name(), "-", bee.Description())
+// OnCriticalFailure sets the function that gets called when a critical bee
+// keeps crashing and gets terminated. Passing nil restores the default
+// handler, which logs the failure and stops all bees, leaving it to the
+// process supervisor to restart beehive.
+func OnCriticalFailure(f func(bee string)) {
+	if f == nil {
+		f = defaultCriticalFailureHandler
+	}
+
+	criticalFailureMutex.Lock()
+	defer criticalFailureMutex.Unlock()
+	criticalFailureHandler = f
+}
 
-	bees[bee.Name()] = &bee
+func defaultCriticalFailureHandler(bee string) {
+	logger.Errorf("Critical bee %v failed permanently, stopping all bees!", bee)
+	StopBees()
 }
 
-// GetBee returns a bee with a specific name.
-func GetBee(identifier string) *BeeInterface {
-	bee, ok := bees[identifier]
-	if ok {
-		return bee
+// RegisterBee gets called by Bees to register themselves. It fails with
+// ErrDuplicateBee if a bee with the same name is registered already.
+func RegisterBee(bee BeeInterface) error {
+	if err := registry.RegisterBee(&bee); err != nil {
+		return err
+	}
+	logBeef(bee, LogInfo, "Worker bee ready: %v", bee.Description())
+
+	return nil
+}
+
+// RenameBee renames a registered bee. It fails with ErrUnknownBee if no bee
+// with the name old exists, or with ErrDuplicateBee if name is taken. Chains
+// keep referring to the old name and need to be updated by the caller.
+func RenameBee(old, name string) error {
+	if err := registry.RenameBee(old, name); err != nil {
+		return err
+	}
+	logger.Infof("Renamed bee %v to %v", old, name)
+
+	referenceMutex.Lock()
+	if options, ok := rawOptions[old]; ok {
+		rawOptions[name] = options
+		delete(rawOptions, old)
+	}
+	referenceMutex.Unlock()
+	renameBeeSecrets(old, name)
+	if c, ok := instanceConfig(old); ok {
+		c.Name = name
+		setInstanceConfig(c)
+		deleteInstanceConfig(old)
 	}
+	renameGroupMember(old, name)
+	renameInstanceDescriptors(old, name)
+	renameActionQueue(old, name)
 
 	return nil
 }
 
+// GetBee returns a bee with a specific identifier. Bees in a scope are
+// identified as "scope/name", but can also be looked up by their bare name,
+// as long as no other bee shares it.
+func GetBee(identifier string) *BeeInterface {
+	return registry.Resolve(identifier)
+}
+
 // GetBees returns all known bees.
 func GetBees() []*BeeInterface {
+	return registry.Bees()
+}
+
+// GetBeesByNamespace returns all bees of a namespace, i.e. spawned by the
+// factory with that ID.
+func GetBeesByNamespace(namespace string) []*BeeInterface {
 	r := []*BeeInterface{}
-	for _, bee := range bees {
-		r = append(r, bee)
+	for _, bee := range GetBees() {
+		if (*bee).Namespace() == namespace {
+			r = append(r, bee)
+		}
 	}
 
 	return r
 }
 
-// startBee starts a bee and recovers from panics.
-func startBee(bee *BeeInterface, fatals int) {
-	if fatals >= 3 {
-		log.Println("Terminating evil bee", (*bee).Name(), "after", fatals, "failed tries!")
-		(*bee).Stop()
+// GetBeesByClass returns all bees of a class. For bees created with
+// NewBeeInstance, the class of their config is used, otherwise their
+// namespace.
+func GetBeesByClass(class string) []*BeeInterface {
+	r := []*BeeInterface{}
+	for _, bee := range GetBees() {
+		c, ok := instanceConfig((*bee).Name())
+		if !ok {
+			c.Class = (*bee).Namespace()
+		}
+		if c.Class == class {
+			r = append(r, bee)
+		}
+	}
+
+	return r
+}
+
+// CountBeesByNamespace returns the number of bees of a namespace.
+func CountBeesByNamespace(namespace string) int {
+	n := 0
+	for _, bee := range GetBees() {
+		if (*bee).Namespace() == namespace {
+			n++
+		}
+	}
+
+	return n
+}
+
+// BeeStats contains runtime statistics of a bee.
+type BeeStats struct {
+	EventsReceived  int64
+	ActionsExecuted int64
+	ActionErrors    int64
+	Panics          int64
+	// Restarts counts how often the bee got restarted after crashing
+	Restarts   int64
+	LastError  string
+	LastEvent  time.Time
+	LastAction time.Time
+	Uptime     time.Duration
+
+	// ActionTimeouts counts the actions abandoned after their timeout
+	ActionTimeouts int64
+	// ActionTime is the total time spent executing actions, MaxActionTime
+	// the duration of the slowest action
+	ActionTime    time.Duration
+	MaxActionTime time.Duration
+
+	mutex     sync.Mutex
+	startedAt time.Time
+}
+
+func (s *BeeStats) started() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if s.startedAt.IsZero() {
+		s.startedAt = time.Now()
+	}
+}
+
+func (s *BeeStats) stopped() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.startedAt = time.Time{}
+}
+
+func (s *BeeStats) setError(err string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.LastError = err
+}
+
+func (s *BeeStats) logEvent() {
+	atomic.AddInt64(&s.EventsReceived, 1)
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.LastEvent = time.Now()
+}
+
+func (s *BeeStats) logAction() {
+	atomic.AddInt64(&s.ActionsExecuted, 1)
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.LastAction = time.Now()
+}
+
+func (s *BeeStats) recordActionError(e interface{}) {
+	atomic.AddInt64(&s.ActionErrors, 1)
+	s.setError(fmt.Sprint(e))
+}
+
+func (s *BeeStats) recordActionTime(d time.Duration) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.ActionTime += d
+	if d > s.MaxActionTime {
+		s.MaxActionTime = d
+	}
+}
+
+func (s *BeeStats) recordActionTimeout(d time.Duration) {
+	atomic.AddInt64(&s.ActionTimeouts, 1)
+	s.recordActionTime(d)
+}
+
+func (s *BeeStats) recordRestart() {
+	atomic.AddInt64(&s.Restarts, 1)
+}
+
+func (s *BeeStats) recordPanic(e interface{}) {
+	atomic.AddInt64(&s.Panics, 1)
+	s.setError(fmt.Sprint(e))
+}
+
+// snapshot returns a copy of the stats that is safe to hand out.
+func (s *BeeStats) snapshot() *BeeStats {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	r := &BeeStats{
+		EventsReceived:  atomic.LoadInt64(&s.EventsReceived),
+		ActionsExecuted: atomic.LoadInt64(&s.ActionsExecuted),
+		ActionErrors:    atomic.LoadInt64(&s.ActionErrors),
+		Panics:          atomic.LoadInt64(&s.Panics),
+		Restarts:        atomic.LoadInt64(&s.Restarts),
+		LastError:       s.LastError,
+		LastEvent:       s.LastEvent,
+		LastAction:      s.LastAction,
+		ActionTimeouts:  atomic.LoadInt64(&s.ActionTimeouts),
+		ActionTime:      s.ActionTime,
+		MaxActionTime:   s.MaxActionTime,
+	}
+	if !s.startedAt.IsZero() {
+		r.Uptime = time.Since(s.startedAt)
+	}
+
+	return r
+}
+
+// statsOf returns the live stats of a bee, or nil if the bee doesn't embed
+// Bee.
+func statsOf(bee *BeeInterface) *BeeStats {
+	if b, ok := (*bee).(interface{ beeStats() *BeeStats }); ok {
+		return b.beeStats()
+	}
+
+	return nil
+}
+
+// GetBeeStats returns the runtime statistics of a bee.
+func GetBeeStats(name string) (*BeeStats, bool) {
+	bee := GetBee(name)
+	if bee == nil {
+		return nil, false
+	}
+
+	return (*bee).Stats(), true
+}
+
+// GetAllStats returns the runtime statistics of all bees, keyed by name.
+func GetAllStats() map[string]*BeeStats {
+	r := make(map[string]*BeeStats)
+	for _, bee := range GetBees() {
+		r[(*bee).Name()] = (*bee).Stats()
+	}
+
+	return r
+}
+
+// Stats returns the runtime statistics aggregated across all bees. The
+// timestamps are the most recent ones of any bee.
+func Stats() *BeeStats {
+	r := &BeeStats{}
+	for _, s := range GetAllStats() {
+		r.EventsReceived += s.EventsReceived
+		r.ActionsExecuted += s.ActionsExecuted
+		r.ActionErrors += s.ActionErrors
+		r.Panics += s.Panics
+		r.Restarts += s.Restarts
+		if s.LastEvent.After(r.LastEvent) {
+			r.LastEvent = s.LastEvent
+		}
+		if s.LastAction.After(r.LastAction) {
+			r.LastAction = s.LastAction
+		}
+	}
+
+	return r
+}
+
+// runBee runs a bee on a separate goroutine, restarting it with a growing
+// delay when it panics, see SetRestartBackoff. The bee gets added to its
+// WaitGroup before the goroutine is launched, so stopping the bee right away
+// still waits for it to finish.
+func runBee(bee *BeeInterface, fatals int) {
+	retries := maxRetries((*bee).Name())
+	if fatals > retries {
+		// stopping waits for the failed run to finish, so don't block it
+		go terminateBee(bee, fatals)
 		return
 	}
+	setBeeState(bee, BeeStarting)
 
 	(*bee).WaitGroup().Add(1)
+	if fatals == 0 {
+		go startBee(bee, fatals)
+		return
+	}
+
+	if s := statsOf(bee); s != nil {
+		s.recordRestart()
+	}
+	delay := restartDelay(fatals)
+	logBeef(*bee, LogWarn, "Restarting bee in %v (retry %d of %d)", delay, fatals, retries)
+	go func() {
+		select {
+		case <-time.After(delay):
+			startBee(bee, fatals)
+		case <-(*bee).Context().Done():
+			// the bee got stopped while waiting to be restarted
+			(*bee).WaitGroup().Done()
+		}
+	}()
+}
+
+// terminateBee stops a bee that kept crashing. It shares the pending stop of
+// a shutdown that's already stopping the bee, see stopBee.
+func terminateBee(bee *BeeInterface, fatals int) {
+	logBeef(*bee, LogError, "Terminating evil bee after %v failed tries!", fatals)
+	StopBee(bee)
+	setBeeState(bee, BeeCrashed)
+
+	if c, ok := instanceConfig((*bee).Name()); ok && c.Critical {
+		criticalFailureMutex.RLock()
+		f := criticalFailureHandler
+		criticalFailureMutex.RUnlock()
+
+		f((*bee).Name())
+	}
+}
+
+// startBee runs a bee and recovers from panics. The bee must have been added
+// to its WaitGroup already, see runBee.
+func startBee(bee *BeeInterface, fatals int) {
 	defer (*bee).WaitGroup().Done()
 
 	defer func(bee *BeeInterface) {
 		if e := recover(); e != nil {
-			log.Println("Fatal bee event:", e, fatals)
-			go startBee(bee, fatals+1)
+			logBeef(*bee, LogError, "Fatal bee event: %v %v", e, fatals)
+			if s := statsOf(bee); s != nil {
+				s.recordPanic(e)
+			}
+			if h, ok := (*bee).(healthReporter); ok {
+				h.recordCrash(e)
+			}
+			beeCrashed((*bee).Name(), e)
+			runBee(bee, fatals+1)
 		}
 	}(bee)
 
-	(*bee).Run(eventsIn)
+	setBeeState(bee, BeeRunning)
+	beeStarted((*bee).Name())
+	go replayJournal()
+	in, _ := eventChannel()
+	(*bee).Run((*bee).Context(), in)
+}
+
+// NewBeeInstance sets up a new Bee with supplied config. It fails if the
+// bee's class is unknown (ErrUnknownBeeClass), its options are invalid or
+// its name is taken (ErrDuplicateBee).
+//
+// Older versions panicked on unknown classes. Code recovering from that
+// panic should check the returned error with errors.Is instead.
+func NewBeeInstance(bee BeeConfig) (*BeeInterface, error) {
+	bee = qualifyBee(bee)
+	if registry.Bee(bee.Name) != nil {
+		return nil, ErrDuplicateBee
+	}
+	mod, err := newBeeInstance(bee)
+	if err != nil {
+		referenceMutex.Lock()
+		delete(rawOptions, bee.Name)
+		referenceMutex.Unlock()
+		return nil, err
+	}
+	if err := RegisterBee(*mod); err != nil {
+		return nil, err
+	}
+	setInstanceConfig(bee)
+	setInstanceDescriptors(mod)
+
+	return mod, nil
 }
 
-// NewBeeInstance sets up a new Bee with supplied config.
-func NewBeeInstance(bee BeeConfig) *BeeInterface {
+// newBeeInstance sets up a new Bee with supplied config, without registering
+// it. Factories panicking while creating the bee get reported as an error.
+func newBeeInstance(bee BeeConfig) (b *BeeInterface, err error) {
 	factory := GetFactory(bee.Class)
 	if factory == nil {
-		panic("Unknown bee-class in config file: " + bee.Class)
+		return nil, fmt.Errorf("%w in config file: %s", ErrUnknownBeeClass, bee.Class)
+	}
+	options, err := resolveBeeOptions(bee.Name, bee.Class, bee.Options)
+	if err != nil {
+		return nil, err
+	}
+	options, errs := PrepareOptions(bee.Class, options)
+	if len(errs) > 0 {
+		return nil, validationError(errs)
+	}
+
+	defer func() {
+		if e := recover(); e != nil {
+			b, err = nil, fmt.Errorf("Bee %s failed to initialize: %v", bee.Name, e)
+		}
+	}()
+	mod := (*factory).New(bee.Name, bee.Description, options)
+
+	return &mod, nil
+}
+
+// SwapBee replaces a running bee with a new instance, without a gap during
+// which the bee is unavailable: the new bee gets started first, and only
+// once it's running it replaces the old bee, which then gets stopped. Events
+// emitted by the old bee in the meantime still get handled.
+func SwapBee(old *BeeInterface, bee BeeConfig) (*BeeInterface, error) {
+	bee = qualifyBee(bee)
+	mod, err := newBeeInstance(bee)
+	if err != nil {
+		return nil, err
+	}
+	logBeef(*old, LogInfo, "Swapping bee for a new instance")
+
+	launchBee(mod)
+	if WaitForRunning(mod, DefaultStopTimeout) != nil {
+		(*mod).Stop()
+		return nil, errors.New("New instance of bee " + bee.Name + " failed to start")
 	}
-	mod := (*factory).New(bee.Name, bee.Description, bee.Options)
-	RegisterBee(mod)
 
-	return &mod
+	if !registry.ReplaceBee(old, mod) {
+		(*mod).Stop()
+		return nil, errors.New("Bee " + (*old).Name() + " is not registered anymore")
+	}
+	setInstanceConfig(bee)
+	if (*old).Name() != (*mod).Name() {
+		deleteInstanceDescriptors((*old).Name())
+	}
+	setInstanceDescriptors(mod)
+	// the new instance already took over the name stopBee dedupes on
+	(*old).Stop()
+
+	return mod, nil
 }
 
-// DeleteBee removes a Bee instance.
+// DeleteBee removes a Bee instance, including its persisted state unless
+// disabled with SetPurgeStateOnDelete.
 func DeleteBee(bee *BeeInterface) {
-	(*bee).Stop()
+	removeBee(bee, true)
+}
 
-	delete(bees, (*bee).Name())
+// removeBee removes a Bee instance. Its persisted state only gets purged if
+// purge is set, so bees being recreated can pick it up again.
+func removeBee(bee *BeeInterface, purge bool) {
+	StopBee(bee)
+
+	registry.DeleteBee((*bee).Name())
+	if purge {
+		purgeState((*bee).Name())
+	}
+
+	referenceMutex.Lock()
+	delete(rawOptions, (*bee).Name())
+	referenceMutex.Unlock()
+	deleteBeeSecrets((*bee).Name())
+	deleteInstanceConfig((*bee).Name())
+	deleteBeeRateLimit((*bee).Name())
+	RemoveBeeDedup((*bee).Name())
+	removeGroupMember((*bee).Name())
+	deleteInstanceDescriptors((*bee).Name())
+	SerializeActions((*bee).Name(), false)
 }
 
-// StartBee starts a bee.
-func StartBee(bee BeeConfig) *BeeInterface {
-	b := NewBeeInstance(bee)
+// StartBee starts a bee. It fails if the bee can't be set up, see
+// NewBeeInstance.
+func StartBee(bee BeeConfig) (*BeeInterface, error) {
+	b, err := NewBeeInstance(bee)
+	if err != nil {
+		return nil, err
+	}
+	launchBee(b)
 
+	return b, nil
+}
+
+// StartBeeInstance registers and starts a bee that has already been set up,
+// e.g. by a factory's New, the same way StartBee does.
+func StartBeeInstance(bee *BeeInterface) error {
+	if registry.Bee((*bee).Name()) != nil {
+		return ErrDuplicateBee
+	}
+	if err := RegisterBee(*bee); err != nil {
+		return err
+	}
+	setInstanceConfig((*bee).Config())
+	setInstanceDescriptors(bee)
+	launchBee(bee)
+
+	return nil
+}
+
+// launchBee starts a bee instance.
+func launchBee(b *BeeInterface) {
 	(*b).Start()
-	go func(mod *BeeInterface) {
-		startBee(mod, 0)
-	}(b)
+	runBee(b, 0)
+}
 
-	return b
+// StartBees starts all registered bees, in the order of their dependencies,
+// see startBees. Bees that can't be set up get skipped, so a single broken
+// bee doesn't prevent the others from starting. Their errors get logged and
+// returned. StartBees then waits up to the startup timeout for the bees to
+// be running, see SetStartupTimeout, and also returns an error for each bee
+// that didn't get there in time.
+func StartBees(beeList []BeeConfig) []error {
+	go handleEvents(openEventQueue())
+	startHealthChecks()
+
+	errs := startBees(beeList)
+	return append(errs, waitForStartup(beeList)...)
+}
+
+// StartBeesOrdered works like StartBees, but returns a single error listing
+// all problems, e.g. cyclic dependencies, or nil if all bees got started.
+func StartBeesOrdered(beeList []BeeConfig) error {
+	errs := StartBees(beeList)
+	if len(errs) == 0 {
+		return nil
+	}
+
+	s := make([]string, len(errs))
+	for i, err := range errs {
+		s[i] = err.Error()
+	}
+	return errors.New(strings.Join(s, "; "))
+}
+
+// uniqueBees returns beeList with the names of scoped bees qualified, see
+// QualifiedBeeName, and without the bees whose name has been used by an
+// earlier entry, and an error for each of them naming both entries.
+func uniqueBees(beeList []BeeConfig) ([]BeeConfig, []error) {
+	errs := []error{}
+	unique := []BeeConfig{}
+	seen := make(map[string]int)
+	for i, bee := range beeList {
+		bee = qualifyBee(bee)
+		if j, ok := seen[bee.Name]; ok {
+			err := fmt.Errorf("Bee %s: duplicate name, used by entry %d (%s) and entry %d (%s)",
+				bee.Name, j+1, beeList[j].Class, i+1, bee.Class)
+			logger.Errorf("Skipping duplicate bee: %v", err)
+			errs = append(errs, err)
+			continue
+		}
+		seen[bee.Name] = i
+		unique = append(unique, bee)
+	}
+
+	return unique, errs
+}
+
+// PrepareBees starts all registered bees like StartBees, but doesn't start
+// the event loop. Instead it returns a function running the event loop,
+// which the caller has to invoke on a goroutine of their choice. The function
+// returns once ctx gets cancelled or StopBees gets called. Panics raised in
+// the event loop are not recovered, leaving that to the caller's supervision.
+// Just like StartBees, PrepareBees returns an error for each bee that could
+// not be started or didn't get running within the startup timeout.
+//
+// Bees block when emitting events until the event loop is running.
+func PrepareBees(beeList []BeeConfig) (func(ctx context.Context), []error) {
+	in := openEventQueue()
+	startHealthChecks()
+
+	errs := startBees(beeList)
+	errs = append(errs, waitForStartup(beeList)...)
+
+	return func(ctx context.Context) {
+		runEventLoop(eventLoopContext(ctx), in)
+	}, errs
+}
+
+// DefaultStopTimeout is the default time StopBees waits for bees to stop.
+const DefaultStopTimeout = 30 * time.Second
+
+var (
+	stopping      = make(map[string]chan struct{})
+	abandoned     = make(map[string]struct{})
+	stoppingMutex sync.Mutex
+
+	stopTimeout = int64(DefaultStopTimeout)
+)
+
+// SetStopTimeout sets the time StopBees waits for bees to stop. A timeout of
+// 0 waits indefinitely.
+func SetStopTimeout(d time.Duration) {
+	atomic.StoreInt64(&stopTimeout, int64(d))
 }
 
-// StartBees starts all registered bees.
-func StartBees(beeList []BeeConfig) {
-	eventsIn = make(chan Event)
-	go handleEvents()
+// AbandonedBees returns the names of the bees which failed to stop in time
+// and whose Run still hasn't returned, sorted by name.
+func AbandonedBees() []string {
+	stoppingMutex.Lock()
+	defer stoppingMutex.Unlock()
 
-	for _, bee := range beeList {
-		StartBee(bee)
+	names := make([]string, 0, len(abandoned))
+	for name := range abandoned {
+		names = append(names, name)
 	}
+	sort.Strings(names)
+	return names
 }
 
-// StopBees stops all bees gracefully.
+// StopBees stops all bees gracefully, after firing all partial batches. It
+// waits up to the timeout set with SetStopTimeout for every bee's Run to
+// return and for all pending chains and actions to finish, see
+// StopBeesTimeout.
 func StopBees() {
-	for _, bee := range bees {
-		log.Println("Stopping bee:", (*bee).Name())
+	if err := StopBeesTimeout(time.Duration(atomic.LoadInt64(&stopTimeout))); err != nil {
+		logger.Errorf("%v", err)
+	}
+}
+
+// StopBeesTimeout works like StopBees, but waits at most timeout for the bees
+// to stop. Bees that fail to stop in time get logged and abandoned: they are
+// removed like all other bees, and the returned error lists their names.
+// They're reported by AbandonedBees until they eventually stop. Queued
+// events only get handled if all bees stopped in time, otherwise the event
+// handler stops right away. A timeout of 0 waits indefinitely.
+func StopBeesTimeout(timeout time.Duration) error {
+	stopHealthChecks()
+	FlushDebounces()
+	FlushBatches()
+	CancelDelayedActions()
+	CancelDeferredEvents()
+	CancelRetries()
+
+	var deadline time.Time
+	if timeout > 0 {
+		deadline = time.Now().Add(timeout)
+	}
+
+	stopped := make(map[string]chan struct{})
+	for _, bee := range GetBees() {
+		stopped[(*bee).Name()] = stopBee(bee)
+	}
+
+	stuck := []string{}
+	for name, done := range stopped {
+		if !waitUntil(done, deadline) || !waitForWorkUntil(name, deadline) {
+			stuck = append(stuck, name)
+		}
+	}
+	registry.ClearBees()
+	if len(stuck) > 0 {
+		sort.Strings(stuck)
+		for _, name := range stuck {
+			logger.Errorf("Abandoning bee %v which failed to stop in time!", name)
+			abandonBee(name, stopped[name])
+		}
+
+		closeEventChannel()
+		stopEventLoop()
+		return fmt.Errorf("Timed out waiting for bees to stop: %s", strings.Join(stuck, ", "))
+	}
+
+	closeEventChannel()
+	if !eventQueueOpen() {
+		// without a queue left to drain, the handler reads the event
+		// channel itself
+		stopEventLoop()
+	}
+	return nil
+}
+
+// abandonBee reports a bee by AbandonedBees until done gets closed.
+func abandonBee(name string, done chan struct{}) {
+	stoppingMutex.Lock()
+	abandoned[name] = struct{}{}
+	stoppingMutex.Unlock()
+
+	go func() {
+		<-done
+		logger.Warnf("Abandoned bee %v stopped eventually", name)
+
+		stoppingMutex.Lock()
+		delete(abandoned, name)
+		stoppingMutex.Unlock()
+	}()
+}
+
+// StopBee stops a bee and waits for it to finish. Stopping a bee that is
+// already being stopped, e.g. by StopBees, waits for the pending stop.
+func StopBee(bee *BeeInterface) {
+	<-stopBee(bee)
+}
+
+// stopBee stops a bee in the background. The returned channel gets closed
+// once the bee stopped. Stopping a bee that is still being stopped returns
+// the channel of the pending stop.
+func stopBee(bee *BeeInterface) chan struct{} {
+	stoppingMutex.Lock()
+	defer stoppingMutex.Unlock()
+
+	name := (*bee).Name()
+	if done, ok := stopping[name]; ok {
+		return done
+	}
+
+	logBeef(*bee, LogInfo, "Stopping bee")
+	done := make(chan struct{})
+	stopping[name] = done
+	go func() {
+		running := (*bee).IsRunning()
 		(*bee).Stop()
+		if running {
+			beeStopped(name)
+		}
+
+		stoppingMutex.Lock()
+		delete(stopping, name)
+		stoppingMutex.Unlock()
+		close(done)
+	}()
+
+	return done
+}
+
+// waitUntil waits for done to be closed. It returns false if that didn't
+// happen before deadline. A zero deadline waits indefinitely.
+func waitUntil(done chan struct{}, deadline time.Time) bool {
+	if deadline.IsZero() {
+		<-done
+		return true
+	}
+	select {
+	case <-done:
+		return true
+	default:
 	}
 
-	close(eventsIn)
-	bees = make(map[string]*BeeInterface)
+	t := time.NewTimer(time.Until(deadline))
+	defer t.Stop()
+
+	select {
+	case <-done:
+		return true
+	case <-t.C:
+		return false
+	}
 }
 
-// RestartBee restarts a Bee.
+// RestartBee restarts a Bee. It waits for the bee's previous run to finish
+// and starts a fresh one, with a new SigChan and context.
 func RestartBee(bee *BeeInterface) {
-	(*bee).Stop()
+	StopBee(bee)
 
 	(*bee).SetSigChan(make(chan bool))
 	(*bee).Start()
-	go func(mod *BeeInterface) {
-		startBee(mod, 0)
-	}(bee)
+	runBee(bee, 0)
 }
 
 // RestartBees stops all running bees and restarts a new set of bees.
@@ -212,7 +897,8 @@ func RestartBees(bees []BeeConfig) {
 	StartBees(bees)
 }
 
-// NewBee returns a new bee and sets up sig-channel & waitGroup.
+// NewBee returns a new bee and sets up sig-channel & waitGroup. The bee's
+// persisted state gets loaded from the StateStore.
 func NewBee(name, factoryName, description string, options []BeeOption) Bee {
 	c := BeeConfig{
 		Name:        name,
@@ -221,52 +907,91 @@ func NewBee(name, factoryName, description string, options []BeeOption) Bee {
 		Options:     options,
 	}
 	b := Bee{
-		config:    c,
-		SigChan:   make(chan bool),
-		waitGroup: &sync.WaitGroup{},
+		id:          UUID(),
+		config:      c,
+		configMutex: &sync.RWMutex{},
+		SigChan:     make(chan bool),
+		waitGroup:   &sync.WaitGroup{},
+		runMutex:    &sync.Mutex{},
+		stats:       &BeeStats{},
+		health:      &beeHealth{},
+		storage:     &beeStorage{},
 	}
+	b.ctx, b.cancel = context.WithCancel(context.Background())
+	b.storage.preload(name)
 
 	return b
 }
 
 // Name returns the configured name for a bee.
 func (bee *Bee) Name() string {
+	bee.configMutex.RLock()
+	defer bee.configMutex.RUnlock()
 	return bee.config.Name
 }
 
+// ID returns the unique ID of a bee instance. Unlike its name, it never
+// gets reused: a bee that's replaced by a new instance, see SwapBee, or
+// restarted from its config gets a new ID.
+func (bee *Bee) ID() string {
+	return bee.id
+}
+
 // Namespace returns the namespace for a bee.
 func (bee *Bee) Namespace() string {
+	bee.configMutex.RLock()
+	defer bee.configMutex.RUnlock()
 	return bee.config.Class
 }
 
 // Description returns the description for a bee.
 func (bee *Bee) Description() string {
+	bee.configMutex.RLock()
+	defer bee.configMutex.RUnlock()
 	return bee.config.Description
 }
 
 // SetDescription sets the description for a bee.
 func (bee *Bee) SetDescription(s string) {
+	bee.configMutex.Lock()
+	defer bee.configMutex.Unlock()
 	bee.config.Description = s
 }
 
+// SetName sets the name for a bee. Persisted values move along.
+func (bee *Bee) SetName(s string) {
+	bee.configMutex.Lock()
+	defer bee.configMutex.Unlock()
+	bee.storage.rename(bee.config.Name, s)
+	bee.config.Name = s
+}
+
 // Config returns the config for a bee.
 func (bee *Bee) Config() BeeConfig {
+	bee.configMutex.RLock()
+	defer bee.configMutex.RUnlock()
 	return bee.config
 }
 
 // Options returns the options for a bee.
 func (bee *Bee) Options() BeeOptions {
+	bee.configMutex.RLock()
+	defer bee.configMutex.RUnlock()
 	return bee.config.Options
 }
 
 // SetOptions sets the options for a bee.
 func (bee *Bee) SetOptions(options BeeOptions) {
+	bee.configMutex.Lock()
+	defer bee.configMutex.Unlock()
 	bee.config.Options = options
 }
 
 // SetOption sets one option for a bee.
 func (bee *Bee) SetOption(name string, value string) bool {
-	for i := 0 ; i < len(bee.config.Options); i++ {
+	bee.configMutex.Lock()
+	defer bee.configMutex.Unlock()
+	for i := 0; i < len(bee.config.Options); i++ {
 		if bee.config.Options[i].Name == name {
 			bee.config.Options[i].Value = value
 
@@ -279,110 +1004,241 @@ func (bee *Bee) SetOption(name string, value string) bool {
 
 // SetSigChan sets the signaling channel for a bee.
 func (bee *Bee) SetSigChan(c chan bool) {
+	bee.runMutex.Lock()
+	defer bee.runMutex.Unlock()
 	bee.SigChan = c
 }
 
+// sigChan returns the signaling channel of a bee, see EmitEvent.
+func (bee *Bee) sigChan() chan bool {
+	bee.runMutex.Lock()
+	defer bee.runMutex.Unlock()
+	return bee.SigChan
+}
+
 // WaitGroup returns the WaitGroup for a bee.
 func (bee *Bee) WaitGroup() *sync.WaitGroup {
 	return bee.waitGroup
 }
 
+// Context returns the bee's context, which gets cancelled when the bee stops.
+func (bee *Bee) Context() context.Context {
+	bee.runMutex.Lock()
+	defer bee.runMutex.Unlock()
+	return bee.ctx
+}
+
+// Sleep pauses for d. It returns early with false when the bee gets stopped,
+// otherwise true.
+func (bee *Bee) Sleep(d time.Duration) bool {
+	t := time.NewTimer(d)
+	defer t.Stop()
+
+	select {
+	case <-t.C:
+		return true
+	case <-bee.Context().Done():
+		return false
+	}
+}
+
 // Run is the default, empty implementation of a Bee's Run method.
-func (bee *Bee) Run(chan Event) {
+func (bee *Bee) Run(ctx context.Context, eventChan chan Event) {
 	select {
-	case <-bee.SigChan:
+	case <-ctx.Done():
+		return
+	case <-bee.sigChan():
 		return
 	}
 }
 
+// OnOptionsReload is the default, empty implementation of a Bee's
+// OnOptionsReload method.
+func (bee *Bee) OnOptionsReload() error {
+	return nil
+}
+
+// HealthCheck is the default implementation of a Bee's HealthCheck method,
+// always reporting the bee as healthy.
+func (bee *Bee) HealthCheck() error {
+	return nil
+}
+
+// Dependencies is the default implementation of a Bee's Dependencies method,
+// returning no dependencies.
+func (bee *Bee) Dependencies() []string {
+	return nil
+}
+
 // Action is the default, empty implementation of a Bee's Action method.
-func (bee *Bee) Action(action Action) []Placeholder {
+func (bee *Bee) Action(ctx context.Context, action Action) []Placeholder {
 	return []Placeholder{}
 }
 
 // IsRunning returns whether a Bee is currently running.
 func (bee *Bee) IsRunning() bool {
+	bee.runMutex.Lock()
+	defer bee.runMutex.Unlock()
 	return bee.Running
 }
 
 // Start gets called when a Bee gets started.
 func (bee *Bee) Start() {
+	bee.runMutex.Lock()
+	if bee.ctx == nil || bee.ctx.Err() != nil {
+		bee.ctx, bee.cancel = context.WithCancel(context.Background())
+	}
 	bee.Running = true
+	bee.runMutex.Unlock()
+	bee.stats.started()
 }
 
-// Stop gracefully stops a Bee.
+// Stop gracefully stops a Bee. Stopping a bee that is already being stopped
+// waits for its Run to return.
 func (bee *Bee) Stop() {
-	if !bee.IsRunning() {
+	bee.runMutex.Lock()
+	if !bee.Running {
+		bee.runMutex.Unlock()
 		return
 	}
-	log.Println(bee.Name(), "stopping gracefully!")
-
+	if bee.ctx.Err() != nil {
+		bee.runMutex.Unlock()
+		bee.waitGroup.Wait()
+		return
+	}
+	bee.cancel()
 	close(bee.SigChan)
+	bee.runMutex.Unlock()
+	bee.Logf("Stopping gracefully!")
+
 	bee.waitGroup.Wait()
+	bee.runMutex.Lock()
 	bee.Running = false
-	log.Println(bee.Name(), "stopped gracefully!")
+	bee.runMutex.Unlock()
+	bee.storage.flush(bee.Name())
+	bee.SetState(BeeStopped)
+	bee.stats.stopped()
+	bee.Logf("Stopped gracefully!")
 }
 
 // LastEvent returns the timestamp of the last triggered event.
 func (bee *Bee) LastEvent() time.Time {
-	return bee.lastEvent
+	bee.stats.mutex.Lock()
+	defer bee.stats.mutex.Unlock()
+	return bee.stats.LastEvent
 }
 
 // LastAction returns the timestamp of the last triggered action.
 func (bee *Bee) LastAction() time.Time {
-	return bee.lastAction
+	bee.stats.mutex.Lock()
+	defer bee.stats.mutex.Unlock()
+	return bee.stats.LastAction
 }
 
 // LogEvent logs the last triggered event.
 func (bee *Bee) LogEvent() {
-	bee.lastEvent = time.Now()
+	bee.stats.logEvent()
 }
 
 // LogAction logs the last triggered action.
 func (bee *Bee) LogAction() {
-	bee.lastAction = time.Now()
+	bee.stats.logAction()
+}
+
+// Stats returns a snapshot of the bee's runtime statistics.
+func (bee *Bee) Stats() *BeeStats {
+	return bee.stats.snapshot()
+}
+
+func (bee *Bee) beeStats() *BeeStats {
+	return bee.stats
 }
 
 // Logln logs args
 func (bee *Bee) Logln(args ...interface{}) {
-	a := []interface{}{"[" + bee.Name() + "]:"}
-	for _, v := range args {
-		a = append(a, v)
-	}
-
-	log.Println(a...)
-	Log(bee.Name(), fmt.Sprintln(args...), LogInfo)
+	logBee(bee.Name(), bee.Namespace(), LogInfo, strings.TrimSuffix(fmt.Sprintln(args...), "\n"))
 }
 
 // Logf logs a formatted string
 func (bee *Bee) Logf(format string, args ...interface{}) {
-	s := fmt.Sprintf(format, args...)
-	log.Printf("[%s]: %s", bee.Name(), s)
-	Log(bee.Name(), s, LogInfo)
+	logBee(bee.Name(), bee.Namespace(), LogInfo, fmt.Sprintf(format, args...))
 }
 
 // LogErrorf logs a formatted error string
 func (bee *Bee) LogErrorf(format string, args ...interface{}) {
 	s := fmt.Sprintf(format, args...)
-	log.Errorf("[%s]: %s", bee.Name(), s)
-	Log(bee.Name(), s, LogError)
+	bee.stats.setError(s)
+	logBee(bee.Name(), bee.Namespace(), LogError, s)
+}
+
+// Errorf logs a formatted error string, like LogErrorf
+func (bee *Bee) Errorf(format string, args ...interface{}) {
+	bee.LogErrorf(format, args...)
+}
+
+// LogWarnf logs a formatted warning string
+func (bee *Bee) LogWarnf(format string, args ...interface{}) {
+	logBee(bee.Name(), bee.Namespace(), LogWarn, fmt.Sprintf(format, args...))
+}
+
+// Warnf logs a formatted warning string, like LogWarnf
+func (bee *Bee) Warnf(format string, args ...interface{}) {
+	bee.LogWarnf(format, args...)
 }
 
 // LogDebugf logs a formatted debug string
 func (bee *Bee) LogDebugf(format string, args ...interface{}) {
+	logBee(bee.Name(), bee.Namespace(), LogDebug, fmt.Sprintf(format, args...))
+}
+
+// Debugf logs a formatted debug string, like LogDebugf
+func (bee *Bee) Debugf(format string, args ...interface{}) {
+	bee.LogDebugf(format, args...)
+}
+
+// WithFields returns a logger for the bee which attaches fields to every
+// message, e.g. bee.WithFields(LogFields{"channel": ch}).LogErrorf(...).
+func (bee *Bee) WithFields(fields LogFields) *BeeLog {
+	return &BeeLog{bee: bee, fields: fields}
+}
+
+// BeeLog logs messages of a bee with structured fields, see WithFields.
+type BeeLog struct {
+	bee    *Bee
+	fields LogFields
+}
+
+func (l *BeeLog) log(level MessageType, format string, args ...interface{}) string {
 	s := fmt.Sprintf(format, args...)
-	log.Debugf("[%s]: %s", bee.Name(), s)
-	Log(bee.Name(), s, LogDebug)
+	logBeeFields(l.bee.Name(), l.bee.Namespace(), level, s, l.fields)
+	return s
+}
+
+// Logf logs a formatted string
+func (l *BeeLog) Logf(format string, args ...interface{}) {
+	l.log(LogInfo, format, args...)
+}
+
+// LogDebugf logs a formatted debug string
+func (l *BeeLog) LogDebugf(format string, args ...interface{}) {
+	l.log(LogDebug, format, args...)
+}
+
+// LogWarnf logs a formatted warning string
+func (l *BeeLog) LogWarnf(format string, args ...interface{}) {
+	l.log(LogWarn, format, args...)
+}
+
+// LogErrorf logs a formatted error string
+func (l *BeeLog) LogErrorf(format string, args ...interface{}) {
+	l.bee.stats.setError(l.log(LogError, format, args...))
 }
 
 // LogFatal logs a fatal error
 func (bee *Bee) LogFatal(args ...interface{}) {
-	a := []interface{}{"[" + bee.Name() + "]:"}
-	for _, v := range args {
-		a = append(a, v)
-	}
-	log.Panicln(a...)
-	Log(bee.Name(), fmt.Sprintln(args...), LogFatal)
+	s := strings.TrimSuffix(fmt.Sprintln(args...), "\n")
+	logBee(bee.Name(), bee.Namespace(), LogFatal, s)
+	panic(s)
 }
 
 // UUID generates a new unique ID.
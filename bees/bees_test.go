@@ -0,0 +1,626 @@
+package bees
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// stuckBee ignores its SigChan until released.
+type stuckBee struct {
+	recordingBee
+
+	release chan struct{}
+}
+
+func (mod *stuckBee) Run(ctx context.Context, eventChan chan Event) {
+	<-mod.release
+}
+
+func TestStopBeesTimeout(t *testing.T) {
+	defer useEventChannel(make(chan Event))()
+
+	newRecordingBee("busybee")
+	mod := &stuckBee{
+		recordingBee: recordingBee{Bee: NewBee("stuckbee", "recordingbee", "", BeeOptions{})},
+		release:      make(chan struct{}),
+	}
+	RegisterBee(mod)
+	launchBee(GetBee("stuckbee"))
+	time.Sleep(10 * time.Millisecond)
+
+	err := StopBeesTimeout(50 * time.Millisecond)
+	if err == nil || !strings.Contains(err.Error(), "stuckbee") || strings.Contains(err.Error(), "busybee") {
+		t.Errorf("Expected only stuckbee to fail stopping, got %v", err)
+	}
+	if len(GetBees()) != 0 {
+		t.Error("Expected stuck bee to be abandoned")
+	}
+	if names := AbandonedBees(); len(names) != 1 || names[0] != "stuckbee" {
+		t.Errorf("Expected stuckbee to be reported as abandoned, got %v", names)
+	}
+
+	close(mod.release)
+	deadline := time.Now().Add(time.Second)
+	for len(AbandonedBees()) > 0 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	if names := AbandonedBees(); len(names) != 0 {
+		t.Errorf("Expected no abandoned bees once stuckbee stopped, got %v", names)
+	}
+}
+
+func TestConcurrentStop(t *testing.T) {
+	mod, _ := NewBeeInstance(BeeConfig{Name: "concurrentstopbee", Class: "recordingbee"})
+	defer DeleteBee(mod)
+	launchBee(mod)
+
+	// every stop path may race the others, none of them may close the
+	// bee's SigChan twice
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(3)
+		go func() {
+			defer wg.Done()
+			(*mod).Stop()
+		}()
+		go func() {
+			defer wg.Done()
+			StopBee(mod)
+		}()
+		go func() {
+			defer wg.Done()
+			<-stopBee(mod)
+		}()
+	}
+	wg.Wait()
+
+	if (*mod).IsRunning() || (*mod).Context().Err() == nil {
+		t.Error("Expected the bee to be stopped")
+	}
+}
+
+func TestRegistryConcurrency(t *testing.T) {
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+
+			name := "hammerbee" + strconv.Itoa(i)
+			mod, _ := NewBeeInstance(BeeConfig{Name: name, Class: "recordingbee"})
+			for j := 0; j < 10; j++ {
+				GetBee(name)
+				GetBees()
+				GetFactory("recordingbee")
+				GetFactories()
+			}
+			DeleteBee(mod)
+		}(i)
+	}
+	wg.Wait()
+
+	for _, bee := range GetBees() {
+		if strings.HasPrefix((*bee).Name(), "hammerbee") {
+			t.Error("Expected bee to be deleted:", (*bee).Name())
+		}
+	}
+}
+
+func TestRegistryConcurrentRegistration(t *testing.T) {
+	r := NewBeeRegistry()
+
+	var registered int32
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			var bee BeeInterface = &recordingBee{Bee: NewBee("racebee", "recordingbee", "", BeeOptions{})}
+			if r.RegisterBee(&bee) == nil {
+				atomic.AddInt32(&registered, 1)
+			}
+			r.Bees()
+		}()
+	}
+	wg.Wait()
+
+	if registered != 1 || len(r.Bees()) != 1 {
+		t.Fatalf("Expected exactly one bee to be registered, got %d", registered)
+	}
+	if err := r.RenameBee("racebee", "renamedbee"); err != nil {
+		t.Fatal(err)
+	}
+	if r.Bee("racebee") != nil || r.Bee("renamedbee") == nil {
+		t.Error("Expected the registry to track the renamed bee")
+	}
+	if err := r.RenameBee("racebee", "otherbee"); err != ErrUnknownBee {
+		t.Errorf("Expected ErrUnknownBee, got %v", err)
+	}
+}
+
+func init() {
+	RegisterFactory(&testFactory{
+		id: "validatingbee",
+		validate: func(options BeeOptions) []error {
+			if options.Value("token") == nil {
+				return []error{errors.New("Missing token")}
+			}
+			return nil
+		},
+	})
+}
+
+func TestValidateFactoryOptions(t *testing.T) {
+	if errs := ValidateOptions("validatingbee", BeeOptions{}); len(errs) != 1 || errs[0].Error() != "Missing token" {
+		t.Errorf("Expected missing token error, got %v", errs)
+	}
+	if _, err := NewBeeInstance(BeeConfig{Name: "invalidbee", Class: "validatingbee"}); err == nil {
+		t.Error("Expected bee with invalid options to fail")
+	}
+	if GetBee("invalidbee") != nil {
+		t.Error("Expected bee with invalid options not to be registered")
+	}
+}
+
+func TestStartBeesSkipsBroken(t *testing.T) {
+	errs := startBees([]BeeConfig{
+		{Name: "typobee", Class: "nosuchclass"},
+		{Name: "goodbee", Class: "recordingbee"},
+	})
+	defer DeleteBee(GetBee("goodbee"))
+
+	if len(errs) != 1 || !strings.Contains(errs[0].Error(), "typobee") {
+		t.Errorf("Expected an error for typobee, got %v", errs)
+	}
+	if GetBee("goodbee") == nil {
+		t.Error("Expected goodbee to be started")
+	}
+	if _, err := StartBee(BeeConfig{Name: "typobee", Class: "nosuchclass"}); !errors.Is(err, ErrUnknownBeeClass) {
+		t.Errorf("Expected ErrUnknownBeeClass, got %v", err)
+	}
+}
+
+func TestPrepareBees(t *testing.T) {
+	defer useEventChannel(nil)()
+	defer stopHealthChecks()
+
+	run, errs := PrepareBees([]BeeConfig{
+		{Name: "typobee", Class: "nosuchclass"},
+		{Name: "preparedbee", Class: "recordingbee"},
+	})
+	defer DeleteBee(GetBee("preparedbee"))
+
+	if len(errs) != 1 || !strings.Contains(errs[0].Error(), "typobee") {
+		t.Errorf("Expected an error for typobee, got %v", errs)
+	}
+	if bee := GetBee("preparedbee"); bee == nil || !(*bee).IsRunning() {
+		t.Error("Expected preparedbee to be running")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		run(ctx)
+	}()
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Expected the event loop to return once its context is done")
+	}
+}
+
+func TestStartBeesRejectsDuplicates(t *testing.T) {
+	errs := startBees([]BeeConfig{
+		{Name: "dupbee", Class: "recordingbee", Description: "first"},
+		{Name: "dupbee", Class: "recordingbee", Description: "second"},
+	})
+	defer DeleteBee(GetBee("dupbee"))
+
+	if len(errs) != 1 || !strings.Contains(errs[0].Error(), "entry 1") || !strings.Contains(errs[0].Error(), "entry 2") {
+		t.Errorf("Expected an error naming both entries, got %v", errs)
+	}
+	if bee := GetBee("dupbee"); bee == nil || (*bee).Description() != "first" {
+		t.Error("Expected the first dupbee to be started")
+	}
+	if _, err := StartBee(BeeConfig{Name: "dupbee", Class: "recordingbee"}); err != ErrDuplicateBee {
+		t.Errorf("Expected ErrDuplicateBee, got %v", err)
+	}
+}
+
+func TestRenameBee(t *testing.T) {
+	newRecordingBee("oldname")
+	newRecordingBee("takenname")
+	defer DeleteBee(GetBee("takenname"))
+
+	if err := RenameBee("oldname", "takenname"); err != ErrDuplicateBee {
+		t.Errorf("Expected ErrDuplicateBee, got %v", err)
+	}
+	if err := RenameBee("nosuchbee", "newname"); err != ErrUnknownBee {
+		t.Errorf("Expected ErrUnknownBee, got %v", err)
+	}
+	if err := RenameBee("oldname", "newname"); err != nil {
+		t.Fatal(err)
+	}
+	defer DeleteBee(GetBee("newname"))
+
+	if GetBee("oldname") != nil {
+		t.Error("Expected oldname to be gone")
+	}
+	if bee := GetBee("newname"); bee == nil || (*bee).Name() != "newname" {
+		t.Error("Expected bee to be registered as newname")
+	}
+	if c, ok := instanceConfig("newname"); !ok || c.Name != "newname" {
+		t.Errorf("Expected config to move along, got %v", c)
+	}
+}
+
+func TestMandatoryOptions(t *testing.T) {
+	RegisterFactory(&testFactory{
+		id: "mandatorybee",
+		options: []BeeOptionDescriptor{
+			{Name: "url", Type: "url", Mandatory: true},
+			{Name: "interval", Type: "int", Mandatory: true, Default: 60},
+		},
+	})
+
+	if errs := ValidateOptions("mandatorybee", BeeOptions{}); len(errs) != 1 || !strings.Contains(errs[0].Error(), "url") {
+		t.Errorf("Expected missing url error, got %v", errs)
+	}
+	if errs := ValidateOptions("mandatorybee", BeeOptions{{Name: "url", Value: "http://example.com"}}); len(errs) > 0 {
+		t.Error(errs)
+	}
+}
+
+func TestBeeStats(t *testing.T) {
+	mod := newRecordingBee("statsbee")
+	defer DeleteBee(GetBee("statsbee"))
+
+	mod.LogEvent()
+	mod.LogEvent()
+	mod.LogAction()
+	mod.beeStats().recordPanic("boom")
+
+	stats, ok := GetBeeStats("statsbee")
+	if !ok {
+		t.Fatal("Expected stats for statsbee")
+	}
+	if stats.EventsReceived != 2 || stats.ActionsExecuted != 1 || stats.Panics != 1 {
+		t.Errorf("Unexpected stats: %+v", stats)
+	}
+	if stats.LastError != "boom" {
+		t.Errorf("Expected last error boom, got %q", stats.LastError)
+	}
+	if _, ok := GetAllStats()["statsbee"]; !ok {
+		t.Error("Expected statsbee in all stats")
+	}
+	if _, ok := GetBeeStats("nosuchbee"); ok {
+		t.Error("Expected no stats for unknown bee")
+	}
+
+	if total := Stats(); total.EventsReceived < 2 || total.LastEvent.IsZero() {
+		t.Errorf("Expected aggregated stats to include statsbee, got %+v", total)
+	}
+
+	RestartBee(GetBee("statsbee"))
+	if stats := mod.Stats(); stats.EventsReceived != 2 {
+		t.Errorf("Expected RestartBee to preserve counters, got %+v", stats)
+	}
+
+	mod.Stop()
+	if stats := mod.Stats(); stats.Uptime != 0 {
+		t.Errorf("Expected zero uptime of stopped bee, got %v", stats.Uptime)
+	}
+}
+
+func TestActionErrorStats(t *testing.T) {
+	mod := newRecordingBee("failstatsbee")
+	defer DeleteBee(GetBee("failstatsbee"))
+
+	func() {
+		defer func() { recover() }()
+		execAction(context.Background(), Action{Bee: "failstatsbee", Name: "fail"}, nil, nil)
+	}()
+
+	stats := mod.Stats()
+	if stats.ActionsExecuted != 1 || stats.ActionErrors != 1 || stats.LastAction.IsZero() {
+		t.Errorf("Unexpected stats: %+v", stats)
+	}
+}
+
+// panickingBee crashes whenever it gets run.
+type panickingBee struct {
+	recordingBee
+}
+
+func (mod *panickingBee) Run(ctx context.Context, eventChan chan Event) {
+	panic("boom")
+}
+
+func TestBeeCrashes(t *testing.T) {
+	SetRestartBackoff(time.Millisecond, time.Millisecond)
+	defer SetRestartBackoff(DefaultRestartBackoff, DefaultMaxRestartBackoff)
+
+	mod := &panickingBee{recordingBee: recordingBee{Bee: NewBee("panickingbee", "recordingbee", "", BeeOptions{})}}
+	if mod.State() != BeeStopped {
+		t.Errorf("Expected new bee to be stopped, got %v", mod.State())
+	}
+
+	var bee BeeInterface = mod
+	launchBee(&bee)
+	for i := 0; i < 100 && mod.State() != BeeCrashed; i++ {
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	if mod.State() != BeeCrashed {
+		t.Fatalf("Expected bee to crash, got %v", mod.State())
+	}
+	if mod.LastError() == nil || mod.LastError().Error() != "boom" {
+		t.Errorf("Expected last error boom, got %v", mod.LastError())
+	}
+	if mod.IsRunning() {
+		t.Error("Expected crashed bee to be stopped")
+	}
+	if n := mod.Stats().Panics; n != 3 {
+		t.Errorf("Expected 3 panics, got %d", n)
+	}
+	if n := mod.Stats().Restarts; n != 2 {
+		t.Errorf("Expected 2 restarts, got %d", n)
+	}
+
+	mod.SetState(BeeDegraded)
+	if s := mod.State().String(); s != "degraded" {
+		t.Errorf("Expected degraded state, got %s", s)
+	}
+}
+
+// crashBee registers and launches a panickingBee, configured as critical or
+// not, that gets terminated after its first crash.
+func crashBee(name string, critical bool) *panickingBee {
+	setInstanceConfig(BeeConfig{Name: name, Critical: critical, MaxRetries: -1})
+	mod := &panickingBee{recordingBee: recordingBee{Bee: NewBee(name, "recordingbee", "", BeeOptions{})}}
+	var bee BeeInterface = mod
+	RegisterBee(mod)
+	launchBee(&bee)
+
+	return mod
+}
+
+func TestCriticalFailure(t *testing.T) {
+	failed := make(chan string, 2)
+	OnCriticalFailure(func(bee string) {
+		failed <- bee
+	})
+	defer OnCriticalFailure(nil)
+
+	crashBee("uncriticalbee", false)
+	defer deleteInstanceConfig("uncriticalbee")
+	defer DeleteBee(GetBee("uncriticalbee"))
+	mod := crashBee("criticalbee", true)
+	defer deleteInstanceConfig("criticalbee")
+	defer DeleteBee(GetBee("criticalbee"))
+
+	select {
+	case bee := <-failed:
+		if bee != "criticalbee" {
+			t.Errorf("Expected the critical bee to fail, got %s", bee)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Expected the critical failure handler to be called")
+	}
+	if mod.State() != BeeCrashed || mod.IsRunning() {
+		t.Errorf("Expected the critical bee to be terminated, got %v", mod.State())
+	}
+
+	select {
+	case bee := <-failed:
+		t.Errorf("Expected only critical bees to fail the hive, got %s", bee)
+	case <-time.After(20 * time.Millisecond):
+	}
+}
+
+func TestCriticalFailureStopsBees(t *testing.T) {
+	defer useEventChannel(make(chan Event))()
+
+	// the default handler stops all bees from the failing bee's own failure
+	// path, including the failing bee itself
+	done := make(chan struct{})
+	OnCriticalFailure(func(bee string) {
+		defaultCriticalFailureHandler(bee)
+		close(done)
+	})
+	defer OnCriticalFailure(nil)
+
+	other := newRecordingBee("bystanderbee")
+	crashBee("fatalbee", true)
+	defer deleteInstanceConfig("fatalbee")
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Expected stopping all bees from the failure path to return")
+	}
+	if other.IsRunning() || GetBee("bystanderbee") != nil || GetBee("fatalbee") != nil {
+		t.Error("Expected all bees to be stopped and removed")
+	}
+}
+
+func TestBeeRestartPolicy(t *testing.T) {
+	SetRestartBackoff(100*time.Millisecond, 400*time.Millisecond)
+	defer SetRestartBackoff(DefaultRestartBackoff, DefaultMaxRestartBackoff)
+
+	for fatals, max := range map[int]time.Duration{1: 100, 2: 200, 3: 400, 10: 400} {
+		max *= time.Millisecond
+		if d := restartDelay(fatals); d < max/2 || d > max {
+			t.Errorf("Expected delay after %d crashes between %v and %v, got %v", fatals, max/2, max, d)
+		}
+	}
+
+	setInstanceConfig(BeeConfig{Name: "fragilebee", MaxRetries: -1})
+	setInstanceConfig(BeeConfig{Name: "sturdybee", MaxRetries: 5})
+	defer deleteInstanceConfig("fragilebee")
+	defer deleteInstanceConfig("sturdybee")
+	if n := maxRetries("fragilebee"); n != 0 {
+		t.Errorf("Expected no retries, got %d", n)
+	}
+	if n := maxRetries("sturdybee"); n != 5 {
+		t.Errorf("Expected 5 retries, got %d", n)
+	}
+	if n := maxRetries("nosuchbee"); n != DefaultMaxRetries {
+		t.Errorf("Expected the default retries, got %d", n)
+	}
+
+	// stopping a bee waiting to be restarted doesn't block
+	SetRestartBackoff(time.Hour, time.Hour)
+	mod := &panickingBee{recordingBee: recordingBee{Bee: NewBee("waitingbee", "recordingbee", "", BeeOptions{})}}
+	var bee BeeInterface = mod
+	launchBee(&bee)
+	for i := 0; i < 100 && mod.Stats().Panics == 0; i++ {
+		time.Sleep(time.Millisecond)
+	}
+	done := make(chan struct{})
+	go func() {
+		mod.Stop()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Expected the pending restart to be cancelled")
+	}
+	if n := mod.Stats().Panics; n != 1 {
+		t.Errorf("Expected a single crash, got %d", n)
+	}
+}
+
+func TestGetBeesByNamespace(t *testing.T) {
+	newRecordingBee("nsbee1")
+	newRecordingBee("nsbee2")
+	defer DeleteBee(GetBee("nsbee1"))
+	defer DeleteBee(GetBee("nsbee2"))
+
+	n := CountBeesByNamespace("recordingbee")
+	if n < 2 || len(GetBeesByNamespace("recordingbee")) != n {
+		t.Errorf("Expected at least 2 recording bees, got %d", n)
+	}
+	if len(GetBeesByClass("recordingbee")) != n {
+		t.Errorf("Expected the same bees by class and namespace")
+	}
+	if CountBeesByNamespace("nosuchclass") != 0 || len(GetBeesByClass("nosuchclass")) != 0 {
+		t.Error("Expected no bees of an unknown class")
+	}
+}
+
+func TestScopedBees(t *testing.T) {
+	a, err := NewBeeInstance(BeeConfig{Name: "irc", Class: "recordingbee", Scope: "tenant-a"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer DeleteBee(a)
+	b, err := NewBeeInstance(BeeConfig{Name: "irc", Class: "recordingbee", Scope: "tenant-b"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer DeleteBee(b)
+	(*a).Start()
+	(*b).Start()
+
+	if (*a).Name() != "tenant-a/irc" || *GetBee("tenant-a/irc") != *a || *GetBee("tenant-b/irc") != *b {
+		t.Errorf("Expected bees to be identified by scope and name, got %s", (*a).Name())
+	}
+	if GetBee("irc") != nil {
+		t.Error("Expected an ambiguous bare name not to resolve")
+	}
+	if BeeInstanceID(a) == "" || BeeInstanceID(a) == BeeInstanceID(b) {
+		t.Error("Expected unique instance IDs")
+	}
+	if _, err := NewBeeInstance(BeeConfig{Name: "tenant-a/irc", Class: "recordingbee", Scope: "tenant-a"}); !errors.Is(err, ErrDuplicateBee) {
+		t.Errorf("Expected a qualified duplicate to be rejected, got %v", err)
+	}
+
+	oldActions, oldChains := GetActions(), GetChains()
+	defer func() {
+		SetActions(oldActions)
+		chains = oldChains
+	}()
+	SetActions([]Action{{ID: "post", Bee: "irc", Name: "post"}})
+	chains = []Chain{{Name: "b", Event: &Event{Bee: "irc", Name: "message"}, Actions: []string{"post"}, Scope: "tenant-b"}}
+	setInstanceConfig(BeeConfig{Name: "tenant-b/irc", Class: "recordingbee", Scope: "tenant-b"})
+
+	execChains(context.Background(), &Event{Bee: "tenant-b/irc", Name: "message"})
+	if got := (*a).(*recordingBee).executed(); len(got) != 0 {
+		t.Errorf("Expected no actions on the other scope's bee, got %v", got)
+	}
+	if got := (*b).(*recordingBee).executed(); len(got) != 1 || got[0] != "post" {
+		t.Errorf("Expected the action on the chain's scope's bee, got %v", got)
+	}
+}
+
+func TestSwapBee(t *testing.T) {
+	old, err := StartBee(BeeConfig{Name: "swapbee", Class: "recordingbee", Options: BeeOptions{{Name: "token", Value: "old"}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mod, err := SwapBee(old, BeeConfig{Name: "swapbee", Class: "recordingbee", Options: BeeOptions{{Name: "token", Value: "new"}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer DeleteBee(GetBee("swapbee"))
+
+	if (*old).IsRunning() || !(*mod).IsRunning() {
+		t.Error("Expected old bee to be stopped and new bee to be running")
+	}
+	if v := (*GetBee("swapbee")).Options().Value("token"); v != "new" {
+		t.Errorf("Expected registry to contain the new bee, got token %v", v)
+	}
+
+	if _, err := SwapBee(old, BeeConfig{Name: "swapbee", Class: "recordingbee"}); err == nil {
+		t.Error("Expected error swapping a bee that was already replaced")
+	}
+	if (*GetBee("swapbee")).Options().Value("token") != "new" {
+		t.Error("Expected failed swap to keep the current bee")
+	}
+}
+
+func TestBeeContext(t *testing.T) {
+	bee := newRecordingBee("contextbee")
+	defer DeleteBee(GetBee("contextbee"))
+	ctx := bee.Context()
+
+	woke := make(chan bool)
+	go func() {
+		woke <- bee.Sleep(time.Hour)
+	}()
+
+	bee.Stop()
+	select {
+	case <-ctx.Done():
+	default:
+		t.Fatal("Expected Stop to cancel the bee's context")
+	}
+	select {
+	case ok := <-woke:
+		if ok {
+			t.Error("Expected Sleep to report that the bee got stopped")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Expected sleeping bee to wake up on Stop")
+	}
+
+	RestartBee(GetBee("contextbee"))
+	if bee.Context() == ctx || bee.Context().Err() != nil {
+		t.Fatal("Expected restarted bee to get a fresh context")
+	}
+	if !bee.Sleep(time.Millisecond) {
+		t.Error("Expected Sleep of a running bee to complete")
+	}
+}
@@ -0,0 +1,121 @@
+/*
+ *    Copyright (C) 2026 Christian Muehlhaeuser
+ *
+ *    This program is free software: you can redistribute it and/or modify
+ *    it under the terms of the GNU Affero General Public License as published
+ *    by the Free Software Foundation, either version 3 of the License, or
+ *    (at your option) any later version.
+ *
+ *    This program is distributed in the hope that it will be useful,
+ *    but WITHOUT ANY WARRANTY; without even the implied warranty of
+ *    MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ *    GNU Affero General Public License for more details.
+ *
+ *    You should have received a copy of the GNU Affero General Public License
+ *    along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ *    Authors:
+ *      Christian Muehlhaeuser <muesli@gmail.com>
+ */
+
+// Package bees is Beehive's central module system.
+package bees
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// BeeState describes the health of a bee.
+type BeeState uint
+
+const (
+	// BeeStopped is the state of bees which aren't running
+	BeeStopped BeeState = iota
+
+	// BeeStarting is the state of bees which are being started
+	BeeStarting BeeState = iota
+
+	// BeeRunning is the state of healthy, running bees
+	BeeRunning BeeState = iota
+
+	// BeeDegraded is reported by running bees which are impaired, e.g. while
+	// they are reconnecting to a service
+	BeeDegraded BeeState = iota
+
+	// BeeCrashed is the state of bees which got terminated after crashing
+	// repeatedly
+	BeeCrashed BeeState = iota
+)
+
+// String returns the name of a BeeState.
+func (s BeeState) String() string {
+	switch s {
+	case BeeStopped:
+		return "stopped"
+	case BeeStarting:
+		return "starting"
+	case BeeRunning:
+		return "running"
+	case BeeDegraded:
+		return "degraded"
+	case BeeCrashed:
+		return "crashed"
+	}
+
+	return fmt.Sprintf("unknown (%d)", uint(s))
+}
+
+// beeHealth holds the state of a bee and its last error.
+type beeHealth struct {
+	mutex sync.RWMutex
+	state BeeState
+	err   error
+}
+
+// State returns the bee's current state.
+func (bee *Bee) State() BeeState {
+	bee.health.mutex.RLock()
+	defer bee.health.mutex.RUnlock()
+
+	return bee.health.state
+}
+
+// SetState sets the bee's current state. Bees can report being degraded with
+// it, and switch back to BeeRunning once they recovered.
+func (bee *Bee) SetState(state BeeState) {
+	bee.health.mutex.Lock()
+	defer bee.health.mutex.Unlock()
+
+	bee.health.state = state
+}
+
+// LastError returns the error that made the bee panic most recently, or nil.
+func (bee *Bee) LastError() error {
+	bee.health.mutex.RLock()
+	defer bee.health.mutex.RUnlock()
+
+	return bee.health.err
+}
+
+// recordCrash retains the value a panic of the bee was raised with.
+func (bee *Bee) recordCrash(e interface{}) {
+	bee.health.mutex.Lock()
+	defer bee.health.mutex.Unlock()
+
+	bee.health.err = errors.New(fmt.Sprint(e))
+}
+
+// healthReporter is implemented by all bees embedding Bee.
+type healthReporter interface {
+	SetState(state BeeState)
+	recordCrash(e interface{})
+}
+
+// setBeeState sets the state of a bee, if it embeds Bee.
+func setBeeState(bee *BeeInterface, state BeeState) {
+	if h, ok := (*bee).(healthReporter); ok {
+		h.SetState(state)
+	}
+}
@@ -0,0 +1,126 @@
+/*
+ *    Copyright (C) 2026 Christian Muehlhaeuser
+ *
+ *    This program is free software: you can redistribute it and/or modify
+ *    it under the terms of the GNU Affero General Public License as published
+ *    by the Free Software Foundation, either version 3 of the License, or
+ *    (at your option) any later version.
+ *
+ *    This program is distributed in the hope that it will be useful,
+ *    but WITHOUT ANY WARRANTY; without even the implied warranty of
+ *    MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ *    GNU Affero General Public License for more details.
+ *
+ *    You should have received a copy of the GNU Affero General Public License
+ *    along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ *    Authors:
+ *      Christian Muehlhaeuser <muesli@gmail.com>
+ */
+
+// Package bees is Beehive's central module system.
+package bees
+
+import (
+	"sort"
+	"sync/atomic"
+	"time"
+)
+
+// DefaultIdleThreshold is the time after which a running bee without any
+// events or actions is reported as idle.
+const DefaultIdleThreshold = time.Hour
+
+var idleThreshold = int64(DefaultIdleThreshold)
+
+// SetIdleThreshold sets the time after which a running bee without any events
+// or actions is reported as idle, see BeeStatus. A threshold of 0 never
+// reports bees as idle.
+func SetIdleThreshold(d time.Duration) {
+	atomic.StoreInt64(&idleThreshold, int64(d))
+}
+
+// BeeStatus summarizes whether a bee is alive, idle or broken.
+type BeeStatus struct {
+	Name  string
+	State BeeState
+	// Paused is set for bees paused with PauseBee
+	Paused bool
+	// Healthy is the outcome of the bee's health check. Bees which aren't
+	// running are never healthy.
+	Healthy     bool
+	HealthError string `json:",omitempty"`
+	// Idle is set for running bees which neither emitted an event nor
+	// executed an action within the idle threshold, see SetIdleThreshold.
+	// Paused bees are never idle
+	Idle       bool
+	LastEvent  time.Time
+	LastAction time.Time
+	Restarts   int64
+	Panics     int64
+	LastError  string `json:",omitempty"`
+	Uptime     time.Duration
+}
+
+// GetBeeStatus returns the status of the bee with a specific name. Running
+// bees get their health checked.
+func GetBeeStatus(name string) (BeeStatus, error) {
+	bee := GetBee(name)
+	if bee == nil {
+		return BeeStatus{}, ErrUnknownBee
+	}
+
+	return beeStatus(bee), nil
+}
+
+// GetBeeStatuses returns the status of all bees, sorted by name.
+func GetBeeStatuses() []BeeStatus {
+	r := []BeeStatus{}
+	for _, bee := range GetBees() {
+		r = append(r, beeStatus(bee))
+	}
+	sort.Slice(r, func(i, j int) bool {
+		return r[i].Name < r[j].Name
+	})
+
+	return r
+}
+
+func beeStatus(bee *BeeInterface) BeeStatus {
+	stats := (*bee).Stats()
+	s := BeeStatus{
+		Name:       (*bee).Name(),
+		State:      (*bee).State(),
+		Paused:     BeePaused((*bee).Name()),
+		LastEvent:  stats.LastEvent,
+		LastAction: stats.LastAction,
+		Restarts:   stats.Restarts,
+		Panics:     stats.Panics,
+		LastError:  stats.LastError,
+		Uptime:     stats.Uptime,
+	}
+	if !(*bee).IsRunning() {
+		return s
+	}
+
+	if err := (*bee).HealthCheck(); err != nil {
+		s.HealthError = err.Error()
+	} else {
+		s.Healthy = true
+	}
+
+	// paused bees are expected to be quiet
+	if threshold := time.Duration(atomic.LoadInt64(&idleThreshold)); threshold > 0 && !s.Paused {
+		last := stats.LastEvent
+		if stats.LastAction.After(last) {
+			last = stats.LastAction
+		}
+		if last.IsZero() {
+			// never active, count from when the bee got started
+			last = time.Now().Add(-stats.Uptime)
+		}
+		s.Idle = time.Since(last) > threshold
+	}
+
+	return s
+}
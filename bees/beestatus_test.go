@@ -0,0 +1,39 @@
+package bees
+
+import (
+	"testing"
+	"time"
+)
+
+func TestGetBeeStatus(t *testing.T) {
+	defer StopBees()
+	defer SetIdleThreshold(DefaultIdleThreshold)
+
+	errs := StartBees([]BeeConfig{
+		{Name: "status-irc", Class: "typedbee", Options: BeeOptions{{Name: "server", Value: "irc://irc.example.com"}}},
+	})
+	if len(errs) > 0 {
+		t.Fatal(errs)
+	}
+
+	s, err := GetBeeStatus("status-irc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.State == BeeStopped || !s.Healthy || s.Idle {
+		t.Errorf("Expected a healthy, busy bee, got %+v", s)
+	}
+
+	SetIdleThreshold(time.Nanosecond)
+	time.Sleep(time.Millisecond)
+	if s, _ := GetBeeStatus("status-irc"); !s.Idle {
+		t.Errorf("Expected bee to be idle, got %+v", s)
+	}
+
+	if all := GetBeeStatuses(); len(all) != 1 || all[0].Name != "status-irc" {
+		t.Errorf("Expected the status of a single bee, got %+v", all)
+	}
+	if _, err := GetBeeStatus("status-unknown"); err != ErrUnknownBee {
+		t.Errorf("Expected ErrUnknownBee, got %v", err)
+	}
+}
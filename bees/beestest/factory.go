@@ -0,0 +1,83 @@
+/*
+ *    Copyright (C) 2026 Christian Muehlhaeuser
+ *
+ *    This program is free software: you can redistribute it and/or modify
+ *    it under the terms of the GNU Affero General Public License as published
+ *    by the Free Software Foundation, either version 3 of the License, or
+ *    (at your option) any later version.
+ *
+ *    This program is distributed in the hope that it will be useful,
+ *    but WITHOUT ANY WARRANTY; without even the implied warranty of
+ *    MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ *    GNU Affero General Public License for more details.
+ *
+ *    You should have received a copy of the GNU Affero General Public License
+ *    along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ *    Authors:
+ *      Christian Muehlhaeuser <muesli@gmail.com>
+ */
+// Package beestest provides utilities for testing bees.
+package beestest
+
+import "github.com/muesli/beehive/bees"
+
+// Factory is a configurable bee factory, which makes the hive aware of a
+// bee's descriptors, e.g. to validate its events, without having to
+// implement a bee factory of its own.
+type Factory struct {
+	bees.BeeFactory
+
+	// FactoryID is the factory's ID, i.e. the namespace of its bees
+	FactoryID string
+	// EventDescriptors are the events the factory's bees emit
+	EventDescriptors []bees.EventDescriptor
+	// ActionDescriptors are the actions the factory's bees provide
+	ActionDescriptors []bees.ActionDescriptor
+	// OptionDescriptors are the options the factory's bees accept
+	OptionDescriptors []bees.BeeOptionDescriptor
+	// NewBee creates a bee, see BeeFactoryInterface.New
+	NewBee func(name, description string, options bees.BeeOptions) bees.BeeInterface
+}
+
+// RegisterFactory registers f with the hive and returns it. Registering a
+// factory with an ID that is already taken replaces the earlier factory.
+func RegisterFactory(f *Factory) *Factory {
+	bees.RegisterFactory(f)
+	return f
+}
+
+// ID returns the factory's ID.
+func (f *Factory) ID() string {
+	return f.FactoryID
+}
+
+// Name returns the factory's name.
+func (f *Factory) Name() string {
+	return f.FactoryID
+}
+
+// Description returns the factory's description.
+func (f *Factory) Description() string {
+	return "Test factory " + f.FactoryID
+}
+
+// Events returns the factory's event descriptors.
+func (f *Factory) Events() []bees.EventDescriptor {
+	return f.EventDescriptors
+}
+
+// Actions returns the factory's action descriptors.
+func (f *Factory) Actions() []bees.ActionDescriptor {
+	return f.ActionDescriptors
+}
+
+// Options returns the factory's option descriptors.
+func (f *Factory) Options() []bees.BeeOptionDescriptor {
+	return f.OptionDescriptors
+}
+
+// New creates a new bee using NewBee.
+func (f *Factory) New(name, description string, options bees.BeeOptions) bees.BeeInterface {
+	return f.NewBee(name, description, options)
+}
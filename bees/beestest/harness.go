@@ -0,0 +1,205 @@
+/*
+ *    Copyright (C) 2026 Christian Muehlhaeuser
+ *
+ *    This program is free software: you can redistribute it and/or modify
+ *    it under the terms of the GNU Affero General Public License as published
+ *    by the Free Software Foundation, either version 3 of the License, or
+ *    (at your option) any later version.
+ *
+ *    This program is distributed in the hope that it will be useful,
+ *    but WITHOUT ANY WARRANTY; without even the implied warranty of
+ *    MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ *    GNU Affero General Public License for more details.
+ *
+ *    You should have received a copy of the GNU Affero General Public License
+ *    along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ *    Authors:
+ *      Christian Muehlhaeuser <muesli@gmail.com>
+ */
+// Package beestest provides utilities for testing bees.
+package beestest
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/muesli/beehive/bees"
+)
+
+// DefaultStopTimeout is the time Close waits for the bee's Run to return.
+const DefaultStopTimeout = 5 * time.Second
+
+// Harness runs a single bee the same way the hive does and records the
+// events it emits. As the hive's event handler is shared, only one Harness
+// may be used at a time, so tests using it must not run in parallel.
+type Harness struct {
+	// StopTimeout is the time Close waits for the bee's Run to return
+	StopTimeout time.Duration
+
+	t    testing.TB
+	bee  *bees.BeeInterface
+	hook *bees.Hook
+
+	mutex   sync.Mutex
+	events  []bees.Event
+	updated chan struct{}
+	closed  bool
+}
+
+// New starts bee along with the hive's event handler. The caller has to call
+// Close once the test finished, usually with defer.
+func New(t testing.TB, bee bees.BeeInterface) *Harness {
+	h := &Harness{
+		StopTimeout: DefaultStopTimeout,
+		t:           t,
+		bee:         &bee,
+		updated:     make(chan struct{}),
+	}
+	h.hook = bees.OnEvent(h.record)
+
+	if errs := bees.StartBees(nil); len(errs) > 0 {
+		t.Fatalf("Starting the hive failed: %v", errs)
+	}
+	if err := bees.StartBeeInstance(h.bee); err != nil {
+		h.hook.Remove()
+		t.Fatalf("Starting bee %s failed: %v", bee.Name(), err)
+	}
+	if err := bees.WaitForRunning(h.bee, h.StopTimeout); err != nil {
+		t.Fatal(err)
+	}
+
+	return h
+}
+
+// Bee returns the bee under test.
+func (h *Harness) Bee() bees.BeeInterface {
+	return *h.bee
+}
+
+// record stores the events of the bee under test.
+func (h *Harness) record(event bees.Event) {
+	if event.Bee != (*h.bee).Name() {
+		return
+	}
+
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	h.events = append(h.events, event)
+	close(h.updated)
+	h.updated = make(chan struct{})
+}
+
+// Events returns all events the bee emitted so far.
+func (h *Harness) Events() []bees.Event {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	return append([]bees.Event{}, h.events...)
+}
+
+// WaitForEvent waits up to timeout for the bee to emit an event called name
+// and returns the first such event. Events emitted before the call count, too.
+func (h *Harness) WaitForEvent(name string, timeout time.Duration) (bees.Event, error) {
+	deadline := time.After(timeout)
+	for {
+		h.mutex.Lock()
+		for _, event := range h.events {
+			if event.Name == name {
+				h.mutex.Unlock()
+				return event, nil
+			}
+		}
+		updated := h.updated
+		h.mutex.Unlock()
+
+		select {
+		case <-updated:
+		case <-deadline:
+			return bees.Event{}, fmt.Errorf("No event %s emitted by bee %s within %v", name, (*h.bee).Name(), timeout)
+		}
+	}
+}
+
+// Action executes the action called name on the bee, with options built from
+// the given map, and returns its results. The placeholders' types get
+// derived from the values' Go types.
+func (h *Harness) Action(name string, options map[string]interface{}) []bees.Placeholder {
+	a := bees.Action{
+		Bee:     (*h.bee).Name(),
+		Name:    name,
+		Options: Placeholders(options),
+	}
+
+	return (*h.bee).Action((*h.bee).Context(), a)
+}
+
+// Close stops the bee and the hive's event handler. The test fails if the
+// bee's Run doesn't return within StopTimeout. Calling Close more than once
+// is a no-op.
+func (h *Harness) Close() {
+	h.mutex.Lock()
+	if h.closed {
+		h.mutex.Unlock()
+		return
+	}
+	h.closed = true
+	h.mutex.Unlock()
+
+	h.hook.Remove()
+	if err := bees.StopBeesTimeout(h.StopTimeout); err != nil {
+		h.t.Errorf("Bee %s didn't shut down cleanly: %v", (*h.bee).Name(), err)
+	}
+}
+
+// Placeholders converts a map to placeholders, sorted by name. Their types
+// get derived from the values' Go types: strings, bools, integers, floats and
+// string slices map to "string", "bool", "int", "float64" and "[]string".
+// Other values get an empty type.
+func Placeholders(m map[string]interface{}) bees.Placeholders {
+	ph := bees.Placeholders{}
+	for _, name := range sortedKeys(m) {
+		ph = append(ph, bees.Placeholder{Name: name, Type: typeOf(m[name]), Value: m[name]})
+	}
+
+	return ph
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
+
+// typeOf returns the placeholder type of a value.
+func typeOf(v interface{}) string {
+	if v == nil {
+		return ""
+	}
+	if _, ok := v.([]string); ok {
+		return "[]string"
+	}
+
+	switch reflect.TypeOf(v).Kind() {
+	case reflect.String:
+		return "string"
+	case reflect.Bool:
+		return "bool"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "int"
+	case reflect.Float32, reflect.Float64:
+		return "float64"
+	}
+
+	return ""
+}
@@ -0,0 +1,109 @@
+package beestest
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/muesli/beehive/bees"
+)
+
+// tickBee emits a "tick" event once it's running and echoes the options of
+// its actions. With stubborn set, its Run ignores being stopped instead.
+type tickBee struct {
+	bees.Bee
+
+	stubborn bool
+	release  chan struct{}
+}
+
+func (mod *tickBee) Run(ctx context.Context, eventChan chan bees.Event) {
+	if mod.stubborn {
+		<-mod.release
+		return
+	}
+	if err := bees.EmitEvent(mod, "tick"); err != nil {
+		panic(err)
+	}
+	<-ctx.Done()
+}
+
+func (mod *tickBee) ReloadOptions(options bees.BeeOptions) {
+	mod.SetOptions(options)
+}
+
+func (mod *tickBee) Action(ctx context.Context, action bees.Action) []bees.Placeholder {
+	return action.Options
+}
+
+var tickBeeFactory = RegisterFactory(&Factory{
+	FactoryID:        "tickbee",
+	EventDescriptors: []bees.EventDescriptor{{Namespace: "tickbee", Name: "tick"}},
+	NewBee: func(name, description string, options bees.BeeOptions) bees.BeeInterface {
+		return &tickBee{Bee: bees.NewBee(name, "tickbee", description, options)}
+	},
+})
+
+// recordingTB records the errors reported by Harness.Close.
+type recordingTB struct {
+	testing.TB
+	errors []string
+}
+
+func (tb *recordingTB) Errorf(format string, args ...interface{}) {
+	tb.errors = append(tb.errors, fmt.Sprintf(format, args...))
+}
+
+func TestHarness(t *testing.T) {
+	h := New(t, tickBeeFactory.New("ticker", "", nil))
+	defer h.Close()
+
+	if _, err := h.WaitForEvent("tick", time.Second); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := h.WaitForEvent("tock", 10*time.Millisecond); err == nil {
+		t.Error("Expected waiting for an event that doesn't occur to fail")
+	}
+	if n := len(h.Events()); n != 1 {
+		t.Errorf("Expected a single event, got %d", n)
+	}
+
+	res := bees.Placeholders(h.Action("echo", map[string]interface{}{
+		"text":     "hello",
+		"count":    3,
+		"ratio":    0.5,
+		"enabled":  true,
+		"channels": []string{"#beehive"},
+	}))
+	types := map[string]string{"text": "string", "count": "int", "ratio": "float64", "enabled": "bool", "channels": "[]string"}
+	if len(res) != len(types) {
+		t.Fatalf("Expected %d placeholders, got %+v", len(types), res)
+	}
+	for _, ph := range res {
+		if ph.Type != types[ph.Name] {
+			t.Errorf("Expected placeholder %s to have type %s, got %s", ph.Name, types[ph.Name], ph.Type)
+		}
+	}
+
+	h.Close()
+	if bees.GetBee("ticker") != nil {
+		t.Error("Expected Close to remove the bee")
+	}
+}
+
+func TestHarnessStuckBee(t *testing.T) {
+	bee := tickBeeFactory.New("stuck", "", nil).(*tickBee)
+	bee.stubborn = true
+	bee.release = make(chan struct{})
+	defer close(bee.release)
+
+	tb := &recordingTB{TB: t}
+	h := New(tb, bee)
+	h.StopTimeout = 50 * time.Millisecond
+	h.Close()
+
+	if len(tb.errors) != 1 {
+		t.Errorf("Expected Close to fail for a bee that doesn't stop, got %v", tb.errors)
+	}
+}
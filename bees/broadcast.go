@@ -0,0 +1,122 @@
+/*
+ *    Copyright (C) 2026 Christian Muehlhaeuser
+ *
+ *    This program is free software: you can redistribute it and/or modify
+ *    it under the terms of the GNU Affero General Public License as published
+ *    by the Free Software Foundation, either version 3 of the License, or
+ *    (at your option) any later version.
+ *
+ *    This program is distributed in the hope that it will be useful,
+ *    but WITHOUT ANY WARRANTY; without even the implied warranty of
+ *    MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ *    GNU Affero General Public License for more details.
+ *
+ *    You should have received a copy of the GNU Affero General Public License
+ *    along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ *    Authors:
+ *      Christian Muehlhaeuser <muesli@gmail.com>
+ */
+// Package bees is Beehive's central module system.
+package bees
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync/atomic"
+	"time"
+)
+
+// DefaultBroadcastTimeout is the default time BroadcastAction waits for the
+// bees to respond.
+const DefaultBroadcastTimeout = 30 * time.Second
+
+// ErrBroadcastTimeout is the error of bees that didn't respond to a broadcast
+// action in time.
+var ErrBroadcastTimeout = errors.New("Bee did not respond in time")
+
+// BroadcastResult is the outcome of a broadcast action on a single bee.
+type BroadcastResult struct {
+	Bee          string
+	Placeholders []Placeholder
+	Err          error `json:"-"`
+}
+
+var broadcastTimeout = int64(DefaultBroadcastTimeout)
+
+// SetBroadcastTimeout sets the time BroadcastAction waits for the bees to
+// respond.
+func SetBroadcastTimeout(d time.Duration) {
+	atomic.StoreInt64(&broadcastTimeout, int64(d))
+}
+
+// BroadcastAction sends an action to all running bees of the namespace named
+// by the action's Bee, or to all running bees if it's the Wildcard, which
+// provide an action with that name. The bees get called concurrently. Results
+// are sorted by bee name. Bees that didn't respond within the broadcast
+// timeout, see SetBroadcastTimeout, are included with ErrBroadcastTimeout.
+func BroadcastAction(action Action) []BroadcastResult {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(atomic.LoadInt64(&broadcastTimeout)))
+	defer cancel()
+
+	var names []string
+	for _, bee := range GetBees() {
+		if !(*bee).IsRunning() || !providesAction(bee, action.Name) {
+			continue
+		}
+		if action.Bee == Wildcard || (*bee).Namespace() == action.Bee {
+			names = append(names, (*bee).Name())
+		}
+	}
+	sort.Strings(names)
+
+	done := make(chan BroadcastResult, len(names))
+	for _, name := range names {
+		go func(name string) {
+			a := action
+			a.Bee = name
+
+			r := BroadcastResult{Bee: name}
+			func() {
+				defer func() {
+					if e := recover(); e != nil {
+						r.Err = fmt.Errorf("%v", e)
+					}
+				}()
+
+				r.Placeholders = execAction(ctx, a, map[string]interface{}{}, nil)
+			}()
+			done <- r
+		}(name)
+	}
+
+	results := make(map[string]BroadcastResult)
+	for range names {
+		select {
+		case r := <-done:
+			results[r.Bee] = r
+		case <-ctx.Done():
+		}
+		if ctx.Err() != nil {
+			break
+		}
+	}
+
+	rs := make([]BroadcastResult, 0, len(names))
+	for _, name := range names {
+		r, ok := results[name]
+		if !ok {
+			r = BroadcastResult{Bee: name, Err: ErrBroadcastTimeout}
+		}
+		rs = append(rs, r)
+	}
+	return rs
+}
+
+// providesAction returns whether a bee provides an action.
+func providesAction(bee *BeeInterface, name string) bool {
+	_, ok := beeDescriptorsOf(bee).action(name)
+	return ok
+}
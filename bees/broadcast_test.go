@@ -0,0 +1,58 @@
+package bees
+
+import (
+	"testing"
+	"time"
+)
+
+func init() {
+	RegisterFactory(&testFactory{
+		id: "broadcastbee",
+		actions: []ActionDescriptor{
+			{Namespace: "broadcastbee", Name: "shorten"},
+			{Namespace: "broadcastbee", Name: "hang"},
+		},
+	})
+}
+
+func TestBroadcastAction(t *testing.T) {
+	for _, name := range []string{"cast2", "cast1", "cast3"} {
+		mod, err := NewBeeInstance(BeeConfig{Name: name, Class: "broadcastbee"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		(*mod).Start()
+		defer DeleteBee(mod)
+	}
+	(*GetBee("cast3")).Stop()
+	other := newRecordingBee("notcast")
+	defer DeleteBee(GetBee("notcast"))
+
+	rs := BroadcastAction(Action{Bee: "broadcastbee", Name: "shorten"})
+	if len(rs) != 2 || rs[0].Bee != "cast1" || rs[1].Bee != "cast2" {
+		t.Fatalf("Expected results of the running broadcast bees, got %+v", rs)
+	}
+	for _, r := range rs {
+		if r.Err != nil || placeholderMap(r.Placeholders)["shortened_url"] != "https://sho.rt/1" {
+			t.Errorf("Unexpected result %+v", r)
+		}
+	}
+	if len(BroadcastAction(Action{Bee: Wildcard, Name: "shorten"})) != 2 {
+		t.Error("Expected wildcard to match all bees providing the action")
+	}
+	if len(other.executed()) != 0 {
+		t.Error("Expected bees not providing the action to be skipped")
+	}
+
+	SetBroadcastTimeout(50 * time.Millisecond)
+	defer SetBroadcastTimeout(DefaultBroadcastTimeout)
+	rs = BroadcastAction(Action{Bee: "broadcastbee", Name: "hang", Timeout: -1})
+	if len(rs) != 2 {
+		t.Fatalf("Expected results of all bees, got %+v", rs)
+	}
+	for _, r := range rs {
+		if r.Err == nil {
+			t.Errorf("Expected bee %s to time out", r.Bee)
+		}
+	}
+}
@@ -0,0 +1,270 @@
+/*
+ *    Copyright (C) 2026 Christian Muehlhaeuser
+ *
+ *    This program is free software: you can redistribute it and/or modify
+ *    it under the terms of the GNU Affero General Public License as published
+ *    by the Free Software Foundation, either version 3 of the License, or
+ *    (at your option) any later version.
+ *
+ *    This program is distributed in the hope that it will be useful,
+ *    but WITHOUT ANY WARRANTY; without even the implied warranty of
+ *    MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ *    GNU Affero General Public License for more details.
+ *
+ *    You should have received a copy of the GNU Affero General Public License
+ *    along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ *    Authors:
+ *      Christian Muehlhaeuser <muesli@gmail.com>
+ */
+
+// Package bees is Beehive's central module system.
+package bees
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"reflect"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+// A ChainBundle is a sharable recipe: a chain along with the actions it
+// executes and the bees it needs. Bundles use the same layout as beehive's
+// configuration files, in JSON or YAML, see ExportChain and ImportChain.
+type ChainBundle struct {
+	// Version is the schema version of the bundle, see ConfigVersion.
+	Version int `json:",omitempty"`
+
+	Chain   Chain
+	Actions []Action
+	Bees    []BeeConfig
+}
+
+// ExportChain bundles the chain with a specific name along with its actions,
+// including their compensating actions, and the bees triggering the chain or
+// executing its actions. The values of password options get replaced by
+// references to shared values named "<bee>.<option>", which users of the
+// bundle have to provide, see SetReferences.
+func ExportChain(name string) (*ChainBundle, error) {
+	c := GetChain(name)
+	if c == nil {
+		return nil, errors.New("Unknown chain " + name)
+	}
+	b := &ChainBundle{
+		Version: ConfigVersion,
+		Chain:   *c,
+		Actions: []Action{},
+		Bees:    []BeeConfig{},
+	}
+
+	beeNames := make(map[string]bool)
+	for _, trigger := range c.triggers() {
+		if trigger != nil {
+			beeNames[trigger.Bee] = true
+		}
+	}
+
+	seen := make(map[string]bool)
+	ids := append(append([]string{}, c.Actions...), c.OnError...)
+	for len(ids) > 0 {
+		id := ids[0]
+		ids = ids[1:]
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+
+		a := GetAction(id)
+		if a == nil {
+			return nil, fmt.Errorf("Chain %s: unknown action %s", name, id)
+		}
+		b.Actions = append(b.Actions, *a)
+		if !isBroadcast(*a) {
+			beeNames[a.Bee] = true
+		}
+		if len(a.Compensate) > 0 {
+			ids = append(ids, a.Compensate)
+		}
+	}
+
+	for ref := range beeNames {
+		bee := GetBee(ref)
+		if bee == nil {
+			// wildcards, groups and the system bee don't need exporting
+			continue
+		}
+		b.Bees = append(b.Bees, bundledBee(bee))
+	}
+	sort.Slice(b.Bees, func(i, j int) bool {
+		return b.Bees[i].Name < b.Bees[j].Name
+	})
+
+	return b, nil
+}
+
+// bundledBee returns the config of a bee, with its password options replaced
+// by references to shared values.
+func bundledBee(bee *BeeInterface) BeeConfig {
+	options := (*bee).Options()
+	referenceMutex.RLock()
+	if raw, ok := rawOptions[(*bee).Name()]; ok {
+		options = raw
+	}
+	referenceMutex.RUnlock()
+
+	secrets := secretOptions((*bee).Namespace())
+	c, _ := instanceConfig((*bee).Name())
+	c.Name = (*bee).Name()
+	c.Class = (*bee).Namespace()
+	c.Description = (*bee).Description()
+	c.Options = BeeOptions{}
+	for _, opt := range options {
+		if _, ok := reference(opt.Value); !ok && secrets[opt.Name] {
+			opt.Value = refPrefix + (*bee).Name() + "." + opt.Name
+		}
+		c.Options = append(c.Options, opt)
+	}
+
+	return c
+}
+
+// JSON returns the bundle encoded as JSON.
+func (b *ChainBundle) JSON() ([]byte, error) {
+	return json.MarshalIndent(b, "", "  ")
+}
+
+// YAML returns the bundle encoded as YAML, using the same keys as JSON.
+func (b *ChainBundle) YAML() ([]byte, error) {
+	j, err := json.Marshal(b)
+	if err != nil {
+		return nil, err
+	}
+
+	var doc interface{}
+	if err := json.Unmarshal(j, &doc); err != nil {
+		return nil, err
+	}
+	return yaml.Marshal(doc)
+}
+
+// ReadChainBundle decodes a bundle written as JSON or YAML.
+func ReadChainBundle(r io.Reader) (*ChainBundle, error) {
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	if !strings.HasPrefix(string(bytes.TrimSpace(data)), "{") {
+		var doc interface{}
+		if err := yaml.Unmarshal(data, &doc); err != nil {
+			return nil, err
+		}
+		if data, err = json.Marshal(stringKeys(doc)); err != nil {
+			return nil, err
+		}
+	}
+
+	var b ChainBundle
+	if err := json.Unmarshal(data, &b); err != nil {
+		return nil, err
+	}
+	if b.Version > ConfigVersion {
+		return nil, fmt.Errorf("Bundle version %d is newer than the supported version %d", b.Version, ConfigVersion)
+	}
+	return &b, nil
+}
+
+// stringKeys converts the maps of a decoded YAML document, so it can be
+// encoded as JSON.
+func stringKeys(v interface{}) interface{} {
+	switch vt := v.(type) {
+	case map[interface{}]interface{}:
+		m := make(map[string]interface{}, len(vt))
+		for k, e := range vt {
+			m[fmt.Sprint(k)] = stringKeys(e)
+		}
+		return m
+	case []interface{}:
+		for i, e := range vt {
+			vt[i] = stringKeys(e)
+		}
+	}
+	return v
+}
+
+// ImportChain reads a bundle, see ReadChainBundle, and installs its recipe:
+// bees the hive doesn't know yet get started, the actions get added and so
+// does the chain. Bees and actions already present get reused, as long as
+// they match the bundle. The bundle gets validated first, and if it
+// conflicts with the current configuration, nothing gets changed. Shared
+// values the bundle's options refer to have to be set beforehand, see
+// SetReferences.
+func ImportChain(r io.Reader) (*ChainBundle, error) {
+	b, err := ReadChainBundle(r)
+	if err != nil {
+		return nil, err
+	}
+	if len(b.Chain.Name) == 0 {
+		return nil, errors.New("Bundle contains no chain")
+	}
+
+	var errs []error
+	if GetChain(b.Chain.Name) != nil {
+		errs = append(errs, fmt.Errorf("Chain %s: already exists", b.Chain.Name))
+	}
+
+	var newActions []Action
+	for _, a := range b.Actions {
+		existing := GetAction(a.ID)
+		switch {
+		case existing == nil:
+			newActions = append(newActions, a)
+		case !reflect.DeepEqual(*existing, a):
+			errs = append(errs, fmt.Errorf("Action %s: conflicts with an existing action", a.ID))
+		}
+	}
+
+	var newBees []BeeConfig
+	for _, c := range b.Bees {
+		c = qualifyBee(c)
+		if bee := registry.Bee(c.Name); bee != nil {
+			if (*bee).Namespace() != c.Class {
+				errs = append(errs, fmt.Errorf("Bee %s: exists with class %s instead of %s", c.Name, (*bee).Namespace(), c.Class))
+			}
+			continue
+		}
+		if GetFactory(c.Class) == nil {
+			errs = append(errs, fmt.Errorf("Bee %s: %w %s", c.Name, ErrUnknownBeeClass, c.Class))
+			continue
+		}
+		resolved, err := ResolveOptions(c.Options)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("Bee %s: %v", c.Name, err))
+			continue
+		}
+		for _, err := range ValidateOptions(c.Class, resolved) {
+			errs = append(errs, fmt.Errorf("Bee %s: %v", c.Name, err))
+		}
+		newBees = append(newBees, c)
+	}
+	if len(errs) > 0 {
+		return nil, validationError(errs)
+	}
+
+	for _, c := range newBees {
+		if _, err := StartBee(c); err != nil {
+			return nil, fmt.Errorf("Bee %s: %v", c.Name, err)
+		}
+	}
+	SetActions(append(append([]Action{}, GetActions()...), newActions...))
+	AddChain(b.Chain)
+
+	return b, nil
+}
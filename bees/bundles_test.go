@@ -0,0 +1,83 @@
+package bees
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestChainBundles(t *testing.T) {
+	oldActions := GetActions()
+	defer SetActions(oldActions)
+	oldChains := chains
+	defer func() { chains = oldChains }()
+	defer StopBees()
+
+	errs := StartBees([]BeeConfig{
+		{Name: "bundle-irc", Class: "typedbee", Options: BeeOptions{
+			{Name: "server", Value: "irc://irc.example.com"},
+			{Name: "password", Value: "secret"},
+		}},
+	})
+	if len(errs) > 0 {
+		t.Fatal(errs)
+	}
+	SetActions([]Action{
+		{ID: "bundle-send", Bee: "bundle-irc", Name: "send", Compensate: "bundle-undo"},
+		{ID: "bundle-undo", Bee: "bundle-irc", Name: "undo"},
+		{ID: "unrelated", Bee: "bundle-irc", Name: "send"},
+	})
+	SetChains([]Chain{{
+		Name:    "bundle-chain",
+		Event:   &Event{Bee: "bundle-irc", Name: "message"},
+		Filters: []string{`{{test .text "beehive"}}`},
+		Actions: []string{"bundle-send"},
+	}})
+
+	b, err := ExportChain("bundle-chain")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(b.Actions) != 2 || len(b.Bees) != 1 {
+		t.Fatalf("Expected the chain's actions and bee, got %+v", b)
+	}
+	if v := b.Bees[0].Options.Value("password"); v != "$ref:bundle-irc.password" {
+		t.Errorf("Expected the password to be replaced by a reference, got %v", v)
+	}
+
+	y, err := b.YAML()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ImportChain(bytes.NewReader(y)); err == nil || !strings.Contains(err.Error(), "already exists") {
+		t.Errorf("Expected an error importing an existing chain, got %v", err)
+	}
+
+	DeleteBee(GetBee("bundle-irc"))
+	SetActions(nil)
+	SetChains(nil)
+	if _, err := ImportChain(bytes.NewReader(y)); err == nil {
+		t.Error("Expected an error for a missing shared value")
+	}
+
+	SetReferences(map[string]interface{}{"bundle-irc.password": "secret"})
+	defer SetReferences(nil)
+	if _, err := ImportChain(bytes.NewReader(y)); err != nil {
+		t.Fatal(err)
+	}
+	c := GetChain("bundle-chain")
+	if c == nil || len(c.Filters) != 1 || GetAction("bundle-undo") == nil {
+		t.Fatalf("Expected the chain and its actions to be installed, got %+v", c)
+	}
+	if bee := GetBee("bundle-irc"); bee == nil || (*bee).Options().Value("password") != "secret" {
+		t.Error("Expected the bee to be started with the shared password")
+	}
+
+	j, err := b.JSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ReadChainBundle(bytes.NewReader(j)); err != nil {
+		t.Errorf("Expected JSON bundles to be readable, got %v", err)
+	}
+}
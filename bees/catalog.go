@@ -0,0 +1,227 @@
+/*
+ *    Copyright (C) 2026 Christian Muehlhaeuser
+ *
+ *    This program is free software: you can redistribute it and/or modify
+ *    it under the terms of the GNU Affero General Public License as published
+ *    by the Free Software Foundation, either version 3 of the License, or
+ *    (at your option) any later version.
+ *
+ *    This program is distributed in the hope that it will be useful,
+ *    but WITHOUT ANY WARRANTY; without even the implied warranty of
+ *    MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ *    GNU Affero General Public License for more details.
+ *
+ *    You should have received a copy of the GNU Affero General Public License
+ *    along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ *    Authors:
+ *      Christian Muehlhaeuser <muesli@gmail.com>
+ */
+
+// Package bees is Beehive's central module system.
+package bees
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// A HiveSchema describes a hive: the options its bees get configured with,
+// the events they emit and the actions they provide.
+type HiveSchema struct {
+	ID          string
+	Name        string
+	Description string
+	Options     []BeeOptionDescriptor
+	Events      []EventDescriptor
+	Actions     []ActionDescriptor
+}
+
+// A Catalog is a machine-readable description of all registered hives and
+// the events emitted by the hive itself. It gets generated from the
+// descriptors of the factories, so it always matches the running code.
+type Catalog struct {
+	Hives []HiveSchema
+
+	// SystemEvents are emitted by the SystemBee.
+	SystemEvents []EventDescriptor
+	// BeeEvents may be emitted by bees of any hive.
+	BeeEvents []EventDescriptor
+}
+
+var (
+	systemEvents = []EventDescriptor{
+		{
+			Namespace:   SystemBee,
+			Name:        ActionFailedEvent,
+			Description: "An action panicked, failed or timed out",
+			Options: []PlaceholderDescriptor{
+				{Name: "chain", Description: "Name of the chain executing the action", Type: "string"},
+				{Name: "bee", Description: "Name of the bee executing the action", Type: "string"},
+				{Name: "action", Description: "Name of the failed action", Type: "string"},
+				{Name: "error", Description: "Error the action failed with", Type: "string"},
+			},
+		},
+		{
+			Namespace:   SystemBee,
+			Name:        ChainTimeoutEvent,
+			Description: "A chain's actions got abandoned because they exceeded the chain's timeout",
+			Options: []PlaceholderDescriptor{
+				{Name: "chain", Description: "Name of the chain", Type: "string"},
+				{Name: "elapsed", Description: "Time the actions ran for", Type: "string"},
+			},
+		},
+		{
+			Namespace:   SystemBee,
+			Name:        CycleDetectedEvent,
+			Description: "A chain got aborted because its actions would have closed a feedback loop",
+			Options: []PlaceholderDescriptor{
+				{Name: "chain", Description: "Name of the aborted chain", Type: "string"},
+				{Name: "bee", Description: "Name of the bee the action would have run on", Type: "string"},
+				{Name: "action", Description: "Name of the action", Type: "string"},
+				{Name: "path", Description: "The chains forming the loop", Type: "string"},
+			},
+		},
+		{
+			Namespace:   SystemBee,
+			Name:        SLABreachEvent,
+			Description: "A chain took longer to handle an event than permitted by its SLA",
+			Options: []PlaceholderDescriptor{
+				{Name: "chain", Description: "Name of the chain", Type: "string"},
+				{Name: "event_bee", Description: "Name of the bee which emitted the event", Type: "string"},
+				{Name: "event_name", Description: "Name of the event", Type: "string"},
+				{Name: "event_id", Description: "ID of the event", Type: "string"},
+				{Name: "sla", Description: "The permitted duration", Type: "string"},
+				{Name: "elapsed", Description: "The actual duration", Type: "string"},
+			},
+		},
+		{
+			Namespace:   SystemBee,
+			Name:        TimerEvent,
+			Description: "A scheduled timer fired",
+			Options: []PlaceholderDescriptor{
+				{Name: "timer", Description: "Name of the timer", Type: "string"},
+				{Name: "count", Description: "How often the timer fired", Type: "int"},
+				{Name: "timestamp", Description: "Time the timer fired, in RFC 3339 format", Type: "string"},
+			},
+		},
+	}
+
+	beeEvents = []EventDescriptor{
+		{
+			Name:        ReconfiguredEvent,
+			Description: "The bee's options changed",
+			Options: []PlaceholderDescriptor{
+				{Name: "bee", Description: "Name of the bee", Type: "string"},
+				{Name: "options", Description: "Names of the changed options", Type: "[]string"},
+				{Name: "values", Description: "New values of the changed options", Type: "map"},
+			},
+		},
+	}
+)
+
+// GetCatalog returns the catalog of all registered hives, sorted by their ID.
+func GetCatalog() Catalog {
+	c := Catalog{
+		SystemEvents: cloneEventDescriptors(systemEvents),
+		BeeEvents:    cloneEventDescriptors(beeEvents),
+	}
+
+	for _, f := range GetFactories() {
+		factory := *f
+		c.Hives = append(c.Hives, HiveSchema{
+			ID:          factory.ID(),
+			Name:        factory.Name(),
+			Description: factory.Description(),
+			Options:     append([]BeeOptionDescriptor{}, factory.Options()...),
+			Events:      cloneEventDescriptors(factory.Events()),
+			Actions:     cloneActionDescriptors(factory.Actions()),
+		})
+	}
+	sort.Slice(c.Hives, func(i, j int) bool {
+		return c.Hives[i].ID < c.Hives[j].ID
+	})
+
+	return c
+}
+
+// WriteMarkdown writes the catalog as Markdown documentation.
+func (c Catalog) WriteMarkdown(w io.Writer) error {
+	var b strings.Builder
+
+	b.WriteString("# Hives\n")
+	for _, h := range c.Hives {
+		fmt.Fprintf(&b, "\n## %s (`%s`)\n\n%s\n", h.Name, h.ID, h.Description)
+
+		if len(h.Options) > 0 {
+			b.WriteString("\n### Options\n\n| Name | Type | Default | Mandatory | Description |\n|---|---|---|---|---|\n")
+			for _, o := range h.Options {
+				def := ""
+				if o.Default != nil {
+					def = fmt.Sprintf("`%v`", o.Default)
+				}
+				desc := o.Description
+				if len(o.Choices) > 0 {
+					choices := []string{}
+					for _, ch := range o.Choices {
+						choices = append(choices, fmt.Sprintf("`%v`", ch))
+					}
+					desc += " (one of " + strings.Join(choices, ", ") + ")"
+				}
+				fmt.Fprintf(&b, "| `%s` | %s | %s | %s | %s |\n", o.Name, o.Type, markdownCell(def), yesNo(o.Mandatory), markdownCell(desc))
+			}
+		}
+		if len(h.Events) > 0 {
+			b.WriteString("\n### Events\n")
+			writeMarkdownEvents(&b, h.Events)
+		}
+		if len(h.Actions) > 0 {
+			b.WriteString("\n### Actions\n")
+			for _, a := range h.Actions {
+				fmt.Fprintf(&b, "\n#### `%s`\n\n%s\n", a.Name, a.Description)
+				writeMarkdownPlaceholders(&b, "Option", a.Options)
+			}
+		}
+	}
+
+	b.WriteString("\n# System events\n\nThese events get emitted by the bee `" + SystemBee + "`.\n")
+	writeMarkdownEvents(&b, c.SystemEvents)
+	b.WriteString("\n# Common events\n\nThese events may get emitted by bees of any hive.\n")
+	writeMarkdownEvents(&b, c.BeeEvents)
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func writeMarkdownEvents(b *strings.Builder, events []EventDescriptor) {
+	for _, e := range events {
+		fmt.Fprintf(b, "\n#### `%s`\n\n%s\n", e.Name, e.Description)
+		writeMarkdownPlaceholders(b, "Placeholder", e.Options)
+	}
+}
+
+func writeMarkdownPlaceholders(b *strings.Builder, title string, ps []PlaceholderDescriptor) {
+	if len(ps) == 0 {
+		return
+	}
+
+	fmt.Fprintf(b, "\n| %s | Type | Mandatory | Description |\n|---|---|---|---|\n", title)
+	for _, p := range ps {
+		fmt.Fprintf(b, "| `%s` | %s | %s | %s |\n", p.Name, p.Type, yesNo(p.Mandatory), markdownCell(p.Description))
+	}
+}
+
+// markdownCell escapes s for use in a Markdown table cell.
+func markdownCell(s string) string {
+	s = strings.Replace(s, "|", `\|`, -1)
+	return strings.Replace(s, "\n", " ", -1)
+}
+
+func yesNo(b bool) string {
+	if b {
+		return "yes"
+	}
+	return "no"
+}
@@ -0,0 +1,72 @@
+package bees
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestCatalog(t *testing.T) {
+	catalog := *remoteTestFactory
+	catalog.id = "catalogbee"
+	catalog.name = "Catalog"
+	catalog.options = []BeeOptionDescriptor{
+		{Name: "mode", Description: "How to operate", Type: "string", Default: "fast", Choices: []interface{}{"fast", "safe"}},
+	}
+	RegisterFactory(&catalog)
+
+	c := GetCatalog()
+	var hive *HiveSchema
+	for i, h := range c.Hives {
+		if i > 0 && c.Hives[i-1].ID > h.ID {
+			t.Errorf("Hives not sorted: %s before %s", c.Hives[i-1].ID, h.ID)
+		}
+		if h.ID == "catalogbee" {
+			hive = &c.Hives[i]
+		}
+	}
+	if hive == nil {
+		t.Fatal("Expected catalogbee in catalog")
+	}
+	if len(hive.Options) != 1 || len(hive.Events) != 1 || len(hive.Actions) != 1 {
+		t.Fatalf("Unexpected descriptors: %+v", hive)
+	}
+	if hive.Events[0].Options[0].Name != "text" {
+		t.Errorf("Expected placeholder text, got %+v", hive.Events[0].Options)
+	}
+
+	found := false
+	for _, e := range c.SystemEvents {
+		if e.Name == ActionFailedEvent && e.Namespace == SystemBee {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("Expected %s in system events", ActionFailedEvent)
+	}
+
+	// the catalog is meant to be consumed by external tools
+	b, err := json.Marshal(c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(b), `"Choices":["fast","safe"]`) {
+		t.Errorf("Expected choices in JSON, got %s", b)
+	}
+
+	var md strings.Builder
+	if err := c.WriteMarkdown(&md); err != nil {
+		t.Fatal(err)
+	}
+	for _, s := range []string{
+		"## Catalog (`catalogbee`)",
+		"| `mode` | string | `fast` | no | How to operate (one of `fast`, `safe`) |",
+		"#### `hello`",
+		"#### `echo`",
+		"#### `" + ActionFailedEvent + "`",
+	} {
+		if !strings.Contains(md.String(), s) {
+			t.Errorf("Expected %q in Markdown:\n%s", s, md.String())
+		}
+	}
+}
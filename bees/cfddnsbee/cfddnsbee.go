@@ -21,6 +21,7 @@
 package cfddns
 
 import (
+	"context"
 	"regexp"
 
 	"github.com/cloudflare/cloudflare-go"
@@ -37,11 +38,11 @@ type CFDDNSBee struct {
 }
 
 // Run executes the Bee's event loop.
-func (mod *CFDDNSBee) Run(eventChan chan bees.Event) {
+func (mod *CFDDNSBee) Run(ctx context.Context, eventChan chan bees.Event) {
 }
 
 // Action triggers the action passed to it.
-func (mod *CFDDNSBee) Action(action bees.Action) []bees.Placeholder {
+func (mod *CFDDNSBee) Action(ctx context.Context, action bees.Action) []bees.Placeholder {
 	outs := []bees.Placeholder{}
 
 	switch action.Name {
@@ -0,0 +1,201 @@
+/*
+ *    Copyright (C) 2026 Christian Muehlhaeuser
+ *
+ *    This program is free software: you can redistribute it and/or modify
+ *    it under the terms of the GNU Affero General Public License as published
+ *    by the Free Software Foundation, either version 3 of the License, or
+ *    (at your option) any later version.
+ *
+ *    This program is distributed in the hope that it will be useful,
+ *    but WITHOUT ANY WARRANTY; without even the implied warranty of
+ *    MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ *    GNU Affero General Public License for more details.
+ *
+ *    You should have received a copy of the GNU Affero General Public License
+ *    along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ *    Authors:
+ *      Christian Muehlhaeuser <muesli@gmail.com>
+ */
+// Package bees is Beehive's central module system.
+package bees
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// DefaultChainWorkers is the default number of workers executing chains.
+const DefaultChainWorkers = 16
+
+// chainPool executes chain jobs on a bounded number of workers. Jobs that
+// can't be picked up right away get queued, by default without a limit, so
+// the event handler never blocks on the pool. Jobs get picked up in the order
+// they were submitted, but as they run concurrently, the order in which the
+// chains of different events finish is not guaranteed, not even for events of
+// the same bee.
+type chainPool struct {
+	sync.Mutex
+	room *sync.Cond
+
+	queue   []poolJob
+	size    int
+	workers int
+
+	depth   int
+	policy  OverflowPolicy
+	dropped int64
+}
+
+// poolJob is a queued job. drop gets called instead of run if the job gets
+// discarded due to the pool's overflow policy.
+type poolJob struct {
+	run  func()
+	drop func()
+}
+
+var chainJobs = newChainPool(DefaultChainWorkers)
+
+func newChainPool(size int) *chainPool {
+	p := &chainPool{size: size}
+	p.room = sync.NewCond(&p.Mutex)
+	return p
+}
+
+// SetChainWorkers sets the number of workers executing chains. Changing it
+// doesn't affect queued or running chains: additional workers start picking
+// up queued jobs right away, superfluous workers exit once they finished
+// their current job. A size of 0 removes the limit, executing every event's
+// chains on a goroutine of their own.
+func SetChainWorkers(n int) {
+	if n < 0 {
+		n = 0
+	}
+	chainJobs.resize(n)
+}
+
+// SetChainQueue limits the number of events waiting for a free chain worker
+// to depth, and sets the policy applied to events arriving while the queue is
+// full:
+//   - OverflowBlock makes the event handler wait until there is room
+//   - OverflowDropNewest discards the arriving event
+//   - OverflowDropOldest discards the event that has been waiting longest
+//   - OverflowSpill persists the arriving event to the spill file, see
+//     SetChainSpillFile, and handles it once the queue drained
+//
+// A depth of 0 (the default) queues without a limit.
+func SetChainQueue(depth int, policy OverflowPolicy) {
+	if depth < 0 {
+		depth = 0
+	}
+
+	chainJobs.Lock()
+	defer chainJobs.Unlock()
+
+	chainJobs.depth = depth
+	chainJobs.policy = policy
+	chainJobs.room.Broadcast()
+}
+
+// submit queues a job and makes sure there are enough workers to run it. If
+// the queue is full, the pool's overflow policy gets applied.
+func (p *chainPool) submit(run, drop func()) {
+	var dropped []func()
+	p.Lock()
+	defer func() {
+		p.Unlock()
+		for _, d := range dropped {
+			d()
+		}
+	}()
+
+	for p.size > 0 && p.depth > 0 && len(p.queue) >= p.depth {
+		switch p.policy {
+		case OverflowDropNewest:
+			atomic.AddInt64(&p.dropped, 1)
+			dropped = append(dropped, drop)
+			return
+		case OverflowDropOldest:
+			atomic.AddInt64(&p.dropped, 1)
+			dropped = append(dropped, p.queue[0].drop)
+			p.queue[0] = poolJob{}
+			p.queue = p.queue[1:]
+		default:
+			// OverflowSpill ends up here if the event couldn't be spilled
+			p.room.Wait()
+		}
+	}
+
+	if p.size == 0 {
+		go run()
+		return
+	}
+
+	p.queue = append(p.queue, poolJob{run: run, drop: drop})
+	if p.workers < p.size {
+		p.workers++
+		go p.work()
+	}
+}
+
+// overflowing returns whether the queue is full and events should get
+// spilled to disk.
+func (p *chainPool) overflowing() bool {
+	p.Lock()
+	defer p.Unlock()
+
+	return p.policy == OverflowSpill && p.size > 0 && p.depth > 0 && len(p.queue) >= p.depth
+}
+
+// resize changes the size of the pool. Queued jobs get handed to
+// goroutines of their own if the limit gets removed.
+func (p *chainPool) resize(n int) {
+	p.Lock()
+	defer p.Unlock()
+
+	p.size = n
+	if n == 0 {
+		for _, job := range p.queue {
+			go job.run()
+		}
+		p.queue = nil
+		p.room.Broadcast()
+	}
+	for p.workers < n && p.workers < len(p.queue) {
+		p.workers++
+		go p.work()
+	}
+}
+
+// work runs queued jobs until the queue is empty or the pool shrank. Idle
+// workers exit instead of waiting, submit starts new ones as needed. Once the
+// queue drained, spilled events get handled.
+func (p *chainPool) work() {
+	p.Lock()
+	for len(p.queue) > 0 && p.workers <= p.size {
+		job := p.queue[0]
+		p.queue[0] = poolJob{}
+		p.queue = p.queue[1:]
+		p.room.Signal()
+
+		p.Unlock()
+		job.run()
+		p.Lock()
+	}
+	p.workers--
+	drained := len(p.queue) == 0
+	p.Unlock()
+
+	if drained {
+		replaySpilledEvents()
+	}
+}
+
+// stats returns the number of queued jobs, the size of the pool and the
+// maximum number of queued jobs.
+func (p *chainPool) stats() (int, int, int) {
+	p.Lock()
+	defer p.Unlock()
+
+	return len(p.queue), p.size, p.depth
+}
@@ -0,0 +1,145 @@
+package bees
+
+import (
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestChainWorkers(t *testing.T) {
+	old := chainJobs
+	chainJobs = newChainPool(3)
+	defer func() { chainJobs = old }()
+
+	var running, peak int64
+	var wg sync.WaitGroup
+	gate := make(chan struct{})
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		chainJobs.submit(func() {
+			defer wg.Done()
+			n := atomic.AddInt64(&running, 1)
+			for {
+				p := atomic.LoadInt64(&peak)
+				if n <= p || atomic.CompareAndSwapInt64(&peak, p, n) {
+					break
+				}
+			}
+			<-gate
+			time.Sleep(time.Millisecond)
+			atomic.AddInt64(&running, -1)
+		}, nil)
+	}
+
+	for atomic.LoadInt64(&running) < 3 {
+		time.Sleep(time.Millisecond)
+	}
+	if s := ConcurrencyStats(); s.QueuedChains != 47 || s.ChainPoolSize != 3 {
+		t.Errorf("Expected 47 queued chains in a pool of 3, got %+v", s)
+	}
+
+	// growing the pool must not lose any queued jobs
+	SetChainWorkers(5)
+	close(gate)
+	wg.Wait()
+
+	if peak > 5 || peak < 3 {
+		t.Errorf("Expected 3 to 5 simultaneous chains, got %d", peak)
+	}
+	if s := ConcurrencyStats(); s.QueuedChains != 0 {
+		t.Errorf("Expected empty queue, got %+v", s)
+	}
+}
+
+func TestChainQueueOverflow(t *testing.T) {
+	for _, tt := range []struct {
+		policy OverflowPolicy
+		ran    []int
+	}{
+		{OverflowDropNewest, []int{0, 1, 2}},
+		{OverflowDropOldest, []int{0, 3, 4}},
+	} {
+		old := chainJobs
+		chainJobs = newChainPool(1)
+		SetChainQueue(2, tt.policy)
+
+		var mutex sync.Mutex
+		var ran, dropped []int
+		gate := make(chan struct{})
+		started := make(chan struct{})
+		for i := 0; i < 5; i++ {
+			i := i
+			chainJobs.submit(func() {
+				if i == 0 {
+					close(started)
+					<-gate
+				}
+				mutex.Lock()
+				ran = append(ran, i)
+				mutex.Unlock()
+			}, func() {
+				mutex.Lock()
+				dropped = append(dropped, i)
+				mutex.Unlock()
+			})
+			if i == 0 {
+				<-started
+			}
+		}
+		if s := ConcurrencyStats(); s.QueuedChains != 2 || s.DroppedChains != 2 || s.ChainQueueDepth != 2 {
+			t.Errorf("Expected 2 queued and 2 dropped chains, got %+v", s)
+		}
+		close(gate)
+		for deadline := time.Now().Add(time.Second); time.Now().Before(deadline); time.Sleep(time.Millisecond) {
+			mutex.Lock()
+			n := len(ran)
+			mutex.Unlock()
+			if n == 3 {
+				break
+			}
+		}
+
+		mutex.Lock()
+		if !reflect.DeepEqual(ran, tt.ran) || len(dropped) != 2 {
+			t.Errorf("Policy %d: expected jobs %v to run, got %v (dropped %v)", tt.policy, tt.ran, ran, dropped)
+		}
+		mutex.Unlock()
+		chainJobs = old
+	}
+}
+
+func TestChainQueueBlock(t *testing.T) {
+	old := chainJobs
+	chainJobs = newChainPool(1)
+	defer func() { chainJobs = old }()
+	SetChainQueue(1, OverflowBlock)
+
+	gate := make(chan struct{})
+	var ran int64
+	job := func() {
+		<-gate
+		atomic.AddInt64(&ran, 1)
+	}
+	chainJobs.submit(job, nil)
+	chainJobs.submit(job, nil)
+
+	submitted := make(chan struct{})
+	go func() {
+		chainJobs.submit(job, nil)
+		close(submitted)
+	}()
+	select {
+	case <-submitted:
+		t.Fatal("Expected submit to block while the queue is full")
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	close(gate)
+	select {
+	case <-submitted:
+	case <-time.After(time.Second):
+		t.Fatal("Expected submit to continue once there was room")
+	}
+}
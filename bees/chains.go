@@ -21,7 +21,20 @@
 // Package bees is Beehive's central module system.
 package bees
 
-import log "github.com/sirupsen/logrus"
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime/debug"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/robfig/cron/v3"
+)
 
 // ChainElement is an element in a Chain
 type ChainElement struct {
@@ -37,20 +50,183 @@ type Chain struct {
 	Filters     []string
 	Actions     []string
 	Elements    []ChainElement `json:"Elements,omitempty"`
+
+	// Events lists further bee/event pairs triggering the chain, in addition
+	// to Event. In both, a Bee of "*" matches events of any bee, and a Name
+	// of "*" matches any event of the bee. A chain gets executed at most once
+	// per event, no matter how many of its pairs match.
+	Events []*Event `json:"Events,omitempty"`
+
+	// FilterTree composes filters with All, Any and Not groups. The flat
+	// Filters are combined with it, as if they were part of an All group.
+	FilterTree *FilterNode `json:"FilterTree,omitempty"`
+
+	// Schedule is an optional cron expression (standard 5-field format)
+	// describing when the chain is active, e.g. "* 9-17 * * MON-FRI" for
+	// business hours. Outside of it, matching events are ignored.
+	Schedule string `json:"Schedule,omitempty"`
+
+	// Windows restrict the chain to recurring time spans, e.g. weekdays from
+	// 09:00 to 17:00: the chain is active within any of them. Holidays lists
+	// iCal calendars, by URL or file path, whose events the chain is inactive
+	// during, e.g. public holidays. OutsideWindow decides what happens to
+	// matching events while the chain is inactive due to its Schedule,
+	// Windows or Holidays: "drop" (the default) ignores them, "defer" holds
+	// them back until the chain becomes active again.
+	Windows       []ActiveWindow `json:"Windows,omitempty"`
+	Holidays      []string       `json:"Holidays,omitempty"`
+	OutsideWindow string         `json:"OutsideWindow,omitempty"`
+
+	// SLA is the time within which the chain should complete handling an
+	// event. It overrides the event's own SLA. Breaches don't abort the chain,
+	// but an SLABreachEvent gets emitted once the chain completed.
+	SLA time.Duration `json:"SLA,omitempty"`
+
+	// SampleRate is the fraction of matching events the chain fires for,
+	// which allows canary testing new chains. 0 (the default) and 1 both
+	// mean the chain always fires.
+	SampleRate float64 `json:"SampleRate,omitempty"`
+
+	// Timeout is the deadline for executing the chain's actions. Once it
+	// passes, the actions get abandoned, the context handed to them gets
+	// cancelled and a ChainTimeoutEvent gets emitted. 0 means no timeout.
+	Timeout time.Duration `json:"Timeout,omitempty"`
+
+	// Scope restricts the chain to events of bees in the same scope:
+	//   - events of global bees (empty scope) are visible to all chains,
+	//     except those of profiles, see Profile
+	//   - events of scoped bees are only visible to chains of the same scope
+	// Scoped chains thus see their own scope's and global events, while
+	// global chains (the default) only see global events.
+	Scope string `json:"Scope,omitempty"`
+
+	// BatchSize and BatchWindow enable batch mode: all events passing the
+	// chain's filters get buffered, and the chain's actions fire once for
+	// the entire batch, as soon as it contains BatchSize events or
+	// BatchWindow has passed since its first event, whichever comes first.
+	// BatchCron is an optional cron expression (standard 5-field format)
+	// firing batches at fixed times instead, e.g. "0 8 * * *" for a daily
+	// digest. Partial batches get flushed when the hive shuts down.
+	BatchSize   int           `json:"BatchSize,omitempty"`
+	BatchWindow time.Duration `json:"BatchWindow,omitempty"`
+	BatchCron   string        `json:"BatchCron,omitempty"`
+
+	// DryRun makes the chain log the actions it would execute, including
+	// their resolved options, instead of executing them. Filters still get
+	// evaluated as usual.
+	DryRun bool `json:"DryRun,omitempty"`
+
+	// Priority decides the order in which chains matching the same event get
+	// executed: chains with a higher priority run first. Chains of equal
+	// priority keep their configured order. The default priority is 0.
+	Priority int `json:"Priority,omitempty"`
+
+	// StopPropagation makes the chain consume the events it fires for: once
+	// an event passed the chain's filters, the chains ordered after it don't
+	// get to handle the event anymore.
+	StopPropagation bool `json:"StopPropagation,omitempty"`
+
+	// Enabled can be set to false to disable the chain, e.g. to mute it
+	// during maintenance. Disabled chains don't execute their actions, but
+	// count the events they would have fired for. Unset means enabled.
+	Enabled *bool `json:"Enabled,omitempty"`
+
+	// MinInterval rate-limits the chain: it doesn't fire again until
+	// MinInterval has passed since it fired last.
+	MinInterval time.Duration `json:"MinInterval,omitempty"`
+
+	// Debounce collapses bursts of events into a single execution: an event
+	// passing the chain's filters is held back until Debounce has passed
+	// without further events, and only the last event of the burst gets
+	// dispatched. The number of collapsed events is available to the
+	// actions as "debounced". Rate limits and batches apply to the
+	// dispatched event only.
+	Debounce time.Duration `json:"Debounce,omitempty"`
+
+	// DedupKey is a template rendered for each event passing the chain's
+	// filters, e.g. "{{.sensor}}". The chain doesn't fire for events rendering
+	// to a key it has seen within the last DedupWindow.
+	DedupKey    string        `json:"DedupKey,omitempty"`
+	DedupWindow time.Duration `json:"DedupWindow,omitempty"`
+
+	// Enrich adds placeholders to the events triggering the chain before its
+	// filters get evaluated, see Enrichment. Global enrichments have already
+	// been applied.
+	Enrich []Enrichment `json:"Enrich,omitempty"`
+
+	// Transform reshapes the event's placeholders once the chain's filters
+	// passed and before its actions' templates get rendered, see
+	// Transformation. A failing transformation fails the chain.
+	Transform []Transformation `json:"Transform,omitempty"`
+
+	// OnError lists the IDs of actions to execute when the chain fails, e.g.
+	// to send a notification. Their templates can refer to the failure as
+	// "error" and to the chain's name as "chain", besides the placeholders of
+	// the event. Failing error actions get logged and skipped.
+	OnError []string `json:"OnError,omitempty"`
+
+	// Correlation makes the chain wait for a sequence of events, see
+	// Correlation. Its events trigger the chain in addition to Event and
+	// Events, and the chain's filters get evaluated for the event completing
+	// the sequence.
+	Correlation *Correlation `json:"Correlation,omitempty"`
+
+	// EmitResults makes the chain emit an event once each of its actions
+	// completed, which other chains can react to, see emitActionResult.
+	EmitResults bool `json:"EmitResults,omitempty"`
+
+	// Mode decides how the chain's actions get executed: SequentialMode (the
+	// default) executes them one after another, making the results of each
+	// action available to the subsequent ones and stopping at the first
+	// failing action. ParallelMode executes all actions at once, their
+	// results only being available once all of them completed.
+	Mode string `json:"Mode,omitempty"`
 }
 
+// Execution modes of a chain's actions, see Chain.Mode.
+const (
+	SequentialMode = "sequential"
+	ParallelMode   = "parallel"
+)
+
 var (
-	chains []Chain
+	chains      []Chain
+	chainsMutex sync.RWMutex
+
+	globalDryRun int32
 )
 
-// GetChains returns all chains
+// SetGlobalDryRun puts all chains into dry-run mode, regardless of their own
+// DryRun setting, e.g. to smoke-test a freshly loaded config.
+func SetGlobalDryRun(enabled bool) {
+	var v int32
+	if enabled {
+		v = 1
+	}
+	atomic.StoreInt32(&globalDryRun, v)
+}
+
+// dryRun returns whether the chain's actions should only get logged.
+func (c *Chain) dryRun() bool {
+	return c.DryRun || atomic.LoadInt32(&globalDryRun) != 0
+}
+
+// IsEnabled returns whether the chain is enabled.
+func (c *Chain) IsEnabled() bool {
+	return c.Enabled == nil || *c.Enabled
+}
+
+// GetChains returns a copy of all chains
 func GetChains() []Chain {
-	return chains
+	chainsMutex.RLock()
+	defer chainsMutex.RUnlock()
+
+	return append([]Chain{}, chains...)
 }
 
 // GetChain returns a chain with a specific id
 func GetChain(id string) *Chain {
-	for _, c := range chains {
+	for _, c := range GetChains() {
 		if c.Name == id {
 			return &c
 		}
@@ -68,7 +244,7 @@ func SetChains(cs []Chain) {
 			if el.Action.Name != "" {
 				el.Action.ID = UUID()
 				c.Actions = append(c.Actions, el.Action.ID)
-				actions = append(actions, el.Action)
+				addActions(el.Action)
 			}
 			if el.Filter.Name != "" {
 				//FIXME: migrate old style filters
@@ -79,45 +255,682 @@ func SetChains(cs []Chain) {
 
 		newcs = append(newcs, c)
 	}
+	sort.SliceStable(newcs, func(i, j int) bool {
+		return newcs[i].Priority > newcs[j].Priority
+	})
+	warnInvalidChains(newcs)
 
+	chainsMutex.Lock()
+	defer chainsMutex.Unlock()
 	chains = newcs
+	pruneChainStats(newcs)
+	pruneChainTraces(newcs)
+	resetChainLimits(newcs)
+	resetCorrelations(newcs)
+}
+
+// InsertChain adds a chain, keeping the chains sorted by priority. The chain
+// gets inserted after all chains of the same priority.
+func InsertChain(c Chain) {
+	warnInvalidChains([]Chain{c})
+	resetChainLimit(c)
+
+	chainsMutex.Lock()
+	defer chainsMutex.Unlock()
+
+	i := sort.Search(len(chains), func(i int) bool {
+		return chains[i].Priority < c.Priority
+	})
+
+	// copy, so readers iterating over the old slice aren't affected
+	newcs := make([]Chain, 0, len(chains)+1)
+	newcs = append(newcs, chains[:i]...)
+	newcs = append(newcs, c)
+	chains = append(newcs, chains[i:]...)
+}
+
+// AddChain adds a chain at runtime. Like InsertChain, it keeps the chains
+// ordered by priority, appending the chain after all chains of its priority.
+func AddChain(c Chain) {
+	InsertChain(c)
 }
 
-// execChains executes chains for an event we received
-func execChains(event *Event) {
+// RemoveChain removes the chain with a specific name. Returns whether such a
+// chain existed.
+func RemoveChain(name string) bool {
+	chainsMutex.Lock()
+	defer chainsMutex.Unlock()
+
+	found := false
+	newcs := make([]Chain, 0, len(chains))
 	for _, c := range chains {
-		if c.Event.Name != event.Name || c.Event.Bee != event.Bee {
+		if c.Name == name {
+			found = true
+			continue
+		}
+		newcs = append(newcs, c)
+	}
+	chains = newcs
+	if found {
+		deleteChainStats(name)
+		deleteChainTraces(name)
+		deleteChainLimit(name)
+		deleteCorrelation(name)
+	}
+
+	return found
+}
+
+// UpdateChain replaces the chain with a specific name by c, which may carry a
+// new name. The change is applied through SetChains, so the chains stay
+// ordered by priority. Returns whether such a chain existed.
+func UpdateChain(name string, c Chain) bool {
+	cs := GetChains()
+	found := false
+	for i := range cs {
+		if cs[i].Name == name {
+			cs[i] = c
+			found = true
+			break
+		}
+	}
+	if !found {
+		return false
+	}
+
+	SetChains(cs)
+	return true
+}
+
+// EnableChain enables the chain with a specific name. Returns whether such a
+// chain exists.
+func EnableChain(name string) bool {
+	return setChainEnabled(name, true)
+}
+
+// DisableChain disables the chain with a specific name, see Chain.Enabled.
+// Returns whether such a chain exists.
+func DisableChain(name string) bool {
+	return setChainEnabled(name, false)
+}
+
+func setChainEnabled(name string, enabled bool) bool {
+	chainsMutex.Lock()
+	defer chainsMutex.Unlock()
+
+	found := false
+	// copy, so readers iterating over the old slice aren't affected
+	newcs := append([]Chain{}, chains...)
+	for i := range newcs {
+		if newcs[i].Name == name {
+			found = true
+			newcs[i].Enabled = &enabled
+		}
+	}
+	chains = newcs
+
+	return found
+}
+
+// Wildcard matches any bee or event name in a chain's trigger.
+const Wildcard = "*"
+
+// matches returns whether the chain gets triggered by event. If so, it also
+// returns how specific the best matching trigger is: 2 for an exact match, 1
+// if either the bee or the event name is a wildcard, 0 if both are. A group
+// of bees, see GroupPrefix, counts as naming the bee.
+func (c *Chain) matches(event *Event) (int, bool) {
+	best, matched := 0, false
+	for _, trigger := range c.triggers() {
+		if !triggerMatches(trigger, event) {
 			continue
 		}
 
-		m := make(map[string]interface{})
-		for _, opt := range event.Options {
-			m[opt.Name] = opt.Value
+		specificity := 0
+		if trigger.Bee != Wildcard {
+			specificity++
+		}
+		if trigger.Name != Wildcard {
+			specificity++
+		}
+		if !matched || specificity > best {
+			best = specificity
+		}
+		matched = true
+	}
+
+	return best, matched
+}
+
+// triggers returns all events triggering the chain.
+func (c *Chain) triggers() []*Event {
+	ts := append([]*Event{c.Event}, c.Events...)
+	if c.Correlation != nil {
+		ts = append(ts, c.Correlation.Events...)
+	}
+	return ts
+}
+
+// triggerMatches returns whether event matches trigger. A trigger naming a
+// bee without its scope matches bees of that name in any scope; which of
+// them a chain gets to see is up to scopeVisible.
+func triggerMatches(trigger *Event, event *Event) bool {
+	if trigger == nil {
+		return false
+	}
+	return (trigger.Bee == Wildcard || matchesBeeRef(trigger.Bee, event.Bee) || inGroup(trigger.Bee, event.Bee)) &&
+		(trigger.Name == Wildcard || trigger.Name == event.Name)
+}
+
+// activeAt returns whether the chain's schedule covers the minute of t, t
+// lies within one of its windows and not on one of its holidays.
+func (c *Chain) activeAt(t time.Time) (bool, error) {
+	if len(c.Schedule) > 0 {
+		sched, err := cron.ParseStandard(c.Schedule)
+		if err != nil {
+			return false, err
+		}
+
+		minute := t.Truncate(time.Minute)
+		if !sched.Next(minute.Add(-time.Second)).Equal(minute) {
+			return false, nil
+		}
+	}
+
+	if ok, err := c.inWindows(t); !ok || err != nil {
+		return false, err
+	}
+	return !c.onHolidays(t), nil
+}
+
+// filters returns all of the chain's filters as a single FilterNode.
+func (c *Chain) filters() FilterNode {
+	n := FilterNode{}
+	for _, f := range c.Filters {
+		n.All = append(n.All, FilterNode{Filter: f})
+	}
+	if c.FilterTree != nil {
+		n.All = append(n.All, *c.FilterTree)
+	}
+
+	return n
+}
+
+// scopeVisible returns whether events of a bee in beeScope are visible to
+// chains in chainScope.
+func scopeVisible(beeScope, chainScope string) bool {
+	if beeScope == chainScope {
+		return true
+	}
+	// profiles have an event bus of their own, which global bees don't feed
+	return len(beeScope) == 0 && GetProfile(chainScope) == nil
+}
+
+// sampled returns whether the chain should fire for a matching event.
+func (c *Chain) sampled() bool {
+	if c.SampleRate <= 0 || c.SampleRate >= 1 {
+		return true
+	}
+
+	return randFloat64() < c.SampleRate
+}
+
+// eventMap returns the placeholders of an event, together with the current
+// bee context and the chain variables, as a map suitable for filters and
+// templates.
+func eventMap(event *Event) map[string]interface{} {
+	m := make(map[string]interface{})
+	for _, opt := range event.Options {
+		m[opt.Name] = opt.Value
+	}
+	ctx.FillMap(m)
+	m["vars"] = GetVariables()
+	if event.Replayed {
+		m["replayed"] = true
+	}
+
+	return m
+}
+
+// matchingChains returns the chains triggered by event. They are ordered by
+// priority, and for chains of the same priority, chains with an exact
+// trigger come before chains only matching by wildcard.
+func matchingChains(event *Event) []Chain {
+	type match struct {
+		chain       Chain
+		specificity int
+	}
+	var ms []match
+	for _, c := range GetChains() {
+		if s, ok := c.matches(event); ok {
+			ms = append(ms, match{c, s})
+		}
+	}
+	sort.SliceStable(ms, func(i, j int) bool {
+		if ms[i].chain.Priority != ms[j].chain.Priority {
+			return ms[i].chain.Priority > ms[j].chain.Priority
 		}
-		ctx.FillMap(m)
+		return ms[i].specificity > ms[j].specificity
+	})
 
-		failed := false
-		log.Debugln("Executing chain:", c.Name, "-", c.Description)
-		for _, el := range c.Filters {
-			if execFilter(el, m) {
-				log.Debugln("\t\tPassed filter!")
+	cs := make([]Chain, len(ms))
+	for i, m := range ms {
+		cs[i] = m.chain
+	}
+	return cs
+}
+
+// execChains executes chains for an event we received. The chains' actions
+// are executed with a context derived from ctx. Returns the names of the
+// chains the event matched.
+func execChains(ctx context.Context, event *Event) []string {
+	var matched []string
+	cache := filterCache{}
+	scope := beeScope(event.Bee)
+	for _, c := range matchingChains(event) {
+		if !scopeVisible(scope, c.Scope) {
+			continue
+		}
+
+		active, err := c.activeAt(clock.Now())
+		if err != nil {
+			logger.Errorf("Invalid schedule for chain %v: %v", c.Name, err)
+			continue
+		}
+		if !active {
+			if c.OutsideWindow == DeferOutsideWindow && c.IsEnabled() {
+				deferEvent(c, *event)
+				continue
+			}
+			logger.Debugf("Skipping chain outside of its schedule: %v", c.Name)
+			continue
+		}
+		if !c.IsEnabled() {
+			passed, _, err := c.filters().evaluate(eventMap(event), cache)
+			if err == nil && passed {
+				logger.Debugf("Skipping disabled chain: %v", c.Name)
+				statsOfChain(c.Name).wouldHaveTriggered()
+			}
+			continue
+		}
+
+		matched = append(matched, c.Name)
+		if _, passed := evalChain(ctx, c, event, cache, false); passed && c.StopPropagation {
+			logger.Debugf("Chain %v stopped the propagation of the event", c.Name)
+			break
+		}
+	}
+
+	return matched
+}
+
+// execChain executes a single chain for an event, if the event passes the
+// chain's filters. Executions get recorded in the chain history, including
+// the error of a failed execution. Filter results are memoized in cache,
+// unless it is nil. Sampling and batching are skipped when replaying an
+// execution. Returns nil if the chain didn't fire (yet).
+func execChain(ctx context.Context, c Chain, event *Event, cache filterCache, replay bool) *ChainExecution {
+	exe, _ := evalChain(ctx, c, event, cache, replay)
+	return exe
+}
+
+// evalChain works like execChain, but additionally reports whether the event
+// passed the chain's filters and sampling.
+func evalChain(ctx context.Context, c Chain, event *Event, cache filterCache, replay bool) (*ChainExecution, bool) {
+	ctx, trace, done := beginChainTrace(ctx, c, event)
+	defer done()
+
+	if len(c.Enrich) > 0 {
+		enriched := *event
+		enrichEvent(ctx, &enriched, c.Enrich)
+		event = &enriched
+		// cached filter results don't know about the chain's placeholders
+		cache = nil
+	}
+	m := eventMap(event)
+	if !replay && c.correlated() && !correlateEvent(c, event, m) {
+		logger.Debugf("Chain %v waits for correlating events", c.Name)
+		trace.conclude(TraceDeferred, "Waiting for correlating events", nil)
+		return nil, false
+	}
+
+	logger.Debugf("Executing chain: %v - %v", c.Name, c.Description)
+	passed, decider, err := c.filters().evaluateTraced(m, cache, trace)
+	if err != nil {
+		logger.Errorf("Fatal filter event: %v", err)
+		trace.conclude(TraceFailed, "", err)
+		return nil, false
+	}
+	if !passed {
+		logger.Debugf("\t\tDid not pass filter: %v", decider)
+		statsOfChain(c.Name).filteredOut()
+		trace.conclude(TraceFiltered, "Did not pass filter "+decider, nil)
+		return nil, false
+	}
+	logger.Debugf("\t\tPassed filters!")
+	if !replay && !c.sampled() {
+		logger.Debugf("\t\tSkipping chain due to sampling: %v", c.Name)
+		trace.conclude(TraceSkipped, "Sampled out", nil)
+		return nil, false
+	}
+	if replay {
+		return runChain(ctx, c, event, m), true
+	}
+	if c.debounced() {
+		debounceEvent(c, *event)
+		trace.conclude(TraceDeferred, "Debounced", nil)
+		return nil, true
+	}
+
+	return dispatchChain(ctx, c, event, m), true
+}
+
+// dispatchChain executes the chain's actions for an event that passed its
+// filters, unless the chain is rate-limited. Batched chains add the event to
+// their batch instead.
+func dispatchChain(ctx context.Context, c Chain, event *Event, m map[string]interface{}) *ChainExecution {
+	if c.limited(m) {
+		chainTraceOf(ctx).conclude(TraceSkipped, "Rate-limited", nil)
+		return nil
+	}
+	if c.batched() {
+		// a full batch fires right away, concluding the trace
+		chainTraceOf(ctx).conclude(TraceDeferred, "Batched", nil)
+		addToBatch(ctx, c, *event)
+		return nil
+	}
+
+	return runChain(ctx, c, event, m)
+}
+
+// runChain executes a chain's actions, with m providing the values for the
+// actions' templates, and records the execution.
+func runChain(ctx context.Context, c Chain, event *Event, m map[string]interface{}) *ChainExecution {
+	trace := chainTraceOf(ctx)
+	if trace == nil {
+		// e.g. batches and debounced events, which fire asynchronously
+		var done func()
+		ctx, trace, done = beginChainTrace(ctx, c, event)
+		defer done()
+	}
+
+	exec := ChainExecution{
+		ID:           UUID(),
+		ChainName:    c.Name,
+		TriggerEvent: *event,
+		StartedAt:    clock.Now(),
+	}
+	stats := statsOfChain(c.Name)
+	stats.triggered()
+	if exec.Err = applyTransforms(c, m); exec.Err != nil {
+		logger.Errorf("Chain %v: %v", c.Name, exec.Err)
+	} else if c.Timeout > 0 {
+		exec.Err = runActionsTimeout(ctx, c, event, m)
+	} else {
+		exec.Err = runActions(ctx, c, event, m)
+	}
+	exec.Duration = clock.Now().Sub(exec.StartedAt)
+	checkSLA(&c, event, exec.StartedAt)
+	if exec.Err != nil {
+		exec.Error = exec.Err.Error()
+		stats.actionError()
+		deadLetter(*event, exec.Err)
+		runErrorActions(ctx, c, event, m, exec.Err)
+	}
+
+	recordExecution(exec)
+	chainExecuted(exec)
+	trace.executed(exec)
+	return &exec
+}
+
+// runErrorActions executes the OnError actions of a chain that failed with
+// err.
+func runErrorActions(ctx context.Context, c Chain, event *Event, m map[string]interface{}, err error) {
+	if len(c.OnError) == 0 {
+		return
+	}
+
+	opts := make(map[string]interface{}, len(m)+2)
+	for k, v := range m {
+		opts[k] = v
+	}
+	opts["error"] = err.Error()
+	opts["chain"] = c.Name
+
+	for _, id := range c.OnError {
+		action := GetAction(id)
+		if action == nil {
+			logger.Errorf("\t\tERROR: Unknown error action %v referenced by chain %v", id, c.Name)
+			continue
+		}
+
+		logger.Infof("\tChain %v failed, executing error action: %v / %v", c.Name, action.Bee, action.Name)
+		func() {
+			defer func() {
+				if e := recover(); e != nil {
+					logger.Errorf("\tError action failed: %v / %v - %v", action.Bee, action.Name, e)
+				}
+			}()
+
+			if isBroadcast(*action) {
+				execBroadcast(ctx, *action, opts, event)
 			} else {
-				log.Debugln("\t\tDid not pass filter!")
-				failed = true
-				break
+				execAction(ctx, *action, opts, event)
 			}
+		}()
+	}
+}
+
+// runActionsTimeout executes a chain's actions on a separate goroutine and
+// abandons them once the chain's timeout expired. In that case the context
+// handed to the actions gets cancelled and a ChainTimeoutEvent gets emitted.
+func runActionsTimeout(ctx context.Context, c Chain, event *Event, m map[string]interface{}) error {
+	ctx, cancel := context.WithTimeout(ctx, c.Timeout)
+	defer cancel()
+
+	// abandoned actions keep running, so they work on their own copy of the
+	// placeholders, which only gets merged back if they finish in time
+	results := make(map[string]interface{}, len(m))
+	for k, v := range m {
+		results[k] = v
+	}
+
+	started := clock.Now()
+	done := make(chan error, 1)
+	go func() {
+		done <- runActions(ctx, c, event, results)
+	}()
+
+	select {
+	case err := <-done:
+		for k, v := range results {
+			m[k] = v
+		}
+		return err
+	case <-ctx.Done():
+	}
+
+	elapsed := clock.Now().Sub(started)
+	if ctx.Err() != context.DeadlineExceeded {
+		return ctx.Err()
+	}
+
+	logger.Errorf("Chain %v timed out after %v", c.Name, elapsed)
+	go injectEvent(deriveEvent(event, Event{
+		Bee:  SystemBee,
+		Name: ChainTimeoutEvent,
+		Options: Placeholders{
+			{Name: "chain", Type: "string", Value: c.Name},
+			{Name: "elapsed", Type: "string", Value: elapsed.String()},
+		},
+	}))
+	return fmt.Errorf("Chain timed out after %s", elapsed)
+}
+
+// runActions executes a chain's actions. If an action fails, the
+// compensating actions get run and the failure is returned.
+func runActions(ctx context.Context, c Chain, event *Event, m map[string]interface{}) (err error) {
+	if c.Mode == ParallelMode {
+		return runActionsParallel(ctx, c, event, m)
+	}
+	ctx = withChainScope(withChainName(ctx, c.Name), c.Scope)
+	var executed []Action
+	defer func() {
+		if e := recover(); e != nil {
+			logger.Errorf("Fatal chain event: %s %s", e, debug.Stack())
+			err = fmt.Errorf("%v", e)
+			compensate(ctx, executed, m, event)
+		}
+	}()
+
+	for i, el := range c.Actions {
+		action := GetAction(el)
+		if action == nil {
+			logger.Errorf("\t\tERROR: Unknown action referenced!")
+			continue
 		}
-		if failed {
+		if c.dryRun() || event.DryRun {
+			a := resolveAction(*action, m)
+			logger.Infof("Dry run: chain %v would execute action %v / %v with options %v", c.Name, a.Bee, a.Name, a.Options)
+			event.recordDryRun(c.Name, a)
+			chainTraceOf(ctx).addAction(ActionTrace{Bee: a.Bee, Name: a.Name, Options: a.Options, StartedAt: clock.Now(), DryRun: true})
 			continue
 		}
+		if action.Delay > 0 {
+			scheduleAction(ctx, *action, m, event)
+			continue
+		}
+		if isBroadcast(*action) {
+			// results are available to subsequent actions, keyed by bee name
+			m["broadcast"] = execBroadcast(ctx, *action, m, event)
+		} else {
+			if err := checkCycle(ctx, c.Name, action.Bee, action.Name, event); err != nil {
+				compensate(ctx, executed, m, event)
+				return err
+			}
+			if action.Retries > 0 {
+				res, err := tryChainAction(ctx, c, *action, m, event)
+				if err != nil {
+					retryAction(c, *action, m, event, err)
+					continue
+				}
+				mergeResults(m, i, res)
+			} else {
+				mergeResults(m, i, execChainAction(ctx, c, *action, m, event))
+			}
+		}
+		executed = append(executed, *action)
+	}
 
-		for _, el := range c.Actions {
-			action := GetAction(el)
-			if action == nil {
-				log.Println("\t\tERROR: Unknown action referenced!")
-				continue
+	return nil
+}
+
+// runActionsParallel executes all of a chain's actions at once and waits for
+// them to complete. Their results get merged in the order of the actions. If
+// any action fails, the compensating actions of the successful ones get run
+// and all failures are returned.
+func runActionsParallel(ctx context.Context, c Chain, event *Event, m map[string]interface{}) error {
+	ctx = withChainScope(withChainName(ctx, c.Name), c.Scope)
+
+	type outcome struct {
+		action    *Action
+		res       []Placeholder
+		broadcast map[string]interface{}
+		err       error
+	}
+	outcomes := make([]outcome, len(c.Actions))
+
+	var wg sync.WaitGroup
+	for i, el := range c.Actions {
+		action := GetAction(el)
+		if action == nil {
+			logger.Errorf("\t\tERROR: Unknown action referenced!")
+			continue
+		}
+		if c.dryRun() || event.DryRun {
+			a := resolveAction(*action, m)
+			logger.Infof("Dry run: chain %v would execute action %v / %v with options %v", c.Name, a.Bee, a.Name, a.Options)
+			event.recordDryRun(c.Name, a)
+			chainTraceOf(ctx).addAction(ActionTrace{Bee: a.Bee, Name: a.Name, Options: a.Options, StartedAt: clock.Now(), DryRun: true})
+			continue
+		}
+		if action.Delay > 0 {
+			scheduleAction(ctx, *action, m, event)
+			continue
+		}
+
+		wg.Add(1)
+		go func(o *outcome, action Action) {
+			defer wg.Done()
+			defer func() {
+				if e := recover(); e != nil {
+					logger.Errorf("Fatal chain event: %s %s", e, debug.Stack())
+					o.err = fmt.Errorf("%v", e)
+				}
+			}()
+
+			if isBroadcast(action) {
+				o.broadcast = execBroadcast(ctx, action, m, event)
+			} else {
+				if err := checkCycle(ctx, c.Name, action.Bee, action.Name, event); err != nil {
+					o.err = err
+					return
+				}
+				if action.Retries > 0 {
+					res, err := tryChainAction(ctx, c, action, m, event)
+					if err != nil {
+						retryAction(c, action, m, event, err)
+						return
+					}
+					o.res = res
+				} else {
+					o.res = execChainAction(ctx, c, action, m, event)
+				}
 			}
-			execAction(*action, m)
+			o.action = &action
+		}(&outcomes[i], *action)
+	}
+	wg.Wait()
+
+	var executed []Action
+	var errs []string
+	for i, o := range outcomes {
+		if o.err != nil {
+			errs = append(errs, o.err.Error())
+			continue
+		}
+		if o.action == nil {
+			continue
 		}
+		if o.broadcast != nil {
+			m["broadcast"] = o.broadcast
+		} else {
+			mergeResults(m, i, o.res)
+		}
+		executed = append(executed, *o.action)
+	}
+	if len(errs) > 0 {
+		compensate(ctx, executed, m, event)
+		return errors.New(strings.Join(errs, "; "))
+	}
+
+	return nil
+}
+
+// mergeResults makes the results of the i-th action of a chain available to
+// the subsequent actions. Results are accessible directly by name, with the
+// newest result winning on collisions, as well as namespaced by the action's
+// position in the chain, e.g. "{{.action0.result}}".
+func mergeResults(m map[string]interface{}, i int, res []Placeholder) {
+	if len(res) == 0 {
+		return
+	}
+
+	ns := make(map[string]interface{})
+	for _, ph := range res {
+		m[ph.Name] = ph.Value
+		ns[ph.Name] = ph.Value
 	}
+	m["action"+strconv.Itoa(i)] = ns
 }
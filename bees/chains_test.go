@@ -0,0 +1,1145 @@
+package bees
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	return c.now
+}
+
+func TestChainSchedule(t *testing.T) {
+	fc := &fakeClock{}
+	SetClock(fc)
+	defer SetClock(nil)
+
+	c := Chain{Name: "office", Schedule: "* 9-17 * * MON-FRI"}
+	tests := []struct {
+		now    time.Time
+		active bool
+	}{
+		{time.Date(2026, 10, 14, 10, 30, 15, 0, time.Local), true}, // Wednesday
+		{time.Date(2026, 10, 14, 17, 59, 59, 0, time.Local), true}, // Wednesday
+		{time.Date(2026, 10, 14, 18, 0, 0, 0, time.Local), false},  // Wednesday
+		{time.Date(2026, 10, 18, 10, 30, 0, 0, time.Local), false}, // Sunday
+	}
+
+	for _, tt := range tests {
+		fc.now = tt.now
+		active, err := c.activeAt(clock.Now())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if active != tt.active {
+			t.Errorf("Expected chain active=%v at %s", tt.active, tt.now)
+		}
+	}
+
+	c.Schedule = ""
+	if active, _ := c.activeAt(clock.Now()); !active {
+		t.Error("Chains without a schedule should always be active")
+	}
+
+	c.Schedule = "invalid"
+	if _, err := c.activeAt(clock.Now()); err == nil {
+		t.Error("Expected an error for an invalid schedule")
+	}
+}
+
+func TestChainSampling(t *testing.T) {
+	SetRandSource(rand.NewSource(42))
+	defer SetRandSource(rand.NewSource(time.Now().UnixNano()))
+
+	for _, rate := range []float64{0, 1} {
+		c := Chain{SampleRate: rate}
+		for i := 0; i < 100; i++ {
+			if !c.sampled() {
+				t.Fatalf("Chain with sample rate %v should always fire", rate)
+			}
+		}
+	}
+
+	c := Chain{SampleRate: 0.25}
+	fired := 0
+	for i := 0; i < 10000; i++ {
+		if c.sampled() {
+			fired++
+		}
+	}
+	if fired < 2250 || fired > 2750 {
+		t.Errorf("Expected chain to fire for about 25%% of events, fired %d times", fired)
+	}
+}
+
+func TestChainCompensation(t *testing.T) {
+	bee := newRecordingBee("sagabee")
+	defer DeleteBee(GetBee("sagabee"))
+
+	oldActions := GetActions()
+	defer SetActions(oldActions)
+	SetActions([]Action{
+		{ID: "a", Bee: "sagabee", Name: "create_a", Compensate: "undo_a"},
+		{ID: "b", Bee: "sagabee", Name: "create_b", Compensate: "undo_b"},
+		{ID: "c", Bee: "sagabee", Name: "fail", Compensate: "undo_c"},
+		{ID: "undo_a", Bee: "sagabee", Name: "delete_a"},
+		{ID: "undo_b", Bee: "sagabee", Name: "fail"},
+		{ID: "undo_c", Bee: "sagabee", Name: "delete_c"},
+	})
+
+	c := Chain{Name: "saga", Event: &Event{Bee: "sagabee", Name: "trigger"}, Actions: []string{"a", "b", "c"}}
+	exec := execChain(context.Background(), c, &Event{Bee: "sagabee", Name: "trigger"}, nil, false)
+	if exec == nil || exec.Err == nil {
+		t.Fatal("Expected chain execution to fail")
+	}
+
+	exp := []string{"create_a", "create_b", "fail", "fail", "delete_a"}
+	got := bee.executed()
+	if len(got) != len(exp) {
+		t.Fatalf("Expected actions %v, got %v", exp, got)
+	}
+	for i := range exp {
+		if got[i] != exp[i] {
+			t.Errorf("Expected actions %v, got %v", exp, got)
+			break
+		}
+	}
+}
+
+type fallibleBee struct {
+	recordingBee
+}
+
+func (mod *fallibleBee) ActionE(ctx context.Context, action Action) ([]Placeholder, error) {
+	res := mod.Action(ctx, action)
+	if action.Name == "reject" {
+		return nil, errors.New("rejected")
+	}
+	return res, nil
+}
+
+func TestChainOnError(t *testing.T) {
+	var fallible BeeInterface = &fallibleBee{recordingBee: recordingBee{Bee: NewBee("falliblebee", "recordingbee", "", nil)}}
+	if err := RegisterBee(fallible); err != nil {
+		t.Fatal(err)
+	}
+	fallible.Start()
+	defer DeleteBee(&fallible)
+	notifier := newRecordingBee("notifierbee")
+	defer DeleteBee(GetBee("notifierbee"))
+
+	oldActions := GetActions()
+	defer SetActions(oldActions)
+	SetActions([]Action{
+		{ID: "accept", Bee: "falliblebee", Name: "accept"},
+		{ID: "reject", Bee: "falliblebee", Name: "reject"},
+		{ID: "notify", Bee: "notifierbee", Name: "notify", Options: Placeholders{
+			{Name: "text", Type: "string", Value: "{{.chain}} failed for {{.user}}: {{.error}}"},
+		}},
+	})
+
+	ev := &Event{Bee: "falliblebee", Name: "trigger", Options: Placeholders{{Name: "user", Type: "string", Value: "alice"}}}
+	c := Chain{Name: "fallible", Event: ev, Actions: []string{"accept"}, OnError: []string{"notify"}}
+	if exec := execChain(context.Background(), c, ev, nil, false); exec == nil || exec.Err != nil {
+		t.Fatalf("Expected chain to succeed, got %+v", exec)
+	}
+	if got := notifier.executed(); len(got) != 0 {
+		t.Errorf("Expected no error actions for a successful chain, got %v", got)
+	}
+
+	c.Actions = []string{"reject", "accept"}
+	exec := execChain(context.Background(), c, ev, nil, false)
+	if exec == nil || exec.Err == nil || exec.Err.Error() != "rejected" {
+		t.Fatalf("Expected the returned error to fail the chain, got %+v", exec)
+	}
+	if got := fallible.(*fallibleBee).executed(); len(got) != 2 || got[1] != "reject" {
+		t.Errorf("Expected chain to stop at the failing action, got %v", got)
+	}
+
+	notifier.mutex.Lock()
+	defer notifier.mutex.Unlock()
+	if len(notifier.options) != 1 {
+		t.Fatalf("Expected a single error action, got %v", notifier.actions)
+	}
+	if text := notifier.options[0].Value("text"); text != "fallible failed for alice: rejected" {
+		t.Errorf("Expected rendered error message, got %v", text)
+	}
+}
+
+func TestChainCorrelation(t *testing.T) {
+	fc := &fakeClock{now: time.Now()}
+	SetClock(fc)
+	defer SetClock(nil)
+
+	bee := newRecordingBee("correlationbee")
+	defer DeleteBee(GetBee("correlationbee"))
+
+	oldActions := GetActions()
+	defer SetActions(oldActions)
+	SetActions([]Action{
+		{ID: "alert", Bee: "correlationbee", Name: "alert", Options: Placeholders{
+			{Name: "text", Type: "string", Value: "{{.host}} {{len .correlated}}"},
+		}},
+	})
+
+	down := &Event{Bee: "monitorbee", Name: "down"}
+	deployed := &Event{Bee: "cibee", Name: "deployed"}
+	c := Chain{Name: "correlation", Actions: []string{"alert"}, Correlation: &Correlation{
+		Events: []*Event{down, deployed},
+		Window: 10 * time.Minute,
+		Key:    []string{"host"},
+	}}
+	if _, ok := c.matches(deployed); !ok {
+		t.Fatal("Expected correlated events to trigger the chain")
+	}
+	defer deleteCorrelation(c.Name)
+
+	event := func(trigger *Event, host string) *Event {
+		return &Event{Bee: trigger.Bee, Name: trigger.Name, Options: Placeholders{{Name: "host", Type: "string", Value: host}}}
+	}
+	fire := func(e *Event) bool {
+		return execChain(context.Background(), c, e, nil, false) != nil
+	}
+
+	if fire(event(deployed, "a")) {
+		t.Error("Expected events out of order not to fire the chain")
+	}
+	if fire(event(down, "a")) || fire(event(deployed, "b")) {
+		t.Error("Expected chain to wait for the sequence to complete")
+	}
+	if !fire(event(deployed, "a")) {
+		t.Fatal("Expected completed sequence to fire the chain")
+	}
+	if got := bee.executed(); len(got) != 1 {
+		t.Fatalf("Expected a single action, got %v", got)
+	}
+	bee.mutex.Lock()
+	text := bee.options[0].Value("text")
+	bee.mutex.Unlock()
+	if text != "a 2" {
+		t.Errorf("Expected correlated events in the template, got %v", text)
+	}
+
+	// sequences expire after the window
+	fire(event(down, "a"))
+	fc.now = fc.now.Add(11 * time.Minute)
+	if fire(event(deployed, "a")) {
+		t.Error("Expected expired sequence not to fire the chain")
+	}
+}
+
+func TestChainParallelMode(t *testing.T) {
+	bee := newRecordingBee("fanoutbee")
+	defer DeleteBee(GetBee("fanoutbee"))
+
+	oldActions := GetActions()
+	defer SetActions(oldActions)
+	SetActions([]Action{
+		{ID: "shorten", Bee: "fanoutbee", Name: "shorten", Compensate: "unshorten"},
+		{ID: "fail", Bee: "fanoutbee", Name: "fail"},
+		{ID: "post", Bee: "fanoutbee", Name: "post"},
+		{ID: "unshorten", Bee: "fanoutbee", Name: "unshorten"},
+	})
+
+	c := Chain{Name: "fanout", Mode: ParallelMode, Actions: []string{"shorten", "post"}}
+	m := map[string]interface{}{}
+	if err := runActions(context.Background(), c, &Event{Bee: "fanoutbee", Name: "trigger"}, m); err != nil {
+		t.Fatal(err)
+	}
+	if m["shortened_url"] != "https://sho.rt/1" || m["action0"] == nil {
+		t.Errorf("Expected results to be merged, got %v", m)
+	}
+
+	// unlike in sequential mode, a failing action doesn't stop the others
+	c.Actions = []string{"shorten", "fail", "post"}
+	if err := runActions(context.Background(), c, &Event{Bee: "fanoutbee", Name: "trigger"}, m); err == nil {
+		t.Fatal("Expected chain execution to fail")
+	}
+	counts := map[string]int{}
+	for _, name := range bee.executed() {
+		counts[name]++
+	}
+	if counts["shorten"] != 2 || counts["post"] != 2 || counts["fail"] != 1 || counts["unshorten"] != 1 {
+		t.Errorf("Unexpected actions %v", bee.executed())
+	}
+
+	if errs := ValidateChain(Chain{Name: "invalid", Mode: "random"}); len(errs) != 1 {
+		t.Errorf("Expected an error for an unknown mode, got %v", errs)
+	}
+}
+
+func TestChainScope(t *testing.T) {
+	bee := newRecordingBee("tenantbee")
+	defer DeleteBee(GetBee("tenantbee"))
+	setInstanceConfig(BeeConfig{Name: "tenantbee", Class: "recordingbee", Scope: "tenant-a"})
+
+	oldActions, oldChains := GetActions(), GetChains()
+	defer func() {
+		SetActions(oldActions)
+		chains = oldChains
+	}()
+	SetActions([]Action{
+		{ID: "global", Bee: "tenantbee", Name: "global"},
+		{ID: "a", Bee: "tenantbee", Name: "tenant-a"},
+		{ID: "b", Bee: "tenantbee", Name: "tenant-b"},
+	})
+	ev := &Event{Bee: "tenantbee", Name: "trigger"}
+	chains = []Chain{
+		{Name: "global", Event: ev, Actions: []string{"global"}},
+		{Name: "a", Event: ev, Actions: []string{"a"}, Scope: "tenant-a"},
+		{Name: "b", Event: ev, Actions: []string{"b"}, Scope: "tenant-b"},
+	}
+
+	execChains(context.Background(), &Event{Bee: "tenantbee", Name: "trigger"})
+	got := bee.executed()
+	if len(got) != 1 || got[0] != "tenant-a" {
+		t.Errorf("Expected only tenant-a chain to fire, got %v", got)
+	}
+
+	if !scopeVisible("", "tenant-b") {
+		t.Error("Expected global events to be visible to scoped chains")
+	}
+}
+
+func TestChainBatch(t *testing.T) {
+	bee := newRecordingBee("batchbee")
+	defer DeleteBee(GetBee("batchbee"))
+
+	oldActions := GetActions()
+	defer SetActions(oldActions)
+	SetActions([]Action{
+		{ID: "digest", Bee: "batchbee", Name: "digest", Options: Placeholders{
+			{Name: "text", Value: "{{range .batch}}{{.msg}}{{end}}"},
+		}},
+	})
+
+	c := Chain{Name: "digest", Event: &Event{Bee: "batchbee", Name: "error"}, Actions: []string{"digest"}, BatchSize: 3}
+	for _, msg := range []string{"a", "b", "c", "d"} {
+		execChain(context.Background(), c, &Event{Bee: "batchbee", Name: "error", Options: Placeholders{{Name: "msg", Value: msg}}}, nil, false)
+	}
+	if got := bee.executed(); len(got) != 1 {
+		t.Fatalf("Expected exactly one batch to fire, got %v", got)
+	}
+	if v := bee.options[0].Value("text"); v != "abc" {
+		t.Errorf("Expected batch abc, got %v", v)
+	}
+
+	FlushBatches()
+	if got := bee.executed(); len(got) != 2 {
+		t.Fatalf("Expected partial batch to be flushed, got %v", got)
+	}
+	if v := bee.options[1].Value("text"); v != "d" {
+		t.Errorf("Expected batch d, got %v", v)
+	}
+}
+
+func TestChainBatchCron(t *testing.T) {
+	now := time.Date(2026, 10, 14, 7, 59, 30, 0, time.Local)
+
+	c := Chain{BatchCron: "0 8 * * *"}
+	if d, err := c.batchDelay(now); err != nil || d != 30*time.Second {
+		t.Errorf("Expected batch to fire at 8:00, got %v (%v)", d, err)
+	}
+	c.BatchWindow = 10 * time.Second
+	if d, _ := c.batchDelay(now); d != 10*time.Second {
+		t.Errorf("Expected the earlier of window and schedule, got %v", d)
+	}
+
+	bee := newRecordingBee("digestbee")
+	defer DeleteBee(GetBee("digestbee"))
+
+	oldActions := GetActions()
+	defer SetActions(oldActions)
+	SetActions([]Action{{ID: "digest", Bee: "digestbee", Name: "digest"}})
+
+	// an invalid schedule must not hold back events forever
+	c = Chain{Name: "digest", Event: &Event{Bee: "digestbee", Name: "error"}, Actions: []string{"digest"}, BatchCron: "daily"}
+	execChain(context.Background(), c, &Event{Bee: "digestbee", Name: "error"}, nil, false)
+	if got := bee.executed(); len(got) != 1 {
+		t.Errorf("Expected event to fire despite the invalid schedule, got %v", got)
+	}
+}
+
+func TestChainDebounce(t *testing.T) {
+	bee := newRecordingBee("debouncebee")
+	defer DeleteBee(GetBee("debouncebee"))
+
+	oldActions := GetActions()
+	defer SetActions(oldActions)
+	SetActions([]Action{
+		{ID: "debounce-notify", Bee: "debouncebee", Name: "notify", Options: Placeholders{
+			{Name: "text", Value: "{{.msg}} of {{.debounced}}"},
+		}},
+	})
+	oldChains := chains
+	defer func() { chains = oldChains }()
+	c := Chain{Name: "debounce", Event: &Event{Bee: "debouncebee", Name: "flap"}, Actions: []string{"debounce-notify"}, Debounce: 50 * time.Millisecond}
+	SetChains([]Chain{c})
+	defer deleteChainStats("debounce")
+
+	for _, msg := range []string{"a", "b", "c"} {
+		execChain(context.Background(), c, &Event{Bee: "debouncebee", Name: "flap", Options: Placeholders{{Name: "msg", Value: msg}}}, nil, false)
+	}
+	if got := bee.executed(); len(got) != 0 {
+		t.Fatalf("Expected the chain to wait for the burst to end, got %v", got)
+	}
+	for i := 0; i < 100 && len(bee.executed()) == 0; i++ {
+		time.Sleep(5 * time.Millisecond)
+	}
+	if got := bee.executed(); len(got) != 1 {
+		t.Fatalf("Expected the burst to fire once, got %v", got)
+	}
+	bee.mutex.Lock()
+	if v := bee.options[0].Value("text"); v != "c of 3" {
+		t.Errorf("Expected the last event of the burst, got %v", v)
+	}
+	bee.mutex.Unlock()
+	if s := ChainStats("debounce"); s.Debounced != 2 || s.Triggered != 1 {
+		t.Errorf("Expected 2 debounced events, got %+v", s)
+	}
+
+	execChain(context.Background(), c, &Event{Bee: "debouncebee", Name: "flap", Options: Placeholders{{Name: "msg", Value: "d"}}}, nil, false)
+	FlushDebounces()
+	if got := bee.executed(); len(got) != 2 {
+		t.Fatalf("Expected the pending event to be flushed, got %v", got)
+	}
+}
+
+func TestChainTimeout(t *testing.T) {
+	bee := newRecordingBee("timeoutbee")
+	defer DeleteBee(GetBee("timeoutbee"))
+
+	oldActions := GetActions()
+	defer SetActions(oldActions)
+	SetActions([]Action{{ID: "slow", Bee: "timeoutbee", Name: "slow"}})
+
+	c := Chain{Name: "slow", Event: &Event{Bee: "timeoutbee", Name: "trigger"}, Actions: []string{"slow"}, Timeout: time.Minute}
+	execChain(context.Background(), c, &Event{Bee: "timeoutbee", Name: "trigger"}, nil, false)
+
+	if len(bee.ctxs) != 1 {
+		t.Fatal("Expected action to be executed once")
+	}
+	if _, ok := bee.ctxs[0].Deadline(); !ok {
+		t.Error("Expected action context to carry the chain's deadline")
+	}
+	if bee.ctxs[0].Err() == nil {
+		t.Error("Expected action context to be cancelled after the chain completed")
+	}
+}
+
+func TestChainTimeoutEvent(t *testing.T) {
+	newRecordingBee("hangbee")
+	defer DeleteBee(GetBee("hangbee"))
+
+	events := make(chan Event, 1)
+	defer useEventChannel(events)()
+
+	oldActions := GetActions()
+	defer SetActions(oldActions)
+	SetActions([]Action{{ID: "hang", Bee: "hangbee", Name: "hang"}})
+
+	c := Chain{Name: "hanging", Event: &Event{Bee: "hangbee", Name: "trigger"}, Actions: []string{"hang"}, Timeout: 20 * time.Millisecond}
+	exec := execChain(context.Background(), c, &Event{Bee: "hangbee", Name: "trigger"}, nil, false)
+	if exec == nil || exec.Err == nil {
+		t.Fatal("Expected chain execution to time out")
+	}
+
+	for {
+		select {
+		case ev := <-events:
+			if ev.Name == ActionFailedEvent {
+				// emitted by failing actions of other tests
+				continue
+			}
+			if ev.Bee != SystemBee || ev.Name != ChainTimeoutEvent || ev.Options.Value("chain") != "hanging" {
+				t.Errorf("Unexpected event %+v", ev)
+			}
+		case <-time.After(time.Second):
+			t.Error("Expected a chain timeout event")
+		}
+		return
+	}
+}
+
+func TestChainTimeoutResults(t *testing.T) {
+	newRecordingBee("abandonbee")
+	defer DeleteBee(GetBee("abandonbee"))
+	defer useEventChannel(make(chan Event, 1))()
+
+	oldActions := GetActions()
+	defer SetActions(oldActions)
+	SetActions([]Action{
+		{ID: "abandon-hang", Bee: "abandonbee", Name: "hang"},
+		{ID: "abandon-shorten", Bee: "abandonbee", Name: "shorten"},
+	})
+
+	c := Chain{Name: "abandoned", Event: &Event{Bee: "abandonbee", Name: "trigger"}, Actions: []string{"abandon-shorten", "abandon-hang"}, Timeout: 20 * time.Millisecond}
+	m := map[string]interface{}{"text": "hello"}
+	exec := runChain(context.Background(), c, &Event{Bee: "abandonbee", Name: "trigger"}, m)
+	if exec == nil || exec.Err == nil {
+		t.Fatal("Expected chain execution to time out")
+	}
+
+	if _, ok := m["shortened_url"]; ok {
+		t.Error("Expected results of abandoned actions not to be merged")
+	}
+	if m["text"] != "hello" {
+		t.Errorf("Expected placeholders to be kept, got %v", m)
+	}
+}
+
+func TestBeeActionTimeout(t *testing.T) {
+	newRecordingBee("slowbee")
+	defer DeleteBee(GetBee("slowbee"))
+	setInstanceConfig(BeeConfig{Name: "slowbee", ActionTimeout: 20 * time.Millisecond})
+	defer deleteInstanceConfig("slowbee")
+
+	events := make(chan Event, 1)
+	defer useEventChannel(events)()
+
+	oldActions := GetActions()
+	defer SetActions(oldActions)
+	SetActions([]Action{{ID: "hang", Bee: "slowbee", Name: "hang"}, {ID: "ok", Bee: "slowbee", Name: "ok"}})
+
+	if a := GetAction("hang"); a.timeout() != 20*time.Millisecond {
+		t.Errorf("Expected the bee's action timeout, got %v", a.timeout())
+	}
+	if a := (Action{Bee: "slowbee", Timeout: -1}); a.timeout() != 0 {
+		t.Errorf("Expected the action's own timeout to take precedence, got %v", a.timeout())
+	}
+
+	c := Chain{Name: "hung", Event: &Event{Bee: "slowbee", Name: "trigger"}, Actions: []string{"ok", "hang"}}
+	exec := execChain(context.Background(), c, &Event{Bee: "slowbee", Name: "trigger"}, nil, false)
+	if exec == nil || exec.Err == nil {
+		t.Fatal("Expected the hanging action to be abandoned")
+	}
+
+	s, _ := GetBeeStats("slowbee")
+	if s.ActionTimeouts != 1 {
+		t.Errorf("Expected a single timed out action, got %d", s.ActionTimeouts)
+	}
+	if s.MaxActionTime < 20*time.Millisecond || s.ActionTime < s.MaxActionTime {
+		t.Errorf("Expected action durations to be tracked, got %v total, %v max", s.ActionTime, s.MaxActionTime)
+	}
+}
+
+func TestChainPassesResults(t *testing.T) {
+	bee := newRecordingBee("shortenerbee")
+	defer DeleteBee(GetBee("shortenerbee"))
+
+	oldActions := GetActions()
+	defer SetActions(oldActions)
+	SetActions([]Action{
+		{ID: "shorten", Bee: "shortenerbee", Name: "shorten"},
+		{ID: "say", Bee: "shortenerbee", Name: "say", Options: Placeholders{
+			{Name: "text", Value: "{{.shortened_url}} {{.action0.shortened_url}}"},
+		}},
+	})
+
+	c := Chain{Name: "shorten-and-say", Actions: []string{"shorten", "say"}}
+	if err := runActions(context.Background(), c, &Event{}, map[string]interface{}{}); err != nil {
+		t.Fatal(err)
+	}
+
+	bee.mutex.Lock()
+	defer bee.mutex.Unlock()
+	if len(bee.options) != 2 {
+		t.Fatalf("Expected two actions to run, got %v", bee.actions)
+	}
+	if v := bee.options[1].Value("text"); v != "https://sho.rt/1 https://sho.rt/1" {
+		t.Errorf("Expected second action to see the first's result, got %v", v)
+	}
+}
+
+func TestChainDryRun(t *testing.T) {
+	bee := newRecordingBee("dryrunbee")
+	defer DeleteBee(GetBee("dryrunbee"))
+
+	rl := &recordingLogger{}
+	SetLogger(rl)
+	defer SetLogger(nil)
+
+	oldActions := GetActions()
+	defer SetActions(oldActions)
+	SetActions([]Action{{ID: "say", Bee: "dryrunbee", Name: "say", Options: Placeholders{
+		{Name: "text", Value: "hello {{.name}}"},
+	}}})
+
+	c := Chain{Name: "greeter", Actions: []string{"say"}, DryRun: true}
+	m := map[string]interface{}{"name": "world"}
+	if err := runActions(context.Background(), c, &Event{}, m); err != nil {
+		t.Fatal(err)
+	}
+
+	c.DryRun = false
+	SetGlobalDryRun(true)
+	err := runActions(context.Background(), c, &Event{}, m)
+	SetGlobalDryRun(false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := bee.executed(); len(got) != 0 {
+		t.Errorf("Expected no actions to be executed, got %v", got)
+	}
+	rl.Lock()
+	defer rl.Unlock()
+	n := 0
+	for _, l := range rl.lines {
+		if strings.Contains(l, "Dry run: chain greeter") && strings.Contains(l, "hello world") {
+			n++
+		}
+	}
+	if n != 2 {
+		t.Errorf("Expected two dry-run log entries, got %q", rl.lines)
+	}
+}
+
+func TestDelayedActions(t *testing.T) {
+	bee := newRecordingBee("delaybee")
+	defer DeleteBee(GetBee("delaybee"))
+
+	oldActions := GetActions()
+	defer SetActions(oldActions)
+	SetActions([]Action{
+		{ID: "later", Bee: "delaybee", Name: "later", Delay: 20 * time.Millisecond},
+		{ID: "now", Bee: "delaybee", Name: "now"},
+		{ID: "debounced", Bee: "delaybee", Name: "debounced", Delay: 20 * time.Millisecond,
+			CancelOn: &Event{Bee: "sensorbee", Name: "motion"}},
+	})
+
+	c := Chain{Name: "delayed", Actions: []string{"later", "now", "debounced"}}
+	if err := runActions(context.Background(), c, &Event{}, map[string]interface{}{}); err != nil {
+		t.Fatal(err)
+	}
+	if got := bee.executed(); len(got) != 1 || got[0] != "now" {
+		t.Errorf("Expected only the immediate action to run, got %v", got)
+	}
+	if n := PendingActions(); n != 2 {
+		t.Errorf("Expected 2 pending actions, got %d", n)
+	}
+
+	cancelDelayedActions(&Event{Bee: "sensorbee", Name: "motion"})
+	time.Sleep(100 * time.Millisecond)
+
+	if got := bee.executed(); len(got) != 2 || got[1] != "later" {
+		t.Errorf("Expected the delayed action to run after the immediate one, got %v", got)
+	}
+	if n := PendingActions(); n != 0 {
+		t.Errorf("Expected no pending actions, got %d", n)
+	}
+
+	runActions(context.Background(), c, &Event{}, map[string]interface{}{})
+	CancelDelayedActions()
+	time.Sleep(50 * time.Millisecond)
+	if got := bee.executed(); len(got) != 3 {
+		t.Errorf("Expected cancelled actions not to run, got %v", got)
+	}
+}
+
+func TestDelayedActionContext(t *testing.T) {
+	bee := newRecordingBee("delayctxbee")
+	defer DeleteBee(GetBee("delayctxbee"))
+
+	trace := actionTrace{{Bee: "sensorbee", Action: "poll"}}
+	ctx := withTrace(withChainScope(context.Background(), "team"), trace)
+	scheduleAction(ctx, Action{Bee: "delayctxbee", Name: "later", Delay: time.Millisecond}, nil, &Event{})
+	time.Sleep(50 * time.Millisecond)
+
+	bee.mutex.Lock()
+	defer bee.mutex.Unlock()
+	if len(bee.ctxs) != 1 {
+		t.Fatalf("Expected the delayed action to run, got %v", bee.actions)
+	}
+	if scope := chainScopeOf(bee.ctxs[0]); scope != "team" {
+		t.Errorf("Expected the delayed action to run in the chain's scope, got %q", scope)
+	}
+	if got := traceOf(bee.ctxs[0]); len(got) != 2 || got[0] != trace[0] {
+		t.Errorf("Expected the delayed action to continue the chain's trace, got %v", got)
+	}
+}
+
+func TestChainPriority(t *testing.T) {
+	oldChains := chains
+	defer func() { chains = oldChains }()
+
+	SetChains([]Chain{
+		{Name: "a"},
+		{Name: "b", Priority: 10},
+		{Name: "c"},
+		{Name: "d", Priority: -1},
+		{Name: "e", Priority: 10},
+	})
+	InsertChain(Chain{Name: "f", Priority: 10})
+	InsertChain(Chain{Name: "g"})
+
+	names := []string{}
+	for _, c := range GetChains() {
+		names = append(names, c.Name)
+	}
+	if got := strings.Join(names, ","); got != "b,e,f,a,c,g,d" {
+		t.Errorf("Unexpected chain order: %s", got)
+	}
+}
+
+func TestStopPropagation(t *testing.T) {
+	bee := newRecordingBee("stopbee")
+	defer DeleteBee(GetBee("stopbee"))
+
+	oldActions := GetActions()
+	defer SetActions(oldActions)
+	SetActions([]Action{
+		{ID: "stop-page", Bee: "stopbee", Name: "page"},
+		{ID: "stop-log", Bee: "stopbee", Name: "log"},
+	})
+	oldChains := chains
+	defer func() { chains = oldChains }()
+	ev := &Event{Bee: "stopbee", Name: "alert"}
+	SetChains([]Chain{
+		{Name: "log", Event: ev, Actions: []string{"stop-log"}},
+		{Name: "page", Event: ev, Priority: 10, StopPropagation: true, Filters: []string{`{{test HasPrefix .text "critical"}}`}, Actions: []string{"stop-page"}},
+	})
+
+	critical := &Event{Bee: "stopbee", Name: "alert", Options: Placeholders{{Name: "text", Type: "string", Value: "critical: disk full"}}}
+	if matched := execChains(context.Background(), critical); strings.Join(matched, ",") != "page" {
+		t.Errorf("Expected page to consume the event, got %v", matched)
+	}
+	warning := &Event{Bee: "stopbee", Name: "alert", Options: Placeholders{{Name: "text", Type: "string", Value: "warning"}}}
+	if matched := execChains(context.Background(), warning); strings.Join(matched, ",") != "page,log" {
+		t.Errorf("Expected filtered events to propagate, got %v", matched)
+	}
+	if got := strings.Join(bee.executed(), ","); got != "page,log" {
+		t.Errorf("Unexpected actions %s", got)
+	}
+}
+
+func TestActionTimeout(t *testing.T) {
+	newRecordingBee("deadbee")
+	defer DeleteBee(GetBee("deadbee"))
+
+	events := make(chan Event, 1)
+	defer useEventChannel(events)()
+
+	oldActions := GetActions()
+	defer SetActions(oldActions)
+	SetActions([]Action{
+		{ID: "hang", Bee: "deadbee", Name: "hang", Timeout: 20 * time.Millisecond},
+		{ID: "fail", Bee: "deadbee", Name: "fail"},
+	})
+
+	for _, action := range []string{"hang", "fail"} {
+		c := Chain{Name: "stuck", Actions: []string{action}}
+		if err := runActions(context.Background(), c, &Event{ID: "1"}, map[string]interface{}{}); err == nil {
+			t.Fatalf("Expected action %s to fail", action)
+		}
+
+		var ev Event
+		for ev.Options.Value("bee") != "deadbee" {
+			select {
+			case ev = <-events:
+			case <-time.After(time.Second):
+				t.Fatal("Expected an action failure event")
+			}
+		}
+		if ev.Bee != SystemBee || ev.Name != ActionFailedEvent || ev.CausationID != "1" {
+			t.Errorf("Unexpected event %+v", ev)
+		}
+		if ev.Options.Value("chain") != "stuck" || ev.Options.Value("action") != action {
+			t.Errorf("Expected failure event to describe the action, got %v", ev.Options)
+		}
+	}
+}
+
+func TestAddRemoveChain(t *testing.T) {
+	oldChains := chains
+	defer func() { chains = oldChains }()
+	ev := &Event{Bee: "nosuchbee", Name: "other"}
+	SetChains([]Chain{{Name: "a", Event: ev}})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(2)
+		go func(i int) {
+			defer wg.Done()
+			AddChain(Chain{Name: "chain" + strconv.Itoa(i), Event: ev})
+		}(i)
+		go func() {
+			defer wg.Done()
+			execChains(context.Background(), &Event{Bee: "nosuchbee", Name: "trigger"})
+		}()
+	}
+	wg.Wait()
+
+	if n := len(GetChains()); n != 21 {
+		t.Errorf("Expected 21 chains, got %d", n)
+	}
+	if !RemoveChain("chain3") || RemoveChain("chain3") {
+		t.Error("Expected chain3 to be removed exactly once")
+	}
+	if GetChain("chain3") != nil || GetChain("a") == nil {
+		t.Error("Expected only chain3 to be removed")
+	}
+}
+
+func TestUpdateChainAndActions(t *testing.T) {
+	oldChains := chains
+	defer func() { chains = oldChains }()
+	oldActions := GetActions()
+	defer SetActions(oldActions)
+
+	ev := &Event{Bee: "nosuchbee", Name: "other"}
+	SetChains([]Chain{{Name: "a", Event: ev}, {Name: "b", Event: ev}})
+	SetActions([]Action{{ID: "x", Bee: "nosuchbee", Name: "one"}, {ID: "y", Bee: "nosuchbee", Name: "two"}})
+
+	if UpdateChain("nosuchchain", Chain{Name: "c"}) {
+		t.Error("Expected updating an unknown chain to fail")
+	}
+	if !UpdateChain("b", Chain{Name: "c", Event: ev, Priority: 1}) {
+		t.Fatal("Expected chain b to be updated")
+	}
+	if cs := GetChains(); len(cs) != 2 || cs[0].Name != "c" || cs[1].Name != "a" {
+		t.Errorf("Expected the renamed chain to be sorted first, got %v", cs)
+	}
+
+	if UpdateAction(Action{ID: "z"}) {
+		t.Error("Expected updating an unknown action to fail")
+	}
+	if !UpdateAction(Action{ID: "x", Bee: "nosuchbee", Name: "three"}) || GetAction("x").Name != "three" {
+		t.Error("Expected action x to be updated")
+	}
+	if !RemoveAction("y") || RemoveAction("y") || len(GetActions()) != 1 {
+		t.Error("Expected action y to be removed exactly once")
+	}
+}
+
+func TestActionsConcurrentAccess(t *testing.T) {
+	oldActions := GetActions()
+	defer SetActions(oldActions)
+	SetActions([]Action{{ID: "x", Bee: "nosuchbee", Name: "one", Options: Placeholders{{Name: "text", Type: "string", Value: "hi"}}}})
+
+	a := GetAction("x")
+	a.Name = "changed"
+	a.Options[0].Value = "changed"
+	if a := GetAction("x"); a.Name != "one" || a.Options[0].Value != "hi" {
+		t.Errorf("Expected GetAction to return a copy, got %+v", a)
+	}
+
+	// chains keep looking up actions while the API updates them
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				UpdateAction(Action{ID: "x", Bee: "nosuchbee", Name: "two"})
+				RemoveAction("y")
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				if a := GetAction("x"); a == nil {
+					t.Error("Expected action x to exist")
+				}
+				GetActions()
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func TestChainEnableDisable(t *testing.T) {
+	bee := newRecordingBee("mutebee")
+	defer DeleteBee(GetBee("mutebee"))
+
+	oldActions := GetActions()
+	defer SetActions(oldActions)
+	SetActions([]Action{
+		{ID: "mute-send", Bee: "mutebee", Name: "send"},
+		{ID: "mute-fail", Bee: "mutebee", Name: "fail"},
+	})
+	oldChains := chains
+	defer func() { chains = oldChains }()
+	ev := &Event{Bee: "mutebee", Name: "alert"}
+	SetChains([]Chain{
+		{Name: "alerting", Event: ev, Filters: []string{`{{test HasPrefix .text "alert"}}`}, Actions: []string{"mute-send"}},
+		{Name: "failing", Event: ev, Actions: []string{"mute-fail"}},
+	})
+	alert := &Event{Bee: "mutebee", Name: "alert", Options: Placeholders{{Name: "text", Type: "string", Value: "alert!"}}}
+	other := &Event{Bee: "mutebee", Name: "alert", Options: Placeholders{{Name: "text", Type: "string", Value: "fine"}}}
+
+	if !DisableChain("alerting") || DisableChain("nosuchchain") {
+		t.Fatal("Expected only existing chains to be disabled")
+	}
+	if GetChain("alerting").IsEnabled() {
+		t.Error("Expected alerting to be disabled")
+	}
+	execChains(context.Background(), alert)
+	execChains(context.Background(), other)
+	for _, a := range bee.executed() {
+		if a == "send" {
+			t.Error("Expected disabled chain not to execute")
+		}
+	}
+	if s := ChainStats("alerting"); s.WouldHaveTriggered != 1 || s.Triggered != 0 {
+		t.Errorf("Expected one would-be trigger, got %+v", s)
+	}
+
+	EnableChain("alerting")
+	execChains(context.Background(), alert)
+	execChains(context.Background(), other)
+	s := ChainStats("alerting")
+	if s.Triggered != 1 || s.ActionsExecuted != 1 || s.FilteredOut != 1 || s.LastTriggered.IsZero() {
+		t.Errorf("Unexpected stats %+v", s)
+	}
+	if s := ChainStats("failing"); s.Triggered != 4 || s.ActionErrors != 4 {
+		t.Errorf("Expected all executions of failing to error, got %+v", s)
+	}
+	if ChainStats("nosuchchain") != nil {
+		t.Error("Expected no stats for unknown chains")
+	}
+	if all := AllChainStats(); len(all) != 2 || all["failing"].ActionErrors != 4 {
+		t.Errorf("Expected the stats of both chains, got %v", all)
+	}
+}
+
+func TestChainWildcards(t *testing.T) {
+	ev := &Event{Bee: "twitterbee", Name: "tweet"}
+	tests := []struct {
+		chain Chain
+		match bool
+	}{
+		{Chain{Event: &Event{Bee: "twitterbee", Name: "tweet"}}, true},
+		{Chain{Event: &Event{Bee: "twitterbee", Name: "*"}}, true},
+		{Chain{Event: &Event{Bee: "*", Name: "tweet"}}, true},
+		{Chain{Event: &Event{Bee: "*", Name: "*"}}, true},
+		{Chain{Event: &Event{Bee: "ircbee", Name: "*"}}, false},
+		{Chain{Event: &Event{Bee: "*", Name: "message"}}, false},
+		{Chain{Events: []*Event{{Bee: "ircbee", Name: "message"}, {Bee: "twitterbee", Name: "tweet"}}}, true},
+		{Chain{Events: []*Event{{Bee: "ircbee", Name: "message"}}}, false},
+		{Chain{}, false},
+	}
+	for _, tt := range tests {
+		if _, ok := tt.chain.matches(ev); ok != tt.match {
+			t.Errorf("Expected %+v matching to be %v", tt.chain, tt.match)
+		}
+	}
+}
+
+func TestChainWildcardExecution(t *testing.T) {
+	bee := newRecordingBee("wildcardbee")
+	defer DeleteBee(GetBee("wildcardbee"))
+
+	oldActions := GetActions()
+	defer SetActions(oldActions)
+	SetActions([]Action{
+		{ID: "wild-any", Bee: "wildcardbee", Name: "any", Options: Placeholders{{Name: "text", Type: "string", Value: "{{.text}}|{{.missing}}|{{.user.name}}"}}},
+		{ID: "wild-exact", Bee: "wildcardbee", Name: "exact"},
+		{ID: "wild-multi", Bee: "wildcardbee", Name: "multi"},
+	})
+	oldChains := chains
+	defer func() { chains = oldChains }()
+	SetChains([]Chain{
+		{Name: "any", Event: &Event{Bee: "*", Name: "*"}, Actions: []string{"wild-any"}},
+		{Name: "exact", Event: &Event{Bee: "fsbee", Name: "changed"}, Actions: []string{"wild-exact"}},
+		{Name: "multi", Event: &Event{Bee: "fsbee", Name: "*"}, Events: []*Event{{Bee: "*", Name: "changed"}, {Bee: "fsbee", Name: "changed"}}, Actions: []string{"wild-multi"}},
+	})
+
+	matched := execChains(context.Background(), &Event{Bee: "fsbee", Name: "changed", Options: Placeholders{{Name: "text", Type: "string", Value: "hi"}}})
+	if strings.Join(matched, ",") != "exact,multi,any" {
+		t.Errorf("Expected exact matches to take precedence, got %v", matched)
+	}
+	if strings.Join(bee.executed(), ",") != "exact,multi,any" {
+		t.Errorf("Expected each chain to execute once, got %v", bee.executed())
+	}
+
+	bee.mutex.Lock()
+	defer bee.mutex.Unlock()
+	if v := bee.options[2].Value("text"); v != "hi||" {
+		t.Errorf("Expected missing placeholders to render empty, got %q", v)
+	}
+}
+
+func TestChainRateLimit(t *testing.T) {
+	fc := &fakeClock{now: time.Date(2026, 10, 14, 10, 0, 0, 0, time.Local)}
+	SetClock(fc)
+	defer SetClock(nil)
+
+	bee := newRecordingBee("flappingbee")
+	defer DeleteBee(GetBee("flappingbee"))
+
+	oldActions := GetActions()
+	defer SetActions(oldActions)
+	SetActions([]Action{
+		{ID: "flap-limited", Bee: "flappingbee", Name: "limited"},
+		{ID: "flap-dedup", Bee: "flappingbee", Name: "dedup"},
+	})
+	oldChains := chains
+	defer func() { chains = oldChains }()
+	ev := &Event{Bee: "flappingbee", Name: "changed"}
+	cs := []Chain{
+		{Name: "limited", Event: ev, Actions: []string{"flap-limited"}, MinInterval: time.Minute},
+		{Name: "dedup", Event: ev, Actions: []string{"flap-dedup"}, DedupKey: "{{.sensor}}", DedupWindow: time.Minute},
+	}
+	SetChains(cs)
+
+	burst := func(sensors ...string) {
+		for _, s := range sensors {
+			execChains(context.Background(), &Event{Bee: "flappingbee", Name: "changed", Options: Placeholders{{Name: "sensor", Type: "string", Value: s}}})
+			fc.now = fc.now.Add(time.Second)
+		}
+	}
+	count := func(name string) int {
+		n := 0
+		for _, a := range bee.executed() {
+			if a == name {
+				n++
+			}
+		}
+		return n
+	}
+
+	burst("a", "a", "a", "b", "a", "b")
+	if n := count("limited"); n != 1 {
+		t.Errorf("Expected rate limited chain to fire once, fired %d times", n)
+	}
+	if n := count("dedup"); n != 2 {
+		t.Errorf("Expected deduplicated chain to fire once per key, fired %d times", n)
+	}
+	if s := ChainStats("limited"); s.RateLimited != 5 {
+		t.Errorf("Expected 5 rate limited events, got %+v", s)
+	}
+	if s := ChainStats("dedup"); s.Deduplicated != 4 {
+		t.Errorf("Expected 4 deduplicated events, got %+v", s)
+	}
+
+	// reloading unchanged chains keeps their state
+	SetChains(cs)
+	burst("a")
+	if count("limited") != 1 || count("dedup") != 2 {
+		t.Error("Expected rate limits to survive reloading unchanged chains")
+	}
+
+	// changed chains start over
+	cs[0].MinInterval = 2 * time.Minute
+	SetChains(cs)
+	burst("a")
+	if count("limited") != 2 || count("dedup") != 2 {
+		t.Errorf("Expected only the changed chain to start over, got %v", bee.executed())
+	}
+
+	fc.now = fc.now.Add(time.Minute)
+	burst("a")
+	if count("dedup") != 3 {
+		t.Error("Expected keys to expire after the dedup window")
+	}
+}
+
+func TestChainHistory(t *testing.T) {
+	newRecordingBee("historybee")
+	defer DeleteBee(GetBee("historybee"))
+
+	oldActions := GetActions()
+	defer SetActions(oldActions)
+	SetActions([]Action{
+		{ID: "history-send", Bee: "historybee", Name: "send"},
+		{ID: "history-fail", Bee: "historybee", Name: "fail"},
+	})
+	SetChainHistorySize(3)
+	defer SetChainHistorySize(DefaultChainHistorySize)
+
+	ev := &Event{Bee: "historybee", Name: "trigger"}
+	ok := Chain{Name: "history-ok", Event: ev, Actions: []string{"history-send"}}
+	failing := Chain{Name: "history-failing", Event: ev, Actions: []string{"history-fail"}}
+	for i := 0; i < 5; i++ {
+		execChain(context.Background(), ok, ev, nil, false)
+	}
+	execChain(context.Background(), failing, ev, nil, false)
+
+	h := GetChainHistory("history-ok")
+	if len(h) != 3 {
+		t.Fatalf("Expected 3 executions in the history, got %d", len(h))
+	}
+	for _, exec := range h {
+		if exec.ChainName != "history-ok" || exec.Err != nil || exec.TriggerEvent.Name != "trigger" {
+			t.Errorf("Unexpected execution %+v", exec)
+		}
+	}
+	if h := GetChainHistory("history-failing"); len(h) != 1 || h[0].Err == nil || h[0].Error != h[0].Err.Error() {
+		t.Errorf("Expected the panicking execution to be recorded with its error, got %+v", h)
+	}
+	if _, found := GetChainExecution(h[2].ID); !found {
+		t.Error("Expected to find execution by its ID")
+	}
+
+	all := GetAllChainHistory()
+	if len(all["history-ok"]) != 3 || len(all["history-failing"]) != 1 {
+		t.Errorf("Unexpected history %+v", all)
+	}
+	if len(GetChainHistory("nosuchchain")) != 0 {
+		t.Error("Expected no history for unknown chains")
+	}
+}
+
+func TestReplayChain(t *testing.T) {
+	bee := newRecordingBee("replaychainbee")
+	defer DeleteBee(GetBee("replaychainbee"))
+
+	oldActions := GetActions()
+	defer SetActions(oldActions)
+	SetActions([]Action{
+		{ID: "replay-send", Bee: "replaychainbee", Name: "send"},
+		{ID: "replay-fail", Bee: "replaychainbee", Name: "fail"},
+	})
+	oldChains := GetChains()
+	defer SetChains(oldChains)
+
+	ev := &Event{Bee: "replaychainbee", Name: "trigger", Options: Placeholders{{Name: "text", Type: "string", Value: "hi"}}}
+	c := Chain{Name: "replay-chain", Event: ev, Actions: []string{"replay-fail"}}
+	SetChains([]Chain{c})
+	execChain(context.Background(), c, ev, nil, false)
+
+	h := GetChainHistory("replay-chain")
+	if len(h) != 1 || h[0].Err == nil {
+		t.Fatalf("Expected the failed execution to be recorded, got %+v", h)
+	}
+	if _, err := ReplayChain("nosuchexecution"); err == nil {
+		t.Error("Expected an error replaying an unknown execution")
+	}
+
+	// the fixed chain would hardly ever sample the event and only fire for
+	// full batches, neither of which applies to replays
+	c.Actions = []string{"replay-send"}
+	c.SampleRate = 0.000001
+	c.BatchSize = 10
+	SetChains([]Chain{c})
+
+	r, err := ReplayChain(h[0].ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.ChainName != "replay-chain" || r.ExecutionID == h[0].ID {
+		t.Errorf("Expected a new execution of the chain, got %+v", r)
+	}
+	if got := bee.executed(); len(got) != 2 || got[0] != "fail" || got[1] != "send" {
+		t.Errorf("Expected the replay to execute the fixed action right away, got %v", got)
+	}
+	if h := GetChainHistory("replay-chain"); len(h) != 2 || h[1].Err != nil || h[1].TriggerEvent.Options.Value("text") != "hi" {
+		t.Errorf("Expected the replay to be recorded with the original event, got %+v", h)
+	}
+
+	SetChains(nil)
+	if _, err := ReplayChain(h[0].ID); err == nil {
+		t.Error("Expected an error replaying a removed chain")
+	}
+}
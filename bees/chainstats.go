@@ -0,0 +1,179 @@
+/*
+ *    Copyright (C) 2026 Christian Muehlhaeuser
+ *
+ *    This program is free software: you can redistribute it and/or modify
+ *    it under the terms of the GNU Affero General Public License as published
+ *    by the Free Software Foundation, either version 3 of the License, or
+ *    (at your option) any later version.
+ *
+ *    This program is distributed in the hope that it will be useful,
+ *    but WITHOUT ANY WARRANTY; without even the implied warranty of
+ *    MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ *    GNU Affero General Public License for more details.
+ *
+ *    You should have received a copy of the GNU Affero General Public License
+ *    along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ *    Authors:
+ *      Christian Muehlhaeuser <muesli@gmail.com>
+ */
+
+// Package bees is Beehive's central module system.
+package bees
+
+import (
+	"sync"
+	"time"
+)
+
+// ChainStatistics contains runtime statistics of a chain.
+type ChainStatistics struct {
+	// Triggered counts how often the chain's actions got executed
+	Triggered int64
+	// ActionsExecuted counts the individual actions the chain executed
+	ActionsExecuted int64
+	// FilteredOut counts the matching events rejected by the chain's filters
+	FilteredOut int64
+	// WouldHaveTriggered counts the events passing the chain's filters while
+	// the chain was disabled
+	WouldHaveTriggered int64
+	// ActionErrors counts the executions that failed
+	ActionErrors int64
+	// RateLimited counts the events skipped due to the chain's MinInterval
+	RateLimited int64
+	// Deduplicated counts the events skipped due to the chain's DedupKey
+	Deduplicated int64
+	// Debounced counts the events superseded by a later event within the
+	// chain's Debounce period
+	Debounced     int64
+	LastTriggered time.Time
+
+	mutex sync.Mutex
+}
+
+var (
+	chainStats      = make(map[string]*ChainStatistics)
+	chainStatsMutex sync.Mutex
+)
+
+// statsOfChain returns the statistics of a chain, creating them if needed.
+func statsOfChain(name string) *ChainStatistics {
+	chainStatsMutex.Lock()
+	defer chainStatsMutex.Unlock()
+
+	s, ok := chainStats[name]
+	if !ok {
+		s = &ChainStatistics{}
+		chainStats[name] = s
+	}
+	return s
+}
+
+// deleteChainStats drops the statistics of a chain.
+func deleteChainStats(name string) {
+	chainStatsMutex.Lock()
+	defer chainStatsMutex.Unlock()
+
+	delete(chainStats, name)
+}
+
+// pruneChainStats drops the statistics of all chains not in cs.
+func pruneChainStats(cs []Chain) {
+	names := make(map[string]struct{})
+	for _, c := range cs {
+		names[c.Name] = struct{}{}
+	}
+
+	chainStatsMutex.Lock()
+	defer chainStatsMutex.Unlock()
+	for name := range chainStats {
+		if _, ok := names[name]; !ok {
+			delete(chainStats, name)
+		}
+	}
+}
+
+// ChainStats returns a snapshot of the statistics of the chain with a
+// specific name, or nil if no such chain exists.
+func ChainStats(name string) *ChainStatistics {
+	if GetChain(name) == nil {
+		return nil
+	}
+	return statsOfChain(name).snapshot()
+}
+
+// AllChainStats returns snapshots of the statistics of all chains, keyed by
+// name.
+func AllChainStats() map[string]*ChainStatistics {
+	r := make(map[string]*ChainStatistics)
+	for _, c := range GetChains() {
+		r[c.Name] = statsOfChain(c.Name).snapshot()
+	}
+
+	return r
+}
+
+func (s *ChainStatistics) triggered() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.Triggered++
+	s.LastTriggered = clock.Now()
+}
+
+func (s *ChainStatistics) actionExecuted() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.ActionsExecuted++
+}
+
+func (s *ChainStatistics) filteredOut() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.FilteredOut++
+}
+
+func (s *ChainStatistics) wouldHaveTriggered() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.WouldHaveTriggered++
+}
+
+func (s *ChainStatistics) actionError() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.ActionErrors++
+}
+
+func (s *ChainStatistics) rateLimited() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.RateLimited++
+}
+
+func (s *ChainStatistics) deduplicated() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.Deduplicated++
+}
+
+func (s *ChainStatistics) debounced() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.Debounced++
+}
+
+func (s *ChainStatistics) snapshot() *ChainStatistics {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return &ChainStatistics{
+		Triggered:          s.Triggered,
+		ActionsExecuted:    s.ActionsExecuted,
+		FilteredOut:        s.FilteredOut,
+		WouldHaveTriggered: s.WouldHaveTriggered,
+		ActionErrors:       s.ActionErrors,
+		RateLimited:        s.RateLimited,
+		Deduplicated:       s.Deduplicated,
+		Debounced:          s.Debounced,
+		LastTriggered:      s.LastTriggered,
+	}
+}
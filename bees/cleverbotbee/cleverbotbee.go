@@ -23,6 +23,8 @@
 package cleverbotbee
 
 import (
+	"context"
+
 	"github.com/CleverbotIO/go-cleverbot.io"
 	"github.com/muesli/beehive/bees"
 )
@@ -41,7 +43,7 @@ type CleverbotBee struct {
 }
 
 // Action triggers the action passed to it.
-func (mod *CleverbotBee) Action(action bees.Action) []bees.Placeholder {
+func (mod *CleverbotBee) Action(ctx context.Context, action bees.Action) []bees.Placeholder {
 	outs := []bees.Placeholder{}
 
 	switch action.Name {
@@ -76,7 +78,7 @@ func (mod *CleverbotBee) Action(action bees.Action) []bees.Placeholder {
 }
 
 // Run executes the Bee's event loop.
-func (mod *CleverbotBee) Run(eventChan chan bees.Event) {
+func (mod *CleverbotBee) Run(ctx context.Context, eventChan chan bees.Event) {
 	mod.evchan = eventChan
 
 	client, err := cleverbot.New(mod.api_user, mod.api_key, mod.session_nick)
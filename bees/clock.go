@@ -0,0 +1,70 @@
+/*
+ *    Copyright (C) 2026 Christian Muehlhaeuser
+ *
+ *    This program is free software: you can redistribute it and/or modify
+ *    it under the terms of the GNU Affero General Public License as published
+ *    by the Free Software Foundation, either version 3 of the License, or
+ *    (at your option) any later version.
+ *
+ *    This program is distributed in the hope that it will be useful,
+ *    but WITHOUT ANY WARRANTY; without even the implied warranty of
+ *    MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ *    GNU Affero General Public License for more details.
+ *
+ *    You should have received a copy of the GNU Affero General Public License
+ *    along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ *    Authors:
+ *      Christian Muehlhaeuser <muesli@gmail.com>
+ */
+
+// Package bees is Beehive's central module system.
+package bees
+
+import (
+	"sync"
+	"time"
+)
+
+// Clock provides the current time to the hive. It can be replaced to make
+// time-dependent behavior deterministic in tests.
+type Clock interface {
+	Now() time.Time
+}
+
+type systemClock struct{}
+
+// Now returns the current local time.
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+// hiveClock guards the clock set by SetClock, which may get replaced while
+// bees and chains are reading it.
+type hiveClock struct {
+	mutex sync.RWMutex
+	clock Clock
+}
+
+// Now returns the current time of the configured clock.
+func (hc *hiveClock) Now() time.Time {
+	hc.mutex.RLock()
+	c := hc.clock
+	hc.mutex.RUnlock()
+	return c.Now()
+}
+
+var (
+	clock = &hiveClock{clock: systemClock{}}
+)
+
+// SetClock sets the clock used by the hive. Passing nil restores the system
+// clock.
+func SetClock(c Clock) {
+	if c == nil {
+		c = systemClock{}
+	}
+	clock.mutex.Lock()
+	clock.clock = c
+	clock.mutex.Unlock()
+}
@@ -0,0 +1,186 @@
+/*
+ *    Copyright (C) 2026 Christian Muehlhaeuser
+ *
+ *    This program is free software: you can redistribute it and/or modify
+ *    it under the terms of the GNU Affero General Public License as published
+ *    by the Free Software Foundation, either version 3 of the License, or
+ *    (at your option) any later version.
+ *
+ *    This program is distributed in the hope that it will be useful,
+ *    but WITHOUT ANY WARRANTY; without even the implied warranty of
+ *    MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ *    GNU Affero General Public License for more details.
+ *
+ *    You should have received a copy of the GNU Affero General Public License
+ *    along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ *    Authors:
+ *      Christian Muehlhaeuser <muesli@gmail.com>
+ */
+
+// Package bees is Beehive's central module system.
+package bees
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// ConcurrencyInfo describes the amount of work currently going on in the hive.
+type ConcurrencyInfo struct {
+	// ChainWorkers is the number of goroutines executing chains
+	ChainWorkers int64
+	// ChainPoolSize is the maximum number of ChainWorkers, 0 if unlimited
+	ChainPoolSize int
+	// QueuedChains is the number of events waiting for a free chain worker
+	QueuedChains int
+	// ChainQueueDepth is the maximum number of QueuedChains, 0 if unlimited
+	ChainQueueDepth int
+	// DroppedChains is the number of events dropped due to a full chain
+	// queue, see SetChainQueue
+	DroppedChains int64
+	// SpilledChains is the number of events currently spilled to disk
+	SpilledChains int64
+	// InFlightActions is the number of actions currently being executed
+	InFlightActions int64
+	// ScheduledTimers is the number of pending timers, e.g. for batches or
+	// delayed actions
+	ScheduledTimers int64
+	// GlobalLimit is the maximum number of simultaneous actions, 0 if unlimited
+	GlobalLimit int
+}
+
+var (
+	chainWorkers    int64
+	inFlightActions int64
+	scheduledTimers int64
+
+	globalLimit     int
+	globalSemaphore chan struct{}
+	globalMutex     sync.RWMutex
+
+	chainSemaphore chan struct{}
+	dropOnLimit    bool
+	droppedEvents  int64
+	chainMutex     sync.RWMutex
+)
+
+// ConcurrencyStats returns information about the work currently going on in
+// the hive.
+func ConcurrencyStats() ConcurrencyInfo {
+	queued, size, depth := chainJobs.stats()
+
+	globalMutex.RLock()
+	defer globalMutex.RUnlock()
+
+	return ConcurrencyInfo{
+		ChainWorkers:    atomic.LoadInt64(&chainWorkers),
+		ChainPoolSize:   size,
+		QueuedChains:    queued,
+		ChainQueueDepth: depth,
+		DroppedChains:   atomic.LoadInt64(&chainJobs.dropped),
+		SpilledChains:   atomic.LoadInt64(&spilledEvents),
+		InFlightActions: atomic.LoadInt64(&inFlightActions),
+		ScheduledTimers: atomic.LoadInt64(&scheduledTimers),
+		GlobalLimit:     globalLimit,
+	}
+}
+
+// SetGlobalConcurrencyLimit limits the number of actions executed
+// simultaneously across the entire hive, regardless of which chain triggered
+// them. This protects shared resources from being overwhelmed when many
+// chains fire at once. A limit of 0 removes the limit.
+func SetGlobalConcurrencyLimit(n int) {
+	globalMutex.Lock()
+	defer globalMutex.Unlock()
+
+	globalLimit = n
+	if n > 0 {
+		globalSemaphore = make(chan struct{}, n)
+	} else {
+		globalSemaphore = nil
+	}
+}
+
+// SetMaxConcurrentChains limits the number of events whose chains get
+// executed simultaneously. Once the limit is reached, the event handler waits
+// for a running chain to finish before dispatching the next event, unless
+// SetDropOnChainLimit is enabled. A limit of 0 (the default) removes the
+// limit.
+//
+// Note that chains emitting events themselves, e.g. via streaming actions,
+// need a free slot for those events to get dispatched, so the limit shouldn't
+// be set too low.
+func SetMaxConcurrentChains(n int) {
+	chainMutex.Lock()
+	defer chainMutex.Unlock()
+
+	if n > 0 {
+		chainSemaphore = make(chan struct{}, n)
+	} else {
+		chainSemaphore = nil
+	}
+}
+
+// SetDropOnChainLimit decides whether events arriving while the limit set by
+// SetMaxConcurrentChains is reached get dropped instead of waiting for a free
+// slot. Dropped events are counted, see DroppedEvents.
+func SetDropOnChainLimit(drop bool) {
+	chainMutex.Lock()
+	defer chainMutex.Unlock()
+
+	dropOnLimit = drop
+}
+
+// DroppedEvents returns the number of events dropped because the limit set
+// by SetMaxConcurrentChains was reached.
+func DroppedEvents() int64 {
+	return atomic.LoadInt64(&droppedEvents)
+}
+
+// acquireChainSlot waits until an event's chains may be executed. It returns
+// false if the event should be dropped instead. Otherwise the returned
+// function must be called once the chains finished.
+func acquireChainSlot() (func(), bool) {
+	chainMutex.RLock()
+	sem := chainSemaphore
+	drop := dropOnLimit
+	chainMutex.RUnlock()
+
+	if sem == nil {
+		return func() {}, true
+	}
+
+	if drop {
+		select {
+		case sem <- struct{}{}:
+		default:
+			atomic.AddInt64(&droppedEvents, 1)
+			return nil, false
+		}
+	} else {
+		sem <- struct{}{}
+	}
+
+	return func() { <-sem }, true
+}
+
+// acquireActionSlot blocks until an action may be executed. The returned
+// function must be called once the action finished.
+func acquireActionSlot() func() {
+	globalMutex.RLock()
+	sem := globalSemaphore
+	globalMutex.RUnlock()
+
+	if sem != nil {
+		sem <- struct{}{}
+	}
+	atomic.AddInt64(&inFlightActions, 1)
+
+	return func() {
+		atomic.AddInt64(&inFlightActions, -1)
+		if sem != nil {
+			<-sem
+		}
+	}
+}
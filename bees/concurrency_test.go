@@ -0,0 +1,68 @@
+package bees
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestGlobalConcurrencyLimit(t *testing.T) {
+	SetGlobalConcurrencyLimit(2)
+	defer SetGlobalConcurrencyLimit(0)
+
+	var running, peak int64
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			release := acquireActionSlot()
+			defer release()
+
+			n := atomic.AddInt64(&running, 1)
+			for {
+				p := atomic.LoadInt64(&peak)
+				if n <= p || atomic.CompareAndSwapInt64(&peak, p, n) {
+					break
+				}
+			}
+			time.Sleep(5 * time.Millisecond)
+			atomic.AddInt64(&running, -1)
+		}()
+	}
+	wg.Wait()
+
+	if peak > 2 {
+		t.Errorf("Expected at most 2 simultaneous actions, got %d", peak)
+	}
+	if s := ConcurrencyStats(); s.InFlightActions != 0 || s.GlobalLimit != 2 {
+		t.Errorf("Unexpected concurrency stats: %+v", s)
+	}
+}
+
+func TestMaxConcurrentChains(t *testing.T) {
+	SetMaxConcurrentChains(1)
+	SetDropOnChainLimit(true)
+	defer SetMaxConcurrentChains(0)
+	defer SetDropOnChainLimit(false)
+
+	release, ok := acquireChainSlot()
+	if !ok {
+		t.Fatal("Expected to acquire a free slot")
+	}
+
+	dropped := DroppedEvents()
+	if _, ok := acquireChainSlot(); ok {
+		t.Error("Expected event to be dropped while the limit is reached")
+	}
+	if DroppedEvents() != dropped+1 {
+		t.Error("Expected dropped event to be counted")
+	}
+
+	release()
+	if release, ok = acquireChainSlot(); !ok {
+		t.Error("Expected to acquire a released slot")
+	}
+	release()
+}
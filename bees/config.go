@@ -21,7 +21,11 @@
 // Package bees is Beehive's central module system.
 package bees
 
-import "errors"
+import (
+	"errors"
+	"sync"
+	"time"
+)
 
 // BeeConfig contains all settings for a single Bee.
 type BeeConfig struct {
@@ -29,6 +33,65 @@ type BeeConfig struct {
 	Class       string
 	Description string
 	Options     BeeOptions
+
+	// Critical bees stop the entire hive when they fail permanently, see
+	// OnCriticalFailure.
+	Critical bool `json:",omitempty"`
+
+	// Scope restricts the visibility of the bee's events to chains of the
+	// same scope, e.g. a tenant. Empty means global, see Chain.Scope. Scoped
+	// bees are identified as "scope/name", see QualifiedBeeName.
+	Scope string `json:",omitempty"`
+
+	// MaxRetries is how often the bee gets restarted after crashing, before
+	// it's marked as BeeCrashed. Zero uses DefaultMaxRetries, a negative
+	// value never restarts the bee.
+	MaxRetries int `json:",omitempty"`
+
+	// ActionTimeout is the time after which the bee's actions get abandoned,
+	// unless they specify a Timeout of their own. Zero uses the default set
+	// by SetDefaultActionTimeout, a negative value never abandons actions.
+	ActionTimeout time.Duration `json:",omitempty"`
+
+	// Paused bees keep running, but their events get dropped and actions on
+	// them skipped, see PauseBee.
+	Paused bool `json:",omitempty"`
+}
+
+var (
+	instances     = make(map[string]BeeConfig)
+	instanceMutex sync.RWMutex
+)
+
+// setInstanceConfig remembers the config a bee instance was created with.
+func setInstanceConfig(c BeeConfig) {
+	instanceMutex.Lock()
+	defer instanceMutex.Unlock()
+
+	instances[c.Name] = c
+}
+
+// instanceConfig returns the config a bee instance was created with.
+func instanceConfig(name string) (BeeConfig, bool) {
+	instanceMutex.RLock()
+	defer instanceMutex.RUnlock()
+
+	c, ok := instances[name]
+	return c, ok
+}
+
+// beeScope returns the scope of a bee instance.
+func beeScope(name string) string {
+	c, _ := instanceConfig(name)
+	return c.Scope
+}
+
+// deleteInstanceConfig forgets the config of a deleted bee instance.
+func deleteInstanceConfig(name string) {
+	instanceMutex.Lock()
+	defer instanceMutex.Unlock()
+
+	delete(instances, name)
 }
 
 // NewBeeConfig validates a configuration and sets up a new BeeConfig
@@ -47,6 +110,14 @@ func NewBeeConfig(name, class, description string, options BeeOptions) (BeeConfi
 		return BeeConfig{}, errors.New("Invalid class specified")
 	}
 
+	resolved, err := ResolveOptions(options)
+	if err != nil {
+		return BeeConfig{}, err
+	}
+	if errs := ValidateOptions(class, resolved); len(errs) > 0 {
+		return BeeConfig{}, validationError(errs)
+	}
+
 	return BeeConfig{
 		Name:        name,
 		Class:       class,
@@ -58,8 +129,25 @@ func NewBeeConfig(name, class, description string, options BeeOptions) (BeeConfi
 // BeeConfigs returns configs for all Bees.
 func BeeConfigs() []BeeConfig {
 	bs := []BeeConfig{}
-	for _, b := range bees {
-		bs = append(bs, (*b).Config())
+	referenceMutex.RLock()
+	defer referenceMutex.RUnlock()
+
+	for _, b := range GetBees() {
+		c := (*b).Config()
+		if raw, ok := rawOptions[c.Name]; ok {
+			c.Options = raw
+		}
+		if options, err := EncryptOptions(c.Class, c.Options); err != nil {
+			logger.Errorf("Can't encrypt the options of bee %v: %v", c.Name, err)
+		} else {
+			c.Options = options
+		}
+		if ic, ok := instanceConfig(c.Name); ok {
+			c.Critical = ic.Critical
+			c.Paused = ic.Paused
+			c.Scope = ic.Scope
+		}
+		bs = append(bs, c)
 	}
 
 	return bs
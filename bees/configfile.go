@@ -0,0 +1,203 @@
+/*
+ *    Copyright (C) 2026 Christian Muehlhaeuser
+ *
+ *    This program is free software: you can redistribute it and/or modify
+ *    it under the terms of the GNU Affero General Public License as published
+ *    by the Free Software Foundation, either version 3 of the License, or
+ *    (at your option) any later version.
+ *
+ *    This program is distributed in the hope that it will be useful,
+ *    but WITHOUT ANY WARRANTY; without even the implied warranty of
+ *    MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ *    GNU Affero General Public License for more details.
+ *
+ *    You should have received a copy of the GNU Affero General Public License
+ *    along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ *    Authors:
+ *      Christian Muehlhaeuser <muesli@gmail.com>
+ */
+
+// Package bees is Beehive's central module system.
+package bees
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"sync"
+)
+
+// ConfigVersion is the schema version of configurations written by
+// SaveConfig. Files without a version are treated as version 0.
+const ConfigVersion = 2
+
+// A ConfigMigration upgrades a decoded configuration document from one
+// schema version to the next.
+type ConfigMigration func(doc map[string]interface{}) error
+
+var (
+	configMigrations = map[int]ConfigMigration{
+		// version 1 only introduced the Version field itself
+		0: func(doc map[string]interface{}) error { return nil },
+		1: qualifyScopedBees,
+	}
+	configMigrationsMutex sync.RWMutex
+)
+
+// RegisterConfigMigration registers the migration upgrading configurations of
+// schema version from to version from+1. It replaces any migration
+// previously registered for that version.
+func RegisterConfigMigration(from int, m ConfigMigration) {
+	configMigrationsMutex.Lock()
+	defer configMigrationsMutex.Unlock()
+
+	configMigrations[from] = m
+}
+
+// SaveConfig writes the current configuration of the hive, see ExportConfig,
+// to a JSON file, in the same format as beehive's configuration files. The
+// file gets replaced atomically.
+func SaveConfig(path string) error {
+	c, err := ExportConfig(false)
+	if err != nil {
+		return err
+	}
+
+	b, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return writeFileAtomically(path, b)
+}
+
+// LoadConfig reads a configuration written by SaveConfig, migrates it to the
+// current schema version and replaces the hive's configuration with it, see
+// ImportConfig.
+func LoadConfig(path string) error {
+	c, err := readConfig(path)
+	if err != nil {
+		return err
+	}
+
+	return ImportConfig(c)
+}
+
+// readConfig reads and migrates a configuration file.
+func readConfig(path string) (Config, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return Config{}, err
+	}
+
+	var doc map[string]interface{}
+	if err := json.Unmarshal(b, &doc); err != nil {
+		return Config{}, err
+	}
+	if doc == nil {
+		doc = make(map[string]interface{})
+	}
+
+	if err := migrateConfig(doc); err != nil {
+		return Config{}, err
+	}
+
+	// round-trip through JSON to decode the migrated document
+	b, err = json.Marshal(doc)
+	if err != nil {
+		return Config{}, err
+	}
+	var c Config
+	err = json.Unmarshal(b, &c)
+	return c, err
+}
+
+// migrateConfig upgrades a decoded configuration to ConfigVersion by running
+// the registered migrations in order.
+func migrateConfig(doc map[string]interface{}) error {
+	version := 0
+	if v, ok := doc["Version"]; ok {
+		f, ok := v.(float64)
+		if !ok {
+			return fmt.Errorf("Invalid configuration version %v", v)
+		}
+		version = int(f)
+	}
+	if version > ConfigVersion {
+		return fmt.Errorf("Configuration version %d is newer than the supported version %d", version, ConfigVersion)
+	}
+
+	configMigrationsMutex.RLock()
+	defer configMigrationsMutex.RUnlock()
+
+	for ; version < ConfigVersion; version++ {
+		m, ok := configMigrations[version]
+		if !ok {
+			return fmt.Errorf("No migration for configuration version %d", version)
+		}
+		if err := m(doc); err != nil {
+			return fmt.Errorf("Migrating configuration from version %d: %v", version, err)
+		}
+		doc["Version"] = float64(version + 1)
+	}
+
+	return nil
+}
+
+// qualifyScopedBees migrates configurations of version 1, which identified
+// bees by their bare names, to version 2: scoped bees get identified by
+// their qualified names, see QualifiedBeeName, and so do the references to
+// them in actions and chains. Version 1 names were unique across scopes, so
+// all references can be rewritten unambiguously.
+func qualifyScopedBees(doc map[string]interface{}) error {
+	renamed := make(map[string]string)
+	bs, _ := doc["Bees"].([]interface{})
+	for _, b := range bs {
+		bee, ok := b.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		name, _ := bee["Name"].(string)
+		scope, _ := bee["Scope"].(string)
+		if q := qualifyBee(BeeConfig{Name: name, Scope: scope}); q.Name != name {
+			bee["Name"] = q.Name
+			renamed[name] = q.Name
+		}
+	}
+	if len(renamed) == 0 {
+		return nil
+	}
+
+	rename := func(v interface{}) {
+		m, ok := v.(map[string]interface{})
+		if !ok {
+			return
+		}
+		if name, ok := m["Bee"].(string); ok && len(renamed[name]) > 0 {
+			m["Bee"] = renamed[name]
+		}
+	}
+	renameAll := func(v interface{}) {
+		l, _ := v.([]interface{})
+		for _, e := range l {
+			rename(e)
+		}
+	}
+
+	renameAll(doc["Actions"])
+	cs, _ := doc["Chains"].([]interface{})
+	for _, c := range cs {
+		chain, ok := c.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		rename(chain["Event"])
+		renameAll(chain["Events"])
+		if corr, ok := chain["Correlation"].(map[string]interface{}); ok {
+			renameAll(corr["Events"])
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,117 @@
+package bees
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSaveLoadConfig(t *testing.T) {
+	oldActions := GetActions()
+	defer SetActions(oldActions)
+	oldChains := chains
+	defer func() { chains = oldChains }()
+	defer StopBees()
+
+	dir, err := ioutil.TempDir("", "beehive-config")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	errs := StartBees([]BeeConfig{
+		{Name: "saved-irc", Class: "typedbee", Options: BeeOptions{
+			{Name: "server", Value: "irc://irc.example.com"},
+			{Name: "port", Value: 6667},
+		}},
+	})
+	if len(errs) > 0 {
+		t.Fatal(errs)
+	}
+	SetActions([]Action{{ID: "saved-action", Bee: "saved-irc", Name: "send"}})
+	SetChains([]Chain{{
+		Name:    "saved-chain",
+		Event:   &Event{Bee: "saved-irc", Name: "message"},
+		Actions: []string{"saved-action"},
+	}})
+
+	path := filepath.Join(dir, "beehive.conf")
+	if err := SaveConfig(path); err != nil {
+		t.Fatal(err)
+	}
+	c, err := readConfig(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.Version != ConfigVersion {
+		t.Errorf("Expected config version %d, got %d", ConfigVersion, c.Version)
+	}
+
+	SetChains(nil)
+	if err := LoadConfig(path); err != nil {
+		t.Fatal(err)
+	}
+	if GetBee("saved-irc") == nil {
+		t.Error("Expected bee to be loaded")
+	}
+	if GetChain("saved-chain") == nil {
+		t.Error("Expected chain to be loaded")
+	}
+}
+
+func TestMigrateConfig(t *testing.T) {
+	old := configMigrations[0]
+	defer RegisterConfigMigration(0, old)
+
+	RegisterConfigMigration(0, func(doc map[string]interface{}) error {
+		doc["Chains"] = doc["Rules"]
+		delete(doc, "Rules")
+		return nil
+	})
+
+	doc := map[string]interface{}{"Rules": []interface{}{}}
+	if err := migrateConfig(doc); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := doc["Chains"]; !ok || doc["Version"] != float64(ConfigVersion) {
+		t.Errorf("Expected migrated document of version %d, got %v", ConfigVersion, doc)
+	}
+
+	if err := migrateConfig(map[string]interface{}{"Version": float64(ConfigVersion + 1)}); err == nil {
+		t.Error("Expected an error for a configuration from a newer version")
+	}
+}
+
+func TestMigrateScopedBees(t *testing.T) {
+	doc := map[string]interface{}{
+		"Version": float64(1),
+		"Bees": []interface{}{
+			map[string]interface{}{"Name": "irc", "Scope": "tenant-a"},
+			map[string]interface{}{"Name": "web"},
+		},
+		"Actions": []interface{}{
+			map[string]interface{}{"ID": "post", "Bee": "irc"},
+			map[string]interface{}{"ID": "get", "Bee": "web"},
+		},
+		"Chains": []interface{}{
+			map[string]interface{}{"Event": map[string]interface{}{"Bee": "irc"}},
+		},
+	}
+	if err := migrateConfig(doc); err != nil {
+		t.Fatal(err)
+	}
+
+	bees := doc["Bees"].([]interface{})
+	if bees[0].(map[string]interface{})["Name"] != "tenant-a/irc" || bees[1].(map[string]interface{})["Name"] != "web" {
+		t.Errorf("Expected only the scoped bee to be renamed, got %v", bees)
+	}
+	as := doc["Actions"].([]interface{})
+	if as[0].(map[string]interface{})["Bee"] != "tenant-a/irc" || as[1].(map[string]interface{})["Bee"] != "web" {
+		t.Errorf("Expected action references to be migrated, got %v", as)
+	}
+	ev := doc["Chains"].([]interface{})[0].(map[string]interface{})["Event"].(map[string]interface{})
+	if ev["Bee"] != "tenant-a/irc" {
+		t.Errorf("Expected chain references to be migrated, got %v", ev)
+	}
+}
@@ -21,41 +21,126 @@
 // Package bees is Beehive's central module system.
 package bees
 
+import "sync"
+
 var (
 	ctx = NewContext()
 )
 
 type Context struct {
-	state map[*Bee]map[string]interface{}
+	mutex sync.RWMutex
+	state map[string]map[string]interface{}
 }
 
 func NewContext() *Context {
 	return &Context{
-		state: make(map[*Bee]map[string]interface{}),
+		state: make(map[string]map[string]interface{}),
 	}
 }
 
 func (c *Context) Set(bee *Bee, key string, value interface{}) {
-	if _, ok := c.state[bee]; !ok {
-		c.state[bee] = make(map[string]interface{})
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if _, ok := c.state[bee.Name()]; !ok {
+		c.state[bee.Name()] = make(map[string]interface{})
 	}
-	c.state[bee][key] = value
+	c.state[bee.Name()][key] = value
 }
 
 func (c *Context) Value(bee *Bee, key string) interface{} {
-	return c.state[bee][key]
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	return c.state[bee.Name()][key]
+}
+
+func (c *Context) Delete(bee *Bee, key string) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	delete(c.state[bee.Name()], key)
 }
 
 func (c *Context) FillMap(m map[string]interface{}) {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
 	cd := make(map[string]interface{})
 	for bee, d := range c.state {
-		cd[bee.Name()] = d
+		cd[bee] = d
 	}
 	m["context"] = cd
 }
 
+// snapshot returns a copy of the values stored by all bees.
+func (c *Context) snapshot() map[string]map[string]interface{} {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	state := make(map[string]map[string]interface{}, len(c.state))
+	for bee, d := range c.state {
+		values := make(map[string]interface{}, len(d))
+		for k, v := range d {
+			values[k] = v
+		}
+		state[bee] = values
+	}
+	return state
+}
+
+// restore replaces the values stored by all bees.
+func (c *Context) restore(state map[string]map[string]interface{}) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.state = state
+}
+
+// ContextSet stores a value in the bee's context, where it is accessible to
+// chains. If a StateStore is configured, the value also gets persisted when
+// the bee stops, and can be retrieved with ContextGet after a restart.
 func (bee *Bee) ContextSet(key string, value interface{}) {
 	ctx.Set(bee, key, value)
+	bee.storage.set(bee.Name(), key, value)
+}
+
+// ContextGet decodes the value stored for key with ContextSet into out,
+// which must be a pointer. Persisted values get loaded on first access.
+func (bee *Bee) ContextGet(key string, out interface{}) error {
+	return bee.storage.get(bee.Name(), key, out)
+}
+
+// SaveState stores a JSON-encodable value for key and persists it in the
+// StateStore right away, unlike ContextSet. The value isn't published to
+// chains.
+func (bee *Bee) SaveState(key string, value interface{}) error {
+	return bee.storage.save(bee.Name(), key, value)
+}
+
+// LoadState decodes the value stored for key into out, which must be a
+// pointer. It returns false if no value is stored for key.
+func (bee *Bee) LoadState(key string, out interface{}) (bool, error) {
+	err := bee.storage.get(bee.Name(), key, out)
+	if err == ErrNoSuchKey {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// DeleteState removes the value stored for key, with either SaveState or
+// ContextSet, and persists the change right away. It fails with
+// ErrStateNotDeletable if the StateStore can't delete values, see
+// EnumerableStateStore.
+func (bee *Bee) DeleteState(key string) error {
+	ctx.Delete(bee, key)
+	return bee.storage.remove(bee.Name(), key)
+}
+
+// StateKeys returns the sorted keys the bee stored values for. Persisted
+// keys are only included if the StateStore is an EnumerableStateStore.
+func (bee *Bee) StateKeys() []string {
+	return bee.storage.keys(bee.Name())
 }
 
 func (bee *Bee) ContextValue(key string) interface{} {
@@ -0,0 +1,202 @@
+/*
+ *    Copyright (C) 2026 Christian Muehlhaeuser
+ *
+ *    This program is free software: you can redistribute it and/or modify
+ *    it under the terms of the GNU Affero General Public License as published
+ *    by the Free Software Foundation, either version 3 of the License, or
+ *    (at your option) any later version.
+ *
+ *    This program is distributed in the hope that it will be useful,
+ *    but WITHOUT ANY WARRANTY; without even the implied warranty of
+ *    MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ *    GNU Affero General Public License for more details.
+ *
+ *    You should have received a copy of the GNU Affero General Public License
+ *    along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ *    Authors:
+ *      Christian Muehlhaeuser <muesli@gmail.com>
+ */
+
+// Package bees is Beehive's central module system.
+package bees
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+	"time"
+)
+
+// Correlation makes a chain fire only once a sequence of events occurred
+// within a time window, e.g. a "down" event of a monitoring bee followed by
+// a "deployed" event of a CI bee within 10 minutes. Events only correlate if
+// they share the values of the Key placeholders, e.g. "host".
+type Correlation struct {
+	// Events is the sequence of events, in the order they have to occur
+	Events []*Event
+	// Window is the time the sequence has to complete in, measured from its
+	// first event
+	Window time.Duration
+	// Key lists the placeholders whose values the events have to share
+	Key []string `json:",omitempty"`
+}
+
+// correlationState tracks a partially completed sequence of events.
+type correlationState struct {
+	step    int
+	started time.Time
+	events  []map[string]interface{}
+}
+
+// chainCorrelation holds the partially completed sequences of a chain,
+// keyed by the values of the correlation's Key placeholders.
+type chainCorrelation struct {
+	correlation Correlation
+	states      map[string]*correlationState
+}
+
+var (
+	correlations     = make(map[string]*chainCorrelation)
+	correlationMutex sync.Mutex
+)
+
+// correlated returns whether the chain waits for a sequence of events.
+func (c *Chain) correlated() bool {
+	return c.Correlation != nil && len(c.Correlation.Events) > 0
+}
+
+// correlationKey returns the values of the placeholders events need to
+// share to correlate.
+func correlationKey(event *Event, placeholders []string) string {
+	values := make([]interface{}, len(placeholders))
+	for i, name := range placeholders {
+		values[i] = event.Options.Value(name)
+	}
+	return fmt.Sprintf("%v", values)
+}
+
+// correlateEvent advances the chain's sequences with event. It returns true
+// once event completed a sequence, adding the placeholders of all events of
+// the sequence to m as "correlated". Sequences exceeding the chain's window
+// get discarded.
+func correlateEvent(c Chain, event *Event, m map[string]interface{}) bool {
+	correlationMutex.Lock()
+	defer correlationMutex.Unlock()
+
+	now := clock.Now()
+	cc, ok := correlations[c.Name]
+	if !ok || !reflect.DeepEqual(cc.correlation, *c.Correlation) {
+		cc = &chainCorrelation{correlation: *c.Correlation, states: make(map[string]*correlationState)}
+		correlations[c.Name] = cc
+	}
+	states := cc.states
+	for k, s := range states {
+		if now.Sub(s.started) > c.Correlation.Window {
+			delete(states, k)
+		}
+	}
+
+	seq := c.Correlation.Events
+	key := correlationKey(event, c.Correlation.Key)
+	s := states[key]
+	switch {
+	case s != nil && triggerMatches(seq[s.step], event):
+		s.step++
+		s.events = append(s.events, correlatedEvent(event))
+	case triggerMatches(seq[0], event):
+		s = &correlationState{started: now, step: 1, events: []map[string]interface{}{correlatedEvent(event)}}
+		states[key] = s
+	default:
+		return false
+	}
+
+	if s.step < len(seq) {
+		return false
+	}
+	delete(states, key)
+	m["correlated"] = s.events
+	return true
+}
+
+// correlatedEvent returns the placeholders of an event as a map, together
+// with the names of its bee and the event.
+func correlatedEvent(event *Event) map[string]interface{} {
+	m := placeholderMap(event.Options)
+	m["bee"] = event.Bee
+	m["event"] = event.Name
+	return m
+}
+
+// resetCorrelations drops the partially completed sequences of all chains
+// but the unchanged ones in cs.
+func resetCorrelations(cs []Chain) {
+	correlationMutex.Lock()
+	defer correlationMutex.Unlock()
+
+	current := make(map[string]*chainCorrelation)
+	for _, c := range cs {
+		if cc, ok := correlations[c.Name]; ok && c.correlated() && reflect.DeepEqual(cc.correlation, *c.Correlation) {
+			current[c.Name] = cc
+		}
+	}
+	correlations = current
+}
+
+// deleteCorrelation drops the partially completed sequences of a chain.
+func deleteCorrelation(name string) {
+	correlationMutex.Lock()
+	defer correlationMutex.Unlock()
+
+	delete(correlations, name)
+}
+
+// correlationSnapshot is the serialized form of a partially completed
+// sequence of events, see PrepareHandoff.
+type correlationSnapshot struct {
+	Step    int
+	Started time.Time
+	Events  []map[string]interface{}
+}
+
+// snapshotCorrelations returns the partially completed sequences of all
+// chains, keyed by chain name and correlation key.
+func snapshotCorrelations() map[string]map[string]correlationSnapshot {
+	correlationMutex.Lock()
+	defer correlationMutex.Unlock()
+
+	snap := make(map[string]map[string]correlationSnapshot)
+	for name, cc := range correlations {
+		if len(cc.states) == 0 {
+			continue
+		}
+		states := make(map[string]correlationSnapshot, len(cc.states))
+		for k, s := range cc.states {
+			states[k] = correlationSnapshot{Step: s.step, Started: s.started, Events: s.events}
+		}
+		snap[name] = states
+	}
+	return snap
+}
+
+// restoreCorrelations restores the sequences returned by
+// snapshotCorrelations, for all chains that still wait for one.
+func restoreCorrelations(snap map[string]map[string]correlationSnapshot) {
+	correlationMutex.Lock()
+	defer correlationMutex.Unlock()
+
+	for name, states := range snap {
+		c := GetChain(name)
+		if c == nil || !c.correlated() {
+			continue
+		}
+		cc := &chainCorrelation{correlation: *c.Correlation, states: make(map[string]*correlationState)}
+		for k, s := range states {
+			if s.Step <= 0 || s.Step >= len(c.Correlation.Events) {
+				continue
+			}
+			cc.states[k] = &correlationState{step: s.Step, started: s.Started, events: s.Events}
+		}
+		correlations[name] = cc
+	}
+}
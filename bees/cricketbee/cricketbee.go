@@ -21,6 +21,8 @@ package cricketbee
  */
 
 import (
+	"context"
+
 	"github.com/akashshinde/go_cricket"
 	"github.com/muesli/beehive/bees"
 )
@@ -80,7 +82,7 @@ func (c *CricketBee) announceCricketEvent(response gocricket.ResponseEvent) {
 	}
 }
 
-func (c *CricketBee) Run(cin chan bees.Event) {
+func (c *CricketBee) Run(ctx context.Context, cin chan bees.Event) {
 	evt := make(chan gocricket.ResponseEvent)
 	// Start Cricket GoRoutine to poll cricket score
 	cricket := gocricket.NewCricketWatcher(c.favTeam, evt)
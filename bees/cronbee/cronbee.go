@@ -24,6 +24,7 @@
 package cronbee
 
 import (
+	"context"
 	"strings"
 	"time"
 
@@ -55,7 +56,7 @@ func (mod *CronBee) runTask() {
 }
 
 // Run executes the Bee's event loop.
-func (mod *CronBee) Run(eventChan chan bees.Event) {
+func (mod *CronBee) Run(ctx context.Context, eventChan chan bees.Event) {
 	mod.eventChan = eventChan
 	c := cron.New(cron.WithSeconds())
 	mod.LogDebugf("Scheduling " + strings.Join(mod.input[:], " "))
@@ -0,0 +1,161 @@
+/*
+ *    Copyright (C) 2026 Christian Muehlhaeuser
+ *
+ *    This program is free software: you can redistribute it and/or modify
+ *    it under the terms of the GNU Affero General Public License as published
+ *    by the Free Software Foundation, either version 3 of the License, or
+ *    (at your option) any later version.
+ *
+ *    This program is distributed in the hope that it will be useful,
+ *    but WITHOUT ANY WARRANTY; without even the implied warranty of
+ *    MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ *    GNU Affero General Public License for more details.
+ *
+ *    You should have received a copy of the GNU Affero General Public License
+ *    along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ *    Authors:
+ *      Christian Muehlhaeuser <muesli@gmail.com>
+ */
+// Package bees is Beehive's central module system.
+package bees
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+)
+
+// A traceStep is an action executed on a bee, as part of a trace.
+type traceStep struct {
+	Bee    string
+	Action string
+}
+
+func (s traceStep) String() string {
+	return s.Bee + "/" + s.Action
+}
+
+// An actionTrace lists the actions which led to an event, oldest first.
+// Events caused by an action inherit the trace of the action, so a chain
+// executing an action which is already part of the trace would close a
+// feedback loop.
+type actionTrace []traceStep
+
+// contains returns whether a step is part of the trace.
+func (t actionTrace) contains(s traceStep) bool {
+	for _, step := range t {
+		if step == s {
+			return true
+		}
+	}
+	return false
+}
+
+// with returns a copy of the trace, extended by a step.
+func (t actionTrace) with(s traceStep) actionTrace {
+	return append(append(actionTrace{}, t...), s)
+}
+
+// String returns the path of the trace, e.g. "bee1/send -> bee2/post".
+func (t actionTrace) String() string {
+	steps := make([]string, len(t))
+	for i, step := range t {
+		steps[i] = step.String()
+	}
+	return strings.Join(steps, " -> ")
+}
+
+type traceKey struct{}
+
+// withTrace returns a context remembering the trace of the actions executed
+// with it.
+func withTrace(ctx context.Context, t actionTrace) context.Context {
+	return context.WithValue(ctx, traceKey{}, t)
+}
+
+// traceOf returns the trace remembered by ctx.
+func traceOf(ctx context.Context) actionTrace {
+	t, _ := ctx.Value(traceKey{}).(actionTrace)
+	return t
+}
+
+var (
+	// activeTraces holds the traces of the actions currently executed by
+	// each bee
+	activeTraces      = make(map[string][]*actionTrace)
+	activeTracesMutex sync.Mutex
+)
+
+// beginTrace marks an action of a trace as being executed by a bee. Events
+// the bee emits meanwhile inherit the trace. The returned function must be
+// called once the action finished.
+func beginTrace(bee string, t actionTrace) func() {
+	active := &t
+
+	activeTracesMutex.Lock()
+	activeTraces[bee] = append(activeTraces[bee], active)
+	activeTracesMutex.Unlock()
+
+	return func() {
+		activeTracesMutex.Lock()
+		defer activeTracesMutex.Unlock()
+
+		ts := activeTraces[bee]
+		for i := range ts {
+			if ts[i] == active {
+				ts = append(ts[:i:i], ts[i+1:]...)
+				break
+			}
+		}
+		if len(ts) == 0 {
+			delete(activeTraces, bee)
+		} else {
+			activeTraces[bee] = ts
+		}
+	}
+}
+
+// activeTrace returns the trace of the action most recently started by a bee
+// and still being executed.
+func activeTrace(bee string) actionTrace {
+	activeTracesMutex.Lock()
+	defer activeTracesMutex.Unlock()
+
+	ts := activeTraces[bee]
+	if len(ts) == 0 {
+		return nil
+	}
+	return *ts[len(ts)-1]
+}
+
+// checkCycle returns an error if executing an action on a bee would close a
+// feedback loop, i.e. the action is already part of ctx's trace. In that case
+// the cycle gets logged and a CycleDetectedEvent gets emitted.
+func checkCycle(ctx context.Context, chain string, bee, action string, cause *Event) error {
+	step := traceStep{Bee: bee, Action: action}
+	trace := traceOf(ctx)
+	if !trace.contains(step) {
+		return nil
+	}
+
+	path := trace.with(step).String()
+	logger.Errorf("Chain %v aborted, it would close a feedback loop: %v", chain, path)
+
+	ev := deriveEvent(cause, Event{
+		Bee:  SystemBee,
+		Name: CycleDetectedEvent,
+		Options: Placeholders{
+			{Name: "chain", Type: "string", Value: chain},
+			{Name: "bee", Type: "string", Value: bee},
+			{Name: "action", Type: "string", Value: action},
+			{Name: "path", Type: "string", Value: path},
+		},
+	})
+	// chains handling the cycle must not be considered part of it
+	ev.trace = nil
+	go injectEvent(ev)
+
+	return errors.New("Feedback loop detected: " + path)
+}
@@ -0,0 +1,69 @@
+package bees
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// echoBee emits an event named after each action it executes.
+type echoBee struct {
+	recordingBee
+}
+
+func (mod *echoBee) Action(ctx context.Context, action Action) []Placeholder {
+	mod.recordingBee.Action(ctx, action)
+	EmitEvent(mod, action.Name)
+	return nil
+}
+
+func TestCycleDetection(t *testing.T) {
+	events := make(chan Event, 16)
+	defer useEventChannel(events)()
+
+	for _, name := range []string{"echoa", "echob"} {
+		var bee BeeInterface = &echoBee{recordingBee: recordingBee{Bee: NewBee(name, "recordingbee", "", nil)}}
+		if err := RegisterBee(bee); err != nil {
+			t.Fatal(err)
+		}
+		bee.Start()
+		defer DeleteBee(&bee)
+	}
+
+	oldActions := GetActions()
+	defer SetActions(oldActions)
+	SetActions([]Action{
+		{ID: "to-b", Bee: "echob", Name: "pong"},
+		{ID: "to-a", Bee: "echoa", Name: "ping"},
+	})
+	oldChains := chains
+	defer func() { chains = oldChains }()
+	SetChains([]Chain{
+		{Name: "ping-pong", Event: &Event{Bee: "echoa", Name: "ping"}, Actions: []string{"to-b"}},
+		{Name: "pong-ping", Event: &Event{Bee: "echob", Name: "pong"}, Actions: []string{"to-a"}},
+	})
+
+	events <- Event{Bee: "echoa", Name: "ping"}
+	timeout := time.After(2 * time.Second)
+	for handled := 0; ; handled++ {
+		if handled > 10 {
+			t.Fatal("Expected feedback loop to be broken")
+		}
+
+		select {
+		case ev := <-events:
+			if ev.Bee == SystemBee && ev.Name == CycleDetectedEvent {
+				if p := ev.Options.Value("path"); p != "echob/pong -> echoa/ping -> echob/pong" {
+					t.Errorf("Unexpected cycle path %v", p)
+				}
+				if c := ev.Options.Value("chain"); c != "ping-pong" {
+					t.Errorf("Expected chain ping-pong to be aborted, got %v", c)
+				}
+				return
+			}
+			handleEvent(context.Background(), ev)
+		case <-timeout:
+			t.Fatal("Timed out waiting for the cycle to be detected")
+		}
+	}
+}
@@ -0,0 +1,218 @@
+/*
+ *    Copyright (C) 2026 Christian Muehlhaeuser
+ *
+ *    This program is free software: you can redistribute it and/or modify
+ *    it under the terms of the GNU Affero General Public License as published
+ *    by the Free Software Foundation, either version 3 of the License, or
+ *    (at your option) any later version.
+ *
+ *    This program is distributed in the hope that it will be useful,
+ *    but WITHOUT ANY WARRANTY; without even the implied warranty of
+ *    MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ *    GNU Affero General Public License for more details.
+ *
+ *    You should have received a copy of the GNU Affero General Public License
+ *    along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ *    Authors:
+ *      Christian Muehlhaeuser <muesli@gmail.com>
+ */
+
+// Package bees is Beehive's central module system.
+package bees
+
+import (
+	"sync"
+	"time"
+)
+
+// DefaultDeadLetterQueueSize is the number of entries the default dead-letter
+// queue keeps.
+const DefaultDeadLetterQueueSize = 100
+
+// DeadLetterEntry describes an event whose handling failed, or an action
+// which still failed after all its retries, see Action.Retries.
+type DeadLetterEntry struct {
+	ID    string
+	Event Event
+	Err   error `json:"-"`
+	Time  time.Time
+
+	// Action is the failed action, with its options as they got executed,
+	// and Chain the chain it belongs to. Event caused the chain to fire.
+	// Attempts counts the action's executions. Unset for events
+	Action   *Action `json:",omitempty"`
+	Chain    string  `json:",omitempty"`
+	Attempts int     `json:",omitempty"`
+
+	retry *actionRetry
+}
+
+// DeadLetterQueue keeps the most recent events whose handling failed, so they
+// can be inspected and retried.
+type DeadLetterQueue struct {
+	mutex   sync.Mutex
+	size    int
+	entries []DeadLetterEntry
+}
+
+var (
+	deadLetters      = NewDeadLetterQueue(DefaultDeadLetterQueueSize)
+	deadLettersMutex sync.RWMutex
+)
+
+// NewDeadLetterQueue returns a DeadLetterQueue keeping up to size entries.
+func NewDeadLetterQueue(size int) *DeadLetterQueue {
+	if size < 1 {
+		size = 1
+	}
+
+	return &DeadLetterQueue{size: size}
+}
+
+// SetDeadLetterQueue sets the queue failed events and actions get stored in.
+// By default, it keeps DefaultDeadLetterQueueSize entries. Passing nil
+// disables the dead-letter queue.
+func SetDeadLetterQueue(q *DeadLetterQueue) {
+	deadLettersMutex.Lock()
+	defer deadLettersMutex.Unlock()
+	deadLetters = q
+}
+
+// GetDeadLetterQueue returns the dead-letter queue, or nil if it's disabled.
+func GetDeadLetterQueue() *DeadLetterQueue {
+	deadLettersMutex.RLock()
+	defer deadLettersMutex.RUnlock()
+	return deadLetters
+}
+
+// deadLetter stores an event whose handling failed in the dead-letter queue,
+// if one is configured.
+func deadLetter(event Event, err error) {
+	if q := GetDeadLetterQueue(); q != nil {
+		q.add(DeadLetterEntry{ID: UUID(), Event: event, Err: err, Time: clock.Now()})
+	}
+}
+
+// deadLetterAction stores an action which ran out of retries in the
+// dead-letter queue, if one is configured.
+func deadLetterAction(r *actionRetry) {
+	q := GetDeadLetterQueue()
+	if q == nil {
+		return
+	}
+
+	a := r.action
+	func() {
+		// keep the unresolved action if its templates are the problem
+		defer func() { recover() }()
+		resolved := resolveAction(r.action, r.opts)
+		a.Options = resolved.Options
+	}()
+	q.add(DeadLetterEntry{
+		ID:       UUID(),
+		Event:    r.cause,
+		Err:      r.lastErr,
+		Time:     clock.Now(),
+		Action:   &a,
+		Chain:    r.chain,
+		Attempts: r.attempts,
+		retry:    r,
+	})
+}
+
+func (q *DeadLetterQueue) add(entry DeadLetterEntry) {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	q.entries = append(q.entries, entry)
+	if len(q.entries) > q.size {
+		q.entries = q.entries[len(q.entries)-q.size:]
+	}
+}
+
+// Entries returns the buffered failures, oldest first.
+func (q *DeadLetterQueue) Entries() []DeadLetterEntry {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	return append([]DeadLetterEntry{}, q.entries...)
+}
+
+// Entry returns the entry with the given ID.
+func (q *DeadLetterQueue) Entry(id string) (DeadLetterEntry, bool) {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for _, entry := range q.entries {
+		if entry.ID == id {
+			return entry, true
+		}
+	}
+	return DeadLetterEntry{}, false
+}
+
+// Remove discards the entry with the given ID. Returns false if there is no
+// such entry.
+func (q *DeadLetterQueue) Remove(id string) bool {
+	_, ok := q.take(id)
+	return ok
+}
+
+// take removes the entry with the given ID from the queue and returns it.
+func (q *DeadLetterQueue) take(id string) (DeadLetterEntry, bool) {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for i, entry := range q.entries {
+		if entry.ID == id {
+			q.entries = append(q.entries[:i], q.entries[i+1:]...)
+			return entry, true
+		}
+	}
+	return DeadLetterEntry{}, false
+}
+
+// Redrive removes the entry with the given ID from the queue and tries again:
+// events get fed back into the hive, like Replay does, and actions get
+// executed once more in the background, with their full number of retries.
+// Returns false if there is no such entry.
+func (q *DeadLetterQueue) Redrive(id string) bool {
+	entry, ok := q.take(id)
+	if ok {
+		redrive(entry)
+	}
+	return ok
+}
+
+// redrive handles a failed event or action once more.
+func redrive(entry DeadLetterEntry) {
+	if entry.retry != nil {
+		redriveAction(entry.retry)
+		return
+	}
+	injectEvent(replayOf(entry.Event))
+}
+
+// Replay removes the last n entries from the queue and handles them again,
+// as described for Redrive. Events get marked as Replayed and end up in the
+// queue once more if they fail again. Returns the number of replayed
+// entries.
+func (q *DeadLetterQueue) Replay(n int) int {
+	q.mutex.Lock()
+	if n > len(q.entries) {
+		n = len(q.entries)
+	}
+	if n < 0 {
+		n = 0
+	}
+	replay := append([]DeadLetterEntry{}, q.entries[len(q.entries)-n:]...)
+	q.entries = q.entries[:len(q.entries)-n]
+	q.mutex.Unlock()
+
+	for _, entry := range replay {
+		redrive(entry)
+	}
+
+	return n
+}
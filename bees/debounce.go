@@ -0,0 +1,108 @@
+/*
+ *    Copyright (C) 2026 Christian Muehlhaeuser
+ *
+ *    This program is free software: you can redistribute it and/or modify
+ *    it under the terms of the GNU Affero General Public License as published
+ *    by the Free Software Foundation, either version 3 of the License, or
+ *    (at your option) any later version.
+ *
+ *    This program is distributed in the hope that it will be useful,
+ *    but WITHOUT ANY WARRANTY; without even the implied warranty of
+ *    MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ *    GNU Affero General Public License for more details.
+ *
+ *    You should have received a copy of the GNU Affero General Public License
+ *    along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ *    Authors:
+ *      Christian Muehlhaeuser <muesli@gmail.com>
+ */
+
+// Package bees is Beehive's central module system.
+package bees
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// pendingDebounce holds the latest event of a debounced chain, waiting for
+// the chain's Debounce period to pass without further events.
+type pendingDebounce struct {
+	chain Chain
+	event Event
+	count int
+	timer *time.Timer
+}
+
+var (
+	debounces     = make(map[string]*pendingDebounce)
+	debounceMutex sync.Mutex
+)
+
+// debounced returns whether the chain collapses bursts of events.
+func (c *Chain) debounced() bool {
+	return c.Debounce > 0
+}
+
+// debounceEvent makes event the pending event of the chain, restarting the
+// chain's Debounce period. Events replaced by a newer one get counted as
+// debounced.
+func debounceEvent(c Chain, event Event) {
+	debounceMutex.Lock()
+	defer debounceMutex.Unlock()
+
+	d, ok := debounces[c.Name]
+	if ok && d.timer.Stop() {
+		statsOfChain(c.Name).debounced()
+		d.chain = c
+		d.event = event
+		d.count++
+		d.timer.Reset(c.Debounce)
+		return
+	}
+
+	d = &pendingDebounce{chain: c, event: event, count: 1}
+	debounces[c.Name] = d
+	atomic.AddInt64(&scheduledTimers, 1)
+	d.timer = time.AfterFunc(c.Debounce, func() {
+		fireDebounce(context.Background(), d)
+	})
+}
+
+// fireDebounce dispatches the pending event of a debounced chain, unless it
+// has already been dispatched.
+func fireDebounce(ctx context.Context, d *pendingDebounce) {
+	debounceMutex.Lock()
+	if debounces[d.chain.Name] != d {
+		debounceMutex.Unlock()
+		return
+	}
+	delete(debounces, d.chain.Name)
+	d.timer.Stop()
+	atomic.AddInt64(&scheduledTimers, -1)
+	c, event, count := d.chain, d.event, d.count
+	debounceMutex.Unlock()
+
+	logger.Debugf("Firing debounced chain %v for the last of %v events", c.Name, count)
+	m := eventMap(&event)
+	m["debounced"] = count
+	dispatchChain(ctx, c, &event, m)
+}
+
+// FlushDebounces immediately dispatches the pending events of all debounced
+// chains.
+func FlushDebounces() {
+	debounceMutex.Lock()
+	ds := []*pendingDebounce{}
+	for _, d := range debounces {
+		ds = append(ds, d)
+	}
+	debounceMutex.Unlock()
+
+	for _, d := range ds {
+		fireDebounce(context.Background(), d)
+	}
+}
@@ -0,0 +1,177 @@
+/*
+ *    Copyright (C) 2026 Christian Muehlhaeuser
+ *
+ *    This program is free software: you can redistribute it and/or modify
+ *    it under the terms of the GNU Affero General Public License as published
+ *    by the Free Software Foundation, either version 3 of the License, or
+ *    (at your option) any later version.
+ *
+ *    This program is distributed in the hope that it will be useful,
+ *    but WITHOUT ANY WARRANTY; without even the implied warranty of
+ *    MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ *    GNU Affero General Public License for more details.
+ *
+ *    You should have received a copy of the GNU Affero General Public License
+ *    along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ *    Authors:
+ *      Christian Muehlhaeuser <muesli@gmail.com>
+ */
+
+// Package bees is Beehive's central module system.
+package bees
+
+import (
+	"container/list"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// DefaultDedupMaxEntries is the default number of distinct events remembered
+// for deduplication.
+const DefaultDedupMaxEntries = 1000
+
+// beeDedup configures deduplicating the events of a single bee.
+type beeDedup struct {
+	window       time.Duration
+	placeholders []string
+}
+
+// dedupEntry is an event remembered for deduplication.
+type dedupEntry struct {
+	key  string
+	seen time.Time
+}
+
+var (
+	dedupWindow     time.Duration
+	dedupMaxEntries = DefaultDedupMaxEntries
+	dedupEntries    = list.New()
+	dedupIndex      = make(map[string]*list.Element)
+	beeDedups       = make(map[string]beeDedup)
+	dedupMutex      sync.Mutex
+
+	duplicatesDropped int64
+)
+
+// SetDedupWindow enables dropping events identical to one received within the
+// last d, i.e. events of the same bee with the same name and options. This
+// suppresses rapid-fire duplicates some bees emit. A window of 0 (the
+// default) disables deduplication.
+func SetDedupWindow(d time.Duration) {
+	dedupMutex.Lock()
+	defer dedupMutex.Unlock()
+
+	dedupWindow = d
+	if d <= 0 {
+		dedupEntries.Init()
+		dedupIndex = make(map[string]*list.Element)
+	}
+}
+
+// SetBeeDedup enables deduplicating the events of a single bee, overriding
+// the window set by SetDedupWindow: events of the bee get dropped when an
+// event with the same name was received within the last window. With
+// placeholders, only their values are compared, instead of all of the
+// event's options, e.g. to ignore timestamps differing between otherwise
+// identical events. A window of 0 disables deduplicating the bee's events.
+func SetBeeDedup(beeName string, window time.Duration, placeholders ...string) {
+	dedupMutex.Lock()
+	defer dedupMutex.Unlock()
+
+	beeDedups[beeName] = beeDedup{
+		window:       window,
+		placeholders: append([]string{}, placeholders...),
+	}
+}
+
+// RemoveBeeDedup removes the deduplication settings of a bee, see
+// SetBeeDedup. Its events are deduplicated as set by SetDedupWindow again.
+func RemoveBeeDedup(beeName string) {
+	dedupMutex.Lock()
+	defer dedupMutex.Unlock()
+
+	delete(beeDedups, beeName)
+}
+
+// SetDedupMaxEntries limits how many distinct events are remembered for
+// deduplication. Once the limit is reached, the least recently seen events
+// get forgotten.
+func SetDedupMaxEntries(n int) {
+	if n < 1 {
+		n = 1
+	}
+
+	dedupMutex.Lock()
+	defer dedupMutex.Unlock()
+
+	dedupMaxEntries = n
+	evictDedupEntries()
+}
+
+// DuplicatesDropped returns the number of events dropped as duplicates.
+func DuplicatesDropped() int64 {
+	return atomic.LoadInt64(&duplicatesDropped)
+}
+
+// isDuplicate returns whether an identical event has been seen within the
+// dedup window, and remembers the event otherwise.
+func isDuplicate(event *Event) bool {
+	dedupMutex.Lock()
+	defer dedupMutex.Unlock()
+
+	window := dedupWindow
+	var placeholders []string
+	if d, ok := beeDedups[event.Bee]; ok {
+		window = d.window
+		placeholders = d.placeholders
+	}
+	if window <= 0 {
+		return false
+	}
+
+	key := dedupKey(event, placeholders)
+	now := clock.Now()
+	if el, ok := dedupIndex[key]; ok {
+		e := el.Value.(*dedupEntry)
+		if now.Sub(e.seen) < window {
+			dedupEntries.MoveToFront(el)
+			atomic.AddInt64(&duplicatesDropped, 1)
+			return true
+		}
+
+		e.seen = now
+		dedupEntries.MoveToFront(el)
+		return false
+	}
+
+	dedupIndex[key] = dedupEntries.PushFront(&dedupEntry{key: key, seen: now})
+	evictDedupEntries()
+	return false
+}
+
+// dedupKey identifies an event for deduplication by its bee, name and either
+// all of its options or just the values of placeholders.
+func dedupKey(event *Event, placeholders []string) string {
+	if len(placeholders) == 0 {
+		return fmt.Sprintf("%s\x00%s\x00%v", event.Bee, event.Name, event.Options)
+	}
+
+	values := make([]interface{}, len(placeholders))
+	for i, name := range placeholders {
+		values[i] = event.Options.Value(name)
+	}
+	return fmt.Sprintf("%s\x00%s\x00%v", event.Bee, event.Name, values)
+}
+
+// evictDedupEntries forgets the least recently seen events exceeding the
+// limit. Must be called with dedupMutex held.
+func evictDedupEntries() {
+	for dedupEntries.Len() > dedupMaxEntries {
+		el := dedupEntries.Back()
+		dedupEntries.Remove(el)
+		delete(dedupIndex, el.Value.(*dedupEntry).key)
+	}
+}
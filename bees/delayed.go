@@ -0,0 +1,213 @@
+/*
+ *    Copyright (C) 2026 Christian Muehlhaeuser
+ *
+ *    This program is free software: you can redistribute it and/or modify
+ *    it under the terms of the GNU Affero General Public License as published
+ *    by the Free Software Foundation, either version 3 of the License, or
+ *    (at your option) any later version.
+ *
+ *    This program is distributed in the hope that it will be useful,
+ *    but WITHOUT ANY WARRANTY; without even the implied warranty of
+ *    MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ *    GNU Affero General Public License for more details.
+ *
+ *    You should have received a copy of the GNU Affero General Public License
+ *    along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ *    Authors:
+ *      Christian Muehlhaeuser <muesli@gmail.com>
+ */
+
+// Package bees is Beehive's central module system.
+package bees
+
+import (
+	"context"
+	"runtime/debug"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// delayedAction is an action of a chain waiting for its delay to pass.
+type delayedAction struct {
+	action Action
+	opts   map[string]interface{}
+	cause  Event
+	due    time.Time
+	timer  *time.Timer
+
+	// scope and trace of the chain that scheduled the action
+	scope string
+	trace actionTrace
+}
+
+var (
+	delayedActions      = make(map[*delayedAction]struct{})
+	delayedActionsMutex sync.Mutex
+)
+
+// scheduleAction executes an action once its delay has passed. The action
+// uses a snapshot of opts, taken when it gets scheduled, and runs in the
+// chain's scope and trace carried by ctx.
+func scheduleAction(ctx context.Context, action Action, opts map[string]interface{}, cause *Event) {
+	snapshot := make(map[string]interface{}, len(opts))
+	for k, v := range opts {
+		snapshot[k] = v
+	}
+	d := &delayedAction{
+		action: action,
+		opts:   snapshot,
+		cause:  *cause,
+		due:    time.Now().Add(action.Delay),
+		scope:  chainScopeOf(ctx),
+		trace:  traceOf(ctx),
+	}
+	d.schedule()
+}
+
+// schedule starts the timer executing a delayed action once it's due.
+func (d *delayedAction) schedule() {
+	delayedActionsMutex.Lock()
+	defer delayedActionsMutex.Unlock()
+
+	delayedActions[d] = struct{}{}
+	atomic.AddInt64(&scheduledTimers, 1)
+	d.timer = time.AfterFunc(time.Until(d.due), func() {
+		fireDelayedAction(d)
+	})
+}
+
+// unscheduleAction removes a delayed action, returning false if it already
+// fired or got cancelled.
+func unscheduleAction(d *delayedAction) bool {
+	delayedActionsMutex.Lock()
+	defer delayedActionsMutex.Unlock()
+
+	if _, ok := delayedActions[d]; !ok {
+		return false
+	}
+	delete(delayedActions, d)
+	d.timer.Stop()
+	atomic.AddInt64(&scheduledTimers, -1)
+
+	return true
+}
+
+// fireDelayedAction executes a delayed action, unless its bee is gone.
+func fireDelayedAction(d *delayedAction) {
+	if !unscheduleAction(d) {
+		return
+	}
+	if GetBee(d.action.Bee) == nil {
+		logger.Debugf("Dropping delayed action of removed bee: %v / %v", d.action.Bee, d.action.Name)
+		return
+	}
+
+	defer func() {
+		if e := recover(); e != nil {
+			logger.Errorf("Fatal delayed action event: %s %s", e, debug.Stack())
+		}
+	}()
+	ctx := withTrace(withChainScope(context.Background(), d.scope), d.trace)
+	execAction(ctx, d.action, d.opts, &d.cause)
+}
+
+// cancelDelayedActions cancels the pending actions waiting to be cancelled by
+// event.
+func cancelDelayedActions(event *Event) {
+	delayedActionsMutex.Lock()
+	ds := []*delayedAction{}
+	for d := range delayedActions {
+		c := d.action.CancelOn
+		if c != nil && c.Bee == event.Bee && c.Name == event.Name {
+			ds = append(ds, d)
+		}
+	}
+	delayedActionsMutex.Unlock()
+
+	for _, d := range ds {
+		if unscheduleAction(d) {
+			logger.Debugf("Cancelled delayed action %v / %v due to event %v / %v", d.action.Bee, d.action.Name, event.Bee, event.Name)
+		}
+	}
+}
+
+// CancelDelayedActions cancels all pending delayed actions.
+func CancelDelayedActions() {
+	delayedActionsMutex.Lock()
+	ds := []*delayedAction{}
+	for d := range delayedActions {
+		ds = append(ds, d)
+	}
+	delayedActionsMutex.Unlock()
+
+	for _, d := range ds {
+		unscheduleAction(d)
+	}
+}
+
+// delayedActionState is the serialized form of a delayed action, see
+// PrepareHandoff.
+type delayedActionState struct {
+	Action Action
+	Opts   map[string]interface{} `json:",omitempty"`
+	Cause  Event
+	Due    time.Time
+	Scope  string      `json:",omitempty"`
+	Trace  actionTrace `json:",omitempty"`
+}
+
+// takeDelayedActions cancels all pending delayed actions and returns them, so
+// they can be rescheduled with restoreDelayedActions.
+func takeDelayedActions() []delayedActionState {
+	delayedActionsMutex.Lock()
+	ds := []*delayedAction{}
+	for d := range delayedActions {
+		ds = append(ds, d)
+	}
+	delayedActionsMutex.Unlock()
+
+	states := []delayedActionState{}
+	for _, d := range ds {
+		if !unscheduleAction(d) {
+			// it fired meanwhile
+			continue
+		}
+		states = append(states, delayedActionState{
+			Action: d.action,
+			Opts:   d.opts,
+			Cause:  d.cause,
+			Due:    d.due,
+			Scope:  d.scope,
+			Trace:  d.trace,
+		})
+	}
+	return states
+}
+
+// restoreDelayedActions schedules the delayed actions returned by
+// takeDelayedActions. Actions that became due meanwhile get executed right
+// away.
+func restoreDelayedActions(states []delayedActionState) {
+	for _, s := range states {
+		d := &delayedAction{
+			action: s.Action,
+			opts:   s.Opts,
+			cause:  s.Cause,
+			due:    s.Due,
+			scope:  s.Scope,
+			trace:  s.Trace,
+		}
+		d.schedule()
+	}
+}
+
+// PendingActions returns the number of delayed actions waiting to be
+// executed.
+func PendingActions() int {
+	delayedActionsMutex.Lock()
+	defer delayedActionsMutex.Unlock()
+
+	return len(delayedActions)
+}
@@ -0,0 +1,197 @@
+/*
+ *    Copyright (C) 2026 Christian Muehlhaeuser
+ *
+ *    This program is free software: you can redistribute it and/or modify
+ *    it under the terms of the GNU Affero General Public License as published
+ *    by the Free Software Foundation, either version 3 of the License, or
+ *    (at your option) any later version.
+ *
+ *    This program is distributed in the hope that it will be useful,
+ *    but WITHOUT ANY WARRANTY; without even the implied warranty of
+ *    MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ *    GNU Affero General Public License for more details.
+ *
+ *    You should have received a copy of the GNU Affero General Public License
+ *    along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ *    Authors:
+ *      Christian Muehlhaeuser <muesli@gmail.com>
+ */
+
+// Package bees is Beehive's central module system.
+package bees
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// DefaultDependencyTimeout is the time startBees waits for a bee to be
+// running before giving up on starting its dependents.
+const DefaultDependencyTimeout = 30 * time.Second
+
+// pendingBee is a bee that has been set up by startBees, but not started yet.
+type pendingBee struct {
+	config  BeeConfig
+	bee     *BeeInterface
+	deps    []*pendingBee
+	started bool
+}
+
+// startBees starts all bees in beeList, skipping the broken ones. Bees get
+// started after the bees they depend on, see BeeInterface.Dependencies, once
+// those are running. Dependencies on bees not in beeList need to be
+// registered already. Bees with unknown or cyclic dependencies get skipped,
+// as do the dependents of bees which failed to start.
+func startBees(beeList []BeeConfig) []error {
+	beeList, errs := uniqueBees(beeList)
+	skip := func(name string, err error) {
+		logger.Errorf("Skipping bee %v: %v", name, err)
+		errs = append(errs, fmt.Errorf("Bee %s: %v", name, err))
+
+		referenceMutex.Lock()
+		delete(rawOptions, name)
+		referenceMutex.Unlock()
+	}
+
+	var pending []*pendingBee
+	for _, c := range beeList {
+		if registry.Bee(c.Name) != nil {
+			skip(c.Name, ErrDuplicateBee)
+			continue
+		}
+		mod, err := newBeeInstance(c)
+		if err != nil {
+			skip(c.Name, err)
+			continue
+		}
+		pending = append(pending, &pendingBee{config: c, bee: mod})
+	}
+
+	pending = resolveDependencies(pending, skip)
+	ordered, cyclic := sortByDependencies(pending)
+	if len(cyclic) > 0 {
+		names := []string{}
+		for _, p := range cyclic {
+			names = append(names, p.config.Name)
+		}
+		err := errors.New("Cyclic dependencies between bees: " + strings.Join(names, ", "))
+		logger.Errorf("%v", err)
+		errs = append(errs, err)
+		for _, p := range cyclic {
+			skip(p.config.Name, errors.New("Cyclic dependency"))
+		}
+	}
+
+	for _, p := range ordered {
+		if dep := unstartedDependency(p); dep != "" {
+			skip(p.config.Name, errors.New("Dependency "+dep+" failed to start"))
+			continue
+		}
+		if err := RegisterBee(*p.bee); err != nil {
+			skip(p.config.Name, err)
+			continue
+		}
+		setInstanceConfig(p.config)
+		launchBee(p.bee)
+		p.started = true
+	}
+
+	return errs
+}
+
+// resolveDependencies looks up the bees each bee in pending depends on. A
+// dependency either names a bee or a namespace, in which case the bee depends
+// on all bees of that namespace. Dependencies on registered bees are always
+// satisfied. Bees with unknown dependencies get skipped and aren't returned.
+func resolveDependencies(pending []*pendingBee, skip func(name string, err error)) []*pendingBee {
+	resolved := []*pendingBee{}
+	for _, p := range pending {
+		var unknown []string
+		for _, d := range (*p.bee).Dependencies() {
+			var deps []*pendingBee
+			for _, other := range pending {
+				if other != p && other.config.Name == d {
+					deps = append(deps, other)
+				}
+			}
+			if len(deps) == 0 {
+				for _, other := range pending {
+					if other != p && other.config.Class == d {
+						deps = append(deps, other)
+					}
+				}
+			}
+			if len(deps) == 0 && GetBee(d) == nil && len(GetBeesByNamespace(d)) == 0 {
+				unknown = append(unknown, d)
+			}
+			p.deps = append(p.deps, deps...)
+		}
+
+		if len(unknown) > 0 {
+			skip(p.config.Name, errors.New("Unknown dependencies: "+strings.Join(unknown, ", ")))
+			continue
+		}
+		resolved = append(resolved, p)
+	}
+
+	return resolved
+}
+
+// sortByDependencies sorts pending topologically, so every bee comes after
+// the bees it depends on. Otherwise the configured order is kept. Bees which
+// are part of a dependency cycle, or depend on one, are returned separately.
+func sortByDependencies(pending []*pendingBee) (ordered, cyclic []*pendingBee) {
+	known := make(map[*pendingBee]bool)
+	for _, p := range pending {
+		known[p] = true
+	}
+	done := make(map[*pendingBee]bool)
+
+	for progress := true; progress; {
+		progress = false
+		for _, p := range pending {
+			if done[p] {
+				continue
+			}
+			ready := true
+			for _, d := range p.deps {
+				if known[d] && !done[d] {
+					ready = false
+					break
+				}
+			}
+			if ready {
+				ordered = append(ordered, p)
+				done[p] = true
+				progress = true
+			}
+		}
+	}
+
+	for _, p := range pending {
+		if !done[p] {
+			cyclic = append(cyclic, p)
+		}
+	}
+	sort.SliceStable(cyclic, func(i, j int) bool {
+		return cyclic[i].config.Name < cyclic[j].config.Name
+	})
+
+	return ordered, cyclic
+}
+
+// unstartedDependency waits for the bees p depends on to be running. It
+// returns the name of the first one which didn't start, or "".
+func unstartedDependency(p *pendingBee) string {
+	for _, d := range p.deps {
+		if !d.started || WaitForRunning(d.bee, DefaultDependencyTimeout) != nil {
+			return d.config.Name
+		}
+	}
+
+	return ""
+}
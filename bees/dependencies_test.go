@@ -0,0 +1,98 @@
+package bees
+
+import (
+	"strings"
+	"sync"
+	"testing"
+)
+
+var (
+	startOrder      []string
+	startOrderMutex sync.Mutex
+)
+
+// dependentBee depends on the bees listed in its "after" option and records
+// the order in which bees get started.
+type dependentBee struct {
+	recordingBee
+}
+
+func (mod *dependentBee) Dependencies() []string {
+	var after string
+	mod.Options().Bind("after", &after)
+	if after == "" {
+		return nil
+	}
+	return strings.Split(after, ",")
+}
+
+func (mod *dependentBee) Start() {
+	startOrderMutex.Lock()
+	startOrder = append(startOrder, mod.Name())
+	startOrderMutex.Unlock()
+
+	mod.recordingBee.Start()
+}
+
+func init() {
+	RegisterFactory(&testFactory{
+		id: "dependentbee",
+		newBee: func(name, description string, options BeeOptions) BeeInterface {
+			return &dependentBee{recordingBee{Bee: NewBee(name, "dependentbee", description, options)}}
+		},
+	})
+}
+
+func dependentBeeConfig(name, after string) BeeConfig {
+	return BeeConfig{Name: name, Class: "dependentbee", Options: BeeOptions{{Name: "after", Value: after}}}
+}
+
+func TestStartBeesDependencyOrder(t *testing.T) {
+	startOrderMutex.Lock()
+	startOrder = nil
+	startOrderMutex.Unlock()
+
+	errs := startBees([]BeeConfig{
+		dependentBeeConfig("query", "db,cache"),
+		dependentBeeConfig("db", ""),
+		dependentBeeConfig("report", "dependentbee"),
+		dependentBeeConfig("cache", "db"),
+		dependentBeeConfig("orphan", "nosuchbee"),
+	})
+	for _, name := range []string{"query", "db", "report", "cache"} {
+		if bee := GetBee(name); bee != nil {
+			defer DeleteBee(bee)
+			WaitForRunning(bee, DefaultDependencyTimeout)
+		}
+	}
+
+	if len(errs) != 2 || !strings.Contains(errs[0].Error(), "orphan") {
+		t.Errorf("Expected errors for orphan and report, got %v", errs)
+	}
+	if len(errs) == 2 && !strings.Contains(errs[1].Error(), "report") {
+		t.Errorf("Expected report to depend on the failed orphan, got %v", errs[1])
+	}
+
+	startOrderMutex.Lock()
+	defer startOrderMutex.Unlock()
+	if strings.Join(startOrder, ",") != "db,cache,query" {
+		t.Errorf("Unexpected start order %v", startOrder)
+	}
+}
+
+func TestStartBeesOrderedCycle(t *testing.T) {
+	defer useEventChannel(make(chan Event))()
+	defer StopBees()
+
+	err := StartBeesOrdered([]BeeConfig{
+		dependentBeeConfig("chicken", "egg"),
+		dependentBeeConfig("egg", "chicken"),
+		dependentBeeConfig("hen", ""),
+	})
+	if err == nil || !strings.Contains(err.Error(), "Cyclic dependencies between bees: chicken, egg") {
+		t.Errorf("Expected a cyclic dependency error, got %v", err)
+	}
+	if GetBee("chicken") != nil || GetBee("egg") != nil || GetBee("hen") == nil {
+		t.Error("Expected only hen to be started")
+	}
+}
@@ -21,6 +21,10 @@
 // Package bees is Beehive's central module system.
 package bees
 
+import (
+	"sync"
+)
+
 // EventDescriptor describes an Event provided by a Bee.
 type EventDescriptor struct {
 	Namespace   string
@@ -35,6 +39,10 @@ type ActionDescriptor struct {
 	Name        string
 	Description string
 	Options     []PlaceholderDescriptor
+
+	// Cacheable marks actions without side-effects, whose results may be
+	// cached and re-used for identical options.
+	Cacheable bool `json:",omitempty"`
 }
 
 // A PlaceholderDescriptor shows which in & out values a module expects and returns.
@@ -52,6 +60,10 @@ type BeeOptionDescriptor struct {
 	Type        string
 	Default     interface{}
 	Mandatory   bool
+
+	// Choices optionally restricts the option to a set of values. For
+	// []string options, each element has to be one of them.
+	Choices []interface{} `json:",omitempty"`
 }
 
 // StateDescriptor describes a State provided by a Bee.
@@ -61,34 +73,211 @@ type StateDescriptor struct {
 	Type        string
 }
 
-// GetActionDescriptor returns the ActionDescriptor matching an action.
+// An InstanceDescriber is an optional interface for bee factories whose bees
+// provide different events or actions, depending on their options.
+type InstanceDescriber interface {
+	InstanceEvents(options BeeOptions) []EventDescriptor
+	InstanceActions(options BeeOptions) []ActionDescriptor
+}
+
+// beeDescriptors holds the descriptors of a bee instance or factory, indexed
+// by name. They never get modified once built, so they can be shared.
+type beeDescriptors struct {
+	events  []EventDescriptor
+	actions []ActionDescriptor
+
+	eventIndex  map[string]int
+	actionIndex map[string]int
+}
+
+// newBeeDescriptors indexes events and actions. Of descriptors sharing a
+// name, the first one wins.
+func newBeeDescriptors(events []EventDescriptor, actions []ActionDescriptor) beeDescriptors {
+	d := beeDescriptors{
+		events:      events,
+		actions:     actions,
+		eventIndex:  make(map[string]int, len(events)),
+		actionIndex: make(map[string]int, len(actions)),
+	}
+	for i := len(events) - 1; i >= 0; i-- {
+		d.eventIndex[events[i].Name] = i
+	}
+	for i := len(actions) - 1; i >= 0; i-- {
+		d.actionIndex[actions[i].Name] = i
+	}
+	return d
+}
+
+// event returns the descriptor of the event called name.
+func (d beeDescriptors) event(name string) (EventDescriptor, bool) {
+	if i, ok := d.eventIndex[name]; ok {
+		return d.events[i], true
+	}
+	return EventDescriptor{}, false
+}
+
+// action returns the descriptor of the action called name.
+func (d beeDescriptors) action(name string) (ActionDescriptor, bool) {
+	if i, ok := d.actionIndex[name]; ok {
+		return d.actions[i], true
+	}
+	return ActionDescriptor{}, false
+}
+
+var (
+	instanceDescriptors      = make(map[string]beeDescriptors)
+	instanceDescriptorsMutex sync.RWMutex
+
+	// factoryDescriptors caches the descriptors of factories, by ID. Most
+	// factories build them from scratch on every call, so they only get
+	// requested on first use
+	factoryDescriptors      = make(map[string]beeDescriptors)
+	factoryDescriptorsMutex sync.RWMutex
+)
+
+// factoryDescriptorsOf returns the cached descriptors of a factory.
+func factoryDescriptorsOf(f *BeeFactoryInterface) beeDescriptors {
+	id := (*f).ID()
+	factoryDescriptorsMutex.RLock()
+	d, ok := factoryDescriptors[id]
+	factoryDescriptorsMutex.RUnlock()
+	if ok {
+		return d
+	}
+
+	d = newBeeDescriptors(cloneEventDescriptors((*f).Events()), cloneActionDescriptors((*f).Actions()))
+	factoryDescriptorsMutex.Lock()
+	defer factoryDescriptorsMutex.Unlock()
+	if cached, ok := factoryDescriptors[id]; ok {
+		// another goroutine beat us to it
+		return cached
+	}
+	factoryDescriptors[id] = d
+	return d
+}
+
+// forgetFactoryDescriptors drops the cached descriptors of a factory, e.g.
+// because it got replaced.
+func forgetFactoryDescriptors(id string) {
+	factoryDescriptorsMutex.Lock()
+	defer factoryDescriptorsMutex.Unlock()
+
+	delete(factoryDescriptors, id)
+}
+
+// setInstanceDescriptors stores the descriptors of a bee instance, cloned
+// from its factory.
+func setInstanceDescriptors(bee *BeeInterface) {
+	f := GetFactory((*bee).Namespace())
+	if f == nil {
+		return
+	}
+	factory := *f
+
+	var d beeDescriptors
+	if id, ok := factory.(InstanceDescriber); ok {
+		d = newBeeDescriptors(cloneEventDescriptors(id.InstanceEvents((*bee).Options())),
+			cloneActionDescriptors(id.InstanceActions((*bee).Options())))
+	} else {
+		d = factoryDescriptorsOf(f)
+	}
+
+	instanceDescriptorsMutex.Lock()
+	defer instanceDescriptorsMutex.Unlock()
+	instanceDescriptors[(*bee).Name()] = d
+}
+
+// renameInstanceDescriptors moves the descriptors of a renamed bee.
+func renameInstanceDescriptors(old, name string) {
+	instanceDescriptorsMutex.Lock()
+	defer instanceDescriptorsMutex.Unlock()
+
+	if d, ok := instanceDescriptors[old]; ok {
+		instanceDescriptors[name] = d
+		delete(instanceDescriptors, old)
+	}
+}
+
+// deleteInstanceDescriptors removes the descriptors of a deleted bee.
+func deleteInstanceDescriptors(name string) {
+	instanceDescriptorsMutex.Lock()
+	defer instanceDescriptorsMutex.Unlock()
+
+	delete(instanceDescriptors, name)
+}
+
+// beeDescriptorsOf returns the descriptors of a bee instance, falling back
+// to the descriptors of its factory.
+func beeDescriptorsOf(bee *BeeInterface) beeDescriptors {
+	instanceDescriptorsMutex.RLock()
+	d, ok := instanceDescriptors[(*bee).Name()]
+	instanceDescriptorsMutex.RUnlock()
+	if ok {
+		return d
+	}
+
+	if f := GetFactory((*bee).Namespace()); f != nil {
+		return factoryDescriptorsOf(f)
+	}
+	return beeDescriptors{}
+}
+
+// BeeEvents returns the events provided by the bee with a specific name.
+func BeeEvents(name string) []EventDescriptor {
+	bee := GetBee(name)
+	if bee == nil {
+		return nil
+	}
+	return cloneEventDescriptors(beeDescriptorsOf(bee).events)
+}
+
+// BeeActions returns the actions provided by the bee with a specific name.
+func BeeActions(name string) []ActionDescriptor {
+	bee := GetBee(name)
+	if bee == nil {
+		return nil
+	}
+	return cloneActionDescriptors(beeDescriptorsOf(bee).actions)
+}
+
+func cloneEventDescriptors(ds []EventDescriptor) []EventDescriptor {
+	r := make([]EventDescriptor, len(ds))
+	for i, d := range ds {
+		d.Options = append([]PlaceholderDescriptor(nil), d.Options...)
+		r[i] = d
+	}
+	return r
+}
+
+func cloneActionDescriptors(ds []ActionDescriptor) []ActionDescriptor {
+	r := make([]ActionDescriptor, len(ds))
+	for i, d := range ds {
+		d.Options = append([]PlaceholderDescriptor(nil), d.Options...)
+		r[i] = d
+	}
+	return r
+}
+
+// GetActionDescriptor returns the ActionDescriptor matching an action. The
+// descriptors of the action's bee instance take precedence over the ones of
+// its factory.
 func GetActionDescriptor(action *Action) ActionDescriptor {
 	bee := GetBee(action.Bee)
 	if bee == nil {
 		panic("Bee " + action.Bee + " not registered")
 	}
-	factory := (*GetFactory((*bee).Namespace()))
-	for _, ac := range factory.Actions() {
-		if ac.Name == action.Name {
-			return ac
-		}
-	}
-
-	return ActionDescriptor{}
+	ac, _ := beeDescriptorsOf(bee).action(action.Name)
+	return ac
 }
 
-// GetEventDescriptor returns the EventDescriptor matching an event.
+// GetEventDescriptor returns the EventDescriptor matching an event. The
+// descriptors of the event's bee instance take precedence over the ones of
+// its factory.
 func GetEventDescriptor(event *Event) EventDescriptor {
 	bee := GetBee(event.Bee)
 	if bee == nil {
 		panic("Bee " + event.Bee + " not registered")
 	}
-	factory := (*GetFactory((*bee).Namespace()))
-	for _, ev := range factory.Events() {
-		if ev.Name == event.Name {
-			return ev
-		}
-	}
-
-	return EventDescriptor{}
+	ev, _ := beeDescriptorsOf(bee).event(event.Name)
+	return ev
 }
@@ -0,0 +1,201 @@
+package bees
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"testing"
+)
+
+// networkBeeFactory declares descriptors depending on a bee's network.
+type networkBeeFactory struct {
+	testFactory
+}
+
+func (factory *networkBeeFactory) InstanceEvents(options BeeOptions) []EventDescriptor {
+	network, _ := options.GetString("network")
+	return []EventDescriptor{{Namespace: "networkbee", Name: "message", Description: "A message on " + network}}
+}
+
+func (factory *networkBeeFactory) InstanceActions(options BeeOptions) []ActionDescriptor {
+	network, _ := options.GetString("network")
+	return []ActionDescriptor{{Namespace: "networkbee", Name: "send", Description: "Send a message to " + network}}
+}
+
+func init() {
+	RegisterFactory(&networkBeeFactory{testFactory{
+		id:     "networkbee",
+		events: []EventDescriptor{{Namespace: "networkbee", Name: "message", Description: "A message"}},
+	}})
+}
+
+func TestInstanceDescriptors(t *testing.T) {
+	for _, network := range []string{"freenode", "oftc"} {
+		mod, err := NewBeeInstance(BeeConfig{
+			Name:    network,
+			Class:   "networkbee",
+			Options: BeeOptions{{Name: "network", Value: network}},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		(*mod).Start()
+		defer DeleteBee(GetBee(network))
+	}
+	sink := newRecordingBee("networksink")
+	defer DeleteBee(GetBee("networksink"))
+
+	for _, network := range []string{"freenode", "oftc"} {
+		if d := GetEventDescriptor(&Event{Bee: network, Name: "message"}); d.Description != "A message on "+network {
+			t.Errorf("Expected descriptor of %s, got %+v", network, d)
+		}
+		if d := GetActionDescriptor(&Action{Bee: network, Name: "send"}); d.Description != "Send a message to "+network {
+			t.Errorf("Expected action descriptor of %s, got %+v", network, d)
+		}
+		if evs := BeeEvents(network); len(evs) != 1 {
+			t.Errorf("Expected one event for %s, got %+v", network, evs)
+		}
+	}
+
+	oldActions := GetActions()
+	defer SetActions(oldActions)
+	SetActions([]Action{
+		{ID: "from-freenode", Bee: "networksink", Name: "freenode"},
+		{ID: "from-oftc", Bee: "networksink", Name: "oftc"},
+	})
+	oldChains := chains
+	defer func() { chains = oldChains }()
+	SetChains([]Chain{
+		{Name: "freenode", Event: &Event{Bee: "freenode", Name: "message"}, Actions: []string{"from-freenode"}},
+		{Name: "oftc", Event: &Event{Bee: "oftc", Name: "message"}, Actions: []string{"from-oftc"}},
+	})
+
+	execChains(context.Background(), &Event{Bee: "oftc", Name: "message"})
+	execChains(context.Background(), &Event{Bee: "freenode", Name: "message"})
+	if got := sink.executed(); len(got) != 2 || got[0] != "oftc" || got[1] != "freenode" {
+		t.Errorf("Expected each event to be matched by its instance's chain, got %v", got)
+	}
+
+	DeleteBee(GetBee("oftc"))
+	instanceDescriptorsMutex.RLock()
+	_, ok := instanceDescriptors["oftc"]
+	instanceDescriptorsMutex.RUnlock()
+	if ok {
+		t.Error("Expected descriptors of deleted bee to be removed")
+	}
+
+	if err := RenameBee("freenode", "libera"); err != nil {
+		t.Fatal(err)
+	}
+	if d := GetEventDescriptor(&Event{Bee: "libera", Name: "message"}); d.Description != "A message on freenode" {
+		t.Errorf("Expected descriptors to move with the renamed bee, got %+v", d)
+	}
+}
+
+// busyBeeFactory declares plenty of events and counts how often it gets
+// asked for them.
+type busyBeeFactory struct {
+	testFactory
+	calls int32
+}
+
+func (factory *busyBeeFactory) Events() []EventDescriptor {
+	atomic.AddInt32(&factory.calls, 1)
+
+	var evs []EventDescriptor
+	for i := 0; i < 50; i++ {
+		evs = append(evs, EventDescriptor{Namespace: "busybee", Name: fmt.Sprintf("event%d", i)})
+	}
+	return evs
+}
+
+var busyFactory = &busyBeeFactory{testFactory: testFactory{id: "busybee"}}
+
+func init() {
+	RegisterFactory(busyFactory)
+}
+
+func TestFactoryDescriptorCache(t *testing.T) {
+	forgetFactoryDescriptors("busybee")
+	atomic.StoreInt32(&busyFactory.calls, 0)
+
+	mod, err := NewBeeInstance(BeeConfig{Name: "busy", Class: "busybee"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer DeleteBee(mod)
+
+	for i := 0; i < 10; i++ {
+		if d := GetEventDescriptor(&Event{Bee: "busy", Name: "event42"}); d.Name != "event42" {
+			t.Errorf("Expected descriptor of event42, got %+v", d)
+		}
+	}
+	if d := GetEventDescriptor(&Event{Bee: "busy", Name: "unknown"}); d.Name != "" {
+		t.Errorf("Expected no descriptor for unknown event, got %+v", d)
+	}
+
+	// bees without instance descriptors again fall back to the factory
+	deleteInstanceDescriptors("busy")
+	if d := GetEventDescriptor(&Event{Bee: "busy", Name: "event0"}); d.Name != "event0" {
+		t.Errorf("Expected descriptor of event0, got %+v", d)
+	}
+	if calls := atomic.LoadInt32(&busyFactory.calls); calls != 1 {
+		t.Errorf("Expected factory to be asked for its events once, got %d", calls)
+	}
+
+	RegisterFactory(busyFactory)
+	GetEventDescriptor(&Event{Bee: "busy", Name: "event0"})
+	if calls := atomic.LoadInt32(&busyFactory.calls); calls != 2 {
+		t.Errorf("Expected re-registering the factory to drop its cached events, got %d calls", calls)
+	}
+}
+
+func TestBeeDescriptorsFirstWins(t *testing.T) {
+	d := newBeeDescriptors([]EventDescriptor{
+		{Name: "message", Description: "first"},
+		{Name: "message", Description: "second"},
+	}, nil)
+	if ev, ok := d.event("message"); !ok || ev.Description != "first" {
+		t.Errorf("Expected first descriptor to win, got %+v", ev)
+	}
+	if _, ok := d.action("send"); ok {
+		t.Error("Expected no action descriptor")
+	}
+}
+
+// linearEventDescriptor looks an event up the way Beehive used to, asking the
+// factory for its descriptors and scanning them on every call.
+func linearEventDescriptor(event *Event) EventDescriptor {
+	bee := GetBee(event.Bee)
+	for _, ev := range (*GetFactory((*bee).Namespace())).Events() {
+		if ev.Name == event.Name {
+			return ev
+		}
+	}
+	return EventDescriptor{}
+}
+
+func benchmarkEventDescriptor(b *testing.B, lookup func(*Event) EventDescriptor) {
+	mod, err := NewBeeInstance(BeeConfig{Name: "busybench", Class: "busybee"})
+	if err != nil {
+		b.Fatal(err)
+	}
+	defer DeleteBee(mod)
+
+	event := &Event{Bee: "busybench", Name: "event49"}
+	b.ReportAllocs()
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			lookup(event)
+		}
+	})
+}
+
+func BenchmarkEventDescriptorLinear(b *testing.B) {
+	benchmarkEventDescriptor(b, linearEventDescriptor)
+}
+
+func BenchmarkEventDescriptorIndexed(b *testing.B) {
+	benchmarkEventDescriptor(b, GetEventDescriptor)
+}
@@ -23,6 +23,8 @@
 package devrantbee
 
 import (
+	"context"
+
 	"github.com/jayeshsolanki93/devgorant"
 
 	"github.com/muesli/beehive/bees"
@@ -38,7 +40,7 @@ type DevrantBee struct {
 }
 
 // Action triggers the action passed to it.
-func (mod *DevrantBee) Action(action bees.Action) []bees.Placeholder {
+func (mod *DevrantBee) Action(ctx context.Context, action bees.Action) []bees.Placeholder {
 	outs := []bees.Placeholder{}
 
 	switch action.Name {
@@ -141,7 +143,7 @@ func (mod *DevrantBee) triggerEvent(rant devgorant.RantModel) {
 }
 
 // Run executes the Bee's event loop.
-func (mod *DevrantBee) Run(eventChan chan bees.Event) {
+func (mod *DevrantBee) Run(ctx context.Context, eventChan chan bees.Event) {
 	mod.evchan = eventChan
 
 	// Setting up the client, unfortunately we can't really log in as an user
@@ -25,6 +25,8 @@
 package discordbee
 
 import (
+	"context"
+
 	"github.com/bwmarrin/discordgo"
 	"github.com/muesli/beehive/bees"
 )
@@ -41,7 +43,7 @@ type DiscordBee struct {
 }
 
 // Run executes the Bee's event loop.
-func (mod *DiscordBee) Run(eventChan chan bees.Event) {
+func (mod *DiscordBee) Run(ctx context.Context, eventChan chan bees.Event) {
 	mod.eventChan = eventChan
 
 	mod.LogDebugf("Starting discord bee with apiToken %s", mod.apiToken)
@@ -117,7 +119,7 @@ func (mod *DiscordBee) onSend(s *discordgo.Session, event *discordgo.MessageCrea
 }
 
 // Action triggers the action passed to it.
-func (mod *DiscordBee) Action(action bees.Action) []bees.Placeholder {
+func (mod *DiscordBee) Action(ctx context.Context, action bees.Action) []bees.Placeholder {
 	outs := []bees.Placeholder{}
 	switch action.Name {
 	case "send":
@@ -22,6 +22,7 @@
 package efabee
 
 import (
+	"context"
 	"time"
 
 	"github.com/muesli/goefa"
@@ -40,7 +41,7 @@ type EFABee struct {
 }
 
 // Action triggers the action passed to it.
-func (mod *EFABee) Action(action bees.Action) []bees.Placeholder {
+func (mod *EFABee) Action(ctx context.Context, action bees.Action) []bees.Placeholder {
 	outs := []bees.Placeholder{}
 
 	switch action.Name {
@@ -205,7 +206,7 @@ func (mod *EFABee) Action(action bees.Action) []bees.Placeholder {
 }
 
 // Run executes the Bee's event loop.
-func (mod *EFABee) Run(eventChan chan bees.Event) {
+func (mod *EFABee) Run(ctx context.Context, eventChan chan bees.Event) {
 	mod.eventChan = eventChan
 }
 
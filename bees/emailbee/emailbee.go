@@ -18,39 +18,55 @@
  *      Christian Muehlhaeuser <muesli@gmail.com>
  */
 
-// Package emailbee is a Bee that is able to send emails.
+// Package emailbee is a Bee that is able to send and receive emails.
 package emailbee
 
 import (
+	"context"
+	"errors"
 	"net"
-
 	"strconv"
 
 	"github.com/go-mail/mail"
 	"github.com/muesli/beehive/bees"
 )
 
-// EmailBee is a Bee that is able to send emails.
+// EmailBee is a Bee that is able to send emails and watch an IMAP mailbox.
 type EmailBee struct {
 	bees.Bee
 
 	username string
 	password string
 	server   string
+
+	imapServer   string
+	imapUsername string
+	imapPassword string
+	mailbox      string
+	markSeen     bool
 }
 
-// Action triggers the action passed to it.
-func (mod *EmailBee) Action(action bees.Action) []bees.Placeholder {
+// ActionE triggers the action passed to it.
+func (mod *EmailBee) ActionE(ctx context.Context, action bees.Action) ([]bees.Placeholder, error) {
 	outs := []bees.Placeholder{}
 
 	switch action.Name {
 	case "send":
-		var from, to, plainText, htmlText, subject string
+		var from, plainText, htmlText, subject, replyTo string
+		var to, cc, bcc, attachments []string
 		action.Options.Bind("sender", &from)
 		action.Options.Bind("recipient", &to)
+		action.Options.Bind("cc", &cc)
+		action.Options.Bind("bcc", &bcc)
+		action.Options.Bind("reply_to", &replyTo)
 		action.Options.Bind("subject", &subject)
 		action.Options.Bind("text", &plainText)
 		action.Options.Bind("html", &htmlText)
+		action.Options.Bind("attachments", &attachments)
+
+		if len(to) == 0 {
+			return outs, errors.New("No recipient given")
+		}
 
 		m := mail.NewMessage()
 		if len(from) > 0 {
@@ -58,13 +74,31 @@ func (mod *EmailBee) Action(action bees.Action) []bees.Placeholder {
 		} else {
 			m.SetHeader("From", mod.username)
 		}
-		m.SetHeader("To", to)
+		m.SetHeader("To", to...)
+		if len(cc) > 0 {
+			m.SetHeader("Cc", cc...)
+		}
+		if len(bcc) > 0 {
+			m.SetHeader("Bcc", bcc...)
+		}
+		if replyTo != "" {
+			m.SetHeader("Reply-To", replyTo)
+		}
 		m.SetHeader("Subject", subject)
 		if plainText != "" {
 			m.SetBody("text/plain", plainText)
 		}
 		if htmlText != "" {
-			m.SetBody("text/html", htmlText)
+			if plainText != "" {
+				m.AddAlternative("text/html", htmlText)
+			} else {
+				m.SetBody("text/html", htmlText)
+			}
+		}
+		for _, path := range attachments {
+			if path != "" {
+				m.Attach(path)
+			}
 		}
 
 		host, portstr, err := net.SplitHostPort(mod.server)
@@ -77,13 +111,13 @@ func (mod *EmailBee) Action(action bees.Action) []bees.Placeholder {
 		if len(mod.username) > 0 && len(mod.password) > 0 {
 			// With authentication
 			if err := mail.NewDialer(host, port, mod.username, mod.password).DialAndSend(m); err != nil {
-				panic(err)
+				return outs, err
 			}
 		} else {
 			// No Auth
 			d := mail.Dialer{Host: host, Port: port}
 			if err := d.DialAndSend(m); err != nil {
-				panic(err)
+				return outs, err
 			}
 		}
 
@@ -91,6 +125,15 @@ func (mod *EmailBee) Action(action bees.Action) []bees.Placeholder {
 		panic("Unknown action triggered in " + mod.Name() + ": " + action.Name)
 	}
 
+	return outs, nil
+}
+
+// Action triggers the action passed to it.
+func (mod *EmailBee) Action(ctx context.Context, action bees.Action) []bees.Placeholder {
+	outs, err := mod.ActionE(ctx, action)
+	if err != nil {
+		mod.LogErrorf("Error: %v", err)
+	}
 	return outs
 }
 
@@ -101,4 +144,11 @@ func (mod *EmailBee) ReloadOptions(options bees.BeeOptions) {
 	options.Bind("username", &mod.username)
 	options.Bind("password", &mod.password)
 	options.Bind("address", &mod.server)
+
+	mod.mailbox = "INBOX"
+	options.Bind("imap_address", &mod.imapServer)
+	options.Bind("imap_username", &mod.imapUsername)
+	options.Bind("imap_password", &mod.imapPassword)
+	options.Bind("mailbox", &mod.mailbox)
+	options.Bind("mark_seen", &mod.markSeen)
 }
@@ -51,7 +51,7 @@ func (factory *EmailBeeFactory) Name() string {
 
 // Description returns the description of this Bee.
 func (factory *EmailBeeFactory) Description() string {
-	return "Lets you send emails"
+	return "Lets you send emails and reacts to incoming mail"
 }
 
 // Image returns the filename of an image for this Bee.
@@ -85,13 +85,98 @@ func (factory *EmailBeeFactory) Options() []bees.BeeOptionDescriptor {
 			Type:        "address",
 			Mandatory:   true,
 		},
+		{
+			Name:        "imap_address",
+			Description: "Address of IMAP server to watch for new mail, eg: imap.myserver.com:993",
+			Type:        "address",
+			Mandatory:   false,
+		},
+		{
+			Name:        "imap_username",
+			Description: "Username used for IMAP auth, defaults to the SMTP username",
+			Type:        "string",
+			Mandatory:   false,
+		},
+		{
+			Name:        "imap_password",
+			Description: "Password used for IMAP auth, defaults to the SMTP password",
+			Type:        "password",
+			Mandatory:   false,
+		},
+		{
+			Name:        "mailbox",
+			Description: "Mailbox to watch, defaults to INBOX",
+			Type:        "string",
+			Default:     "INBOX",
+			Mandatory:   false,
+		},
+		{
+			Name:        "mark_seen",
+			Description: "Whether to mark incoming mail as read",
+			Type:        "bool",
+			Default:     false,
+			Mandatory:   false,
+		},
 	}
 	return opts
 }
 
 // Events describes the available events provided by this Bee.
 func (factory *EmailBeeFactory) Events() []bees.EventDescriptor {
-	events := []bees.EventDescriptor{}
+	events := []bees.EventDescriptor{
+		{
+			Namespace:   factory.Name(),
+			Name:        "message",
+			Description: "A new email arrived in the watched mailbox",
+			Options: []bees.PlaceholderDescriptor{
+				{
+					Name:        "from",
+					Description: "email address of the sender",
+					Type:        "string",
+				},
+				{
+					Name:        "from_name",
+					Description: "Display name of the sender",
+					Type:        "string",
+				},
+				{
+					Name:        "to",
+					Description: "email addresses of the recipients",
+					Type:        "[]string",
+				},
+				{
+					Name:        "subject",
+					Description: "Subject of the email",
+					Type:        "string",
+				},
+				{
+					Name:        "text",
+					Description: "Plain text content of the email",
+					Type:        "string",
+				},
+				{
+					Name:        "html",
+					Description: "HTML content of the email",
+					Type:        "string",
+				},
+				{
+					Name:        "date",
+					Description: "Date the email was sent",
+					Type:        "timestamp",
+				},
+				{
+					Name:        "message_id",
+					Description: "Message-ID of the email",
+					Type:        "string",
+				},
+				{
+					Name:        "attachments",
+					Description: "File names of the attachments",
+					Type:        "[]string",
+				},
+			},
+		},
+	}
 	return events
 }
 
@@ -110,10 +195,25 @@ func (factory *EmailBeeFactory) Actions() []bees.ActionDescriptor {
 				},
 				{
 					Name:        "recipient",
-					Description: "email address of the recipient",
+					Description: "email address of the recipient, comma-separated for multiple recipients",
 					Type:        "string",
 					Mandatory:   true,
 				},
+				{
+					Name:        "cc",
+					Description: "email addresses to send a copy to",
+					Type:        "[]string",
+				},
+				{
+					Name:        "bcc",
+					Description: "email addresses to send a blind copy to",
+					Type:        "[]string",
+				},
+				{
+					Name:        "reply_to",
+					Description: "email address replies should be sent to",
+					Type:        "string",
+				},
 				{
 					Name:        "subject",
 					Description: "Subject of the email",
@@ -130,6 +230,11 @@ func (factory *EmailBeeFactory) Actions() []bees.ActionDescriptor {
 					Description: "Content of the email using HTML",
 					Type:        "string",
 				},
+				{
+					Name:        "attachments",
+					Description: "Paths of files to attach to the email",
+					Type:        "[]string",
+				},
 			},
 		},
 	}
@@ -0,0 +1,317 @@
+/*
+ *    Copyright (C) 2026 Christian Muehlhaeuser
+ *
+ *    This program is free software: you can redistribute it and/or modify
+ *    it under the terms of the GNU Affero General Public License as published
+ *    by the Free Software Foundation, either version 3 of the License, or
+ *    (at your option) any later version.
+ *
+ *    This program is distributed in the hope that it will be useful,
+ *    but WITHOUT ANY WARRANTY; without even the implied warranty of
+ *    MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ *    GNU Affero General Public License for more details.
+ *
+ *    You should have received a copy of the GNU Affero General Public License
+ *    along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ *    Authors:
+ *      Christian Muehlhaeuser <muesli@gmail.com>
+ */
+
+package emailbee
+
+import (
+	"context"
+	"io"
+	"io/ioutil"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/emersion/go-imap"
+	"github.com/emersion/go-imap/client"
+	_ "github.com/emersion/go-message/charset" // decode non-UTF-8 messages
+	"github.com/emersion/go-message/mail"
+
+	"github.com/muesli/beehive/bees"
+)
+
+// reconnectDelay is how long the bee waits before reconnecting to the IMAP
+// server after a connection error.
+var reconnectDelay = 30 * time.Second
+
+// Run executes the Bee's event loop. It only watches a mailbox if an IMAP
+// server has been configured.
+func (mod *EmailBee) Run(ctx context.Context, eventChan chan bees.Event) {
+	if mod.imapServer == "" {
+		select {
+		case <-mod.SigChan:
+		case <-ctx.Done():
+		}
+		return
+	}
+
+	var lastUID uint32
+	for {
+		err := mod.watch(ctx, eventChan, &lastUID)
+		if err == nil {
+			return
+		}
+		mod.LogErrorf("IMAP error: %v", err)
+
+		select {
+		case <-mod.SigChan:
+			return
+		case <-ctx.Done():
+			return
+		case <-time.After(reconnectDelay):
+		}
+	}
+}
+
+// watch connects to the IMAP server and emits an event for every message
+// arriving in the mailbox, until the bee gets stopped or the connection
+// fails. It uses IDLE if the server supports it and falls back to polling
+// otherwise.
+func (mod *EmailBee) watch(ctx context.Context, eventChan chan bees.Event, lastUID *uint32) error {
+	c, err := client.DialTLS(mod.imapServer, nil)
+	if err != nil {
+		return err
+	}
+	defer c.Logout()
+
+	username, password := mod.imapUsername, mod.imapPassword
+	if username == "" {
+		username, password = mod.username, mod.password
+	}
+	if err := c.Login(username, password); err != nil {
+		return err
+	}
+
+	status, err := c.Select(mod.mailbox, !mod.markSeen)
+	if err != nil {
+		return err
+	}
+	if *lastUID == 0 && status.UidNext > 0 {
+		// only report messages arriving after the bee started
+		*lastUID = status.UidNext - 1
+	}
+
+	// collapse the server's unilateral updates into a single notification,
+	// so the client never blocks on a full updates channel
+	updates := make(chan client.Update, 16)
+	notify := make(chan struct{}, 1)
+	quit := make(chan struct{})
+	defer close(quit)
+	c.Updates = updates
+	go func() {
+		for {
+			select {
+			case <-updates:
+				select {
+				case notify <- struct{}{}:
+				default:
+				}
+			case <-quit:
+				return
+			}
+		}
+	}()
+
+	for {
+		if err := mod.fetchNew(ctx, c, eventChan, lastUID); err != nil {
+			return err
+		}
+
+		stop := make(chan struct{})
+		done := make(chan error, 1)
+		go func() {
+			done <- c.Idle(stop, nil)
+		}()
+
+		select {
+		case <-notify:
+			close(stop)
+			if err := <-done; err != nil {
+				return err
+			}
+		case err := <-done:
+			if err != nil {
+				return err
+			}
+		case <-mod.SigChan:
+			close(stop)
+			<-done
+			return nil
+		case <-ctx.Done():
+			close(stop)
+			<-done
+			return nil
+		}
+	}
+}
+
+// fetchNew emits an event for every message with a UID above lastUID and
+// advances lastUID accordingly.
+func (mod *EmailBee) fetchNew(ctx context.Context, c *client.Client, eventChan chan bees.Event, lastUID *uint32) error {
+	criteria := imap.NewSearchCriteria()
+	criteria.Uid = new(imap.SeqSet)
+	criteria.Uid.AddRange(*lastUID+1, 0)
+
+	found, err := c.UidSearch(criteria)
+	if err != nil {
+		return err
+	}
+
+	// "n:*" always matches the newest message, even if its UID is below n
+	uids := []uint32{}
+	for _, uid := range found {
+		if uid > *lastUID {
+			uids = append(uids, uid)
+		}
+	}
+	if len(uids) == 0 {
+		return nil
+	}
+	sort.Slice(uids, func(i, j int) bool { return uids[i] < uids[j] })
+
+	seqset := new(imap.SeqSet)
+	seqset.AddNum(uids...)
+	section := &imap.BodySectionName{Peek: !mod.markSeen}
+	items := []imap.FetchItem{imap.FetchUid, section.FetchItem()}
+
+	messages := make(chan *imap.Message, len(uids))
+	done := make(chan error, 1)
+	go func() {
+		done <- c.UidFetch(seqset, items, messages)
+	}()
+
+	for msg := range messages {
+		if msg.Uid > *lastUID {
+			*lastUID = msg.Uid
+		}
+
+		body := msg.GetBody(section)
+		if body == nil {
+			continue
+		}
+		ev, err := mod.messageEvent(body)
+		if err != nil {
+			mod.LogErrorf("Can't parse message %d: %v", msg.Uid, err)
+			continue
+		}
+		select {
+		case eventChan <- ev:
+		case <-ctx.Done():
+			return nil
+		}
+	}
+
+	return <-done
+}
+
+// messageEvent parses a raw message and turns it into a message event.
+func (mod *EmailBee) messageEvent(r io.Reader) (bees.Event, error) {
+	mr, err := mail.CreateReader(r)
+	if err != nil {
+		return bees.Event{}, err
+	}
+
+	var from, fromName, messageID string
+	if addrs, err := mr.Header.AddressList("From"); err == nil && len(addrs) > 0 {
+		from = addrs[0].Address
+		fromName = addrs[0].Name
+	}
+	to := []string{}
+	if addrs, err := mr.Header.AddressList("To"); err == nil {
+		for _, addr := range addrs {
+			to = append(to, addr.Address)
+		}
+	}
+	subject, _ := mr.Header.Subject()
+	date, _ := mr.Header.Date()
+	messageID, _ = mr.Header.MessageID()
+
+	var text, html strings.Builder
+	attachments := []string{}
+	for {
+		p, err := mr.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return bees.Event{}, err
+		}
+
+		switch h := p.Header.(type) {
+		case *mail.InlineHeader:
+			ct, _, _ := h.ContentType()
+			b, err := ioutil.ReadAll(p.Body)
+			if err != nil {
+				return bees.Event{}, err
+			}
+			switch ct {
+			case "text/html":
+				html.Write(b)
+			case "text/plain", "":
+				text.Write(b)
+			}
+
+		case *mail.AttachmentHeader:
+			name, _ := h.Filename()
+			attachments = append(attachments, name)
+		}
+	}
+
+	return bees.Event{
+		Bee:  mod.Name(),
+		Name: "message",
+		Options: []bees.Placeholder{
+			{
+				Name:  "from",
+				Type:  "string",
+				Value: from,
+			},
+			{
+				Name:  "from_name",
+				Type:  "string",
+				Value: fromName,
+			},
+			{
+				Name:  "to",
+				Type:  "[]string",
+				Value: to,
+			},
+			{
+				Name:  "subject",
+				Type:  "string",
+				Value: subject,
+			},
+			{
+				Name:  "text",
+				Type:  "string",
+				Value: text.String(),
+			},
+			{
+				Name:  "html",
+				Type:  "string",
+				Value: html.String(),
+			},
+			{
+				Name:  "date",
+				Type:  "timestamp",
+				Value: date,
+			},
+			{
+				Name:  "message_id",
+				Type:  "string",
+				Value: messageID,
+			},
+			{
+				Name:  "attachments",
+				Type:  "[]string",
+				Value: attachments,
+			},
+		},
+	}, nil
+}
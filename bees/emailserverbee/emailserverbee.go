@@ -21,6 +21,8 @@
 package emailserverbee
 
 import (
+	"context"
+
 	"github.com/flashmob/go-guerrilla"
 	"github.com/flashmob/go-guerrilla/backends"
 	"github.com/flashmob/go-guerrilla/mail"
@@ -124,7 +126,7 @@ func (mod *EmailServerBee) mailProcessor() func() backends.Decorator {
 }
 
 // Run executes the Bee's event loop.
-func (mod *EmailServerBee) Run(cin chan bees.Event) {
+func (mod *EmailServerBee) Run(ctx context.Context, cin chan bees.Event) {
 	mod.eventChan = cin
 
 	// see https://github.com/flashmob/go-guerrilla/wiki/Using-as-a-package
@@ -0,0 +1,146 @@
+/*
+ *    Copyright (C) 2026 Christian Muehlhaeuser
+ *
+ *    This program is free software: you can redistribute it and/or modify
+ *    it under the terms of the GNU Affero General Public License as published
+ *    by the Free Software Foundation, either version 3 of the License, or
+ *    (at your option) any later version.
+ *
+ *    This program is distributed in the hope that it will be useful,
+ *    but WITHOUT ANY WARRANTY; without even the implied warranty of
+ *    MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ *    GNU Affero General Public License for more details.
+ *
+ *    You should have received a copy of the GNU Affero General Public License
+ *    along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ *    Authors:
+ *      Christian Muehlhaeuser <muesli@gmail.com>
+ */
+// Package bees is Beehive's central module system.
+package bees
+
+import (
+	"fmt"
+	"sync/atomic"
+)
+
+var strictEvents int32
+
+// SetStrictEvents controls whether EmitEvent rejects events that don't match
+// their descriptor, instead of merely logging a warning. Test suites of bees
+// should enable it to catch descriptors drifting apart from the events.
+func SetStrictEvents(strict bool) {
+	var v int32
+	if strict {
+		v = 1
+	}
+	atomic.StoreInt32(&strictEvents, v)
+}
+
+// EmitEvent sends an event of a bee to the hive, after checking its
+// placeholders against the event's descriptor. Missing and unknown
+// placeholders get logged, or fail the event in strict mode, see
+// SetStrictEvents. Events emitted while the bee executes an action are
+// considered to be caused by it, which allows detecting feedback loops.
+// Returns ErrBeeNotRunning, without sending the event, if the bee is being
+// stopped or deleted, see DeleteBeeGracefully.
+func EmitEvent(bee BeeInterface, name string, placeholders ...Placeholder) error {
+	event := Event{
+		Bee:     bee.Name(),
+		Name:    name,
+		Options: placeholders,
+		trace:   activeTrace(bee.Name()),
+	}
+
+	if errs := checkEventDescriptor(bee, event); len(errs) > 0 {
+		if atomic.LoadInt32(&strictEvents) != 0 {
+			return validationError(errs)
+		}
+		for _, err := range errs {
+			logBeef(bee, LogWarn, "%v", err)
+		}
+	}
+
+	return sendEvent(bee, event)
+}
+
+// sendEvent feeds an event into the event handler, unless the bee is being
+// stopped or the event handler has already been stopped.
+func sendEvent(bee BeeInterface, event Event) error {
+	var sig chan bool
+	if b, ok := bee.(interface{ sigChan() chan bool }); ok {
+		sig = b.sigChan()
+	}
+	var done <-chan struct{}
+	if ctx := bee.Context(); ctx != nil {
+		done = ctx.Done()
+	}
+
+	if beeDraining(bee.Name()) {
+		return ErrBeeNotRunning
+	}
+
+	in, stopped := eventChannel()
+	select {
+	case <-sig:
+		return ErrBeeNotRunning
+	case <-done:
+		return ErrBeeNotRunning
+	case <-stopped:
+		return ErrBeeNotRunning
+	default:
+	}
+
+	select {
+	case in <- event:
+		bee.LogEvent()
+		return nil
+	case <-stopped:
+		return ErrBeeNotRunning
+	case <-sig:
+		return ErrBeeNotRunning
+	case <-done:
+		return ErrBeeNotRunning
+	}
+}
+
+// checkEventDescriptor checks an event's placeholders against the
+// descriptor of the event provided by the bee.
+func checkEventDescriptor(bee BeeInterface, event Event) []error {
+	if GetFactory(bee.Namespace()) == nil {
+		return []error{fmt.Errorf("Event %s/%s: unknown bee factory %s", event.Bee, event.Name, bee.Namespace())}
+	}
+
+	desc, ok := beeDescriptorsOf(&bee).event(event.Name)
+	if !ok {
+		return []error{fmt.Errorf("Event %s/%s: not declared by bee factory %s", event.Bee, event.Name, bee.Namespace())}
+	}
+
+	var errs []error
+	declared := make(map[string]bool)
+	for _, opt := range desc.Options {
+		declared[opt.Name] = true
+		var ph *Placeholder
+		for i := range event.Options {
+			if event.Options[i].Name == opt.Name {
+				ph = &event.Options[i]
+				break
+			}
+		}
+		if ph == nil {
+			errs = append(errs, fmt.Errorf("Event %s/%s: missing placeholder %s", event.Bee, event.Name, opt.Name))
+			continue
+		}
+		if len(opt.Type) > 0 && ph.Type != opt.Type {
+			errs = append(errs, fmt.Errorf("Event %s/%s: placeholder %s has type %s, expected %s", event.Bee, event.Name, ph.Name, ph.Type, opt.Type))
+		}
+	}
+	for _, ph := range event.Options {
+		if !declared[ph.Name] {
+			errs = append(errs, fmt.Errorf("Event %s/%s: unknown placeholder %s", event.Bee, event.Name, ph.Name))
+		}
+	}
+
+	return errs
+}
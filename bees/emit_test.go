@@ -0,0 +1,68 @@
+package bees
+
+import (
+	"testing"
+)
+
+func init() {
+	RegisterFactory(&testFactory{
+		id: "emitbee",
+		events: []EventDescriptor{
+			{
+				Namespace: "emitbee",
+				Name:      "message",
+				Options: []PlaceholderDescriptor{
+					{Name: "text", Type: "string"},
+					{Name: "user", Type: "string"},
+				},
+			},
+		},
+	})
+}
+
+func TestEmitEvent(t *testing.T) {
+	events := make(chan Event, 1)
+	defer useEventChannel(events)()
+
+	bee := &recordingBee{Bee: NewBee("emitter", "emitbee", "", nil)}
+	bee.Start()
+
+	text := Placeholder{Name: "text", Type: "string", Value: "hello"}
+	user := Placeholder{Name: "user", Type: "string", Value: "muesli"}
+	// emitting valid events is covered by TestHarnessEmitEvent
+	SetStrictEvents(true)
+	defer SetStrictEvents(false)
+	invalid := [][]Placeholder{
+		{text},
+		{text, user, {Name: "channel", Type: "string", Value: "#beehive"}},
+		{text, {Name: "user", Type: "int", Value: 1}},
+	}
+	for _, ph := range invalid {
+		if err := EmitEvent(bee, "message", ph...); err == nil {
+			t.Errorf("Expected event with placeholders %+v to be rejected", ph)
+		}
+	}
+	if err := EmitEvent(bee, "undeclared", text); err == nil {
+		t.Error("Expected undeclared event to be rejected")
+	}
+	if len(events) != 0 {
+		t.Error("Expected rejected events not to be sent")
+	}
+
+	SetStrictEvents(false)
+	if err := EmitEvent(bee, "message", text); err != nil {
+		t.Errorf("Expected invalid event to pass outside of strict mode, got %v", err)
+	}
+	<-events
+
+	bee.Stop()
+	if err := EmitEvent(bee, "message", text, user); err != ErrBeeNotRunning {
+		t.Errorf("Expected emitting from a stopped bee to fail, got %v", err)
+	}
+
+	bee = &recordingBee{Bee: NewBee("emitter", "emitbee", "", nil)}
+	closeEventChannel()
+	if err := EmitEvent(bee, "message", text, user); err != ErrBeeNotRunning {
+		t.Errorf("Expected emitting to a stopped event handler to fail, got %v", err)
+	}
+}
@@ -0,0 +1,182 @@
+/*
+ *    Copyright (C) 2026 Christian Muehlhaeuser
+ *
+ *    This program is free software: you can redistribute it and/or modify
+ *    it under the terms of the GNU Affero General Public License as published
+ *    by the Free Software Foundation, either version 3 of the License, or
+ *    (at your option) any later version.
+ *
+ *    This program is distributed in the hope that it will be useful,
+ *    but WITHOUT ANY WARRANTY; without even the implied warranty of
+ *    MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ *    GNU Affero General Public License for more details.
+ *
+ *    You should have received a copy of the GNU Affero General Public License
+ *    along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ *    Authors:
+ *      Christian Muehlhaeuser <muesli@gmail.com>
+ */
+
+// Package bees is Beehive's central module system.
+package bees
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/muesli/beehive/enrichers"
+)
+
+// DefaultEnrichmentTimeout is the time an enricher may take to look up a
+// value, unless its Enrichment sets another one.
+const DefaultEnrichmentTimeout = 2 * time.Second
+
+// An Enrichment adds a placeholder to events before chains get matched,
+// computed by an enricher from another placeholder, e.g. the host name
+// belonging to an IP address. Enrichments are either global or part of a
+// chain, in which case the added placeholder is only visible to that chain.
+type Enrichment struct {
+	// Enricher is the name of the enricher, see enrichers.RegisterEnricher.
+	Enricher string
+	// Input is the placeholder the enricher looks up.
+	Input string
+	// Output is the placeholder getting added.
+	Output string
+	// Options configure the enricher.
+	Options map[string]interface{} `json:",omitempty"`
+
+	// Event restricts the enrichment to matching events, like a chain's
+	// trigger does.
+	Event *Event `json:",omitempty"`
+	// If is a filter expression events have to pass to get enriched.
+	If string `json:",omitempty"`
+
+	Timeout time.Duration `json:",omitempty"`
+}
+
+var (
+	enrichments      []Enrichment
+	enrichmentsMutex sync.RWMutex
+)
+
+// SetEnrichments replaces the global enrichments, which apply to all events
+// before chains get matched.
+func SetEnrichments(es []Enrichment) {
+	enrichmentsMutex.Lock()
+	defer enrichmentsMutex.Unlock()
+
+	enrichments = append([]Enrichment(nil), es...)
+}
+
+// GetEnrichments returns the global enrichments.
+func GetEnrichments() []Enrichment {
+	enrichmentsMutex.RLock()
+	defer enrichmentsMutex.RUnlock()
+
+	return append([]Enrichment{}, enrichments...)
+}
+
+// ValidateEnrichment checks that an enrichment refers to a registered
+// enricher and has a valid filter expression.
+func ValidateEnrichment(e Enrichment) error {
+	if len(e.Input) == 0 || len(e.Output) == 0 {
+		return errors.New("Enrichments need an input and an output placeholder")
+	}
+	if enrichers.GetEnricher(e.Enricher) == nil {
+		return fmt.Errorf("Unknown enricher %s", e.Enricher)
+	}
+	if len(e.If) > 0 {
+		return validateFilter(e.If)
+	}
+	return nil
+}
+
+// enriched returns whether the placeholder called name gets added to the
+// events of chain c by one of its or the global enrichments.
+func enriched(c Chain, name string) bool {
+	for _, e := range append(GetEnrichments(), c.Enrich...) {
+		if e.Output == name {
+			return true
+		}
+	}
+	return false
+}
+
+// enrichEvent applies enrichments to an event. Failing lookups get logged and
+// leave the event as it is.
+func enrichEvent(ctx context.Context, event *Event, es []Enrichment) {
+	copied := false
+	for _, e := range es {
+		if e.Event != nil && !triggerMatches(e.Event, event) {
+			continue
+		}
+		value := event.Options.Value(e.Input)
+		if value == nil {
+			continue
+		}
+		if len(e.If) > 0 {
+			passed, _, err := FilterNode{Filter: e.If}.evaluate(eventMap(event), nil)
+			if err != nil {
+				logger.Errorf("Enrichment of %s failed: %v", e.Output, err)
+				continue
+			}
+			if !passed {
+				continue
+			}
+		}
+
+		res, err := enrich(ctx, e, value)
+		if err != nil {
+			logger.Warnf("Enrichment of %s for event %s/%s failed: %v", e.Output, event.Bee, event.Name, err)
+			continue
+		}
+
+		if !copied {
+			// the options may be shared with other copies of the event
+			event.Options = append(Placeholders{}, event.Options...)
+			copied = true
+		}
+		event.Options.SetValue(e.Output, placeholderType(res), res)
+	}
+}
+
+// enrich looks up value with the enrichment's enricher.
+func enrich(ctx context.Context, e Enrichment, value interface{}) (res interface{}, err error) {
+	enricher := enrichers.GetEnricher(e.Enricher)
+	if enricher == nil {
+		return nil, fmt.Errorf("Unknown enricher %s", e.Enricher)
+	}
+
+	timeout := e.Timeout
+	if timeout <= 0 {
+		timeout = DefaultEnrichmentTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%v", r)
+		}
+	}()
+	return (*enricher).Enrich(ctx, value, e.Options)
+}
+
+// placeholderType returns the placeholder type of an enriched value.
+func placeholderType(v interface{}) string {
+	switch v.(type) {
+	case bool:
+		return "bool"
+	case int, int64:
+		return "int"
+	case float64:
+		return "float"
+	case map[string]interface{}:
+		return "map"
+	}
+	return "string"
+}
@@ -0,0 +1,99 @@
+package bees
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/muesli/beehive/enrichers"
+)
+
+// upperEnricher upper-cases values and fails for "fail".
+type upperEnricher struct{}
+
+func (upperEnricher) Name() string        { return "upper" }
+func (upperEnricher) Description() string { return "Upper-cases values" }
+
+func (upperEnricher) Enrich(ctx context.Context, value interface{}, options map[string]interface{}) (interface{}, error) {
+	s := fmt.Sprint(value)
+	if s == "fail" {
+		return nil, errors.New("lookup failed")
+	}
+	return strings.ToUpper(s), nil
+}
+
+func init() {
+	enrichers.RegisterEnricher(upperEnricher{})
+}
+
+func TestEnrichEvent(t *testing.T) {
+	es := []Enrichment{
+		{Enricher: "upper", Input: "user", Output: "loud"},
+		{Enricher: "upper", Input: "user", Output: "other", Event: &Event{Bee: "otherbee", Name: Wildcard}},
+		{Enricher: "upper", Input: "user", Output: "admin", If: `{{test eq .user "root"}}`},
+	}
+
+	options := Placeholders{{Name: "user", Type: "string", Value: "alice"}}
+	ev := Event{Bee: "enrichbee", Name: "login", Options: options}
+	enrichEvent(context.Background(), &ev, es)
+	if v := ev.Options.Value("loud"); v != "ALICE" {
+		t.Errorf("Expected an enriched placeholder, got %v", v)
+	}
+	if v := ev.Options.Value("other"); v != nil {
+		t.Errorf("Expected enrichments for other events to be skipped, got %v", v)
+	}
+	if v := ev.Options.Value("admin"); v != nil {
+		t.Errorf("Expected enrichments whose condition fails to be skipped, got %v", v)
+	}
+	if len(options) != 1 {
+		t.Errorf("Expected the original options to be left alone, got %v", options)
+	}
+
+	ev = Event{Bee: "enrichbee", Name: "login", Options: Placeholders{{Name: "user", Type: "string", Value: "fail"}}}
+	enrichEvent(context.Background(), &ev, es)
+	if len(ev.Options) != 1 {
+		t.Errorf("Expected failed lookups to leave the event alone, got %v", ev.Options)
+	}
+}
+
+func TestChainEnrichment(t *testing.T) {
+	bee := newRecordingBee("enrichbee")
+	defer DeleteBee(GetBee("enrichbee"))
+
+	oldActions := GetActions()
+	defer SetActions(oldActions)
+	SetActions([]Action{
+		{ID: "greet", Bee: "enrichbee", Name: "greet", Options: Placeholders{
+			{Name: "text", Type: "string", Value: "Hello {{.loud}}"},
+		}},
+	})
+
+	c := Chain{
+		Name:    "enriched",
+		Event:   &Event{Bee: "enrichbee", Name: "login"},
+		Enrich:  []Enrichment{{Enricher: "upper", Input: "user", Output: "loud"}},
+		Filters: []string{`{{test eq .loud "ALICE"}}`},
+		Actions: []string{"greet"},
+	}
+	if errs := ValidateChain(c); len(errs) > 0 {
+		t.Fatalf("Expected a valid chain, got %v", errs)
+	}
+
+	ev := &Event{Bee: "enrichbee", Name: "login", Options: Placeholders{{Name: "user", Type: "string", Value: "alice"}}}
+	if exec := execChain(context.Background(), c, ev, filterCache{}, false); exec == nil || exec.Err != nil {
+		t.Fatalf("Expected the enriched event to pass the chain's filters, got %+v", exec)
+	}
+	if text := bee.options[0].Value("text"); text != "Hello ALICE" {
+		t.Errorf("Expected the enriched placeholder in the action, got %v", text)
+	}
+	if v := ev.Options.Value("loud"); v != nil {
+		t.Errorf("Expected chain enrichments to be invisible to other chains, got %v", v)
+	}
+
+	c.Enrich = []Enrichment{{Enricher: "nosuchenricher", Input: "user", Output: "loud"}}
+	if errs := ValidateChain(c); len(errs) != 1 {
+		t.Errorf("Expected an unknown enricher to be reported, got %v", errs)
+	}
+}
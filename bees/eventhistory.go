@@ -0,0 +1,194 @@
+/*
+ *    Copyright (C) 2026 Christian Muehlhaeuser
+ *
+ *    This program is free software: you can redistribute it and/or modify
+ *    it under the terms of the GNU Affero General Public License as published
+ *    by the Free Software Foundation, either version 3 of the License, or
+ *    (at your option) any later version.
+ *
+ *    This program is distributed in the hope that it will be useful,
+ *    but WITHOUT ANY WARRANTY; without even the implied warranty of
+ *    MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ *    GNU Affero General Public License for more details.
+ *
+ *    You should have received a copy of the GNU Affero General Public License
+ *    along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ *    Authors:
+ *      Christian Muehlhaeuser <muesli@gmail.com>
+ */
+
+// Package bees is Beehive's central module system.
+package bees
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"sync"
+	"time"
+)
+
+// DefaultEventHistorySize is the default number of events kept in the
+// history of each bee.
+const DefaultEventHistorySize = 50
+
+// eventHistorySaveDelay is how long changes to the event history get
+// collected before they're written to the history file.
+const eventHistorySaveDelay = time.Second
+
+var (
+	eventHistory       = make(map[string][]LoggedEvent)
+	eventHistorySize   = DefaultEventHistorySize
+	eventHistoryPath   string
+	eventHistorySaving bool
+	eventHistoryMutex  sync.RWMutex
+)
+
+// SetEventHistorySize sets how many of its most recent events are kept for
+// each bee. Zero disables the event history.
+func SetEventHistorySize(size int) {
+	if size < 0 {
+		size = 0
+	}
+
+	eventHistoryMutex.Lock()
+	defer eventHistoryMutex.Unlock()
+
+	eventHistorySize = size
+	for bee, events := range eventHistory {
+		if len(events) > size {
+			eventHistory[bee] = append([]LoggedEvent{}, events[len(events)-size:]...)
+		}
+		if size == 0 {
+			delete(eventHistory, bee)
+		}
+	}
+}
+
+// SetEventHistoryFile persists the event history to path, so it survives
+// restarts of beehive. An existing history in path gets loaded. Changes are
+// written to the file shortly after they happened. An empty path stops
+// persisting the history.
+func SetEventHistoryFile(path string) error {
+	eventHistoryMutex.Lock()
+	defer eventHistoryMutex.Unlock()
+
+	eventHistoryPath = path
+	if path == "" {
+		return nil
+	}
+
+	b, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if len(b) == 0 {
+		return nil
+	}
+
+	loaded := make(map[string][]LoggedEvent)
+	if err := json.Unmarshal(b, &loaded); err != nil {
+		return err
+	}
+	for bee, events := range loaded {
+		events = append(events, eventHistory[bee]...)
+		if len(events) > eventHistorySize {
+			events = events[len(events)-eventHistorySize:]
+		}
+		if len(events) > 0 {
+			eventHistory[bee] = events
+		}
+	}
+
+	return nil
+}
+
+// EventHistory returns up to limit of the most recent events of a bee, oldest
+// first, along with the chains they triggered. A limit of zero or less
+// returns all of the bee's recorded events.
+func EventHistory(beeName string, limit int) []LoggedEvent {
+	eventHistoryMutex.RLock()
+	defer eventHistoryMutex.RUnlock()
+
+	events := eventHistory[beeName]
+	if limit > 0 && len(events) > limit {
+		events = events[len(events)-limit:]
+	}
+
+	r := make([]LoggedEvent, len(events))
+	for i, ev := range events {
+		ev.Chains = append([]string{}, ev.Chains...)
+		r[i] = ev
+	}
+
+	return r
+}
+
+// recordBeeEvent adds an event to the history of the bee which emitted it.
+func recordBeeEvent(event Event) {
+	eventHistoryMutex.Lock()
+	defer eventHistoryMutex.Unlock()
+
+	if eventHistorySize == 0 {
+		return
+	}
+	events := append(eventHistory[event.Bee], LoggedEvent{
+		Event:    event,
+		Received: event.received,
+	})
+	if len(events) > eventHistorySize {
+		events = events[len(events)-eventHistorySize:]
+	}
+	eventHistory[event.Bee] = events
+	scheduleEventHistorySave()
+}
+
+// setBeeEventChains stores the names of the chains an event in a bee's
+// history matched.
+func setBeeEventChains(bee, id string, chains []string) {
+	eventHistoryMutex.Lock()
+	defer eventHistoryMutex.Unlock()
+
+	events := eventHistory[bee]
+	for i := len(events) - 1; i >= 0; i-- {
+		if events[i].Event.ID == id {
+			events[i].Chains = chains
+			scheduleEventHistorySave()
+			return
+		}
+	}
+}
+
+// scheduleEventHistorySave writes the event history to its file after
+// eventHistorySaveDelay, unless a write is pending already. Must be called
+// with eventHistoryMutex held.
+func scheduleEventHistorySave() {
+	if eventHistoryPath == "" || eventHistorySaving {
+		return
+	}
+
+	eventHistorySaving = true
+	time.AfterFunc(eventHistorySaveDelay, func() {
+		if err := saveEventHistory(); err != nil {
+			logger.Errorf("Error saving event history: %v", err)
+		}
+	})
+}
+
+// saveEventHistory writes the event history to its file.
+func saveEventHistory() error {
+	eventHistoryMutex.Lock()
+	eventHistorySaving = false
+	path := eventHistoryPath
+	b, err := json.Marshal(eventHistory)
+	eventHistoryMutex.Unlock()
+
+	if err != nil || path == "" {
+		return err
+	}
+	return writeFileAtomically(path, b)
+}
@@ -0,0 +1,90 @@
+package bees
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestEventHistory(t *testing.T) {
+	newRecordingBee("historybee")
+	defer DeleteBee(GetBee("historybee"))
+	defer SetEventHistorySize(DefaultEventHistorySize)
+	SetEventHistorySize(2)
+
+	oldActions, oldChains := GetActions(), GetChains()
+	defer func() {
+		SetActions(oldActions)
+		chains = oldChains
+	}()
+	SetActions([]Action{{ID: "history-record", Bee: "historybee", Name: "record"}})
+	chains = []Chain{
+		{Name: "historian", Event: &Event{Bee: "historybee", Name: "trigger"}, Actions: []string{"history-record"}},
+	}
+
+	for _, name := range []string{"ignored", "ignored", "trigger"} {
+		handleEvent(context.Background(), Event{Bee: "historybee", Name: name})
+	}
+	// events of other bees don't push historybee's events out
+	for i := 0; i < 3; i++ {
+		handleEvent(context.Background(), Event{Bee: "otherhistorybee", Name: "noise"})
+	}
+	waitForWork("historybee", time.Second)
+	waitForWork("otherhistorybee", time.Second)
+
+	events := EventHistory("historybee", 0)
+	if len(events) != 2 {
+		t.Fatalf("Expected 2 events in the history, got %d", len(events))
+	}
+	if events[0].Event.Name != "ignored" || events[0].Chains[0] != NoMatch {
+		t.Errorf("Expected an unmatched event first, got %+v", events[0])
+	}
+	if events[1].Event.Name != "trigger" || events[1].Chains[0] != "historian" {
+		t.Errorf("Expected the triggering event last, got %+v", events[1])
+	}
+	if last := EventHistory("historybee", 1); len(last) != 1 || last[0].Event.ID != events[1].Event.ID {
+		t.Errorf("Expected only the most recent event, got %+v", last)
+	}
+	if n := len(EventHistory("nosuchbee", 0)); n != 0 {
+		t.Errorf("Expected no history for an unknown bee, got %d events", n)
+	}
+}
+
+func TestEventHistoryFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "beehive-history")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "history.json")
+	if err := SetEventHistoryFile(path); err != nil {
+		t.Fatal(err)
+	}
+	defer SetEventHistoryFile("")
+	defer func() {
+		eventHistoryMutex.Lock()
+		delete(eventHistory, "persistbee")
+		eventHistoryMutex.Unlock()
+	}()
+
+	recordBeeEvent(Event{ID: "persisted", Bee: "persistbee", Name: "ping"})
+	setBeeEventChains("persistbee", "persisted", []string{"pong"})
+	if err := saveEventHistory(); err != nil {
+		t.Fatal(err)
+	}
+
+	eventHistoryMutex.Lock()
+	delete(eventHistory, "persistbee")
+	eventHistoryMutex.Unlock()
+	if err := SetEventHistoryFile(path); err != nil {
+		t.Fatal(err)
+	}
+
+	events := EventHistory("persistbee", 0)
+	if len(events) != 1 || events[0].Event.ID != "persisted" || events[0].Chains[0] != "pong" {
+		t.Errorf("Expected the persisted event to be loaded, got %+v", events)
+	}
+}
@@ -0,0 +1,380 @@
+/*
+ *    Copyright (C) 2026 Christian Muehlhaeuser
+ *
+ *    This program is free software: you can redistribute it and/or modify
+ *    it under the terms of the GNU Affero General Public License as published
+ *    by the Free Software Foundation, either version 3 of the License, or
+ *    (at your option) any later version.
+ *
+ *    This program is distributed in the hope that it will be useful,
+ *    but WITHOUT ANY WARRANTY; without even the implied warranty of
+ *    MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ *    GNU Affero General Public License for more details.
+ *
+ *    You should have received a copy of the GNU Affero General Public License
+ *    along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ *    Authors:
+ *      Christian Muehlhaeuser <muesli@gmail.com>
+ */
+
+// Package bees is Beehive's central module system.
+package bees
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// OverflowPolicy decides what happens to events emitted while the event
+// queue is full.
+type OverflowPolicy int
+
+const (
+	// OverflowBlock blocks the emitting bee until there is room in the queue
+	OverflowBlock OverflowPolicy = iota
+	// OverflowDropOldest discards the oldest queued event to make room
+	OverflowDropOldest
+	// OverflowDropNewest discards the event being emitted
+	OverflowDropNewest
+	// OverflowSpill persists the event to disk until there is room. Only
+	// supported by the chain queue, see SetChainQueue; the event queue
+	// blocks instead
+	OverflowSpill
+)
+
+// Priorities bees can tag their events with, see Event.Priority. Queued
+// events of a higher priority get handled first, and a full queue drops
+// events of a lower priority to make room for them.
+const (
+	PriorityLow    = -1
+	PriorityNormal = 0
+	PriorityHigh   = 1
+)
+
+// DefaultEventQueueSize is the default number of events that can be queued
+// while the event handler is busy.
+const DefaultEventQueueSize = 256
+
+// EventQueueInfo describes the state of the event queue.
+type EventQueueInfo struct {
+	// Size is the capacity of the queue, 0 if unbuffered
+	Size int
+	// Length is the number of events currently queued
+	Length int
+	// Lanes holds the number of queued events per priority: "high",
+	// "normal" and "low"
+	Lanes map[string]int
+	// Dropped is the number of events discarded due to the overflow policy
+	Dropped int64
+	// Saturated is the number of times the queue ran full
+	Saturated int64
+}
+
+var (
+	queueSize      = DefaultEventQueueSize
+	queuePolicy    OverflowPolicy
+	eventQueue     *eventLanes
+	queueMutex     sync.RWMutex
+	droppedCount   int64
+	saturatedCount int64
+
+	// dispatching maps the channels handed to event loops to the queues
+	// feeding them, see abandonEventQueue.
+	dispatching = make(map[chan Event]*eventLanes)
+)
+
+// laneNames are the names of the queue's lanes, from the highest priority to
+// the lowest.
+var laneNames = [...]string{"high", "normal", "low"}
+
+// laneOf returns the index of the lane events of priority p get queued in.
+func laneOf(p int) int {
+	switch {
+	case p > PriorityNormal:
+		return 0
+	case p < PriorityNormal:
+		return 2
+	}
+	return 1
+}
+
+// SetEventQueueSize sets the number of events that can be queued while the
+// event handler is busy. A size of 0 hands events directly from bees to the
+// event handler, ignoring their priorities. Takes effect the next time bees
+// get started.
+func SetEventQueueSize(n int) {
+	queueMutex.Lock()
+	defer queueMutex.Unlock()
+
+	queueSize = n
+}
+
+// SetEventQueuePolicy sets the policy applied to events emitted while the
+// event queue is full. Only has an effect if the queue size is greater than 0.
+func SetEventQueuePolicy(p OverflowPolicy) {
+	queueMutex.Lock()
+	defer queueMutex.Unlock()
+
+	queuePolicy = p
+}
+
+// EventQueueStats returns information about the event queue.
+func EventQueueStats() EventQueueInfo {
+	queueMutex.RLock()
+	q := eventQueue
+	queueMutex.RUnlock()
+
+	info := EventQueueInfo{
+		Lanes:     make(map[string]int),
+		Dropped:   atomic.LoadInt64(&droppedCount),
+		Saturated: atomic.LoadInt64(&saturatedCount),
+	}
+	for _, name := range laneNames {
+		info.Lanes[name] = 0
+	}
+	if q != nil {
+		q.mutex.Lock()
+		info.Size = q.size
+		info.Length = q.length
+		for i, l := range q.lanes {
+			info.Lanes[laneNames[i]] = len(l)
+		}
+		q.mutex.Unlock()
+	}
+	return info
+}
+
+// openEventQueue sets up a fresh channel for bees to emit their events to and
+// returns the channel the event handler has to read the events from.
+func openEventQueue() chan Event {
+	queueMutex.Lock()
+	defer queueMutex.Unlock()
+
+	in, stopped := make(chan Event), make(chan struct{})
+	setEventChannel(in, stopped)
+	if queueSize <= 0 {
+		eventQueue = nil
+		return in
+	}
+
+	q := newEventLanes(queueSize, queuePolicy)
+	eventQueue = q
+	out := make(chan Event)
+	dispatching[out] = q
+	go pumpEvents(in, stopped, q)
+	go dispatchEvents(q, out)
+
+	return out
+}
+
+// abandonEventQueue discards the events queued for an event loop reading from
+// out, which stopped without draining them, and stops the goroutines feeding
+// it.
+func abandonEventQueue(out chan Event) {
+	queueMutex.RLock()
+	q, ok := dispatching[out]
+	queueMutex.RUnlock()
+
+	if ok {
+		q.abandon()
+	}
+}
+
+// eventQueueOpen returns whether events currently get queued.
+func eventQueueOpen() bool {
+	queueMutex.RLock()
+	defer queueMutex.RUnlock()
+
+	return eventQueue != nil
+}
+
+// pumpEvents moves events from in to q. Closes q once stopped gets closed.
+func pumpEvents(in chan Event, stopped chan struct{}, q *eventLanes) {
+	for {
+		select {
+		case event := <-in:
+			if !admitEvent(&event) {
+				logger.Debugf("Dropped event %s from bee %s: bee is being deleted", event.Name, event.Bee)
+				continue
+			}
+			if q.push(event) {
+				runHooks(eventQueueSaturatedHook, func(fn interface{}) {
+					fn.(func(EventQueueInfo))(EventQueueStats())
+				})
+			}
+		case <-stopped:
+			q.close()
+			return
+		}
+	}
+}
+
+// dispatchEvents hands the events queued in q to out, highest priority
+// first. Closes out once q got closed and drained, or abandoned.
+func dispatchEvents(q *eventLanes, out chan Event) {
+	defer func() {
+		queueMutex.Lock()
+		delete(dispatching, out)
+		queueMutex.Unlock()
+		close(out)
+	}()
+
+	for {
+		event, ok := q.pop()
+		if !ok {
+			return
+		}
+
+		select {
+		case out <- event:
+		case <-q.abandoned:
+			event.finish()
+			return
+		}
+	}
+}
+
+// eventLanes is a bounded queue of events with a lane per priority.
+type eventLanes struct {
+	mutex  sync.Mutex
+	cond   *sync.Cond
+	lanes  [len(laneNames)][]Event
+	size   int
+	length int
+	policy OverflowPolicy
+	full   bool
+	closed bool
+	// abandoned gets closed once nobody reads the queued events anymore
+	abandoned chan struct{}
+}
+
+func newEventLanes(size int, policy OverflowPolicy) *eventLanes {
+	q := &eventLanes{size: size, policy: policy, abandoned: make(chan struct{})}
+	q.cond = sync.NewCond(&q.mutex)
+	return q
+}
+
+// push queues an event. While the queue is full, the newest event of a lower
+// priority gets dropped to make room; if there is none, the overflow policy
+// applies. Events pushed to a closed queue get discarded. Returns whether the
+// queue ran full.
+func (q *eventLanes) push(event Event) bool {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	l := laneOf(event.Priority)
+	for !q.closed && q.length >= q.size {
+		if q.dropLower(l) {
+			break
+		}
+
+		switch q.policy {
+		case OverflowDropNewest:
+			atomic.AddInt64(&droppedCount, 1)
+			event.finish()
+			return false
+
+		case OverflowDropOldest:
+			if len(q.lanes[l]) == 0 {
+				// only events of a higher priority are queued
+				atomic.AddInt64(&droppedCount, 1)
+				event.finish()
+				return false
+			}
+			q.lanes[l][0].finish()
+			q.lanes[l] = q.lanes[l][1:]
+			q.length--
+			atomic.AddInt64(&droppedCount, 1)
+
+		default:
+			q.cond.Wait()
+		}
+	}
+	if q.closed {
+		event.finish()
+		return false
+	}
+
+	q.lanes[l] = append(q.lanes[l], event)
+	q.length++
+	q.cond.Broadcast()
+
+	if q.length >= q.size && !q.full {
+		q.full = true
+		atomic.AddInt64(&saturatedCount, 1)
+		return true
+	}
+	return false
+}
+
+// dropLower drops the newest event queued in the lowest lane below lane l.
+// Returns false if all those lanes are empty.
+func (q *eventLanes) dropLower(l int) bool {
+	for i := len(q.lanes) - 1; i > l; i-- {
+		if n := len(q.lanes[i]); n > 0 {
+			q.lanes[i][n-1].finish()
+			q.lanes[i] = q.lanes[i][:n-1]
+			q.length--
+			atomic.AddInt64(&droppedCount, 1)
+			return true
+		}
+	}
+	return false
+}
+
+// pop removes and returns the oldest event of the highest priority, waiting
+// for one to be queued. Returns false once the queue got closed and drained.
+func (q *eventLanes) pop() (Event, bool) {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for q.length == 0 && !q.closed {
+		q.cond.Wait()
+	}
+	for i, l := range q.lanes {
+		if len(l) > 0 {
+			event := l[0]
+			q.lanes[i] = l[1:]
+			q.length--
+			if q.length < q.size {
+				q.full = false
+			}
+			q.cond.Broadcast()
+			return event, true
+		}
+	}
+	return Event{}, false
+}
+
+// close makes pop return false once all queued events got handed out.
+func (q *eventLanes) close() {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	q.closed = true
+	q.cond.Broadcast()
+}
+
+// abandon closes the queue and discards all queued events.
+func (q *eventLanes) abandon() {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for i, l := range q.lanes {
+		for j := range l {
+			l[j].finish()
+		}
+		q.lanes[i] = nil
+	}
+	q.length = 0
+	q.full = false
+
+	select {
+	case <-q.abandoned:
+	default:
+		close(q.abandoned)
+	}
+	q.closed = true
+	q.cond.Broadcast()
+}
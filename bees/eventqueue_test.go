@@ -0,0 +1,171 @@
+package bees
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func pumpEventsThrough(q *eventLanes, events ...Event) ([]string, int64) {
+	dropped := atomic.LoadInt64(&droppedCount)
+
+	in, stopped := make(chan Event), make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		pumpEvents(in, stopped, q)
+		close(done)
+	}()
+	for _, ev := range events {
+		in <- ev
+	}
+	close(stopped)
+	<-done
+
+	r := []string{}
+	for {
+		ev, ok := q.pop()
+		if !ok {
+			break
+		}
+		r = append(r, ev.Name)
+	}
+	return r, atomic.LoadInt64(&droppedCount) - dropped
+}
+
+func pumpNames(policy OverflowPolicy, names ...string) ([]string, int64) {
+	events := []Event{}
+	for _, name := range names {
+		events = append(events, Event{Name: name})
+	}
+	return pumpEventsThrough(newEventLanes(2, policy), events...)
+}
+
+func TestEventQueueDropNewest(t *testing.T) {
+	names, dropped := pumpNames(OverflowDropNewest, "a", "b", "c", "d")
+	if len(names) != 2 || names[0] != "a" || names[1] != "b" || dropped != 2 {
+		t.Errorf("Expected [a b] with 2 dropped, got %v with %d dropped", names, dropped)
+	}
+}
+
+func TestEventQueueDropOldest(t *testing.T) {
+	names, dropped := pumpNames(OverflowDropOldest, "a", "b", "c", "d")
+	if len(names) != 2 || names[0] != "c" || names[1] != "d" || dropped != 2 {
+		t.Errorf("Expected [c d] with 2 dropped, got %v with %d dropped", names, dropped)
+	}
+}
+
+func TestEventQueueBlock(t *testing.T) {
+	in, stopped := make(chan Event), make(chan struct{})
+	q := newEventLanes(2, OverflowBlock)
+	go pumpEvents(in, stopped, q)
+
+	sent := make(chan struct{})
+	go func() {
+		for _, name := range []string{"a", "b", "c", "d"} {
+			in <- Event{Name: name}
+		}
+		close(sent)
+	}()
+
+	select {
+	case <-sent:
+		t.Fatal("Expected producer to block on a full queue")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	for _, exp := range []string{"a", "b", "c", "d"} {
+		if ev, _ := q.pop(); ev.Name != exp {
+			t.Errorf("Expected event %s, got %s", exp, ev.Name)
+		}
+	}
+	<-sent
+	close(stopped)
+}
+
+func TestEventQueuePriorities(t *testing.T) {
+	names, dropped := pumpEventsThrough(newEventLanes(3, OverflowBlock),
+		Event{Name: "low", Priority: PriorityLow},
+		Event{Name: "normal"},
+		Event{Name: "high", Priority: PriorityHigh},
+	)
+	if len(names) != 3 || names[0] != "high" || names[1] != "normal" || names[2] != "low" || dropped != 0 {
+		t.Errorf("Expected [high normal low], got %v with %d dropped", names, dropped)
+	}
+
+	// a full queue makes room for important events by dropping the newest
+	// low priority one, even though it would block otherwise
+	names, dropped = pumpEventsThrough(newEventLanes(2, OverflowBlock),
+		Event{Name: "low1", Priority: PriorityLow},
+		Event{Name: "low2", Priority: PriorityLow},
+		Event{Name: "normal"},
+		Event{Name: "high", Priority: PriorityHigh},
+	)
+	if len(names) != 2 || names[0] != "high" || names[1] != "normal" || dropped != 2 {
+		t.Errorf("Expected [high normal] with 2 dropped, got %v with %d dropped", names, dropped)
+	}
+
+	// low priority events never displace more important ones
+	names, dropped = pumpEventsThrough(newEventLanes(1, OverflowDropOldest),
+		Event{Name: "normal"},
+		Event{Name: "low", Priority: PriorityLow},
+	)
+	if len(names) != 1 || names[0] != "normal" || dropped != 1 {
+		t.Errorf("Expected [normal] with 1 dropped, got %v with %d dropped", names, dropped)
+	}
+}
+
+func TestEventQueueSaturation(t *testing.T) {
+	var infos []EventQueueInfo
+	h := OnEventQueueSaturated(func(info EventQueueInfo) {
+		infos = append(infos, info)
+	})
+	defer h.Remove()
+
+	saturated := atomic.LoadInt64(&saturatedCount)
+	pumpNames(OverflowDropNewest, "a", "b", "c", "d")
+	if len(infos) != 1 {
+		t.Fatalf("Expected the hook to be called once, got %d calls", len(infos))
+	}
+	if n := atomic.LoadInt64(&saturatedCount) - saturated; n != 1 {
+		t.Errorf("Expected the queue to be saturated once, got %d", n)
+	}
+	if s := EventQueueStats(); s.Saturated < 1 || len(s.Lanes) != 3 {
+		t.Errorf("Expected saturation and lanes in the stats, got %+v", s)
+	}
+}
+
+func TestEventQueueAbandon(t *testing.T) {
+	in, stopped := make(chan Event), make(chan struct{})
+	q := newEventLanes(1, OverflowBlock)
+	out := make(chan Event)
+	go pumpEvents(in, stopped, q)
+	dispatched := make(chan struct{})
+	go func() {
+		dispatchEvents(q, out)
+		close(dispatched)
+	}()
+
+	// nobody reads out, so the dispatcher holds the first event, the second
+	// one gets queued and the pump blocks on the third
+	events := []Event{}
+	for i := 0; i < 3; i++ {
+		ev := Event{Name: "queued", done: &eventDone{ch: make(chan struct{})}}
+		events = append(events, ev)
+		in <- ev
+	}
+
+	q.abandon()
+	for i, ev := range events {
+		select {
+		case <-ev.done.ch:
+		case <-time.After(time.Second):
+			t.Fatalf("Expected event %d to be finished once the queue got abandoned", i)
+		}
+	}
+	select {
+	case <-dispatched:
+	case <-time.After(time.Second):
+		t.Fatal("Expected the dispatcher to stop once the queue got abandoned")
+	}
+	close(stopped)
+}
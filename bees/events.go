@@ -22,10 +22,12 @@
 package bees
 
 import (
+	"context"
 	"fmt"
 	"runtime/debug"
-
-	log "github.com/sirupsen/logrus"
+	"sync"
+	"sync/atomic"
+	"time"
 )
 
 // An Event describes an event including its parameters.
@@ -33,42 +35,339 @@ type Event struct {
 	Bee     string
 	Name    string
 	Options Placeholders
+
+	// ID uniquely identifies the event. It gets assigned by the hive if a bee
+	// didn't set one.
+	ID string `json:",omitempty"`
+	// CorrelationID is shared by all events of a cascade, i.e. an event and
+	// all the events caused by it, directly or indirectly.
+	CorrelationID string `json:",omitempty"`
+	// CausationID is the ID of the event that directly caused this one.
+	CausationID string `json:",omitempty"`
+
+	// SLA is the time within which the chains handling this event should
+	// complete. Chains exceeding it emit an SLABreachEvent.
+	SLA time.Duration `json:",omitempty"`
+
+	// Replayed is set for events re-injected by ReplayEvent.
+	Replayed bool `json:",omitempty"`
+
+	// DryRun makes the chains handling this event only log the actions they
+	// would execute, as if they had DryRun set, see InjectEvent.
+	DryRun bool `json:",omitempty"`
+
+	// Priority of the event, see PriorityHigh and PriorityLow. Events of a
+	// higher priority get handled first while the event queue is busy.
+	Priority int `json:",omitempty"`
+
+	// ResultHops is the number of action result events that led to this
+	// one, see Chain.EmitResults.
+	ResultHops int `json:",omitempty"`
+
+	received  time.Time
+	trace     actionTrace
+	done      *eventDone
+	work      *eventWork
+	journaled bool
+	injected  *injection
 }
 
+const (
+	// SystemBee is the name events generated by the hive itself are emitted
+	// under.
+	SystemBee = "beehive"
+
+	// SLABreachEvent gets emitted by the SystemBee when a chain took longer to
+	// handle an event than permitted by the event's or the chain's SLA.
+	SLABreachEvent = "event.sla_breach"
+
+	// ChainTimeoutEvent gets emitted by the SystemBee when a chain's actions
+	// got abandoned because they exceeded the chain's timeout.
+	ChainTimeoutEvent = "chain_timeout"
+
+	// CycleDetectedEvent gets emitted by the SystemBee when a chain got
+	// aborted because its actions would have closed a feedback loop.
+	CycleDetectedEvent = "cycle_detected"
+)
+
 var (
-	eventsIn = make(chan Event)
+	// eventsIn is the channel bees emit their events to. It never gets
+	// closed, senders watch eventsStopped instead, which gets closed once the
+	// hive stopped handling events. Both get replaced by openEventQueue, so
+	// access them through eventChannel.
+	eventsIn      = make(chan Event)
+	eventsStopped = make(chan struct{})
+	eventsMutex   sync.RWMutex
+
+	cancelEventLoop = func() {}
+	eventLoopMutex  sync.Mutex
 )
 
+// eventChannel returns the channel bees emit their events to and a channel
+// that gets closed once the hive stopped handling them.
+func eventChannel() (chan Event, chan struct{}) {
+	eventsMutex.RLock()
+	defer eventsMutex.RUnlock()
+
+	return eventsIn, eventsStopped
+}
+
+// setEventChannel replaces the channels returned by eventChannel.
+func setEventChannel(in chan Event, stopped chan struct{}) {
+	eventsMutex.Lock()
+	defer eventsMutex.Unlock()
+
+	eventsIn = in
+	eventsStopped = stopped
+}
+
+// closeEventChannel tells all senders that the hive stopped handling events.
+func closeEventChannel() {
+	eventsMutex.Lock()
+	defer eventsMutex.Unlock()
+
+	select {
+	case <-eventsStopped:
+	default:
+		close(eventsStopped)
+	}
+}
+
+// deliverEvent hands an event to the event handler. Returns false if the hive
+// stopped handling events.
+func deliverEvent(event Event) bool {
+	return deliverEventContext(context.Background(), event)
+}
+
+// deliverEventContext hands an event to the event handler, giving up once ctx
+// is done. Returns false if the event didn't get delivered.
+func deliverEventContext(ctx context.Context, event Event) bool {
+	in, stopped := eventChannel()
+	select {
+	case <-stopped:
+		return false
+	case <-ctx.Done():
+		return false
+	default:
+	}
+
+	select {
+	case in <- event:
+		return true
+	case <-stopped:
+		return false
+	case <-ctx.Done():
+		return false
+	}
+}
+
 // handleEvents handles incoming events and executes matching Chains.
-func handleEvents() {
+func handleEvents(in chan Event) {
+	runEventLoop(eventLoopContext(context.Background()), in)
+}
+
+// eventLoopContext returns the context for a new event loop, which gets
+// cancelled by stopEventLoop.
+func eventLoopContext(parent context.Context) context.Context {
+	eventLoopMutex.Lock()
+	defer eventLoopMutex.Unlock()
+
+	ctx, cancel := context.WithCancel(parent)
+	cancelEventLoop = cancel
+	return ctx
+}
+
+// stopEventLoop stops the running event loop without closing the event
+// channel.
+func stopEventLoop() {
+	eventLoopMutex.Lock()
+	defer eventLoopMutex.Unlock()
+
+	cancelEventLoop()
+}
+
+// runEventLoop handles events arriving on in, until either in gets closed or
+// ctx gets cancelled.
+func runEventLoop(ctx context.Context, in chan Event) {
 	for {
-		event, ok := <-eventsIn
-		if !ok {
-			log.Println()
-			log.Println("Stopped event handler!")
-			break
+		select {
+		case <-ctx.Done():
+			abandonEventQueue(in)
+			logger.Infof("Stopped event handler!")
+			return
+
+		case event, ok := <-in:
+			if !ok {
+				logger.Infof("Stopped event handler!")
+				return
+			}
+
+			if !admitEvent(&event) {
+				logger.Debugf("Dropped event %s from bee %s: bee is being deleted", event.Name, event.Bee)
+				continue
+			}
+
+			if BeePaused(event.Bee) {
+				logger.Debugf("Dropped event %s from bee %s: bee is paused", event.Name, event.Bee)
+				event.finish()
+				continue
+			}
+			if !allowEvent(event) {
+				logger.Debugf("Dropped event %s from bee %s: rate limit exceeded", event.Name, event.Bee)
+				event.finish()
+				continue
+			}
+
+			handleEvent(ctx, event)
 		}
+	}
+}
+
+// handleEvent handles a single event and executes matching Chains.
+func handleEvent(ctx context.Context, event Event) {
+	if !event.journaled && isDuplicate(&event) {
+		logger.Debugf("Dropping duplicate event: %v / %v", event.Bee, event.Name)
+		event.finish()
+		return
+	}
+	if len(event.ID) == 0 {
+		event.ID = UUID()
+	}
+	if len(event.CorrelationID) == 0 {
+		event.CorrelationID = event.ID
+	}
+	if !event.DryRun && journalEvent(&event) {
+		event.finish()
+		return
+	}
+	if chainJobs.overflowing() && spillEvent(&event) {
+		event.finish()
+		return
+	}
+
+	event.received = clock.Now()
+	// events without a trace of their own inherit the trace of the action
+	// the bee is executing
+	if event.trace == nil {
+		event.trace = activeTrace(event.Bee)
+	}
+	ctx = withTrace(ctx, event.trace)
+
+	for _, err := range ValidateEvent(event) {
+		logger.Warnf("%v", err)
+	}
 
-		bee := GetBee(event.Bee)
+	description := "internal event"
+	bee := GetBee(event.Bee)
+	if bee != nil {
 		(*bee).LogEvent()
+		description = GetEventDescriptor(&event).Description
+	}
 
-		log.Debugln()
-		log.Debugln("Event received:", event.Bee, "/", event.Name, "-", GetEventDescriptor(&event).Description)
-		for _, v := range event.Options {
-			vv := truncateString(fmt.Sprintln(v), 1000)
-			log.Debugln("\tOptions:", vv)
+	if bee != nil {
+		logBeef(*bee, LogDebug, "Event received: %v - %v", event.Name, description)
+	} else {
+		logger.Debugf("Event received: %v / %v - %v", event.Bee, event.Name, description)
+	}
+	for _, v := range event.Options {
+		vv := truncateString(fmt.Sprintln(v), 1000)
+		if bee != nil {
+			logBeef(*bee, LogDebug, "\tOptions: %v", vv)
+		} else {
+			logger.Debugf("\tOptions: %v", vv)
 		}
+	}
+	notifyWatchers(event)
+	eventHandled(event)
+	recordEvent(event)
+	captureEvent(event)
+	cancelDelayedActions(&event)
 
-		go func() {
-			defer func() {
-				if e := recover(); e != nil {
-					log.Printf("Fatal chain event: %s %s", e, debug.Stack())
-				}
-			}()
+	release, ok := acquireChainSlot()
+	if !ok {
+		logger.Debugf("Dropping event due to chain concurrency limit: %v / %v", event.Bee, event.Name)
+		event.finish()
+		return
+	}
 
-			execChains(&event)
+	beginWork(event.Bee)
+	chainJobs.submit(func() {
+		defer event.finish()
+		defer release()
+		atomic.AddInt64(&chainWorkers, 1)
+		defer atomic.AddInt64(&chainWorkers, -1)
+		defer endWork(event.Bee)
+		defer func() {
+			if e := recover(); e != nil {
+				logger.Errorf("Fatal chain event: %s %s", e, debug.Stack())
+				deadLetter(event, fmt.Errorf("%v", e))
+			}
 		}()
+
+		enrichEvent(ctx, &event, GetEnrichments())
+		matched := execChains(ctx, &event)
+		setEventChains(&event, matched)
+		event.recordChains(matched)
+	}, func() {
+		logger.Warnf("Dropping event due to a full chain queue: %v / %v", event.Bee, event.Name)
+		endWork(event.Bee)
+		release()
+		event.finish()
+	})
+}
+
+// deriveEvent links an event to the event that caused it: the causation ID
+// gets set to the cause's ID, and the cause's correlation ID is propagated.
+func deriveEvent(cause *Event, event Event) Event {
+	if cause != nil {
+		event.CausationID = cause.ID
+		event.CorrelationID = cause.CorrelationID
+		event.trace = cause.trace
+	}
+
+	return event
+}
+
+// checkSLA emits an SLABreachEvent if a chain took longer to handle an event
+// than permitted by its SLA. The chain's SLA takes precedence over the event's.
+// The breach event gets injected asynchronously: the caller still holds a
+// chain slot, which the event handler might be waiting for.
+func checkSLA(c *Chain, event *Event, started time.Time) {
+	sla := event.SLA
+	if c.SLA > 0 {
+		sla = c.SLA
+	}
+	if sla <= 0 {
+		return
+	}
+
+	if !event.received.IsZero() {
+		started = event.received
 	}
+	elapsed := clock.Now().Sub(started)
+	if elapsed <= sla {
+		return
+	}
+
+	logger.Infof("Chain %v breached its SLA of %v handling event %v", c.Name, sla, event.ID)
+	go injectEvent(deriveEvent(event, Event{
+		Bee:  SystemBee,
+		Name: SLABreachEvent,
+		Options: Placeholders{
+			{Name: "chain", Type: "string", Value: c.Name},
+			{Name: "event_bee", Type: "string", Value: event.Bee},
+			{Name: "event_name", Type: "string", Value: event.Name},
+			{Name: "event_id", Type: "string", Value: event.ID},
+			{Name: "sla", Type: "string", Value: sla.String()},
+			{Name: "elapsed", Type: "string", Value: elapsed.String()},
+		},
+	}))
+}
+
+// injectEvent feeds an event generated by the hive itself into the event
+// handler. The event is discarded if the handler has already been stopped.
+func injectEvent(event Event) {
+	deliverEvent(event)
 }
 
 func truncateString(str string, num int) string {
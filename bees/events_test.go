@@ -0,0 +1,240 @@
+package bees
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestCheckSLA(t *testing.T) {
+	fc := &fakeClock{now: time.Now()}
+	SetClock(fc)
+	defer SetClock(nil)
+
+	events := make(chan Event, 1)
+	defer useEventChannel(events)()
+
+	c := Chain{Name: "alerts"}
+	event := Event{ID: "1", Bee: "sensorbee", Name: "alarm", SLA: time.Second, received: fc.now}
+
+	fc.now = fc.now.Add(500 * time.Millisecond)
+	checkSLA(&c, &event, fc.now)
+	if len(events) != 0 {
+		t.Fatal("Expected no breach within the event's SLA")
+	}
+
+	c.SLA = 100 * time.Millisecond
+	checkSLA(&c, &event, fc.now)
+	var ev Event
+	select {
+	case ev = <-events:
+	case <-time.After(time.Second):
+		t.Fatal("Expected the chain's SLA to take precedence")
+	}
+
+	if ev.Bee != SystemBee || ev.Name != SLABreachEvent || ev.CausationID != "1" {
+		t.Errorf("Unexpected breach event: %+v", ev)
+	}
+	if ev.Options.Value("chain") != "alerts" {
+		t.Errorf("Expected breach event to carry the chain name, got %v", ev.Options.Value("chain"))
+	}
+}
+
+func TestCheckSLAChainLimit(t *testing.T) {
+	SetMaxConcurrentChains(1)
+	defer SetMaxConcurrentChains(0)
+
+	events := make(chan Event)
+	defer useEventChannel(events)()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go runEventLoop(ctx, events)
+
+	mod := newGatedBee("slabee")
+	defer DeleteBee(GetBee("slabee"))
+
+	oldActions, oldChains := GetActions(), GetChains()
+	defer func() {
+		SetActions(oldActions)
+		SetChains(oldChains)
+	}()
+	SetActions([]Action{{ID: "record", Bee: "slabee", Name: "record"}})
+	SetChains([]Chain{
+		{Name: "slow", Event: &Event{Bee: "slasource", Name: "trigger"}, Actions: []string{"record"}, SLA: time.Nanosecond},
+	})
+
+	// the second event waits for the chain slot held by the first one, which
+	// breaches its SLA
+	events <- Event{Bee: "slasource", Name: "trigger"}
+	events <- Event{Bee: "slasource", Name: "trigger"}
+	time.Sleep(20 * time.Millisecond)
+	close(mod.gate)
+
+	if !waitForWork("slasource", time.Second) || !waitForWork("slabee", time.Second) {
+		t.Fatal("Expected the SLA breach not to block the event handler")
+	}
+	if got := mod.executed(); len(got) != 2 {
+		t.Errorf("Expected both events to be handled, got %v", got)
+	}
+}
+
+func TestRecentEvents(t *testing.T) {
+	bee := newRecordingBee("replaybee")
+	defer DeleteBee(GetBee("replaybee"))
+	defer SetRecentEventsSize(DefaultRecentEventsSize)
+	SetRecentEventsSize(2)
+
+	oldActions, oldChains := GetActions(), GetChains()
+	defer func() {
+		SetActions(oldActions)
+		chains = oldChains
+	}()
+	SetActions([]Action{{ID: "record", Bee: "replaybee", Name: "record"}})
+	chains = []Chain{
+		{Name: "recorder", Event: &Event{Bee: "replaybee", Name: "trigger"}, Actions: []string{"record"}},
+	}
+
+	for _, name := range []string{"ignored", "ignored", "trigger"} {
+		handleEvent(context.Background(), Event{Bee: "replaybee", Name: name})
+	}
+	waitForWork("replaybee", time.Second)
+
+	events := GetRecentEvents(0)
+	if len(events) != 2 {
+		t.Fatalf("Expected 2 recent events, got %d", len(events))
+	}
+	if events[0].Chains[0] != NoMatch || events[1].Chains[0] != "recorder" {
+		t.Errorf("Unexpected matched chains: %v, %v", events[0].Chains, events[1].Chains)
+	}
+	if last := GetRecentEvents(1); len(last) != 1 || last[0].Event.ID != events[1].Event.ID {
+		t.Errorf("Expected only the most recent event, got %+v", last)
+	}
+
+	if err := ReplayEvent(events[1].Event.ID); err != nil {
+		t.Fatal(err)
+	}
+	if got := bee.executed(); len(got) != 2 {
+		t.Errorf("Expected replayed event to execute the chain again, got %v", got)
+	}
+	if err := ReplayEvent("unknown"); err == nil {
+		t.Error("Expected error replaying an unknown event")
+	}
+}
+
+func TestDeadLetterQueue(t *testing.T) {
+	newRecordingBee("deadletterbee")
+	defer DeleteBee(GetBee("deadletterbee"))
+
+	q := NewDeadLetterQueue(2)
+	SetDeadLetterQueue(q)
+	defer SetDeadLetterQueue(nil)
+
+	oldActions := GetActions()
+	defer SetActions(oldActions)
+	SetActions([]Action{{ID: "fail", Bee: "deadletterbee", Name: "fail"}})
+
+	c := Chain{Name: "failing", Actions: []string{"fail"}}
+	for _, id := range []string{"1", "2", "3"} {
+		runChain(context.Background(), c, &Event{ID: id, Bee: "deadletterbee", Name: "trigger"}, map[string]interface{}{})
+	}
+
+	entries := q.Entries()
+	if len(entries) != 2 || entries[0].Event.ID != "2" || entries[1].Event.ID != "3" {
+		t.Fatalf("Expected the two most recent failures, got %+v", entries)
+	}
+	if entries[1].Err == nil {
+		t.Error("Expected failure to carry its error")
+	}
+
+	events := make(chan Event, 2)
+	defer useEventChannel(events)()
+
+	if n := q.Replay(1); n != 1 {
+		t.Errorf("Expected to replay one event, replayed %d", n)
+	}
+	ev := <-events
+	if !ev.Replayed || ev.CausationID != "3" {
+		t.Errorf("Unexpected replayed event: %+v", ev)
+	}
+	if len(q.Entries()) != 1 {
+		t.Error("Expected replayed entry to be removed from the queue")
+	}
+}
+
+func TestDedupWindow(t *testing.T) {
+	fc := &fakeClock{now: time.Now()}
+	SetClock(fc)
+	defer SetClock(nil)
+
+	ev := &Event{Bee: "fsbee", Name: "changed", Options: Placeholders{{Name: "path", Value: "/tmp/a"}}}
+	other := &Event{Bee: "fsbee", Name: "changed", Options: Placeholders{{Name: "path", Value: "/tmp/b"}}}
+	if isDuplicate(ev) || isDuplicate(ev) {
+		t.Fatal("Expected no deduplication by default")
+	}
+
+	SetDedupWindow(time.Second)
+	defer SetDedupWindow(0)
+	dropped := DuplicatesDropped()
+
+	if isDuplicate(ev) {
+		t.Error("Expected first event to pass")
+	}
+	if !isDuplicate(ev) {
+		t.Error("Expected identical event to be dropped")
+	}
+	if isDuplicate(other) {
+		t.Error("Expected event with different options to pass")
+	}
+	fc.now = fc.now.Add(2 * time.Second)
+	if isDuplicate(ev) {
+		t.Error("Expected event to pass after the window passed")
+	}
+	if n := DuplicatesDropped() - dropped; n != 1 {
+		t.Errorf("Expected 1 dropped duplicate, got %d", n)
+	}
+
+	SetDedupMaxEntries(1)
+	defer SetDedupMaxEntries(DefaultDedupMaxEntries)
+	isDuplicate(other)
+	if isDuplicate(ev) {
+		t.Error("Expected evicted event to pass")
+	}
+}
+
+func TestBeeDedup(t *testing.T) {
+	fc := &fakeClock{now: time.Now()}
+	SetClock(fc)
+	defer SetClock(nil)
+
+	SetBeeDedup("rssbee", time.Minute, "url")
+	defer RemoveBeeDedup("rssbee")
+
+	item := func(url, fetched string) *Event {
+		return &Event{Bee: "rssbee", Name: "item", Options: Placeholders{
+			{Name: "url", Value: url},
+			{Name: "fetched", Value: fetched},
+		}}
+	}
+	if isDuplicate(item("http://a", "10:00")) {
+		t.Error("Expected first event to pass")
+	}
+	if !isDuplicate(item("http://a", "10:05")) {
+		t.Error("Expected refetched event to be dropped, ignoring other placeholders")
+	}
+	if isDuplicate(item("http://b", "10:05")) {
+		t.Error("Expected event with a different URL to pass")
+	}
+
+	ev := &Event{Bee: "otherbee", Name: "item"}
+	if isDuplicate(ev) || isDuplicate(ev) {
+		t.Error("Expected other bees not to be deduplicated")
+	}
+
+	SetDedupWindow(time.Minute)
+	defer SetDedupWindow(0)
+	SetBeeDedup("otherbee", 0)
+	defer RemoveBeeDedup("otherbee")
+	if isDuplicate(ev) || isDuplicate(ev) {
+		t.Error("Expected bee with a window of 0 not to be deduplicated")
+	}
+}
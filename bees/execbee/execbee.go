@@ -25,11 +25,15 @@
 package execbee
 
 import (
-	"bufio"
+	"bytes"
+	"context"
 	"encoding/csv"
-	"io"
+	"errors"
+	"os"
 	"os/exec"
+	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/muesli/beehive/bees"
 )
@@ -38,106 +42,99 @@ import (
 type ExecBee struct {
 	bees.Bee
 
+	allowed    []string
+	allowedEnv []string
+	workdir    string
+	env        []string
+	timeout    time.Duration
+
+	watchCommand  string
+	watchInterval time.Duration
+
 	eventChan chan bees.Event
 }
 
+// A result describes a finished command.
+type result struct {
+	stdout   string
+	stderr   string
+	exitCode int
+}
+
 // Action triggers the action passed to it.
-func (mod *ExecBee) Action(action bees.Action) []bees.Placeholder {
+func (mod *ExecBee) Action(ctx context.Context, action bees.Action) []bees.Placeholder {
 	outs := []bees.Placeholder{}
 
 	switch action.Name {
 	case "execute":
 		var command string
+		var args []string
 		var stdin string
+		workdir := mod.workdir
+		env := []string{}
+		timeout := mod.timeout
 
 		action.Options.Bind("command", &command)
+		action.Options.Bind("args", &args)
 		action.Options.Bind("stdin", &stdin)
+		action.Options.Bind("workdir", &workdir)
+		action.Options.Bind("env", &env)
+		var t string
+		action.Options.Bind("timeout", &t)
+		if t != "" {
+			d, err := time.ParseDuration(t)
+			if err != nil {
+				mod.LogErrorf("Invalid timeout %s: %v", t, err)
+				return outs
+			}
+			timeout = d
+		}
+		workdir, err := mod.restrict(workdir, env)
+		if err != nil {
+			mod.LogErrorf("Not executing %s: %v", command, err)
+			return outs
+		}
 		mod.Logln("Executing locally: ", command)
 
 		go func() {
-			r := csv.NewReader(strings.NewReader(command))
-			r.Comma = ' ' // space
-			c, err := r.Read()
+			res, err := mod.run(mod.Context(), command, args, stdin, workdir, env, timeout)
 			if err != nil {
-				mod.LogErrorf("Error parsing command: %v", err)
-				return
-			}
-
-			cmd := exec.Command(c[0], c[1:]...)
-
-			if stdin != "" {
-				stdinPipe, err := cmd.StdinPipe()
-
-				if err != nil {
-					mod.LogErrorf("Error creating stdinPipe for Cmd: %v", err)
+				mod.LogErrorf("Error executing %s: %v", command, err)
+				if res == nil {
 					return
 				}
-
-				go func() {
-					io.WriteString(stdinPipe, stdin)
-					stdinPipe.Close()
-				}()
-			}
-
-			// read and print stdout
-			outReader, err := cmd.StdoutPipe()
-			if err != nil {
-				mod.LogErrorf("Error creating StdoutPipe for Cmd: %v", err)
-				return
-			}
-			outBuffer := []string{}
-			outScanner := bufio.NewScanner(outReader)
-			go func() {
-				for outScanner.Scan() {
-					foo := outScanner.Text()
-					mod.Logln("execbee: std: | ", foo)
-					outBuffer = append(outBuffer, foo)
-				}
-			}()
-
-			// read and print stderr
-			errReader, err := cmd.StderrPipe()
-			if err != nil {
-				mod.LogErrorf("Error creating StderrPipe for Cmd: %v", err)
-				return
-			}
-			errBuffer := []string{}
-			errScanner := bufio.NewScanner(errReader)
-			go func() {
-				for errScanner.Scan() {
-					foo := errScanner.Text()
-					mod.Logln("Err: | ", foo)
-					errBuffer = append(errBuffer, foo)
-				}
-			}()
-
-			err = cmd.Start()
-			if err != nil {
-				mod.LogErrorf("Error starting Cmd: %v", err)
-			}
-
-			err = cmd.Wait()
-			if err != nil {
-				mod.LogErrorf("Error waiting for Cmd: %v", err)
 			}
 
 			ev := bees.Event{
 				Bee:  mod.Name(),
 				Name: "result",
 				Options: []bees.Placeholder{
+					{
+						Name:  "command",
+						Type:  "string",
+						Value: command,
+					},
+					{
+						Name:  "exit_code",
+						Type:  "int",
+						Value: res.exitCode,
+					},
 					{
 						Name:  "stdout",
 						Type:  "string",
-						Value: strings.Join(outBuffer, "\n"),
+						Value: res.stdout,
 					},
 					{
 						Name:  "stderr",
 						Type:  "string",
-						Value: strings.Join(errBuffer, "\n"),
+						Value: res.stderr,
 					},
 				},
 			}
-			mod.eventChan <- ev
+			select {
+			case mod.eventChan <- ev:
+			case <-mod.Context().Done():
+			}
 		}()
 
 	default:
@@ -147,12 +144,193 @@ func (mod *ExecBee) Action(action bees.Action) []bees.Placeholder {
 	return outs
 }
 
+// run executes command with args appended to the command's own arguments.
+// The command gets killed once ctx is done, e.g. when the bee stops. A
+// non-nil result is returned for commands that ran, even if they timed out.
+func (mod *ExecBee) run(ctx context.Context, command string, args []string, stdin, workdir string, env []string, timeout time.Duration) (*result, error) {
+	r := csv.NewReader(strings.NewReader(command))
+	r.Comma = ' ' // space
+	c, err := r.Read()
+	if err != nil {
+		return nil, err
+	}
+	c = append(c, args...)
+
+	if !mod.permitted(c[0]) {
+		return nil, errors.New("Command " + c[0] + " is not in the list of allowed commands")
+	}
+
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	cmd := exec.CommandContext(ctx, c[0], c[1:]...)
+	cmd.Dir = workdir
+	if len(mod.env) > 0 || len(env) > 0 {
+		cmd.Env = append(append(os.Environ(), mod.env...), env...)
+	}
+	if stdin != "" {
+		cmd.Stdin = strings.NewReader(stdin)
+	}
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	err = cmd.Run()
+	res := &result{
+		stdout: strings.TrimRight(stdout.String(), "\n"),
+		stderr: strings.TrimRight(stderr.String(), "\n"),
+	}
+	if cmd.ProcessState != nil {
+		res.exitCode = cmd.ProcessState.ExitCode()
+	} else {
+		return nil, err
+	}
+	if ctx.Err() == context.DeadlineExceeded {
+		return res, errors.New("Timed out after " + timeout.String())
+	}
+	if ctx.Err() != nil {
+		return res, ctx.Err()
+	}
+	if _, ok := err.(*exec.ExitError); ok {
+		// a non-zero exit code is reported in the result
+		err = nil
+	}
+
+	return res, err
+}
+
+// restrict applies the allowlist to an action's workdir and env. Without it,
+// actions could set e.g. LD_PRELOAD or PATH to run arbitrary code under an
+// allowed command, so once allowed_commands is set, actions always run in the
+// bee's workdir and may only set the variables listed in allowed_env.
+func (mod *ExecBee) restrict(workdir string, env []string) (string, error) {
+	if len(mod.allowed) == 0 {
+		return workdir, nil
+	}
+
+	if workdir != mod.workdir {
+		mod.LogErrorf("Ignoring workdir %s, actions can't override the workdir of a restricted bee", workdir)
+	}
+	for _, e := range env {
+		key := strings.SplitN(e, "=", 2)[0]
+		if !contains(mod.allowedEnv, key) {
+			return mod.workdir, errors.New("Environment variable " + key + " is not in the list of allowed variables")
+		}
+	}
+
+	return mod.workdir, nil
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// permitted returns whether name may be executed. Without an allowlist all
+// commands are permitted. Entries either match the full path of a command or,
+// if they don't contain a path separator, its base name.
+func (mod *ExecBee) permitted(name string) bool {
+	if len(mod.allowed) == 0 {
+		return true
+	}
+
+	for _, a := range mod.allowed {
+		if a == name {
+			return true
+		}
+		if !strings.ContainsRune(a, filepath.Separator) && !strings.ContainsRune(name, filepath.Separator) && a == filepath.Base(name) {
+			return true
+		}
+	}
+
+	return false
+}
+
 // Run executes the Bee's event loop.
-func (mod *ExecBee) Run(eventChan chan bees.Event) {
+func (mod *ExecBee) Run(ctx context.Context, eventChan chan bees.Event) {
 	mod.eventChan = eventChan
+
+	if mod.watchCommand == "" {
+		return
+	}
+
+	var last *result
+	for {
+		res, err := mod.run(ctx, mod.watchCommand, nil, "", mod.workdir, nil, mod.timeout)
+		if err != nil {
+			mod.LogErrorf("Error executing %s: %v", mod.watchCommand, err)
+		}
+		if res != nil && (last == nil || *res != *last) {
+			last = res
+			ev := bees.Event{
+				Bee:  mod.Name(),
+				Name: "output",
+				Options: []bees.Placeholder{
+					{
+						Name:  "command",
+						Type:  "string",
+						Value: mod.watchCommand,
+					},
+					{
+						Name:  "exit_code",
+						Type:  "int",
+						Value: res.exitCode,
+					},
+					{
+						Name:  "stdout",
+						Type:  "string",
+						Value: res.stdout,
+					},
+					{
+						Name:  "stderr",
+						Type:  "string",
+						Value: res.stderr,
+					},
+				},
+			}
+			select {
+			case mod.eventChan <- ev:
+			case <-ctx.Done():
+				return
+			}
+		}
+
+		select {
+		case <-mod.SigChan:
+			return
+		case <-ctx.Done():
+			return
+		case <-time.After(mod.watchInterval):
+		}
+	}
 }
 
 // ReloadOptions parses the config options and initializes the Bee.
 func (mod *ExecBee) ReloadOptions(options bees.BeeOptions) {
 	mod.SetOptions(options)
+
+	mod.allowed = nil
+	options.Bind("allowed_commands", &mod.allowed)
+	mod.allowedEnv = nil
+	options.Bind("allowed_env", &mod.allowedEnv)
+	mod.workdir = ""
+	options.Bind("workdir", &mod.workdir)
+	mod.env = nil
+	options.Bind("env", &mod.env)
+	mod.timeout, _ = options.GetDuration("timeout")
+
+	mod.watchCommand = ""
+	options.Bind("watch_command", &mod.watchCommand)
+	mod.watchInterval = time.Minute
+	if d, err := options.GetDuration("watch_interval"); err == nil && d > 0 {
+		mod.watchInterval = d
+	}
 }
@@ -0,0 +1,174 @@
+package execbee
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/muesli/beehive/bees"
+)
+
+func TestRunStoppedBee(t *testing.T) {
+	factory := ExecBeeFactory{}
+	mod := factory.New("execbee", "", bees.BeeOptions{}).(*ExecBee)
+	mod.Start()
+
+	done := make(chan error)
+	go func() {
+		_, err := mod.run(mod.Context(), "sleep 10", nil, "", "", nil, 0)
+		done <- err
+	}()
+
+	time.Sleep(50 * time.Millisecond)
+	mod.Stop()
+	select {
+	case err := <-done:
+		if err != context.Canceled {
+			t.Errorf("Expected the killed command to be cancelled, got %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Expected the command to be killed when the bee stops")
+	}
+}
+
+func TestWatchStoppedBee(t *testing.T) {
+	factory := ExecBeeFactory{}
+	mod := factory.New("execbee", "", bees.BeeOptions{
+		{Name: "watch_command", Value: "echo hello"},
+	}).(*ExecBee)
+
+	// nobody handles the bee's events anymore
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		mod.Run(ctx, make(chan bees.Event))
+		close(done)
+	}()
+
+	time.Sleep(50 * time.Millisecond)
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Expected the watch loop to return when the bee stops")
+	}
+}
+
+func TestPermitted(t *testing.T) {
+	factory := ExecBeeFactory{}
+	mod := factory.New("execbee", "", bees.BeeOptions{
+		{Name: "allowed_commands", Value: []string{"echo", "/bin/cat"}},
+	}).(*ExecBee)
+
+	tests := []struct {
+		name      string
+		permitted bool
+	}{
+		{"echo", true},
+		{"/bin/cat", true},
+		{"cat", false},
+		{"/bin/echo", false},
+		{"/tmp/echo", false},
+		{"sh", false},
+	}
+	for _, tt := range tests {
+		if p := mod.permitted(tt.name); p != tt.permitted {
+			t.Errorf("Expected permitted(%s) to be %v, got %v", tt.name, tt.permitted, p)
+		}
+	}
+
+	mod.ReloadOptions(bees.BeeOptions{})
+	if !mod.permitted("sh") {
+		t.Error("Expected all commands to be permitted without an allowlist")
+	}
+}
+
+func TestRestrict(t *testing.T) {
+	factory := ExecBeeFactory{}
+	mod := factory.New("execbee", "", bees.BeeOptions{
+		{Name: "workdir", Value: "/tmp"},
+	}).(*ExecBee)
+
+	if workdir, err := mod.restrict("/", []string{"LD_PRELOAD=evil.so"}); err != nil || workdir != "/" {
+		t.Errorf("Expected an unrestricted bee to accept the action's workdir and env, got %s, %v", workdir, err)
+	}
+
+	mod.ReloadOptions(bees.BeeOptions{
+		{Name: "allowed_commands", Value: []string{"echo"}},
+		{Name: "allowed_env", Value: []string{"LANG"}},
+		{Name: "workdir", Value: "/tmp"},
+	})
+	tests := []struct {
+		env   []string
+		valid bool
+	}{
+		{nil, true},
+		{[]string{"LANG=C"}, true},
+		{[]string{"LANG=C", "LD_PRELOAD=evil.so"}, false},
+		{[]string{"PATH=/tmp"}, false},
+		{[]string{"LANG"}, true},
+	}
+	for _, tt := range tests {
+		workdir, err := mod.restrict("/", tt.env)
+		if (err == nil) != tt.valid {
+			t.Errorf("Expected env %v to be valid: %v, got %v", tt.env, tt.valid, err)
+		}
+		if workdir != "/tmp" {
+			t.Errorf("Expected the bee's workdir to be kept, got %s", workdir)
+		}
+	}
+}
+
+func TestRun(t *testing.T) {
+	factory := ExecBeeFactory{}
+	mod := factory.New("execbee", "", bees.BeeOptions{}).(*ExecBee)
+
+	res, err := mod.run(context.Background(), "sh -c", []string{"echo out; echo err >&2; exit 3"}, "", "", nil, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.exitCode != 3 {
+		t.Errorf("Expected exit code 3, got %d", res.exitCode)
+	}
+	if res.stdout != "out" || res.stderr != "err" {
+		t.Errorf("Expected stdout and stderr to be captured, got %q and %q", res.stdout, res.stderr)
+	}
+
+	res, err = mod.run(context.Background(), "cat", nil, "hello\n", "", nil, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.exitCode != 0 || res.stdout != "hello" {
+		t.Errorf("Expected stdin to be passed on, got %+v", res)
+	}
+
+	res, err = mod.run(context.Background(), "sh -c", []string{"echo $GREETING"}, "", "", []string{"GREETING=hi"}, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.stdout != "hi" {
+		t.Errorf("Expected env to be passed on, got %q", res.stdout)
+	}
+
+	mod.ReloadOptions(bees.BeeOptions{{Name: "allowed_commands", Value: []string{"echo"}}})
+	if _, err := mod.run(context.Background(), "cat", nil, "", "", nil, 0); err == nil {
+		t.Error("Expected commands outside the allowlist to be refused")
+	}
+}
+
+func TestRunTimeout(t *testing.T) {
+	factory := ExecBeeFactory{}
+	mod := factory.New("execbee", "", bees.BeeOptions{}).(*ExecBee)
+
+	start := time.Now()
+	res, err := mod.run(context.Background(), "sleep 10", nil, "", "", nil, 50*time.Millisecond)
+	if err == nil || err.Error() != "Timed out after 50ms" {
+		t.Errorf("Expected a timeout error, got %v", err)
+	}
+	if res == nil {
+		t.Error("Expected a result for the killed command")
+	}
+	if time.Since(start) > 5*time.Second {
+		t.Error("Expected the command to be killed after the timeout")
+	}
+}
@@ -67,6 +67,49 @@ func (factory *ExecBeeFactory) LogoColor() string {
 	return "#be1728"
 }
 
+// Options returns the options available to configure this Bee.
+func (factory *ExecBeeFactory) Options() []bees.BeeOptionDescriptor {
+	opts := []bees.BeeOptionDescriptor{
+		{
+			Name:        "allowed_commands",
+			Description: "Commands this bee may execute, either full paths or names looked up in PATH. Empty allows all commands",
+			Type:        "[]string",
+		},
+		{
+			Name:        "allowed_env",
+			Description: "Environment variables actions may set when allowed_commands is set, eg: LANG",
+			Type:        "[]string",
+		},
+		{
+			Name:        "workdir",
+			Description: "Working directory for commands",
+			Type:        "string",
+		},
+		{
+			Name:        "env",
+			Description: "Additional environment variables for commands, eg: LANG=C",
+			Type:        "[]string",
+		},
+		{
+			Name:        "timeout",
+			Description: "Time after which commands get killed, eg: 30s. Empty disables the timeout",
+			Type:        "duration",
+		},
+		{
+			Name:        "watch_command",
+			Description: "Command to run periodically, emitting an output event whenever its output changes (optional)",
+			Type:        "string",
+		},
+		{
+			Name:        "watch_interval",
+			Description: "How often to run the watch command, eg: 5m",
+			Type:        "duration",
+			Default:     "1m",
+		},
+	}
+	return opts
+}
+
 // Events describes the available events provided by this Bee.
 func (factory *ExecBeeFactory) Events() []bees.EventDescriptor {
 	events := []bees.EventDescriptor{
@@ -75,6 +118,43 @@ func (factory *ExecBeeFactory) Events() []bees.EventDescriptor {
 			Name:        "result",
 			Description: "A command was executed",
 			Options: []bees.PlaceholderDescriptor{
+				{
+					Name:        "command",
+					Description: "The executed command",
+					Type:        "string",
+				},
+				{
+					Name:        "exit_code",
+					Description: "Exit code of the executed command",
+					Type:        "int",
+				},
+				{
+					Name:        "stdout",
+					Description: "stdout output of the executed command",
+					Type:        "string",
+				},
+				{
+					Name:        "stderr",
+					Description: "stderr output of the executed command",
+					Type:        "string",
+				},
+			},
+		},
+		{
+			Namespace:   factory.Name(),
+			Name:        "output",
+			Description: "The output of the watch command changed",
+			Options: []bees.PlaceholderDescriptor{
+				{
+					Name:        "command",
+					Description: "The executed command",
+					Type:        "string",
+				},
+				{
+					Name:        "exit_code",
+					Description: "Exit code of the executed command",
+					Type:        "int",
+				},
 				{
 					Name:        "stdout",
 					Description: "stdout output of the executed command",
@@ -105,12 +185,36 @@ func (factory *ExecBeeFactory) Actions() []bees.ActionDescriptor {
 					Type:        "string",
 					Mandatory:   true,
 				},
+				{
+					Name:        "args",
+					Description: "Additional arguments, passed to the command as they are",
+					Type:        "[]string",
+					Mandatory:   false,
+				},
 				{
 					Name:        "stdin",
 					Description: "stdin-Data for the command",
 					Type:        "string",
 					Mandatory:   false,
 				},
+				{
+					Name:        "workdir",
+					Description: "Working directory, overriding the bee's setting unless allowed_commands is set",
+					Type:        "string",
+					Mandatory:   false,
+				},
+				{
+					Name:        "env",
+					Description: "Additional environment variables, eg: LANG=C. Restricted to allowed_env if allowed_commands is set",
+					Type:        "[]string",
+					Mandatory:   false,
+				},
+				{
+					Name:        "timeout",
+					Description: "Time after which the command gets killed, eg: 30s, overriding the bee's setting",
+					Type:        "duration",
+					Mandatory:   false,
+				},
 			},
 		},
 	}
@@ -0,0 +1,179 @@
+/*
+ *    Copyright (C) 2026 Christian Muehlhaeuser
+ *
+ *    This program is free software: you can redistribute it and/or modify
+ *    it under the terms of the GNU Affero General Public License as published
+ *    by the Free Software Foundation, either version 3 of the License, or
+ *    (at your option) any later version.
+ *
+ *    This program is distributed in the hope that it will be useful,
+ *    but WITHOUT ANY WARRANTY; without even the implied warranty of
+ *    MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ *    GNU Affero General Public License for more details.
+ *
+ *    You should have received a copy of the GNU Affero General Public License
+ *    along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ *    Authors:
+ *      Christian Muehlhaeuser <muesli@gmail.com>
+ */
+// Package bees is Beehive's central module system.
+package bees
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Config describes the runtime configuration of the hive: its bees, actions
+// and chains. It uses the same layout as beehive's configuration files.
+type Config struct {
+	// Version is the schema version of the configuration, see ConfigVersion.
+	Version int `json:",omitempty"`
+
+	Bees    []BeeConfig
+	Actions []Action
+	Chains  []Chain
+}
+
+// ExportConfig returns the current configuration of the hive, including bees
+// and chains that got created or modified while running. Bees are sorted by
+// name. With maskPasswords, the values of password options get replaced by
+// PasswordMask; ImportConfig restores them from the running bees.
+func ExportConfig(maskPasswords bool) (Config, error) {
+	c := Config{
+		Version: ConfigVersion,
+		Bees:    []BeeConfig{},
+		Actions: append([]Action{}, GetActions()...),
+		Chains:  append([]Chain{}, GetChains()...),
+	}
+
+	for _, bee := range GetBees() {
+		options := (*bee).Options()
+		referenceMutex.RLock()
+		if raw, ok := rawOptions[(*bee).Name()]; ok {
+			options = raw
+		}
+		referenceMutex.RUnlock()
+		if maskPasswords {
+			options = MaskOptions((*bee).Namespace(), options)
+		} else {
+			var err error
+			if options, err = EncryptOptions((*bee).Namespace(), options); err != nil {
+				return Config{}, fmt.Errorf("Bee %s: %v", (*bee).Name(), err)
+			}
+		}
+
+		bc, _ := instanceConfig((*bee).Name())
+		bc.Name = (*bee).Name()
+		bc.Class = (*bee).Namespace()
+		bc.Description = (*bee).Description()
+		bc.Options = append(BeeOptions{}, options...)
+		c.Bees = append(c.Bees, bc)
+	}
+	sort.Slice(c.Bees, func(i, j int) bool {
+		return c.Bees[i].Name < c.Bees[j].Name
+	})
+
+	return c, nil
+}
+
+// ImportConfig replaces the hive's configuration: all bees get stopped, and
+// the bees, actions and chains of c get set up. The configuration gets
+// validated first, and if there are any problems, all of them are returned
+// without changing anything.
+func ImportConfig(c Config) error {
+	beeList, errs := validateConfig(c)
+	if len(errs) > 0 {
+		return validationError(errs)
+	}
+
+	StopBees()
+	SetActions(c.Actions)
+	SetChains(c.Chains)
+	return validationError(StartBees(beeList))
+}
+
+// validateConfig checks a configuration for unknown bee classes, invalid
+// options, duplicate names and references to nonexistent bees or actions.
+// Returns the config's bees with masked passwords restored.
+func validateConfig(c Config) ([]BeeConfig, []error) {
+	var errs []error
+
+	beeList, dups := uniqueBees(c.Bees)
+	errs = append(errs, dups...)
+	names := make(map[string]bool)
+	for i, bee := range beeList {
+		names[bee.Name] = true
+		if len(bee.Name) == 0 {
+			errs = append(errs, fmt.Errorf("Bee %d: name can't be empty", i+1))
+		}
+		if GetFactory(bee.Class) == nil {
+			errs = append(errs, fmt.Errorf("Bee %s: unknown class %s", bee.Name, bee.Class))
+			continue
+		}
+
+		bee.Options = UnmaskOptions(bee.Name, bee.Options)
+		beeList[i] = bee
+		for _, opt := range bee.Options {
+			if opt.Value == PasswordMask && secretOptions(bee.Class)[opt.Name] {
+				errs = append(errs, fmt.Errorf("Bee %s: masked password option %s can't be restored", bee.Name, opt.Name))
+			}
+		}
+		resolved, err := ResolveOptions(bee.Options)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("Bee %s: %v", bee.Name, err))
+			continue
+		}
+		for _, err := range ValidateOptions(bee.Class, resolved) {
+			errs = append(errs, fmt.Errorf("Bee %s: %v", bee.Name, err))
+		}
+	}
+
+	// bare names may refer to bees of any scope, see matchesBeeRef
+	namedBee := func(ref string) bool {
+		for id := range names {
+			if matchesBeeRef(ref, id) {
+				return true
+			}
+		}
+		return false
+	}
+	knownBee := func(name string) bool {
+		return namedBee(name) || name == Wildcard || name == SystemBee ||
+			strings.HasPrefix(name, GroupPrefix)
+	}
+
+	actionIDs := make(map[string]bool)
+	for _, a := range c.Actions {
+		if actionIDs[a.ID] {
+			errs = append(errs, fmt.Errorf("Action %s: duplicate ID", a.ID))
+		}
+		actionIDs[a.ID] = true
+		if !isBroadcast(a) && !namedBee(a.Bee) {
+			errs = append(errs, fmt.Errorf("Action %s: unknown bee %s", a.ID, a.Bee))
+		}
+	}
+
+	chainNames := make(map[string]bool)
+	for _, ch := range c.Chains {
+		if chainNames[ch.Name] {
+			errs = append(errs, fmt.Errorf("Chain %s: duplicate name", ch.Name))
+		}
+		chainNames[ch.Name] = true
+
+		for _, trigger := range ch.triggers() {
+			if trigger != nil && !knownBee(trigger.Bee) {
+				errs = append(errs, fmt.Errorf("Chain %s: unknown bee %s", ch.Name, trigger.Bee))
+			}
+		}
+		for _, id := range append(append([]string{}, ch.Actions...), ch.OnError...) {
+			if !actionIDs[id] {
+				errs = append(errs, fmt.Errorf("Chain %s: unknown action %s", ch.Name, id))
+			}
+		}
+	}
+
+	return beeList, errs
+}
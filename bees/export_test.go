@@ -0,0 +1,81 @@
+package bees
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestExportImportConfig(t *testing.T) {
+	oldActions := GetActions()
+	defer SetActions(oldActions)
+	oldChains := chains
+	defer func() { chains = oldChains }()
+	defer StopBees()
+
+	errs := StartBees([]BeeConfig{
+		{Name: "export-irc", Class: "typedbee", Options: BeeOptions{
+			{Name: "server", Value: "irc://irc.example.com"},
+			{Name: "password", Value: "secret"},
+		}},
+	})
+	if len(errs) > 0 {
+		t.Fatal(errs)
+	}
+	SetActions([]Action{{ID: "export-action", Bee: "export-irc", Name: "send"}})
+	SetChains([]Chain{{
+		Name:    "export-chain",
+		Event:   &Event{Bee: "export-irc", Name: "message"},
+		Actions: []string{"export-action"},
+	}})
+
+	c, err := ExportConfig(true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(c.Bees) != 1 || c.Bees[0].Options.Value("password") != PasswordMask {
+		t.Fatalf("Expected a single bee with a masked password, got %+v", c.Bees)
+	}
+
+	if err := ImportConfig(c); err != nil {
+		t.Fatal(err)
+	}
+	if v := (*GetBee("export-irc")).Options().Value("password"); v != "secret" {
+		t.Errorf("Expected password to be restored, got %v", v)
+	}
+
+	again, err := ExportConfig(true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(c, again) {
+		t.Errorf("Expected round-trip to preserve the config:\n%+v\n%+v", c, again)
+	}
+}
+
+func TestImportConfigValidation(t *testing.T) {
+	err := ImportConfig(Config{
+		Bees: []BeeConfig{
+			{Name: "invalid-a", Class: "nosuchbee"},
+			{Name: "invalid-b", Class: "typedbee"},
+		},
+		Actions: []Action{{ID: "invalid-action", Bee: "invalid-c", Name: "send"}},
+		Chains: []Chain{{
+			Name:    "invalid-chain",
+			Event:   &Event{Bee: "invalid-d", Name: "message"},
+			Actions: []string{"nosuchaction"},
+		}},
+	})
+	if err == nil {
+		t.Fatal("Expected import of an invalid config to fail")
+	}
+
+	for _, s := range []string{"nosuchbee", "server", "invalid-c", "invalid-d", "nosuchaction"} {
+		if !strings.Contains(err.Error(), s) {
+			t.Errorf("Expected error to mention %s, got: %v", s, err)
+		}
+	}
+	if GetBee("invalid-b") != nil {
+		t.Error("Expected no bees to be started for an invalid config")
+	}
+}
@@ -22,6 +22,7 @@
 package facebookbee
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -58,7 +59,7 @@ type FacebookBee struct {
 }
 
 // Run executes the Bee's event loop.
-func (mod *FacebookBee) Run(eventChan chan bees.Event) {
+func (mod *FacebookBee) Run(ctx context.Context, eventChan chan bees.Event) {
 	err := mod.handlePermanentPageToken()
 
 	if err != nil {
@@ -239,7 +240,7 @@ func (mod *FacebookBee) fetchPermanentPageAccessToken(accountID string, accessTo
 }
 
 // Action triggers the action passed to it.
-func (mod *FacebookBee) Action(action bees.Action) []bees.Placeholder {
+func (mod *FacebookBee) Action(ctx context.Context, action bees.Action) []bees.Placeholder {
 	outs := []bees.Placeholder{}
 	switch action.Name {
 	case "post":
@@ -66,6 +66,34 @@ func (factory *BeeFactory) Actions() []ActionDescriptor {
 	return []ActionDescriptor{}
 }
 
+// ValidateOptions accepts all options per default.
+func (factory *BeeFactory) ValidateOptions(options BeeOptions) []error {
+	return nil
+}
+
+// Doc returns the default empty documentation.
+func (factory *BeeFactory) Doc() string {
+	return ""
+}
+
+// Examples returns the default empty examples set.
+func (factory *BeeFactory) Examples() []ConfigExample {
+	return []ConfigExample{}
+}
+
+// A ConfigExample is an example configuration of a bee.
+type ConfigExample struct {
+	Title       string
+	Description string
+	Options     BeeOptions
+}
+
+// FactoryHelpInfo bundles the documentation and examples of a factory.
+type FactoryHelpInfo struct {
+	Doc      string
+	Examples []ConfigExample
+}
+
 // A BeeFactoryInterface is the interface that gets implemented by a BeeFactory.
 type BeeFactoryInterface interface {
 	// ID of the module
@@ -90,6 +118,13 @@ type BeeFactoryInterface interface {
 	Events() []EventDescriptor
 	// Actions supported by module
 	Actions() []ActionDescriptor
+	// ValidateOptions checks a bee's options before the bee gets created
+	ValidateOptions(options BeeOptions) []error
+
+	// Human-readable documentation of the module
+	Doc() string
+	// Example configurations of the module
+	Examples() []ConfigExample
 
 	New(name, description string, options BeeOptions) BeeInterface
 }
@@ -111,25 +146,30 @@ func RegisterFactory(factory BeeFactoryInterface) {
 	}
 	log.Println() */
 
-	factories[factory.ID()] = &factory
+	registry.RegisterFactory(&factory)
+	forgetFactoryDescriptors(factory.ID())
 }
 
 // GetFactory returns the factory with a specific name.
 func GetFactory(identifier string) *BeeFactoryInterface {
-	factory, ok := factories[identifier]
-	if ok {
-		return factory
+	return registry.Factory(identifier)
+}
+
+// FactoryHelp returns the documentation and example configurations provided
+// by the factory with a specific name.
+func FactoryHelp(class string) (FactoryHelpInfo, error) {
+	factory := GetFactory(class)
+	if factory == nil {
+		return FactoryHelpInfo{}, errors.New("Invalid class specified")
 	}
 
-	return nil
+	return FactoryHelpInfo{
+		Doc:      (*factory).Doc(),
+		Examples: (*factory).Examples(),
+	}, nil
 }
 
 // GetFactories returns all known bee factories.
 func GetFactories() []*BeeFactoryInterface {
-	r := []*BeeFactoryInterface{}
-	for _, factory := range factories {
-		r = append(r, factory)
-	}
-
-	return r
+	return registry.Factories()
 }
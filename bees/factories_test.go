@@ -0,0 +1,50 @@
+package bees
+
+import (
+	"fmt"
+)
+
+func init() {
+	RegisterFactory(&testFactory{
+		id:  "documentedbee",
+		doc: "Relays messages to a chat channel.",
+		examples: []ConfigExample{
+			{
+				Title:       "Ops channel",
+				Description: "Relays to the channel of the ops team",
+				Options:     BeeOptions{{Name: "channel", Value: "#ops"}},
+			},
+		},
+	})
+}
+
+func ExampleFactoryHelp() {
+	help, err := FactoryHelp("documentedbee")
+	if err != nil {
+		panic(err)
+	}
+
+	fmt.Println(help.Doc)
+	for _, ex := range help.Examples {
+		fmt.Printf("%s: %s\n", ex.Title, ex.Description)
+		for _, opt := range ex.Options {
+			fmt.Printf("\t%s = %v\n", opt.Name, opt.Value)
+		}
+	}
+	// Output:
+	// Relays messages to a chat channel.
+	// Ops channel: Relays to the channel of the ops team
+	// 	channel = #ops
+}
+
+func ExampleFactoryHelp_undocumented() {
+	// factories embedding BeeFactory come without documentation
+	help, err := FactoryHelp("recordingbee")
+	if err != nil {
+		panic(err)
+	}
+
+	fmt.Printf("%q %d\n", help.Doc, len(help.Examples))
+	// Output:
+	// "" 0
+}
@@ -0,0 +1,53 @@
+/*
+ *    Copyright (C) 2026 Christian Muehlhaeuser
+ *
+ *    This program is free software: you can redistribute it and/or modify
+ *    it under the terms of the GNU Affero General Public License as published
+ *    by the Free Software Foundation, either version 3 of the License, or
+ *    (at your option) any later version.
+ *
+ *    This program is distributed in the hope that it will be useful,
+ *    but WITHOUT ANY WARRANTY; without even the implied warranty of
+ *    MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ *    GNU Affero General Public License for more details.
+ *
+ *    You should have received a copy of the GNU Affero General Public License
+ *    along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ *    Authors:
+ *      Christian Muehlhaeuser <muesli@gmail.com>
+ */
+// Package bees is Beehive's central module system.
+package bees
+
+import (
+	"fmt"
+)
+
+// FactoryPluginSymbol is the name of the symbol a factory plugin has to
+// export, see RegisterFactoryPlugin.
+const FactoryPluginSymbol = "BeeFactory"
+
+// registerFactorySymbol registers the factory exported by a plugin as sym.
+func registerFactorySymbol(path string, sym interface{}) error {
+	var factory BeeFactoryInterface
+	switch s := sym.(type) {
+	case *BeeFactoryInterface:
+		if s == nil || *s == nil {
+			return fmt.Errorf("Plugin %s: %s is nil", path, FactoryPluginSymbol)
+		}
+		factory = *s
+	case BeeFactoryInterface:
+		factory = s
+	default:
+		return fmt.Errorf("Plugin %s: %s has type %T, expected a bees.BeeFactoryInterface", path, FactoryPluginSymbol, sym)
+	}
+
+	if GetFactory(factory.ID()) != nil {
+		return fmt.Errorf("Plugin %s: a factory with ID %s is already registered", path, factory.ID())
+	}
+
+	RegisterFactory(factory)
+	logger.Infof("Registered bee factory %s from plugin %s", factory.ID(), path)
+	return nil
+}
@@ -0,0 +1,52 @@
+//go:build (linux && cgo) || (darwin && cgo) || (freebsd && cgo)
+// +build linux,cgo darwin,cgo freebsd,cgo
+
+/*
+ *    Copyright (C) 2026 Christian Muehlhaeuser
+ *
+ *    This program is free software: you can redistribute it and/or modify
+ *    it under the terms of the GNU Affero General Public License as published
+ *    by the Free Software Foundation, either version 3 of the License, or
+ *    (at your option) any later version.
+ *
+ *    This program is distributed in the hope that it will be useful,
+ *    but WITHOUT ANY WARRANTY; without even the implied warranty of
+ *    MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ *    GNU Affero General Public License for more details.
+ *
+ *    You should have received a copy of the GNU Affero General Public License
+ *    along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ *    Authors:
+ *      Christian Muehlhaeuser <muesli@gmail.com>
+ */
+// Package bees is Beehive's central module system.
+package bees
+
+import (
+	"fmt"
+	"plugin"
+)
+
+// RegisterFactoryPlugin loads a bee factory from a Go plugin, built with
+// `go build -buildmode=plugin`. The plugin has to export a variable named
+// BeeFactory of type bees.BeeFactoryInterface, holding the factory:
+//
+//	var BeeFactory bees.BeeFactoryInterface = &MyBeeFactory{}
+//
+// Exporting a value implementing the interface directly works, too. The
+// plugin must be built against the same version of Beehive and its
+// dependencies as the running binary, and the factory's ID must not be in
+// use already.
+func RegisterFactoryPlugin(path string) error {
+	p, err := plugin.Open(path)
+	if err != nil {
+		return fmt.Errorf("Can't open plugin %s: %v", path, err)
+	}
+	sym, err := p.Lookup(FactoryPluginSymbol)
+	if err != nil {
+		return fmt.Errorf("Plugin %s: %v", path, err)
+	}
+
+	return registerFactorySymbol(path, sym)
+}
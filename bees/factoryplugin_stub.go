@@ -0,0 +1,34 @@
+//go:build (!linux && !darwin && !freebsd) || !cgo
+// +build !linux,!darwin,!freebsd !cgo
+
+/*
+ *    Copyright (C) 2026 Christian Muehlhaeuser
+ *
+ *    This program is free software: you can redistribute it and/or modify
+ *    it under the terms of the GNU Affero General Public License as published
+ *    by the Free Software Foundation, either version 3 of the License, or
+ *    (at your option) any later version.
+ *
+ *    This program is distributed in the hope that it will be useful,
+ *    but WITHOUT ANY WARRANTY; without even the implied warranty of
+ *    MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ *    GNU Affero General Public License for more details.
+ *
+ *    You should have received a copy of the GNU Affero General Public License
+ *    along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ *    Authors:
+ *      Christian Muehlhaeuser <muesli@gmail.com>
+ */
+// Package bees is Beehive's central module system.
+package bees
+
+import (
+	"errors"
+)
+
+// RegisterFactoryPlugin loads a bee factory from a Go plugin. Plugins aren't
+// supported on this platform, so it always fails.
+func RegisterFactoryPlugin(path string) error {
+	return errors.New("Can't load plugin " + path + ": Go plugins are not supported on this platform")
+}
@@ -0,0 +1,30 @@
+package bees
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRegisterFactorySymbol(t *testing.T) {
+	var factory BeeFactoryInterface = &testFactory{id: "pluginbee"}
+	if err := registerFactorySymbol("pluginbee.so", &factory); err != nil {
+		t.Fatal(err)
+	}
+	if GetFactory("pluginbee") == nil {
+		t.Fatal("Expected factory to be registered")
+	}
+
+	err := registerFactorySymbol("other.so", &testFactory{id: "pluginbee"})
+	if err == nil || !strings.Contains(err.Error(), "already registered") {
+		t.Errorf("Expected duplicate factory to fail, got %v", err)
+	}
+
+	s := "not a factory"
+	if err := registerFactorySymbol("wrong.so", &s); err == nil || !strings.Contains(err.Error(), "*string") {
+		t.Errorf("Expected wrong symbol type to fail, got %v", err)
+	}
+	var empty BeeFactoryInterface
+	if err := registerFactorySymbol("empty.so", &empty); err == nil {
+		t.Error("Expected nil factory to fail")
+	}
+}
@@ -22,7 +22,11 @@
 package bees
 
 import (
-	log "github.com/sirupsen/logrus"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"unicode"
 
 	"github.com/muesli/beehive/filters"
 )
@@ -34,16 +38,319 @@ type Filter struct {
 	Options FilterOption
 }
 
-// execFilter executes a filter. Returns whether the filter passed or not.
-func execFilter(filter string, opts map[string]interface{}) bool {
-	f := *filters.GetFilter("template")
-	log.Println("\tExecuting filter:", filter)
+// A FilterResult is the outcome of evaluating a filter against an event.
+type FilterResult struct {
+	// Passed is whether the event passed the filter
+	Passed bool
+	// Decider is the filter expression that decided the result, e.g. the
+	// first failing condition of a node requiring all of its conditions
+	Decider string
+}
+
+// EvaluateFilter evaluates a filter against an event without executing any
+// chains or actions. It returns whether the event passed the filter, or an
+// error when the filter could not be evaluated at all. Filters without a name
+// may select one by prefixing their expression, just like chain filters, see
+// splitFilter. Use EvaluateFilterNode to find out which condition decided the
+// result.
+func EvaluateFilter(filter Filter, event Event) (bool, error) {
+	expr, ok := filter.Options.Value.(string)
+	if !ok {
+		return false, errors.New("Filter value must be a string")
+	}
+	if len(filter.Name) > 0 {
+		if filters.GetFilter(filter.Name) == nil {
+			return false, errors.New("Unknown filter: " + filter.Name)
+		}
+		expr = filter.Name + ":" + expr
+	}
+
+	node := FilterNode{Filter: expr}
+	if filter.Options.Inverse {
+		node = FilterNode{Not: &FilterNode{Filter: expr}}
+	}
+	res, err := EvaluateFilterNode(node, event)
+	return res.Passed, err
+}
+
+// EvaluateFilterNode evaluates a composed filter against an event without
+// executing any chains or actions, or affecting the filter statistics. The
+// result carries the sub-condition which decided it.
+func EvaluateFilterNode(node FilterNode, event Event) (FilterResult, error) {
+	opts := eventMap(&event)
+	passed, decider, err := node.evaluateWith(func(filter string) (bool, error) {
+		name, expr := splitFilter(filter)
+		return evaluateFilter(name, expr, opts)
+	}, nil)
+
+	return FilterResult{Passed: passed, Decider: decider}, err
+}
+
+// evaluateFilter runs a named filter on opts, turning panics into errors.
+func evaluateFilter(name, expr string, opts map[string]interface{}) (passed bool, err error) {
+	f := filters.GetFilter(name)
+	if f == nil {
+		return false, errors.New("Unknown filter: " + name)
+	}
 
 	defer func() {
 		if e := recover(); e != nil {
-			log.Println("Fatal filter event:", e)
+			passed = false
+			err = fmt.Errorf("filter %s failed on %q: %v", name, expr, e)
 		}
 	}()
 
-	return f.Passes(opts, filter)
+	if ff, ok := (*f).(filters.FallibleFilter); ok {
+		return ff.PassesE(opts, expr)
+	}
+	return (*f).Passes(opts, expr), nil
+}
+
+// validateFilter checks a chain's filter expression with the filter it
+// selects, if that filter supports validation, see filters.ValidatingFilter.
+func validateFilter(filter string) error {
+	name, expr := splitFilter(filter)
+	f := filters.GetFilter(name)
+	if f == nil {
+		return nil
+	}
+	if vf, ok := (*f).(filters.ValidatingFilter); ok {
+		if err := vf.Validate(expr); err != nil {
+			return fmt.Errorf("Invalid %s filter %q: %v", name, strings.TrimSpace(expr), err)
+		}
+	}
+	return nil
+}
+
+// FilterStat contains evaluation statistics for a filter expression.
+type FilterStat struct {
+	// Evaluations is the number of times the filter has been evaluated
+	Evaluations int64
+	// CacheHits is the number of times a cached result has been re-used
+	CacheHits int64
+}
+
+var (
+	filterStats      = make(map[string]*FilterStat)
+	filterStatsMutex sync.Mutex
+)
+
+// filterCache memoizes filter results while dispatching a single event, so
+// identical filters used by several chains only get evaluated once. Results
+// are keyed by the filter and all of its inputs, as the variables, the bee
+// context or chain specific placeholders may change from chain to chain.
+type filterCache map[string]bool
+
+// filterCacheKey returns the key of a filter's result in a filterCache.
+func filterCacheKey(filter string, opts map[string]interface{}) string {
+	return filter + "\x00" + fmt.Sprint(opts)
+}
+
+// normalizeFilter normalizes the whitespace in a filter expression.
+func normalizeFilter(filter string) string {
+	return strings.Join(strings.Fields(filter), " ")
+}
+
+// timeFuncs are the functions filters can call to get the current time.
+var timeFuncs = map[string]bool{
+	"TimeNow": true,
+}
+
+// cacheableFilter returns whether a filter's result may be memoized. Filters
+// depending on the current time may not.
+func cacheableFilter(filter string) bool {
+	idents := strings.FieldsFunc(filter, func(c rune) bool {
+		return !unicode.IsLetter(c) && !unicode.IsDigit(c) && c != '_'
+	})
+	for _, ident := range idents {
+		if timeFuncs[ident] {
+			return false
+		}
+	}
+	return true
+}
+
+// FilterStats returns evaluation statistics for all filter expressions,
+// keyed by their normalized expression. This helps finding redundant filters
+// in large configurations.
+func FilterStats() map[string]FilterStat {
+	filterStatsMutex.Lock()
+	defer filterStatsMutex.Unlock()
+
+	r := make(map[string]FilterStat)
+	for k, v := range filterStats {
+		r[k] = *v
+	}
+
+	return r
+}
+
+// countFilter updates the statistics for a filter expression.
+func countFilter(key string, hit bool) {
+	filterStatsMutex.Lock()
+	defer filterStatsMutex.Unlock()
+
+	st, ok := filterStats[key]
+	if !ok {
+		st = &FilterStat{}
+		filterStats[key] = st
+	}
+	if hit {
+		st.CacheHits++
+	} else {
+		st.Evaluations++
+	}
+}
+
+// splitFilter returns the name of the filter a chain's filter expression
+// should be evaluated with, and the expression itself. Expressions can select
+// a filter by prefixing it with the filter's name and a colon, e.g.
+// "expr: channel == \"#ops\"". All other expressions are templates.
+func splitFilter(filter string) (string, string) {
+	i := strings.Index(filter, ":")
+	if i <= 0 {
+		return "template", filter
+	}
+	name := strings.TrimSpace(filter[:i])
+	for _, c := range name {
+		if !unicode.IsLetter(c) && !unicode.IsDigit(c) && c != '_' {
+			return "template", filter
+		}
+	}
+	if filters.GetFilter(name) == nil {
+		return "template", filter
+	}
+
+	return name, filter[i+1:]
+}
+
+// execFilter executes a filter. Returns whether the filter passed or not.
+// Results get memoized in cache, unless it is nil.
+func execFilter(filter string, opts map[string]interface{}, cache filterCache) bool {
+	passed, err := tryFilter(filter, opts, cache)
+	if err != nil {
+		logger.Errorf("Fatal filter event: %v", err)
+	}
+
+	return passed
+}
+
+// tryFilter executes a filter. Returns whether the filter passed, or an error
+// if it failed, e.g. due to a missing placeholder. Successful results get
+// memoized in cache, unless it is nil.
+func tryFilter(filter string, opts map[string]interface{}, cache filterCache) (bool, error) {
+	key := normalizeFilter(filter)
+	cacheable := cache != nil && cacheableFilter(key)
+	var cacheKey string
+	if cacheable {
+		cacheKey = filterCacheKey(key, opts)
+		if passed, ok := cache[cacheKey]; ok {
+			countFilter(key, true)
+			return passed, nil
+		}
+	}
+
+	logger.Debugf("\tExecuting filter: %v", filter)
+	countFilter(key, false)
+
+	name, expr := splitFilter(filter)
+	passed, err := evaluateFilter(name, expr, opts)
+	if err != nil {
+		return false, err
+	}
+
+	if cacheable {
+		cache[cacheKey] = passed
+	}
+	return passed, nil
+}
+
+// A FilterNode composes filters: it passes if all of the nodes in All pass,
+// any of the nodes in Any passes, the node in Not doesn't pass, or the
+// filter expression in Filter passes. Exactly one of these should be set; an
+// empty node always passes.
+type FilterNode struct {
+	All    []FilterNode `json:"All,omitempty"`
+	Any    []FilterNode `json:"Any,omitempty"`
+	Not    *FilterNode  `json:"Not,omitempty"`
+	Filter string       `json:"Filter,omitempty"`
+}
+
+// expressions returns all filter expressions of the node and its children.
+func (n FilterNode) expressions() []string {
+	var exprs []string
+	if len(n.Filter) > 0 {
+		exprs = append(exprs, n.Filter)
+	}
+	if n.Not != nil {
+		exprs = append(exprs, n.Not.expressions()...)
+	}
+	for _, child := range append(append([]FilterNode{}, n.All...), n.Any...) {
+		exprs = append(exprs, child.expressions()...)
+	}
+	return exprs
+}
+
+// evaluate evaluates the node against opts, short-circuiting as early as
+// possible. If a filter expression fails, e.g. due to a missing placeholder,
+// the error is returned and the entire node doesn't pass, regardless of
+// negations. Otherwise the filter expression that decided the result is
+// returned.
+func (n FilterNode) evaluate(opts map[string]interface{}, cache filterCache) (bool, string, error) {
+	return n.evaluateTraced(opts, cache, nil)
+}
+
+// evaluateTraced works like evaluate, additionally recording the result of
+// each evaluated filter expression in t, unless it is nil.
+func (n FilterNode) evaluateTraced(opts map[string]interface{}, cache filterCache, t *chainTrace) (bool, string, error) {
+	return n.evaluateWith(func(filter string) (bool, error) {
+		return tryFilter(filter, opts, cache)
+	}, t)
+}
+
+// evaluateWith evaluates the node, executing its filter expressions with
+// eval. The result of each of them gets recorded in t, unless it is nil.
+func (n FilterNode) evaluateWith(eval func(filter string) (bool, error), t *chainTrace) (bool, string, error) {
+	switch {
+	case len(n.Filter) > 0:
+		start := clock.Now()
+		passed, err := eval(n.Filter)
+		if t != nil {
+			ft := FilterTrace{Filter: n.Filter, Passed: passed, Duration: clock.Now().Sub(start)}
+			if err != nil {
+				ft.Error = err.Error()
+			}
+			t.addFilter(ft)
+		}
+		return passed, n.Filter, err
+
+	case n.Not != nil:
+		passed, decider, err := n.Not.evaluateWith(eval, t)
+		if err != nil {
+			return false, decider, err
+		}
+		return !passed, decider, nil
+
+	case len(n.Any) > 0:
+		var decider string
+		for _, child := range n.Any {
+			passed, d, err := child.evaluateWith(eval, t)
+			if err != nil || passed {
+				return passed, d, err
+			}
+			decider = d
+		}
+		return false, decider, nil
+
+	default:
+		var decider string
+		for _, child := range n.All {
+			passed, d, err := child.evaluateWith(eval, t)
+			if err != nil || !passed {
+				return passed, d, err
+			}
+			decider = d
+		}
+		return true, decider, nil
+	}
 }
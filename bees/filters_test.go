@@ -0,0 +1,267 @@
+package bees
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/muesli/beehive/filters"
+	_ "github.com/muesli/beehive/filters/expr"
+	_ "github.com/muesli/beehive/filters/template"
+)
+
+func TestEvaluateFilter(t *testing.T) {
+	event := Event{
+		Bee:  "testbee",
+		Name: "message",
+		Options: Placeholders{
+			{Name: "text", Type: "string", Value: "hello world"},
+		},
+	}
+
+	f := Filter{Options: FilterOption{Value: `{{test Contains .text "hello"}}`}}
+	passed, err := EvaluateFilter(f, event)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !passed {
+		t.Error("Expected event to pass filter")
+	}
+
+	f.Options.Inverse = true
+	passed, err = EvaluateFilter(f, event)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if passed {
+		t.Error("Expected event not to pass inverted filter")
+	}
+
+	// expressions may select a filter, just like chain filters
+	f = Filter{Options: FilterOption{Value: `expr: text == "hello world"`}}
+	if passed, err := EvaluateFilter(f, event); err != nil || !passed {
+		t.Errorf("Expected event to pass expr filter, got %v: %v", passed, err)
+	}
+	f = Filter{Name: "expr", Options: FilterOption{Value: `text == "goodbye"`}}
+	if passed, err := EvaluateFilter(f, event); err != nil || passed {
+		t.Errorf("Expected event not to pass named expr filter, got %v: %v", passed, err)
+	}
+
+	f = Filter{Options: FilterOption{Value: "{{test .text"}}
+	if _, err := EvaluateFilter(f, event); err == nil {
+		t.Error("Expected an error for a malformed filter")
+	}
+
+	f = Filter{Name: "nonexistent", Options: FilterOption{Value: "true"}}
+	if _, err := EvaluateFilter(f, event); err == nil {
+		t.Error("Expected an error for an unknown filter")
+	}
+}
+
+func TestEvaluateFilterNode(t *testing.T) {
+	event := Event{
+		Bee:  "testbee",
+		Name: "message",
+		Options: Placeholders{
+			{Name: "text", Type: "string", Value: "hello world"},
+			{Name: "channel", Type: "string", Value: "#random"},
+		},
+	}
+	before := FilterStats()
+
+	node := FilterNode{All: []FilterNode{
+		{Filter: `{{test Contains .text "hello"}}`},
+		{Filter: `expr: channel == "#ops"`},
+		{Filter: `{{test Contains .text "world"}}`},
+	}}
+	res, err := EvaluateFilterNode(node, event)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Passed || res.Decider != `expr: channel == "#ops"` {
+		t.Errorf("Expected the channel condition to fail the filter, got %+v", res)
+	}
+
+	if after := FilterStats(); len(after) != len(before) || after[normalizeFilter(`{{test Contains .text "hello"}}`)] != before[normalizeFilter(`{{test Contains .text "hello"}}`)] {
+		t.Error("Expected evaluating a filter not to affect the filter statistics")
+	}
+}
+
+func TestFilterCache(t *testing.T) {
+	opts := map[string]interface{}{"text": "hello world"}
+	cache := filterCache{}
+
+	f := `{{test Contains .text "hello"}}`
+	before := FilterStats()[normalizeFilter(f)]
+	for i := 0; i < 3; i++ {
+		if !execFilter(f, opts, cache) {
+			t.Fatal("Expected filter to pass")
+		}
+	}
+	after := FilterStats()[normalizeFilter(f)]
+	if after.Evaluations-before.Evaluations != 1 || after.CacheHits-before.CacheHits != 2 {
+		t.Errorf("Expected 1 evaluation and 2 cache hits, got %+v", after)
+	}
+
+	n := len(cache)
+	f = `{{test gt (TimeNow).Year 2000}}`
+	execFilter(f, opts, cache)
+	if len(cache) != n {
+		t.Error("Time dependent filters must not be cached")
+	}
+	if !cacheableFilter(`{{test eq .known "snow"}}`) {
+		t.Error("Expected filters merely containing \"now\" to be cacheable")
+	}
+
+	// an earlier chain's actions may have changed the variables
+	f = `{{test eq .vars.mode "on"}}`
+	opts["vars"] = map[string]interface{}{"mode": "on"}
+	if !execFilter(f, opts, cache) {
+		t.Fatal("Expected filter to pass")
+	}
+	opts["vars"] = map[string]interface{}{"mode": "off"}
+	if execFilter(f, opts, cache) {
+		t.Error("Expected changed variables not to use the cached result")
+	}
+}
+
+func benchmarkFilters(b *testing.B, cached bool) {
+	opts := map[string]interface{}{"text": "hello world"}
+	filters := []string{
+		`{{test Contains .text "hello"}}`,
+		`{{test HasPrefix .text "hello"}}`,
+		`{{test Contains .text "world"}}`,
+	}
+
+	for i := 0; i < b.N; i++ {
+		var cache filterCache
+		if cached {
+			cache = filterCache{}
+		}
+
+		// simulate 20 chains sharing the same filters
+		for c := 0; c < 20; c++ {
+			for _, f := range filters {
+				execFilter(f, opts, cache)
+			}
+		}
+	}
+}
+
+func BenchmarkFiltersUncached(b *testing.B) {
+	benchmarkFilters(b, false)
+}
+
+func BenchmarkFiltersCached(b *testing.B) {
+	benchmarkFilters(b, true)
+}
+
+func TestFilterNode(t *testing.T) {
+	opts := map[string]interface{}{
+		"text":   "hello world",
+		"sender": "alice",
+	}
+	contains := FilterNode{Filter: `{{test Contains .text "hello"}}`}
+	fromBob := FilterNode{Filter: `{{test eq .sender "bob"}}`}
+	missing := FilterNode{Filter: `{{test gt .missing 1}}`}
+
+	tests := []struct {
+		node   FilterNode
+		passed bool
+		err    bool
+	}{
+		{FilterNode{}, true, false},
+		{FilterNode{All: []FilterNode{contains, {Not: &fromBob}}}, true, false},
+		{FilterNode{All: []FilterNode{contains, fromBob}}, false, false},
+		{FilterNode{Any: []FilterNode{fromBob, contains}}, true, false},
+		{FilterNode{Any: []FilterNode{fromBob}}, false, false},
+		{FilterNode{Not: &missing}, false, true},
+		// short-circuits before reaching the broken filter
+		{FilterNode{Any: []FilterNode{contains, missing}}, true, false},
+	}
+
+	for i, test := range tests {
+		passed, _, err := test.node.evaluate(opts, nil)
+		if passed != test.passed || (err != nil) != test.err {
+			t.Errorf("Test %d: expected %v (error: %v), got %v (%v)", i, test.passed, test.err, passed, err)
+		}
+	}
+}
+
+func TestExpressionFilters(t *testing.T) {
+	opts := map[string]interface{}{"text": "deploying v2", "channel": "#ops"}
+
+	tests := []struct {
+		filter string
+		passed bool
+	}{
+		{`expr: contains(text, "deploy") && channel == "#ops"`, true},
+		{`expr: contains(text, "deploy") && channel == "#dev"`, false},
+		{`expr:text =~ "^deploy"`, true},
+		// colons in templates don't select a filter
+		{`{{test eq .channel "#ops"}}{{/* note: selected by channel */}}`, true},
+	}
+	for _, test := range tests {
+		passed, err := tryFilter(test.filter, opts, nil)
+		if err != nil {
+			t.Errorf("Filter %q failed: %v", test.filter, err)
+		}
+		if passed != test.passed {
+			t.Errorf("Expected filter %q to return %v, got %v", test.filter, test.passed, passed)
+		}
+	}
+
+	if _, err := tryFilter(`expr: channel ==`, opts, nil); err == nil {
+		t.Error("Expected an error for a malformed expression")
+	}
+}
+
+// minLengthFilter passes when an event's text is at least as long as the
+// filter's value.
+type minLengthFilter struct{}
+
+func (f *minLengthFilter) Name() string        { return "minlength" }
+func (f *minLengthFilter) Description() string { return "Passes for texts of a minimum length" }
+
+func (f *minLengthFilter) Passes(data map[string]interface{}, value string) bool {
+	passed, _ := f.PassesE(data, value)
+	return passed
+}
+
+func (f *minLengthFilter) PassesE(data map[string]interface{}, value string) (bool, error) {
+	text, ok := data["text"].(string)
+	if !ok {
+		return false, errors.New("No text to measure")
+	}
+	n, err := strconv.Atoi(strings.TrimSpace(value))
+	if err != nil {
+		return false, err
+	}
+	return len(text) >= n, nil
+}
+
+func (f *minLengthFilter) Validate(value string) error {
+	_, err := strconv.Atoi(strings.TrimSpace(value))
+	return err
+}
+
+func TestCustomFilters(t *testing.T) {
+	filters.RegisterFilter(&minLengthFilter{})
+
+	opts := map[string]interface{}{"text": "hello"}
+	if passed, err := tryFilter("minlength: 3", opts, nil); err != nil || !passed {
+		t.Errorf("Expected custom filter to pass, got %v (%v)", passed, err)
+	}
+	if passed, err := tryFilter("minlength: 10", opts, nil); err != nil || passed {
+		t.Errorf("Expected custom filter not to pass, got %v (%v)", passed, err)
+	}
+	if _, err := tryFilter("minlength: 3", map[string]interface{}{}, nil); err == nil {
+		t.Error("Expected the custom filter's error to be returned")
+	}
+
+	c := Chain{Name: "custom", FilterTree: &FilterNode{Any: []FilterNode{{Filter: "minlength: many"}, {Filter: "expr: (("}}}}
+	if errs := ValidateChain(c); len(errs) != 2 {
+		t.Errorf("Expected both invalid filter expressions to be reported, got %v", errs)
+	}
+}
@@ -21,6 +21,8 @@
 package fsnotifybee
 
 import (
+	"context"
+
 	"github.com/fsnotify/fsnotify"
 	"github.com/muesli/beehive/bees"
 )
@@ -29,7 +31,7 @@ type FSNotifyBee struct {
 	bees.Bee
 }
 
-func (mod *FSNotifyBee) Run(eventChan chan bees.Event) {
+func (mod *FSNotifyBee) Run(ctx context.Context, eventChan chan bees.Event) {
 	watcher, err := fsnotify.NewWatcher()
 	if err != nil {
 		mod.LogFatal("error: could not create the fswatcher:", err)
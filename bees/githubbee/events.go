@@ -30,6 +30,14 @@ import (
 	"github.com/muesli/beehive/bees"
 )
 
+// emit sends an event to the hive, unless the bee gets stopped meanwhile.
+func (mod *GitHubBee) emit(ev bees.Event) {
+	select {
+	case mod.eventChan <- ev:
+	case <-mod.Context().Done():
+	}
+}
+
 func (mod *GitHubBee) handleReleaseEvent(event *github.Event) {
 	var b github.ReleaseEvent
 	json.Unmarshal(*event.RawPayload, &b)
@@ -80,7 +88,7 @@ func (mod *GitHubBee) handleReleaseEvent(event *github.Event) {
 			},
 		},
 	}
-	mod.eventChan <- ev
+	mod.emit(ev)
 }
 
 func (mod *GitHubBee) handlePushEvent(event *github.Event) {
@@ -123,7 +131,7 @@ func (mod *GitHubBee) handlePushEvent(event *github.Event) {
 			},
 		},
 	}
-	mod.eventChan <- ev
+	mod.emit(ev)
 
 	for _, commit := range b.Commits {
 		ev := bees.Event{
@@ -167,7 +175,7 @@ func (mod *GitHubBee) handlePushEvent(event *github.Event) {
 				},
 			},
 		}
-		mod.eventChan <- ev
+		mod.emit(ev)
 	}
 }
 
@@ -206,7 +214,7 @@ func (mod *GitHubBee) handleWatchEvent(event *github.Event) {
 			},
 		},
 	}
-	mod.eventChan <- ev
+	mod.emit(ev)
 }
 
 func (mod *GitHubBee) handleForkEvent(event *github.Event) {
@@ -244,7 +252,7 @@ func (mod *GitHubBee) handleForkEvent(event *github.Event) {
 			},
 		},
 	}
-	mod.eventChan <- ev
+	mod.emit(ev)
 }
 
 func (mod *GitHubBee) handleIssuesEvent(event *github.Event) {
@@ -307,7 +315,7 @@ func (mod *GitHubBee) handleIssuesEvent(event *github.Event) {
 				},
 			},
 		}
-		mod.eventChan <- ev
+		mod.emit(ev)
 
 	default:
 		mod.LogErrorf("Unhandled issues event: %s", *b.Action)
@@ -366,7 +374,7 @@ func (mod *GitHubBee) handleIssueCommentEvent(event *github.Event) {
 				},
 			},
 		}
-		mod.eventChan <- ev
+		mod.emit(ev)
 
 	default:
 		mod.LogErrorf("Unhandled issue comment event: %s", *b.Action)
@@ -434,7 +442,7 @@ func (mod *GitHubBee) handlePullRequestEvent(event *github.Event) {
 			},
 		}
 
-		mod.eventChan <- ev
+		mod.emit(ev)
 
 	default:
 		mod.LogErrorf("Unhandled pullrequest event: %s", *b.Action)
@@ -493,7 +501,7 @@ func (mod *GitHubBee) handlePullRequestReviewCommentEvent(event *github.Event) {
 				},
 			},
 		}
-		mod.eventChan <- ev
+		mod.emit(ev)
 
 	default:
 		mod.LogErrorf("Unhandled pullrequest review comment event: %s", *b.Action)
@@ -24,6 +24,7 @@ package githubbee
 
 import (
 	"context"
+	"fmt"
 	"time"
 
 	"github.com/google/go-github/github"
@@ -42,11 +43,14 @@ type GitHubBee struct {
 	accessToken string
 	owner       string
 	repository  string
+
+	addr   string
+	path   string
+	secret string
 }
 
-// Action triggers the actions passed to it.
-func (mod *GitHubBee) Action(action bees.Action) []bees.Placeholder {
-	ctx := context.Background()
+// ActionE triggers the actions passed to it.
+func (mod *GitHubBee) ActionE(ctx context.Context, action bees.Action) ([]bees.Placeholder, error) {
 	outs := []bees.Placeholder{}
 	switch action.Name {
 	case "follow":
@@ -54,7 +58,7 @@ func (mod *GitHubBee) Action(action bees.Action) []bees.Placeholder {
 		action.Options.Bind("username", &user)
 
 		if _, err := mod.client.Users.Follow(ctx, user); err != nil {
-			mod.LogErrorf("Failed to follow user: %v", err)
+			return outs, fmt.Errorf("Failed to follow user: %v", err)
 		}
 
 	case "unfollow":
@@ -62,7 +66,7 @@ func (mod *GitHubBee) Action(action bees.Action) []bees.Placeholder {
 		action.Options.Bind("username", &user)
 
 		if _, err := mod.client.Users.Unfollow(ctx, user); err != nil {
-			mod.LogErrorf("Failed to follow user: %v", err)
+			return outs, fmt.Errorf("Failed to unfollow user: %v", err)
 		}
 
 	case "star":
@@ -72,7 +76,7 @@ func (mod *GitHubBee) Action(action bees.Action) []bees.Placeholder {
 		action.Options.Bind("repository", &repo)
 
 		if _, err := mod.client.Activity.Star(ctx, user, repo); err != nil {
-			mod.LogErrorf("Failed to star repository: %v", err)
+			return outs, fmt.Errorf("Failed to star repository: %v", err)
 		}
 
 	case "unstar":
@@ -82,25 +86,102 @@ func (mod *GitHubBee) Action(action bees.Action) []bees.Placeholder {
 		action.Options.Bind("repository", &repo)
 
 		if _, err := mod.client.Activity.Unstar(ctx, user, repo); err != nil {
-			mod.LogErrorf("Failed to unstar repository: %v", err)
+			return outs, fmt.Errorf("Failed to unstar repository: %v", err)
+		}
+
+	case "comment":
+		var number int
+		var body string
+		owner, repo := mod.repositoryOf(action)
+		action.Options.Bind("number", &number)
+		action.Options.Bind("body", &body)
+
+		c, _, err := mod.client.Issues.CreateComment(ctx, owner, repo, number, &github.IssueComment{Body: &body})
+		if err != nil {
+			return outs, fmt.Errorf("Failed to comment on issue: %v", err)
+		}
+		outs = append(outs, bees.Placeholder{Name: "url", Type: "url", Value: c.GetHTMLURL()})
+
+	case "label":
+		var number int
+		var labels []string
+		owner, repo := mod.repositoryOf(action)
+		action.Options.Bind("number", &number)
+		action.Options.Bind("labels", &labels)
+
+		if _, _, err := mod.client.Issues.AddLabelsToIssue(ctx, owner, repo, number, labels); err != nil {
+			return outs, fmt.Errorf("Failed to label issue: %v", err)
+		}
+
+	case "create_issue":
+		var title, body string
+		var labels, assignees []string
+		owner, repo := mod.repositoryOf(action)
+		action.Options.Bind("title", &title)
+		action.Options.Bind("body", &body)
+		action.Options.Bind("labels", &labels)
+		action.Options.Bind("assignees", &assignees)
+
+		req := &github.IssueRequest{Title: &title, Body: &body}
+		if len(labels) > 0 {
+			req.Labels = &labels
 		}
+		if len(assignees) > 0 {
+			req.Assignees = &assignees
+		}
+		issue, _, err := mod.client.Issues.Create(ctx, owner, repo, req)
+		if err != nil {
+			return outs, fmt.Errorf("Failed to create issue: %v", err)
+		}
+		outs = append(outs,
+			bees.Placeholder{Name: "number", Type: "int", Value: issue.GetNumber()},
+			bees.Placeholder{Name: "url", Type: "url", Value: issue.GetHTMLURL()})
 
 	default:
 		panic("Unknown action triggered in " + mod.Name() + ": " + action.Name)
 	}
 
+	return outs, nil
+}
+
+// Action triggers the actions passed to it.
+func (mod *GitHubBee) Action(ctx context.Context, action bees.Action) []bees.Placeholder {
+	outs, err := mod.ActionE(ctx, action)
+	if err != nil {
+		mod.LogErrorf("Error: %v", err)
+	}
 	return outs
 }
 
-// Run executes the Bee's event loop.
-func (mod *GitHubBee) Run(eventChan chan bees.Event) {
-	ts := oauth2.StaticTokenSource(
-		&oauth2.Token{AccessToken: mod.accessToken},
-	)
-	tc := oauth2.NewClient(oauth2.NoContext, ts)
+// repositoryOf returns the repository an action refers to, defaulting to the
+// bee's repository.
+func (mod *GitHubBee) repositoryOf(action bees.Action) (string, string) {
+	owner, repo := mod.owner, mod.repository
+	action.Options.Bind("owner", &owner)
+	action.Options.Bind("repository", &repo)
+	return owner, repo
+}
 
+// Run executes the Bee's event loop. It receives webhooks if the bee was
+// configured with an address to listen on, and polls the repository's
+// events otherwise.
+func (mod *GitHubBee) Run(ctx context.Context, eventChan chan bees.Event) {
 	mod.eventChan = eventChan
-	mod.client = github.NewClient(tc)
+
+	if mod.addr != "" {
+		srv, err := mod.listen()
+		if err != nil {
+			mod.LogErrorf("Can't listen on %s: %v", mod.addr, err)
+			return
+		}
+		defer srv.Close()
+
+		select {
+		case <-mod.SigChan:
+		case <-ctx.Done():
+		}
+		return
+	}
 
 	since := time.Now() // .Add(-time.Duration(24 * time.Hour))
 	timeout := time.Duration(time.Second * 10)
@@ -136,31 +217,35 @@ func (mod *GitHubBee) getRepositoryEvents(owner, repo string, since time.Time) {
 			if since.After(*v.CreatedAt) {
 				return
 			}
-			switch *v.Type {
-			case "PushEvent":
-				mod.handlePushEvent(v)
-			case "WatchEvent":
-				mod.handleWatchEvent(v)
-			case "ForkEvent":
-				mod.handleForkEvent(v)
-			case "IssuesEvent":
-				mod.handleIssuesEvent(v)
-			case "IssueCommentEvent":
-				mod.handleIssueCommentEvent(v)
-			case "PullRequestEvent":
-				mod.handlePullRequestEvent(v)
-			case "PullRequestReviewCommentEvent":
-				mod.handlePullRequestReviewCommentEvent(v)
-			case "ReleaseEvent":
-				mod.handleReleaseEvent(v)
-
-			default:
-				mod.LogErrorf("Unhandled event: %s", *v.Type)
-			}
+			mod.handleEvent(v)
 		}
 	}
 }
 
+func (mod *GitHubBee) handleEvent(v *github.Event) {
+	switch *v.Type {
+	case "PushEvent":
+		mod.handlePushEvent(v)
+	case "WatchEvent":
+		mod.handleWatchEvent(v)
+	case "ForkEvent":
+		mod.handleForkEvent(v)
+	case "IssuesEvent":
+		mod.handleIssuesEvent(v)
+	case "IssueCommentEvent":
+		mod.handleIssueCommentEvent(v)
+	case "PullRequestEvent":
+		mod.handlePullRequestEvent(v)
+	case "PullRequestReviewCommentEvent":
+		mod.handlePullRequestReviewCommentEvent(v)
+	case "ReleaseEvent":
+		mod.handleReleaseEvent(v)
+
+	default:
+		mod.LogErrorf("Unhandled event: %s", *v.Type)
+	}
+}
+
 /*
 func (mod *GitHubBee) getNotifications() {
 	opts := &github.NotificationListOptions{
@@ -211,7 +296,7 @@ func (mod *GitHubBee) getNotifications() {
 				},
 			},
 		}
-		mod.eventChan <- ev
+		mod.emit(ev)
 	}
 }
 */
@@ -223,4 +308,13 @@ func (mod *GitHubBee) ReloadOptions(options bees.BeeOptions) {
 	options.Bind("accesstoken", &mod.accessToken)
 	options.Bind("owner", &mod.owner)
 	options.Bind("repository", &mod.repository)
+	options.Bind("address", &mod.addr)
+	options.Bind("path", &mod.path)
+	options.Bind("secret", &mod.secret)
+
+	ts := oauth2.StaticTokenSource(
+		&oauth2.Token{AccessToken: mod.accessToken},
+	)
+	tc := oauth2.NewClient(oauth2.NoContext, ts)
+	mod.client = github.NewClient(tc)
 }
@@ -87,6 +87,22 @@ func (factory *GitHubBeeFactory) Options() []bees.BeeOptionDescriptor {
 			Type:        "string",
 			Mandatory:   true,
 		},
+		{
+			Name:        "address",
+			Description: "Address to receive webhooks on instead of polling, e.g. 0.0.0.0:12347",
+			Type:        "address",
+		},
+		{
+			Name:        "path",
+			Description: "Path to receive webhooks on",
+			Type:        "string",
+			Default:     "/",
+		},
+		{
+			Name:        "secret",
+			Description: "Secret of the webhook, used to verify requests stem from GitHub",
+			Type:        "password",
+		},
 	}
 	return opts
 }
@@ -627,6 +643,102 @@ func (factory *GitHubBeeFactory) Actions() []bees.ActionDescriptor {
 				},
 			},
 		},
+		{
+			Namespace:   factory.Name(),
+			Name:        "comment",
+			Description: "Comments on an issue or pull request",
+			Options: []bees.PlaceholderDescriptor{
+				{
+					Name:        "owner",
+					Description: "Owner of the repository, defaults to the bee's owner",
+					Type:        "string",
+				},
+				{
+					Name:        "repository",
+					Description: "Name of the repository, defaults to the bee's repository",
+					Type:        "string",
+				},
+				{
+					Name:        "number",
+					Description: "Number of the issue or pull request",
+					Type:        "int",
+					Mandatory:   true,
+				},
+				{
+					Name:        "body",
+					Description: "Text of the comment",
+					Type:        "string",
+					Mandatory:   true,
+				},
+			},
+		},
+		{
+			Namespace:   factory.Name(),
+			Name:        "label",
+			Description: "Adds labels to an issue or pull request",
+			Options: []bees.PlaceholderDescriptor{
+				{
+					Name:        "owner",
+					Description: "Owner of the repository, defaults to the bee's owner",
+					Type:        "string",
+				},
+				{
+					Name:        "repository",
+					Description: "Name of the repository, defaults to the bee's repository",
+					Type:        "string",
+				},
+				{
+					Name:        "number",
+					Description: "Number of the issue or pull request",
+					Type:        "int",
+					Mandatory:   true,
+				},
+				{
+					Name:        "labels",
+					Description: "Labels to add",
+					Type:        "[]string",
+					Mandatory:   true,
+				},
+			},
+		},
+		{
+			Namespace:   factory.Name(),
+			Name:        "create_issue",
+			Description: "Creates an issue",
+			Options: []bees.PlaceholderDescriptor{
+				{
+					Name:        "owner",
+					Description: "Owner of the repository, defaults to the bee's owner",
+					Type:        "string",
+				},
+				{
+					Name:        "repository",
+					Description: "Name of the repository, defaults to the bee's repository",
+					Type:        "string",
+				},
+				{
+					Name:        "title",
+					Description: "Title of the issue",
+					Type:        "string",
+					Mandatory:   true,
+				},
+				{
+					Name:        "body",
+					Description: "Description of the issue",
+					Type:        "string",
+				},
+				{
+					Name:        "labels",
+					Description: "Labels of the issue",
+					Type:        "[]string",
+				},
+				{
+					Name:        "assignees",
+					Description: "Users to assign the issue to",
+					Type:        "[]string",
+				},
+			},
+		},
 	}
 
 	return actions
@@ -0,0 +1,158 @@
+/*
+ *    Copyright (C) 2026 Christian Muehlhaeuser
+ *
+ *    This program is free software: you can redistribute it and/or modify
+ *    it under the terms of the GNU Affero General Public License as published
+ *    by the Free Software Foundation, either version 3 of the License, or
+ *    (at your option) any later version.
+ *
+ *    This program is distributed in the hope that it will be useful,
+ *    but WITHOUT ANY WARRANTY; without even the implied warranty of
+ *    MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ *    GNU Affero General Public License for more details.
+ *
+ *    You should have received a copy of the GNU Affero General Public License
+ *    along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ *    Authors:
+ *      Christian Muehlhaeuser <muesli@gmail.com>
+ */
+
+package githubbee
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net"
+	"net/http"
+
+	"github.com/google/go-github/github"
+)
+
+// webhookTypes maps the types of webhook events to the types of the Events
+// API, which the event handlers expect.
+var webhookTypes = map[string]string{
+	"push":                        "PushEvent",
+	"watch":                       "WatchEvent",
+	"fork":                        "ForkEvent",
+	"issues":                      "IssuesEvent",
+	"issue_comment":               "IssueCommentEvent",
+	"pull_request":                "PullRequestEvent",
+	"pull_request_review_comment": "PullRequestReviewCommentEvent",
+	"release":                     "ReleaseEvent",
+}
+
+// listen starts the HTTP server receiving GitHub's webhook requests.
+func (mod *GitHubBee) listen() (*http.Server, error) {
+	mux := http.NewServeMux()
+	mux.Handle(mod.path, mod)
+	srv := &http.Server{Addr: mod.addr, Handler: mux}
+
+	l, err := net.Listen("tcp", mod.addr)
+	if err != nil {
+		return nil, err
+	}
+
+	go func() {
+		err := srv.Serve(l)
+		if err != nil && err != http.ErrServerClosed {
+			mod.LogErrorf("Server error: %v", err)
+		}
+	}()
+	return srv, nil
+}
+
+func (mod *GitHubBee) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	defer req.Body.Close()
+	req.Body = http.MaxBytesReader(w, req.Body, 25*1024*1024)
+
+	var payload []byte
+	var err error
+	if mod.secret != "" {
+		payload, err = github.ValidatePayload(req, []byte(mod.secret))
+		if err != nil {
+			mod.LogErrorf("Rejected webhook from %s: %v", req.RemoteAddr, err)
+			http.Error(w, "Forbidden", http.StatusForbidden)
+			return
+		}
+	} else if req.Header.Get("Content-Type") == "application/x-www-form-urlencoded" {
+		payload = []byte(req.PostFormValue("payload"))
+	} else {
+		payload, err = ioutil.ReadAll(req.Body)
+		if err != nil {
+			http.Error(w, "Invalid payload", http.StatusBadRequest)
+			return
+		}
+	}
+
+	typ, ok := webhookTypes[github.WebHookType(req)]
+	if !ok {
+		// e.g. the ping GitHub sends when setting up a webhook
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	ev, err := webhookEvent(typ, github.DeliveryID(req), payload)
+	if err != nil {
+		http.Error(w, "Invalid payload", http.StatusBadRequest)
+		return
+	}
+
+	mod.handleEvent(ev)
+	if mod.Context().Err() != nil {
+		http.Error(w, "Bee is stopping", http.StatusServiceUnavailable)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// webhookEvent converts the payload of a webhook to an Events API event.
+func webhookEvent(typ, id string, payload []byte) (*github.Event, error) {
+	var p map[string]interface{}
+	if err := json.Unmarshal(payload, &p); err != nil {
+		return nil, err
+	}
+
+	var hook struct {
+		Repository struct {
+			FullName string `json:"full_name"`
+			Private  bool   `json:"private"`
+		} `json:"repository"`
+		Sender struct {
+			Login string `json:"login"`
+		} `json:"sender"`
+	}
+	if err := json.Unmarshal(payload, &hook); err != nil {
+		return nil, err
+	}
+
+	if typ == "PushEvent" {
+		// the Events API calls some fields of pushes differently
+		p["head"] = p["after"]
+		if commits, ok := p["commits"].([]interface{}); ok {
+			for _, c := range commits {
+				if c, ok := c.(map[string]interface{}); ok {
+					c["sha"] = c["id"]
+				}
+			}
+		}
+	}
+	b, err := json.Marshal(p)
+	if err != nil {
+		return nil, err
+	}
+	raw := json.RawMessage(b)
+
+	public := !hook.Repository.Private
+	return &github.Event{
+		Type:       &typ,
+		ID:         &id,
+		Public:     &public,
+		RawPayload: &raw,
+		Repo:       &github.Repository{Name: &hook.Repository.FullName},
+		Actor:      &github.User{Login: &hook.Sender.Login},
+	}, nil
+}
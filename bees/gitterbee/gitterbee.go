@@ -23,6 +23,7 @@
 package gitterbee
 
 import (
+	"context"
 	"fmt"
 
 	gitter "github.com/sromku/go-gitter"
@@ -44,7 +45,7 @@ type GitterBee struct {
 }
 
 // Action triggers the actions passed to it.
-func (mod *GitterBee) Action(action bees.Action) []bees.Placeholder {
+func (mod *GitterBee) Action(ctx context.Context, action bees.Action) []bees.Placeholder {
 	outs := []bees.Placeholder{}
 	switch action.Name {
 
@@ -93,7 +94,7 @@ func (mod *GitterBee) Action(action bees.Action) []bees.Placeholder {
 }
 
 // Run executes the Bee's event loop.
-func (mod *GitterBee) Run(eventChan chan bees.Event) {
+func (mod *GitterBee) Run(ctx context.Context, eventChan chan bees.Event) {
 	mod.eventChan = eventChan
 
 	mod.roomChans = make(map[string]chan interface{})
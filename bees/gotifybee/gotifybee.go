@@ -22,6 +22,7 @@
 package gotifybee
 
 import (
+	"context"
 	"net/http"
 	"net/url"
 	"regexp"
@@ -37,7 +38,7 @@ type GotifyBee struct {
 }
 
 // Action triggers the action passed to it.
-func (mod *GotifyBee) Action(action bees.Action) []bees.Placeholder {
+func (mod *GotifyBee) Action(ctx context.Context, action bees.Action) []bees.Placeholder {
 	outs := []bees.Placeholder{}
 
 	switch action.Name {
@@ -0,0 +1,190 @@
+/*
+ *    Copyright (C) 2026 Christian Muehlhaeuser
+ *
+ *    This program is free software: you can redistribute it and/or modify
+ *    it under the terms of the GNU Affero General Public License as published
+ *    by the Free Software Foundation, either version 3 of the License, or
+ *    (at your option) any later version.
+ *
+ *    This program is distributed in the hope that it will be useful,
+ *    but WITHOUT ANY WARRANTY; without even the implied warranty of
+ *    MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ *    GNU Affero General Public License for more details.
+ *
+ *    You should have received a copy of the GNU Affero General Public License
+ *    along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ *    Authors:
+ *      Christian Muehlhaeuser <muesli@gmail.com>
+ */
+// Package bees is Beehive's central module system.
+package bees
+
+import (
+	"errors"
+	"strings"
+	"sync"
+)
+
+// GroupPrefix marks the bee of a chain's event as referring to a group of
+// bees, e.g. "group:irc". The chain then gets triggered by events of any
+// member of the group.
+const GroupPrefix = "group:"
+
+// ErrUnknownGroup is returned when referring to a group that doesn't exist.
+var ErrUnknownGroup = errors.New("No group with that name exists")
+
+// A BeeGroup is a named set of bees, which can be started and stopped as a
+// unit.
+type BeeGroup struct {
+	Name    string
+	Members []string
+}
+
+var (
+	groups      = make(map[string]*BeeGroup)
+	groupsMutex sync.RWMutex
+)
+
+// copy returns a copy of the group, safe to be handed out.
+func (g *BeeGroup) copy() *BeeGroup {
+	return &BeeGroup{
+		Name:    g.Name,
+		Members: append([]string{}, g.Members...),
+	}
+}
+
+// has returns whether a bee is a member of the group.
+func (g *BeeGroup) has(beeName string) bool {
+	for _, m := range g.Members {
+		if m == beeName {
+			return true
+		}
+	}
+	return false
+}
+
+// CreateBeeGroup creates an empty group of bees. If the group already exists
+// it's left unchanged. Returns a copy of the group.
+func CreateBeeGroup(name string) *BeeGroup {
+	groupsMutex.Lock()
+	defer groupsMutex.Unlock()
+
+	g, ok := groups[name]
+	if !ok {
+		g = &BeeGroup{Name: name}
+		groups[name] = g
+	}
+	return g.copy()
+}
+
+// AddBeeToGroup adds a bee to a group. Adding a member again is a no-op.
+func AddBeeToGroup(groupName, beeName string) error {
+	if GetBee(beeName) == nil {
+		return ErrUnknownBee
+	}
+
+	groupsMutex.Lock()
+	defer groupsMutex.Unlock()
+
+	g, ok := groups[groupName]
+	if !ok {
+		return ErrUnknownGroup
+	}
+	if !g.has(beeName) {
+		g.Members = append(g.Members, beeName)
+	}
+	return nil
+}
+
+// GetGroup returns a copy of the group with a specific name.
+func GetGroup(name string) (*BeeGroup, bool) {
+	groupsMutex.RLock()
+	defer groupsMutex.RUnlock()
+
+	g, ok := groups[name]
+	if !ok {
+		return nil, false
+	}
+	return g.copy(), true
+}
+
+// groupMembers returns the bees of a group which are currently registered.
+func groupMembers(name string) []*BeeInterface {
+	g, ok := GetGroup(name)
+	if !ok {
+		return nil
+	}
+
+	var bees []*BeeInterface
+	for _, m := range g.Members {
+		if bee := GetBee(m); bee != nil {
+			bees = append(bees, bee)
+		}
+	}
+	return bees
+}
+
+// StartGroup starts all members of a group which aren't running.
+func StartGroup(name string) {
+	for _, bee := range groupMembers(name) {
+		if (*bee).IsRunning() {
+			continue
+		}
+
+		(*bee).SetSigChan(make(chan bool))
+		(*bee).Start()
+		runBee(bee, 0)
+	}
+}
+
+// StopGroup stops all members of a group and waits for them to finish.
+func StopGroup(name string) {
+	for _, bee := range groupMembers(name) {
+		StopBee(bee)
+	}
+}
+
+// inGroup returns whether trigger refers to a group and the bee is one of its
+// members.
+func inGroup(trigger, beeName string) bool {
+	if !strings.HasPrefix(trigger, GroupPrefix) {
+		return false
+	}
+
+	groupsMutex.RLock()
+	defer groupsMutex.RUnlock()
+
+	g, ok := groups[strings.TrimPrefix(trigger, GroupPrefix)]
+	return ok && g.has(beeName)
+}
+
+// renameGroupMember updates the memberships of a renamed bee.
+func renameGroupMember(old, name string) {
+	groupsMutex.Lock()
+	defer groupsMutex.Unlock()
+
+	for _, g := range groups {
+		for i, m := range g.Members {
+			if m == old {
+				g.Members[i] = name
+			}
+		}
+	}
+}
+
+// removeGroupMember removes a deleted bee from all groups.
+func removeGroupMember(beeName string) {
+	groupsMutex.Lock()
+	defer groupsMutex.Unlock()
+
+	for _, g := range groups {
+		members := g.Members[:0]
+		for _, m := range g.Members {
+			if m != beeName {
+				members = append(members, m)
+			}
+		}
+		g.Members = members
+	}
+}
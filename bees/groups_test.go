@@ -0,0 +1,63 @@
+package bees
+
+import (
+	"testing"
+)
+
+func TestBeeGroups(t *testing.T) {
+	newRecordingBee("groupbee1")
+	defer DeleteBee(GetBee("groupbee1"))
+	newRecordingBee("groupbee2")
+	defer DeleteBee(GetBee("groupbee2"))
+	newRecordingBee("outsider")
+	defer DeleteBee(GetBee("outsider"))
+
+	g := CreateBeeGroup("room")
+	if g.Name != "room" || len(g.Members) != 0 {
+		t.Fatalf("Unexpected group %+v", g)
+	}
+	for _, name := range []string{"groupbee1", "groupbee2", "groupbee1"} {
+		if err := AddBeeToGroup("room", name); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := AddBeeToGroup("room", "nosuchbee"); err != ErrUnknownBee {
+		t.Errorf("Expected adding an unknown bee to fail, got %v", err)
+	}
+	if err := AddBeeToGroup("nosuchgroup", "groupbee1"); err != ErrUnknownGroup {
+		t.Errorf("Expected adding to an unknown group to fail, got %v", err)
+	}
+	if g, ok := GetGroup("room"); !ok || len(g.Members) != 2 {
+		t.Errorf("Expected group with two members, got %+v", g)
+	}
+	if g := CreateBeeGroup("room"); len(g.Members) != 2 {
+		t.Error("Expected creating an existing group to keep its members")
+	}
+
+	StopGroup("room")
+	if (*GetBee("groupbee1")).IsRunning() || (*GetBee("groupbee2")).IsRunning() {
+		t.Error("Expected group members to be stopped")
+	}
+	if !(*GetBee("outsider")).IsRunning() {
+		t.Error("Expected bees outside of the group to keep running")
+	}
+	StartGroup("room")
+	if !(*GetBee("groupbee1")).IsRunning() || !(*GetBee("groupbee2")).IsRunning() {
+		t.Error("Expected group members to be started")
+	}
+
+	c := Chain{Name: "room", Event: &Event{Bee: GroupPrefix + "room", Name: "reading"}}
+	for bee, exp := range map[string]bool{"groupbee1": true, "groupbee2": true, "outsider": false} {
+		if _, ok := c.matches(&Event{Bee: bee, Name: "reading"}); ok != exp {
+			t.Errorf("Expected event of %s to match: %v, got %v", bee, exp, ok)
+		}
+	}
+
+	if err := RenameBee("groupbee2", "groupbee3"); err != nil {
+		t.Fatal(err)
+	}
+	DeleteBee(GetBee("groupbee1"))
+	if g, _ := GetGroup("room"); len(g.Members) != 1 || g.Members[0] != "groupbee3" {
+		t.Errorf("Expected renamed and deleted bees to be reflected, got %+v", g)
+	}
+}
@@ -0,0 +1,186 @@
+/*
+ *    Copyright (C) 2026 Christian Muehlhaeuser
+ *
+ *    This program is free software: you can redistribute it and/or modify
+ *    it under the terms of the GNU Affero General Public License as published
+ *    by the Free Software Foundation, either version 3 of the License, or
+ *    (at your option) any later version.
+ *
+ *    This program is distributed in the hope that it will be useful,
+ *    but WITHOUT ANY WARRANTY; without even the implied warranty of
+ *    MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ *    GNU Affero General Public License for more details.
+ *
+ *    You should have received a copy of the GNU Affero General Public License
+ *    along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ *    Authors:
+ *      Christian Muehlhaeuser <muesli@gmail.com>
+ */
+
+// Package bees is Beehive's central module system.
+package bees
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// handoffVersion is the version of the handoff format written by this binary.
+const handoffVersion = 1
+
+// handoff contains the state passed from a beehive process to its successor.
+type handoff struct {
+	Version    int
+	Bees       []BeeConfig
+	Actions    []Action
+	Chains     []Chain
+	References map[string]interface{}            `json:",omitempty"`
+	Context    map[string]map[string]interface{} `json:",omitempty"`
+	States     map[string]beeState               `json:",omitempty"`
+
+	Timers         []Timer                                   `json:",omitempty"`
+	DelayedActions []delayedActionState                      `json:",omitempty"`
+	Correlations   map[string]map[string]correlationSnapshot `json:",omitempty"`
+}
+
+// beeState is the serialized internal state of a StatefulBee.
+type beeState struct {
+	Version int
+	Data    []byte
+}
+
+// StatefulBee is an optional interface for bees whose internal state should
+// survive a handoff. The state format is versioned: StateVersion returns the
+// version written by MarshalState, and UnmarshalState receives the version
+// the state was written with, letting bees migrate states written by older
+// versions of their code.
+type StatefulBee interface {
+	StateVersion() int
+	MarshalState() ([]byte, error)
+	UnmarshalState(version int, data []byte) error
+}
+
+// PrepareHandoff stops all bees and serializes the state of the hive, so a
+// freshly started process can pick up where this one left off by calling
+// ResumeFromHandoff. This allows upgrading the beehive binary in place.
+//
+// The following state survives a handoff:
+//   - the configuration of all bees, including options changed at runtime
+//   - all actions and chains
+//   - shared option references
+//   - the values bees stored in their context via ContextSet
+//   - the internal state of bees implementing StatefulBee
+//   - all timers, including how often they fired
+//   - pending delayed actions, which fire at their original time
+//   - partially completed correlations of chains
+//
+// Everything else is lost, most notably events that haven't been dispatched
+// yet, pending retries and deferred events, and the internal state of all
+// other bees. Context values and the options of delayed actions are
+// serialized as JSON, so numbers will be restored as float64.
+//
+// If a StatefulBee fails to serialize its state, PrepareHandoff restarts the
+// bees it stopped and returns the error, leaving the hive running.
+func PrepareHandoff() ([]byte, error) {
+	FlushDebounces()
+	FlushBatches()
+	configs := BeeConfigs()
+
+	states := make(map[string]beeState)
+	stopped := []*BeeInterface{}
+	for _, bee := range GetBees() {
+		StopBee(bee)
+		stopped = append(stopped, bee)
+
+		if sb, ok := (*bee).(StatefulBee); ok {
+			data, err := sb.MarshalState()
+			if err != nil {
+				for _, b := range stopped {
+					RestartBee(b)
+				}
+				return nil, fmt.Errorf("Bee %s: %v", (*bee).Name(), err)
+			}
+			states[(*bee).Name()] = beeState{
+				Version: sb.StateVersion(),
+				Data:    data,
+			}
+		}
+	}
+	ts := takeTimers()
+	delayed := takeDelayedActions()
+	StopBees()
+
+	referenceMutex.RLock()
+	refs := make(map[string]interface{})
+	for k, v := range references {
+		refs[k] = v
+	}
+	referenceMutex.RUnlock()
+
+	h := handoff{
+		Version:    handoffVersion,
+		Bees:       configs,
+		Actions:    GetActions(),
+		Chains:     GetChains(),
+		References: refs,
+		Context:    ctx.snapshot(),
+		States:     states,
+
+		Timers:         ts,
+		DelayedActions: delayed,
+		Correlations:   snapshotCorrelations(),
+	}
+
+	return json.Marshal(h)
+}
+
+// ResumeFromHandoff restores the state serialized by PrepareHandoff and
+// starts all bees. StatefulBees get their state restored before they start;
+// if that fails, the error gets logged and the bee starts from scratch. The
+// same goes for timers that can't be scheduled again.
+func ResumeFromHandoff(data []byte) error {
+	var h handoff
+	if err := json.Unmarshal(data, &h); err != nil {
+		return err
+	}
+	if h.Version != handoffVersion {
+		return fmt.Errorf("Unsupported handoff version %d", h.Version)
+	}
+
+	SetReferences(h.References)
+	for _, b := range h.Bees {
+		if _, err := ResolveOptions(b.Options); err != nil {
+			return fmt.Errorf("Bee %s: %v", b.Name, err)
+		}
+	}
+
+	SetActions(h.Actions)
+	SetChains(h.Chains)
+	if h.Context != nil {
+		ctx.restore(h.Context)
+	}
+	restoreCorrelations(h.Correlations)
+
+	go handleEvents(openEventQueue())
+
+	for _, b := range h.Bees {
+		bee, err := NewBeeInstance(b)
+		if err != nil {
+			logger.Errorf("Skipping bee %v: %v", b.Name, err)
+			continue
+		}
+		if st, ok := h.States[b.Name]; ok {
+			if sb, ok := (*bee).(StatefulBee); ok {
+				if err := sb.UnmarshalState(st.Version, st.Data); err != nil {
+					logger.Errorf("Failed to restore state of bee %v: %v", b.Name, err)
+				}
+			}
+		}
+		launchBee(bee)
+	}
+	restoreTimers(h.Timers)
+	restoreDelayedActions(h.DelayedActions)
+
+	return nil
+}
@@ -0,0 +1,212 @@
+package bees
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+)
+
+// counterBee keeps a counter as internal state. Version 1 of its state
+// stored the counter as decimal string, version 2 stores it in hex. Its
+// state can't be serialized while broken is set.
+type counterBee struct {
+	recordingBee
+
+	counter int64
+	broken  bool
+}
+
+func (mod *counterBee) StateVersion() int {
+	return 2
+}
+
+func (mod *counterBee) MarshalState() ([]byte, error) {
+	if mod.broken {
+		return nil, errors.New("broken counter")
+	}
+	return []byte(strconv.FormatInt(mod.counter, 16)), nil
+}
+
+func (mod *counterBee) UnmarshalState(version int, data []byte) error {
+	base := 16
+	if version == 1 {
+		base = 10
+	}
+
+	var err error
+	mod.counter, err = strconv.ParseInt(string(data), base, 64)
+	return err
+}
+
+func init() {
+	RegisterFactory(&testFactory{
+		id: "counterbee",
+		newBee: func(name, description string, options BeeOptions) BeeInterface {
+			return &counterBee{recordingBee: recordingBee{Bee: NewBee(name, "counterbee", description, options)}}
+		},
+	})
+}
+
+func TestHandoffState(t *testing.T) {
+	defer useEventChannel(make(chan Event))()
+
+	mod, _ := NewBeeInstance(BeeConfig{Name: "counter", Class: "counterbee"})
+	launchBee(mod)
+	(*mod).(*counterBee).counter = 42
+
+	data, err := PrepareHandoff()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := ResumeFromHandoff(data); err != nil {
+		t.Fatal(err)
+	}
+	defer StopBees()
+
+	bee := GetBee("counter")
+	if bee == nil {
+		t.Fatal("Expected bee to be restored")
+	}
+	if c := (*bee).(*counterBee).counter; c != 42 {
+		t.Errorf("Expected counter 42, got %d", c)
+	}
+
+}
+
+func TestHandoffStateError(t *testing.T) {
+	defer useEventChannel(make(chan Event))()
+
+	mod, _ := NewBeeInstance(BeeConfig{Name: "brokencounter", Class: "counterbee"})
+	launchBee(mod)
+	defer DeleteBee(GetBee("brokencounter"))
+	(*mod).(*counterBee).broken = true
+
+	if _, err := PrepareHandoff(); err == nil {
+		t.Fatal("Expected an error handing off a bee that can't serialize its state")
+	}
+	if bee := GetBee("brokencounter"); bee == nil || !(*bee).IsRunning() {
+		t.Error("Expected the bee to keep running after a failed handoff")
+	}
+}
+
+func TestHandoffSchedules(t *testing.T) {
+	defer useEventChannel(make(chan Event))()
+	oldChains := GetChains()
+	defer SetChains(oldChains)
+
+	down := &Event{Bee: "monitorbee", Name: "down"}
+	c := Chain{Name: "handoff-correlation", Correlation: &Correlation{
+		Events: []*Event{down, {Bee: "cibee", Name: "deployed"}},
+		Window: time.Hour,
+	}}
+	SetChains([]Chain{c})
+	defer deleteCorrelation(c.Name)
+	correlateEvent(c, down, map[string]interface{}{})
+
+	if err := AddTimer(Timer{Name: "handofftimer", Interval: time.Hour}); err != nil {
+		t.Fatal(err)
+	}
+	defer RemoveTimer("handofftimer")
+	timersMutex.Lock()
+	timers["handofftimer"].Fired = 3
+	timersMutex.Unlock()
+
+	scheduleAction(withChainScope(context.Background(), "team"), Action{Bee: "handoffdelaybee", Name: "later", Delay: time.Hour}, nil, &Event{ID: "cause"})
+	defer CancelDelayedActions()
+
+	data, err := PrepareHandoff()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if GetTimer("handofftimer") != nil || PendingActions() != 0 {
+		t.Error("Expected timers and delayed actions to be handed off")
+	}
+
+	deleteCorrelation(c.Name)
+	if err := ResumeFromHandoff(data); err != nil {
+		t.Fatal(err)
+	}
+	defer StopBees()
+
+	if tm := GetTimer("handofftimer"); tm == nil || tm.Fired != 3 || tm.Next.IsZero() {
+		t.Errorf("Expected the timer to be restored and scheduled, got %+v", tm)
+	}
+	delayedActionsMutex.Lock()
+	var restored []delayedAction
+	for d := range delayedActions {
+		restored = append(restored, *d)
+	}
+	delayedActionsMutex.Unlock()
+	if len(restored) != 1 || restored[0].cause.ID != "cause" || restored[0].scope != "team" || time.Until(restored[0].due) < 59*time.Minute {
+		t.Errorf("Expected the delayed action to be restored, got %+v", restored)
+	}
+	correlationMutex.Lock()
+	cc := correlations[c.Name]
+	correlationMutex.Unlock()
+	if cc == nil || len(cc.states) != 1 {
+		t.Errorf("Expected the partial correlation to be restored, got %+v", cc)
+	}
+}
+
+func TestHandoff(t *testing.T) {
+	oldActions := GetActions()
+	defer SetActions(oldActions)
+	oldChains := GetChains()
+	defer SetChains(oldChains)
+
+	mod := newRecordingBee("handoffbee")
+	SetActions([]Action{{ID: "handoff-post", Bee: "handoffbee", Name: "post"}})
+	SetChains([]Chain{{Name: "handoff", Event: &Event{Bee: "handoffbee", Name: "tick"}, Actions: []string{"handoff-post"}}})
+	mod.ContextSet("seen", "hello")
+
+	// bees may still update their context while the hive gets handed off
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		for i := 0; ; i++ {
+			select {
+			case <-done:
+				return
+			default:
+				mod.ContextSet("count", i)
+			}
+		}
+	}()
+	data, err := PrepareHandoff()
+	close(done)
+	<-stopped
+	if err != nil {
+		t.Fatal(err)
+	}
+	if GetBee("handoffbee") != nil {
+		t.Error("Expected the bees to be stopped before handing off")
+	}
+
+	SetActions(nil)
+	SetChains(nil)
+	if err := ResumeFromHandoff(data); err != nil {
+		t.Fatal(err)
+	}
+	defer StopBees()
+
+	bee := GetBee("handoffbee")
+	if bee == nil || !(*bee).IsRunning() {
+		t.Fatal("Expected the bee to be restored and running")
+	}
+	if a := GetActions(); len(a) != 1 || a[0].ID != "handoff-post" {
+		t.Errorf("Expected the actions to be restored, got %+v", a)
+	}
+	if c := GetChain("handoff"); c == nil || len(c.Actions) != 1 {
+		t.Errorf("Expected the chains to be restored, got %+v", c)
+	}
+	if v := (*bee).(*recordingBee).ContextValue("seen"); v != "hello" {
+		t.Errorf("Expected the bee's context to be restored, got %v", v)
+	}
+
+	if err := ResumeFromHandoff([]byte(`{"Version":0}`)); err == nil {
+		t.Error("Expected an error resuming from an unsupported handoff version")
+	}
+}
@@ -0,0 +1,110 @@
+package bees_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/muesli/beehive/bees"
+	"github.com/muesli/beehive/bees/beestest"
+)
+
+// sayBee emits a "message" event for every "say" action.
+type sayBee struct {
+	bees.Bee
+}
+
+func (mod *sayBee) Run(ctx context.Context, eventChan chan bees.Event) {
+	<-ctx.Done()
+}
+
+func (mod *sayBee) ReloadOptions(options bees.BeeOptions) {
+	mod.SetOptions(options)
+}
+
+func (mod *sayBee) Action(ctx context.Context, action bees.Action) []bees.Placeholder {
+	if action.Name == "say" {
+		if err := bees.EmitEvent(mod, "message", action.Options...); err != nil {
+			panic(err)
+		}
+	}
+	return nil
+}
+
+var sayBeeFactory = beestest.RegisterFactory(&beestest.Factory{
+	FactoryID: "saybee",
+	EventDescriptors: []bees.EventDescriptor{
+		{
+			Namespace: "saybee",
+			Name:      "message",
+			Options: []bees.PlaceholderDescriptor{
+				{Name: "text", Type: "string"},
+				{Name: "user", Type: "string"},
+			},
+		},
+	},
+	NewBee: func(name, description string, options bees.BeeOptions) bees.BeeInterface {
+		return &sayBee{Bee: bees.NewBee(name, "saybee", description, options)}
+	},
+})
+
+func TestHarnessEmitEvent(t *testing.T) {
+	bees.SetStrictEvents(true)
+	defer bees.SetStrictEvents(false)
+
+	h := beestest.New(t, sayBeeFactory.New("emitter", "", nil))
+	defer h.Close()
+
+	h.Action("say", map[string]interface{}{"text": "hello", "user": "muesli"})
+	ev, err := h.WaitForEvent("message", time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ev.Bee != "emitter" || ev.Options.Value("text") != "hello" || ev.Options.Value("user") != "muesli" {
+		t.Errorf("Unexpected event %+v", ev)
+	}
+	if h.Bee().LastEvent().IsZero() {
+		t.Error("Expected event to be logged")
+	}
+}
+
+// pokeBee emits a "poked" event whenever it gets triggered.
+type pokeBee struct {
+	bees.Bee
+}
+
+func (mod *pokeBee) Run(ctx context.Context, eventChan chan bees.Event) {
+	<-ctx.Done()
+}
+
+func (mod *pokeBee) ReloadOptions(options bees.BeeOptions) {
+	mod.SetOptions(options)
+}
+
+func (mod *pokeBee) Trigger(eventChan chan bees.Event) error {
+	eventChan <- bees.Event{Bee: mod.Name(), Name: "poked"}
+	return nil
+}
+
+var pokeBeeFactory = beestest.RegisterFactory(&beestest.Factory{
+	FactoryID: "pokebee",
+	NewBee: func(name, description string, options bees.BeeOptions) bees.BeeInterface {
+		return &pokeBee{Bee: bees.NewBee(name, "pokebee", description, options)}
+	},
+})
+
+func TestHarnessTriggerBee(t *testing.T) {
+	h := beestest.New(t, pokeBeeFactory.New("poker", "", nil))
+	defer h.Close()
+
+	if err := bees.TriggerBee("poker"); err != nil {
+		t.Fatal(err)
+	}
+	ev, err := h.WaitForEvent("poked", time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ev.Bee != "poker" {
+		t.Errorf("Unexpected event %+v", ev)
+	}
+}
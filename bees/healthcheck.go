@@ -0,0 +1,145 @@
+/*
+ *    Copyright (C) 2026 Christian Muehlhaeuser
+ *
+ *    This program is free software: you can redistribute it and/or modify
+ *    it under the terms of the GNU Affero General Public License as published
+ *    by the Free Software Foundation, either version 3 of the License, or
+ *    (at your option) any later version.
+ *
+ *    This program is distributed in the hope that it will be useful,
+ *    but WITHOUT ANY WARRANTY; without even the implied warranty of
+ *    MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ *    GNU Affero General Public License for more details.
+ *
+ *    You should have received a copy of the GNU Affero General Public License
+ *    along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ *    Authors:
+ *      Christian Muehlhaeuser <muesli@gmail.com>
+ */
+
+// Package bees is Beehive's central module system.
+package bees
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// DefaultHealthCheckInterval is the interval in which StartBees checks the
+// health of all running bees.
+const DefaultHealthCheckInterval = time.Minute
+
+var (
+	healthCheckInterval = int64(DefaultHealthCheckInterval)
+
+	healthChecksMutex sync.Mutex
+	healthChecksStop  chan struct{}
+)
+
+// SetHealthCheckInterval sets the interval in which the health of running
+// bees gets checked. An interval of 0 disables health checks.
+func SetHealthCheckInterval(interval time.Duration) {
+	atomic.StoreInt64(&healthCheckInterval, int64(interval))
+}
+
+// CheckBeeHealth runs the health check of the bee with a specific name.
+func CheckBeeHealth(name string) error {
+	bee := GetBee(name)
+	if bee == nil {
+		return ErrUnknownBee
+	}
+	if !(*bee).IsRunning() {
+		return ErrBeeNotRunning
+	}
+
+	return (*bee).HealthCheck()
+}
+
+// CheckAllBeeHealth runs the health checks of all running bees. The returned
+// map contains the result of each bee's check, keyed by bee name.
+func CheckAllBeeHealth() map[string]error {
+	r := make(map[string]error)
+	for _, bee := range GetBees() {
+		if (*bee).IsRunning() {
+			r[(*bee).Name()] = (*bee).HealthCheck()
+		}
+	}
+
+	return r
+}
+
+// startHealthChecks starts checking the health of all bees in the
+// background, replacing previously started checks.
+func startHealthChecks() {
+	healthChecksMutex.Lock()
+	defer healthChecksMutex.Unlock()
+
+	if healthChecksStop != nil {
+		close(healthChecksStop)
+	}
+	healthChecksStop = make(chan struct{})
+	go watchHealth(healthChecksStop)
+}
+
+// stopHealthChecks stops the checks started by startHealthChecks.
+func stopHealthChecks() {
+	healthChecksMutex.Lock()
+	defer healthChecksMutex.Unlock()
+
+	if healthChecksStop != nil {
+		close(healthChecksStop)
+		healthChecksStop = nil
+	}
+}
+
+// watchHealth checks the health of all bees until stop gets closed. Unhealthy
+// bees get restarted. Bees that stay unhealthy get restarted with an
+// exponential backoff: after n consecutive restarts, the next one happens no
+// earlier than 2^n intervals later, up to maxBackoff intervals.
+func watchHealth(stop chan struct{}) {
+	failures := make(map[string]int)
+	nextRestart := make(map[string]time.Time)
+
+	for {
+		interval := time.Duration(atomic.LoadInt64(&healthCheckInterval))
+		wait := interval
+		if wait <= 0 {
+			// disabled, check again later whether that changed
+			wait = DefaultHealthCheckInterval
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-stop:
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+		if interval <= 0 {
+			continue
+		}
+
+		for name, err := range CheckAllBeeHealth() {
+			if err == nil {
+				delete(failures, name)
+				delete(nextRestart, name)
+				continue
+			}
+			bee := GetBee(name)
+			if bee == nil {
+				continue
+			}
+			if time.Now().Before(nextRestart[name]) {
+				logBeef(*bee, LogDebug, "Still unhealthy, waiting before restarting again: %v", err)
+				continue
+			}
+
+			logBeef(*bee, LogError, "Health check failed, restarting bee: %v", err)
+			failures[name]++
+			nextRestart[name] = time.Now().Add(interval * time.Duration(backoff(failures[name])))
+			RestartBee(bee)
+		}
+	}
+}
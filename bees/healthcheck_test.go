@@ -0,0 +1,76 @@
+package bees
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type unhealthyBee struct {
+	recordingBee
+
+	healthy int32
+	runs    int32
+}
+
+func (mod *unhealthyBee) Run(ctx context.Context, eventChan chan Event) {
+	atomic.AddInt32(&mod.runs, 1)
+	mod.recordingBee.Run(ctx, eventChan)
+}
+
+func (mod *unhealthyBee) HealthCheck() error {
+	if atomic.LoadInt32(&mod.healthy) == 0 {
+		return errors.New("connection lost")
+	}
+	return nil
+}
+
+func TestCheckBeeHealth(t *testing.T) {
+	mod := &unhealthyBee{recordingBee: recordingBee{Bee: NewBee("unhealthybee", "recordingbee", "", BeeOptions{})}}
+	RegisterBee(mod)
+	defer DeleteBee(GetBee("unhealthybee"))
+
+	if err := CheckBeeHealth("nosuchbee"); err != ErrUnknownBee {
+		t.Errorf("Expected ErrUnknownBee, got %v", err)
+	}
+	if err := CheckBeeHealth("unhealthybee"); err != ErrBeeNotRunning {
+		t.Errorf("Expected ErrBeeNotRunning, got %v", err)
+	}
+
+	launchBee(GetBee("unhealthybee"))
+	if err := CheckBeeHealth("unhealthybee"); err == nil {
+		t.Error("Expected bee to be unhealthy")
+	}
+	if errs := CheckAllBeeHealth(); errs["unhealthybee"] == nil {
+		t.Errorf("Expected bee to be reported unhealthy, got %v", errs)
+	}
+}
+
+func TestHealthCheckRestarts(t *testing.T) {
+	SetHealthCheckInterval(10 * time.Millisecond)
+	defer SetHealthCheckInterval(DefaultHealthCheckInterval)
+
+	mod := &unhealthyBee{recordingBee: recordingBee{Bee: NewBee("restartedbee", "recordingbee", "", BeeOptions{})}}
+	RegisterBee(mod)
+	defer DeleteBee(GetBee("restartedbee"))
+	launchBee(GetBee("restartedbee"))
+
+	stop := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		watchHealth(stop)
+		close(done)
+	}()
+	time.Sleep(200 * time.Millisecond)
+	atomic.StoreInt32(&mod.healthy, 1)
+	close(stop)
+	<-done
+
+	// with backoff, restarts happen after 1, 3, 7 and 15 intervals
+	runs := atomic.LoadInt32(&mod.runs)
+	if runs < 3 || runs > 8 {
+		t.Errorf("Expected a few backed off restarts, got %d runs", runs)
+	}
+}
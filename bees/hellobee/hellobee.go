@@ -25,6 +25,8 @@
 package hellobee
 
 import (
+	"context"
+
 	"github.com/muesli/beehive/bees"
 )
 
@@ -35,7 +37,7 @@ type HelloBee struct {
 }
 
 // Run executes the Bee's event loop.
-func (mod *HelloBee) Run(eventChan chan bees.Event) {
+func (mod *HelloBee) Run(ctx context.Context, eventChan chan bees.Event) {
 	/*	ev := bees.Event{
 			Bee: mod.Name(),
 			Name:      "hello",
@@ -46,7 +48,7 @@ func (mod *HelloBee) Run(eventChan chan bees.Event) {
 }
 
 // Action triggers the action passed to it.
-func (mod *HelloBee) Action(action bees.Action) []bees.Placeholder {
+func (mod *HelloBee) Action(ctx context.Context, action bees.Action) []bees.Placeholder {
 	return []bees.Placeholder{}
 }
 
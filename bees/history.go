@@ -0,0 +1,162 @@
+/*
+ *    Copyright (C) 2026 Christian Muehlhaeuser
+ *
+ *    This program is free software: you can redistribute it and/or modify
+ *    it under the terms of the GNU Affero General Public License as published
+ *    by the Free Software Foundation, either version 3 of the License, or
+ *    (at your option) any later version.
+ *
+ *    This program is distributed in the hope that it will be useful,
+ *    but WITHOUT ANY WARRANTY; without even the implied warranty of
+ *    MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ *    GNU Affero General Public License for more details.
+ *
+ *    You should have received a copy of the GNU Affero General Public License
+ *    along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ *    Authors:
+ *      Christian Muehlhaeuser <muesli@gmail.com>
+ */
+
+// Package bees is Beehive's central module system.
+package bees
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// DefaultChainHistorySize is the default number of executions kept in the
+// history of each chain.
+const DefaultChainHistorySize = 100
+
+// ChainExecution describes a single execution of a chain.
+type ChainExecution struct {
+	ID           string
+	ChainName    string
+	TriggerEvent Event
+	StartedAt    time.Time
+	Duration     time.Duration
+	Err          error `json:"-"`
+	// Error holds the message of Err, as Err itself can't be serialized
+	Error string `json:",omitempty"`
+}
+
+// ActionResult describes the outcome of replaying a chain execution.
+type ActionResult struct {
+	// ExecutionID identifies the new execution in the history
+	ExecutionID string
+	ChainName   string
+	Duration    time.Duration
+	Err         error `json:"-"`
+}
+
+var (
+	history          = make(map[string][]ChainExecution)
+	chainHistorySize = DefaultChainHistorySize
+	historyMutex     sync.RWMutex
+)
+
+// SetChainHistorySize sets the number of executions kept in the history of
+// each chain.
+func SetChainHistorySize(n int) {
+	if n < 1 {
+		n = 1
+	}
+
+	historyMutex.Lock()
+	defer historyMutex.Unlock()
+
+	chainHistorySize = n
+	for name, execs := range history {
+		history[name] = trimHistory(execs, n)
+	}
+}
+
+// trimHistory drops the oldest executions exceeding size.
+func trimHistory(execs []ChainExecution, size int) []ChainExecution {
+	if len(execs) <= size {
+		return execs
+	}
+
+	trimmed := make([]ChainExecution, size)
+	copy(trimmed, execs[len(execs)-size:])
+	return trimmed
+}
+
+// recordExecution adds a chain execution to the chain's history.
+func recordExecution(exec ChainExecution) {
+	historyMutex.Lock()
+	defer historyMutex.Unlock()
+
+	history[exec.ChainName] = trimHistory(append(history[exec.ChainName], exec), chainHistorySize)
+}
+
+// GetChainHistory returns the recent executions of a chain, oldest first.
+func GetChainHistory(chainName string) []ChainExecution {
+	historyMutex.RLock()
+	defer historyMutex.RUnlock()
+
+	execs := make([]ChainExecution, len(history[chainName]))
+	copy(execs, history[chainName])
+	return execs
+}
+
+// GetAllChainHistory returns the recent executions of all chains, keyed by
+// the chain's name.
+func GetAllChainHistory() map[string][]ChainExecution {
+	historyMutex.RLock()
+	defer historyMutex.RUnlock()
+
+	r := make(map[string][]ChainExecution, len(history))
+	for name, execs := range history {
+		r[name] = append([]ChainExecution(nil), execs...)
+	}
+	return r
+}
+
+// GetChainExecution returns the chain execution with a specific ID.
+func GetChainExecution(id string) (ChainExecution, bool) {
+	historyMutex.RLock()
+	defer historyMutex.RUnlock()
+
+	for _, execs := range history {
+		for _, exec := range execs {
+			if exec.ID == id {
+				return exec, true
+			}
+		}
+	}
+
+	return ChainExecution{}, false
+}
+
+// ReplayChain re-executes a chain from the history against the event that
+// originally triggered it, e.g. after fixing the cause of a failure. The
+// chain's current definition is used. The returned error is either set when
+// the execution can't be replayed, or contains the error of the replayed
+// execution itself.
+func ReplayChain(historyID string) (ActionResult, error) {
+	exec, ok := GetChainExecution(historyID)
+	if !ok {
+		return ActionResult{}, errors.New("No chain execution with that ID found")
+	}
+	c := GetChain(exec.ChainName)
+	if c == nil {
+		return ActionResult{}, errors.New("Chain " + exec.ChainName + " does not exist anymore")
+	}
+
+	r := execChain(context.Background(), *c, &exec.TriggerEvent, nil, true)
+	if r == nil {
+		return ActionResult{}, errors.New("Event did not pass the chain's filters")
+	}
+
+	return ActionResult{
+		ExecutionID: r.ID,
+		ChainName:   r.ChainName,
+		Duration:    r.Duration,
+		Err:         r.Err,
+	}, r.Err
+}
@@ -0,0 +1,375 @@
+/*
+ *    Copyright (C) 2026 Christian Muehlhaeuser
+ *
+ *    This program is free software: you can redistribute it and/or modify
+ *    it under the terms of the GNU Affero General Public License as published
+ *    by the Free Software Foundation, either version 3 of the License, or
+ *    (at your option) any later version.
+ *
+ *    This program is distributed in the hope that it will be useful,
+ *    but WITHOUT ANY WARRANTY; without even the implied warranty of
+ *    MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ *    GNU Affero General Public License for more details.
+ *
+ *    You should have received a copy of the GNU Affero General Public License
+ *    along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ *    Authors:
+ *      Christian Muehlhaeuser <muesli@gmail.com>
+ */
+
+// Package bees is Beehive's central module system.
+package bees
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// ErrHiveRunning is returned when starting a Hive while a hive is already
+// running in the same process.
+var ErrHiveRunning = errors.New("A hive is already running in this process")
+
+// HiveOptions configure a Hive, see NewHive. They mirror the parts of
+// Beehive's configuration file the hive itself cares about.
+type HiveOptions struct {
+	Bees        []BeeConfig
+	Actions     []Action
+	Chains      []Chain
+	Profiles    []Profile
+	References  map[string]interface{}
+	Enrichments []Enrichment
+
+	// DataDir is the directory bees persist their state in, see SetDataDir
+	DataDir string
+	// DryRun makes all chains only log the actions they would execute
+	DryRun bool
+	// StopTimeout overrides the time Stop waits for bees to stop, see
+	// SetStopTimeout
+	StopTimeout time.Duration
+}
+
+// A Hive runs bees and chains inside another Go program:
+//
+//	hive := bees.NewHive(bees.HiveOptions{Bees: ..., Actions: ..., Chains: ...})
+//	if err := hive.Start(); err != nil {
+//		...
+//	}
+//	defer hive.Stop()
+//
+// A Hive is not an isolated instance: Start loads its options into the
+// package-level state of this package, i.e. the process' bees, chains,
+// actions and event queue, whose functions keep working while the hive is
+// running. Hence only a single hive can run per process at a time, starting
+// another one fails with ErrHiveRunning. While it's stopped, a hive's methods
+// only work on its options and leave the process' state alone, so they don't
+// interfere with a running hive. Use profiles to host several isolated setups
+// in one hive, see Profile.
+type Hive struct {
+	mutex   sync.Mutex
+	opts    HiveOptions
+	running bool
+}
+
+var (
+	runningHive      *Hive
+	runningHiveMutex sync.Mutex
+)
+
+// NewHive returns a new, stopped hive.
+func NewHive(opts HiveOptions) *Hive {
+	return &Hive{opts: opts}
+}
+
+// Start loads the hive's options and starts its bees and the event loop.
+// Bees that can't be started get skipped, like with StartBees. Their errors
+// get returned, combined into one, while the hive keeps running nevertheless.
+func (h *Hive) Start() error {
+	runningHiveMutex.Lock()
+	defer runningHiveMutex.Unlock()
+	if runningHive != nil {
+		return ErrHiveRunning
+	}
+
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	if err := SetProfiles(h.opts.Profiles); err != nil {
+		return err
+	}
+	if h.opts.DataDir != "" {
+		SetDataDir(h.opts.DataDir)
+	}
+	SetGlobalDryRun(h.opts.DryRun)
+	SetReferences(h.opts.References)
+	SetEnrichments(h.opts.Enrichments)
+	SetActions(h.opts.Actions)
+	SetChains(h.opts.Chains)
+
+	runningHive = h
+	h.running = true
+	return StartBeesOrdered(h.opts.Bees)
+}
+
+// Stop stops the hive's bees and the event loop, see StopBeesTimeout. The
+// bees, actions and chains of the running hive get kept, so Options reflects
+// the changes made while it ran and a subsequent Start resumes them.
+func (h *Hive) Stop() error {
+	runningHiveMutex.Lock()
+	defer runningHiveMutex.Unlock()
+
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	if !h.running {
+		return nil
+	}
+
+	h.opts.Bees = BeeConfigs()
+	h.opts.Actions = GetActions()
+	h.opts.Chains = GetChains()
+
+	timeout := h.opts.StopTimeout
+	if timeout == 0 {
+		timeout = time.Duration(atomic.LoadInt64(&stopTimeout))
+	}
+	err := StopBeesTimeout(timeout)
+
+	h.running = false
+	runningHive = nil
+	return err
+}
+
+// Running returns whether the hive is running.
+func (h *Hive) Running() bool {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	return h.running
+}
+
+// Options returns the hive's options. While it's running, they contain its
+// current bees, actions and chains, e.g. to be persisted.
+func (h *Hive) Options() HiveOptions {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	opts := h.opts
+	if h.running {
+		opts.Bees = BeeConfigs()
+		opts.Actions = GetActions()
+		opts.Chains = GetChains()
+	}
+	return opts
+}
+
+// Bees returns the configs of all running bees, or those of the options
+// while the hive is stopped.
+func (h *Hive) Bees() []BeeConfig {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	if !h.running {
+		return append([]BeeConfig{}, h.opts.Bees...)
+	}
+
+	return BeeConfigs()
+}
+
+// CreateBee creates and starts a new bee.
+func (h *Hive) CreateBee(c BeeConfig) (*BeeInterface, error) {
+	if !h.Running() {
+		return nil, ErrNotHandling
+	}
+
+	return StartBee(c)
+}
+
+// DeleteBee stops and removes the bee with a specific name. While the hive is
+// stopped, the bee only gets removed from its options.
+func (h *Hive) DeleteBee(name string) error {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	if !h.running {
+		for i, c := range h.opts.Bees {
+			if c.Name == name {
+				h.opts.Bees = append(h.opts.Bees[:i:i], h.opts.Bees[i+1:]...)
+				return nil
+			}
+		}
+		return fmt.Errorf("Bee %s does not exist", name)
+	}
+
+	bee := GetBee(name)
+	if bee == nil {
+		return fmt.Errorf("Bee %s does not exist", name)
+	}
+
+	DeleteBee(bee)
+	return nil
+}
+
+// PauseBee pauses the bee with a specific name, see PauseBee.
+func (h *Hive) PauseBee(name string) error {
+	if !h.Running() {
+		return ErrNotHandling
+	}
+
+	return PauseBee(name)
+}
+
+// ResumeBee resumes the bee with a specific name, see ResumeBee.
+func (h *Hive) ResumeBee(name string) error {
+	if !h.Running() {
+		return ErrNotHandling
+	}
+
+	return ResumeBee(name)
+}
+
+// Chains returns all chains.
+func (h *Hive) Chains() []Chain {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	if !h.running {
+		return append([]Chain{}, h.opts.Chains...)
+	}
+
+	return GetChains()
+}
+
+// PutChain validates a chain, then replaces the chain of the same name or
+// adds it, if there is none.
+func (h *Hive) PutChain(c Chain) error {
+	if errs := ValidateChain(c); len(errs) > 0 {
+		return validationError(errs)
+	}
+
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	if !h.running {
+		for i, old := range h.opts.Chains {
+			if old.Name == c.Name {
+				h.opts.Chains[i] = c
+				return nil
+			}
+		}
+		h.opts.Chains = append(h.opts.Chains, c)
+		return nil
+	}
+
+	if !UpdateChain(c.Name, c) {
+		AddChain(c)
+	}
+	return nil
+}
+
+// RemoveChain removes the chain with a specific name. Returns whether such a
+// chain existed.
+func (h *Hive) RemoveChain(name string) bool {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	if !h.running {
+		for i, c := range h.opts.Chains {
+			if c.Name == name {
+				h.opts.Chains = append(h.opts.Chains[:i:i], h.opts.Chains[i+1:]...)
+				return true
+			}
+		}
+		return false
+	}
+
+	return RemoveChain(name)
+}
+
+// Actions returns all configured actions.
+func (h *Hive) Actions() []Action {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	if !h.running {
+		return append([]Action{}, h.opts.Actions...)
+	}
+
+	return GetActions()
+}
+
+// PutAction replaces the action sharing a's ID, or adds it. Actions without
+// an ID get a new one assigned. Returns the stored action.
+func (h *Hive) PutAction(a Action) Action {
+	if len(a.ID) == 0 {
+		a.ID = UUID()
+	}
+
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	if !h.running {
+		for i, old := range h.opts.Actions {
+			if old.ID == a.ID {
+				h.opts.Actions[i] = a
+				return a
+			}
+		}
+		h.opts.Actions = append(h.opts.Actions, a)
+		return a
+	}
+
+	if !UpdateAction(a) {
+		SetActions(append(GetActions(), a))
+	}
+	return a
+}
+
+// InjectEvent pushes an event through the hive's chains, see InjectEvent.
+func (h *Hive) InjectEvent(ctx context.Context, event Event) (InjectResult, error) {
+	if !h.Running() {
+		return InjectResult{}, ErrNotHandling
+	}
+
+	return InjectEvent(ctx, event)
+}
+
+// ExecuteAction executes an action on its bee right away, without a chain,
+// and returns its results. Like with BroadcastAction, templates in the
+// action's options get rendered without any placeholders. Actions that don't
+// match their bee's descriptors return a *ValidationError.
+func (h *Hive) ExecuteAction(ctx context.Context, a Action) (res []Placeholder, err error) {
+	if !h.Running() {
+		return nil, ErrNotHandling
+	}
+
+	bee := GetBee(a.Bee)
+	if bee == nil {
+		return nil, fmt.Errorf("Bee %s does not exist", a.Bee)
+	}
+	if !(*bee).IsRunning() {
+		return nil, fmt.Errorf("Bee %s is not running", a.Bee)
+	}
+	v := a
+	if len(v.ID) == 0 {
+		// name the action in errors
+		v.ID = a.Bee + "/" + a.Name
+	}
+	if errs := ValidateAction(v); len(errs) > 0 {
+		return nil, validationError(errs)
+	}
+
+	defer func() {
+		if e := recover(); e != nil {
+			err = fmt.Errorf("%v", e)
+		}
+	}()
+	return execAction(ctx, a, map[string]interface{}{}, nil), nil
+}
+
+// Watch returns a channel delivering the events matching pattern, see Watch.
+// The channel isn't bound to the hive, but receives all events handled in the
+// process, e.g. those of the hive once it got started.
+func (h *Hive) Watch(pattern string) <-chan Event {
+	return Watch(pattern)
+}
+
+// Unwatch closes a channel returned by Watch, see Unwatch.
+func (h *Hive) Unwatch(ch <-chan Event) {
+	Unwatch(ch)
+}
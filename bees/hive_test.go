@@ -0,0 +1,167 @@
+package bees
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestHive(t *testing.T) {
+	oldActions := GetActions()
+	defer SetActions(oldActions)
+	oldChains := chains
+	defer func() {
+		// stopped hives may still be draining their event queue
+		chainsMutex.Lock()
+		chains = oldChains
+		chainsMutex.Unlock()
+	}()
+
+	hive := NewHive(HiveOptions{
+		Bees:    []BeeConfig{{Name: "hivesink", Class: "recordingbee"}},
+		Actions: []Action{{ID: "hive-action", Bee: "hivesink", Name: "relay"}},
+		Chains: []Chain{{
+			Name:    "hive-chain",
+			Event:   &Event{Bee: "hivesource", Name: "ping"},
+			Actions: []string{"hive-action"},
+		}},
+		StopTimeout: time.Second,
+	})
+	if err := hive.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer hive.Stop()
+	if err := NewHive(HiveOptions{}).Start(); err != ErrHiveRunning {
+		t.Errorf("Expected a second hive not to start, got %v", err)
+	}
+
+	r, err := hive.InjectEvent(context.Background(), Event{Bee: "hivesource", Name: "ping"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(r.Chains) != 1 || r.Chains[0] != "hive-chain" {
+		t.Errorf("Expected event to trigger hive-chain, got %v", r.Chains)
+	}
+
+	res, err := hive.ExecuteAction(context.Background(), Action{Bee: "hivesink", Name: "shorten"})
+	if err != nil || len(res) != 1 || res[0].Value != "https://sho.rt/1" {
+		t.Errorf("Expected the action's result, got %v %v", res, err)
+	}
+	if _, err := hive.ExecuteAction(context.Background(), Action{Bee: "hivesink", Name: "fail"}); err == nil {
+		t.Error("Expected failing action to return an error")
+	}
+	if _, err := hive.ExecuteAction(context.Background(), Action{Bee: "nosuchbee", Name: "relay"}); err == nil {
+		t.Error("Expected action on unknown bee to return an error")
+	}
+	sink := (*GetBee("hivesink")).(*recordingBee)
+	if got := sink.executed(); len(got) != 3 || got[0] != "relay" || got[1] != "shorten" {
+		t.Errorf("Expected relay, shorten and fail to be executed, got %v", got)
+	}
+
+	hive.PutChain(Chain{Name: "hive-chain-2", Event: &Event{Bee: "hivesource", Name: "pong"}})
+	if err := hive.Stop(); err != nil {
+		t.Fatal(err)
+	}
+	if hive.Running() || GetBee("hivesink") != nil {
+		t.Fatal("Expected hive to be stopped")
+	}
+	if opts := hive.Options(); len(opts.Bees) != 1 || len(opts.Chains) != 2 {
+		t.Errorf("Expected options to keep the bee and both chains, got %+v", opts)
+	}
+
+	// restarting resumes where the hive stopped
+	if err := hive.Start(); err != nil {
+		t.Fatal(err)
+	}
+	if GetBee("hivesink") == nil || GetChain("hive-chain-2") == nil {
+		t.Error("Expected restarted hive to resume its bees and chains")
+	}
+}
+
+func TestHiveConcurrentStart(t *testing.T) {
+	hives := make([]*Hive, 8)
+	errs := make(chan error, len(hives))
+	for i := range hives {
+		hives[i] = NewHive(HiveOptions{StopTimeout: time.Second})
+		go func(h *Hive) { errs <- h.Start() }(hives[i])
+	}
+	defer func() {
+		for _, h := range hives {
+			h.Stop()
+		}
+	}()
+
+	started := 0
+	for range hives {
+		switch err := <-errs; err {
+		case nil:
+			started++
+		case ErrHiveRunning:
+		default:
+			t.Errorf("Expected ErrHiveRunning, got %v", err)
+		}
+	}
+	if started != 1 {
+		t.Errorf("Expected exactly one hive to start, %d did", started)
+	}
+}
+
+func TestHiveStopped(t *testing.T) {
+	oldActions := GetActions()
+	defer SetActions(oldActions)
+	oldChains := chains
+	defer func() {
+		// stopped hives may still be draining their event queue
+		chainsMutex.Lock()
+		chains = oldChains
+		chainsMutex.Unlock()
+	}()
+
+	running := NewHive(HiveOptions{
+		Bees:        []BeeConfig{{Name: "runningsink", Class: "recordingbee"}},
+		Chains:      []Chain{{Name: "running-chain", Event: &Event{Bee: "runningsource", Name: "ping"}}},
+		StopTimeout: time.Second,
+	})
+	if err := running.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer running.Stop()
+
+	stopped := NewHive(HiveOptions{
+		Bees:   []BeeConfig{{Name: "stoppedsink", Class: "recordingbee"}},
+		Chains: []Chain{{Name: "stopped-chain", Event: &Event{Bee: "stoppedsource", Name: "ping"}}},
+	})
+	if err := stopped.PutChain(Chain{Name: "running-chain", Event: &Event{Bee: "stoppedsource", Name: "pong"}}); err != nil {
+		t.Fatal(err)
+	}
+	if !stopped.RemoveChain("stopped-chain") || stopped.RemoveChain("stopped-chain") {
+		t.Error("Expected the stopped hive's chain to be removed once")
+	}
+	stopped.PutAction(Action{ID: "stopped-action", Bee: "stoppedsink", Name: "relay"})
+	if err := stopped.DeleteBee("runningsink"); err == nil {
+		t.Error("Expected the running hive's bee to be unknown to the stopped hive")
+	}
+	if err := stopped.DeleteBee("stoppedsink"); err != nil {
+		t.Error(err)
+	}
+	if _, err := stopped.InjectEvent(context.Background(), Event{Bee: "runningsource", Name: "ping"}); err != ErrNotHandling {
+		t.Errorf("Expected the stopped hive not to handle events, got %v", err)
+	}
+	if _, err := stopped.ExecuteAction(context.Background(), Action{Bee: "runningsink", Name: "relay"}); err != ErrNotHandling {
+		t.Errorf("Expected the stopped hive not to execute actions, got %v", err)
+	}
+
+	if c := GetChain("running-chain"); c == nil || c.Event.Name != "ping" {
+		t.Errorf("Expected the running hive's chain to be left alone, got %+v", c)
+	}
+	if GetAction("stopped-action") != nil || GetBee("runningsink") == nil {
+		t.Error("Expected the running hive's actions and bees to be left alone")
+	}
+	opts := stopped.Options()
+	if len(opts.Bees) != 0 || len(opts.Actions) != 1 || len(opts.Chains) != 1 || opts.Chains[0].Event.Name != "pong" {
+		t.Errorf("Expected the stopped hive's options to be changed, got %+v", opts)
+	}
+	if cs := stopped.Chains(); len(cs) != 1 || cs[0].Name != "running-chain" {
+		t.Errorf("Expected the stopped hive's chains, got %+v", cs)
+	}
+}
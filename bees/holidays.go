@@ -0,0 +1,235 @@
+/*
+ *    Copyright (C) 2026 Christian Muehlhaeuser
+ *
+ *    This program is free software: you can redistribute it and/or modify
+ *    it under the terms of the GNU Affero General Public License as published
+ *    by the Free Software Foundation, either version 3 of the License, or
+ *    (at your option) any later version.
+ *
+ *    This program is distributed in the hope that it will be useful,
+ *    but WITHOUT ANY WARRANTY; without even the implied warranty of
+ *    MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ *    GNU Affero General Public License for more details.
+ *
+ *    You should have received a copy of the GNU Affero General Public License
+ *    along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ *    Authors:
+ *      Christian Muehlhaeuser <muesli@gmail.com>
+ */
+
+// Package bees is Beehive's central module system.
+package bees
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// holidayRefresh is how often holiday calendars get fetched again.
+const holidayRefresh = 6 * time.Hour
+
+// holidaySpan is the time span of an event in a holiday calendar.
+type holidaySpan struct {
+	start, end time.Time
+	// yearly spans recur on the same date every year
+	yearly bool
+}
+
+// contains returns whether t lies within the span.
+func (s holidaySpan) contains(t time.Time) bool {
+	if !s.yearly {
+		return !t.Before(s.start) && t.Before(s.end)
+	}
+
+	// check this year's occurrence, and last year's in case it spans the
+	// turn of the year
+	for _, year := range []int{t.Year(), t.Year() - 1} {
+		shift := year - s.start.Year()
+		if !t.Before(s.start.AddDate(shift, 0, 0)) && t.Before(s.end.AddDate(shift, 0, 0)) {
+			return true
+		}
+	}
+	return false
+}
+
+// holidayCalendar is a fetched holiday calendar.
+type holidayCalendar struct {
+	spans      []holidaySpan
+	fetched    time.Time
+	refreshing bool
+}
+
+var (
+	holidayCalendars = make(map[string]*holidayCalendar)
+	holidayMutex     sync.Mutex
+	holidayClient    = &http.Client{Timeout: 10 * time.Second}
+)
+
+// onHoliday returns whether t lies within an event of the iCal calendar at
+// src, a URL or a file path. Calendars get fetched when they're first needed
+// and refreshed in the background every holidayRefresh. Calendars that can't
+// be fetched don't contain any holidays, until they can be.
+func onHoliday(src string, t time.Time) bool {
+	holidayMutex.Lock()
+	cal, ok := holidayCalendars[src]
+	if ok && !cal.refreshing && time.Since(cal.fetched) > holidayRefresh {
+		cal.refreshing = true
+		go refreshHolidays(src)
+	}
+	holidayMutex.Unlock()
+
+	if !ok {
+		cal = refreshHolidays(src)
+	}
+	for _, s := range cal.spans {
+		if s.contains(t) {
+			return true
+		}
+	}
+	return false
+}
+
+// refreshHolidays fetches the calendar at src. If that fails, the previously
+// fetched calendar is kept.
+func refreshHolidays(src string) *holidayCalendar {
+	spans, err := fetchHolidays(src)
+
+	holidayMutex.Lock()
+	defer holidayMutex.Unlock()
+
+	cal := &holidayCalendar{spans: spans, fetched: time.Now()}
+	if err != nil {
+		logger.Errorf("Fetching holiday calendar %v failed: %v", src, err)
+		if old, ok := holidayCalendars[src]; ok {
+			cal.spans = old.spans
+		}
+		// try again in a minute
+		cal.fetched = cal.fetched.Add(time.Minute - holidayRefresh)
+	}
+	holidayCalendars[src] = cal
+	return cal
+}
+
+// fetchHolidays reads the iCal calendar at src.
+func fetchHolidays(src string) ([]holidaySpan, error) {
+	if !strings.HasPrefix(src, "http://") && !strings.HasPrefix(src, "https://") {
+		f, err := os.Open(src)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		return parseICal(f)
+	}
+
+	resp, err := holidayClient.Get(src)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		io.Copy(ioutil.Discard, resp.Body)
+		return nil, fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	return parseICal(resp.Body)
+}
+
+// parseICal returns the time spans of the events in an iCal calendar. Events
+// recurring yearly are supported, other recurrence rules are ignored, and
+// only the first occurrence of such events is taken into account.
+func parseICal(r io.Reader) ([]holidaySpan, error) {
+	var lines []string
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		// long lines are folded onto lines starting with whitespace
+		if len(lines) > 0 && (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		lines = append(lines, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	var spans []holidaySpan
+	var span *holidaySpan
+	var allDay bool
+	for _, line := range lines {
+		i := strings.Index(line, ":")
+		if i < 0 {
+			continue
+		}
+		params := strings.Split(line[:i], ";")
+		name, value := strings.ToUpper(params[0]), line[i+1:]
+
+		switch {
+		case name == "BEGIN" && value == "VEVENT":
+			span = &holidaySpan{}
+		case span == nil:
+		case name == "END" && value == "VEVENT":
+			if span.start.IsZero() {
+				return nil, fmt.Errorf("event without start")
+			}
+			if span.end.IsZero() {
+				// events without an end last a day, or no time at all
+				span.end = span.start
+				if allDay {
+					span.end = span.start.AddDate(0, 0, 1)
+				}
+			}
+			spans = append(spans, *span)
+			span = nil
+		case name == "DTSTART":
+			var err error
+			span.start, allDay, err = parseICalTime(value, params[1:])
+			if err != nil {
+				return nil, err
+			}
+		case name == "DTEND":
+			var err error
+			span.end, _, err = parseICalTime(value, params[1:])
+			if err != nil {
+				return nil, err
+			}
+		case name == "RRULE":
+			span.yearly = strings.Contains(strings.ToUpper(value), "FREQ=YEARLY")
+		}
+	}
+
+	return spans, nil
+}
+
+// parseICalTime parses an iCal date or date-time value. Dates and floating
+// date-times are in local time. Returns whether the value is a date.
+func parseICalTime(value string, params []string) (time.Time, bool, error) {
+	loc := time.Local
+	for _, p := range params {
+		if kv := strings.SplitN(p, "=", 2); len(kv) == 2 && strings.ToUpper(kv[0]) == "TZID" {
+			l, err := time.LoadLocation(strings.Trim(kv[1], `"`))
+			if err != nil {
+				return time.Time{}, false, err
+			}
+			loc = l
+		}
+	}
+
+	if len(value) == 8 {
+		t, err := time.ParseInLocation("20060102", value, time.Local)
+		return t, true, err
+	}
+	if strings.HasSuffix(value, "Z") {
+		t, err := time.Parse("20060102T150405Z", value)
+		return t, false, err
+	}
+	t, err := time.ParseInLocation("20060102T150405", value, loc)
+	return t, false, err
+}
@@ -0,0 +1,100 @@
+package bees
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+const testHolidays = "BEGIN:VCALENDAR\r\n" +
+	"VERSION:2.0\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"SUMMARY:Christmas\r\n" +
+	"DTSTART;VALUE=DATE:20201225\r\n" +
+	"DTEND;VALUE=DATE:20201227\r\n" +
+	"RRULE:FREQ=YEARLY\r\n" +
+	"END:VEVENT\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"SUMMARY:Company\r\n" +
+	" offsite\r\n" +
+	"DTSTART;TZID=UTC:20261014T120000\r\n" +
+	"DTEND:20261014T140000Z\r\n" +
+	"END:VEVENT\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"DTSTART:20261101\r\n" +
+	"END:VEVENT\r\n" +
+	"END:VCALENDAR\r\n"
+
+func TestParseICal(t *testing.T) {
+	spans, err := parseICal(strings.NewReader(testHolidays))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(spans) != 3 {
+		t.Fatalf("Expected 3 events, got %d", len(spans))
+	}
+
+	tests := []struct {
+		time    time.Time
+		holiday bool
+	}{
+		{time.Date(2026, 12, 26, 18, 0, 0, 0, time.Local), true},
+		{time.Date(2026, 12, 27, 0, 0, 0, 0, time.Local), false},
+		{time.Date(2026, 10, 14, 13, 0, 0, 0, time.UTC), true},
+		{time.Date(2026, 10, 14, 14, 0, 0, 0, time.UTC), false},
+		{time.Date(2026, 11, 1, 23, 59, 0, 0, time.Local), true},
+		{time.Date(2027, 11, 1, 12, 0, 0, 0, time.Local), false},
+	}
+	for _, tt := range tests {
+		holiday := false
+		for _, s := range spans {
+			holiday = holiday || s.contains(tt.time)
+		}
+		if holiday != tt.holiday {
+			t.Errorf("Expected holiday=%v at %v", tt.holiday, tt.time)
+		}
+	}
+
+	if _, err := parseICal(strings.NewReader("BEGIN:VEVENT\nEND:VEVENT\n")); err == nil {
+		t.Error("Expected an error for an event without start")
+	}
+}
+
+func TestChainHolidays(t *testing.T) {
+	dir, err := ioutil.TempDir("", "beehive")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	file := filepath.Join(dir, "holidays.ics")
+	if err := ioutil.WriteFile(file, []byte(testHolidays), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, testHolidays)
+	}))
+	defer ts.Close()
+
+	christmas := time.Date(2026, 12, 25, 10, 0, 0, 0, time.Local)
+	for _, src := range []string{file, ts.URL} {
+		c := Chain{Name: "office", Holidays: []string{src}}
+		if active, err := c.activeAt(christmas); err != nil || active {
+			t.Errorf("Expected chain to be inactive on holidays from %s, got %v (%v)", src, active, err)
+		}
+		if active, _ := c.activeAt(christmas.AddDate(0, 0, 7)); !active {
+			t.Errorf("Expected chain to be active on other days")
+		}
+	}
+
+	c := Chain{Name: "office", Holidays: []string{filepath.Join(dir, "missing.ics")}}
+	if active, _ := c.activeAt(christmas); !active {
+		t.Error("Expected calendars that can't be fetched not to contain holidays")
+	}
+}
@@ -0,0 +1,436 @@
+/*
+ *    Copyright (C) 2026 Christian Muehlhaeuser
+ *
+ *    This program is free software: you can redistribute it and/or modify
+ *    it under the terms of the GNU Affero General Public License as published
+ *    by the Free Software Foundation, either version 3 of the License, or
+ *    (at your option) any later version.
+ *
+ *    This program is distributed in the hope that it will be useful,
+ *    but WITHOUT ANY WARRANTY; without even the implied warranty of
+ *    MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ *    GNU Affero General Public License for more details.
+ *
+ *    You should have received a copy of the GNU Affero General Public License
+ *    along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ *    Authors:
+ *      Christian Muehlhaeuser <muesli@gmail.com>
+ */
+
+// Package homeassistantbee is a Bee that talks to Home Assistant.
+package homeassistantbee
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"path"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+
+	"github.com/muesli/beehive/bees"
+)
+
+// reconnectDelay is how long the bee waits before reconnecting to Home
+// Assistant after a connection error.
+var reconnectDelay = 10 * time.Second
+
+// HomeAssistantBee is a Bee that talks to Home Assistant's WebSocket API.
+type HomeAssistantBee struct {
+	bees.Bee
+
+	url              string
+	token            string
+	entities         []string
+	attributeChanges bool
+
+	mutex   sync.Mutex
+	conn    *websocket.Conn
+	nextID  int
+	pending map[int]chan message
+}
+
+// message is a message of Home Assistant's WebSocket API.
+type message struct {
+	ID      int    `json:"id"`
+	Type    string `json:"type"`
+	Success bool   `json:"success"`
+	Message string `json:"message"`
+	Error   *struct {
+		Code    string `json:"code"`
+		Message string `json:"message"`
+	} `json:"error"`
+	Event *struct {
+		EventType string `json:"event_type"`
+		Data      struct {
+			EntityID string `json:"entity_id"`
+			OldState *state `json:"old_state"`
+			NewState *state `json:"new_state"`
+		} `json:"data"`
+	} `json:"event"`
+}
+
+// state is the state of an entity.
+type state struct {
+	State       string                 `json:"state"`
+	Attributes  map[string]interface{} `json:"attributes"`
+	LastChanged time.Time              `json:"last_changed"`
+}
+
+// ActionE triggers the action passed to it.
+func (mod *HomeAssistantBee) ActionE(ctx context.Context, action bees.Action) ([]bees.Placeholder, error) {
+	outs := []bees.Placeholder{}
+
+	switch action.Name {
+	case "call_service":
+		var service, entityID string
+		action.Options.Bind("service", &service)
+		action.Options.Bind("entity_id", &entityID)
+
+		domain := ""
+		action.Options.Bind("domain", &domain)
+		if domain == "" {
+			// accept services as "light.turn_on"
+			if i := strings.Index(service, "."); i > 0 {
+				domain, service = service[:i], service[i+1:]
+			}
+		}
+		if domain == "" || service == "" {
+			return outs, errors.New("Missing service domain")
+		}
+
+		data, err := serviceData(action.Options.Value("data"))
+		if err != nil {
+			return outs, err
+		}
+
+		req := map[string]interface{}{
+			"type":         "call_service",
+			"domain":       domain,
+			"service":      service,
+			"service_data": data,
+		}
+		if entityID != "" {
+			req["target"] = map[string]interface{}{"entity_id": entityID}
+		}
+		if _, err := mod.call(ctx, req); err != nil {
+			return outs, fmt.Errorf("Calling %s.%s failed: %v", domain, service, err)
+		}
+
+	default:
+		panic("Unknown action triggered in " + mod.Name() + ": " + action.Name)
+	}
+
+	return outs, nil
+}
+
+// Action triggers the action passed to it.
+func (mod *HomeAssistantBee) Action(ctx context.Context, action bees.Action) []bees.Placeholder {
+	outs, err := mod.ActionE(ctx, action)
+	if err != nil {
+		mod.LogErrorf("Error: %v", err)
+	}
+	return outs
+}
+
+// serviceData returns the data of a service call, given either as a map or
+// as a JSON object.
+func serviceData(v interface{}) (map[string]interface{}, error) {
+	switch d := v.(type) {
+	case nil:
+		return map[string]interface{}{}, nil
+	case map[string]interface{}:
+		return d, nil
+	case string:
+		data := map[string]interface{}{}
+		if strings.TrimSpace(d) == "" {
+			return data, nil
+		}
+		if err := json.Unmarshal([]byte(d), &data); err != nil {
+			return nil, fmt.Errorf("Invalid service data: %v", err)
+		}
+		return data, nil
+	default:
+		return nil, fmt.Errorf("Invalid service data: %v", v)
+	}
+}
+
+// call sends a request to Home Assistant and waits for its result.
+func (mod *HomeAssistantBee) call(ctx context.Context, req map[string]interface{}) (message, error) {
+	mod.mutex.Lock()
+	if mod.conn == nil {
+		mod.mutex.Unlock()
+		return message{}, errors.New("Not connected to Home Assistant")
+	}
+	mod.nextID++
+	id := mod.nextID
+	req["id"] = id
+	res := make(chan message, 1)
+	mod.pending[id] = res
+	err := mod.conn.WriteJSON(req)
+	mod.mutex.Unlock()
+
+	defer func() {
+		mod.mutex.Lock()
+		delete(mod.pending, id)
+		mod.mutex.Unlock()
+	}()
+	if err != nil {
+		return message{}, err
+	}
+
+	select {
+	case msg, ok := <-res:
+		if !ok {
+			return message{}, errors.New("Connection to Home Assistant lost")
+		}
+		if !msg.Success {
+			if msg.Error != nil {
+				return msg, errors.New(msg.Error.Message)
+			}
+			return msg, errors.New("Request failed")
+		}
+		return msg, nil
+	case <-ctx.Done():
+		return message{}, ctx.Err()
+	}
+}
+
+// Run executes the Bee's event loop.
+func (mod *HomeAssistantBee) Run(ctx context.Context, eventChan chan bees.Event) {
+	for {
+		err := mod.watch(ctx, eventChan)
+		if err == nil {
+			return
+		}
+		mod.LogErrorf("Home Assistant error: %v", err)
+
+		select {
+		case <-mod.SigChan:
+			return
+		case <-ctx.Done():
+			return
+		case <-time.After(reconnectDelay):
+		}
+	}
+}
+
+// watch connects to Home Assistant and emits an event for every state change,
+// until the bee gets stopped or the connection fails.
+func (mod *HomeAssistantBee) watch(ctx context.Context, eventChan chan bees.Event) error {
+	u, err := websocketURL(mod.url)
+	if err != nil {
+		return err
+	}
+	conn, _, err := websocket.DefaultDialer.DialContext(ctx, u, nil)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if err := authenticate(conn, mod.token); err != nil {
+		return err
+	}
+	if err := conn.WriteJSON(map[string]interface{}{"id": 1, "type": "subscribe_events", "event_type": "state_changed"}); err != nil {
+		return err
+	}
+
+	mod.mutex.Lock()
+	mod.conn = conn
+	mod.nextID = 1
+	mod.pending = map[int]chan message{}
+	mod.mutex.Unlock()
+	defer func() {
+		mod.mutex.Lock()
+		mod.conn = nil
+		for _, res := range mod.pending {
+			close(res)
+		}
+		mod.pending = nil
+		mod.mutex.Unlock()
+	}()
+
+	msgs := make(chan message)
+	readErr := make(chan error, 1)
+	go func() {
+		for {
+			var msg message
+			if err := conn.ReadJSON(&msg); err != nil {
+				readErr <- err
+				return
+			}
+			msgs <- msg
+		}
+	}()
+
+	for {
+		select {
+		case <-mod.SigChan:
+			return nil
+		case <-ctx.Done():
+			return nil
+		case err := <-readErr:
+			return err
+
+		case msg := <-msgs:
+			switch {
+			case msg.ID == 1 && msg.Type == "result" && !msg.Success:
+				return errors.New("Can't subscribe to state changes")
+			case msg.Type == "event" && msg.Event != nil:
+				if ev, ok := mod.stateEvent(msg); ok {
+					select {
+					case eventChan <- ev:
+					case <-ctx.Done():
+						return nil
+					}
+				}
+			case msg.Type == "result":
+				mod.mutex.Lock()
+				if res, ok := mod.pending[msg.ID]; ok {
+					res <- msg
+				}
+				mod.mutex.Unlock()
+			}
+		}
+	}
+}
+
+// websocketURL returns the URL of the WebSocket API of the Home Assistant
+// instance running at base.
+func websocketURL(base string) (string, error) {
+	u, err := url.Parse(base)
+	if err != nil {
+		return "", err
+	}
+	switch u.Scheme {
+	case "http":
+		u.Scheme = "ws"
+	case "https":
+		u.Scheme = "wss"
+	}
+	u.Path = strings.TrimSuffix(u.Path, "/") + "/api/websocket"
+	return u.String(), nil
+}
+
+// authenticate performs the handshake of the WebSocket API.
+func authenticate(conn *websocket.Conn, token string) error {
+	var msg message
+	if err := conn.ReadJSON(&msg); err != nil {
+		return err
+	}
+	if msg.Type != "auth_required" {
+		return fmt.Errorf("Unexpected message %s", msg.Type)
+	}
+
+	if err := conn.WriteJSON(map[string]interface{}{"type": "auth", "access_token": token}); err != nil {
+		return err
+	}
+	if err := conn.ReadJSON(&msg); err != nil {
+		return err
+	}
+	if msg.Type != "auth_ok" {
+		return fmt.Errorf("Authentication failed: %s", msg.Message)
+	}
+	return nil
+}
+
+// watching returns whether the bee emits events for an entity.
+func (mod *HomeAssistantBee) watching(entityID string) bool {
+	if len(mod.entities) == 0 {
+		return true
+	}
+	for _, pattern := range mod.entities {
+		if ok, _ := path.Match(pattern, entityID); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// stateEvent maps a state_changed message to an event.
+func (mod *HomeAssistantBee) stateEvent(msg message) (bees.Event, bool) {
+	data := msg.Event.Data
+	if !mod.watching(data.EntityID) {
+		return bees.Event{}, false
+	}
+
+	old, cur := data.OldState, data.NewState
+	if old == nil {
+		old = &state{}
+	}
+	if cur == nil {
+		// the entity got removed
+		cur = &state{}
+	}
+	if !mod.attributeChanges && old.State == cur.State {
+		return bees.Event{}, false
+	}
+
+	domain := data.EntityID
+	if i := strings.Index(domain, "."); i > 0 {
+		domain = domain[:i]
+	}
+	name, _ := cur.Attributes["friendly_name"].(string)
+	attributes := cur.Attributes
+	if attributes == nil {
+		attributes = map[string]interface{}{}
+	}
+
+	return bees.Event{
+		Bee:  mod.Name(),
+		Name: "state_changed",
+		Options: []bees.Placeholder{
+			{
+				Name:  "entity_id",
+				Type:  "string",
+				Value: data.EntityID,
+			},
+			{
+				Name:  "domain",
+				Type:  "string",
+				Value: domain,
+			},
+			{
+				Name:  "friendly_name",
+				Type:  "string",
+				Value: name,
+			},
+			{
+				Name:  "old_state",
+				Type:  "string",
+				Value: old.State,
+			},
+			{
+				Name:  "new_state",
+				Type:  "string",
+				Value: cur.State,
+			},
+			{
+				Name:  "attributes",
+				Type:  "map",
+				Value: attributes,
+			},
+			{
+				Name:  "last_changed",
+				Type:  "timestamp",
+				Value: cur.LastChanged,
+			},
+		},
+	}, true
+}
+
+// ReloadOptions parses the config options and initializes the Bee.
+func (mod *HomeAssistantBee) ReloadOptions(options bees.BeeOptions) {
+	mod.SetOptions(options)
+
+	options.Bind("url", &mod.url)
+	options.Bind("token", &mod.token)
+	options.Bind("entities", &mod.entities)
+	options.Bind("attribute_changes", &mod.attributeChanges)
+}
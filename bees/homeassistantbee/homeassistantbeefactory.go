@@ -0,0 +1,184 @@
+/*
+ *    Copyright (C) 2026 Christian Muehlhaeuser
+ *
+ *    This program is free software: you can redistribute it and/or modify
+ *    it under the terms of the GNU Affero General Public License as published
+ *    by the Free Software Foundation, either version 3 of the License, or
+ *    (at your option) any later version.
+ *
+ *    This program is distributed in the hope that it will be useful,
+ *    but WITHOUT ANY WARRANTY; without even the implied warranty of
+ *    MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ *    GNU Affero General Public License for more details.
+ *
+ *    You should have received a copy of the GNU Affero General Public License
+ *    along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ *    Authors:
+ *      Christian Muehlhaeuser <muesli@gmail.com>
+ */
+
+package homeassistantbee
+
+import (
+	"github.com/muesli/beehive/bees"
+)
+
+// HomeAssistantBeeFactory is a factory for HomeAssistantBees.
+type HomeAssistantBeeFactory struct {
+	bees.BeeFactory
+}
+
+// New returns a new Bee instance configured with the supplied options.
+func (factory *HomeAssistantBeeFactory) New(name, description string, options bees.BeeOptions) bees.BeeInterface {
+	bee := HomeAssistantBee{
+		Bee: bees.NewBee(name, factory.ID(), description, options),
+	}
+	bee.ReloadOptions(options)
+
+	return &bee
+}
+
+// ID returns the ID of this Bee.
+func (factory *HomeAssistantBeeFactory) ID() string {
+	return "homeassistantbee"
+}
+
+// Name returns the name of this Bee.
+func (factory *HomeAssistantBeeFactory) Name() string {
+	return "Home Assistant"
+}
+
+// Description returns the description of this Bee.
+func (factory *HomeAssistantBeeFactory) Description() string {
+	return "Reacts to state changes in Home Assistant and calls its services"
+}
+
+// Image returns the filename of an image for this Bee.
+func (factory *HomeAssistantBeeFactory) Image() string {
+	return "socketbee.png"
+}
+
+// LogoColor returns the preferred logo background color (used by the admin interface).
+func (factory *HomeAssistantBeeFactory) LogoColor() string {
+	return "#41bdf5"
+}
+
+// Options returns the options available to configure this Bee.
+func (factory *HomeAssistantBeeFactory) Options() []bees.BeeOptionDescriptor {
+	opts := []bees.BeeOptionDescriptor{
+		{
+			Name:        "url",
+			Description: "URL of your Home Assistant instance, e.g. http://homeassistant.local:8123",
+			Type:        "url",
+			Mandatory:   true,
+		},
+		{
+			Name:        "token",
+			Description: "Long-lived access token, created on your Home Assistant profile page",
+			Type:        "password",
+			Mandatory:   true,
+		},
+		{
+			Name:        "entities",
+			Description: "Entities to watch, e.g. light.kitchen or sensor.*; all entities if empty",
+			Type:        "[]string",
+		},
+		{
+			Name:        "attribute_changes",
+			Description: "Also emit events when only an entity's attributes changed",
+			Type:        "bool",
+			Default:     false,
+		},
+	}
+	return opts
+}
+
+// Events describes the available events provided by this Bee.
+func (factory *HomeAssistantBeeFactory) Events() []bees.EventDescriptor {
+	events := []bees.EventDescriptor{
+		{
+			Namespace:   factory.Name(),
+			Name:        "state_changed",
+			Description: "The state of an entity changed",
+			Options: []bees.PlaceholderDescriptor{
+				{
+					Name:        "entity_id",
+					Description: "ID of the entity, e.g. light.kitchen",
+					Type:        "string",
+				},
+				{
+					Name:        "domain",
+					Description: "Domain of the entity, e.g. light",
+					Type:        "string",
+				},
+				{
+					Name:        "friendly_name",
+					Description: "Name of the entity",
+					Type:        "string",
+				},
+				{
+					Name:        "old_state",
+					Description: "Previous state of the entity",
+					Type:        "string",
+				},
+				{
+					Name:        "new_state",
+					Description: "New state of the entity, empty if it got removed",
+					Type:        "string",
+				},
+				{
+					Name:        "attributes",
+					Description: "Attributes of the entity's new state",
+					Type:        "map",
+				},
+				{
+					Name:        "last_changed",
+					Description: "When the state changed",
+					Type:        "timestamp",
+				},
+			},
+		},
+	}
+	return events
+}
+
+// Actions describes the available actions provided by this Bee.
+func (factory *HomeAssistantBeeFactory) Actions() []bees.ActionDescriptor {
+	actions := []bees.ActionDescriptor{
+		{
+			Namespace:   factory.Name(),
+			Name:        "call_service",
+			Description: "Calls a Home Assistant service",
+			Options: []bees.PlaceholderDescriptor{
+				{
+					Name:        "service",
+					Description: "Service to call, e.g. turn_on, or light.turn_on without a domain",
+					Type:        "string",
+					Mandatory:   true,
+				},
+				{
+					Name:        "domain",
+					Description: "Domain of the service, e.g. light",
+					Type:        "string",
+				},
+				{
+					Name:        "entity_id",
+					Description: "Entity to target, e.g. light.kitchen",
+					Type:        "string",
+				},
+				{
+					Name:        "data",
+					Description: "Service data as a JSON object, e.g. {\"brightness_pct\": 50}",
+					Type:        "string",
+				},
+			},
+		},
+	}
+	return actions
+}
+
+func init() {
+	f := HomeAssistantBeeFactory{}
+	bees.RegisterFactory(&f)
+}
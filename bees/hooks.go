@@ -0,0 +1,160 @@
+/*
+ *    Copyright (C) 2026 Christian Muehlhaeuser
+ *
+ *    This program is free software: you can redistribute it and/or modify
+ *    it under the terms of the GNU Affero General Public License as published
+ *    by the Free Software Foundation, either version 3 of the License, or
+ *    (at your option) any later version.
+ *
+ *    This program is distributed in the hope that it will be useful,
+ *    but WITHOUT ANY WARRANTY; without even the implied warranty of
+ *    MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ *    GNU Affero General Public License for more details.
+ *
+ *    You should have received a copy of the GNU Affero General Public License
+ *    along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ *    Authors:
+ *      Christian Muehlhaeuser <muesli@gmail.com>
+ */
+// Package bees is Beehive's central module system.
+package bees
+
+import (
+	"runtime/debug"
+	"sync"
+)
+
+type hookKind int
+
+const (
+	beeStartedHook hookKind = iota
+	beeStoppedHook
+	beeCrashedHook
+	eventHook
+	chainExecutedHook
+	actionExecutedHook
+	eventQueueSaturatedHook
+)
+
+// A Hook is a registered lifecycle callback, see OnBeeStarted.
+type Hook struct {
+	kind hookKind
+	fn   interface{}
+}
+
+var (
+	hooks      = make(map[hookKind][]*Hook)
+	hooksMutex sync.RWMutex
+)
+
+// OnBeeStarted registers a function that gets called whenever a bee starts
+// running, including restarts after a crash.
+func OnBeeStarted(f func(name string)) *Hook {
+	return addHook(beeStartedHook, f)
+}
+
+// OnBeeStopped registers a function that gets called whenever a running bee
+// got stopped by StopBees or DeleteBee.
+func OnBeeStopped(f func(name string)) *Hook {
+	return addHook(beeStoppedHook, f)
+}
+
+// OnBeeCrashed registers a function that gets called whenever a bee panics,
+// with the value it panicked with.
+func OnBeeCrashed(f func(name string, err interface{})) *Hook {
+	return addHook(beeCrashedHook, f)
+}
+
+// OnEvent registers a function that gets called for every event handled by
+// the hive. It's called on the event loop, so it must not block.
+func OnEvent(f func(Event)) *Hook {
+	return addHook(eventHook, f)
+}
+
+// OnChainExecuted registers a function that gets called whenever a chain
+// finished executing its actions, with the outcome of the execution.
+func OnChainExecuted(f func(ChainExecution)) *Hook {
+	return addHook(chainExecutedHook, f)
+}
+
+// OnActionExecuted registers a function that gets called whenever an action
+// of a chain got executed, with the results it returned.
+func OnActionExecuted(f func(chain string, action Action, res []Placeholder)) *Hook {
+	return addHook(actionExecutedHook, f)
+}
+
+// OnEventQueueSaturated registers a function that gets called whenever the
+// event queue ran full, with its state at that moment. It's called while
+// bees emit events, so it must not block.
+func OnEventQueueSaturated(f func(EventQueueInfo)) *Hook {
+	return addHook(eventQueueSaturatedHook, f)
+}
+
+// Remove unregisters the hook. Removing a hook twice is a no-op.
+func (h *Hook) Remove() {
+	hooksMutex.Lock()
+	defer hooksMutex.Unlock()
+
+	hs := hooks[h.kind]
+	for i, hook := range hs {
+		if hook == h {
+			hooks[h.kind] = append(hs[:i:i], hs[i+1:]...)
+			return
+		}
+	}
+}
+
+func addHook(kind hookKind, fn interface{}) *Hook {
+	h := &Hook{kind: kind, fn: fn}
+
+	hooksMutex.Lock()
+	defer hooksMutex.Unlock()
+	hooks[kind] = append(hooks[kind], h)
+
+	return h
+}
+
+// runHooks calls all hooks of a kind in registration order. Panicking hooks
+// get logged and don't affect the other hooks or the caller.
+func runHooks(kind hookKind, call func(fn interface{})) {
+	hooksMutex.RLock()
+	hs := hooks[kind]
+	hooksMutex.RUnlock()
+
+	for _, h := range hs {
+		func() {
+			defer func() {
+				if e := recover(); e != nil {
+					logger.Errorf("Lifecycle hook panicked: %v %s", e, debug.Stack())
+				}
+			}()
+
+			call(h.fn)
+		}()
+	}
+}
+
+func beeStarted(name string) {
+	runHooks(beeStartedHook, func(fn interface{}) { fn.(func(string))(name) })
+}
+
+func beeStopped(name string) {
+	runHooks(beeStoppedHook, func(fn interface{}) { fn.(func(string))(name) })
+}
+
+func beeCrashed(name string, err interface{}) {
+	runHooks(beeCrashedHook, func(fn interface{}) { fn.(func(string, interface{}))(name, err) })
+}
+
+func eventHandled(event Event) {
+	runHooks(eventHook, func(fn interface{}) { fn.(func(Event))(event) })
+}
+
+func chainExecuted(exec ChainExecution) {
+	runHooks(chainExecutedHook, func(fn interface{}) { fn.(func(ChainExecution))(exec) })
+}
+
+func actionExecuted(chain string, action Action, res []Placeholder) {
+	runHooks(actionExecutedHook, func(fn interface{}) { fn.(func(string, Action, []Placeholder))(chain, action, res) })
+}
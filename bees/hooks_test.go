@@ -0,0 +1,108 @@
+package bees
+
+import (
+	"context"
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type crashingBee struct {
+	recordingBee
+	runs int32
+}
+
+func (mod *crashingBee) Run(ctx context.Context, eventChan chan Event) {
+	if atomic.AddInt32(&mod.runs, 1) == 1 {
+		panic("crashed")
+	}
+	mod.recordingBee.Run(ctx, eventChan)
+}
+
+func TestLifecycleHooks(t *testing.T) {
+	var mutex sync.Mutex
+	var calls []string
+	record := func(s string) {
+		mutex.Lock()
+		defer mutex.Unlock()
+		calls = append(calls, s)
+	}
+	recorded := func() []string {
+		mutex.Lock()
+		defer mutex.Unlock()
+		return append([]string{}, calls...)
+	}
+
+	hooks := []*Hook{
+		OnBeeStarted(func(name string) { record("started " + name) }),
+		OnBeeCrashed(func(name string, err interface{}) { record("crashed " + name + ": " + err.(string)) }),
+		OnBeeStopped(func(name string) { record("stopped " + name) }),
+		OnEvent(func(ev Event) { panic("broken hook") }),
+		OnEvent(func(ev Event) { record("event " + ev.Name) }),
+	}
+	defer func() {
+		for _, h := range hooks {
+			h.Remove()
+		}
+	}()
+
+	var bee BeeInterface = &crashingBee{recordingBee: recordingBee{Bee: NewBee("hookbee", "recordingbee", "", nil)}}
+	if err := RegisterBee(bee); err != nil {
+		t.Fatal(err)
+	}
+	launchBee(&bee)
+
+	deadline := time.Now().Add(time.Second)
+	for len(recorded()) < 3 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	handleEvent(context.Background(), Event{Bee: "hookbee", Name: "ping"})
+	DeleteBee(&bee)
+
+	exp := []string{"started hookbee", "crashed hookbee: crashed", "started hookbee", "event ping", "stopped hookbee"}
+	if got := recorded(); !reflect.DeepEqual(got, exp) {
+		t.Errorf("Expected hook calls %v, got %v", exp, got)
+	}
+
+	hooks[4].Remove()
+	hooks[4].Remove()
+	handleEvent(context.Background(), Event{Bee: "hookbee", Name: "pong"})
+	if got := recorded(); len(got) != len(exp) {
+		t.Errorf("Expected removed hook not to be called, got %v", got)
+	}
+}
+
+func TestChainHooks(t *testing.T) {
+	newRecordingBee("chainhookbee")
+	defer DeleteBee(GetBee("chainhookbee"))
+
+	oldActions := GetActions()
+	defer SetActions(oldActions)
+	SetActions([]Action{{ID: "say", Bee: "chainhookbee", Name: "say"}})
+
+	var execs []ChainExecution
+	var executed []string
+	hooks := []*Hook{
+		OnChainExecuted(func(exec ChainExecution) { execs = append(execs, exec) }),
+		OnActionExecuted(func(chain string, action Action, res []Placeholder) {
+			executed = append(executed, chain+": "+action.Name)
+		}),
+	}
+	defer func() {
+		for _, h := range hooks {
+			h.Remove()
+		}
+	}()
+
+	c := Chain{Name: "hooked", Event: &Event{Bee: "chainhookbee", Name: "trigger"}, Actions: []string{"say"}}
+	execChain(context.Background(), c, &Event{Bee: "chainhookbee", Name: "trigger"}, nil, false)
+
+	if len(execs) != 1 || execs[0].ChainName != "hooked" || execs[0].TriggerEvent.Name != "trigger" {
+		t.Errorf("Expected one execution of chain hooked, got %+v", execs)
+	}
+	if exp := []string{"hooked: say"}; !reflect.DeepEqual(executed, exp) {
+		t.Errorf("Expected executed actions %v, got %v", exp, executed)
+	}
+}
@@ -21,6 +21,8 @@
 package horizonboxbee
 
 import (
+	"context"
+
 	"github.com/PuerkitoBio/goquery"
 	"github.com/muesli/beehive/bees"
 	"net/http"
@@ -43,7 +45,7 @@ type HorizonBoxBee struct {
 	password string
 	interval int
 
-	State     HorizonBoxBeeState
+	BoxState  HorizonBoxBeeState
 	eventChan chan bees.Event
 }
 
@@ -92,15 +94,15 @@ func (mod *HorizonBoxBee) poll() {
 		})
 	})
 
-	if mod.State.online != state.online {
+	if mod.BoxState.online != state.online {
 		mod.announceOnlineStateChange(state.online)
 	}
 
-	if mod.State.ip != state.ip {
+	if mod.BoxState.ip != state.ip {
 		mod.announceIpChange(state.ip)
 	}
 
-	mod.State = state
+	mod.BoxState = state
 }
 
 func (mod *HorizonBoxBee) announceIpChange(ip string) {
@@ -134,7 +136,7 @@ func (mod *HorizonBoxBee) announceOnlineStateChange(online bool) {
 }
 
 // Run executes the Bee's event loop.
-func (mod *HorizonBoxBee) Run(cin chan bees.Event) {
+func (mod *HorizonBoxBee) Run(ctx context.Context, cin chan bees.Event) {
 	mod.eventChan = cin
 	for {
 		select {
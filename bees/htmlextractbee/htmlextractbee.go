@@ -22,6 +22,7 @@
 package htmlextractbee
 
 import (
+	"context"
 	"strings"
 
 	"github.com/Profpatsch/GoOse"
@@ -39,7 +40,7 @@ type HTMLExtractBee struct {
 }
 
 // Action triggers the action passed to it.
-func (mod *HTMLExtractBee) Action(action bees.Action) []bees.Placeholder {
+func (mod *HTMLExtractBee) Action(ctx context.Context, action bees.Action) []bees.Placeholder {
 	outs := []bees.Placeholder{}
 
 	switch action.Name {
@@ -107,7 +108,7 @@ func (mod *HTMLExtractBee) Action(action bees.Action) []bees.Placeholder {
 }
 
 // Run executes the Bee's event loop.
-func (mod *HTMLExtractBee) Run(eventChan chan bees.Event) {
+func (mod *HTMLExtractBee) Run(ctx context.Context, eventChan chan bees.Event) {
 	mod.evchan = eventChan
 }
 
@@ -23,6 +23,7 @@
 package httpbee
 
 import (
+	"context"
 	"encoding/json"
 	"io/ioutil"
 	"net/http"
@@ -36,14 +37,15 @@ import (
 type HTTPBee struct {
 	bees.Bee
 
-	addr string
-	path string
+	addr   string
+	path   string
+	client *http.Client
 
 	eventChan chan bees.Event
 }
 
 // Run executes the Bee's event loop.
-func (mod *HTTPBee) Run(cin chan bees.Event) {
+func (mod *HTTPBee) Run(ctx context.Context, cin chan bees.Event) {
 	mod.eventChan = cin
 
 	select {
@@ -53,7 +55,7 @@ func (mod *HTTPBee) Run(cin chan bees.Event) {
 }
 
 // Action triggers the action passed to it.
-func (mod *HTTPBee) Action(action bees.Action) []bees.Placeholder {
+func (mod *HTTPBee) Action(ctx context.Context, action bees.Action) []bees.Placeholder {
 	outs := []bees.Placeholder{}
 
 	u := ""
@@ -71,7 +73,7 @@ func (mod *HTTPBee) Action(action bees.Action) []bees.Placeholder {
 
 		mod.parseHeaders(h, req)
 
-		resp, err := http.DefaultClient.Do(req)
+		resp, err := mod.client.Do(req)
 		if err != nil {
 			mod.LogErrorf("Error: %s", err)
 			return outs
@@ -131,7 +133,7 @@ func (mod *HTTPBee) Action(action bees.Action) []bees.Placeholder {
 
 		mod.parseHeaders(h, req)
 
-		resp, err = http.DefaultClient.Do(req)
+		resp, err = mod.client.Do(req)
 		if err != nil {
 			mod.LogErrorf("Error: %s", err)
 			return outs
@@ -236,4 +238,10 @@ func (mod *HTTPBee) serializeHeaders(req *http.Request, resp *http.Response) ([]
 // ReloadOptions parses the config options and initializes the Bee.
 func (mod *HTTPBee) ReloadOptions(options bees.BeeOptions) {
 	mod.SetOptions(options)
+
+	client, err := newClient(options)
+	if err != nil {
+		mod.LogErrorf("Can't set up TLS: %v", err)
+	}
+	mod.client = client
 }
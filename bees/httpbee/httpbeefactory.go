@@ -64,6 +64,39 @@ func (factory *HTTPBeeFactory) LogoColor() string {
 	return "#223f5e"
 }
 
+// Options returns the options available to configure this Bee.
+func (factory *HTTPBeeFactory) Options() []bees.BeeOptionDescriptor {
+	opts := []bees.BeeOptionDescriptor{
+		{
+			Name:        "timeout",
+			Description: "Timeout for requests, eg: 30s (optional)",
+			Type:        "duration",
+		},
+		{
+			Name:        "insecure",
+			Description: "Skip verification of TLS certificates",
+			Type:        "bool",
+			Default:     false,
+		},
+		{
+			Name:        "ca_file",
+			Description: "File with PEM encoded CA certificates to trust (optional)",
+			Type:        "string",
+		},
+		{
+			Name:        "cert_file",
+			Description: "File with a PEM encoded client certificate (optional)",
+			Type:        "string",
+		},
+		{
+			Name:        "key_file",
+			Description: "File with the PEM encoded key of the client certificate (optional)",
+			Type:        "string",
+		},
+	}
+	return opts
+}
+
 // Events describes the available events provided by this Bee.
 func (factory *HTTPBeeFactory) Events() []bees.EventDescriptor {
 	events := []bees.EventDescriptor{
@@ -184,6 +217,49 @@ func (factory *HTTPBeeFactory) Actions() []bees.ActionDescriptor {
 				},
 			},
 		},
+		{
+			Namespace:   factory.Name(),
+			Name:        "request",
+			Description: "Does an HTTP request, retrying failed attempts",
+			Options: []bees.PlaceholderDescriptor{
+				{
+					Name:        "method",
+					Description: "HTTP method to use, defaults to GET",
+					Type:        "string",
+				},
+				{
+					Name:        "url",
+					Description: "Where to connect to",
+					Type:        "url",
+					Mandatory:   true,
+				},
+				{
+					Name:        "headers",
+					Description: "HTTP headers to send (e.g. 'User-Agent: BeeHive/1.0')",
+					Type:        "[]string",
+				},
+				{
+					Name:        "body",
+					Description: "Request body to send",
+					Type:        "string",
+				},
+				{
+					Name:        "content_type",
+					Description: "Content type of the request body (e.g. 'application/json')",
+					Type:        "string",
+				},
+				{
+					Name:        "retries",
+					Description: "How often to retry on network errors, 429 and 5xx responses",
+					Type:        "int",
+				},
+				{
+					Name:        "retry_delay",
+					Description: "Delay before the first retry, doubled on every attempt (default 1s)",
+					Type:        "string",
+				},
+			},
+		},
 	}
 	return actions
 }
@@ -0,0 +1,204 @@
+/*
+ *    Copyright (C) 2026 Christian Muehlhaeuser
+ *
+ *    This program is free software: you can redistribute it and/or modify
+ *    it under the terms of the GNU Affero General Public License as published
+ *    by the Free Software Foundation, either version 3 of the License, or
+ *    (at your option) any later version.
+ *
+ *    This program is distributed in the hope that it will be useful,
+ *    but WITHOUT ANY WARRANTY; without even the implied warranty of
+ *    MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ *    GNU Affero General Public License for more details.
+ *
+ *    You should have received a copy of the GNU Affero General Public License
+ *    along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ *    Authors:
+ *      Christian Muehlhaeuser <muesli@gmail.com>
+ */
+
+package httpbee
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/muesli/beehive/bees"
+)
+
+const (
+	defaultRetryDelay = time.Second
+	maxRetryDelay     = time.Minute
+)
+
+// ActionE triggers the action passed to it and reports failed requests.
+func (mod *HTTPBee) ActionE(ctx context.Context, action bees.Action) ([]bees.Placeholder, error) {
+	if action.Name != "request" {
+		return mod.Action(ctx, action), nil
+	}
+
+	method := "GET"
+	u := ""
+	body := ""
+	ctype := ""
+	h := []string{}
+	retries := 0
+	action.Options.Bind("method", &method)
+	action.Options.Bind("url", &u)
+	action.Options.Bind("body", &body)
+	action.Options.Bind("content_type", &ctype)
+	action.Options.Bind("headers", &h)
+	action.Options.Bind("retries", &retries)
+
+	delay := defaultRetryDelay
+	d := ""
+	action.Options.Bind("retry_delay", &d)
+	if d != "" {
+		var err error
+		if delay, err = time.ParseDuration(d); err != nil {
+			return nil, err
+		}
+	}
+
+	var resp *http.Response
+	var b []byte
+	var err error
+	for attempt := 0; ; attempt++ {
+		resp, b, err = mod.do(ctx, strings.ToUpper(method), u, ctype, body, h)
+		if err == nil && !retryable(resp.StatusCode) {
+			break
+		}
+		if attempt >= retries {
+			break
+		}
+
+		if err != nil {
+			mod.LogErrorf("Request to %s failed, retrying in %s: %v", u, delay, err)
+		} else {
+			mod.LogErrorf("Request to %s failed with status %d, retrying in %s", u, resp.StatusCode, delay)
+		}
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+		if delay *= 2; delay > maxRetryDelay {
+			delay = maxRetryDelay
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode >= 400 {
+		return nil, fmt.Errorf("Request to %s failed with status %s", u, resp.Status)
+	}
+
+	res := bees.Placeholders{
+		{
+			Name:  "status",
+			Type:  "int",
+			Value: resp.StatusCode,
+		},
+		{
+			Name:  "data",
+			Type:  "string",
+			Value: string(b),
+		},
+	}
+	var payload interface{}
+	if err := json.Unmarshal(b, &payload); err == nil {
+		res.SetValue("json", "map", payload)
+	}
+
+	respHeaders := []string{}
+	for name, header := range resp.Header {
+		respHeaders = append(respHeaders, name+": "+strings.Join(header, ", "))
+	}
+	res.SetValue("respHeaders", "[]string", respHeaders)
+
+	return res, nil
+}
+
+func (mod *HTTPBee) do(ctx context.Context, method, u, ctype, body string, headers []string) (*http.Response, []byte, error) {
+	req, err := http.NewRequest(method, u, strings.NewReader(body))
+	if err != nil {
+		return nil, nil, err
+	}
+	req = req.WithContext(ctx)
+	if ctype != "" {
+		req.Header.Set("Content-Type", ctype)
+	}
+	mod.parseHeaders(headers, req)
+
+	resp, err := mod.client.Do(req)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer resp.Body.Close()
+
+	b, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, nil, err
+	}
+	return resp, b, nil
+}
+
+// retryable returns whether a request that failed with the given status code
+// is worth retrying.
+func retryable(status int) bool {
+	return status == http.StatusTooManyRequests || status >= 500
+}
+
+// newClient returns an HTTP client honoring the bee's TLS options.
+func newClient(options bees.BeeOptions) (*http.Client, error) {
+	insecure := false
+	caFile := ""
+	certFile := ""
+	keyFile := ""
+	options.Bind("insecure", &insecure)
+	options.Bind("ca_file", &caFile)
+	options.Bind("cert_file", &certFile)
+	options.Bind("key_file", &keyFile)
+
+	client := &http.Client{}
+	if d, err := options.GetDuration("timeout"); err == nil {
+		client.Timeout = d
+	}
+	if !insecure && caFile == "" && certFile == "" {
+		return client, nil
+	}
+
+	cfg := &tls.Config{InsecureSkipVerify: insecure}
+	if caFile != "" {
+		pem, err := ioutil.ReadFile(caFile)
+		if err != nil {
+			return client, err
+		}
+		cfg.RootCAs = x509.NewCertPool()
+		if !cfg.RootCAs.AppendCertsFromPEM(pem) {
+			return client, errors.New("No certificates found in " + caFile)
+		}
+	}
+	if certFile != "" {
+		cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+		if err != nil {
+			return client, err
+		}
+		cfg.Certificates = []tls.Certificate{cert}
+	}
+
+	client.Transport = &http.Transport{
+		Proxy:           http.ProxyFromEnvironment,
+		TLSClientConfig: cfg,
+	}
+	return client, nil
+}
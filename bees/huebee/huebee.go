@@ -22,6 +22,7 @@
 package huebee
 
 import (
+	"context"
 	"strconv"
 	"strings"
 
@@ -43,7 +44,7 @@ type HueBee struct {
 }
 
 // Action triggers the action passed to it.
-func (mod *HueBee) Action(action bees.Action) []bees.Placeholder {
+func (mod *HueBee) Action(ctx context.Context, action bees.Action) []bees.Placeholder {
 	outs := []bees.Placeholder{}
 
 	switch action.Name {
@@ -0,0 +1,124 @@
+/*
+ *    Copyright (C) 2026 Christian Muehlhaeuser
+ *
+ *    This program is free software: you can redistribute it and/or modify
+ *    it under the terms of the GNU Affero General Public License as published
+ *    by the Free Software Foundation, either version 3 of the License, or
+ *    (at your option) any later version.
+ *
+ *    This program is distributed in the hope that it will be useful,
+ *    but WITHOUT ANY WARRANTY; without even the implied warranty of
+ *    MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ *    GNU Affero General Public License for more details.
+ *
+ *    You should have received a copy of the GNU Affero General Public License
+ *    along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ *    Authors:
+ *      Christian Muehlhaeuser <muesli@gmail.com>
+ */
+
+// Package bees is Beehive's central module system.
+package bees
+
+import (
+	"context"
+	"path"
+	"strings"
+)
+
+// BeeSeparator separates the scope from the name in a bee's identifier.
+const BeeSeparator = "/"
+
+// QualifiedBeeName returns the identifier of the bee called name in scope,
+// i.e. "scope/name". Bees without a scope are identified by their name.
+func QualifiedBeeName(scope, name string) string {
+	if len(scope) == 0 {
+		return name
+	}
+	return scope + BeeSeparator + name
+}
+
+// SplitBeeName splits a bee's identifier into its scope and its name. The
+// scope is empty for global bees.
+func SplitBeeName(id string) (scope, name string) {
+	i := strings.Index(id, BeeSeparator)
+	if i < 0 {
+		return "", id
+	}
+	return id[:i], id[i+len(BeeSeparator):]
+}
+
+// qualifyBee returns c with its name qualified by its scope, unless it
+// already is. Bees of different scopes can thus share a name.
+func qualifyBee(c BeeConfig) BeeConfig {
+	if len(c.Scope) > 0 && !strings.HasPrefix(c.Name, c.Scope+BeeSeparator) {
+		c.Name = QualifiedBeeName(c.Scope, c.Name)
+	}
+	return c
+}
+
+// matchesBeeRef returns whether ref refers to the bee identified by id: ref
+// is either the full identifier, or just the bee's name without a scope.
+func matchesBeeRef(ref, id string) bool {
+	if ref == id {
+		return true
+	}
+	if strings.Contains(ref, BeeSeparator) {
+		return false
+	}
+	_, name := SplitBeeName(id)
+	return name == ref
+}
+
+// matchesBeePattern returns whether the bee identified by id matches a
+// broadcast pattern of a chain in scope. Patterns without a scope match the
+// names of the bees in the chain's scope, patterns with a scope the bees'
+// full identifiers.
+func matchesBeePattern(pattern, id, scope string) bool {
+	if strings.Contains(pattern, BeeSeparator) {
+		ok, _ := path.Match(pattern, id)
+		return ok
+	}
+
+	s, name := SplitBeeName(id)
+	if s != scope {
+		return false
+	}
+	ok, _ := path.Match(pattern, name)
+	return ok
+}
+
+// BeeInstanceID returns the unique ID of a bee instance, see Bee.ID, or an
+// empty string for bees that don't provide one.
+func BeeInstanceID(bee *BeeInterface) string {
+	if b, ok := (*bee).(interface{ ID() string }); ok {
+		return b.ID()
+	}
+	return ""
+}
+
+// resolveBee returns the bee ref refers to, resolving a bare name within
+// scope first, see GetBee.
+func resolveBee(ref, scope string) *BeeInterface {
+	if len(scope) > 0 && !strings.Contains(ref, BeeSeparator) {
+		if bee := registry.Bee(QualifiedBeeName(scope, ref)); bee != nil {
+			return bee
+		}
+	}
+	return GetBee(ref)
+}
+
+type chainScopeKey struct{}
+
+// withChainScope returns a context remembering the scope of the chain
+// executing actions, which bare bee names in its actions get resolved in.
+func withChainScope(ctx context.Context, scope string) context.Context {
+	return context.WithValue(ctx, chainScopeKey{}, scope)
+}
+
+// chainScopeOf returns the scope remembered by withChainScope.
+func chainScopeOf(ctx context.Context) string {
+	scope, _ := ctx.Value(chainScopeKey{}).(string)
+	return scope
+}
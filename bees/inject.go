@@ -0,0 +1,113 @@
+/*
+ *    Copyright (C) 2026 Christian Muehlhaeuser
+ *
+ *    This program is free software: you can redistribute it and/or modify
+ *    it under the terms of the GNU Affero General Public License as published
+ *    by the Free Software Foundation, either version 3 of the License, or
+ *    (at your option) any later version.
+ *
+ *    This program is distributed in the hope that it will be useful,
+ *    but WITHOUT ANY WARRANTY; without even the implied warranty of
+ *    MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ *    GNU Affero General Public License for more details.
+ *
+ *    You should have received a copy of the GNU Affero General Public License
+ *    along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ *    Authors:
+ *      Christian Muehlhaeuser <muesli@gmail.com>
+ */
+
+// Package bees is Beehive's central module system.
+package bees
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// ErrNotHandling is returned when injecting an event while the hive isn't
+// handling events.
+var ErrNotHandling = errors.New("The event handler is not running")
+
+// A DryRunAction is an action a chain would have executed for an event in
+// dry-run mode, with its options rendered.
+type DryRunAction struct {
+	Chain  string
+	Action Action
+}
+
+// An InjectResult describes how an injected event got handled.
+type InjectResult struct {
+	// EventID is the ID the hive assigned to the event
+	EventID string
+	// Chains are the names of the chains the event triggered
+	Chains []string
+	// Actions are the actions the chains would have executed, if the event
+	// was injected in dry-run mode
+	Actions []DryRunAction
+}
+
+// injection collects the outcome of an injected event. It's shared by all
+// copies of the event.
+type injection struct {
+	mutex  sync.Mutex
+	result InjectResult
+}
+
+// InjectEvent pushes a synthetic event through the dispatcher, as if its bee
+// had emitted it, and waits until it has been handled, i.e. the chains it
+// triggered have completed, or ctx gets cancelled. The event's bee doesn't
+// have to exist, which allows testing chains with crafted placeholders.
+//
+// With DryRun set on the event, the chains don't execute any actions, but
+// render them, so filters and placeholder templates can be checked without
+// waiting for the real trigger. The rendered actions get returned.
+func InjectEvent(ctx context.Context, event Event) (InjectResult, error) {
+	if len(event.Bee) == 0 || len(event.Name) == 0 {
+		return InjectResult{}, errors.New("Events need a bee and a name")
+	}
+
+	event.ID = ""
+	event.trace = nil
+	event.done = &eventDone{ch: make(chan struct{})}
+	event.injected = &injection{}
+	if !injectEventSafely(event) {
+		return InjectResult{}, ErrNotHandling
+	}
+
+	select {
+	case <-event.done.ch:
+	case <-ctx.Done():
+		return InjectResult{}, ctx.Err()
+	}
+
+	event.injected.mutex.Lock()
+	defer event.injected.mutex.Unlock()
+	return event.injected.result, nil
+}
+
+// recordChains remembers the chains an injected event triggered.
+func (e *Event) recordChains(chains []string) {
+	if e.injected == nil {
+		return
+	}
+
+	e.injected.mutex.Lock()
+	defer e.injected.mutex.Unlock()
+	e.injected.result.EventID = e.ID
+	e.injected.result.Chains = append([]string{}, chains...)
+}
+
+// recordDryRun remembers an action a chain would have executed for an
+// injected event.
+func (e *Event) recordDryRun(chain string, a Action) {
+	if e == nil || e.injected == nil {
+		return
+	}
+
+	e.injected.mutex.Lock()
+	defer e.injected.mutex.Unlock()
+	e.injected.result.Actions = append(e.injected.result.Actions, DryRunAction{Chain: chain, Action: a})
+}
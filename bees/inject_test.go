@@ -0,0 +1,59 @@
+package bees
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestInjectEvent(t *testing.T) {
+	events := make(chan Event)
+	defer useEventChannel(events)()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go runEventLoop(ctx, events)
+
+	bee := newRecordingBee("injectsink")
+	defer DeleteBee(GetBee("injectsink"))
+
+	oldActions := GetActions()
+	defer SetActions(oldActions)
+	SetActions([]Action{{ID: "inject-post", Bee: "injectsink", Name: "post", Options: Placeholders{{Name: "text", Value: "Hello {{.user}}"}}}})
+	oldChains := chains
+	defer func() { chains = oldChains }()
+	SetChains([]Chain{{
+		Name:    "inject",
+		Event:   &Event{Bee: "sensor", Name: "greet"},
+		Filters: []string{`{{eq .user "muesli"}}`},
+		Actions: []string{"inject-post"},
+	}})
+
+	wait, done := context.WithTimeout(context.Background(), time.Second)
+	defer done()
+	ev := Event{Bee: "sensor", Name: "greet", Options: Placeholders{{Name: "user", Type: "string", Value: "muesli"}}, DryRun: true}
+	res, err := InjectEvent(wait, ev)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.EventID) == 0 || len(res.Chains) != 1 || res.Chains[0] != "inject" {
+		t.Errorf("Expected the event to trigger the chain, got %+v", res)
+	}
+	if len(res.Actions) != 1 || res.Actions[0].Action.Options.Value("text") != "Hello muesli" {
+		t.Errorf("Expected the rendered action, got %+v", res.Actions)
+	}
+	if got := bee.executed(); len(got) != 0 {
+		t.Errorf("Expected no actions to be executed in dry-run mode, got %v", got)
+	}
+
+	ev.DryRun = false
+	if _, err := InjectEvent(wait, ev); err != nil {
+		t.Fatal(err)
+	}
+	if got := bee.executed(); len(got) != 1 {
+		t.Errorf("Expected the action to be executed, got %v", got)
+	}
+
+	if _, err := InjectEvent(wait, Event{Bee: "sensor"}); err == nil {
+		t.Error("Expected an error for an event without a name")
+	}
+}
@@ -21,6 +21,7 @@
 package instapaperbee
 
 import (
+	"context"
 	"net/http"
 	"net/url"
 
@@ -34,7 +35,7 @@ type InstapaperBee struct {
 	password string
 }
 
-func (mod *InstapaperBee) Action(action bees.Action) []bees.Placeholder {
+func (mod *InstapaperBee) Action(ctx context.Context, action bees.Action) []bees.Placeholder {
 	switch action.Name {
 	case "save":
 		var title, page_url string
@@ -21,6 +21,8 @@
 package ipify
 
 import (
+	"context"
+	"sync/atomic"
 	"time"
 
 	"github.com/muesli/beehive/bees"
@@ -29,7 +31,8 @@ import (
 
 type IpifyBee struct {
 	bees.Bee
-	interval int
+	// interval is accessed atomically, as it can change while the bee runs
+	interval int64
 }
 
 func (mod *IpifyBee) getIP(oldIP string, eventChan chan bees.Event) string {
@@ -61,19 +64,20 @@ func (mod *IpifyBee) getIP(oldIP string, eventChan chan bees.Event) string {
 }
 
 // Run executes the Bee's event loop.
-func (mod *IpifyBee) Run(eventChan chan bees.Event) {
-	// protects us against a user setting the wrong value here
-	if mod.interval < 1 {
-		mod.interval = defaultUpdateInterval
-	}
-
+func (mod *IpifyBee) Run(ctx context.Context, eventChan chan bees.Event) {
 	oldIP := mod.getIP("", eventChan)
 
 	for {
+		interval := atomic.LoadInt64(&mod.interval)
+		// protects us against a user setting the wrong value here
+		if interval < 1 {
+			interval = int64(defaultUpdateInterval)
+		}
+
 		select {
 		case <-mod.SigChan:
 			return
-		case <-time.After(time.Duration(mod.interval) * time.Minute):
+		case <-time.After(time.Duration(interval) * time.Minute):
 			mod.LogDebugf("Retrieving public IP from ipify.com")
 			oldIP = mod.getIP(oldIP, eventChan)
 		}
@@ -81,12 +85,21 @@ func (mod *IpifyBee) Run(eventChan chan bees.Event) {
 }
 
 // Action triggers the action passed to it.
-func (mod *IpifyBee) Action(action bees.Action) []bees.Placeholder {
+func (mod *IpifyBee) Action(ctx context.Context, action bees.Action) []bees.Placeholder {
 	return []bees.Placeholder{}
 }
 
 // ReloadOptions parses the config options and initializes the Bee.
 func (mod *IpifyBee) ReloadOptions(options bees.BeeOptions) {
 	mod.SetOptions(options)
-	options.Bind("interval", &mod.interval)
+	mod.OnOptionsReload()
+}
+
+// OnOptionsReload applies a new update interval while the Bee is running. It
+// takes effect once the current interval has passed.
+func (mod *IpifyBee) OnOptionsReload() error {
+	var interval int
+	mod.Options().Bind("interval", &interval)
+	atomic.StoreInt64(&mod.interval, int64(interval))
+	return nil
 }
@@ -22,6 +22,7 @@
 package ircbee
 
 import (
+	"context"
 	"crypto/tls"
 	"net"
 	"strings"
@@ -56,7 +57,7 @@ func sendLines(to string, msg string, sendFunc func(string, string)) {
 }
 
 // Action triggers the action passed to it.
-func (mod *IrcBee) Action(action bees.Action) []bees.Placeholder {
+func (mod *IrcBee) Action(ctx context.Context, action bees.Action) []bees.Placeholder {
 	outs := []bees.Placeholder{}
 	var sendFunc func(to, msg string)
 
@@ -179,7 +180,7 @@ func (mod *IrcBee) statusChange(eventChan chan bees.Event, conn *irc.Conn, line
 }
 
 // Run executes the Bee's event loop.
-func (mod *IrcBee) Run(eventChan chan bees.Event) {
+func (mod *IrcBee) Run(ctx context.Context, eventChan chan bees.Event) {
 	if len(mod.server) == 0 {
 		return
 	}
@@ -22,6 +22,7 @@
 package jabberbee
 
 import (
+	"context"
 	"strings"
 
 	"github.com/mattn/go-xmpp"
@@ -42,7 +43,7 @@ type JabberBee struct {
 }
 
 // Action triggers the action passed to it.
-func (mod *JabberBee) Action(action bees.Action) []bees.Placeholder {
+func (mod *JabberBee) Action(ctx context.Context, action bees.Action) []bees.Placeholder {
 	outs := []bees.Placeholder{}
 
 	switch action.Name {
@@ -98,7 +99,7 @@ func (mod *JabberBee) handleEvents(eventChan chan bees.Event) error {
 }
 
 // Run executes the Bee's event loop.
-func (mod *JabberBee) Run(eventChan chan bees.Event) {
+func (mod *JabberBee) Run(ctx context.Context, eventChan chan bees.Event) {
 	if len(mod.server) == 0 {
 		return
 	}
@@ -24,6 +24,7 @@
 package jenkinsbee
 
 import (
+	"context"
 	"encoding/json"
 	"io/ioutil"
 	"net/http"
@@ -163,7 +164,7 @@ func (mod *JenkinsBee) poll() {
 }
 
 // Run executes the Bee's event loop.
-func (mod *JenkinsBee) Run(cin chan bees.Event) {
+func (mod *JenkinsBee) Run(ctx context.Context, cin chan bees.Event) {
 	mod.eventChan = cin
 	for {
 		select {
@@ -190,7 +191,7 @@ func (mod *JenkinsBee) triggerBuild(jobname string) {
 }
 
 // Action triggers the action passed to it.
-func (mod *JenkinsBee) Action(action bees.Action) []bees.Placeholder {
+func (mod *JenkinsBee) Action(ctx context.Context, action bees.Action) []bees.Placeholder {
 	outs := []bees.Placeholder{}
 	switch action.Name {
 	case "trigger":
@@ -23,6 +23,7 @@ package jirabee
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"io/ioutil"
 	"net"
@@ -46,7 +47,7 @@ type JiraBee struct {
 }
 
 // Action triggers the actions passed to it.
-func (mod *JiraBee) Action(action bees.Action) []bees.Placeholder {
+func (mod *JiraBee) Action(ctx context.Context, action bees.Action) []bees.Placeholder {
 	outs := []bees.Placeholder{}
 
 	switch action.Name {
@@ -105,7 +106,7 @@ func (mod *JiraBee) Action(action bees.Action) []bees.Placeholder {
 }
 
 // Run executes the Bee's event loop.
-func (mod *JiraBee) Run(eventChan chan bees.Event) {
+func (mod *JiraBee) Run(ctx context.Context, eventChan chan bees.Event) {
 	mod.eventChan = eventChan
 	var err error
 
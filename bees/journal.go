@@ -0,0 +1,246 @@
+/*
+ *    Copyright (C) 2026 Christian Muehlhaeuser
+ *
+ *    This program is free software: you can redistribute it and/or modify
+ *    it under the terms of the GNU Affero General Public License as published
+ *    by the Free Software Foundation, either version 3 of the License, or
+ *    (at your option) any later version.
+ *
+ *    This program is distributed in the hope that it will be useful,
+ *    but WITHOUT ANY WARRANTY; without even the implied warranty of
+ *    MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ *    GNU Affero General Public License for more details.
+ *
+ *    You should have received a copy of the GNU Affero General Public License
+ *    along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ *    Authors:
+ *      Christian Muehlhaeuser <muesli@gmail.com>
+ */
+// Package bees is Beehive's central module system.
+package bees
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+// DefaultJournalRetention is the default time journaled events are kept
+// waiting for their bees.
+const DefaultJournalRetention = 24 * time.Hour
+
+// journalEntry is an event waiting for the bees of its chains to be running.
+type journalEntry struct {
+	Event    Event
+	Received time.Time
+	Waiting  []string
+}
+
+var (
+	journalPath      string
+	journalRetention = DefaultJournalRetention
+	journalEntries   []journalEntry
+	journalMutex     sync.Mutex
+)
+
+// SetEventJournal enables the event journal, stored in the file at path. An
+// event matching chains whose actions use bees that aren't running, e.g.
+// because they are being restarted, gets persisted to the journal instead of
+// being handled. Once all those bees are running again, the event gets
+// replayed. Events waiting longer than retention get discarded.
+//
+// The journal survives restarts of beehive itself: events still waiting in
+// an existing journal get replayed once their bees have been started. An empty
+// path disables the journal, a retention of 0 uses DefaultJournalRetention.
+func SetEventJournal(path string, retention time.Duration) error {
+	journalMutex.Lock()
+	defer journalMutex.Unlock()
+
+	if retention <= 0 {
+		retention = DefaultJournalRetention
+	}
+	journalPath = path
+	journalRetention = retention
+	journalEntries = nil
+	if path == "" {
+		return nil
+	}
+
+	b, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if len(b) > 0 {
+		if err := json.Unmarshal(b, &journalEntries); err != nil {
+			return err
+		}
+	}
+	expireJournal()
+	return saveJournal()
+}
+
+// JournaledEvents returns the events waiting in the journal, oldest first.
+func JournaledEvents() []Event {
+	journalMutex.Lock()
+	defer journalMutex.Unlock()
+
+	events := make([]Event, len(journalEntries))
+	for i, entry := range journalEntries {
+		events[i] = entry.Event
+	}
+	return events
+}
+
+// journalEvent persists event if the journal is enabled and any of the bees
+// needed for the event's chains isn't running. Returns true if the event got
+// journaled and mustn't be handled now.
+func journalEvent(event *Event) bool {
+	journalMutex.Lock()
+	defer journalMutex.Unlock()
+
+	if journalPath == "" {
+		return false
+	}
+	waiting := unavailableBees(event)
+	if len(waiting) == 0 {
+		return false
+	}
+
+	logger.Infof("Journaling event %v / %v until bees are running: %v", event.Bee, event.Name, waiting)
+	journalEntries = append(journalEntries, journalEntry{
+		Event:    *event,
+		Received: clock.Now(),
+		Waiting:  waiting,
+	})
+	if err := saveJournal(); err != nil {
+		logger.Errorf("Failed to save event journal: %v", err)
+	}
+	return true
+}
+
+// replayJournal re-injects all journaled events whose bees are running now.
+// It gets called whenever a bee has been started.
+func replayJournal() {
+	journalMutex.Lock()
+	if journalPath == "" || len(journalEntries) == 0 {
+		journalMutex.Unlock()
+		return
+	}
+
+	expireJournal()
+	var ready []Event
+	var waiting []journalEntry
+	for _, entry := range journalEntries {
+		if beesRunning(entry.Waiting) {
+			ready = append(ready, entry.Event)
+		} else {
+			waiting = append(waiting, entry)
+		}
+	}
+	journalEntries = waiting
+	if len(ready) > 0 {
+		if err := saveJournal(); err != nil {
+			logger.Errorf("Failed to save event journal: %v", err)
+		}
+	}
+	journalMutex.Unlock()
+
+	for _, event := range ready {
+		logger.Infof("Replaying journaled event %v / %v", event.Bee, event.Name)
+		event.journaled = true
+		if !injectEventSafely(event) {
+			logger.Errorf("Failed to replay journaled event %v: the event handler is not running", event.ID)
+		}
+	}
+}
+
+// unavailableBees returns the names of the bees used by the actions of the
+// chains matching event which are registered, but not running.
+func unavailableBees(event *Event) []string {
+	seen := make(map[string]bool)
+	for _, c := range matchingChains(event) {
+		if !c.IsEnabled() {
+			continue
+		}
+		for _, id := range c.Actions {
+			a := GetAction(id)
+			if a == nil || isBroadcast(*a) || seen[a.Bee] {
+				continue
+			}
+			if bee := GetBee(a.Bee); bee != nil && !(*bee).IsRunning() {
+				seen[a.Bee] = true
+			}
+		}
+	}
+
+	names := make([]string, 0, len(seen))
+	for name := range seen {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// beesRunning returns true if all of the named bees are running. Bees that
+// got deleted in the meantime don't count.
+func beesRunning(names []string) bool {
+	for _, name := range names {
+		if bee := GetBee(name); bee != nil && !(*bee).IsRunning() {
+			return false
+		}
+	}
+	return true
+}
+
+// expireJournal discards journaled events older than the retention window.
+// The caller must hold journalMutex.
+func expireJournal() {
+	now := clock.Now()
+	kept := journalEntries[:0]
+	for _, entry := range journalEntries {
+		if now.Sub(entry.Received) > journalRetention {
+			logger.Warnf("Discarding journaled event %v / %v: exceeded retention of %v", entry.Event.Bee, entry.Event.Name, journalRetention)
+			continue
+		}
+		kept = append(kept, entry)
+	}
+	journalEntries = kept
+}
+
+// saveJournal atomically replaces the journal file with the current entries.
+// The caller must hold journalMutex.
+func saveJournal() error {
+	b, err := json.Marshal(journalEntries)
+	if err != nil {
+		return err
+	}
+
+	return writeFileAtomically(journalPath, b)
+}
+
+// writeFileAtomically writes b to a temporary file next to path, then renames
+// it to path, so readers never see a partially written file.
+func writeFileAtomically(path string, b []byte) error {
+	tmp, err := ioutil.TempFile(filepath.Dir(path), filepath.Base(path)+".tmp")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(b); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
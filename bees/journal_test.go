@@ -0,0 +1,96 @@
+package bees
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestEventJournal(t *testing.T) {
+	dir, err := ioutil.TempDir("", "beehive-journal")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "journal.json")
+	if err := SetEventJournal(path, 0); err != nil {
+		t.Fatal(err)
+	}
+	defer SetEventJournal("", 0)
+
+	events := make(chan Event)
+	defer useEventChannel(events)()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go runEventLoop(ctx, events)
+
+	bee := newRecordingBee("journalbee")
+	defer DeleteBee(GetBee("journalbee"))
+	bee.Stop()
+
+	oldActions := GetActions()
+	defer SetActions(oldActions)
+	SetActions([]Action{{ID: "journal-record", Bee: "journalbee", Name: "record"}})
+	oldChains := chains
+	defer func() { chains = oldChains }()
+	SetChains([]Chain{{Name: "journal", Event: &Event{Bee: "sensor", Name: "reading"}, Actions: []string{"journal-record"}}})
+
+	events <- Event{Bee: "sensor", Name: "reading"}
+	events <- Event{Bee: "sensor", Name: "unrelated"}
+	if n := len(JournaledEvents()); n != 1 {
+		t.Fatalf("Expected a single journaled event, got %d", n)
+	}
+
+	// reopening the journal, e.g. after restarting beehive, keeps the event
+	if err := SetEventJournal(path, 0); err != nil {
+		t.Fatal(err)
+	}
+	if n := len(JournaledEvents()); n != 1 {
+		t.Fatalf("Expected the journaled event to be persisted, got %d", n)
+	}
+
+	RestartBee(GetBee("journalbee"))
+	deadline := time.Now().Add(time.Second)
+	for len(bee.executed()) < 1 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	if ex := bee.executed(); len(ex) != 1 || ex[0] != "record" {
+		t.Errorf("Expected the journaled event to be replayed, got %v", ex)
+	}
+	if n := len(JournaledEvents()); n != 0 {
+		t.Errorf("Expected the journal to be empty, got %d events", n)
+	}
+}
+
+func TestEventJournalRetention(t *testing.T) {
+	fc := &fakeClock{now: time.Date(2026, 10, 14, 10, 0, 0, 0, time.Local)}
+	SetClock(fc)
+	defer SetClock(nil)
+
+	dir, err := ioutil.TempDir("", "beehive-journal")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "journal.json")
+	if err := SetEventJournal(path, time.Hour); err != nil {
+		t.Fatal(err)
+	}
+	defer SetEventJournal("", 0)
+
+	journalMutex.Lock()
+	journalEntries = []journalEntry{{Event: Event{Bee: "sensor", Name: "reading"}, Received: fc.now}}
+	saveJournal()
+	journalMutex.Unlock()
+
+	fc.now = fc.now.Add(2 * time.Hour)
+	if err := SetEventJournal(path, time.Hour); err != nil {
+		t.Fatal(err)
+	}
+	if n := len(JournaledEvents()); n != 0 {
+		t.Errorf("Expected expired events to be discarded, got %d", n)
+	}
+}
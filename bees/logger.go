@@ -0,0 +1,271 @@
+/*
+ *    Copyright (C) 2026 Christian Muehlhaeuser
+ *
+ *    This program is free software: you can redistribute it and/or modify
+ *    it under the terms of the GNU Affero General Public License as published
+ *    by the Free Software Foundation, either version 3 of the License, or
+ *    (at your option) any later version.
+ *
+ *    This program is distributed in the hope that it will be useful,
+ *    but WITHOUT ANY WARRANTY; without even the implied warranty of
+ *    MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ *    GNU Affero General Public License for more details.
+ *
+ *    You should have received a copy of the GNU Affero General Public License
+ *    along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ *    Authors:
+ *      Christian Muehlhaeuser <muesli@gmail.com>
+ */
+
+// Package bees is Beehive's central module system.
+package bees
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// Logger is the interface the bees package uses for its operational output.
+// Implement it to route beehive's logs into your own logging pipeline.
+type Logger interface {
+	Debugf(format string, args ...interface{})
+	Infof(format string, args ...interface{})
+	Warnf(format string, args ...interface{})
+	Errorf(format string, args ...interface{})
+}
+
+// LogFields are structured key/value pairs attached to a bee's log message.
+type LogFields map[string]interface{}
+
+// String formats the fields as sorted key=value pairs.
+func (f LogFields) String() string {
+	keys := make([]string, 0, len(f))
+	for k := range f {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	pairs := make([]string, len(keys))
+	for i, k := range keys {
+		pairs[i] = fmt.Sprintf("%s=%v", k, f[k])
+	}
+	return strings.Join(pairs, " ")
+}
+
+// beeLogger is implemented by Loggers that handle messages of bees
+// themselves, instead of getting them prefixed with the bee's name.
+type beeLogger interface {
+	logBee(level MessageType, bee, namespace, message string, fields LogFields)
+}
+
+// defaultLogger writes human-readable lines, or JSON, to stdLog.
+type defaultLogger struct{}
+
+func (defaultLogger) Debugf(format string, args ...interface{}) { stdLog.Debugf(format, args...) }
+func (defaultLogger) Infof(format string, args ...interface{})  { stdLog.Infof(format, args...) }
+func (defaultLogger) Warnf(format string, args ...interface{})  { stdLog.Warnf(format, args...) }
+func (defaultLogger) Errorf(format string, args ...interface{}) { stdLog.Errorf(format, args...) }
+
+func (defaultLogger) logBee(level MessageType, bee, namespace, message string, fields LogFields) {
+	entry := stdLog.WithFields(log.Fields(fields)).WithFields(log.Fields{"bee": bee, "namespace": namespace})
+	switch level {
+	case LogDebug:
+		entry.Debug(message)
+	case LogWarn:
+		entry.Warn(message)
+	case LogError, LogFatal:
+		entry.Error(message)
+	default:
+		entry.Info(message)
+	}
+}
+
+var (
+	loggerMutex  sync.RWMutex
+	activeLogger Logger = defaultLogger{}
+
+	// logger is what the package logs through; it forwards to activeLogger
+	logger Logger = loggerProxy{}
+
+	// stdLog is where the default logger writes to. Levels get filtered
+	// before reaching it
+	stdLog = newStdLog()
+
+	logLevel     = int32(LogInfo)
+	beeLogLevels = make(map[string]MessageType)
+)
+
+func newStdLog() *log.Logger {
+	l := log.New()
+	l.SetLevel(log.DebugLevel)
+	return l
+}
+
+// loggerProxy forwards every call to the currently configured Logger.
+type loggerProxy struct{}
+
+func currentLogger() Logger {
+	loggerMutex.RLock()
+	defer loggerMutex.RUnlock()
+	return activeLogger
+}
+
+func (loggerProxy) Debugf(format string, args ...interface{}) {
+	if logEnabled("", LogDebug) {
+		currentLogger().Debugf(format, args...)
+	}
+}
+
+func (loggerProxy) Infof(format string, args ...interface{}) {
+	if logEnabled("", LogInfo) {
+		currentLogger().Infof(format, args...)
+	}
+}
+
+func (loggerProxy) Warnf(format string, args ...interface{}) {
+	if logEnabled("", LogWarn) {
+		currentLogger().Warnf(format, args...)
+	}
+}
+
+func (loggerProxy) Errorf(format string, args ...interface{}) {
+	if logEnabled("", LogError) {
+		currentLogger().Errorf(format, args...)
+	}
+}
+
+// SetLogger replaces the Logger used by the bees package. Passing nil
+// restores the default logger, which writes to logrus.
+func SetLogger(l Logger) {
+	if l == nil {
+		l = defaultLogger{}
+	}
+
+	loggerMutex.Lock()
+	defer loggerMutex.Unlock()
+	activeLogger = l
+}
+
+// severity orders the MessageTypes, from LogDebug to LogFatal.
+func severity(level MessageType) int {
+	switch level {
+	case LogDebug:
+		return 0
+	case LogInfo:
+		return 1
+	case LogWarn:
+		return 2
+	case LogError:
+		return 3
+	default:
+		return 4
+	}
+}
+
+// SetLogLevel sets the minimum level of messages that get logged. Defaults
+// to LogInfo. Messages of bees with their own level, see SetBeeLogLevel, are
+// filtered by that instead.
+func SetLogLevel(level MessageType) {
+	atomic.StoreInt32(&logLevel, int32(level))
+}
+
+// SetBeeLogLevel sets the minimum level of messages that get logged for a
+// specific bee, e.g. to debug a single misbehaving bee.
+func SetBeeLogLevel(bee string, level MessageType) {
+	loggerMutex.Lock()
+	defer loggerMutex.Unlock()
+	beeLogLevels[bee] = level
+}
+
+// ResetBeeLogLevel makes a bee's messages get filtered by the level set with
+// SetLogLevel again.
+func ResetBeeLogLevel(bee string) {
+	loggerMutex.Lock()
+	defer loggerMutex.Unlock()
+	delete(beeLogLevels, bee)
+}
+
+// logEnabled returns whether messages of a bee with level get logged. Pass
+// an empty bee for messages not belonging to any bee.
+func logEnabled(bee string, level MessageType) bool {
+	min := MessageType(atomic.LoadInt32(&logLevel))
+	if bee != "" {
+		loggerMutex.RLock()
+		if l, ok := beeLogLevels[bee]; ok {
+			min = l
+		}
+		loggerMutex.RUnlock()
+	}
+
+	return severity(level) >= severity(min)
+}
+
+// SetLogOutput sets where the default logger writes to. Defaults to
+// os.Stderr.
+func SetLogOutput(w io.Writer) {
+	stdLog.SetOutput(w)
+}
+
+// AddLogHook adds a hook to the default logger, which gets all messages that
+// pass the log levels, e.g. to forward them to syslog as well.
+func AddLogHook(hook log.Hook) {
+	stdLog.AddHook(hook)
+}
+
+// SetLogJSON makes the default logger write JSON objects instead of
+// human-readable lines.
+func SetLogJSON(enabled bool) {
+	if enabled {
+		stdLog.SetFormatter(&log.JSONFormatter{})
+	} else {
+		stdLog.SetFormatter(&log.TextFormatter{})
+	}
+}
+
+// logBee logs a message of a bee, tagged with its name and namespace, and
+// keeps it in the bee's log buffer, see GetBeeLogs.
+func logBee(bee, namespace string, level MessageType, message string) {
+	logBeeFields(bee, namespace, level, message, nil)
+}
+
+// logBeeFields logs a message of a bee like logBee, with structured fields
+// attached. Loggers not handling bee messages themselves get the fields
+// appended to the message.
+func logBeeFields(bee, namespace string, level MessageType, message string, fields LogFields) {
+	if !logEnabled(bee, level) {
+		return
+	}
+	message = redactSecrets(bee, message)
+	logMessage(bee, message, level, fields)
+
+	l := currentLogger()
+	if bl, ok := l.(beeLogger); ok {
+		bl.logBee(level, bee, namespace, message, fields)
+		return
+	}
+	if len(fields) > 0 {
+		message += " " + fields.String()
+	}
+	switch level {
+	case LogDebug:
+		l.Debugf("[%s]: %s", bee, message)
+	case LogWarn:
+		l.Warnf("[%s]: %s", bee, message)
+	case LogError, LogFatal:
+		l.Errorf("[%s]: %s", bee, message)
+	default:
+		l.Infof("[%s]: %s", bee, message)
+	}
+}
+
+// logBeef logs a formatted message of a registered bee, see logBee.
+func logBeef(bee BeeInterface, level MessageType, format string, args ...interface{}) {
+	logBee(bee.Name(), bee.Namespace(), level, fmt.Sprintf(format, args...))
+}
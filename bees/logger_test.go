@@ -0,0 +1,153 @@
+package bees
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+)
+
+type recordingLogger struct {
+	sync.Mutex
+	lines []string
+}
+
+func (l *recordingLogger) record(level, format string, args ...interface{}) {
+	l.Lock()
+	defer l.Unlock()
+	l.lines = append(l.lines, level+": "+fmt.Sprintf(format, args...))
+}
+
+func (l *recordingLogger) Debugf(format string, args ...interface{}) {
+	l.record("debug", format, args...)
+}
+func (l *recordingLogger) Infof(format string, args ...interface{}) {
+	l.record("info", format, args...)
+}
+func (l *recordingLogger) Warnf(format string, args ...interface{}) {
+	l.record("warn", format, args...)
+}
+func (l *recordingLogger) Errorf(format string, args ...interface{}) {
+	l.record("error", format, args...)
+}
+
+func TestSetLogger(t *testing.T) {
+	rl := &recordingLogger{}
+	SetLogger(rl)
+	defer SetLogger(nil)
+
+	bee := NewBee("loggerbee", "recordingbee", "", BeeOptions{})
+	bee.Logf("hello %s", "world")
+	bee.LogErrorf("oops")
+	bee.Logln("plain", 42)
+
+	rl.Lock()
+	defer rl.Unlock()
+	exp := []string{
+		"info: [loggerbee]: hello world",
+		"error: [loggerbee]: oops",
+		"info: [loggerbee]: plain 42",
+	}
+	if strings.Join(rl.lines, "\n") != strings.Join(exp, "\n") {
+		t.Errorf("Unexpected log output: %q", rl.lines)
+	}
+}
+
+func TestLogLevels(t *testing.T) {
+	rl := &recordingLogger{}
+	SetLogger(rl)
+	defer SetLogger(nil)
+	SetLogLevel(LogError)
+	defer SetLogLevel(LogInfo)
+	SetBeeLogLevel("verbosebee", LogDebug)
+	defer ResetBeeLogLevel("verbosebee")
+
+	quiet := NewBee("quietbee", "recordingbee", "", BeeOptions{})
+	verbose := NewBee("verbosebee", "recordingbee", "", BeeOptions{})
+	quiet.Logf("hidden")
+	quiet.LogErrorf("shown")
+	verbose.Debugf("details")
+	logger.Infof("hidden")
+	logger.Errorf("global")
+
+	rl.Lock()
+	defer rl.Unlock()
+	exp := []string{
+		"error: [quietbee]: shown",
+		"debug: [verbosebee]: details",
+		"error: global",
+	}
+	if strings.Join(rl.lines, "\n") != strings.Join(exp, "\n") {
+		t.Errorf("Unexpected log output: %q", rl.lines)
+	}
+}
+
+func TestGetBeeLogs(t *testing.T) {
+	SetLogger(&recordingLogger{})
+	defer SetLogger(nil)
+	SetBeeLogSize(3)
+	defer SetBeeLogSize(DefaultBeeLogSize)
+
+	bee := NewBee("ringbee", "recordingbee", "", BeeOptions{})
+	for i := 0; i < 10; i++ {
+		bee.Logf("line %d", i)
+	}
+
+	ls := GetBeeLogs("ringbee", 2)
+	if len(ls) != 2 || ls[0].Message != "line 9" || ls[1].Message != "line 8" {
+		t.Errorf("Expected the two newest lines, got %v", ls)
+	}
+	if ls := GetBeeLogs("ringbee", 0); len(ls) != 3 || ls[2].Message != "line 7" {
+		t.Errorf("Expected the kept lines, got %v", ls)
+	}
+}
+
+func TestLogFields(t *testing.T) {
+	rl := &recordingLogger{}
+	SetLogger(rl)
+	defer SetLogger(nil)
+
+	bee := NewBee("fieldsbee", "recordingbee", "", BeeOptions{})
+	bee.WithFields(LogFields{"user": "alice", "channel": "#ops"}).LogWarnf("kicked %d times", 2)
+	bee.WithFields(LogFields{"code": 500}).LogErrorf("request failed")
+
+	rl.Lock()
+	exp := []string{
+		"warn: [fieldsbee]: kicked 2 times channel=#ops user=alice",
+		"error: [fieldsbee]: request failed code=500",
+	}
+	if strings.Join(rl.lines, "\n") != strings.Join(exp, "\n") {
+		t.Errorf("Unexpected log output: %q", rl.lines)
+	}
+	rl.Unlock()
+
+	ls := GetBeeLogs("fieldsbee", 0)
+	if len(ls) != 2 || ls[1].Fields["user"] != "alice" || ls[0].Fields["code"] != 500 {
+		t.Errorf("Expected the fields to be kept with the messages, got %v", ls)
+	}
+	if bee.Stats().LastError != "request failed" {
+		t.Errorf("Expected the error to be recorded, got %q", bee.Stats().LastError)
+	}
+}
+
+func TestSetLogJSON(t *testing.T) {
+	var buf bytes.Buffer
+	SetLogOutput(&buf)
+	defer SetLogOutput(os.Stderr)
+	SetLogJSON(true)
+	defer SetLogJSON(false)
+
+	bee := NewBee("jsonbee", "recordingbee", "", BeeOptions{})
+	bee.Logf("hello")
+
+	var entry map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("Expected a JSON log entry, got %q: %v", buf.String(), err)
+	}
+	if entry["bee"] != "jsonbee" || entry["namespace"] != "recordingbee" || entry["msg"] != "hello" {
+		t.Errorf("Unexpected log entry: %v", entry)
+	}
+}
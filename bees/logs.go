@@ -34,6 +34,7 @@ type LogMessage struct {
 	Message     string
 	MessageType uint
 	Timestamp   time.Time
+	Fields      LogFields
 }
 
 var (
@@ -56,8 +57,36 @@ const (
 
 	// LogDebug is for debug-level log entries
 	LogDebug MessageType = iota
+
+	// LogWarn is for warning-level log entries
+	LogWarn MessageType = iota
 )
 
+// DefaultBeeLogSize is the number of log messages kept per bee.
+const DefaultBeeLogSize = 1000
+
+var beeLogSize = DefaultBeeLogSize
+
+// SetBeeLogSize sets the number of log messages kept per bee. Older messages
+// get dropped.
+func SetBeeLogSize(size int) {
+	logMutex.Lock()
+	defer logMutex.Unlock()
+
+	beeLogSize = size
+	for bee, ls := range logs {
+		logs[bee] = trimLogs(ls, size)
+	}
+}
+
+// trimLogs drops all but the last size messages from ls.
+func trimLogs(ls []LogMessage, size int) []LogMessage {
+	if len(ls) <= size {
+		return ls
+	}
+	return append([]LogMessage{}, ls[len(ls)-size:]...)
+}
+
 // LogSorter is used for sorting an array of LogMessages by their timestamp
 type LogSorter []LogMessage
 
@@ -78,10 +107,22 @@ func NewLogMessage(bee string, message string, messageType MessageType) LogMessa
 
 // Log adds a new LogMessage to the log
 func Log(bee string, message string, messageType MessageType) {
+	logMessage(bee, message, messageType, nil)
+}
+
+// logMessage adds a new LogMessage with structured fields to the log.
+func logMessage(bee string, message string, messageType MessageType, fields LogFields) {
+	msg := NewLogMessage(bee, message, messageType)
+	msg.Fields = fields
+
 	logMutex.Lock()
 	defer logMutex.Unlock()
 
-	logs[bee] = append(logs[bee], NewLogMessage(bee, message, messageType))
+	ls := append(logs[bee], msg)
+	if len(ls) > 2*beeLogSize {
+		ls = trimLogs(ls, beeLogSize)
+	}
+	logs[bee] = ls
 }
 
 // GetLogs returns all logs for a Bee.
@@ -91,7 +132,7 @@ func GetLogs(bee string) []LogMessage {
 	logMutex.RLock()
 	for b, ls := range logs {
 		if len(bee) == 0 || bee == b {
-			for _, l := range ls {
+			for _, l := range trimLogs(ls, beeLogSize) {
 				r = append(r, l)
 			}
 		}
@@ -101,3 +142,21 @@ func GetLogs(bee string) []LogMessage {
 	sort.Sort(LogSorter(r))
 	return r
 }
+
+// GetBeeLogs returns the last limit log messages of a bee, newest first. A
+// limit of 0 returns all messages kept, see SetBeeLogSize.
+func GetBeeLogs(bee string, limit int) []LogMessage {
+	logMutex.RLock()
+	ls := trimLogs(logs[bee], beeLogSize)
+	logMutex.RUnlock()
+
+	if limit > 0 {
+		ls = trimLogs(ls, limit)
+	}
+	r := make([]LogMessage, len(ls))
+	for i, l := range ls {
+		r[len(ls)-1-i] = l
+	}
+
+	return r
+}
@@ -0,0 +1,223 @@
+/*
+ *    Copyright (C) 2026 Christian Muehlhaeuser
+ *
+ *    This program is free software: you can redistribute it and/or modify
+ *    it under the terms of the GNU Affero General Public License as published
+ *    by the Free Software Foundation, either version 3 of the License, or
+ *    (at your option) any later version.
+ *
+ *    This program is distributed in the hope that it will be useful,
+ *    but WITHOUT ANY WARRANTY; without even the implied warranty of
+ *    MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ *    GNU Affero General Public License for more details.
+ *
+ *    You should have received a copy of the GNU Affero General Public License
+ *    along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ *    Authors:
+ *      Christian Muehlhaeuser <muesli@gmail.com>
+ */
+
+// Package logwatchbee is a Bee that follows journald or syslog streams and
+// emits events for log lines matching patterns.
+package logwatchbee
+
+import (
+	"context"
+	"regexp"
+	"time"
+
+	"github.com/muesli/beehive/bees"
+)
+
+// retryInterval is the time to wait before following a source again after
+// it failed, e.g. because journalctl exited.
+const retryInterval = 10 * time.Second
+
+// severities are the names of the syslog priorities, from 0 to 7.
+var severities = []string{"emerg", "alert", "crit", "err", "warning", "notice", "info", "debug"}
+
+// logEntry is a single line read from a log source.
+type logEntry struct {
+	message  string
+	host     string
+	ident    string
+	priority int
+	time     time.Time
+}
+
+// LogWatchBee is a Bee that follows journald or syslog streams and emits
+// events for log lines matching patterns.
+type LogWatchBee struct {
+	bees.Bee
+
+	source   string
+	units    []string
+	path     string
+	address  string
+	priority int
+	patterns []*regexp.Regexp
+	matchAll bool
+}
+
+// Run executes the Bee's event loop.
+func (mod *LogWatchBee) Run(ctx context.Context, eventChan chan bees.Event) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	entries := make(chan logEntry)
+	go mod.follow(ctx, entries)
+
+	for {
+		select {
+		case <-mod.SigChan:
+			return
+		case <-ctx.Done():
+			return
+		case e := <-entries:
+			mod.handleEntry(e, eventChan)
+		}
+	}
+}
+
+// follow reads entries from the configured source until ctx is done,
+// following it again whenever it fails.
+func (mod *LogWatchBee) follow(ctx context.Context, entries chan<- logEntry) {
+	for {
+		var err error
+		switch mod.source {
+		case "syslog":
+			err = listenSyslog(ctx, mod.address, entries)
+		case "file":
+			err = followFile(ctx, mod.path, entries)
+		default:
+			err = followJournal(ctx, mod.units, entries)
+		}
+		if ctx.Err() != nil {
+			return
+		}
+		mod.LogErrorf("Can't follow %s: %v", mod.source, err)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(retryInterval):
+		}
+	}
+}
+
+// handleEntry emits an event for an entry matching one of the patterns, or
+// for every entry if none are configured. Named groups of the matching pattern
+// become placeholders of the event.
+func (mod *LogWatchBee) handleEntry(e logEntry, eventChan chan bees.Event) {
+	if e.priority > mod.priority {
+		return
+	}
+
+	pattern := ""
+	var groups map[string]string
+	if !mod.matchAll {
+		for _, re := range mod.patterns {
+			match := re.FindStringSubmatch(e.message)
+			if match == nil {
+				continue
+			}
+
+			pattern = re.String()
+			groups = make(map[string]string)
+			for i, name := range re.SubexpNames() {
+				if i > 0 && name != "" {
+					groups[name] = match[i]
+				}
+			}
+			break
+		}
+		if groups == nil {
+			return
+		}
+	}
+
+	severity := ""
+	if e.priority >= 0 && e.priority < len(severities) {
+		severity = severities[e.priority]
+	}
+
+	ev := bees.Event{
+		Bee:  mod.Name(),
+		Name: "match",
+		Options: []bees.Placeholder{
+			{
+				Name:  "message",
+				Type:  "string",
+				Value: e.message,
+			},
+			{
+				Name:  "host",
+				Type:  "string",
+				Value: e.host,
+			},
+			{
+				Name:  "ident",
+				Type:  "string",
+				Value: e.ident,
+			},
+			{
+				Name:  "priority",
+				Type:  "int",
+				Value: e.priority,
+			},
+			{
+				Name:  "severity",
+				Type:  "string",
+				Value: severity,
+			},
+			{
+				Name:  "time",
+				Type:  "timestamp",
+				Value: e.time,
+			},
+			{
+				Name:  "pattern",
+				Type:  "string",
+				Value: pattern,
+			},
+		},
+	}
+	for name, value := range groups {
+		ev.Options.SetValue(name, "string", value)
+	}
+
+	select {
+	case eventChan <- ev:
+	case <-mod.Context().Done():
+	}
+}
+
+// ReloadOptions parses the config options and initializes the Bee.
+func (mod *LogWatchBee) ReloadOptions(options bees.BeeOptions) {
+	mod.SetOptions(options)
+
+	mod.source = "journald"
+	options.Bind("source", &mod.source)
+	mod.units = nil
+	options.Bind("units", &mod.units)
+	mod.path = "/var/log/syslog"
+	options.Bind("path", &mod.path)
+	mod.address = "127.0.0.1:5514"
+	options.Bind("address", &mod.address)
+	mod.priority = len(severities) - 1
+	options.Bind("priority", &mod.priority)
+
+	var patterns []string
+	options.Bind("patterns", &patterns)
+	mod.matchAll = len(patterns) == 0
+	mod.patterns = nil
+	for _, p := range patterns {
+		re, err := regexp.Compile(p)
+		if err != nil {
+			mod.LogErrorf("Invalid pattern %s: %v", p, err)
+			continue
+		}
+		mod.patterns = append(mod.patterns, re)
+	}
+}
@@ -0,0 +1,109 @@
+package logwatchbee
+
+import (
+	"testing"
+	"time"
+
+	"github.com/muesli/beehive/bees"
+)
+
+func TestHandleEntry(t *testing.T) {
+	now := time.Now()
+	login := `^Failed password for (?P<user>\S+) from (?P<ip>\S+)`
+	disk := `^disk (?P<device>\w+) is full$`
+
+	for _, tt := range []struct {
+		name     string
+		patterns []string
+		priority int
+		entry    logEntry
+		emitted  bool
+		expected map[string]interface{}
+		// the number of placeholders, named groups included
+		placeholders int
+	}{
+		{
+			name:     "named groups",
+			patterns: []string{login, disk},
+			priority: 7,
+			entry:    logEntry{message: "Failed password for root from 10.0.0.1 port 22", host: "web1", ident: "sshd", priority: 4, time: now},
+			emitted:  true,
+			expected: map[string]interface{}{
+				"message": "Failed password for root from 10.0.0.1 port 22", "host": "web1", "ident": "sshd",
+				"priority": 4, "severity": "warning", "time": now, "pattern": login,
+				"user": "root", "ip": "10.0.0.1",
+			},
+			placeholders: 9,
+		},
+		{
+			name:         "first matching pattern",
+			patterns:     []string{login, disk},
+			priority:     7,
+			entry:        logEntry{message: "disk sda is full", priority: 2},
+			emitted:      true,
+			expected:     map[string]interface{}{"pattern": disk, "device": "sda", "severity": "crit"},
+			placeholders: 8,
+		},
+		{
+			name:     "no match",
+			patterns: []string{login, disk},
+			priority: 7,
+			entry:    logEntry{message: "Accepted password for root", priority: 6},
+		},
+		{
+			name:     "too low priority",
+			patterns: []string{disk},
+			priority: 3,
+			entry:    logEntry{message: "disk sda is full", priority: 4},
+		},
+		{
+			name:         "unnamed groups",
+			patterns:     []string{`^(\w+) started$`},
+			priority:     7,
+			entry:        logEntry{message: "cron started", priority: 6},
+			emitted:      true,
+			expected:     map[string]interface{}{"pattern": `^(\w+) started$`, "message": "cron started"},
+			placeholders: 7,
+		},
+		{
+			name:         "no patterns",
+			priority:     7,
+			entry:        logEntry{message: "anything", priority: 7},
+			emitted:      true,
+			expected:     map[string]interface{}{"pattern": "", "message": "anything", "severity": "debug"},
+			placeholders: 7,
+		},
+	} {
+		mod := &LogWatchBee{Bee: bees.NewBee("logwatch", "logwatchbee", "", bees.BeeOptions{})}
+		mod.ReloadOptions(bees.BeeOptions{
+			{Name: "patterns", Value: tt.patterns},
+			{Name: "priority", Value: tt.priority},
+		})
+
+		events := make(chan bees.Event, 1)
+		mod.handleEntry(tt.entry, events)
+		if len(events) != 1 {
+			if tt.emitted {
+				t.Errorf("%s: expected an event", tt.name)
+			}
+			continue
+		}
+		if !tt.emitted {
+			t.Errorf("%s: expected no event", tt.name)
+			continue
+		}
+
+		ev := <-events
+		if ev.Bee != "logwatch" || ev.Name != "match" {
+			t.Errorf("%s: expected a match event, got %s / %s", tt.name, ev.Bee, ev.Name)
+		}
+		for name, value := range tt.expected {
+			if v := ev.Options.Value(name); v != value {
+				t.Errorf("%s: expected %s to be %v, got %v", tt.name, name, value, v)
+			}
+		}
+		if len(ev.Options) != tt.placeholders {
+			t.Errorf("%s: expected %d placeholders, got %v", tt.name, tt.placeholders, ev.Options)
+		}
+	}
+}
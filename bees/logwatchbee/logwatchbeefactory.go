@@ -0,0 +1,161 @@
+/*
+ *    Copyright (C) 2026 Christian Muehlhaeuser
+ *
+ *    This program is free software: you can redistribute it and/or modify
+ *    it under the terms of the GNU Affero General Public License as published
+ *    by the Free Software Foundation, either version 3 of the License, or
+ *    (at your option) any later version.
+ *
+ *    This program is distributed in the hope that it will be useful,
+ *    but WITHOUT ANY WARRANTY; without even the implied warranty of
+ *    MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ *    GNU Affero General Public License for more details.
+ *
+ *    You should have received a copy of the GNU Affero General Public License
+ *    along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ *    Authors:
+ *      Christian Muehlhaeuser <muesli@gmail.com>
+ */
+
+package logwatchbee
+
+import (
+	"github.com/muesli/beehive/bees"
+)
+
+// LogWatchBeeFactory is a factory for LogWatchBees.
+type LogWatchBeeFactory struct {
+	bees.BeeFactory
+}
+
+// New returns a new Bee instance configured with the supplied options.
+func (factory *LogWatchBeeFactory) New(name, description string, options bees.BeeOptions) bees.BeeInterface {
+	bee := LogWatchBee{
+		Bee: bees.NewBee(name, factory.ID(), description, options),
+	}
+	bee.ReloadOptions(options)
+
+	return &bee
+}
+
+// ID returns the ID of this Bee.
+func (factory *LogWatchBeeFactory) ID() string {
+	return "logwatchbee"
+}
+
+// Name returns the name of this Bee.
+func (factory *LogWatchBeeFactory) Name() string {
+	return "Log Watch"
+}
+
+// Description returns the description of this Bee.
+func (factory *LogWatchBeeFactory) Description() string {
+	return "Follows journald or syslog and reacts to matching log lines"
+}
+
+// Image returns the filename of an image for this Bee.
+func (factory *LogWatchBeeFactory) Image() string {
+	return "execbee.png"
+}
+
+// LogoColor returns the preferred logo background color (used by the admin interface).
+func (factory *LogWatchBeeFactory) LogoColor() string {
+	return "#4b4b4b"
+}
+
+// Options returns the options available to configure this Bee.
+func (factory *LogWatchBeeFactory) Options() []bees.BeeOptionDescriptor {
+	opts := []bees.BeeOptionDescriptor{
+		{
+			Name:        "source",
+			Description: "Where to read log lines from: journald, syslog (receive messages over UDP) or file (follow a syslog file)",
+			Type:        "string",
+			Default:     "journald",
+			Choices:     []interface{}{"journald", "syslog", "file"},
+		},
+		{
+			Name:        "patterns",
+			Description: "Regular expressions log lines have to match, eg: Failed password for (?P<user>\\S+). Named groups become placeholders. Empty matches all lines",
+			Type:        "[]string",
+		},
+		{
+			Name:        "priority",
+			Description: "Highest syslog priority to react to, from 0 (emerg) to 7 (debug)",
+			Type:        "int",
+			Default:     7,
+		},
+		{
+			Name:        "units",
+			Description: "Systemd units to follow with the journald source. Empty follows the whole journal",
+			Type:        "[]string",
+		},
+		{
+			Name:        "address",
+			Description: "Address to receive syslog messages on with the syslog source, eg: 127.0.0.1:5514",
+			Type:        "string",
+			Default:     "127.0.0.1:5514",
+		},
+		{
+			Name:        "path",
+			Description: "Log file to follow with the file source",
+			Type:        "string",
+			Default:     "/var/log/syslog",
+		},
+	}
+	return opts
+}
+
+// Events describes the available events provided by this Bee.
+func (factory *LogWatchBeeFactory) Events() []bees.EventDescriptor {
+	events := []bees.EventDescriptor{
+		{
+			Namespace:   factory.Name(),
+			Name:        "match",
+			Description: "A log line matched one of the patterns. Named groups of the pattern are available as additional placeholders",
+			Options: []bees.PlaceholderDescriptor{
+				{
+					Name:        "message",
+					Description: "The log message",
+					Type:        "string",
+				},
+				{
+					Name:        "host",
+					Description: "Host the message originated from",
+					Type:        "string",
+				},
+				{
+					Name:        "ident",
+					Description: "Unit or program that logged the message",
+					Type:        "string",
+				},
+				{
+					Name:        "priority",
+					Description: "Syslog priority of the message, from 0 (emerg) to 7 (debug)",
+					Type:        "int",
+				},
+				{
+					Name:        "severity",
+					Description: "Name of the priority, eg: err",
+					Type:        "string",
+				},
+				{
+					Name:        "time",
+					Description: "When the message got logged",
+					Type:        "timestamp",
+				},
+				{
+					Name:        "pattern",
+					Description: "The pattern that matched",
+					Type:        "string",
+				},
+			},
+		},
+	}
+	return events
+}
+
+func init() {
+	f := LogWatchBeeFactory{}
+	bees.RegisterFactory(&f)
+}
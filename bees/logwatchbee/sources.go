@@ -0,0 +1,338 @@
+/*
+ *    Copyright (C) 2026 Christian Muehlhaeuser
+ *
+ *    This program is free software: you can redistribute it and/or modify
+ *    it under the terms of the GNU Affero General Public License as published
+ *    by the Free Software Foundation, either version 3 of the License, or
+ *    (at your option) any later version.
+ *
+ *    This program is distributed in the hope that it will be useful,
+ *    but WITHOUT ANY WARRANTY; without even the implied warranty of
+ *    MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ *    GNU Affero General Public License for more details.
+ *
+ *    You should have received a copy of the GNU Affero General Public License
+ *    along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ *    Authors:
+ *      Christian Muehlhaeuser <muesli@gmail.com>
+ */
+
+package logwatchbee
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"net"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// pollInterval is how often followFile checks its file for new lines.
+const pollInterval = time.Second
+
+// send hands an entry to entries, unless ctx is done first.
+func send(ctx context.Context, entries chan<- logEntry, e logEntry) bool {
+	select {
+	case entries <- e:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// followJournal follows the systemd journal with journalctl, optionally
+// restricted to units, until ctx is done or journalctl exits.
+func followJournal(ctx context.Context, units []string, entries chan<- logEntry) error {
+	args := []string{"--follow", "--lines=0", "--output=json"}
+	for _, u := range units {
+		args = append(args, "--unit="+u)
+	}
+
+	cmd := exec.CommandContext(ctx, "journalctl", args...)
+	out, err := cmd.StdoutPipe()
+	if err != nil {
+		return err
+	}
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+
+	scanner := bufio.NewScanner(out)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		e, ok := parseJournalEntry(scanner.Bytes())
+		if !ok {
+			continue
+		}
+		if !send(ctx, entries, e) {
+			break
+		}
+	}
+
+	if err := cmd.Wait(); err != nil {
+		return err
+	}
+	return io.ErrUnexpectedEOF
+}
+
+// parseJournalEntry parses an entry in journalctl's JSON output format.
+func parseJournalEntry(b []byte) (logEntry, bool) {
+	var fields map[string]interface{}
+	if err := json.Unmarshal(b, &fields); err != nil {
+		return logEntry{}, false
+	}
+
+	field := func(name string) string {
+		switch v := fields[name].(type) {
+		case string:
+			return v
+		case []interface{}:
+			// fields which aren't valid UTF-8 get encoded as byte arrays
+			b := make([]byte, 0, len(v))
+			for _, c := range v {
+				if f, ok := c.(float64); ok {
+					b = append(b, byte(f))
+				}
+			}
+			return string(b)
+		}
+		return ""
+	}
+
+	e := logEntry{
+		message:  field("MESSAGE"),
+		host:     field("_HOSTNAME"),
+		ident:    field("SYSLOG_IDENTIFIER"),
+		priority: 6,
+		time:     time.Now(),
+	}
+	if unit := field("_SYSTEMD_UNIT"); unit != "" {
+		e.ident = unit
+	}
+	if p, err := strconv.Atoi(field("PRIORITY")); err == nil {
+		e.priority = p
+	}
+	if us, err := strconv.ParseInt(field("__REALTIME_TIMESTAMP"), 10, 64); err == nil {
+		e.time = time.Unix(0, us*int64(time.Microsecond))
+	}
+	return e, true
+}
+
+// listenSyslog receives syslog messages over UDP on address until ctx is
+// done.
+func listenSyslog(ctx context.Context, address string, entries chan<- logEntry) error {
+	conn, err := net.ListenPacket("udp", address)
+	if err != nil {
+		return err
+	}
+	go func() {
+		<-ctx.Done()
+		conn.Close()
+	}()
+
+	buf := make([]byte, 64*1024)
+	for {
+		n, addr, err := conn.ReadFrom(buf)
+		if err != nil {
+			return err
+		}
+
+		e := parseSyslog(string(buf[:n]))
+		if e.host == "" {
+			if udp, ok := addr.(*net.UDPAddr); ok {
+				e.host = udp.IP.String()
+			}
+		}
+		if !send(ctx, entries, e) {
+			return nil
+		}
+	}
+}
+
+// parseSyslog parses an RFC 5424 or RFC 3164 syslog message, or a line of a
+// syslog file, which lacks the leading priority. Messages it can't make sense
+// of are kept as they are, with the priority of a notice.
+func parseSyslog(msg string) logEntry {
+	msg = strings.TrimRight(msg, "\r\n\x00")
+	e := logEntry{message: msg, priority: 5, time: time.Now()}
+
+	if strings.HasPrefix(msg, "<") {
+		end := strings.IndexByte(msg, '>')
+		if end < 2 || end > 4 {
+			return e
+		}
+		pri, err := strconv.Atoi(msg[1:end])
+		if err != nil {
+			return e
+		}
+		e.priority = pri % 8
+		msg = msg[end+1:]
+		e.message = msg
+
+		if strings.HasPrefix(msg, "1 ") {
+			// RFC 5424: VERSION TIMESTAMP HOSTNAME APP-NAME PROCID MSGID SD MSG
+			parts := strings.SplitN(msg[2:], " ", 6)
+			if len(parts) < 6 {
+				return e
+			}
+			if t, err := time.Parse(time.RFC3339Nano, parts[0]); err == nil {
+				e.time = t
+			}
+			e.host = nilValue(parts[1])
+			e.ident = nilValue(parts[2])
+			e.message = skipStructuredData(parts[5])
+			return e
+		}
+	}
+
+	// RFC 3164: TIMESTAMP HOSTNAME TAG: MSG, with a timestamp like
+	// "Oct 14 12:00:00" lacking the year. Syslog files may also use RFC 3339
+	// timestamps instead
+	var rest string
+	if t, err := time.ParseInLocation(time.Stamp, prefix(msg, len(time.Stamp)), time.Local); err == nil {
+		e.time = t.AddDate(time.Now().Year(), 0, 0)
+		rest = msg[len(time.Stamp):]
+	} else if i := strings.IndexByte(msg, ' '); i > 0 {
+		t, err := time.Parse(time.RFC3339Nano, msg[:i])
+		if err != nil {
+			return e
+		}
+		e.time = t
+		rest = msg[i:]
+	} else {
+		return e
+	}
+	rest = strings.TrimLeft(rest, " ")
+
+	if i := strings.IndexByte(rest, ' '); i > 0 {
+		e.host = rest[:i]
+		rest = rest[i+1:]
+	}
+	if i := strings.Index(rest, ": "); i > 0 && !strings.Contains(rest[:i], " ") {
+		e.ident = rest[:i]
+		if j := strings.IndexByte(e.ident, '['); j > 0 {
+			e.ident = e.ident[:j]
+		}
+		rest = rest[i+2:]
+	}
+	e.message = rest
+	return e
+}
+
+// prefix returns the first n bytes of s, or all of s if it's shorter.
+func prefix(s string, n int) string {
+	if len(s) < n {
+		return s
+	}
+	return s[:n]
+}
+
+// nilValue returns s, or an empty string for RFC 5424's nil value "-".
+func nilValue(s string) string {
+	if s == "-" {
+		return ""
+	}
+	return s
+}
+
+// skipStructuredData strips the structured data elements, or their nil
+// value, off the front of an RFC 5424 message.
+func skipStructuredData(s string) string {
+	if strings.HasPrefix(s, "-") {
+		return strings.TrimPrefix(s[1:], " ")
+	}
+
+	escaped := false
+	inValue := false
+	depth := 0
+	for i, c := range s {
+		switch {
+		case escaped:
+			escaped = false
+		case c == '\\':
+			escaped = true
+		case c == '"':
+			inValue = !inValue
+		case inValue:
+		case c == '[':
+			depth++
+		case c == ']':
+			depth--
+			if depth == 0 && (i+1 == len(s) || s[i+1] != '[') {
+				return strings.TrimPrefix(s[i+1:], " ")
+			}
+		}
+	}
+	return s
+}
+
+// followFile follows a log file like "tail -F" does, starting at its end
+// and reopening it once it got rotated or truncated.
+func followFile(ctx context.Context, path string, entries chan<- logEntry) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		f.Close()
+	}()
+
+	offset, err := f.Seek(0, io.SeekEnd)
+	if err != nil {
+		return err
+	}
+	r := bufio.NewReader(f)
+	partial := ""
+
+	for {
+		line, err := r.ReadString('\n')
+		offset += int64(len(line))
+		if err == nil {
+			e := parseSyslog(partial + line)
+			partial = ""
+			if !send(ctx, entries, e) {
+				return nil
+			}
+			continue
+		}
+		if err != io.EOF {
+			return err
+		}
+		partial += line
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(pollInterval):
+		}
+
+		fi, err := os.Stat(path)
+		if err != nil {
+			// the file got rotated away; wait for its successor
+			continue
+		}
+		cur, err := f.Stat()
+		if err != nil {
+			return err
+		}
+		if os.SameFile(fi, cur) && fi.Size() >= offset {
+			continue
+		}
+
+		nf, err := os.Open(path)
+		if err != nil {
+			continue
+		}
+		f.Close()
+		f = nf
+		r.Reset(f)
+		offset = 0
+		partial = ""
+	}
+}
@@ -47,7 +47,7 @@ type MastodonBee struct {
 }
 
 // Action triggers the action passed to it.
-func (mod *MastodonBee) Action(action bees.Action) []bees.Placeholder {
+func (mod *MastodonBee) Action(ctx context.Context, action bees.Action) []bees.Placeholder {
 	outs := []bees.Placeholder{}
 
 	switch action.Name {
@@ -58,7 +58,7 @@ func (mod *MastodonBee) Action(action bees.Action) []bees.Placeholder {
 
 		// Post status toot on mastodon, event gets triggered automatically via
 		// the toot_fetched even.
-		_, err := mod.client.PostStatus(context.Background(), &mastodon.Toot{
+		_, err := mod.client.PostStatus(ctx, &mastodon.Toot{
 			Status: text,
 		})
 		if err != nil {
@@ -71,21 +71,21 @@ func (mod *MastodonBee) Action(action bees.Action) []bees.Placeholder {
 		mod.Logf("Attempting to delete toot \"%s\"", id)
 
 		// Event is automatically handled in handleStreamEvent()
-		err := mod.client.DeleteStatus(context.Background(), mastodon.ID(id))
+		err := mod.client.DeleteStatus(ctx, mastodon.ID(id))
 		if err != nil {
 			mod.LogErrorf("Error deleting toot: %v", err)
 		}
 
 	case "get_toots": // returns the current user's toots
 		mod.Logf("Attempting to get current user")
-		acc, err := mod.client.GetAccountCurrentUser(context.Background())
+		acc, err := mod.client.GetAccountCurrentUser(ctx)
 		if err != nil {
 			mod.LogErrorf("Error getting current user: %v", err)
 			return outs
 		}
 
 		mod.Logf("Attempting to get current user's toots")
-		statuses, err := mod.client.GetAccountStatuses(context.Background(), acc.ID, &mastodon.Pagination{})
+		statuses, err := mod.client.GetAccountStatuses(ctx, acc.ID, &mastodon.Pagination{})
 		if err != nil {
 			mod.LogErrorf("Error getting current user's toots: %v", err)
 			return outs
@@ -101,7 +101,7 @@ func (mod *MastodonBee) Action(action bees.Action) []bees.Placeholder {
 		action.Options.Bind("id", &id)
 		mod.Logf("Attempting to follow account: %s", id)
 
-		rel, err := mod.client.AccountFollow(context.Background(), mastodon.ID(id))
+		rel, err := mod.client.AccountFollow(ctx, mastodon.ID(id))
 		if err != nil {
 			mod.LogErrorf("Failed to follow account %s: %v", id, err)
 			return outs
@@ -140,7 +140,7 @@ func (mod *MastodonBee) Action(action bees.Action) []bees.Placeholder {
 		action.Options.Bind("id", &id)
 		mod.Logf("Attempting to unfollow account: %s", id)
 
-		rel, err := mod.client.AccountUnfollow(context.Background(), mastodon.ID(id))
+		rel, err := mod.client.AccountUnfollow(ctx, mastodon.ID(id))
 		if err != nil {
 			mod.LogErrorf("Failed to unfollow account %s: %v", id, err)
 			return outs
@@ -174,7 +174,7 @@ func (mod *MastodonBee) Action(action bees.Action) []bees.Placeholder {
 		action.Options.Bind("id", &id)
 		mod.Logf("Attempting to favourite toot: %s", id)
 
-		status, err := mod.client.Favourite(context.Background(), mastodon.ID(id))
+		status, err := mod.client.Favourite(ctx, mastodon.ID(id))
 		if err != nil {
 			mod.LogErrorf("Failed to favourite toot: %v", err)
 		}
@@ -223,7 +223,7 @@ func (mod *MastodonBee) Action(action bees.Action) []bees.Placeholder {
 		mod.Logf("Attempting to reblog toot %s", id)
 
 		// reblog-Event should automatically be handled in handleStreamEvent()
-		status, err := mod.client.Reblog(context.Background(), mastodon.ID(id))
+		status, err := mod.client.Reblog(ctx, mastodon.ID(id))
 		if err != nil {
 			mod.LogErrorf("Failed to reblog toot: %v", err)
 			return outs
@@ -310,7 +310,7 @@ func (mod *MastodonBee) handleStream() {
 }
 
 // Run executes the Bee's event loop.
-func (mod *MastodonBee) Run(eventChan chan bees.Event) {
+func (mod *MastodonBee) Run(ctx context.Context, eventChan chan bees.Event) {
 	// Create the new api client
 	c := mastodon.NewClient(&mastodon.Config{
 		Server:       mod.server,
@@ -318,13 +318,13 @@ func (mod *MastodonBee) Run(eventChan chan bees.Event) {
 		ClientSecret: mod.clientSecret,
 	})
 	// authorize it
-	err := c.Authenticate(context.Background(), mod.email, mod.password)
+	err := c.Authenticate(ctx, mod.email, mod.password)
 	if err != nil {
 		mod.LogErrorf("Authorization failed, make sure the mastodon credentials are correct: %s", err)
 		return
 	}
 	// try to get user account
-	acc, err := c.GetAccountCurrentUser(context.Background())
+	acc, err := c.GetAccountCurrentUser(ctx)
 	if err != nil {
 		mod.LogErrorf("Failed to get current user account: %v", err)
 	}
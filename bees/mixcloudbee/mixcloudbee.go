@@ -23,6 +23,7 @@
 package mixcloudbee
 
 import (
+	"context"
 	"time"
 
 	"github.com/horrendus/go-mixcloud"
@@ -102,7 +103,7 @@ func (mod *MixcloudBee) pollFeed(feed string) {
 }
 
 // Action triggers the action passed to it.
-func (mod *MixcloudBee) Action(action bees.Action) []bees.Placeholder {
+func (mod *MixcloudBee) Action(ctx context.Context, action bees.Action) []bees.Placeholder {
 	outs := []bees.Placeholder{}
 
 	switch action.Name {
@@ -116,7 +117,7 @@ func (mod *MixcloudBee) Action(action bees.Action) []bees.Placeholder {
 }
 
 // Run executes the Bee's event loop.
-func (mod *MixcloudBee) Run(cin chan bees.Event) {
+func (mod *MixcloudBee) Run(ctx context.Context, cin chan bees.Event) {
 	mod.eventChan = cin
 }
 
@@ -0,0 +1,204 @@
+/*
+ *    Copyright (C) 2026 Christian Muehlhaeuser
+ *
+ *    This program is free software: you can redistribute it and/or modify
+ *    it under the terms of the GNU Affero General Public License as published
+ *    by the Free Software Foundation, either version 3 of the License, or
+ *    (at your option) any later version.
+ *
+ *    This program is distributed in the hope that it will be useful,
+ *    but WITHOUT ANY WARRANTY; without even the implied warranty of
+ *    MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ *    GNU Affero General Public License for more details.
+ *
+ *    You should have received a copy of the GNU Affero General Public License
+ *    along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ *    Authors:
+ *      Christian Muehlhaeuser <muesli@gmail.com>
+ */
+
+// Package mqttbee is a Bee that can subscribe to and publish on MQTT topics.
+package mqttbee
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	mqtt "github.com/eclipse/paho.mqtt.golang"
+
+	"github.com/muesli/beehive/bees"
+)
+
+const publishTimeout = 30 * time.Second
+
+var errNotConnected = errors.New("Not connected to the MQTT broker")
+
+// MQTTBee is a Bee that can subscribe to and publish on MQTT topics.
+type MQTTBee struct {
+	bees.Bee
+
+	broker   string
+	clientID string
+	username string
+	password string
+	topics   []string
+	qos      byte
+
+	client    mqtt.Client
+	eventChan chan bees.Event
+}
+
+// Run executes the Bee's event loop.
+func (mod *MQTTBee) Run(ctx context.Context, cin chan bees.Event) {
+	mod.eventChan = cin
+
+	opts := mqtt.NewClientOptions().
+		AddBroker(mod.broker).
+		SetClientID(mod.clientID).
+		SetUsername(mod.username).
+		SetPassword(mod.password).
+		SetAutoReconnect(true).
+		SetOnConnectHandler(mod.subscribe).
+		SetConnectionLostHandler(func(c mqtt.Client, err error) {
+			mod.LogErrorf("Lost connection to %s: %v", mod.broker, err)
+		})
+
+	mod.client = mqtt.NewClient(opts)
+	for {
+		token := mod.client.Connect()
+		token.Wait()
+		if token.Error() == nil {
+			break
+		}
+		mod.LogErrorf("Can't connect to %s: %v", mod.broker, token.Error())
+
+		select {
+		case <-mod.SigChan:
+			return
+		case <-ctx.Done():
+			return
+		case <-time.After(10 * time.Second):
+		}
+	}
+	defer mod.client.Disconnect(250)
+
+	select {
+	case <-mod.SigChan:
+	case <-ctx.Done():
+	}
+}
+
+// subscribe (re-)subscribes to the configured topics after connecting.
+func (mod *MQTTBee) subscribe(c mqtt.Client) {
+	mod.Logf("Connected to %s", mod.broker)
+
+	for _, topic := range mod.topics {
+		token := c.Subscribe(topic, mod.qos, mod.handleMessage)
+		token.Wait()
+		if token.Error() != nil {
+			mod.LogErrorf("Can't subscribe to %s: %v", topic, token.Error())
+		}
+	}
+}
+
+func (mod *MQTTBee) handleMessage(c mqtt.Client, msg mqtt.Message) {
+	ev := bees.Event{
+		Bee:  mod.Name(),
+		Name: "message",
+		Options: []bees.Placeholder{
+			{
+				Name:  "topic",
+				Type:  "string",
+				Value: msg.Topic(),
+			},
+			{
+				Name:  "payload",
+				Type:  "string",
+				Value: string(msg.Payload()),
+			},
+			{
+				Name:  "qos",
+				Type:  "int",
+				Value: int(msg.Qos()),
+			},
+			{
+				Name:  "retained",
+				Type:  "bool",
+				Value: msg.Retained(),
+			},
+		},
+	}
+
+	var payload interface{}
+	if err := json.Unmarshal(msg.Payload(), &payload); err == nil {
+		ev.Options.SetValue("json", "map", payload)
+	}
+
+	select {
+	case mod.eventChan <- ev:
+	case <-mod.Context().Done():
+	}
+}
+
+// ActionE triggers the action passed to it.
+func (mod *MQTTBee) ActionE(ctx context.Context, action bees.Action) ([]bees.Placeholder, error) {
+	outs := []bees.Placeholder{}
+
+	switch action.Name {
+	case "publish":
+		topic := ""
+		payload := ""
+		qos := int(mod.qos)
+		retained := false
+		action.Options.Bind("topic", &topic)
+		action.Options.Bind("payload", &payload)
+		action.Options.Bind("qos", &qos)
+		action.Options.Bind("retained", &retained)
+
+		if mod.client == nil || !mod.client.IsConnected() {
+			return outs, errNotConnected
+		}
+
+		token := mod.client.Publish(topic, byte(qos), retained, payload)
+		if !token.WaitTimeout(publishTimeout) {
+			return outs, errors.New("Timed out publishing to " + topic)
+		}
+		if err := token.Error(); err != nil {
+			return outs, err
+		}
+
+	default:
+		panic("Unknown action triggered in " + mod.Name() + ": " + action.Name)
+	}
+
+	return outs, nil
+}
+
+// Action triggers the action passed to it.
+func (mod *MQTTBee) Action(ctx context.Context, action bees.Action) []bees.Placeholder {
+	outs, err := mod.ActionE(ctx, action)
+	if err != nil {
+		mod.LogErrorf("Error: %v", err)
+	}
+	return outs
+}
+
+// ReloadOptions parses the config options and initializes the Bee.
+func (mod *MQTTBee) ReloadOptions(options bees.BeeOptions) {
+	mod.SetOptions(options)
+
+	options.Bind("broker", &mod.broker)
+	mod.clientID = "beehive-" + mod.Name()
+	options.Bind("client_id", &mod.clientID)
+	options.Bind("username", &mod.username)
+	options.Bind("password", &mod.password)
+	mod.topics = nil
+	options.Bind("topics", &mod.topics)
+
+	qos := 0
+	options.Bind("qos", &qos)
+	mod.qos = byte(qos)
+}
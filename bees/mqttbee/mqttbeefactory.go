@@ -0,0 +1,183 @@
+/*
+ *    Copyright (C) 2026 Christian Muehlhaeuser
+ *
+ *    This program is free software: you can redistribute it and/or modify
+ *    it under the terms of the GNU Affero General Public License as published
+ *    by the Free Software Foundation, either version 3 of the License, or
+ *    (at your option) any later version.
+ *
+ *    This program is distributed in the hope that it will be useful,
+ *    but WITHOUT ANY WARRANTY; without even the implied warranty of
+ *    MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ *    GNU Affero General Public License for more details.
+ *
+ *    You should have received a copy of the GNU Affero General Public License
+ *    along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ *    Authors:
+ *      Christian Muehlhaeuser <muesli@gmail.com>
+ */
+
+package mqttbee
+
+import (
+	"github.com/muesli/beehive/bees"
+)
+
+// MQTTBeeFactory is a factory for MQTTBees.
+type MQTTBeeFactory struct {
+	bees.BeeFactory
+}
+
+// New returns a new Bee instance configured with the supplied options.
+func (factory *MQTTBeeFactory) New(name, description string, options bees.BeeOptions) bees.BeeInterface {
+	bee := MQTTBee{
+		Bee: bees.NewBee(name, factory.ID(), description, options),
+	}
+	bee.ReloadOptions(options)
+
+	return &bee
+}
+
+// ID returns the ID of this Bee.
+func (factory *MQTTBeeFactory) ID() string {
+	return "mqttbee"
+}
+
+// Name returns the name of this Bee.
+func (factory *MQTTBeeFactory) Name() string {
+	return "MQTT"
+}
+
+// Description returns the description of this Bee.
+func (factory *MQTTBeeFactory) Description() string {
+	return "Subscribes to and publishes on MQTT topics"
+}
+
+// Image returns the filename of an image for this Bee.
+func (factory *MQTTBeeFactory) Image() string {
+	return "socketbee.png"
+}
+
+// LogoColor returns the preferred logo background color (used by the admin interface).
+func (factory *MQTTBeeFactory) LogoColor() string {
+	return "#660066"
+}
+
+// Options returns the options available to configure this Bee.
+func (factory *MQTTBeeFactory) Options() []bees.BeeOptionDescriptor {
+	opts := []bees.BeeOptionDescriptor{
+		{
+			Name:        "broker",
+			Description: "URL of the MQTT broker, eg: tcp://localhost:1883",
+			Type:        "url",
+			Mandatory:   true,
+		},
+		{
+			Name:        "client_id",
+			Description: "Client ID to connect with (defaults to beehive-<bee name>)",
+			Type:        "string",
+		},
+		{
+			Name:        "username",
+			Description: "Username to authenticate with (optional)",
+			Type:        "string",
+		},
+		{
+			Name:        "password",
+			Description: "Password to authenticate with (optional)",
+			Type:        "password",
+		},
+		{
+			Name:        "topics",
+			Description: "Topics to subscribe to, wildcards are supported, eg: sensors/+/temperature",
+			Type:        "[]string",
+		},
+		{
+			Name:        "qos",
+			Description: "Quality of service level for subscriptions and publishing (0, 1 or 2)",
+			Type:        "int",
+			Default:     0,
+		},
+	}
+	return opts
+}
+
+// Events describes the available events provided by this Bee.
+func (factory *MQTTBeeFactory) Events() []bees.EventDescriptor {
+	events := []bees.EventDescriptor{
+		{
+			Namespace:   factory.Name(),
+			Name:        "message",
+			Description: "A message was received on a subscribed topic",
+			Options: []bees.PlaceholderDescriptor{
+				{
+					Name:        "topic",
+					Description: "Topic the message was published on",
+					Type:        "string",
+				},
+				{
+					Name:        "payload",
+					Description: "Raw payload of the message",
+					Type:        "string",
+				},
+				{
+					Name:        "json",
+					Description: "Payload decoded as JSON, if it is valid JSON",
+					Type:        "map",
+				},
+				{
+					Name:        "qos",
+					Description: "Quality of service level of the message",
+					Type:        "int",
+				},
+				{
+					Name:        "retained",
+					Description: "Whether this is a retained message",
+					Type:        "bool",
+				},
+			},
+		},
+	}
+	return events
+}
+
+// Actions describes the available actions provided by this Bee.
+func (factory *MQTTBeeFactory) Actions() []bees.ActionDescriptor {
+	actions := []bees.ActionDescriptor{
+		{
+			Namespace:   factory.Name(),
+			Name:        "publish",
+			Description: "Publishes a message on a topic",
+			Options: []bees.PlaceholderDescriptor{
+				{
+					Name:        "topic",
+					Description: "Topic to publish on",
+					Type:        "string",
+					Mandatory:   true,
+				},
+				{
+					Name:        "payload",
+					Description: "Payload of the message",
+					Type:        "string",
+				},
+				{
+					Name:        "qos",
+					Description: "Quality of service level (defaults to the bee's setting)",
+					Type:        "int",
+				},
+				{
+					Name:        "retained",
+					Description: "Whether the broker should retain the message",
+					Type:        "bool",
+				},
+			},
+		},
+	}
+	return actions
+}
+
+func init() {
+	f := MQTTBeeFactory{}
+	bees.RegisterFactory(&f)
+}
@@ -22,6 +22,7 @@
 package mumblebee
 
 import (
+	"context"
 	"crypto/tls"
 	"net"
 	"strconv"
@@ -49,7 +50,7 @@ type MumbleBee struct {
 }
 
 // Action triggers the action passed to it.
-func (mod *MumbleBee) Action(action bees.Action) []bees.Placeholder {
+func (mod *MumbleBee) Action(ctx context.Context, action bees.Action) []bees.Placeholder {
 	outs := []bees.Placeholder{}
 
 	switch action.Name {
@@ -106,7 +107,7 @@ func (mod *MumbleBee) move(channel string) {
 }
 
 // Run executes the Bee's event loop.
-func (mod *MumbleBee) Run(eventChan chan bees.Event) {
+func (mod *MumbleBee) Run(ctx context.Context, eventChan chan bees.Event) {
 	if len(mod.server) == 0 {
 		return
 	}
@@ -35,6 +35,7 @@
 package nagiosbee
 
 import (
+	"context"
 	"encoding/json"
 	"io/ioutil"
 	"net/http"
@@ -149,7 +150,7 @@ func (mod *NagiosBee) poll() {
 }
 
 // Run executes the Bee's event loop.
-func (mod *NagiosBee) Run(cin chan bees.Event) {
+func (mod *NagiosBee) Run(ctx context.Context, cin chan bees.Event) {
 	mod.eventChan = cin
 
 	for {
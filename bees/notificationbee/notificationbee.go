@@ -26,6 +26,7 @@
 package notificationbee
 
 import (
+	"context"
 	"strings"
 
 	"github.com/muesli/beehive/bees"
@@ -54,7 +55,7 @@ type NotificationBee struct {
 }
 
 // Run executes the Bee's event loop.
-func (mod *NotificationBee) Run(cin chan bees.Event) {
+func (mod *NotificationBee) Run(ctx context.Context, cin chan bees.Event) {
 	select {
 	case <-mod.SigChan:
 		return
@@ -62,7 +63,7 @@ func (mod *NotificationBee) Run(cin chan bees.Event) {
 }
 
 // Action triggers the action passed to it.
-func (mod *NotificationBee) Action(action bees.Action) []bees.Placeholder {
+func (mod *NotificationBee) Action(ctx context.Context, action bees.Action) []bees.Placeholder {
 	outs := []bees.Placeholder{}
 
 	switch action.Name {
@@ -23,6 +23,8 @@
 package openweathermapbee
 
 import (
+	"context"
+
 	"github.com/muesli/beehive/bees"
 
 	owm "github.com/briandowns/openweathermap"
@@ -42,7 +44,7 @@ type OpenweathermapBee struct {
 }
 
 // Action triggers the action passed to it.
-func (mod *OpenweathermapBee) Action(action bees.Action) []bees.Placeholder {
+func (mod *OpenweathermapBee) Action(ctx context.Context, action bees.Action) []bees.Placeholder {
 	outs := []bees.Placeholder{}
 
 	switch action.Name {
@@ -66,7 +68,7 @@ func (mod *OpenweathermapBee) Action(action bees.Action) []bees.Placeholder {
 }
 
 // Run executes the Bee's event loop.
-func (mod *OpenweathermapBee) Run(eventChan chan bees.Event) {
+func (mod *OpenweathermapBee) Run(ctx context.Context, eventChan chan bees.Event) {
 	mod.evchan = eventChan
 
 	current, err := owm.NewCurrent(mod.unit, mod.language, mod.key)
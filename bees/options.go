@@ -21,7 +21,15 @@
 // Package bees is Beehive's central module system.
 package bees
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
 
 // A FilterOption used by filters.
 type FilterOption struct {
@@ -62,3 +70,254 @@ func (opts BeeOptions) Bind(name string, dst interface{}) error {
 
 	return ConvertValue(v, dst)
 }
+
+// refPrefix marks a string option value as a reference to a shared value.
+const refPrefix = "$ref:"
+
+// ReconfiguredEvent is the name of the event emitted by a bee after its
+// options have been updated, see SetReconfigureNotifications.
+const ReconfiguredEvent = "bee.reconfigured"
+
+var (
+	reconfigureNotifications int32
+
+	references     = make(map[string]interface{})
+	rawOptions     = make(map[string]BeeOptions)
+	referenceMutex sync.RWMutex
+)
+
+// SetReferences sets the shared values bee options can refer to.
+func SetReferences(refs map[string]interface{}) {
+	referenceMutex.Lock()
+	defer referenceMutex.Unlock()
+
+	references = make(map[string]interface{})
+	for k, v := range refs {
+		references[k] = v
+	}
+}
+
+// reference returns the name of the shared value v refers to. References are
+// either of the form {"$ref": "name"} or the string "$ref:name".
+func reference(v interface{}) (string, bool) {
+	switch vt := v.(type) {
+	case string:
+		if strings.HasPrefix(vt, refPrefix) {
+			return strings.TrimSpace(strings.TrimPrefix(vt, refPrefix)), true
+		}
+	case map[string]interface{}:
+		if len(vt) == 1 {
+			name, ok := vt["$ref"].(string)
+			return name, ok
+		}
+	case map[interface{}]interface{}:
+		if len(vt) == 1 {
+			name, ok := vt["$ref"].(string)
+			return name, ok
+		}
+	}
+
+	return "", false
+}
+
+// ResolveOptions returns a copy of options with all references replaced by
+// the shared values they refer to and encrypted values decrypted, see
+// SetSecretKey. It fails if a reference is dangling or a value can't be
+// decrypted.
+func ResolveOptions(options BeeOptions) (BeeOptions, error) {
+	referenceMutex.RLock()
+	defer referenceMutex.RUnlock()
+
+	r := BeeOptions{}
+	for _, opt := range options {
+		if name, ok := reference(opt.Value); ok {
+			v, ok := references[name]
+			if !ok {
+				return nil, fmt.Errorf("Option %s references unknown shared value %s", opt.Name, name)
+			}
+			opt.Value = v
+		}
+		if isEncrypted(opt.Value) {
+			v, err := decryptSecret(opt.Value.(string))
+			if err != nil {
+				return nil, fmt.Errorf("Option %s: %v", opt.Name, err)
+			}
+			opt.Value = v
+		}
+		r = append(r, opt)
+	}
+
+	return r, nil
+}
+
+// hasReferences returns whether any of the options refer to a shared value.
+func hasReferences(options BeeOptions) bool {
+	for _, opt := range options {
+		if _, ok := reference(opt.Value); ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+// resolveBeeOptions resolves the references in a bee's options and decrypts
+// its encrypted options. The unresolved options are kept around, so the bee's
+// config can be saved without losing its references or exposing its secrets.
+func resolveBeeOptions(name, class string, options BeeOptions) (BeeOptions, error) {
+	resolved, err := ResolveOptions(options)
+	if err != nil {
+		return nil, fmt.Errorf("Bee %s: %v", name, err)
+	}
+	setBeeSecrets(name, class, options, resolved)
+
+	referenceMutex.Lock()
+	defer referenceMutex.Unlock()
+	if hasReferences(options) || hasSecrets(options) {
+		rawOptions[name] = options
+	} else {
+		delete(rawOptions, name)
+	}
+
+	return resolved, nil
+}
+
+// ReloadBeeOptions resolves the references in options, validates them and
+// reloads a bee with them.
+func ReloadBeeOptions(bee *BeeInterface, options BeeOptions) error {
+	resolved, err := resolveBeeOptions((*bee).Name(), (*bee).Namespace(), options)
+	if err != nil {
+		return err
+	}
+	resolved, errs := PrepareOptions((*bee).Namespace(), resolved)
+	if len(errs) > 0 {
+		return validationError(errs)
+	}
+
+	(*bee).ReloadOptions(resolved)
+	setInstanceDescriptors(bee)
+	return nil
+}
+
+// ErrRestartRequired can be returned by a bee's OnOptionsReload method when
+// it can't apply its new options while running.
+var ErrRestartRequired = errors.New("Bee needs to be restarted to apply its options")
+
+// ReloadOptions resolves the references in options, validates them and
+// updates a running bee with them, without stopping it: the options get set
+// via SetOptions and the bee gets notified via OnOptionsReload. Bees which
+// can't be reconfigured live return ErrRestartRequired, in which case they get
+// reloaded and restarted.
+func ReloadOptions(bee *BeeInterface, options BeeOptions) error {
+	resolved, err := resolveBeeOptions((*bee).Name(), (*bee).Namespace(), options)
+	if err != nil {
+		return err
+	}
+	resolved, errs := PrepareOptions((*bee).Namespace(), resolved)
+	if len(errs) > 0 {
+		return validationError(errs)
+	}
+
+	(*bee).SetOptions(resolved)
+	setInstanceDescriptors(bee)
+	err = (*bee).OnOptionsReload()
+	if err == ErrRestartRequired {
+		(*bee).ReloadOptions(resolved)
+		RestartBee(bee)
+		return nil
+	}
+
+	return err
+}
+
+// ReconfigureBee updates the options of a single running bee, leaving all
+// other bees untouched. The options get applied as described for
+// ReloadOptions: live, or by restarting just this bee.
+func ReconfigureBee(name string, options BeeOptions) error {
+	bee := GetBee(name)
+	if bee == nil {
+		return ErrUnknownBee
+	}
+
+	return ReloadOptions(bee, options)
+}
+
+// SetReconfigureNotifications enables or disables emitting a
+// "bee.reconfigured" event whenever a bee's options get updated with
+// UpdateBeeOptions. The event is emitted on behalf of the reconfigured bee and
+// carries the names of the changed options, as well as their new values.
+// Values of password options are redacted.
+func SetReconfigureNotifications(enabled bool) {
+	var v int32
+	if enabled {
+		v = 1
+	}
+	atomic.StoreInt32(&reconfigureNotifications, v)
+}
+
+// UpdateBeeOptions updates the options of a bee. Running bees get updated as
+// described for ReloadOptions: live, unless they ask to be restarted.
+func UpdateBeeOptions(name string, options BeeOptions) error {
+	bee := GetBee(name)
+	if bee == nil {
+		return ErrUnknownBee
+	}
+
+	old := (*bee).Options()
+	update := ReloadBeeOptions
+	if (*bee).IsRunning() {
+		update = ReloadOptions
+	}
+	if err := update(bee, options); err != nil {
+		return err
+	}
+
+	if atomic.LoadInt32(&reconfigureNotifications) != 0 {
+		changed, values := changedOptions((*bee).Namespace(), old, (*bee).Options())
+		if len(changed) > 0 {
+			go injectEvent(Event{
+				Bee:  name,
+				Name: ReconfiguredEvent,
+				Options: Placeholders{
+					{Name: "bee", Type: "string", Value: name},
+					{Name: "options", Type: "[]string", Value: changed},
+					{Name: "values", Type: "map", Value: values},
+				},
+			})
+		}
+	}
+
+	return nil
+}
+
+// changedOptions returns the sorted names of all options that differ between
+// old and cur, and their current values, with secrets redacted.
+func changedOptions(class string, old, cur BeeOptions) ([]string, map[string]interface{}) {
+	secrets := secretOptions(class)
+
+	names := make(map[string]bool)
+	for _, opt := range old {
+		names[opt.Name] = true
+	}
+	for _, opt := range cur {
+		names[opt.Name] = true
+	}
+
+	changed := []string{}
+	values := make(map[string]interface{})
+	for name := range names {
+		v := cur.Value(name)
+		if reflect.DeepEqual(old.Value(name), v) {
+			continue
+		}
+
+		changed = append(changed, name)
+		if secrets[name] && v != nil {
+			v = PasswordMask
+		}
+		values[name] = v
+	}
+	sort.Strings(changed)
+
+	return changed, values
+}
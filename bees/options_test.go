@@ -0,0 +1,219 @@
+package bees
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestResolveOptions(t *testing.T) {
+	SetReferences(map[string]interface{}{
+		"sharedToken": "s3cr3t",
+	})
+	defer SetReferences(nil)
+
+	opts := BeeOptions{
+		{Name: "token", Value: map[string]interface{}{"$ref": "sharedToken"}},
+		{Name: "other_token", Value: "$ref:sharedToken"},
+		{Name: "channel", Value: "#beehive"},
+	}
+
+	resolved, err := ResolveOptions(opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"token", "other_token"} {
+		if v := resolved.Value(name); v != "s3cr3t" {
+			t.Errorf("Expected option %s to resolve to the shared value, got %v", name, v)
+		}
+	}
+	if v := resolved.Value("channel"); v != "#beehive" {
+		t.Errorf("Expected plain option to be unchanged, got %v", v)
+	}
+	if _, ok := opts.Value("token").(map[string]interface{}); !ok {
+		t.Error("ResolveOptions must not modify the original options")
+	}
+
+	opts = BeeOptions{{Name: "token", Value: "$ref:missing"}}
+	if _, err := ResolveOptions(opts); err == nil {
+		t.Error("Expected an error for a dangling reference")
+	}
+}
+
+func TestChangedOptions(t *testing.T) {
+	old := BeeOptions{
+		{Name: "server", Value: "irc.example.org"},
+		{Name: "nick", Value: "beehive"},
+		{Name: "channels", Value: []string{"#beehive"}},
+	}
+	cur := BeeOptions{
+		{Name: "server", Value: "irc.example.org"},
+		{Name: "nick", Value: "beehive2"},
+		{Name: "channels", Value: []string{"#beehive"}},
+		{Name: "password", Value: "s3cr3t"},
+	}
+
+	changed, values := changedOptions("", old, cur)
+	if len(changed) != 2 || changed[0] != "nick" || changed[1] != "password" {
+		t.Errorf("Unexpected changed options: %v", changed)
+	}
+	if values["nick"] != "beehive2" {
+		t.Errorf("Unexpected value for changed option: %v", values["nick"])
+	}
+}
+
+// restartingBee can't apply its options while running.
+type restartingBee struct {
+	recordingBee
+
+	reloads int
+}
+
+func (mod *restartingBee) ReloadOptions(options BeeOptions) {
+	mod.reloads++
+	mod.SetOptions(options)
+}
+
+func (mod *restartingBee) OnOptionsReload() error {
+	return ErrRestartRequired
+}
+
+// reloadingBee remembers the options it saw when getting notified about a
+// reload.
+type reloadingBee struct {
+	recordingBee
+
+	reloaded BeeOptions
+}
+
+func (mod *reloadingBee) OnOptionsReload() error {
+	mod.reloaded = mod.Options()
+	return nil
+}
+
+func TestReloadOptions(t *testing.T) {
+	live := newRecordingBee("livebee")
+	defer DeleteBee(GetBee("livebee"))
+
+	if err := ReloadOptions(GetBee("livebee"), BeeOptions{{Name: "interval", Value: 5}}); err != nil {
+		t.Fatal(err)
+	}
+	if live.Options().Value("interval") != 5 || !live.IsRunning() {
+		t.Errorf("Expected live bee to keep running with its new options, got %v", live.Options())
+	}
+
+	notified := &reloadingBee{recordingBee: recordingBee{Bee: NewBee("reloadbee", "recordingbee", "", BeeOptions{})}}
+	notified.Start()
+	defer notified.Stop()
+	var nb BeeInterface = notified
+	if err := ReloadOptions(&nb, BeeOptions{{Name: "interval", Value: 7}}); err != nil {
+		t.Fatal(err)
+	}
+	if notified.reloaded.Value("interval") != 7 {
+		t.Errorf("Expected the options to be set before notifying the bee, got %v", notified.reloaded)
+	}
+
+	mod := &restartingBee{recordingBee: recordingBee{Bee: NewBee("restartbee", "recordingbee", "", BeeOptions{})}}
+	mod.Start()
+	var bee BeeInterface = mod
+	if err := ReloadOptions(&bee, BeeOptions{{Name: "interval", Value: 10}}); err != nil {
+		t.Fatal(err)
+	}
+	defer mod.Stop()
+
+	if mod.reloads != 1 || mod.Options().Value("interval") != 10 {
+		t.Errorf("Expected bee to get reloaded, got %d reloads with %v", mod.reloads, mod.Options())
+	}
+	if !mod.IsRunning() {
+		t.Error("Expected bee to be restarted")
+	}
+}
+
+// greeterBee emits its greeting when triggered. It needs a restart to pick
+// up a new greeting.
+type greeterBee struct {
+	restartingBee
+
+	greeting string
+}
+
+func (mod *greeterBee) ReloadOptions(options BeeOptions) {
+	mod.SetOptions(options)
+	options.Bind("greeting", &mod.greeting)
+}
+
+func (mod *greeterBee) Trigger(eventChan chan Event) error {
+	eventChan <- Event{Bee: mod.Name(), Name: "greeting", Options: Placeholders{
+		{Name: "text", Type: "string", Value: mod.greeting},
+	}}
+	return nil
+}
+
+func TestReconfigureBee(t *testing.T) {
+	options := BeeOptions{{Name: "greeting", Value: "hello"}}
+	mod := &greeterBee{restartingBee: restartingBee{recordingBee: recordingBee{Bee: NewBee("greeterbee", "recordingbee", "", options)}}}
+	mod.ReloadOptions(options)
+	var bee BeeInterface = mod
+	RegisterBee(bee)
+	launchBee(&bee)
+	defer DeleteBee(GetBee("greeterbee"))
+
+	if err := ReconfigureBee("nosuchbee", options); err != ErrUnknownBee {
+		t.Errorf("Expected ErrUnknownBee, got %v", err)
+	}
+	if err := ReconfigureBee("greeterbee", BeeOptions{{Name: "greeting", Value: "howdy"}}); err != nil {
+		t.Fatal(err)
+	}
+	if !mod.IsRunning() {
+		t.Error("Expected bee to be running after reconfiguring it")
+	}
+
+	events := make(chan Event, 1)
+	mod.Trigger(events)
+	if ev := <-events; ev.Options.Value("text") != "howdy" {
+		t.Errorf("Expected event to carry the new greeting, got %v", ev.Options.Value("text"))
+	}
+}
+
+func TestUpdateBeeOptions(t *testing.T) {
+	live := newRecordingBee("updatebee")
+	defer DeleteBee(GetBee("updatebee"))
+
+	ctx := live.Context()
+	if err := UpdateBeeOptions("updatebee", BeeOptions{{Name: "interval", Value: 5}}); err != nil {
+		t.Fatal(err)
+	}
+	if live.Options().Value("interval") != 5 {
+		t.Errorf("Expected the bee's options to be updated, got %v", live.Options())
+	}
+	if live.Context() != ctx || ctx.Err() != nil {
+		t.Error("Expected a bee supporting live updates to keep running undisturbed")
+	}
+
+	mod := &restartingBee{recordingBee: recordingBee{Bee: NewBee("updaterestartbee", "recordingbee", "", BeeOptions{})}}
+	var bee BeeInterface = mod
+	RegisterBee(bee)
+	defer DeleteBee(GetBee("updaterestartbee"))
+
+	if err := UpdateBeeOptions("updaterestartbee", BeeOptions{{Name: "interval", Value: 5}}); err != nil {
+		t.Fatal(err)
+	}
+	if mod.reloads != 1 || mod.IsRunning() {
+		t.Errorf("Expected a stopped bee to get reloaded without being started, got %d reloads", mod.reloads)
+	}
+
+	mod.Start()
+	ctx = mod.Context()
+	if err := UpdateBeeOptions("updaterestartbee", BeeOptions{{Name: "interval", Value: 10}}); err != nil {
+		t.Fatal(err)
+	}
+	if mod.reloads != 2 || mod.Options().Value("interval") != 10 {
+		t.Errorf("Expected the bee to get reloaded, got %d reloads with %v", mod.reloads, mod.Options())
+	}
+	if ctx.Err() == nil || !mod.IsRunning() {
+		t.Error("Expected a bee without live updates to get restarted")
+	}
+
+	if err := UpdateBeeOptions("nosuchbee", BeeOptions{}); !errors.Is(err, ErrUnknownBee) {
+		t.Errorf("Expected ErrUnknownBee, got %v", err)
+	}
+}
@@ -0,0 +1,498 @@
+/*
+ *    Copyright (C) 2026 Christian Muehlhaeuser
+ *
+ *    This program is free software: you can redistribute it and/or modify
+ *    it under the terms of the GNU Affero General Public License as published
+ *    by the Free Software Foundation, either version 3 of the License, or
+ *    (at your option) any later version.
+ *
+ *    This program is distributed in the hope that it will be useful,
+ *    but WITHOUT ANY WARRANTY; without even the implied warranty of
+ *    MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ *    GNU Affero General Public License for more details.
+ *
+ *    You should have received a copy of the GNU Affero General Public License
+ *    along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ *    Authors:
+ *      Christian Muehlhaeuser <muesli@gmail.com>
+ */
+
+// Package bees is Beehive's central module system.
+package bees
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// An OptionType describes a custom type of bee options, referenced by its
+// name in a BeeOptionDescriptor's Type.
+type OptionType struct {
+	// Parse converts a raw option value into its typed representation and
+	// fails if the value is malformed.
+	Parse func(v interface{}) (interface{}, error)
+	// Marshal converts a typed value back into its raw representation.
+	Marshal func(v interface{}) (interface{}, error)
+}
+
+// Color is the value of an option of type "color".
+type Color struct {
+	R, G, B uint8
+}
+
+// String returns the color in its #rrggbb notation.
+func (c Color) String() string {
+	return fmt.Sprintf("#%02x%02x%02x", c.R, c.G, c.B)
+}
+
+var (
+	optionTypes     = make(map[string]OptionType)
+	optionTypeMutex sync.RWMutex
+
+	colorRegexp = regexp.MustCompile(`^#?([0-9a-fA-F]{6}|[0-9a-fA-F]{3})$`)
+)
+
+func init() {
+	RegisterOptionType("duration", OptionType{
+		Parse: func(v interface{}) (interface{}, error) {
+			switch vt := v.(type) {
+			case time.Duration:
+				return vt, nil
+			case string:
+				return time.ParseDuration(vt)
+			}
+			var secs float64
+			if err := convertValue(v, &secs); err != nil {
+				return nil, err
+			}
+			return time.Duration(secs * float64(time.Second)), nil
+		},
+		Marshal: func(v interface{}) (interface{}, error) {
+			d, ok := v.(time.Duration)
+			if !ok {
+				return nil, errors.New("Value is not a duration")
+			}
+			return d.String(), nil
+		},
+	})
+	RegisterOptionType("url", OptionType{
+		Parse: func(v interface{}) (interface{}, error) {
+			s, ok := v.(string)
+			if !ok {
+				return nil, errors.New("URL must be a string")
+			}
+			return url.Parse(s)
+		},
+		Marshal: func(v interface{}) (interface{}, error) {
+			u, ok := v.(*url.URL)
+			if !ok {
+				return nil, errors.New("Value is not a URL")
+			}
+			return u.String(), nil
+		},
+	})
+	RegisterOptionType("color", OptionType{
+		Parse: func(v interface{}) (interface{}, error) {
+			s, ok := v.(string)
+			if !ok || !colorRegexp.MatchString(s) {
+				return nil, fmt.Errorf("Invalid color %v, expected #rrggbb", v)
+			}
+			s = strings.TrimPrefix(s, "#")
+			if len(s) == 3 {
+				s = string([]byte{s[0], s[0], s[1], s[1], s[2], s[2]})
+			}
+			var c Color
+			fmt.Sscanf(s, "%02x%02x%02x", &c.R, &c.G, &c.B)
+			return c, nil
+		},
+		Marshal: func(v interface{}) (interface{}, error) {
+			c, ok := v.(Color)
+			if !ok {
+				return nil, errors.New("Value is not a color")
+			}
+			return c.String(), nil
+		},
+	})
+}
+
+// RegisterOptionType registers a custom option type. Registering a type with
+// an already used name replaces the former type.
+func RegisterOptionType(name string, t OptionType) {
+	optionTypeMutex.Lock()
+	defer optionTypeMutex.Unlock()
+
+	optionTypes[name] = t
+}
+
+// GetOptionType returns the custom option type registered with name.
+func GetOptionType(name string) (OptionType, bool) {
+	optionTypeMutex.RLock()
+	defer optionTypeMutex.RUnlock()
+
+	t, ok := optionTypes[name]
+	return t, ok
+}
+
+// convertValue wraps ConvertValue, turning its panics into errors.
+func convertValue(v interface{}, dst interface{}) (err error) {
+	defer func() {
+		if e := recover(); e != nil {
+			err = fmt.Errorf("%v", e)
+		}
+	}()
+
+	return ConvertValue(v, dst)
+}
+
+// Typed returns the value of an option parsed as the custom type _type.
+func (opts BeeOptions) Typed(name string, _type string) (interface{}, error) {
+	v := opts.Value(name)
+	if v == nil {
+		return nil, errors.New("Option with name " + name + " not found")
+	}
+	t, ok := GetOptionType(_type)
+	if !ok {
+		return nil, errors.New("Unknown option type " + _type)
+	}
+
+	p, err := t.Parse(v)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid value for option %s: %v", name, err)
+	}
+	return p, nil
+}
+
+// GetDuration returns the value of an option of type "duration".
+func (opts BeeOptions) GetDuration(name string) (time.Duration, error) {
+	v, err := opts.Typed(name, "duration")
+	if err != nil {
+		return 0, err
+	}
+	return v.(time.Duration), nil
+}
+
+// GetURL returns the value of an option of type "url".
+func (opts BeeOptions) GetURL(name string) (*url.URL, error) {
+	v, err := opts.Typed(name, "url")
+	if err != nil {
+		return nil, err
+	}
+	return v.(*url.URL), nil
+}
+
+// GetColor returns the value of an option of type "color".
+func (opts BeeOptions) GetColor(name string) (Color, error) {
+	v, err := opts.Typed(name, "color")
+	if err != nil {
+		return Color{}, err
+	}
+	return v.(Color), nil
+}
+
+// PasswordMask replaces the values of password options in API responses
+// and notifications, see MaskOptions.
+const PasswordMask = "********"
+
+// ErrMissingOption is the cause of OptionErrors for mandatory options which
+// weren't set.
+var ErrMissingOption = errors.New("Missing mandatory option")
+
+// OptionError describes a problem with a single option of a bee.
+type OptionError struct {
+	// Option is the name of the option
+	Option string
+	// Err describes the problem
+	Err error
+}
+
+func (e *OptionError) Error() string {
+	if e.Err == ErrMissingOption {
+		return "Missing mandatory option " + e.Option
+	}
+	return fmt.Sprintf("Invalid value for option %s: %v", e.Option, e.Err)
+}
+
+// Unwrap returns the cause of the error.
+func (e *OptionError) Unwrap() error {
+	return e.Err
+}
+
+// ValidationError combines all problems found while validating a bee's
+// options. Problems with single options are reported as *OptionError.
+type ValidationError struct {
+	Errors []error
+}
+
+func (e *ValidationError) Error() string {
+	msgs := []string{}
+	for _, err := range e.Errors {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// ValidateOptions checks the options of a bee of the given class and returns
+// all problems found, see PrepareOptions.
+func ValidateOptions(class string, options BeeOptions) []error {
+	_, errs := PrepareOptions(class, options)
+	return errs
+}
+
+// PrepareOptions checks the options of a bee of the given class against the
+// factory's option descriptors and returns them ready to be used by the bee:
+//   - mandatory options without a default must be present
+//   - omitted options get their default value
+//   - values of the types string, password, int, int64, uint, float64, bool,
+//     []string and time get coerced to that type, e.g. ints arriving as
+//     float64 from JSON
+//   - options whose descriptors use a custom option type get checked for
+//     malformed values
+//   - options with Choices must have one of those values
+//
+// Problems with single options are reported as *OptionError.
+// Options without a descriptor or type are left untouched. Finally the
+// factory validates the options itself.
+func PrepareOptions(class string, options BeeOptions) (BeeOptions, []error) {
+	factory := GetFactory(class)
+	if factory == nil {
+		return nil, []error{errors.New("Invalid class specified")}
+	}
+
+	prepared := append(BeeOptions{}, options...)
+	var errs []error
+	for _, desc := range (*factory).Options() {
+		v := prepared.Value(desc.Name)
+		if v == nil {
+			if desc.Default == nil {
+				if desc.Mandatory {
+					errs = append(errs, &OptionError{Option: desc.Name, Err: ErrMissingOption})
+				}
+				continue
+			}
+			v = desc.Default
+			prepared = withOption(prepared, desc.Name, v)
+		}
+
+		if t, ok := GetOptionType(desc.Type); ok {
+			if _, err := t.Parse(v); err != nil {
+				errs = append(errs, &OptionError{Option: desc.Name, Err: err})
+			}
+			continue
+		}
+		cv, err := coerceOption(desc.Type, v)
+		if err == nil {
+			err = checkChoices(desc, cv)
+		}
+		if err != nil {
+			errs = append(errs, &OptionError{Option: desc.Name, Err: err})
+			continue
+		}
+		prepared = withOption(prepared, desc.Name, cv)
+	}
+	if len(errs) > 0 {
+		return nil, errs
+	}
+
+	if errs := (*factory).ValidateOptions(prepared); len(errs) > 0 {
+		return nil, errs
+	}
+
+	return prepared, nil
+}
+
+// withOption sets the value of an option in opts, adding it if needed.
+func withOption(opts BeeOptions, name string, v interface{}) BeeOptions {
+	for i := range opts {
+		if opts[i].Name == name {
+			opts[i].Value = v
+			return opts
+		}
+	}
+
+	return append(opts, BeeOption{Name: name, Value: v})
+}
+
+// checkChoices makes sure an option's coerced value is one of the choices
+// its descriptor allows.
+func checkChoices(desc BeeOptionDescriptor, v interface{}) error {
+	if len(desc.Choices) == 0 {
+		return nil
+	}
+
+	allowed := make(map[string]bool)
+	names := []string{}
+	for _, c := range desc.Choices {
+		allowed[fmt.Sprint(c)] = true
+		names = append(names, fmt.Sprint(c))
+	}
+
+	values := []string{fmt.Sprint(v)}
+	if l, ok := v.([]string); ok {
+		values = l
+	}
+	for _, s := range values {
+		if !allowed[s] {
+			return fmt.Errorf("Expected one of %s, got %q", strings.Join(names, ", "), s)
+		}
+	}
+	return nil
+}
+
+// coerceOption converts v to the built-in option type _type. Values of other
+// types are returned unchanged.
+func coerceOption(_type string, v interface{}) (interface{}, error) {
+	switch _type {
+	case "string", "password":
+		switch v.(type) {
+		case string, bool, int, int64, float64:
+			var s string
+			err := convertValue(v, &s)
+			return s, err
+		}
+		return nil, fmt.Errorf("Expected a string, got %T", v)
+
+	case "int", "int64", "uint":
+		var i int64
+		switch vt := v.(type) {
+		case float64:
+			if vt != float64(int64(vt)) {
+				return nil, fmt.Errorf("Expected an integer, got %v", vt)
+			}
+			i = int64(vt)
+		case string:
+			var err error
+			if i, err = strconv.ParseInt(vt, 10, 64); err != nil {
+				return nil, fmt.Errorf("Expected an integer, got %q", vt)
+			}
+		case int:
+			i = int64(vt)
+		case int64:
+			i = vt
+		case uint:
+			i = int64(vt)
+		default:
+			return nil, fmt.Errorf("Expected an integer, got %T", v)
+		}
+
+		switch _type {
+		case "int64":
+			return i, nil
+		case "uint":
+			if i < 0 {
+				return nil, fmt.Errorf("Expected a positive integer, got %v", i)
+			}
+			return uint(i), nil
+		}
+		return int(i), nil
+
+	case "float64":
+		if s, ok := v.(string); ok {
+			f, err := strconv.ParseFloat(s, 64)
+			if err != nil {
+				return nil, fmt.Errorf("Expected a number, got %q", s)
+			}
+			return f, nil
+		}
+		var f float64
+		err := convertValue(v, &f)
+		return f, err
+
+	case "bool":
+		return parseBool(v)
+
+	case "[]string":
+		var l []string
+		err := convertValue(v, &l)
+		return l, err
+
+	case "time":
+		switch vt := v.(type) {
+		case string:
+			t, err := time.Parse(time.RFC3339, vt)
+			if err != nil {
+				return nil, fmt.Errorf("Expected an RFC 3339 time, got %q", vt)
+			}
+			return t, nil
+		case float64:
+			return time.Unix(int64(vt), 0), nil
+		}
+		var t time.Time
+		err := convertValue(v, &t)
+		return t, err
+	}
+
+	return v, nil
+}
+
+// validationError combines the errors returned by ValidateOptions into a
+// *ValidationError.
+func validationError(errs []error) error {
+	if len(errs) == 0 {
+		return nil
+	}
+
+	return &ValidationError{Errors: errs}
+}
+
+// secretOptions returns the names of the password options of a class.
+func secretOptions(class string) map[string]bool {
+	secrets := make(map[string]bool)
+	if f := GetFactory(class); f != nil {
+		for _, opt := range (*f).Options() {
+			if opt.Type == "password" {
+				secrets[opt.Name] = true
+			}
+		}
+	}
+
+	return secrets
+}
+
+// MaskOptions returns a copy of the options of a bee of the given class, with
+// the values of password options replaced by PasswordMask.
+func MaskOptions(class string, options BeeOptions) BeeOptions {
+	secrets := secretOptions(class)
+	masked := BeeOptions{}
+	for _, opt := range options {
+		if secrets[opt.Name] && opt.Value != nil {
+			opt.Value = PasswordMask
+		}
+		masked = append(masked, opt)
+	}
+
+	return masked
+}
+
+// UnmaskOptions reverts MaskOptions for new options of a bee: password
+// options whose value is PasswordMask keep the bee's current, unresolved
+// value.
+func UnmaskOptions(bee string, options BeeOptions) BeeOptions {
+	b := GetBee(bee)
+	if b == nil {
+		return options
+	}
+	current := (*b).Options()
+	referenceMutex.RLock()
+	if raw, ok := rawOptions[bee]; ok {
+		current = raw
+	}
+	referenceMutex.RUnlock()
+
+	secrets := secretOptions((*b).Namespace())
+	unmasked := BeeOptions{}
+	for _, opt := range options {
+		if secrets[opt.Name] && opt.Value == PasswordMask {
+			opt.Value = current.Value(opt.Name)
+		}
+		unmasked = append(unmasked, opt)
+	}
+
+	return unmasked
+}
@@ -0,0 +1,161 @@
+package bees
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestOptionTypes(t *testing.T) {
+	opts := BeeOptions{
+		{Name: "interval", Value: "90s"},
+		{Name: "timeout", Value: 5},
+		{Name: "url", Value: "https://example.com/feed"},
+		{Name: "color", Value: "#f80"},
+		{Name: "broken", Value: "soon"},
+	}
+
+	if d, err := opts.GetDuration("interval"); err != nil || d != 90*time.Second {
+		t.Errorf("Expected 90s, got %v (%v)", d, err)
+	}
+	if d, err := opts.GetDuration("timeout"); err != nil || d != 5*time.Second {
+		t.Errorf("Expected 5s, got %v (%v)", d, err)
+	}
+	if u, err := opts.GetURL("url"); err != nil || u.Host != "example.com" {
+		t.Errorf("Expected host example.com, got %v (%v)", u, err)
+	}
+	if c, err := opts.GetColor("color"); err != nil || c.String() != "#ff8800" {
+		t.Errorf("Expected #ff8800, got %v (%v)", c, err)
+	}
+	if _, err := opts.GetDuration("broken"); err == nil {
+		t.Error("Expected malformed duration to fail")
+	}
+	if _, err := opts.GetDuration("missing"); err == nil {
+		t.Error("Expected missing option to fail")
+	}
+}
+
+func init() {
+	RegisterFactory(&testFactory{
+		id: "typedbee",
+		options: []BeeOptionDescriptor{
+			{Name: "server", Type: "url", Mandatory: true},
+			{Name: "port", Type: "int", Default: 6667},
+			{Name: "ssl", Type: "bool"},
+			{Name: "channels", Type: "[]string"},
+			{Name: "ratio", Type: "float64"},
+			{Name: "password", Type: "password"},
+			{Name: "nick", Type: "string"},
+			{Name: "mode", Type: "string", Choices: []interface{}{"fast", "safe"}},
+			{Name: "extra"},
+		},
+	})
+
+	// fragilebee's factory panics when creating bees, like factories not
+	// coping with malformed options do
+	RegisterFactory(&testFactory{
+		id: "fragilebee",
+		newBee: func(name, description string, options BeeOptions) BeeInterface {
+			panic("Unexpected options")
+		},
+	})
+}
+
+func TestPrepareOptions(t *testing.T) {
+	opts, errs := PrepareOptions("typedbee", BeeOptions{
+		{Name: "server", Value: "irc://irc.example.com"},
+		{Name: "ssl", Value: "true"},
+		{Name: "channels", Value: []interface{}{"#beehive", "#go"}},
+		{Name: "ratio", Value: 1},
+		{Name: "nick", Value: 42},
+		{Name: "extra", Value: map[string]interface{}{"a": 1}},
+	})
+	if len(errs) > 0 {
+		t.Fatal(errs)
+	}
+
+	expected := map[string]interface{}{
+		"server":   "irc://irc.example.com",
+		"port":     6667,
+		"ssl":      true,
+		"channels": []string{"#beehive", "#go"},
+		"ratio":    1.0,
+		"nick":     "42",
+		"extra":    map[string]interface{}{"a": 1},
+		"password": nil,
+	}
+	for name, v := range expected {
+		if !reflect.DeepEqual(opts.Value(name), v) {
+			t.Errorf("Expected option %s to be %#v, got %#v", name, v, opts.Value(name))
+		}
+	}
+
+	opts, errs = PrepareOptions("typedbee", BeeOptions{
+		{Name: "server", Value: "irc://irc.example.com"},
+		{Name: "port", Value: float64(7000)},
+	})
+	if len(errs) > 0 || opts.Value("port") != 7000 {
+		t.Errorf("Expected float64 port to become an int, got %#v (%v)", opts.Value("port"), errs)
+	}
+
+	_, errs = PrepareOptions("typedbee", BeeOptions{
+		{Name: "port", Value: 66.5},
+		{Name: "ssl", Value: []string{"yes"}},
+	})
+	if len(errs) != 3 {
+		t.Errorf("Expected errors for server, port and ssl, got %v", errs)
+	}
+}
+
+func TestOptionErrors(t *testing.T) {
+	_, err := NewBeeConfig("typed", "typedbee", "", BeeOptions{
+		{Name: "mode", Value: "turbo"},
+		{Name: "port", Value: "six"},
+	})
+	var ve *ValidationError
+	if !errors.As(err, &ve) || len(ve.Errors) != 3 {
+		t.Fatalf("Expected a ValidationError with three problems, got %v", err)
+	}
+
+	problems := map[string]error{}
+	for _, e := range ve.Errors {
+		var oe *OptionError
+		if !errors.As(e, &oe) {
+			t.Fatalf("Expected an OptionError, got %v", e)
+		}
+		problems[oe.Option] = oe.Err
+	}
+	if problems["server"] != ErrMissingOption || problems["port"] == nil || problems["mode"] == nil {
+		t.Errorf("Unexpected problems %v", problems)
+	}
+	if !strings.Contains(err.Error(), "Missing mandatory option server") {
+		t.Errorf("Expected the problems to be described, got %q", err)
+	}
+
+	opts, errs := PrepareOptions("typedbee", BeeOptions{
+		{Name: "server", Value: "irc://irc.example.com"},
+		{Name: "mode", Value: "safe"},
+	})
+	if len(errs) > 0 || opts.Value("mode") != "safe" {
+		t.Errorf("Expected a valid choice to pass, got %v", errs)
+	}
+
+	if _, err := StartBee(BeeConfig{Name: "fragile", Class: "fragilebee"}); err == nil {
+		t.Error("Expected a panicking factory to be reported as an error")
+	}
+	if GetBee("fragile") != nil {
+		t.Error("Expected the failed bee not to be registered")
+	}
+}
+
+func TestMaskOptions(t *testing.T) {
+	newRecordingBee("maskedbee")
+	defer DeleteBee(GetBee("maskedbee"))
+
+	masked := MaskOptions("typedbee", BeeOptions{{Name: "nick", Value: "bee"}, {Name: "password", Value: "s3cr3t"}})
+	if masked.Value("nick") != "bee" || masked.Value("password") != PasswordMask {
+		t.Errorf("Expected only the password to be masked, got %v", masked)
+	}
+}
@@ -0,0 +1,234 @@
+/*
+ *    Copyright (C) 2026 Christian Muehlhaeuser
+ *
+ *    This program is free software: you can redistribute it and/or modify
+ *    it under the terms of the GNU Affero General Public License as published
+ *    by the Free Software Foundation, either version 3 of the License, or
+ *    (at your option) any later version.
+ *
+ *    This program is distributed in the hope that it will be useful,
+ *    but WITHOUT ANY WARRANTY; without even the implied warranty of
+ *    MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ *    GNU Affero General Public License for more details.
+ *
+ *    You should have received a copy of the GNU Affero General Public License
+ *    along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ *    Authors:
+ *      Christian Muehlhaeuser <muesli@gmail.com>
+ */
+// Package bees is Beehive's central module system.
+package bees
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	durationType = reflect.TypeOf(time.Duration(0))
+	urlType      = reflect.TypeOf(&url.URL{})
+	colorType    = reflect.TypeOf(Color{})
+)
+
+// coerced returns the value of an option converted to the built-in option
+// type _type.
+func (opts BeeOptions) coerced(name string, _type string) (interface{}, error) {
+	v := opts.Value(name)
+	if v == nil {
+		return nil, errors.New("Option with name " + name + " not found")
+	}
+
+	c, err := coerceOption(_type, v)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid value for option %s: %v", name, err)
+	}
+	return c, nil
+}
+
+// GetString returns the value of an option as a string.
+func (opts BeeOptions) GetString(name string) (string, error) {
+	v, err := opts.coerced(name, "string")
+	if err != nil {
+		return "", err
+	}
+	return v.(string), nil
+}
+
+// GetInt returns the value of an option as an int. Numeric strings get
+// parsed.
+func (opts BeeOptions) GetInt(name string) (int, error) {
+	v, err := opts.coerced(name, "int")
+	if err != nil {
+		return 0, err
+	}
+	return v.(int), nil
+}
+
+// GetBool returns the value of an option as a bool. Numbers and strings like
+// "true", "yes" or "1" are accepted.
+func (opts BeeOptions) GetBool(name string) (bool, error) {
+	v, err := opts.coerced(name, "bool")
+	if err != nil {
+		return false, err
+	}
+	return v.(bool), nil
+}
+
+// GetStringSlice returns the value of an option as a string slice. A single
+// string gets split at commas.
+func (opts BeeOptions) GetStringSlice(name string) ([]string, error) {
+	v, err := opts.coerced(name, "[]string")
+	if err != nil {
+		return nil, err
+	}
+	return v.([]string), nil
+}
+
+// BindStruct fills the fields of the struct dst points to with the options
+// named by their `bee:"option_name"` tags. Fields of missing options keep
+// their value, so they can be initialized with defaults.
+func (opts BeeOptions) BindStruct(dst interface{}) error {
+	rv := reflect.ValueOf(dst)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return errors.New("BindStruct requires a pointer to a struct")
+	}
+
+	rv = rv.Elem()
+	rt := rv.Type()
+	for i := 0; i < rt.NumField(); i++ {
+		name := rt.Field(i).Tag.Get("bee")
+		if name == "" || name == "-" {
+			continue
+		}
+		field := rv.Field(i)
+		if !field.CanSet() {
+			return fmt.Errorf("Can't bind option %s to unexported field %s", name, rt.Field(i).Name)
+		}
+		if opts.Value(name) == nil {
+			continue
+		}
+
+		if err := opts.bindField(name, field); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// bindField sets field to the value of the option name.
+func (opts BeeOptions) bindField(name string, field reflect.Value) error {
+	var v interface{}
+	var err error
+	switch field.Type() {
+	case durationType:
+		v, err = opts.GetDuration(name)
+	case urlType:
+		v, err = opts.GetURL(name)
+	case colorType:
+		v, err = opts.GetColor(name)
+	}
+	if err != nil {
+		return err
+	}
+	if v != nil {
+		field.Set(reflect.ValueOf(v))
+		return nil
+	}
+
+	switch field.Kind() {
+	case reflect.String:
+		s, err := opts.GetString(name)
+		if err != nil {
+			return err
+		}
+		field.SetString(s)
+
+	case reflect.Bool:
+		b, err := opts.GetBool(name)
+		if err != nil {
+			return err
+		}
+		field.SetBool(b)
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		v, err := opts.coerced(name, "int64")
+		if err != nil {
+			return err
+		}
+		i := v.(int64)
+		if field.OverflowInt(i) {
+			return fmt.Errorf("Invalid value for option %s: %d is out of range", name, i)
+		}
+		field.SetInt(i)
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		v, err := opts.coerced(name, "uint")
+		if err != nil {
+			return err
+		}
+		u := uint64(v.(uint))
+		if field.OverflowUint(u) {
+			return fmt.Errorf("Invalid value for option %s: %d is out of range", name, u)
+		}
+		field.SetUint(u)
+
+	case reflect.Float32, reflect.Float64:
+		v, err := opts.coerced(name, "float64")
+		if err != nil {
+			return err
+		}
+		f := v.(float64)
+		if field.Kind() == reflect.Float32 && math.Abs(f) > math.MaxFloat32 {
+			return fmt.Errorf("Invalid value for option %s: %v is out of range", name, f)
+		}
+		field.SetFloat(f)
+
+	case reflect.Slice:
+		if field.Type().Elem().Kind() != reflect.String {
+			return fmt.Errorf("Unsupported type %s for option %s", field.Type(), name)
+		}
+		l, err := opts.GetStringSlice(name)
+		if err != nil {
+			return err
+		}
+		field.Set(reflect.ValueOf(l).Convert(field.Type()))
+
+	case reflect.Interface:
+		field.Set(reflect.ValueOf(opts.Value(name)))
+
+	default:
+		return fmt.Errorf("Unsupported type %s for option %s", field.Type(), name)
+	}
+
+	return nil
+}
+
+// parseBool converts v to a bool, accepting numbers and the usual spellings
+// of true and false.
+func parseBool(v interface{}) (bool, error) {
+	s, ok := v.(string)
+	if !ok {
+		var b bool
+		err := convertValue(v, &b)
+		return b, err
+	}
+
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "true", "t", "yes", "y", "on", "1":
+		return true, nil
+	case "false", "f", "no", "n", "off", "0", "":
+		return false, nil
+	}
+	if f, err := strconv.ParseFloat(s, 64); err == nil {
+		return f > 0, nil
+	}
+	return false, fmt.Errorf("Expected a boolean, got %q", s)
+}
@@ -0,0 +1,183 @@
+package bees
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestOptionAccessors(t *testing.T) {
+	tests := []struct {
+		_type string
+		value interface{}
+		exp   interface{}
+		fail  bool
+	}{
+		{"string", "hello", "hello", false},
+		{"string", 42, "42", false},
+		{"string", 1.5, "1.5", false},
+		{"string", true, "true", false},
+		{"string", []interface{}{"a"}, nil, true},
+
+		{"int", 42, 42, false},
+		{"int", float64(42), 42, false},
+		{"int", "42", 42, false},
+		{"int", "-7", -7, false},
+		{"int", 1.5, nil, true},
+		{"int", "forty-two", nil, true},
+		{"int", true, nil, true},
+
+		{"bool", true, true, false},
+		{"bool", "true", true, false},
+		{"bool", "1", true, false},
+		{"bool", 1, true, false},
+		{"bool", float64(1), true, false},
+		{"bool", "Yes", true, false},
+		{"bool", "on", true, false},
+		{"bool", "false", false, false},
+		{"bool", "0", false, false},
+		{"bool", 0, false, false},
+		{"bool", "off", false, false},
+		{"bool", "maybe", nil, true},
+
+		{"duration", "5m", 5 * time.Minute, false},
+		{"duration", 30, 30 * time.Second, false},
+		{"duration", float64(1.5), 1500 * time.Millisecond, false},
+		{"duration", "30", nil, true},
+		{"duration", "soon", nil, true},
+
+		{"[]string", "a,b", []string{"a", "b"}, false},
+		{"[]string", []string{"a"}, []string{"a"}, false},
+		{"[]string", []interface{}{"a", "b"}, []string{"a", "b"}, false},
+		{"[]string", []interface{}{1}, nil, true},
+		{"[]string", 42, nil, true},
+	}
+
+	for _, tt := range tests {
+		opts := BeeOptions{{Name: "opt", Value: tt.value}}
+
+		var v interface{}
+		var err error
+		switch tt._type {
+		case "string":
+			v, err = opts.GetString("opt")
+		case "int":
+			v, err = opts.GetInt("opt")
+		case "bool":
+			v, err = opts.GetBool("opt")
+		case "duration":
+			v, err = opts.GetDuration("opt")
+		case "[]string":
+			v, err = opts.GetStringSlice("opt")
+		}
+
+		if tt.fail {
+			if err == nil {
+				t.Errorf("Expected %s from %#v to fail, got %#v", tt._type, tt.value, v)
+			} else if !strings.Contains(err.Error(), "opt") {
+				t.Errorf("Expected error to name the option, got %v", err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("Unexpected error for %s from %#v: %v", tt._type, tt.value, err)
+			continue
+		}
+		if !reflect.DeepEqual(v, tt.exp) {
+			t.Errorf("Expected %s from %#v to be %#v, got %#v", tt._type, tt.value, tt.exp, v)
+		}
+	}
+
+	opts := BeeOptions{}
+	if _, err := opts.GetString("missing"); err == nil {
+		t.Error("Expected missing option to fail")
+	}
+	if _, err := opts.GetInt("missing"); err == nil {
+		t.Error("Expected missing option to fail")
+	}
+}
+
+type boundConfig struct {
+	Server   string        `bee:"server"`
+	Port     int           `bee:"port"`
+	Small    int8          `bee:"small"`
+	Workers  uint          `bee:"workers"`
+	Ratio    float64       `bee:"ratio"`
+	SSL      bool          `bee:"ssl"`
+	Channels []string      `bee:"channels"`
+	Interval time.Duration `bee:"interval"`
+	Color    Color         `bee:"color"`
+	Extra    interface{}   `bee:"extra"`
+	Nick     string        `bee:"nick"`
+	Ignored  string
+	Skipped  string `bee:"-"`
+}
+
+func TestBindStruct(t *testing.T) {
+	opts := BeeOptions{
+		{Name: "server", Value: "irc.example.com"},
+		{Name: "port", Value: "6697"},
+		{Name: "small", Value: 12},
+		{Name: "workers", Value: float64(4)},
+		{Name: "ratio", Value: "0.5"},
+		{Name: "ssl", Value: "1"},
+		{Name: "channels", Value: []interface{}{"#beehive", "#go"}},
+		{Name: "interval", Value: "5m"},
+		{Name: "color", Value: "#f80"},
+		{Name: "extra", Value: map[string]interface{}{"a": "b"}},
+		{Name: "Ignored", Value: "x"},
+		{Name: "-", Value: "x"},
+	}
+
+	cfg := boundConfig{Nick: "beehive"}
+	if err := opts.BindStruct(&cfg); err != nil {
+		t.Fatal(err)
+	}
+	exp := boundConfig{
+		Server:   "irc.example.com",
+		Port:     6697,
+		Small:    12,
+		Workers:  4,
+		Ratio:    0.5,
+		SSL:      true,
+		Channels: []string{"#beehive", "#go"},
+		Interval: 5 * time.Minute,
+		Color:    Color{R: 0xff, G: 0x88},
+		Extra:    map[string]interface{}{"a": "b"},
+		Nick:     "beehive",
+	}
+	if !reflect.DeepEqual(cfg, exp) {
+		t.Errorf("Expected %+v, got %+v", exp, cfg)
+	}
+
+	failing := []BeeOptions{
+		{{Name: "port", Value: "http"}},
+		{{Name: "small", Value: 1000}},
+		{{Name: "workers", Value: -1}},
+		{{Name: "ssl", Value: "maybe"}},
+		{{Name: "interval", Value: "soon"}},
+	}
+	for _, opts := range failing {
+		err := opts.BindStruct(&boundConfig{})
+		if err == nil || !strings.Contains(err.Error(), opts[0].Name) {
+			t.Errorf("Expected binding %+v to fail naming the option, got %v", opts, err)
+		}
+	}
+
+	if err := opts.BindStruct(cfg); err == nil {
+		t.Error("Expected binding to a non-pointer to fail")
+	}
+	var unexported struct {
+		port int `bee:"port"`
+	}
+	if err := opts.BindStruct(&unexported); err == nil {
+		t.Error("Expected binding to an unexported field to fail")
+	}
+	var unsupported struct {
+		Port chan int `bee:"port"`
+	}
+	if err := opts.BindStruct(&unsupported); err == nil {
+		t.Error("Expected binding to an unsupported type to fail")
+	}
+}
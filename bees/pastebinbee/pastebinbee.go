@@ -22,6 +22,8 @@
 package pastebinbee
 
 import (
+	"context"
+
 	pastebin "github.com/glaxx/go_pastebin"
 	"github.com/muesli/beehive/bees"
 )
@@ -35,7 +37,7 @@ type PastebinBee struct {
 }
 
 // Action triggers the action passed to it.
-func (mod *PastebinBee) Action(action bees.Action) []bees.Placeholder {
+func (mod *PastebinBee) Action(ctx context.Context, action bees.Action) []bees.Placeholder {
 	outs := []bees.Placeholder{}
 
 	switch action.Name {
@@ -64,7 +66,7 @@ func (mod *PastebinBee) Action(action bees.Action) []bees.Placeholder {
 }
 
 // Run executes the Bee's event loop.
-func (mod *PastebinBee) Run(eventChan chan bees.Event) {
+func (mod *PastebinBee) Run(ctx context.Context, eventChan chan bees.Event) {
 	mod.client = pastebin.NewPastebin(mod.apiDevKey)
 }
 
@@ -0,0 +1,71 @@
+/*
+ *    Copyright (C) 2026 Christian Muehlhaeuser
+ *
+ *    This program is free software: you can redistribute it and/or modify
+ *    it under the terms of the GNU Affero General Public License as published
+ *    by the Free Software Foundation, either version 3 of the License, or
+ *    (at your option) any later version.
+ *
+ *    This program is distributed in the hope that it will be useful,
+ *    but WITHOUT ANY WARRANTY; without even the implied warranty of
+ *    MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ *    GNU Affero General Public License for more details.
+ *
+ *    You should have received a copy of the GNU Affero General Public License
+ *    along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ *    Authors:
+ *      Christian Muehlhaeuser <muesli@gmail.com>
+ */
+
+// Package bees is Beehive's central module system.
+package bees
+
+// PauseBee pauses the bee with a specific name: events it emits get dropped,
+// so chains triggered by it don't fire, and actions on it get skipped. The
+// bee keeps running and stays part of the config, which remembers it's
+// paused, until ResumeBee gets called. Fails with ErrUnknownBee if there's no
+// such bee.
+func PauseBee(name string) error {
+	return setBeePaused(name, true)
+}
+
+// ResumeBee resumes a bee paused with PauseBee. Events it emitted while it
+// was paused don't get caught up on.
+func ResumeBee(name string) error {
+	return setBeePaused(name, false)
+}
+
+func setBeePaused(name string, paused bool) error {
+	bee := GetBee(name)
+	if bee == nil {
+		return ErrUnknownBee
+	}
+	name = (*bee).Name()
+
+	instanceMutex.Lock()
+	c, ok := instances[name]
+	if !ok {
+		c = (*bee).Config()
+	}
+	changed := c.Paused != paused
+	c.Paused = paused
+	instances[name] = c
+	instanceMutex.Unlock()
+
+	if !changed {
+		return nil
+	}
+	if paused {
+		logger.Infof("Paused bee %v", name)
+	} else {
+		logger.Infof("Resumed bee %v", name)
+	}
+	return nil
+}
+
+// BeePaused returns whether the bee with a specific name is paused.
+func BeePaused(name string) bool {
+	c, ok := instanceConfig(name)
+	return ok && c.Paused
+}
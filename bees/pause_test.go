@@ -0,0 +1,74 @@
+package bees
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestPauseBee(t *testing.T) {
+	events := make(chan Event)
+	defer useEventChannel(events)()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go runEventLoop(ctx, events)
+
+	newRecordingBee("pausesource")
+	defer DeleteBee(GetBee("pausesource"))
+	sink := newRecordingBee("pausesink")
+	defer DeleteBee(GetBee("pausesink"))
+
+	oldActions := GetActions()
+	defer SetActions(oldActions)
+	SetActions([]Action{{ID: "pause-post", Bee: "pausesink", Name: "post"}})
+	oldChains := chains
+	defer func() { chains = oldChains }()
+	SetChains([]Chain{{
+		Name:    "pause",
+		Event:   &Event{Bee: "pausesource", Name: "ping"},
+		Actions: []string{"pause-post"},
+	}})
+
+	inject := func() InjectResult {
+		wait, done := context.WithTimeout(context.Background(), time.Second)
+		defer done()
+		res, err := InjectEvent(wait, Event{Bee: "pausesource", Name: "ping"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return res
+	}
+
+	if err := PauseBee("pausesource"); err != nil {
+		t.Fatal(err)
+	}
+	if res := inject(); len(res.Chains) != 0 || len(sink.executed()) != 0 {
+		t.Errorf("Expected events of a paused bee to be dropped, got %+v", res)
+	}
+
+	if err := ResumeBee("pausesource"); err != nil {
+		t.Fatal(err)
+	}
+	if res := inject(); len(res.Chains) != 1 || len(sink.executed()) != 1 {
+		t.Errorf("Expected events of a resumed bee to trigger chains, got %+v", res)
+	}
+
+	if err := PauseBee("pausesink"); err != nil {
+		t.Fatal(err)
+	}
+	if res := inject(); len(res.Chains) != 1 || len(sink.executed()) != 1 {
+		t.Errorf("Expected actions on a paused bee to be skipped, got %+v", res)
+	}
+	if status, _ := GetBeeStatus("pausesink"); !status.Paused || !(*GetBee("pausesink")).IsRunning() {
+		t.Errorf("Expected a paused bee to keep running, got %+v", status)
+	}
+	for _, c := range BeeConfigs() {
+		if c.Paused != (c.Name == "pausesink") {
+			t.Errorf("Expected only pausesink to be paused in the config, got %+v", c)
+		}
+	}
+
+	if err := PauseBee("nosuchbee"); err != ErrUnknownBee {
+		t.Errorf("Expected ErrUnknownBee, got %v", err)
+	}
+}
@@ -22,6 +22,7 @@
 package bees
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
 	"net/url"
@@ -41,6 +42,89 @@ type Placeholder struct {
 	Value interface{}
 }
 
+// placeholderJSON is the JSON representation of a Placeholder. ValueType
+// records the Go type of Value, so it survives a round-trip.
+type placeholderJSON struct {
+	Name      string
+	Type      string
+	Value     json.RawMessage
+	ValueType string `json:",omitempty"`
+}
+
+// MarshalJSON encodes a Placeholder, tagging values of type string, int,
+// int64, float64 and bool with their type.
+func (p Placeholder) MarshalJSON() ([]byte, error) {
+	v, err := json.Marshal(p.Value)
+	if err != nil {
+		return nil, err
+	}
+
+	pj := placeholderJSON{
+		Name:  p.Name,
+		Type:  p.Type,
+		Value: v,
+	}
+	switch p.Value.(type) {
+	case string:
+		pj.ValueType = "string"
+	case int:
+		pj.ValueType = "int"
+	case int64:
+		pj.ValueType = "int64"
+	case float64:
+		pj.ValueType = "float64"
+	case bool:
+		pj.ValueType = "bool"
+	}
+
+	return json.Marshal(pj)
+}
+
+// UnmarshalJSON decodes a Placeholder, restoring the type of tagged values.
+// Untagged values get decoded like encoding/json does for interface{}.
+func (p *Placeholder) UnmarshalJSON(b []byte) error {
+	var pj placeholderJSON
+	if err := json.Unmarshal(b, &pj); err != nil {
+		return err
+	}
+	p.Name = pj.Name
+	p.Type = pj.Type
+	p.Value = nil
+	if len(pj.Value) == 0 {
+		return nil
+	}
+
+	var err error
+	switch pj.ValueType {
+	case "string":
+		var v string
+		err = json.Unmarshal(pj.Value, &v)
+		p.Value = v
+	case "int":
+		var v int
+		err = json.Unmarshal(pj.Value, &v)
+		p.Value = v
+	case "int64":
+		var v int64
+		err = json.Unmarshal(pj.Value, &v)
+		p.Value = v
+	case "float64":
+		var v float64
+		err = json.Unmarshal(pj.Value, &v)
+		p.Value = v
+	case "bool":
+		var v bool
+		err = json.Unmarshal(pj.Value, &v)
+		p.Value = v
+	case "":
+		err = json.Unmarshal(pj.Value, &p.Value)
+	default:
+		err = errors.New("Unknown value type " + pj.ValueType + " for placeholder " + pj.Name)
+	}
+
+	return err
+}
+
 // SetValue sets a value in the Placeholder slice.
 func (ph *Placeholders) SetValue(name string, _type string, value interface{}) {
 	if ph.Value(name) == nil {
@@ -81,6 +165,61 @@ func (ph Placeholders) Bind(name string, dst interface{}) error {
 	return ConvertValue(v, dst)
 }
 
+// GetString returns the value of a placeholder converted to a string. It fails
+// if there is no such placeholder, or its value can't be converted.
+func (ph Placeholders) GetString(name string) (string, error) {
+	v, err := ph.typed(name, "string")
+	if err != nil {
+		return "", err
+	}
+	return v.(string), nil
+}
+
+// GetInt returns the value of a placeholder converted to an int, e.g. from a
+// float64 without fraction or a numeric string.
+func (ph Placeholders) GetInt(name string) (int, error) {
+	v, err := ph.typed(name, "int")
+	if err != nil {
+		return 0, err
+	}
+	return v.(int), nil
+}
+
+// GetBool returns the value of a placeholder converted to a bool, e.g. from
+// strings like "true", "yes" or "on".
+func (ph Placeholders) GetBool(name string) (bool, error) {
+	v, err := ph.typed(name, "bool")
+	if err != nil {
+		return false, err
+	}
+	return v.(bool), nil
+}
+
+// GetTime returns the value of a placeholder converted to a time.Time, e.g.
+// from an RFC 3339 string or a Unix timestamp.
+func (ph Placeholders) GetTime(name string) (time.Time, error) {
+	v, err := ph.typed(name, "time")
+	if err != nil {
+		return time.Time{}, err
+	}
+	return v.(time.Time), nil
+}
+
+// typed returns the value of a placeholder coerced to the built-in type
+// _type, see coerceOption.
+func (ph Placeholders) typed(name string, _type string) (interface{}, error) {
+	v := ph.Value(name)
+	if v == nil {
+		return nil, errors.New("Placeholder with name " + name + " not found")
+	}
+
+	cv, err := coerceOption(_type, v)
+	if err != nil {
+		return nil, fmt.Errorf("Placeholder %s: %v", name, err)
+	}
+	return cv, nil
+}
+
 // ConvertValue tries to convert v to dst.
 func ConvertValue(v interface{}, dst interface{}) error {
 	switch d := dst.(type) {
@@ -0,0 +1,116 @@
+package bees
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestPlaceholderJSONRoundTrip(t *testing.T) {
+	ph := Placeholders{
+		{Name: "text", Type: "string", Value: "5"},
+		{Name: "count", Type: "int", Value: 5},
+		{Name: "bignum", Type: "int", Value: int64(1) << 60},
+		{Name: "ratio", Type: "float", Value: 5.0},
+		{Name: "flag", Type: "bool", Value: true},
+		{Name: "list", Type: "[]string", Value: []interface{}{"a", "b"}},
+		{Name: "nothing", Type: "string"},
+	}
+
+	b, err := json.Marshal(ph)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded Placeholders
+	if err := json.Unmarshal(b, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(ph, decoded) {
+		t.Errorf("Expected %#v, got %#v", ph, decoded)
+	}
+}
+
+func TestEventActionJSONRoundTrip(t *testing.T) {
+	ev := Event{Bee: "ircbee", Name: "message", Options: Placeholders{{Name: "count", Type: "int", Value: 3}}}
+	a := Action{Bee: "ircbee", Name: "send", Options: Placeholders{{Name: "flag", Type: "bool", Value: false}}}
+
+	b, err := json.Marshal(ev)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decodedEvent Event
+	if err := json.Unmarshal(b, &decodedEvent); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(ev, decodedEvent) {
+		t.Errorf("Expected %#v, got %#v", ev, decodedEvent)
+	}
+
+	b, err = json.Marshal(a)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decodedAction Action
+	if err := json.Unmarshal(b, &decodedAction); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(a, decodedAction) {
+		t.Errorf("Expected %#v, got %#v", a, decodedAction)
+	}
+}
+
+func TestPlaceholderJSONUntagged(t *testing.T) {
+	var p Placeholder
+	if err := json.Unmarshal([]byte(`{"Name":"count","Type":"int","Value":5}`), &p); err != nil {
+		t.Fatal(err)
+	}
+	if v, ok := p.Value.(float64); !ok || v != 5 {
+		t.Errorf("Expected untagged value to decode as float64, got %#v", p.Value)
+	}
+
+	if err := json.Unmarshal([]byte(`{"Name":"count","Value":5,"ValueType":"complex"}`), &p); err == nil {
+		t.Error("Expected an error for an unknown value type")
+	}
+}
+
+func TestPlaceholderGetters(t *testing.T) {
+	ph := Placeholders{
+		{Name: "text", Type: "string", Value: "hello"},
+		{Name: "count", Type: "int", Value: float64(42)},
+		{Name: "numeric", Type: "string", Value: "7"},
+		{Name: "flag", Type: "string", Value: "yes"},
+		{Name: "timestamp", Type: "string", Value: "2026-10-14T12:00:00Z"},
+		{Name: "unix", Type: "int", Value: int64(1700000000)},
+		{Name: "fraction", Type: "float", Value: 1.5},
+	}
+
+	if s, err := ph.GetString("count"); err != nil || s != "42" {
+		t.Errorf("Expected string 42, got %q (%v)", s, err)
+	}
+	if i, err := ph.GetInt("count"); err != nil || i != 42 {
+		t.Errorf("Expected int 42, got %d (%v)", i, err)
+	}
+	if i, err := ph.GetInt("numeric"); err != nil || i != 7 {
+		t.Errorf("Expected int 7, got %d (%v)", i, err)
+	}
+	if b, err := ph.GetBool("flag"); err != nil || !b {
+		t.Errorf("Expected true, got %v (%v)", b, err)
+	}
+	if tm, err := ph.GetTime("timestamp"); err != nil || tm.Hour() != 12 {
+		t.Errorf("Expected noon, got %v (%v)", tm, err)
+	}
+	if tm, err := ph.GetTime("unix"); err != nil || tm.Unix() != 1700000000 {
+		t.Errorf("Expected the Unix timestamp, got %v (%v)", tm, err)
+	}
+
+	for name, get := range map[string]func() error{
+		"missing placeholder": func() error { _, err := ph.GetString("nosuchplaceholder"); return err },
+		"non-numeric int":     func() error { _, err := ph.GetInt("text"); return err },
+		"fractional int":      func() error { _, err := ph.GetInt("fraction"); return err },
+		"malformed time":      func() error { _, err := ph.GetTime("text"); return err },
+	} {
+		if get() == nil {
+			t.Errorf("Expected an error for a %s", name)
+		}
+	}
+}
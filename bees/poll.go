@@ -0,0 +1,118 @@
+/*
+ *    Copyright (C) 2026 Christian Muehlhaeuser
+ *
+ *    This program is free software: you can redistribute it and/or modify
+ *    it under the terms of the GNU Affero General Public License as published
+ *    by the Free Software Foundation, either version 3 of the License, or
+ *    (at your option) any later version.
+ *
+ *    This program is distributed in the hope that it will be useful,
+ *    but WITHOUT ANY WARRANTY; without even the implied warranty of
+ *    MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ *    GNU Affero General Public License for more details.
+ *
+ *    You should have received a copy of the GNU Affero General Public License
+ *    along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ *    Authors:
+ *      Christian Muehlhaeuser <muesli@gmail.com>
+ */
+
+// Package bees is Beehive's central module system.
+package bees
+
+import (
+	"time"
+
+	"github.com/robfig/cron/v3"
+)
+
+// maxBackoff limits the exponential backoff of failing polls: a failing
+// callback gets retried after at most maxBackoff intervals or occurrences.
+const maxBackoff = 32
+
+// Poll calls f every interval until the bee stops. It's meant to be called
+// from a bee's Run method, replacing hand-written polling loops:
+//
+//	func (mod *ExampleBee) Run(ctx context.Context, eventChan chan bees.Event) {
+//		mod.Poll(5*time.Minute, mod.fetch)
+//	}
+//
+// The first call happens right away. Errors returned by f get logged, and
+// the interval doubles for each consecutive failure, up to maxBackoff times
+// the interval. Poll returns once the bee gets stopped, even while waiting.
+func (bee *Bee) Poll(interval time.Duration, f func() error) {
+	bee.loop(f, func(failures int) time.Duration {
+		return interval * time.Duration(backoff(failures))
+	})
+}
+
+// Schedule calls f according to spec, a cron expression in the standard
+// 5-field format, until the bee stops. It's meant to be called from a bee's
+// Run method, like Poll. Consecutive failures of f back off exponentially by
+// skipping occurrences. An error is returned right away if spec is invalid,
+// otherwise Schedule returns nil once the bee gets stopped.
+func (bee *Bee) Schedule(spec string, f func() error) error {
+	sched, err := cron.ParseStandard(spec)
+	if err != nil {
+		return err
+	}
+
+	next := func(failures int) time.Duration {
+		now := time.Now()
+		t := now
+		for i := 0; i < backoff(failures); i++ {
+			t = sched.Next(t)
+		}
+		return t.Sub(now)
+	}
+	if !bee.wait(next(0)) {
+		return nil
+	}
+	bee.loop(f, next)
+	return nil
+}
+
+// loop calls f until the bee stops, waiting for next(failures) in between,
+// with failures being the number of consecutive errors returned by f.
+func (bee *Bee) loop(f func() error, next func(failures int) time.Duration) {
+	failures := 0
+	for {
+		if err := f(); err != nil {
+			failures++
+			bee.LogErrorf("%v", err)
+		} else {
+			failures = 0
+		}
+
+		if !bee.wait(next(failures)) {
+			return
+		}
+	}
+}
+
+// wait sleeps for d, returning false if the bee got stopped in the meantime.
+func (bee *Bee) wait(d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return true
+	case <-bee.Context().Done():
+		return false
+	case <-bee.sigChan():
+		return false
+	}
+}
+
+// backoff returns the factor to delay the next call by after failures
+// consecutive errors.
+func backoff(failures int) int {
+	b := 1
+	for i := 0; i < failures && b < maxBackoff; i++ {
+		b *= 2
+	}
+
+	return b
+}
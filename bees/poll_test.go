@@ -0,0 +1,85 @@
+package bees
+
+import (
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestPoll(t *testing.T) {
+	bee := NewBee("pollbee", "recordingbee", "", BeeOptions{})
+	bee.Start()
+
+	var calls int32
+	done := make(chan struct{})
+	go func() {
+		bee.Poll(10*time.Millisecond, func() error {
+			atomic.AddInt32(&calls, 1)
+			return nil
+		})
+		close(done)
+	}()
+
+	time.Sleep(55 * time.Millisecond)
+	bee.Stop()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Expected Poll to return once the bee stopped")
+	}
+
+	n := atomic.LoadInt32(&calls)
+	if n < 3 || n > 7 {
+		t.Errorf("Expected about 6 calls, got %d", n)
+	}
+	time.Sleep(30 * time.Millisecond)
+	if atomic.LoadInt32(&calls) != n {
+		t.Error("Expected no calls after the bee stopped")
+	}
+}
+
+func TestPollBackoff(t *testing.T) {
+	bee := NewBee("backoffbee", "recordingbee", "", BeeOptions{})
+	bee.Start()
+
+	var calls int32
+	go bee.Poll(10*time.Millisecond, func() error {
+		atomic.AddInt32(&calls, 1)
+		return errors.New("unavailable")
+	})
+
+	// failing calls happen after 0, 20, 60 and 140ms
+	time.Sleep(100 * time.Millisecond)
+	bee.Stop()
+
+	if n := atomic.LoadInt32(&calls); n != 3 {
+		t.Errorf("Expected 3 calls with backoff, got %d", n)
+	}
+	if backoff(10) != maxBackoff {
+		t.Errorf("Expected backoff to be capped at %d, got %d", maxBackoff, backoff(10))
+	}
+}
+
+func TestSchedule(t *testing.T) {
+	bee := NewBee("schedulebee", "recordingbee", "", BeeOptions{})
+	bee.Start()
+
+	if err := bee.Schedule("not a cron spec", func() error { return nil }); err == nil {
+		t.Error("Expected error for invalid spec")
+	}
+
+	done := make(chan error)
+	go func() {
+		done <- bee.Schedule("* * * * *", func() error { return nil })
+	}()
+	bee.Stop()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Error(err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Expected Schedule to return once the bee stopped")
+	}
+}
@@ -0,0 +1,137 @@
+/*
+ *    Copyright (C) 2026 Christian Muehlhaeuser
+ *
+ *    This program is free software: you can redistribute it and/or modify
+ *    it under the terms of the GNU Affero General Public License as published
+ *    by the Free Software Foundation, either version 3 of the License, or
+ *    (at your option) any later version.
+ *
+ *    This program is distributed in the hope that it will be useful,
+ *    but WITHOUT ANY WARRANTY; without even the implied warranty of
+ *    MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ *    GNU Affero General Public License for more details.
+ *
+ *    You should have received a copy of the GNU Affero General Public License
+ *    along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ *    Authors:
+ *      Christian Muehlhaeuser <muesli@gmail.com>
+ */
+
+// Package bees is Beehive's central module system.
+package bees
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// A Profile is a named set of bees, actions and chains hosted by the hive,
+// isolated from the other profiles, e.g. "personal" and "work". A profile's
+// bees and chains use its name as their scope. Each profile has an event bus
+// of its own: its chains only see events of its own bees, not even those of
+// global bees, and its bees can't be used by the chains of other profiles.
+type Profile struct {
+	Name        string
+	Description string `json:",omitempty"`
+
+	// Config is the URL of a separate configuration holding the profile's
+	// bees, actions and chains.
+	Config string `json:",omitempty"`
+
+	// Token, if set, is required to access the profile through the API.
+	Token string `json:",omitempty"`
+}
+
+var (
+	profiles      = make(map[string]Profile)
+	profilesMutex sync.RWMutex
+)
+
+// SetProfiles replaces the hive's profiles.
+func SetProfiles(ps []Profile) error {
+	m := make(map[string]Profile)
+	for _, p := range ps {
+		if len(p.Name) == 0 {
+			return errors.New("A profile's name can't be empty")
+		}
+		if strings.Contains(p.Name, BeeSeparator) {
+			return fmt.Errorf("Profile name %s must not contain %q", p.Name, BeeSeparator)
+		}
+		if _, ok := m[p.Name]; ok {
+			return fmt.Errorf("Duplicate profile %s", p.Name)
+		}
+		m[p.Name] = p
+	}
+
+	profilesMutex.Lock()
+	defer profilesMutex.Unlock()
+	profiles = m
+	return nil
+}
+
+// GetProfiles returns all profiles, sorted by name.
+func GetProfiles() []Profile {
+	profilesMutex.RLock()
+	defer profilesMutex.RUnlock()
+
+	ps := []Profile{}
+	for _, p := range profiles {
+		ps = append(ps, p)
+	}
+	sort.Slice(ps, func(i, j int) bool { return ps[i].Name < ps[j].Name })
+	return ps
+}
+
+// GetProfile returns the profile called name, or nil if there is none.
+func GetProfile(name string) *Profile {
+	profilesMutex.RLock()
+	defer profilesMutex.RUnlock()
+
+	if p, ok := profiles[name]; ok {
+		return &p
+	}
+	return nil
+}
+
+// profileReachable returns whether chains in chainScope may execute actions
+// of bees in beeScope. Bees of a profile are only reachable by the chains of
+// that profile.
+func profileReachable(beeScope, chainScope string) bool {
+	return beeScope == chainScope || GetProfile(beeScope) == nil
+}
+
+// ProfileActions returns which of as belong to the profile called name, i.e.
+// which act on one of its bees. Bare bee names refer to the profile's bees
+// only if there is no global bee of that name.
+func ProfileActions(name string, as []Action, bs []BeeConfig) []Action {
+	global := make(map[string]struct{})
+	scoped := make(map[string]struct{})
+	for _, b := range bs {
+		switch b.Scope {
+		case "":
+			global[b.Name] = struct{}{}
+		case name:
+			_, n := SplitBeeName(b.Name)
+			scoped[QualifiedBeeName(name, n)] = struct{}{}
+		}
+	}
+
+	r := []Action{}
+	for _, a := range as {
+		ref := a.Bee
+		if !strings.Contains(ref, BeeSeparator) {
+			if _, ok := global[ref]; ok {
+				continue
+			}
+			ref = QualifiedBeeName(name, ref)
+		}
+		if _, ok := scoped[ref]; ok {
+			r = append(r, a)
+		}
+	}
+	return r
+}
@@ -0,0 +1,108 @@
+package bees
+
+import (
+	"context"
+	"testing"
+)
+
+func TestSetProfiles(t *testing.T) {
+	defer SetProfiles(nil)
+
+	for _, ps := range [][]Profile{
+		{{Name: ""}},
+		{{Name: "home/office"}},
+		{{Name: "work"}, {Name: "work"}},
+	} {
+		if err := SetProfiles(ps); err == nil {
+			t.Errorf("Expected an error for profiles %+v", ps)
+		}
+	}
+
+	if err := SetProfiles([]Profile{{Name: "work"}, {Name: "personal"}}); err != nil {
+		t.Fatal(err)
+	}
+	if ps := GetProfiles(); len(ps) != 2 || ps[0].Name != "personal" || ps[1].Name != "work" {
+		t.Errorf("Expected sorted profiles, got %+v", ps)
+	}
+	if GetProfile("work") == nil || GetProfile("unknown") != nil {
+		t.Error("Expected to only find configured profiles")
+	}
+}
+
+func TestProfileIsolation(t *testing.T) {
+	defer SetProfiles(nil)
+	if err := SetProfiles([]Profile{{Name: "work"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	mod, err := NewBeeInstance(BeeConfig{Name: "mailer", Class: "recordingbee", Scope: "work"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	(*mod).Start()
+	defer DeleteBee(mod)
+	bee := (*mod).(*recordingBee)
+
+	oldActions := GetActions()
+	defer SetActions(oldActions)
+	SetActions([]Action{{ID: "mail", Bee: "mailer", Name: "send"}})
+
+	c := Chain{Name: "personal", Event: &Event{Bee: "mailer", Name: "message"}, Actions: []string{"mail"}}
+	if exec := execChain(context.Background(), c, &Event{Bee: "work/mailer", Name: "message"}, nil, false); exec == nil || exec.Err == nil {
+		t.Errorf("Expected a global chain to be denied the profile's bee, got %+v", exec)
+	}
+	if got := bee.executed(); len(got) != 0 {
+		t.Errorf("Expected no executed actions, got %v", got)
+	}
+
+	c.Scope = "work"
+	if exec := execChain(context.Background(), c, &Event{Bee: "work/mailer", Name: "message"}, nil, false); exec == nil || exec.Err != nil {
+		t.Errorf("Expected the profile's chain to succeed, got %+v", exec)
+	}
+	if got := bee.executed(); len(got) != 1 {
+		t.Errorf("Expected one executed action, got %v", got)
+	}
+}
+
+func TestProfileActions(t *testing.T) {
+	bs := []BeeConfig{
+		{Name: "rss"},
+		{Name: "work/mailer", Scope: "work"},
+		{Name: "work/rss", Scope: "work"},
+		{Name: "personal/mailer", Scope: "personal"},
+	}
+	as := []Action{
+		{ID: "bare", Bee: "mailer"},
+		{ID: "qualified", Bee: "work/rss"},
+		{ID: "global", Bee: "rss"},
+		{ID: "other", Bee: "personal/mailer"},
+	}
+
+	got := ProfileActions("work", as, bs)
+	if len(got) != 2 || got[0].ID != "bare" || got[1].ID != "qualified" {
+		t.Errorf("Expected the bare and qualified actions, got %+v", got)
+	}
+}
+
+func TestProfileEventBus(t *testing.T) {
+	defer SetProfiles(nil)
+	if err := SetProfiles([]Profile{{Name: "work"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tt := range []struct {
+		beeScope, chainScope string
+		visible              bool
+	}{
+		{"", "", true},
+		{"work", "work", true},
+		{"", "work", false},
+		{"work", "", false},
+		{"", "tenant", true},
+		{"work", "tenant", false},
+	} {
+		if v := scopeVisible(tt.beeScope, tt.chainScope); v != tt.visible {
+			t.Errorf("Expected events of scope %q to be visible to chains of scope %q: %v, got %v", tt.beeScope, tt.chainScope, tt.visible, v)
+		}
+	}
+}
@@ -25,6 +25,8 @@
 package prometheusbee
 
 import (
+	"context"
+
 	"github.com/muesli/beehive/bees"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
@@ -50,7 +52,7 @@ type PrometheusBee struct {
 }
 
 // Run executes the Bee's event loop.
-func (mod *PrometheusBee) Run(eventChan chan bees.Event) {
+func (mod *PrometheusBee) Run(ctx context.Context, eventChan chan bees.Event) {
 
 	// Counter vector registration
 
@@ -129,7 +131,7 @@ func (mod *PrometheusBee) Run(eventChan chan bees.Event) {
 }
 
 // Action triggers the action passed to it.
-func (mod *PrometheusBee) Action(action bees.Action) []bees.Placeholder {
+func (mod *PrometheusBee) Action(ctx context.Context, action bees.Action) []bees.Placeholder {
 	outs := []bees.Placeholder{}
 	switch action.Name {
 	case "counter_inc":
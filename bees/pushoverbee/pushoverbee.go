@@ -22,6 +22,7 @@
 package pushoverbee
 
 import (
+	"context"
 	"net/http"
 	"net/url"
 
@@ -37,7 +38,7 @@ type PushoverBee struct {
 }
 
 // Run executes the Bee's event loop.
-func (mod *PushoverBee) Run(cin chan bees.Event) {
+func (mod *PushoverBee) Run(ctx context.Context, cin chan bees.Event) {
 	select {
 	case <-mod.SigChan:
 		return
@@ -45,7 +46,7 @@ func (mod *PushoverBee) Run(cin chan bees.Event) {
 }
 
 // Action triggers the action passed to it.
-func (mod *PushoverBee) Action(action bees.Action) []bees.Placeholder {
+func (mod *PushoverBee) Action(ctx context.Context, action bees.Action) []bees.Placeholder {
 	outs := []bees.Placeholder{}
 
 	switch action.Name {
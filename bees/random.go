@@ -0,0 +1,50 @@
+/*
+ *    Copyright (C) 2026 Christian Muehlhaeuser
+ *
+ *    This program is free software: you can redistribute it and/or modify
+ *    it under the terms of the GNU Affero General Public License as published
+ *    by the Free Software Foundation, either version 3 of the License, or
+ *    (at your option) any later version.
+ *
+ *    This program is distributed in the hope that it will be useful,
+ *    but WITHOUT ANY WARRANTY; without even the implied warranty of
+ *    MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ *    GNU Affero General Public License for more details.
+ *
+ *    You should have received a copy of the GNU Affero General Public License
+ *    along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ *    Authors:
+ *      Christian Muehlhaeuser <muesli@gmail.com>
+ */
+
+// Package bees is Beehive's central module system.
+package bees
+
+import (
+	"math/rand"
+	"sync"
+	"time"
+)
+
+var (
+	rng      = rand.New(rand.NewSource(time.Now().UnixNano()))
+	rngMutex sync.Mutex
+)
+
+// SetRandSource sets the source of randomness used by the hive, e.g. to make
+// chain sampling deterministic in tests.
+func SetRandSource(src rand.Source) {
+	rngMutex.Lock()
+	defer rngMutex.Unlock()
+
+	rng = rand.New(src)
+}
+
+// randFloat64 returns a pseudo-random number in [0.0,1.0).
+func randFloat64() float64 {
+	rngMutex.Lock()
+	defer rngMutex.Unlock()
+
+	return rng.Float64()
+}
@@ -0,0 +1,125 @@
+/*
+ *    Copyright (C) 2026 Christian Muehlhaeuser
+ *
+ *    This program is free software: you can redistribute it and/or modify
+ *    it under the terms of the GNU Affero General Public License as published
+ *    by the Free Software Foundation, either version 3 of the License, or
+ *    (at your option) any later version.
+ *
+ *    This program is distributed in the hope that it will be useful,
+ *    but WITHOUT ANY WARRANTY; without even the implied warranty of
+ *    MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ *    GNU Affero General Public License for more details.
+ *
+ *    You should have received a copy of the GNU Affero General Public License
+ *    along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ *    Authors:
+ *      Christian Muehlhaeuser <muesli@gmail.com>
+ */
+
+// Package bees is Beehive's central module system.
+package bees
+
+import (
+	"reflect"
+	"sync"
+	"time"
+)
+
+// chainLimit holds the rate limiting state of a chain. It's tied to the
+// chain's definition, so it gets reset if the chain changes.
+type chainLimit struct {
+	chain     Chain
+	lastFired time.Time
+	seen      map[string]time.Time
+}
+
+var (
+	chainLimits      = make(map[string]*chainLimit)
+	chainLimitsMutex sync.Mutex
+)
+
+// limited returns whether the chain has to skip an event, with m providing
+// the values for its DedupKey. Otherwise the event counts as fired.
+func (c *Chain) limited(m map[string]interface{}) bool {
+	if c.MinInterval <= 0 && len(c.DedupKey) == 0 {
+		return false
+	}
+
+	var key string
+	if len(c.DedupKey) > 0 {
+		var err error
+		key, err = renderTemplate(c.Name+"_dedup", c.DedupKey, m)
+		if err != nil {
+			logger.Errorf("Invalid dedup key for chain %v: %v", c.Name, err)
+			return false
+		}
+	}
+
+	chainLimitsMutex.Lock()
+	defer chainLimitsMutex.Unlock()
+
+	l, ok := chainLimits[c.Name]
+	if !ok {
+		l = &chainLimit{chain: *c, seen: make(map[string]time.Time)}
+		chainLimits[c.Name] = l
+	}
+
+	now := clock.Now()
+	if c.MinInterval > 0 && !l.lastFired.IsZero() && now.Sub(l.lastFired) < c.MinInterval {
+		logger.Debugf("\t\tSkipping chain due to rate limit: %v fired %v ago", c.Name, now.Sub(l.lastFired))
+		statsOfChain(c.Name).rateLimited()
+		return true
+	}
+	if len(c.DedupKey) > 0 {
+		for k, t := range l.seen {
+			if now.Sub(t) >= c.DedupWindow {
+				delete(l.seen, k)
+			}
+		}
+		if _, ok := l.seen[key]; ok {
+			logger.Debugf("\t\tSkipping chain due to duplicate key: %v saw %v within %v", c.Name, key, c.DedupWindow)
+			statsOfChain(c.Name).deduplicated()
+			return true
+		}
+		l.seen[key] = now
+	}
+	l.lastFired = now
+
+	return false
+}
+
+// resetChainLimits drops the rate limiting state of all chains not in cs, or
+// whose definition changed.
+func resetChainLimits(cs []Chain) {
+	chainLimitsMutex.Lock()
+	defer chainLimitsMutex.Unlock()
+
+	current := make(map[string]*chainLimit)
+	for _, c := range cs {
+		if l, ok := chainLimits[c.Name]; ok && reflect.DeepEqual(l.chain, c) {
+			current[c.Name] = l
+		}
+	}
+	chainLimits = current
+}
+
+// resetChainLimit drops the rate limiting state of c, unless its definition
+// is unchanged.
+func resetChainLimit(c Chain) {
+	chainLimitsMutex.Lock()
+	defer chainLimitsMutex.Unlock()
+
+	if l, ok := chainLimits[c.Name]; ok && !reflect.DeepEqual(l.chain, c) {
+		delete(chainLimits, c.Name)
+	}
+}
+
+// deleteChainLimit drops the rate limiting state of a chain.
+func deleteChainLimit(name string) {
+	chainLimitsMutex.Lock()
+	defer chainLimitsMutex.Unlock()
+
+	delete(chainLimits, name)
+}
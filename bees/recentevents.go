@@ -0,0 +1,164 @@
+/*
+ *    Copyright (C) 2026 Christian Muehlhaeuser
+ *
+ *    This program is free software: you can redistribute it and/or modify
+ *    it under the terms of the GNU Affero General Public License as published
+ *    by the Free Software Foundation, either version 3 of the License, or
+ *    (at your option) any later version.
+ *
+ *    This program is distributed in the hope that it will be useful,
+ *    but WITHOUT ANY WARRANTY; without even the implied warranty of
+ *    MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ *    GNU Affero General Public License for more details.
+ *
+ *    You should have received a copy of the GNU Affero General Public License
+ *    along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ *    Authors:
+ *      Christian Muehlhaeuser <muesli@gmail.com>
+ */
+
+// Package bees is Beehive's central module system.
+package bees
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// DefaultRecentEventsSize is the default number of events kept in memory.
+const DefaultRecentEventsSize = 200
+
+// NoMatch is listed as a LoggedEvent's chains when the event didn't match
+// any chain.
+const NoMatch = "no match"
+
+// LoggedEvent describes an event the hive received recently.
+type LoggedEvent struct {
+	Event    Event
+	Received time.Time
+	// Chains contains the names of the chains the event matched, or NoMatch.
+	// It is empty while the event is still being handled.
+	Chains []string
+}
+
+var (
+	recentEvents      []LoggedEvent
+	recentEventsSize  = DefaultRecentEventsSize
+	recentEventsMutex sync.RWMutex
+)
+
+// SetRecentEventsSize sets how many of the most recent events are kept in
+// memory. Zero disables recording events.
+func SetRecentEventsSize(size int) {
+	if size < 0 {
+		size = 0
+	}
+
+	recentEventsMutex.Lock()
+	defer recentEventsMutex.Unlock()
+
+	recentEventsSize = size
+	if len(recentEvents) > size {
+		recentEvents = append([]LoggedEvent{}, recentEvents[len(recentEvents)-size:]...)
+	}
+}
+
+// recordEvent adds an event to the recent events.
+func recordEvent(event Event) {
+	recordBeeEvent(event)
+
+	recentEventsMutex.Lock()
+	defer recentEventsMutex.Unlock()
+
+	if recentEventsSize == 0 {
+		return
+	}
+	recentEvents = append(recentEvents, LoggedEvent{
+		Event:    event,
+		Received: event.received,
+	})
+	if len(recentEvents) > recentEventsSize {
+		recentEvents = recentEvents[len(recentEvents)-recentEventsSize:]
+	}
+}
+
+// setEventChains stores the names of the chains a recent event matched.
+func setEventChains(event *Event, chains []string) {
+	if len(chains) == 0 {
+		chains = []string{NoMatch}
+	}
+	setBeeEventChains(event.Bee, event.ID, chains)
+
+	recentEventsMutex.Lock()
+	defer recentEventsMutex.Unlock()
+
+	for i := len(recentEvents) - 1; i >= 0; i-- {
+		if recentEvents[i].Event.ID == event.ID {
+			recentEvents[i].Chains = chains
+			return
+		}
+	}
+}
+
+// GetRecentEvents returns up to limit of the most recent events, oldest
+// first. A limit of zero or less returns all recorded events.
+func GetRecentEvents(limit int) []LoggedEvent {
+	recentEventsMutex.RLock()
+	defer recentEventsMutex.RUnlock()
+
+	events := recentEvents
+	if limit > 0 && len(events) > limit {
+		events = events[len(events)-limit:]
+	}
+
+	r := make([]LoggedEvent, len(events))
+	for i, ev := range events {
+		ev.Chains = append([]string{}, ev.Chains...)
+		r[i] = ev
+	}
+
+	return r
+}
+
+// ReplayEvent re-injects a recent event into the chains, e.g. to try out
+// changes to a chain's filters without waiting for the event to occur again.
+// The replayed event gets a new ID and is marked as Replayed, which chains
+// can check with the "replayed" placeholder.
+func ReplayEvent(id string) error {
+	var event *Event
+	recentEventsMutex.RLock()
+	for i := len(recentEvents) - 1; i >= 0; i-- {
+		if recentEvents[i].Event.ID == id {
+			ev := recentEvents[i].Event
+			event = &ev
+			break
+		}
+	}
+	recentEventsMutex.RUnlock()
+
+	if event == nil {
+		return errors.New("No recent event with that ID found")
+	}
+
+	replay := replayOf(*event)
+	replay.received = clock.Now()
+
+	execChains(context.Background(), &replay)
+	return nil
+}
+
+// replayOf returns a copy of event to be replayed, caused by the original
+// event and marked as Replayed.
+func replayOf(event Event) Event {
+	replay := deriveEvent(&event, event)
+	replay.ID = UUID()
+	replay.done = nil
+	replay.work = nil
+	replay.injected = nil
+	replay.Replayed = true
+
+	return replay
+}
@@ -21,6 +21,7 @@
 package redisbee
 
 import (
+	"context"
 	"fmt"
 	"time"
 
@@ -45,7 +46,7 @@ type RedisBee struct {
 }
 
 // Run executes the Bee's event loop.
-func (mod *RedisBee) Run(eventChan chan bees.Event) {
+func (mod *RedisBee) Run(ctx context.Context, eventChan chan bees.Event) {
 	if mod.channel == "" {
 		log.Debugf("Redis channel not configured, disabling pubsub")
 		return
@@ -91,7 +92,7 @@ func sendEvent(bee string, channel string, msg string, eventChan chan bees.Event
 }
 
 // Action triggers the action passed to it.
-func (mod *RedisBee) Action(action bees.Action) []bees.Placeholder {
+func (mod *RedisBee) Action(ctx context.Context, action bees.Action) []bees.Placeholder {
 	outs := []bees.Placeholder{}
 
 	switch action.Name {
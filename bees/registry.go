@@ -0,0 +1,200 @@
+/*
+ *    Copyright (C) 2026 Christian Muehlhaeuser
+ *
+ *    This program is free software: you can redistribute it and/or modify
+ *    it under the terms of the GNU Affero General Public License as published
+ *    by the Free Software Foundation, either version 3 of the License, or
+ *    (at your option) any later version.
+ *
+ *    This program is distributed in the hope that it will be useful,
+ *    but WITHOUT ANY WARRANTY; without even the implied warranty of
+ *    MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ *    GNU Affero General Public License for more details.
+ *
+ *    You should have received a copy of the GNU Affero General Public License
+ *    along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ *    Authors:
+ *      Christian Muehlhaeuser <muesli@gmail.com>
+ */
+
+// Package bees is Beehive's central module system.
+package bees
+
+import (
+	"errors"
+	"sync"
+)
+
+// ErrDuplicateBee is returned when registering a bee with a name that is
+// already taken.
+var ErrDuplicateBee = errors.New("A bee with that name already exists")
+
+// ErrUnknownBeeClass is returned when setting up a bee of a class no factory
+// has been registered for. The returned errors wrap it along with the class,
+// use errors.Is to check for it.
+var ErrUnknownBeeClass = errors.New("Unknown bee-class")
+
+// BeeRegistry keeps track of bees and bee factories. It is safe for
+// concurrent use.
+type BeeRegistry struct {
+	mutex     sync.RWMutex
+	bees      map[string]*BeeInterface
+	factories map[string]*BeeFactoryInterface
+}
+
+var (
+	registry = NewBeeRegistry()
+)
+
+// NewBeeRegistry returns a new, empty BeeRegistry.
+func NewBeeRegistry() *BeeRegistry {
+	return &BeeRegistry{
+		bees:      make(map[string]*BeeInterface),
+		factories: make(map[string]*BeeFactoryInterface),
+	}
+}
+
+// RegisterBee adds a bee to the registry. It fails if a bee with the same
+// name is registered already.
+func (r *BeeRegistry) RegisterBee(bee *BeeInterface) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if _, ok := r.bees[(*bee).Name()]; ok {
+		return ErrDuplicateBee
+	}
+	r.bees[(*bee).Name()] = bee
+
+	return nil
+}
+
+// RenameBee renames the registered bee old to name. It fails if old is not
+// registered or name is taken.
+func (r *BeeRegistry) RenameBee(old, name string) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	bee, ok := r.bees[old]
+	if !ok {
+		return ErrUnknownBee
+	}
+	if _, ok := r.bees[name]; ok {
+		return ErrDuplicateBee
+	}
+	(*bee).SetName(name)
+	delete(r.bees, old)
+	r.bees[name] = bee
+
+	return nil
+}
+
+// Bee returns the bee with a specific name, or nil.
+func (r *BeeRegistry) Bee(name string) *BeeInterface {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	return r.bees[name]
+}
+
+// Resolve returns the bee ref refers to, or nil: either the bee with that
+// identifier, or the only bee of any scope with that name, see
+// QualifiedBeeName. Bare names shared by bees of several scopes are
+// ambiguous and resolve to nil.
+func (r *BeeRegistry) Resolve(ref string) *BeeInterface {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	if bee, ok := r.bees[ref]; ok {
+		return bee
+	}
+
+	var found *BeeInterface
+	for id, bee := range r.bees {
+		if matchesBeeRef(ref, id) {
+			if found != nil {
+				return nil
+			}
+			found = bee
+		}
+	}
+	return found
+}
+
+// Bees returns all registered bees.
+func (r *BeeRegistry) Bees() []*BeeInterface {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	bs := []*BeeInterface{}
+	for _, bee := range r.bees {
+		bs = append(bs, bee)
+	}
+
+	return bs
+}
+
+// DeleteBee removes the bee with a specific name from the registry.
+func (r *BeeRegistry) DeleteBee(name string) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	delete(r.bees, name)
+}
+
+// ReplaceBee atomically replaces the registered bee old with bee. It fails if
+// old is not registered anymore, or if bee got renamed to a name that is
+// taken.
+func (r *BeeRegistry) ReplaceBee(old, bee *BeeInterface) bool {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	current, ok := r.bees[(*old).Name()]
+	if !ok || *current != *old {
+		return false
+	}
+	if _, ok := r.bees[(*bee).Name()]; ok && (*bee).Name() != (*old).Name() {
+		return false
+	}
+	delete(r.bees, (*old).Name())
+	r.bees[(*bee).Name()] = bee
+
+	return true
+}
+
+// ClearBees removes all bees from the registry.
+func (r *BeeRegistry) ClearBees() {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.bees = make(map[string]*BeeInterface)
+}
+
+// RegisterFactory adds a bee factory to the registry.
+func (r *BeeRegistry) RegisterFactory(factory *BeeFactoryInterface) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.factories[(*factory).ID()] = factory
+}
+
+// Factory returns the bee factory with a specific ID, or nil.
+func (r *BeeRegistry) Factory(id string) *BeeFactoryInterface {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	return r.factories[id]
+}
+
+// Factories returns all registered bee factories.
+func (r *BeeRegistry) Factories() []*BeeFactoryInterface {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	fs := []*BeeFactoryInterface{}
+	for _, factory := range r.factories {
+		fs = append(fs, factory)
+	}
+
+	return fs
+}
@@ -0,0 +1,133 @@
+/*
+ *    Copyright (C) 2026 Christian Muehlhaeuser
+ *
+ *    This program is free software: you can redistribute it and/or modify
+ *    it under the terms of the GNU Affero General Public License as published
+ *    by the Free Software Foundation, either version 3 of the License, or
+ *    (at your option) any later version.
+ *
+ *    This program is distributed in the hope that it will be useful,
+ *    but WITHOUT ANY WARRANTY; without even the implied warranty of
+ *    MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ *    GNU Affero General Public License for more details.
+ *
+ *    You should have received a copy of the GNU Affero General Public License
+ *    along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ *    Authors:
+ *      Christian Muehlhaeuser <muesli@gmail.com>
+ */
+
+// Package bees is Beehive's central module system.
+package bees
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+)
+
+// ConfigChanges lists the bees ApplyBees changed, by name.
+type ConfigChanges struct {
+	// Started are the bees that weren't running before
+	Started []string
+	// Stopped are the running bees that were removed from the config
+	Stopped []string
+	// Reconfigured are the bees whose options changed; they got reloaded
+	// live or restarted, depending on the bee, see ReloadOptions
+	Reconfigured []string
+	// Restarted are the bees whose class or description changed
+	Restarted []string
+}
+
+// ApplyBees reconciles the running bees with beeList, instead of tearing down
+// all bees like RestartBees: new bees get started, bees missing from beeList
+// get stopped and removed, and bees whose config changed get reconfigured.
+// Bees whose config didn't change keep running undisturbed. Problems with
+// single bees get returned, without affecting the others.
+func ApplyBees(beeList []BeeConfig) (ConfigChanges, []error) {
+	var changes ConfigChanges
+	beeList, errs := uniqueBees(beeList)
+
+	wanted := make(map[string]bool)
+	for _, c := range beeList {
+		wanted[c.Name] = true
+	}
+	for _, bee := range GetBees() {
+		if !wanted[(*bee).Name()] {
+			changes.Stopped = append(changes.Stopped, (*bee).Name())
+			DeleteBee(bee)
+		}
+	}
+
+	var added []BeeConfig
+	for _, c := range beeList {
+		bee := registry.Bee(c.Name)
+		if bee == nil {
+			added = append(added, c)
+			continue
+		}
+
+		if (*bee).Namespace() != c.Class || (*bee).Description() != c.Description {
+			// a bee of another class can't make sense of the old state
+			removeBee(bee, (*bee).Namespace() != c.Class)
+			if _, err := StartBee(c); err != nil {
+				errs = append(errs, fmt.Errorf("Bee %s: %v", c.Name, err))
+				continue
+			}
+			changes.Restarted = append(changes.Restarted, c.Name)
+			continue
+		}
+
+		changed, err := optionsChanged(bee, c.Options)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("Bee %s: %v", c.Name, err))
+			continue
+		}
+		setInstanceConfig(c)
+		if !changed {
+			continue
+		}
+		if err := ReloadOptions(bee, c.Options); err != nil {
+			errs = append(errs, fmt.Errorf("Bee %s: %v", c.Name, err))
+			continue
+		}
+		changes.Reconfigured = append(changes.Reconfigured, c.Name)
+	}
+
+	startErrs := startBees(added)
+	errs = append(errs, startErrs...)
+	for _, c := range added {
+		if registry.Bee(c.Name) != nil {
+			changes.Started = append(changes.Started, c.Name)
+		}
+	}
+
+	sort.Strings(changes.Stopped)
+	return changes, errs
+}
+
+// optionsChanged returns whether options, once resolved and prepared, differ
+// from a running bee's current options.
+func optionsChanged(bee *BeeInterface, options BeeOptions) (bool, error) {
+	resolved, err := ResolveOptions(options)
+	if err != nil {
+		return false, err
+	}
+	prepared, errs := PrepareOptions((*bee).Namespace(), resolved)
+	if len(errs) > 0 {
+		return false, validationError(errs)
+	}
+
+	return !reflect.DeepEqual(optionValues(prepared), optionValues((*bee).Options())), nil
+}
+
+// optionValues maps option names to their values, so options can be compared
+// regardless of their order.
+func optionValues(options BeeOptions) map[string]interface{} {
+	m := make(map[string]interface{}, len(options))
+	for _, opt := range options {
+		m[opt.Name] = opt.Value
+	}
+	return m
+}
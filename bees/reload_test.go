@@ -0,0 +1,50 @@
+package bees
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestApplyBees(t *testing.T) {
+	defer StopBees()
+
+	server := BeeOption{Name: "server", Value: "irc://irc.example.com"}
+	errs := StartBees([]BeeConfig{
+		{Name: "apply-same", Class: "typedbee", Options: BeeOptions{server, {Name: "port", Value: 6667}}},
+		{Name: "apply-changed", Class: "typedbee", Options: BeeOptions{server}},
+		{Name: "apply-removed", Class: "typedbee", Options: BeeOptions{server}},
+	})
+	if len(errs) > 0 {
+		t.Fatal(errs)
+	}
+	same := GetBee("apply-same")
+
+	changes, errs := ApplyBees([]BeeConfig{
+		// JSON configs carry numbers as float64
+		{Name: "apply-same", Class: "typedbee", Options: BeeOptions{{Name: "port", Value: float64(6667)}, server}},
+		{Name: "apply-changed", Class: "typedbee", Options: BeeOptions{server, {Name: "nick", Value: "beehive"}}},
+		{Name: "apply-added", Class: "typedbee", Options: BeeOptions{server}},
+		{Name: "apply-broken", Class: "typedbee"},
+	})
+	if len(errs) != 1 {
+		t.Errorf("Expected an error for the broken bee, got %v", errs)
+	}
+
+	exp := ConfigChanges{
+		Started:      []string{"apply-added"},
+		Stopped:      []string{"apply-removed"},
+		Reconfigured: []string{"apply-changed"},
+	}
+	if !reflect.DeepEqual(changes, exp) {
+		t.Errorf("Expected changes %+v, got %+v", exp, changes)
+	}
+	if GetBee("apply-same") != same {
+		t.Error("Expected unchanged bee to keep running")
+	}
+	if GetBee("apply-removed") != nil {
+		t.Error("Expected removed bee to be stopped")
+	}
+	if v := (*GetBee("apply-changed")).Options().Value("nick"); v != "beehive" {
+		t.Errorf("Expected changed option to be applied, got %v", v)
+	}
+}
@@ -0,0 +1,368 @@
+/*
+ *    Copyright (C) 2026 Christian Muehlhaeuser
+ *
+ *    This program is free software: you can redistribute it and/or modify
+ *    it under the terms of the GNU Affero General Public License as published
+ *    by the Free Software Foundation, either version 3 of the License, or
+ *    (at your option) any later version.
+ *
+ *    This program is distributed in the hope that it will be useful,
+ *    but WITHOUT ANY WARRANTY; without even the implied warranty of
+ *    MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ *    GNU Affero General Public License for more details.
+ *
+ *    You should have received a copy of the GNU Affero General Public License
+ *    along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ *    Authors:
+ *      Christian Muehlhaeuser <muesli@gmail.com>
+ */
+
+// Package bees is Beehive's central module system.
+package bees
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/rpc"
+	"net/rpc/jsonrpc"
+	"sync"
+	"time"
+)
+
+// remoteRetryInterval is the time remote bees wait between attempts to
+// connect to their remote process.
+const remoteRetryInterval = 5 * time.Second
+
+// ErrNotRemoteBee is returned when a remote process emits an event for a
+// bee which isn't provided by a remote process.
+var ErrNotRemoteBee = errors.New("Not a remote bee")
+
+// RemoteFactoryDescriptor describes a bee factory provided by a remote
+// process, see ServeRemoteBees and ServeRemoteFactory.
+type RemoteFactoryDescriptor struct {
+	ID          string
+	Name        string
+	Description string
+	Image       string
+	LogoColor   string
+	Options     []BeeOptionDescriptor
+	Events      []EventDescriptor
+	Actions     []ActionDescriptor
+
+	// Network and Address the remote process serves its bees on, e.g. "unix"
+	// and "/run/beehive/mybee.sock"
+	Network string
+	Address string
+}
+
+// RemoteBeeArgs are the arguments for starting or reconfiguring a bee in a
+// remote process.
+type RemoteBeeArgs struct {
+	Name        string
+	Description string
+	Options     BeeOptions
+}
+
+// RemoteActionArgs are the arguments for executing an action of a bee in a
+// remote process.
+type RemoteActionArgs struct {
+	Bee    string
+	Action Action
+}
+
+// RemoteHive is the RPC service remote processes talk to, see
+// ServeRemoteBees.
+type RemoteHive struct{}
+
+// Register registers the bee factory provided by a remote process. A remote
+// process registering again, e.g. after it got restarted, replaces its
+// earlier registration, and its running bees reconnect to it.
+func (h *RemoteHive) Register(desc RemoteFactoryDescriptor, reply *bool) error {
+	if f := GetFactory(desc.ID); f != nil {
+		rf, ok := (*f).(*remoteFactory)
+		if !ok {
+			return errors.New("A factory with ID " + desc.ID + " is already registered")
+		}
+		rf.setDescriptor(desc)
+		forgetFactoryDescriptors(desc.ID)
+		for _, bee := range GetBeesByNamespace(desc.ID) {
+			setInstanceDescriptors(bee)
+			if rb, ok := (*bee).(*remoteBee); ok && rb.IsRunning() {
+				go rb.reconnect()
+			}
+		}
+		logger.Infof("Updated remote bee factory %s at %s:%s", desc.ID, desc.Network, desc.Address)
+		*reply = true
+		return nil
+	}
+
+	RegisterFactory(&remoteFactory{desc: desc})
+	logger.Infof("Registered remote bee factory %s at %s:%s", desc.ID, desc.Network, desc.Address)
+	*reply = true
+	return nil
+}
+
+// Emit feeds an event of a remote bee into the hive.
+func (h *RemoteHive) Emit(event Event, reply *bool) error {
+	bee := GetBee(event.Bee)
+	if bee == nil {
+		return ErrUnknownBee
+	}
+	if _, ok := (*bee).(*remoteBee); !ok {
+		return ErrNotRemoteBee
+	}
+
+	if err := EmitEvent(*bee, event.Name, event.Options...); err != nil {
+		return err
+	}
+	*reply = true
+	return nil
+}
+
+// ServeRemoteBees accepts connections of remote processes on l, which can
+// then register their bee factories, see ServeRemoteFactory. It blocks until
+// l gets closed.
+func ServeRemoteBees(l net.Listener) error {
+	srv := rpc.NewServer()
+	if err := srv.RegisterName("Hive", &RemoteHive{}); err != nil {
+		return err
+	}
+
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			return err
+		}
+		go srv.ServeCodec(jsonrpc.NewServerCodec(conn))
+	}
+}
+
+// remoteFactory is the stand-in for a factory provided by a remote process.
+type remoteFactory struct {
+	BeeFactory
+
+	mutex sync.RWMutex
+	desc  RemoteFactoryDescriptor
+}
+
+func (factory *remoteFactory) descriptor() RemoteFactoryDescriptor {
+	factory.mutex.RLock()
+	defer factory.mutex.RUnlock()
+	return factory.desc
+}
+
+func (factory *remoteFactory) setDescriptor(desc RemoteFactoryDescriptor) {
+	factory.mutex.Lock()
+	defer factory.mutex.Unlock()
+	factory.desc = desc
+}
+
+// New returns a new remote bee instance configured with the supplied options.
+func (factory *remoteFactory) New(name, description string, options BeeOptions) BeeInterface {
+	bee := remoteBee{
+		Bee:     NewBee(name, factory.ID(), description, options),
+		factory: factory,
+	}
+	bee.ReloadOptions(options)
+
+	return &bee
+}
+
+// ID returns the ID of the remote factory.
+func (factory *remoteFactory) ID() string {
+	return factory.descriptor().ID
+}
+
+// Name returns the name of the remote factory.
+func (factory *remoteFactory) Name() string {
+	return factory.descriptor().Name
+}
+
+// Description returns the description of the remote factory.
+func (factory *remoteFactory) Description() string {
+	return factory.descriptor().Description
+}
+
+// Image returns the filename of an image for the remote factory.
+func (factory *remoteFactory) Image() string {
+	return factory.descriptor().Image
+}
+
+// LogoColor returns the preferred logo background color of the remote factory.
+func (factory *remoteFactory) LogoColor() string {
+	if c := factory.descriptor().LogoColor; c != "" {
+		return c
+	}
+	return factory.BeeFactory.LogoColor()
+}
+
+// Options returns the options available to configure remote bees.
+func (factory *remoteFactory) Options() []BeeOptionDescriptor {
+	return factory.descriptor().Options
+}
+
+// Events describes the available events provided by remote bees.
+func (factory *remoteFactory) Events() []EventDescriptor {
+	return factory.descriptor().Events
+}
+
+// Actions describes the available actions provided by remote bees.
+func (factory *remoteFactory) Actions() []ActionDescriptor {
+	return factory.descriptor().Actions
+}
+
+// remoteBee is the stand-in for a bee running in a remote process. It starts
+// the bee in the remote process when it gets started, and forwards actions
+// to it.
+type remoteBee struct {
+	Bee
+
+	factory *remoteFactory
+
+	mutex  sync.Mutex
+	client *rpc.Client
+}
+
+// connect connects to the remote process and starts the bee there, unless
+// it's connected already.
+func (mod *remoteBee) connect() (*rpc.Client, error) {
+	mod.mutex.Lock()
+	defer mod.mutex.Unlock()
+
+	if mod.client != nil {
+		return mod.client, nil
+	}
+
+	desc := mod.factory.descriptor()
+	c, err := jsonrpc.Dial(desc.Network, desc.Address)
+	if err != nil {
+		return nil, err
+	}
+
+	var ok bool
+	args := RemoteBeeArgs{Name: mod.Name(), Description: mod.Description(), Options: mod.Options()}
+	if err := c.Call("Bee.Start", args, &ok); err != nil {
+		c.Close()
+		return nil, err
+	}
+	mod.client = c
+	return c, nil
+}
+
+// disconnect stops the bee in the remote process and closes the connection.
+func (mod *remoteBee) disconnect() {
+	mod.mutex.Lock()
+	defer mod.mutex.Unlock()
+
+	if mod.client == nil {
+		return
+	}
+	var ok bool
+	if err := mod.client.Call("Bee.Stop", RemoteBeeArgs{Name: mod.Name()}, &ok); err != nil {
+		mod.LogErrorf("Can't stop remote bee: %v", err)
+	}
+	mod.client.Close()
+	mod.client = nil
+}
+
+// reconnect drops the current connection, e.g. after the remote process got
+// restarted, and connects again.
+func (mod *remoteBee) reconnect() {
+	mod.mutex.Lock()
+	if mod.client != nil {
+		mod.client.Close()
+		mod.client = nil
+	}
+	mod.mutex.Unlock()
+
+	if _, err := mod.connect(); err != nil {
+		mod.LogErrorf("Can't connect to remote process: %v", err)
+	}
+}
+
+// call calls a method of the remote process. Calls on a connection which
+// has been shut down get retried once on a new connection.
+func (mod *remoteBee) call(method string, args interface{}, reply interface{}) error {
+	c, err := mod.connect()
+	if err != nil {
+		return err
+	}
+
+	err = c.Call(method, args, reply)
+	if err != rpc.ErrShutdown {
+		return err
+	}
+
+	mod.mutex.Lock()
+	if mod.client == c {
+		mod.client = nil
+	}
+	mod.mutex.Unlock()
+	if c, err = mod.connect(); err != nil {
+		return err
+	}
+	return c.Call(method, args, reply)
+}
+
+// Run connects to the remote process and keeps the bee running there, until
+// the bee gets stopped.
+func (mod *remoteBee) Run(ctx context.Context, eventChan chan Event) {
+	for {
+		_, err := mod.connect()
+		if err == nil {
+			break
+		}
+		mod.LogErrorf("Can't connect to remote process: %v", err)
+
+		select {
+		case <-mod.sigChan():
+			return
+		case <-ctx.Done():
+			return
+		case <-time.After(remoteRetryInterval):
+		}
+	}
+
+	select {
+	case <-mod.sigChan():
+	case <-ctx.Done():
+	}
+	mod.disconnect()
+}
+
+// ActionE executes an action in the remote process.
+func (mod *remoteBee) ActionE(ctx context.Context, action Action) ([]Placeholder, error) {
+	var res []Placeholder
+	err := mod.call("Bee.Action", RemoteActionArgs{Bee: mod.Name(), Action: action}, &res)
+	return res, err
+}
+
+// Action executes an action in the remote process, logging failures.
+func (mod *remoteBee) Action(ctx context.Context, action Action) []Placeholder {
+	res, err := mod.ActionE(ctx, action)
+	if err != nil {
+		mod.LogErrorf("Remote action %s failed: %v", action.Name, err)
+	}
+	return res
+}
+
+// ReloadOptions updates the options of the bee, also in the remote process
+// if the bee is connected.
+func (mod *remoteBee) ReloadOptions(options BeeOptions) {
+	mod.SetOptions(options)
+
+	mod.mutex.Lock()
+	c := mod.client
+	mod.mutex.Unlock()
+	if c == nil {
+		return
+	}
+
+	var ok bool
+	args := RemoteBeeArgs{Name: mod.Name(), Description: mod.Description(), Options: options}
+	if err := c.Call("Bee.Reload", args, &ok); err != nil {
+		mod.LogErrorf("Can't reconfigure remote bee: %v", err)
+	}
+}
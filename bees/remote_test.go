@@ -0,0 +1,108 @@
+package bees
+
+import (
+	"context"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// remoteTestFactory gets served from a remote process, so it doesn't get
+// registered with the hive directly.
+var remoteTestFactory = &testFactory{
+	id:          "remotetestbee",
+	name:        "Remote Test",
+	description: "A bee running in a remote process",
+	events:      []EventDescriptor{{Name: "hello", Options: []PlaceholderDescriptor{{Name: "text", Type: "string"}}}},
+	actions:     []ActionDescriptor{{Name: "echo", Options: []PlaceholderDescriptor{{Name: "text", Type: "string"}}}},
+	newBee: func(name, description string, options BeeOptions) BeeInterface {
+		return &remoteTestBee{Bee: NewBee(name, "remotetestbee", description, options)}
+	},
+}
+
+type remoteTestBee struct {
+	Bee
+}
+
+func (bee *remoteTestBee) Run(ctx context.Context, eventChan chan Event) {
+	eventChan <- Event{Name: "hello", Options: Placeholders{{Name: "text", Type: "string", Value: "hi from " + bee.Name()}}}
+	<-ctx.Done()
+}
+
+func (bee *remoteTestBee) ReloadOptions(options BeeOptions) {
+	bee.SetOptions(options)
+}
+
+func (bee *remoteTestBee) ActionE(ctx context.Context, action Action) ([]Placeholder, error) {
+	return []Placeholder{{Name: "text", Type: "string", Value: action.Options.Value("text")}}, nil
+}
+
+func TestRemoteBees(t *testing.T) {
+	dir, err := ioutil.TempDir("", "beehive-remote")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	hive, err := net.Listen("unix", filepath.Join(dir, "hive.sock"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer hive.Close()
+	go ServeRemoteBees(hive)
+
+	remote, err := net.Listen("unix", filepath.Join(dir, "remote.sock"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer remote.Close()
+	go ServeRemoteFactory(remoteTestFactory, "unix", hive.Addr().String(), remote)
+
+	deadline := time.Now().Add(5 * time.Second)
+	for GetFactory("remotetestbee") == nil {
+		if time.Now().After(deadline) {
+			t.Fatal("Remote factory didn't get registered")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	defer func() {
+		registry.mutex.Lock()
+		delete(registry.factories, "remotetestbee")
+		registry.mutex.Unlock()
+	}()
+
+	events := make(chan Event, 1)
+	h := OnEvent(func(ev Event) {
+		if ev.Bee == "remote1" {
+			select {
+			case events <- ev:
+			default:
+			}
+		}
+	})
+	defer h.Remove()
+
+	StartBees([]BeeConfig{{Name: "remote1", Class: "remotetestbee"}})
+	defer StopBees()
+
+	select {
+	case ev := <-events:
+		if ev.Name != "hello" || ev.Options.Value("text") != "hi from remote1" {
+			t.Errorf("Unexpected event from remote bee: %+v", ev)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Didn't receive the event of the remote bee")
+	}
+
+	bee := GetBee("remote1")
+	res, err := (*bee).(FallibleBee).ActionE(context.Background(), Action{Name: "echo", Options: Placeholders{{Name: "text", Type: "string", Value: "ping"}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res) != 1 || res[0].Value != "ping" {
+		t.Errorf("Expected the remote action's results, got %+v", res)
+	}
+}
@@ -0,0 +1,273 @@
+/*
+ *    Copyright (C) 2026 Christian Muehlhaeuser
+ *
+ *    This program is free software: you can redistribute it and/or modify
+ *    it under the terms of the GNU Affero General Public License as published
+ *    by the Free Software Foundation, either version 3 of the License, or
+ *    (at your option) any later version.
+ *
+ *    This program is distributed in the hope that it will be useful,
+ *    but WITHOUT ANY WARRANTY; without even the implied warranty of
+ *    MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ *    GNU Affero General Public License for more details.
+ *
+ *    You should have received a copy of the GNU Affero General Public License
+ *    along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ *    Authors:
+ *      Christian Muehlhaeuser <muesli@gmail.com>
+ */
+
+// Package bees is Beehive's central module system.
+package bees
+
+import (
+	"fmt"
+	"net"
+	"net/rpc"
+	"net/rpc/jsonrpc"
+	"sync"
+	"time"
+)
+
+// remoteEventBuffer is the number of events of a bee a remote process
+// buffers while the hive is unreachable.
+const remoteEventBuffer = 64
+
+// RemoteFactoryService is the RPC service a remote process provides to the
+// hive, see ServeRemoteFactory.
+type RemoteFactoryService struct {
+	factory BeeFactoryInterface
+
+	hiveNetwork string
+	hiveAddress string
+	desc        RemoteFactoryDescriptor
+
+	mutex sync.Mutex
+	hive  *rpc.Client
+	bees  map[string]BeeInterface
+}
+
+// ServeRemoteFactory provides the bees of factory to the hive listening on
+// hiveNetwork and hiveAddress, see ServeRemoteBees. It registers the factory
+// with the hive, then serves the hive's requests to start and stop bees and
+// execute their actions on l, until l gets closed.
+//
+// Bees running in a remote process have to send their events to the
+// channel passed to their Run method; events emitted with EmitEvent don't
+// reach the hive.
+func ServeRemoteFactory(factory BeeFactoryInterface, hiveNetwork, hiveAddress string, l net.Listener) error {
+	svc := &RemoteFactoryService{
+		factory:     factory,
+		hiveNetwork: hiveNetwork,
+		hiveAddress: hiveAddress,
+		bees:        make(map[string]BeeInterface),
+		desc: RemoteFactoryDescriptor{
+			ID:          factory.ID(),
+			Name:        factory.Name(),
+			Description: factory.Description(),
+			Image:       factory.Image(),
+			LogoColor:   factory.LogoColor(),
+			Options:     factory.Options(),
+			Events:      factory.Events(),
+			Actions:     factory.Actions(),
+			Network:     l.Addr().Network(),
+			Address:     l.Addr().String(),
+		},
+	}
+
+	srv := rpc.NewServer()
+	if err := srv.RegisterName("Bee", svc); err != nil {
+		return err
+	}
+	if _, err := svc.hiveClient(); err != nil {
+		return err
+	}
+
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			svc.stopAll()
+			return err
+		}
+		go srv.ServeCodec(jsonrpc.NewServerCodec(conn))
+	}
+}
+
+// hiveClient returns the connection to the hive, connecting and registering
+// the factory if necessary.
+func (svc *RemoteFactoryService) hiveClient() (*rpc.Client, error) {
+	svc.mutex.Lock()
+	defer svc.mutex.Unlock()
+
+	if svc.hive != nil {
+		return svc.hive, nil
+	}
+
+	c, err := jsonrpc.Dial(svc.hiveNetwork, svc.hiveAddress)
+	if err != nil {
+		return nil, err
+	}
+	var ok bool
+	if err := c.Call("Hive.Register", svc.desc, &ok); err != nil {
+		c.Close()
+		return nil, err
+	}
+	svc.hive = c
+	return c, nil
+}
+
+// emit forwards an event to the hive. Events get retried once on a new
+// connection, if the hive got restarted.
+func (svc *RemoteFactoryService) emit(event Event) error {
+	c, err := svc.hiveClient()
+	if err != nil {
+		return err
+	}
+
+	var ok bool
+	err = c.Call("Hive.Emit", event, &ok)
+	if err != rpc.ErrShutdown {
+		return err
+	}
+
+	svc.mutex.Lock()
+	if svc.hive == c {
+		svc.hive = nil
+	}
+	svc.mutex.Unlock()
+	if c, err = svc.hiveClient(); err != nil {
+		return err
+	}
+	return c.Call("Hive.Emit", event, &ok)
+}
+
+// Start creates and starts a bee. A bee of the same name which is still
+// running, e.g. because the hive reconnected, gets replaced.
+func (svc *RemoteFactoryService) Start(args RemoteBeeArgs, reply *bool) error {
+	svc.Stop(RemoteBeeArgs{Name: args.Name}, reply)
+
+	bee := svc.factory.New(args.Name, args.Description, args.Options)
+	svc.mutex.Lock()
+	svc.bees[args.Name] = bee
+	svc.mutex.Unlock()
+
+	eventChan := make(chan Event, remoteEventBuffer)
+	bee.Start()
+	go svc.forward(bee, eventChan)
+	go svc.run(bee, eventChan)
+
+	*reply = true
+	return nil
+}
+
+// run executes the bee's event loop, restarting it if it panics.
+func (svc *RemoteFactoryService) run(bee BeeInterface, eventChan chan Event) {
+	for {
+		crashed := func() (crashed bool) {
+			defer func() {
+				if e := recover(); e != nil {
+					logBeef(bee, LogError, "Fatal bee event: %v", e)
+					crashed = true
+				}
+			}()
+			bee.Run(bee.Context(), eventChan)
+			return false
+		}()
+		if !crashed {
+			return
+		}
+
+		select {
+		case <-bee.Context().Done():
+			return
+		case <-time.After(time.Second):
+		}
+	}
+}
+
+// forward sends the events of a bee to the hive, until the bee gets stopped.
+func (svc *RemoteFactoryService) forward(bee BeeInterface, eventChan chan Event) {
+	for {
+		select {
+		case <-bee.Context().Done():
+			return
+		case event := <-eventChan:
+			event.Bee = bee.Name()
+			if err := svc.emit(event); err != nil {
+				logBeef(bee, LogError, "Can't forward event %s to the hive: %v", event.Name, err)
+			}
+		}
+	}
+}
+
+// Stop stops and removes a bee.
+func (svc *RemoteFactoryService) Stop(args RemoteBeeArgs, reply *bool) error {
+	svc.mutex.Lock()
+	bee, ok := svc.bees[args.Name]
+	delete(svc.bees, args.Name)
+	svc.mutex.Unlock()
+
+	if ok {
+		bee.Stop()
+	}
+	*reply = ok
+	return nil
+}
+
+// Reload updates the options of a running bee.
+func (svc *RemoteFactoryService) Reload(args RemoteBeeArgs, reply *bool) error {
+	bee, err := svc.bee(args.Name)
+	if err != nil {
+		return err
+	}
+
+	bee.ReloadOptions(args.Options)
+	*reply = true
+	return nil
+}
+
+// Action executes an action of a running bee. A panicking action fails with
+// an error, instead of crashing the remote process.
+func (svc *RemoteFactoryService) Action(args RemoteActionArgs, reply *[]Placeholder) (err error) {
+	bee, err := svc.bee(args.Bee)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if e := recover(); e != nil {
+			err = fmt.Errorf("Action %s panicked: %v", args.Action.Name, e)
+		}
+	}()
+
+	if fb, ok := bee.(FallibleBee); ok {
+		res, err := fb.ActionE(bee.Context(), args.Action)
+		*reply = res
+		return err
+	}
+	*reply = bee.Action(bee.Context(), args.Action)
+	return nil
+}
+
+func (svc *RemoteFactoryService) bee(name string) (BeeInterface, error) {
+	svc.mutex.Lock()
+	defer svc.mutex.Unlock()
+
+	bee, ok := svc.bees[name]
+	if !ok {
+		return nil, ErrUnknownBee
+	}
+	return bee, nil
+}
+
+// stopAll stops all running bees.
+func (svc *RemoteFactoryService) stopAll() {
+	svc.mutex.Lock()
+	bs := svc.bees
+	svc.bees = make(map[string]BeeInterface)
+	svc.mutex.Unlock()
+
+	for _, bee := range bs {
+		bee.Stop()
+	}
+}
@@ -0,0 +1,179 @@
+/*
+ *    Copyright (C) 2026 Christian Muehlhaeuser
+ *
+ *    This program is free software: you can redistribute it and/or modify
+ *    it under the terms of the GNU Affero General Public License as published
+ *    by the Free Software Foundation, either version 3 of the License, or
+ *    (at your option) any later version.
+ *
+ *    This program is distributed in the hope that it will be useful,
+ *    but WITHOUT ANY WARRANTY; without even the implied warranty of
+ *    MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ *    GNU Affero General Public License for more details.
+ *
+ *    You should have received a copy of the GNU Affero General Public License
+ *    along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ *    Authors:
+ *      Christian Muehlhaeuser <muesli@gmail.com>
+ */
+// Package bees is Beehive's central module system.
+package bees
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// eventDone signals that an event has been handled.
+type eventDone struct {
+	once sync.Once
+	ch   chan struct{}
+}
+
+// finish marks an event as handled: either its chains have been executed, or
+// it got dropped.
+func (e *Event) finish() {
+	if e.done != nil {
+		e.done.once.Do(func() { close(e.done.ch) })
+	}
+	if e.work != nil {
+		e.work.end()
+	}
+}
+
+var replayDelay int64
+
+// SetReplayDelay sets the time ReplayEvents waits between injecting two
+// events.
+func SetReplayDelay(d time.Duration) {
+	atomic.StoreInt64(&replayDelay, int64(d))
+}
+
+// ReplayEvents injects events into the hive, in order, e.g. to test chains
+// with events captured by RecordEvents. The returned channel gets closed once
+// all events have been handled, i.e. their chains have been executed.
+func ReplayEvents(events []Event) <-chan struct{} {
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+
+		delay := time.Duration(atomic.LoadInt64(&replayDelay))
+		var pending []*eventDone
+		for i, event := range events {
+			if i > 0 && delay > 0 {
+				time.Sleep(delay)
+			}
+
+			event.ID = ""
+			event.trace = nil
+			event.done = &eventDone{ch: make(chan struct{})}
+			if !injectEventSafely(event) {
+				logger.Errorf("Stopped replaying events: the event handler is not running")
+				break
+			}
+			pending = append(pending, event.done)
+		}
+
+		for _, d := range pending {
+			<-d.ch
+		}
+	}()
+
+	return done
+}
+
+// injectEventSafely works like injectEvent, but reports whether the event
+// could be injected.
+func injectEventSafely(event Event) bool {
+	return deliverEvent(event)
+}
+
+// An EventRecorder captures the events handled by the hive, see
+// RecordEvents.
+type EventRecorder struct {
+	mutex  sync.Mutex
+	n      int
+	events []Event
+	done   chan struct{}
+}
+
+var (
+	recorders      []*EventRecorder
+	recordersMutex sync.Mutex
+)
+
+// RecordEvents captures the next n events handled by the hive. They can be
+// passed to ReplayEvents later.
+func RecordEvents(n int) *EventRecorder {
+	r := &EventRecorder{
+		n:    n,
+		done: make(chan struct{}),
+	}
+	if n <= 0 {
+		close(r.done)
+		return r
+	}
+
+	recordersMutex.Lock()
+	defer recordersMutex.Unlock()
+	recorders = append(recorders, r)
+
+	return r
+}
+
+// Events returns the events captured so far.
+func (r *EventRecorder) Events() []Event {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	return append([]Event{}, r.events...)
+}
+
+// Done returns a channel which gets closed once all events were captured, or
+// the recorder got stopped.
+func (r *EventRecorder) Done() <-chan struct{} {
+	return r.done
+}
+
+// Stop stops capturing events.
+func (r *EventRecorder) Stop() {
+	recordersMutex.Lock()
+	defer recordersMutex.Unlock()
+
+	r.stop()
+}
+
+// stop removes the recorder. Must be called with recordersMutex held.
+func (r *EventRecorder) stop() {
+	for i, rec := range recorders {
+		if rec == r {
+			recorders = append(recorders[:i:i], recorders[i+1:]...)
+			close(r.done)
+			return
+		}
+	}
+}
+
+// captureEvent hands an event to all active recorders.
+func captureEvent(event Event) {
+	event.done = nil
+	event.work = nil
+	event.trace = nil
+
+	recordersMutex.Lock()
+	defer recordersMutex.Unlock()
+
+	for _, r := range append([]*EventRecorder{}, recorders...) {
+		r.mutex.Lock()
+		r.events = append(r.events, event)
+		full := len(r.events) >= r.n
+		r.mutex.Unlock()
+
+		if full {
+			r.stop()
+		}
+	}
+}
@@ -0,0 +1,68 @@
+package bees
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestRecordReplayEvents(t *testing.T) {
+	events := make(chan Event)
+	defer useEventChannel(events)()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go runEventLoop(ctx, events)
+
+	bee := newRecordingBee("replaysink")
+	defer DeleteBee(GetBee("replaysink"))
+
+	oldActions := GetActions()
+	defer SetActions(oldActions)
+	SetActions([]Action{{ID: "replay-record", Bee: "replaysink", Name: "record", Options: Placeholders{{Name: "text", Value: "{{.text}}"}}}})
+	oldChains := chains
+	defer func() { chains = oldChains }()
+	SetChains([]Chain{{Name: "replay", Event: &Event{Bee: "sensor", Name: "reading"}, Actions: []string{"replay-record"}}})
+
+	rec := RecordEvents(2)
+	for _, text := range []string{"first", "second", "third"} {
+		events <- Event{Bee: "sensor", Name: "reading", Options: Placeholders{{Name: "text", Type: "string", Value: text}}}
+	}
+	select {
+	case <-rec.Done():
+	case <-time.After(time.Second):
+		t.Fatal("Expected recorder to capture two events")
+	}
+	recorded := rec.Events()
+	if len(recorded) != 2 || recorded[0].Options.Value("text") != "first" || recorded[1].Options.Value("text") != "second" {
+		t.Fatalf("Unexpected recorded events %+v", recorded)
+	}
+
+	select {
+	case <-ReplayEvents(recorded):
+	case <-time.After(time.Second):
+		t.Fatal("Expected replayed events to be handled")
+	}
+	// the chains of the recorded events run concurrently to the replay
+	deadline := time.Now().Add(time.Second)
+	for len(bee.executed()) < 5 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	counts := map[string]int{}
+	bee.mutex.Lock()
+	for _, opts := range bee.options {
+		counts[opts.Value("text").(string)]++
+	}
+	bee.mutex.Unlock()
+	if exp := map[string]int{"first": 2, "second": 2, "third": 1}; !reflect.DeepEqual(counts, exp) {
+		t.Errorf("Expected actions %v, got %v", exp, counts)
+	}
+
+	cancel()
+	closeEventChannel()
+	select {
+	case <-ReplayEvents(recorded):
+	case <-time.After(time.Second):
+		t.Fatal("Expected replaying to a stopped event handler to give up")
+	}
+}
@@ -0,0 +1,83 @@
+/*
+ *    Copyright (C) 2026 Christian Muehlhaeuser
+ *
+ *    This program is free software: you can redistribute it and/or modify
+ *    it under the terms of the GNU Affero General Public License as published
+ *    by the Free Software Foundation, either version 3 of the License, or
+ *    (at your option) any later version.
+ *
+ *    This program is distributed in the hope that it will be useful,
+ *    but WITHOUT ANY WARRANTY; without even the implied warranty of
+ *    MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ *    GNU Affero General Public License for more details.
+ *
+ *    You should have received a copy of the GNU Affero General Public License
+ *    along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ *    Authors:
+ *      Christian Muehlhaeuser <muesli@gmail.com>
+ */
+
+// Package bees is Beehive's central module system.
+package bees
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// DefaultMaxRetries is the default number of times a crashing bee gets
+// restarted, before it's terminated and marked as BeeCrashed.
+const DefaultMaxRetries = 2
+
+const (
+	// DefaultRestartBackoff is the default delay before restarting a bee
+	// after its first crash. It doubles with every further crash
+	DefaultRestartBackoff = time.Second
+
+	// DefaultMaxRestartBackoff is the default upper bound of the delay
+	// before restarting a crashed bee
+	DefaultMaxRestartBackoff = time.Minute
+)
+
+var (
+	restartBackoff    = int64(DefaultRestartBackoff)
+	maxRestartBackoff = int64(DefaultMaxRestartBackoff)
+)
+
+// SetRestartBackoff sets the delay before restarting a bee after its first
+// crash, and the upper bound the delay grows to with further crashes.
+func SetRestartBackoff(base, max time.Duration) {
+	atomic.StoreInt64(&restartBackoff, int64(base))
+	atomic.StoreInt64(&maxRestartBackoff, int64(max))
+}
+
+// maxRetries returns how often a bee gets restarted after crashing, see
+// BeeConfig.MaxRetries.
+func maxRetries(name string) int {
+	c, ok := instanceConfig(name)
+	switch {
+	case !ok || c.MaxRetries == 0:
+		return DefaultMaxRetries
+	case c.MaxRetries < 0:
+		return 0
+	}
+
+	return c.MaxRetries
+}
+
+// restartDelay returns the delay before restarting a bee after its fatals'th
+// crash: the backoff doubles with each crash, and a random jitter of up to
+// half the delay keeps bees crashing together from restarting in lockstep.
+func restartDelay(fatals int) time.Duration {
+	d := time.Duration(atomic.LoadInt64(&restartBackoff))
+	max := time.Duration(atomic.LoadInt64(&maxRestartBackoff))
+	for i := 1; i < fatals && d < max; i++ {
+		d *= 2
+	}
+	if d > max {
+		d = max
+	}
+
+	return d/2 + time.Duration(randFloat64()*float64(d/2))
+}
@@ -0,0 +1,127 @@
+/*
+ *    Copyright (C) 2026 Christian Muehlhaeuser
+ *
+ *    This program is free software: you can redistribute it and/or modify
+ *    it under the terms of the GNU Affero General Public License as published
+ *    by the Free Software Foundation, either version 3 of the License, or
+ *    (at your option) any later version.
+ *
+ *    This program is distributed in the hope that it will be useful,
+ *    but WITHOUT ANY WARRANTY; without even the implied warranty of
+ *    MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ *    GNU Affero General Public License for more details.
+ *
+ *    You should have received a copy of the GNU Affero General Public License
+ *    along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ *    Authors:
+ *      Christian Muehlhaeuser <muesli@gmail.com>
+ */
+// Package bees is Beehive's central module system.
+package bees
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+)
+
+const (
+	// ActionDoneSuffix gets appended to an action's name to form the name of
+	// the event emitted once the action of a chain with EmitResults completed.
+	ActionDoneSuffix = "_done"
+	// ActionFailedSuffix gets appended to an action's name to form the name
+	// of the event emitted when the action of a chain with EmitResults failed.
+	ActionFailedSuffix = "_failed"
+
+	// DefaultMaxResultHops is the default maximum number of result events
+	// leading to each other.
+	DefaultMaxResultHops = 3
+)
+
+var maxResultHops = int64(DefaultMaxResultHops)
+
+// SetMaxResultHops sets how many action result events, including those of
+// Action.ResultEvent, may lead to each other: chains triggered by a result
+// event that already went through n hops don't emit any further result
+// events. This stops chains reacting to
+// their own results from looping forever.
+func SetMaxResultHops(n int) {
+	atomic.StoreInt64(&maxResultHops, int64(n))
+}
+
+// execChainAction executes an action of chain c. If the chain has
+// EmitResults set, the action's outcome gets emitted as an event afterwards.
+// Independently, the action's results get emitted as its ResultEvent.
+func execChainAction(ctx context.Context, c Chain, action Action, m map[string]interface{}, event *Event) []Placeholder {
+	statsOfChain(c.Name).actionExecuted()
+	if !c.EmitResults {
+		res := execAction(ctx, action, m, event)
+		actionExecuted(c.Name, action, res)
+		emitResultEvent(action, event, res)
+		return res
+	}
+
+	defer func() {
+		if e := recover(); e != nil {
+			emitActionResult(c, action, event, ActionFailedSuffix, Placeholders{
+				{Name: "error", Type: "string", Value: fmt.Sprintf("%v", e)},
+			})
+			panic(e)
+		}
+	}()
+
+	res := execAction(ctx, action, m, event)
+	actionExecuted(c.Name, action, res)
+	emitActionResult(c, action, event, ActionDoneSuffix, res)
+	emitResultEvent(action, event, res)
+	return res
+}
+
+// emitActionResult emits the result of an action as an event of the acting
+// bee, named after the action plus suffix. Besides the action's results it
+// carries the name of the chain in the "chain" placeholder. Nothing gets
+// emitted once the cause went through the maximum number of result hops.
+func emitActionResult(c Chain, action Action, cause *Event, suffix string, res []Placeholder) {
+	opts := append(Placeholders{}, res...)
+	opts.SetValue("chain", "string", c.Name)
+
+	injectResult(action, cause, action.Name+suffix, opts)
+}
+
+// emitResultEvent emits the results of an action as its ResultEvent, unless
+// it has none or the action didn't return any results.
+func emitResultEvent(action Action, cause *Event, res []Placeholder) {
+	if len(action.ResultEvent) == 0 || len(res) == 0 {
+		return
+	}
+
+	injectResult(action, cause, action.ResultEvent, append(Placeholders{}, res...))
+}
+
+// injectResult emits an event of the acting bee, derived from the event
+// that caused the action. It keeps the trace of its cause, rather than picking
+// up whatever the bee happens to be executing once the event gets handled.
+// Nothing gets emitted once the cause went through the maximum number of
+// result hops.
+func injectResult(action Action, cause *Event, name string, opts Placeholders) {
+	hops := 0
+	if cause != nil {
+		hops = cause.ResultHops
+	}
+	if int64(hops) >= atomic.LoadInt64(&maxResultHops) {
+		logger.Debugf("Not emitting result of action %v / %v: exceeded %d result hops", action.Bee, action.Name, hops)
+		return
+	}
+
+	ev := deriveEvent(cause, Event{
+		Bee:        action.Bee,
+		Name:       name,
+		Options:    opts,
+		ResultHops: hops + 1,
+	})
+	if ev.trace == nil {
+		ev.trace = actionTrace{}
+	}
+	go injectEvent(ev)
+}
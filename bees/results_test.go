@@ -0,0 +1,133 @@
+package bees
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestActionResultEvents(t *testing.T) {
+	events := make(chan Event)
+	defer useEventChannel(events)()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go runEventLoop(ctx, events)
+
+	bee := newRecordingBee("resultbee")
+	defer DeleteBee(GetBee("resultbee"))
+
+	oldActions := GetActions()
+	defer SetActions(oldActions)
+	SetActions([]Action{
+		{ID: "result-shorten", Bee: "resultbee", Name: "shorten"},
+		{ID: "result-post", Bee: "resultbee", Name: "post", Options: Placeholders{
+			{Name: "text", Value: "{{.chain}}: {{.shortened_url}}"},
+		}},
+		{ID: "result-fail", Bee: "resultbee", Name: "fail"},
+	})
+	oldChains := chains
+	defer SetChains(oldChains)
+	SetChains([]Chain{
+		{Name: "shorten", Event: &Event{Bee: "feed", Name: "link"}, Actions: []string{"result-shorten", "result-fail"}, EmitResults: true},
+		{Name: "post", Event: &Event{Bee: "resultbee", Name: "shorten_done"}, Actions: []string{"result-post"}},
+		{Name: "failed", Event: &Event{Bee: "resultbee", Name: "fail_failed"}, Actions: []string{"result-post"}},
+	})
+
+	events <- Event{Bee: "feed", Name: "link"}
+
+	deadline := time.Now().Add(time.Second)
+	for len(bee.executed()) < 4 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	texts := map[string]bool{}
+	bee.mutex.Lock()
+	for i, name := range bee.actions {
+		if name == "post" {
+			texts[bee.options[i].Value("text").(string)] = true
+		}
+	}
+	bee.mutex.Unlock()
+	if !texts["shorten: https://sho.rt/1"] || !texts["shorten: "] || len(texts) != 2 {
+		t.Errorf("Expected the results of both actions to get posted, got %v", texts)
+	}
+}
+
+func TestActionResultHops(t *testing.T) {
+	events := make(chan Event)
+	defer useEventChannel(events)()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go runEventLoop(ctx, events)
+
+	SetMaxResultHops(2)
+	defer SetMaxResultHops(DefaultMaxResultHops)
+
+	bee := newRecordingBee("loopbee")
+	defer DeleteBee(GetBee("loopbee"))
+
+	oldActions := GetActions()
+	defer SetActions(oldActions)
+	SetActions([]Action{{ID: "loop-shorten", Bee: "loopbee", Name: "shorten"}})
+	oldChains := chains
+	defer SetChains(oldChains)
+	SetChains([]Chain{
+		{Name: "start", Event: &Event{Bee: "feed", Name: "link"}, Actions: []string{"loop-shorten"}, EmitResults: true},
+		{Name: "loop", Event: &Event{Bee: "loopbee", Name: "shorten_done"}, Actions: []string{"loop-shorten"}, EmitResults: true},
+	})
+
+	events <- Event{Bee: "feed", Name: "link"}
+
+	// the initial action plus one for each of the two permitted hops
+	deadline := time.Now().Add(time.Second)
+	for len(bee.executed()) < 3 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	time.Sleep(50 * time.Millisecond)
+	if n := len(bee.executed()); n != 3 {
+		t.Errorf("Expected the loop to stop after 3 actions, got %d", n)
+	}
+}
+
+func TestResultEvent(t *testing.T) {
+	events := make(chan Event)
+	defer useEventChannel(events)()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go runEventLoop(ctx, events)
+
+	bee := newRecordingBee("resulteventbee")
+	defer DeleteBee(GetBee("resulteventbee"))
+
+	oldActions := GetActions()
+	defer SetActions(oldActions)
+	SetActions([]Action{
+		{ID: "resultevent-shorten", Bee: "resulteventbee", Name: "shorten", ResultEvent: "shortened"},
+		{ID: "resultevent-post", Bee: "resulteventbee", Name: "post", ResultEvent: "posted", Options: Placeholders{
+			{Name: "text", Value: "{{.shortened_url}}"},
+		}},
+	})
+	oldChains := chains
+	defer SetChains(oldChains)
+	SetChains([]Chain{
+		{Name: "shorten", Event: &Event{Bee: "feed", Name: "link"}, Actions: []string{"resultevent-shorten"}},
+		{Name: "post", Event: &Event{Bee: "resulteventbee", Name: "shortened"}, Actions: []string{"resultevent-post"}},
+		{Name: "posted", Event: &Event{Bee: "resulteventbee", Name: "posted"}, Actions: []string{"resultevent-post"}},
+	})
+
+	events <- Event{Bee: "feed", Name: "link"}
+
+	deadline := time.Now().Add(time.Second)
+	for len(bee.executed()) < 2 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	// post returns no results, so it mustn't emit its result event
+	time.Sleep(50 * time.Millisecond)
+	if ex := bee.executed(); len(ex) != 2 || ex[1] != "post" {
+		t.Fatalf("Expected shorten and post to be executed, got %v", ex)
+	}
+	bee.mutex.Lock()
+	defer bee.mutex.Unlock()
+	if v := bee.options[1].Value("text"); v != "https://sho.rt/1" {
+		t.Errorf("Expected the shortened URL to be posted, got %v", v)
+	}
+}
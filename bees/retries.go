@@ -0,0 +1,197 @@
+/*
+ *    Copyright (C) 2026 Christian Muehlhaeuser
+ *
+ *    This program is free software: you can redistribute it and/or modify
+ *    it under the terms of the GNU Affero General Public License as published
+ *    by the Free Software Foundation, either version 3 of the License, or
+ *    (at your option) any later version.
+ *
+ *    This program is distributed in the hope that it will be useful,
+ *    but WITHOUT ANY WARRANTY; without even the implied warranty of
+ *    MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ *    GNU Affero General Public License for more details.
+ *
+ *    You should have received a copy of the GNU Affero General Public License
+ *    along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ *    Authors:
+ *      Christian Muehlhaeuser <muesli@gmail.com>
+ */
+
+// Package bees is Beehive's central module system.
+package bees
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// DefaultRetryBackoff is the delay before the first retry of a failed action,
+// unless the action sets its own RetryBackoff.
+const DefaultRetryBackoff = time.Second
+
+// actionRetry is a failed action waiting to be retried.
+type actionRetry struct {
+	action Action
+	opts   map[string]interface{}
+	cause  Event
+	chain  string
+	scope  string
+	// attempts counts the failed executions so far
+	attempts int
+	lastErr  error
+	timer    *time.Timer
+}
+
+var (
+	actionRetries      = make(map[*actionRetry]struct{})
+	actionRetriesMutex sync.Mutex
+)
+
+// retryBackoff returns the delay before the action's next retry, after it
+// failed attempts times.
+func (a *Action) retryBackoff(attempts int) time.Duration {
+	d := a.RetryBackoff
+	if d <= 0 {
+		d = DefaultRetryBackoff
+	}
+	return d * time.Duration(backoff(attempts-1))
+}
+
+// tryChainAction executes an action of a chain like execChainAction, but
+// returns failures instead of panicking.
+func tryChainAction(ctx context.Context, c Chain, action Action, m map[string]interface{}, event *Event) (res []Placeholder, err error) {
+	defer func() {
+		if e := recover(); e != nil {
+			err = fmt.Errorf("%v", e)
+		}
+	}()
+
+	return execChainAction(ctx, c, action, m, event), nil
+}
+
+// retryAction schedules the first retry of an action of chain c that failed
+// with err. The action uses a snapshot of opts, taken when it gets scheduled.
+func retryAction(c Chain, action Action, opts map[string]interface{}, cause *Event, err error) {
+	snapshot := make(map[string]interface{}, len(opts))
+	for k, v := range opts {
+		snapshot[k] = v
+	}
+
+	logger.Warnf("Action %v / %v of chain %v failed, retrying: %v", action.Bee, action.Name, c.Name, err)
+	scheduleRetry(&actionRetry{
+		action:   action,
+		opts:     snapshot,
+		cause:    *cause,
+		chain:    c.Name,
+		scope:    c.Scope,
+		attempts: 1,
+		lastErr:  err,
+	}, action.retryBackoff(1))
+}
+
+// redriveAction executes a dead-lettered action again right away. It gets its
+// full number of retries once more.
+func redriveAction(r *actionRetry) {
+	redo := &actionRetry{
+		action: r.action,
+		opts:   r.opts,
+		cause:  r.cause,
+		chain:  r.chain,
+		scope:  r.scope,
+	}
+	scheduleRetry(redo, 0)
+}
+
+// scheduleRetry queues r for its next attempt, once delay has passed.
+func scheduleRetry(r *actionRetry, delay time.Duration) {
+	actionRetriesMutex.Lock()
+	defer actionRetriesMutex.Unlock()
+
+	actionRetries[r] = struct{}{}
+	atomic.AddInt64(&scheduledTimers, 1)
+	r.timer = time.AfterFunc(delay, func() {
+		fireRetry(r)
+	})
+}
+
+// unscheduleRetry removes a queued retry, returning false if it already fired
+// or got cancelled.
+func unscheduleRetry(r *actionRetry) bool {
+	actionRetriesMutex.Lock()
+	defer actionRetriesMutex.Unlock()
+
+	if _, ok := actionRetries[r]; !ok {
+		return false
+	}
+	delete(actionRetries, r)
+	r.timer.Stop()
+	atomic.AddInt64(&scheduledTimers, -1)
+
+	return true
+}
+
+// fireRetry executes a queued action again. If it fails once more, it gets
+// queued for another retry, or ends up in the dead-letter queue once it ran
+// out of retries.
+func fireRetry(r *actionRetry) {
+	if !unscheduleRetry(r) {
+		return
+	}
+
+	ctx := withChainScope(withChainName(context.Background(), r.chain), r.scope)
+	err := r.execute(ctx)
+	if err == nil {
+		logger.Infof("Retrying action %v / %v of chain %v succeeded", r.action.Bee, r.action.Name, r.chain)
+		return
+	}
+	r.lastErr = err
+	r.attempts++
+
+	if r.attempts > r.action.Retries {
+		logger.Errorf("Action %v / %v of chain %v failed %d times, giving up: %v", r.action.Bee, r.action.Name, r.chain, r.attempts, r.lastErr)
+		deadLetterAction(r)
+		return
+	}
+	logger.Warnf("Retrying action %v / %v of chain %v failed (attempt %d of %d): %v", r.action.Bee, r.action.Name, r.chain, r.attempts, r.action.Retries+1, r.lastErr)
+	scheduleRetry(r, r.action.retryBackoff(r.attempts))
+}
+
+// execute runs the action with its snapshot of options.
+func (r *actionRetry) execute(ctx context.Context) (err error) {
+	defer func() {
+		if e := recover(); e != nil {
+			err = fmt.Errorf("%v", e)
+		}
+	}()
+
+	res := execAction(ctx, r.action, r.opts, &r.cause)
+	actionExecuted(r.chain, r.action, res)
+	emitResultEvent(r.action, &r.cause, res)
+	return nil
+}
+
+// CancelRetries drops all failed actions waiting to be retried.
+func CancelRetries() {
+	actionRetriesMutex.Lock()
+	rs := []*actionRetry{}
+	for r := range actionRetries {
+		rs = append(rs, r)
+	}
+	actionRetriesMutex.Unlock()
+
+	for _, r := range rs {
+		unscheduleRetry(r)
+	}
+}
+
+// PendingRetries returns the number of failed actions waiting to be retried.
+func PendingRetries() int {
+	actionRetriesMutex.Lock()
+	defer actionRetriesMutex.Unlock()
+
+	return len(actionRetries)
+}
@@ -0,0 +1,119 @@
+package bees
+
+import (
+	"context"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// flakyBee fails its first "send" actions.
+type flakyBee struct {
+	recordingBee
+
+	failures int32
+}
+
+func (mod *flakyBee) Action(ctx context.Context, action Action) []Placeholder {
+	if action.Name == "send" && atomic.AddInt32(&mod.failures, -1) >= 0 {
+		panic("network blip")
+	}
+	return mod.recordingBee.Action(ctx, action)
+}
+
+// waitFor polls cond until it's true or timeout passed.
+func waitFor(timeout time.Duration, cond func() bool) bool {
+	deadline := time.Now().Add(timeout)
+	for !cond() {
+		if time.Now().After(deadline) {
+			return false
+		}
+		time.Sleep(time.Millisecond)
+	}
+	return true
+}
+
+func TestActionRetries(t *testing.T) {
+	mod := &flakyBee{recordingBee: recordingBee{Bee: NewBee("flakybee", "recordingbee", "", BeeOptions{})}, failures: 2}
+	var bee BeeInterface = mod
+	RegisterBee(bee)
+	mod.Start()
+	defer DeleteBee(GetBee("flakybee"))
+
+	oldActions := GetActions()
+	defer SetActions(oldActions)
+	SetActions([]Action{
+		{ID: "flaky-send", Bee: "flakybee", Name: "send", Retries: 2, RetryBackoff: time.Millisecond, Options: Placeholders{
+			{Name: "text", Type: "string", Value: "{{.text}}"},
+		}},
+		{ID: "flaky-log", Bee: "flakybee", Name: "log"},
+	})
+	defer CancelRetries()
+
+	c := Chain{Name: "flaky", Actions: []string{"flaky-send", "flaky-log"}}
+	ev := &Event{Bee: "flakybee", Name: "trigger", Options: Placeholders{{Name: "text", Type: "string", Value: "hello"}}}
+	if exec := execChain(context.Background(), c, ev, nil, false); exec == nil || exec.Err != nil {
+		t.Fatalf("Expected failures of retried actions not to fail the chain, got %+v", exec)
+	}
+	if got := strings.Join(mod.executed(), ","); got != "log" {
+		t.Errorf("Expected the chain to carry on, got %v", got)
+	}
+
+	if !waitFor(time.Second, func() bool { return len(mod.executed()) == 2 }) {
+		t.Fatalf("Expected the action to succeed on its last retry, got %v", mod.executed())
+	}
+	if text := mod.options[1].Value("text"); text != "hello" {
+		t.Errorf("Expected the retry to use the original options, got %v", text)
+	}
+	if n := PendingRetries(); n != 0 {
+		t.Errorf("Expected no pending retries, got %d", n)
+	}
+}
+
+func TestActionDeadLetters(t *testing.T) {
+	mod := &flakyBee{recordingBee: recordingBee{Bee: NewBee("deadbee2", "recordingbee", "", BeeOptions{})}, failures: 3}
+	var bee BeeInterface = mod
+	RegisterBee(bee)
+	mod.Start()
+	defer DeleteBee(GetBee("deadbee2"))
+
+	q := NewDeadLetterQueue(10)
+	SetDeadLetterQueue(q)
+	defer SetDeadLetterQueue(NewDeadLetterQueue(DefaultDeadLetterQueueSize))
+
+	oldActions := GetActions()
+	defer SetActions(oldActions)
+	SetActions([]Action{{ID: "dead-send", Bee: "deadbee2", Name: "send", Retries: 2, RetryBackoff: time.Millisecond}})
+	defer CancelRetries()
+
+	c := Chain{Name: "dead", Actions: []string{"dead-send"}}
+	execChain(context.Background(), c, &Event{ID: "42", Bee: "deadbee2", Name: "trigger"}, nil, false)
+	if !waitFor(time.Second, func() bool { return len(q.Entries()) == 1 }) {
+		t.Fatal("Expected the action to be dead-lettered after its last retry")
+	}
+
+	entry := q.Entries()[0]
+	if entry.Action == nil || entry.Action.Name != "send" || entry.Chain != "dead" || entry.Attempts != 3 {
+		t.Errorf("Unexpected dead letter %+v", entry)
+	}
+	if entry.Err == nil || entry.Event.ID != "42" {
+		t.Errorf("Expected the dead letter to carry its error and cause, got %+v", entry)
+	}
+	if _, ok := q.Entry(entry.ID); !ok {
+		t.Error("Expected to find the dead letter by its ID")
+	}
+
+	if !q.Redrive(entry.ID) {
+		t.Fatal("Expected to re-drive the dead letter")
+	}
+	if len(q.Entries()) != 0 {
+		t.Error("Expected the re-driven dead letter to be removed")
+	}
+	if !waitFor(time.Second, func() bool { return len(mod.executed()) == 1 }) {
+		t.Error("Expected the re-driven action to succeed")
+	}
+	if q.Redrive(entry.ID) || q.Remove(entry.ID) {
+		t.Error("Expected unknown dead letters to be reported")
+	}
+}
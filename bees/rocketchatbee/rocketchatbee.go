@@ -26,6 +26,8 @@
 package rocketchatbee
 
 import (
+	"context"
+
 	"github.com/muesli/beehive/bees"
 )
 
@@ -37,7 +39,7 @@ type RocketchatBee struct {
 }
 
 // Action triggers the action passed to it.
-func (mod *RocketchatBee) Action(action bees.Action) []bees.Placeholder {
+func (mod *RocketchatBee) Action(ctx context.Context, action bees.Action) []bees.Placeholder {
 	outs := []bees.Placeholder{}
 
 	switch action.Name {
@@ -24,6 +24,7 @@
 package rssbee
 
 import (
+	"context"
 	"time"
 
 	rss "github.com/muesli/go-pkg-rss"
@@ -163,7 +164,7 @@ func (mod *RSSBee) pollFeed(uri string, timeout int) {
 }
 
 // Run executes the Bee's event loop.
-func (mod *RSSBee) Run(cin chan bees.Event) {
+func (mod *RSSBee) Run(ctx context.Context, cin chan bees.Event) {
 	mod.eventChan = cin
 
 	time.Sleep(10 * time.Second)
@@ -21,6 +21,7 @@
 package s3bee
 
 import (
+	"context"
 	"mime"
 	"os"
 	"path/filepath"
@@ -36,7 +37,7 @@ type S3Bee struct {
 }
 
 // Action triggers the action passed to it.
-func (bee *S3Bee) Action(action bees.Action) []bees.Placeholder {
+func (bee *S3Bee) Action(ctx context.Context, action bees.Action) []bees.Placeholder {
 	outs := []bees.Placeholder{}
 
 	switch action.Name {
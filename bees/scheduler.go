@@ -0,0 +1,316 @@
+/*
+ *    Copyright (C) 2026 Christian Muehlhaeuser
+ *
+ *    This program is free software: you can redistribute it and/or modify
+ *    it under the terms of the GNU Affero General Public License as published
+ *    by the Free Software Foundation, either version 3 of the License, or
+ *    (at your option) any later version.
+ *
+ *    This program is distributed in the hope that it will be useful,
+ *    but WITHOUT ANY WARRANTY; without even the implied warranty of
+ *    MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ *    GNU Affero General Public License for more details.
+ *
+ *    You should have received a copy of the GNU Affero General Public License
+ *    along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ *    Authors:
+ *      Christian Muehlhaeuser <muesli@gmail.com>
+ */
+// Package bees is Beehive's central module system.
+package bees
+
+import (
+	"errors"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/robfig/cron/v3"
+)
+
+// TimerEvent gets emitted by the SystemBee whenever a timer fires. Its
+// "timer" placeholder carries the name of the timer.
+const TimerEvent = "timer"
+
+var (
+	// ErrUnknownTimer is returned when no timer with the requested name exists
+	ErrUnknownTimer = errors.New("No timer with that name exists")
+	// ErrDuplicateTimer is returned when adding a timer with a name that is
+	// already taken
+	ErrDuplicateTimer = errors.New("A timer with that name already exists")
+	// ErrInvalidTimer is returned when adding a timer that doesn't specify
+	// exactly one of Cron, Interval and At
+	ErrInvalidTimer = errors.New("A timer needs exactly one of Cron, Interval or At")
+)
+
+// A Timer emits a TimerEvent on a schedule: either on a cron expression
+// (standard 5-field format), every Interval, or once At a specific time.
+// One-shot timers get removed once they fired.
+type Timer struct {
+	Name     string
+	Cron     string        `json:"Cron,omitempty"`
+	Interval time.Duration `json:"Interval,omitempty"`
+	At       time.Time     `json:"At,omitempty"`
+
+	// Paused timers don't fire until they get resumed
+	Paused bool
+	// Next is the time the timer fires next, zero while it's paused
+	Next time.Time `json:"Next,omitempty"`
+	// Fired counts how often the timer fired
+	Fired int64
+}
+
+// timer is a scheduled Timer.
+type timer struct {
+	Timer
+	sched cron.Schedule
+	stop  chan struct{}
+}
+
+var (
+	timers      = make(map[string]*timer)
+	timersMutex sync.Mutex
+)
+
+// AddTimer schedules a new timer.
+func AddTimer(t Timer) error {
+	t.Fired = 0
+	return addTimer(t)
+}
+
+// addTimer schedules a new timer, keeping its count of Fired occurrences.
+func addTimer(t Timer) error {
+	n := 0
+	for _, set := range []bool{len(t.Cron) > 0, t.Interval > 0, !t.At.IsZero()} {
+		if set {
+			n++
+		}
+	}
+	if len(t.Name) == 0 || n != 1 {
+		return ErrInvalidTimer
+	}
+
+	tm := &timer{Timer: t}
+	if len(t.Cron) > 0 {
+		sched, err := cron.ParseStandard(t.Cron)
+		if err != nil {
+			return err
+		}
+		tm.sched = sched
+	}
+	tm.Next = time.Time{}
+
+	timersMutex.Lock()
+	defer timersMutex.Unlock()
+
+	if _, ok := timers[t.Name]; ok {
+		return ErrDuplicateTimer
+	}
+	timers[t.Name] = tm
+	if !tm.Paused {
+		tm.start()
+	}
+	return nil
+}
+
+// RemoveTimer stops and removes a timer.
+func RemoveTimer(name string) error {
+	timersMutex.Lock()
+	defer timersMutex.Unlock()
+
+	tm, ok := timers[name]
+	if !ok {
+		return ErrUnknownTimer
+	}
+	tm.halt()
+	delete(timers, name)
+	return nil
+}
+
+// PauseTimer stops a timer from firing until it gets resumed.
+func PauseTimer(name string) error {
+	timersMutex.Lock()
+	defer timersMutex.Unlock()
+
+	tm, ok := timers[name]
+	if !ok {
+		return ErrUnknownTimer
+	}
+	tm.halt()
+	tm.Paused = true
+	return nil
+}
+
+// ResumeTimer resumes a paused timer. Occurrences missed while it was paused
+// don't get caught up on.
+func ResumeTimer(name string) error {
+	timersMutex.Lock()
+	defer timersMutex.Unlock()
+
+	tm, ok := timers[name]
+	if !ok {
+		return ErrUnknownTimer
+	}
+	if tm.Paused {
+		tm.Paused = false
+		tm.start()
+	}
+	return nil
+}
+
+// takeTimers stops and removes all timers and returns them, so they can be
+// scheduled again with restoreTimers.
+func takeTimers() []Timer {
+	timersMutex.Lock()
+	defer timersMutex.Unlock()
+
+	ts := make([]Timer, 0, len(timers))
+	for name, tm := range timers {
+		tm.halt()
+		ts = append(ts, tm.Timer)
+		delete(timers, name)
+	}
+	sort.Slice(ts, func(i, j int) bool {
+		return ts[i].Name < ts[j].Name
+	})
+	return ts
+}
+
+// restoreTimers schedules the timers returned by takeTimers, e.g. in another
+// process, see PrepareHandoff. Timers that can't be scheduled get logged and skipped.
+func restoreTimers(ts []Timer) {
+	for _, t := range ts {
+		if err := addTimer(t); err != nil {
+			logger.Errorf("Failed to restore timer %v: %v", t.Name, err)
+		}
+	}
+}
+
+// GetTimer returns the timer with a specific name, or nil.
+func GetTimer(name string) *Timer {
+	timersMutex.Lock()
+	defer timersMutex.Unlock()
+
+	tm, ok := timers[name]
+	if !ok {
+		return nil
+	}
+	t := tm.Timer
+	return &t
+}
+
+// GetTimers returns all timers, sorted by name.
+func GetTimers() []Timer {
+	timersMutex.Lock()
+	defer timersMutex.Unlock()
+
+	ts := make([]Timer, 0, len(timers))
+	for _, tm := range timers {
+		ts = append(ts, tm.Timer)
+	}
+	sort.Slice(ts, func(i, j int) bool {
+		return ts[i].Name < ts[j].Name
+	})
+	return ts
+}
+
+// next returns the time the timer fires next after now, or zero if it
+// won't fire anymore.
+func (tm *timer) next(now time.Time) time.Time {
+	switch {
+	case tm.sched != nil:
+		return tm.sched.Next(now)
+	case tm.Interval > 0:
+		return now.Add(tm.Interval)
+	case tm.Fired == 0:
+		return tm.At
+	}
+	return time.Time{}
+}
+
+// start launches the timer's goroutine. The caller must hold timersMutex.
+func (tm *timer) start() {
+	tm.stop = make(chan struct{})
+	tm.Next = tm.next(time.Now())
+	atomic.AddInt64(&scheduledTimers, 1)
+	go tm.run(tm.stop, tm.Next)
+}
+
+// halt stops the timer's goroutine, if it's running. The caller must hold
+// timersMutex.
+func (tm *timer) halt() {
+	if tm.stop == nil {
+		return
+	}
+	close(tm.stop)
+	tm.stop = nil
+	tm.Next = time.Time{}
+	atomic.AddInt64(&scheduledTimers, -1)
+}
+
+// run fires the timer at next, and at all following occurrences, until stop
+// gets closed.
+func (tm *timer) run(stop chan struct{}, next time.Time) {
+	for {
+		t := time.NewTimer(time.Until(next))
+		select {
+		case <-stop:
+			t.Stop()
+			return
+		case <-t.C:
+		}
+
+		timersMutex.Lock()
+		select {
+		case <-stop:
+			timersMutex.Unlock()
+			return
+		default:
+		}
+		tm.Fired++
+		fired := tm.Fired
+		next = tm.next(time.Now())
+		tm.Next = next
+		if next.IsZero() {
+			// one-shot timers are done after firing once
+			tm.stop = nil
+			atomic.AddInt64(&scheduledTimers, -1)
+			delete(timers, tm.Name)
+		}
+		timersMutex.Unlock()
+
+		fireTimer(tm.Name, fired, stop)
+		if next.IsZero() {
+			return
+		}
+	}
+}
+
+// fireTimer emits a TimerEvent, giving up if the timer gets stopped while
+// waiting for the event handler.
+func fireTimer(name string, fired int64, stop chan struct{}) {
+	select {
+	case <-stop:
+		return
+	default:
+	}
+
+	logger.Debugf("Timer %v fired", name)
+	e := Event{
+		Bee:  SystemBee,
+		Name: TimerEvent,
+		Options: Placeholders{
+			{Name: "timer", Type: "string", Value: name},
+			{Name: "count", Type: "int", Value: fired},
+			{Name: "timestamp", Type: "string", Value: clock.Now().Format(time.RFC3339)},
+		},
+	}
+	in, stopped := eventChannel()
+	select {
+	case in <- e:
+	case <-stop:
+	case <-stopped:
+	}
+}
@@ -0,0 +1,79 @@
+package bees
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTimers(t *testing.T) {
+	events := make(chan Event, 10)
+	defer useEventChannel(events)()
+
+	if err := AddTimer(Timer{Name: "invalid", Cron: "* * * * *", Interval: time.Second}); err != ErrInvalidTimer {
+		t.Errorf("Expected ErrInvalidTimer, got %v", err)
+	}
+	if err := AddTimer(Timer{Name: "invalid", Cron: "not cron"}); err == nil {
+		t.Error("Expected an error for an invalid cron expression")
+	}
+
+	if err := AddTimer(Timer{Name: "ticker", Interval: 10 * time.Millisecond}); err != nil {
+		t.Fatal(err)
+	}
+	defer RemoveTimer("ticker")
+	if err := AddTimer(Timer{Name: "ticker", Interval: time.Second}); err != ErrDuplicateTimer {
+		t.Errorf("Expected ErrDuplicateTimer, got %v", err)
+	}
+	if err := AddTimer(Timer{Name: "once", At: time.Now().Add(5 * time.Millisecond)}); err != nil {
+		t.Fatal(err)
+	}
+	if err := AddTimer(Timer{Name: "nightly", Cron: "0 3 * * *", Paused: true}); err != nil {
+		t.Fatal(err)
+	}
+	defer RemoveTimer("nightly")
+
+	fired := map[string]int{}
+	deadline := time.After(time.Second)
+	for fired["ticker"] < 2 || fired["once"] < 1 {
+		select {
+		case e := <-events:
+			if e.Bee != SystemBee || e.Name != TimerEvent {
+				t.Fatalf("Unexpected event %+v", e)
+			}
+			fired[e.Options.Value("timer").(string)]++
+		case <-deadline:
+			t.Fatalf("Expected timers to fire, got %v", fired)
+		}
+	}
+	if GetTimer("once") != nil {
+		t.Error("Expected one-shot timer to be removed once it fired")
+	}
+
+	if err := PauseTimer("ticker"); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(5 * time.Millisecond)
+	for len(events) > 0 {
+		<-events
+	}
+	time.Sleep(30 * time.Millisecond)
+	if len(events) != 0 || !GetTimer("ticker").Paused || !GetTimer("ticker").Next.IsZero() {
+		t.Error("Expected paused timer not to fire")
+	}
+
+	if n := GetTimer("nightly").Next; !n.IsZero() {
+		t.Errorf("Expected paused timer to have no next occurrence, got %v", n)
+	}
+	if err := ResumeTimer("nightly"); err != nil {
+		t.Fatal(err)
+	}
+	if n := GetTimer("nightly").Next; n.Hour() != 3 || n.Minute() != 0 {
+		t.Errorf("Expected resumed timer to fire at 3:00, got %v", n)
+	}
+
+	if ts := GetTimers(); len(ts) != 2 || ts[0].Name != "nightly" || ts[1].Name != "ticker" {
+		t.Errorf("Unexpected timers %+v", ts)
+	}
+	if err := RemoveTimer("once"); err != ErrUnknownTimer {
+		t.Errorf("Expected ErrUnknownTimer, got %v", err)
+	}
+}
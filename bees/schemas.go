@@ -0,0 +1,270 @@
+/*
+ *    Copyright (C) 2026 Christian Muehlhaeuser
+ *
+ *    This program is free software: you can redistribute it and/or modify
+ *    it under the terms of the GNU Affero General Public License as published
+ *    by the Free Software Foundation, either version 3 of the License, or
+ *    (at your option) any later version.
+ *
+ *    This program is distributed in the hope that it will be useful,
+ *    but WITHOUT ANY WARRANTY; without even the implied warranty of
+ *    MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ *    GNU Affero General Public License for more details.
+ *
+ *    You should have received a copy of the GNU Affero General Public License
+ *    along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ *    Authors:
+ *      Christian Muehlhaeuser <muesli@gmail.com>
+ */
+
+// Package bees is Beehive's central module system.
+package bees
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/robfig/cron/v3"
+)
+
+// EventSchema describes the placeholders of an event. Unlike an
+// EventDescriptor, which documents an event, a registered EventSchema gets
+// enforced: events and chains not matching it cause warnings.
+type EventSchema struct {
+	// Options lists the placeholders of the event. A Type, if set, has to
+	// match the placeholder's Type. Mandatory placeholders are required.
+	Options []PlaceholderDescriptor
+}
+
+var (
+	eventSchemas      = make(map[string]EventSchema)
+	eventSchemasMutex sync.RWMutex
+
+	// placeholderRef matches references to placeholders in templates, e.g.
+	// ".text" in {{test Contains .text "hello"}}
+	placeholderRef = regexp.MustCompile(`(?:^|[^\w.)\]])\.([A-Za-z_]\w*)`)
+	templateAction = regexp.MustCompile(`{{(.*?)}}`)
+)
+
+// RegisterEventSchema registers the schema of the events called eventName,
+// emitted by bees of a namespace. Bee factories usually register the schemas
+// of their events in init.
+func RegisterEventSchema(namespace, eventName string, schema EventSchema) {
+	eventSchemasMutex.Lock()
+	defer eventSchemasMutex.Unlock()
+
+	eventSchemas[namespace+"/"+eventName] = schema
+}
+
+// eventSchema returns the schema of the events called eventName, emitted by
+// the bee with a specific name.
+func eventSchema(bee, eventName string) (EventSchema, bool) {
+	b := GetBee(bee)
+	if b == nil {
+		return EventSchema{}, false
+	}
+
+	eventSchemasMutex.RLock()
+	defer eventSchemasMutex.RUnlock()
+	schema, ok := eventSchemas[(*b).Namespace()+"/"+eventName]
+	return schema, ok
+}
+
+// ValidateEvent checks an event against the schema registered for it. It
+// reports missing mandatory placeholders, unknown placeholders and type
+// mismatches. Events without a registered schema are always valid.
+func ValidateEvent(e Event) []error {
+	schema, ok := eventSchema(e.Bee, e.Name)
+	if !ok {
+		return nil
+	}
+
+	var errs []error
+	for _, opt := range schema.Options {
+		found := false
+		for _, ph := range e.Options {
+			if ph.Name != opt.Name {
+				continue
+			}
+			found = true
+			if len(opt.Type) > 0 && ph.Type != opt.Type {
+				errs = append(errs, fmt.Errorf("Event %s/%s: placeholder %s has type %s, expected %s", e.Bee, e.Name, ph.Name, ph.Type, opt.Type))
+			}
+		}
+		if !found && opt.Mandatory {
+			errs = append(errs, fmt.Errorf("Event %s/%s: missing placeholder %s", e.Bee, e.Name, opt.Name))
+		}
+	}
+	for _, ph := range e.Options {
+		if !schema.has(ph.Name) {
+			errs = append(errs, fmt.Errorf("Event %s/%s: unknown placeholder %s", e.Bee, e.Name, ph.Name))
+		}
+	}
+
+	return errs
+}
+
+func (schema EventSchema) has(name string) bool {
+	for _, opt := range schema.Options {
+		if opt.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidateChain checks a chain's mode, the options of its actions, including
+// its OnError actions, against their ActionDescriptors, see ValidateAction, and the placeholders referenced
+// by its filters against the schema registered for the chain's event. Chains
+// whose event has no registered schema have valid filters, as do chains
+// triggered by multiple events, whose filters may refer to placeholders of
+// either.
+func ValidateChain(c Chain) []error {
+	var errs []error
+	if c.Mode != "" && c.Mode != SequentialMode && c.Mode != ParallelMode {
+		errs = append(errs, fmt.Errorf("Chain %s: unknown mode %s", c.Name, c.Mode))
+	}
+	if c.Correlation != nil {
+		if len(c.Correlation.Events) == 0 {
+			errs = append(errs, fmt.Errorf("Chain %s: correlation without events", c.Name))
+		}
+		if c.Correlation.Window <= 0 {
+			errs = append(errs, fmt.Errorf("Chain %s: correlation needs a window", c.Name))
+		}
+	}
+	for _, id := range append(append([]string{}, c.Actions...), c.OnError...) {
+		if a := GetAction(id); a != nil {
+			for _, err := range ValidateAction(*a) {
+				errs = append(errs, fmt.Errorf("Chain %s: %v", c.Name, err))
+			}
+		}
+	}
+	if len(c.Schedule) > 0 {
+		if _, err := cron.ParseStandard(c.Schedule); err != nil {
+			errs = append(errs, fmt.Errorf("Chain %s: invalid schedule: %v", c.Name, err))
+		}
+	}
+	for _, w := range c.Windows {
+		if err := w.validate(); err != nil {
+			errs = append(errs, fmt.Errorf("Chain %s: invalid window: %v", c.Name, err))
+		}
+	}
+	if c.OutsideWindow != "" && c.OutsideWindow != DropOutsideWindow && c.OutsideWindow != DeferOutsideWindow {
+		errs = append(errs, fmt.Errorf("Chain %s: unknown OutsideWindow behavior %s", c.Name, c.OutsideWindow))
+	}
+	for _, e := range c.Enrich {
+		if err := ValidateEnrichment(e); err != nil {
+			errs = append(errs, fmt.Errorf("Chain %s: %v", c.Name, err))
+		}
+	}
+	for _, t := range c.Transform {
+		if err := ValidateTransformation(t); err != nil {
+			errs = append(errs, fmt.Errorf("Chain %s: %v", c.Name, err))
+		}
+	}
+	filters := c.filters()
+	for _, f := range filters.expressions() {
+		if err := validateFilter(f); err != nil {
+			errs = append(errs, fmt.Errorf("Chain %s: %v", c.Name, err))
+		}
+	}
+
+	if c.Event == nil || len(c.Events) > 0 {
+		return errs
+	}
+	schema, ok := eventSchema(c.Event.Bee, c.Event.Name)
+	if !ok {
+		return errs
+	}
+
+	for _, f := range c.Filters {
+		for _, name := range placeholderRefs(f) {
+			if !schema.has(name) && !enriched(c, name) {
+				errs = append(errs, fmt.Errorf("Chain %s: filter references unknown placeholder %s of event %s/%s", c.Name, name, c.Event.Bee, c.Event.Name))
+			}
+		}
+	}
+
+	return errs
+}
+
+// ValidateAction checks the options of an action against the descriptor of
+// the action provided by its bee: mandatory options must be present, unknown
+// options are reported, and values have to match the declared types. Values
+// containing templates only get checked once rendered, when the action gets
+// executed. Actions of bees which aren't registered, or which don't describe
+// their actions at all, are always valid.
+func ValidateAction(a Action) []error {
+	bee := GetBee(a.Bee)
+	if bee == nil {
+		return nil
+	}
+	descs := beeDescriptorsOf(bee)
+	if len(descs.actions) == 0 {
+		return nil
+	}
+
+	desc, ok := descs.action(a.Name)
+	if !ok {
+		return []error{fmt.Errorf("Action %s: bee %s has no action %s", a.ID, a.Bee, a.Name)}
+	}
+
+	var errs []error
+	for _, opt := range desc.Options {
+		v := a.Options.Value(opt.Name)
+		if v == nil {
+			if opt.Mandatory {
+				errs = append(errs, fmt.Errorf("Action %s: missing option %s", a.ID, opt.Name))
+			}
+			continue
+		}
+		if s, ok := v.(string); ok && strings.Contains(s, "{{") {
+			continue
+		}
+
+		var err error
+		if t, ok := GetOptionType(opt.Type); ok {
+			_, err = t.Parse(v)
+		} else {
+			_, err = coerceOption(opt.Type, v)
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("Action %s: invalid value for option %s: %v", a.ID, opt.Name, err))
+		}
+	}
+	for _, ph := range a.Options {
+		known := false
+		for _, opt := range desc.Options {
+			known = known || opt.Name == ph.Name
+		}
+		if !known {
+			errs = append(errs, fmt.Errorf("Action %s: unknown option %s", a.ID, ph.Name))
+		}
+	}
+
+	return errs
+}
+
+// placeholderRefs returns the names of the placeholders referenced in a
+// template.
+func placeholderRefs(tmpl string) []string {
+	var names []string
+	for _, action := range templateAction.FindAllStringSubmatch(tmpl, -1) {
+		for _, ref := range placeholderRef.FindAllStringSubmatch(action[1], -1) {
+			names = append(names, ref[1])
+		}
+	}
+	return names
+}
+
+// warnInvalidChains logs the problems ValidateChain finds with cs.
+func warnInvalidChains(cs []Chain) {
+	for _, c := range cs {
+		for _, err := range ValidateChain(c) {
+			logger.Warnf("%v", err)
+		}
+	}
+}
@@ -0,0 +1,104 @@
+package bees
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidateEvent(t *testing.T) {
+	newRecordingBee("schemabee")
+	defer DeleteBee(GetBee("schemabee"))
+	RegisterEventSchema("recordingbee", "message", EventSchema{Options: []PlaceholderDescriptor{
+		{Name: "text", Type: "string", Mandatory: true},
+		{Name: "channel", Type: "string"},
+	}})
+
+	valid := Event{Bee: "schemabee", Name: "message", Options: Placeholders{{Name: "text", Type: "string", Value: "hi"}}}
+	if errs := ValidateEvent(valid); len(errs) != 0 {
+		t.Errorf("Expected event to be valid, got %v", errs)
+	}
+
+	invalid := Event{Bee: "schemabee", Name: "message", Options: Placeholders{
+		{Name: "channel", Type: "int", Value: 5},
+		{Name: "txt", Type: "string", Value: "hi"},
+	}}
+	errs := ValidateEvent(invalid)
+	if len(errs) != 3 {
+		t.Fatalf("Expected 3 errors, got %v", errs)
+	}
+	for i, s := range []string{"missing placeholder text", "type int", "unknown placeholder txt"} {
+		if !strings.Contains(errs[i].Error(), s) {
+			t.Errorf("Expected error %q, got %v", s, errs[i])
+		}
+	}
+
+	if errs := ValidateEvent(Event{Bee: "schemabee", Name: "other"}); len(errs) != 0 {
+		t.Errorf("Expected events without schema to be valid, got %v", errs)
+	}
+}
+
+func TestValidateChain(t *testing.T) {
+	newRecordingBee("schemachainbee")
+	defer DeleteBee(GetBee("schemachainbee"))
+	RegisterEventSchema("recordingbee", "alert", EventSchema{Options: []PlaceholderDescriptor{
+		{Name: "text", Type: "string"},
+	}})
+
+	c := Chain{
+		Name:    "schemachain",
+		Event:   &Event{Bee: "schemachainbee", Name: "alert"},
+		Filters: []string{`{{test HasPrefix .text "alert"}}`, `{{test eq .user.name "bob"}}`},
+	}
+	errs := ValidateChain(c)
+	if len(errs) != 1 || !strings.Contains(errs[0].Error(), "unknown placeholder user") {
+		t.Errorf("Expected an error for placeholder user, got %v", errs)
+	}
+}
+
+func TestValidateAction(t *testing.T) {
+	newRecordingBee("describedbee")
+	defer DeleteBee(GetBee("describedbee"))
+	instanceDescriptorsMutex.Lock()
+	instanceDescriptors["describedbee"] = newBeeDescriptors(nil, []ActionDescriptor{
+		{Name: "send", Options: []PlaceholderDescriptor{
+			{Name: "text", Type: "string", Mandatory: true},
+			{Name: "retries", Type: "int"},
+			{Name: "color", Type: "color"},
+		}},
+	})
+	instanceDescriptorsMutex.Unlock()
+
+	valid := Action{ID: "valid", Bee: "describedbee", Name: "send", Options: Placeholders{
+		{Name: "text", Value: "{{.user}} said {{.text}}"},
+		{Name: "retries", Value: float64(3)},
+		{Name: "color", Value: "#ff0000"},
+	}}
+	if errs := ValidateAction(valid); len(errs) != 0 {
+		t.Errorf("Expected a valid action, got %v", errs)
+	}
+
+	invalid := Action{ID: "invalid", Bee: "describedbee", Name: "send", Options: Placeholders{
+		{Name: "retries", Value: "many"},
+		{Name: "color", Value: "red"},
+		{Name: "colour", Value: "#ff0000"},
+	}}
+	errs := ValidateAction(invalid)
+	if len(errs) != 4 {
+		t.Fatalf("Expected 4 errors, got %v", errs)
+	}
+	for i, exp := range []string{"missing option text", "option retries", "option color", "unknown option colour"} {
+		if !strings.Contains(errs[i].Error(), exp) {
+			t.Errorf("Expected error %q, got %v", exp, errs[i])
+		}
+	}
+	if errs := ValidateAction(Action{ID: "other", Bee: "describedbee", Name: "launch"}); len(errs) != 1 {
+		t.Errorf("Expected an error for an unknown action, got %v", errs)
+	}
+
+	oldActions := GetActions()
+	defer SetActions(oldActions)
+	SetActions([]Action{invalid})
+	if errs := ValidateChain(Chain{Name: "sender", Actions: []string{"invalid"}}); len(errs) != 4 {
+		t.Errorf("Expected the chain to report its action's errors, got %v", errs)
+	}
+}
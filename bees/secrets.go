@@ -0,0 +1,225 @@
+/*
+ *    Copyright (C) 2026 Christian Muehlhaeuser
+ *
+ *    This program is free software: you can redistribute it and/or modify
+ *    it under the terms of the GNU Affero General Public License as published
+ *    by the Free Software Foundation, either version 3 of the License, or
+ *    (at your option) any later version.
+ *
+ *    This program is distributed in the hope that it will be useful,
+ *    but WITHOUT ANY WARRANTY; without even the implied warranty of
+ *    MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ *    GNU Affero General Public License for more details.
+ *
+ *    You should have received a copy of the GNU Affero General Public License
+ *    along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ *    Authors:
+ *      Christian Muehlhaeuser <muesli@gmail.com>
+ */
+
+// Package bees is Beehive's central module system.
+package bees
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"golang.org/x/crypto/scrypt"
+)
+
+// SecretKeyEnvVar is the environment variable beehive reads the passphrase
+// for encrypting password options from.
+const SecretKeyEnvVar = "BEEHIVE_SECRET_KEY"
+
+// SecretPrefix marks option values that are encrypted with the secret key.
+const SecretPrefix = "encrypted:"
+
+// secretSalt is the fixed salt the secret key gets derived with, so the same
+// passphrase always yields the same key.
+var secretSalt = []byte("beehive secret options")
+
+// minRedactedLength is the minimum length of secret values that get redacted
+// from log messages. Shorter values would redact too many unrelated words.
+const minRedactedLength = 4
+
+var (
+	secretAEAD  cipher.AEAD
+	beeSecrets  = make(map[string][]string)
+	secretMutex sync.RWMutex
+)
+
+// SetSecretKey sets the passphrase password options get encrypted with. Once
+// set, password options are stored encrypted, see EncryptOptions, and
+// encrypted values get decrypted when a bee's options get resolved. An empty
+// passphrase disables encrypting options.
+func SetSecretKey(passphrase string) error {
+	var aead cipher.AEAD
+	if passphrase != "" {
+		key, err := scrypt.Key([]byte(passphrase), secretSalt, 32768, 8, 1, 32)
+		if err != nil {
+			return err
+		}
+		block, err := aes.NewCipher(key)
+		if err != nil {
+			return err
+		}
+		aead, err = cipher.NewGCM(block)
+		if err != nil {
+			return err
+		}
+	}
+
+	secretMutex.Lock()
+	defer secretMutex.Unlock()
+	secretAEAD = aead
+	return nil
+}
+
+// isEncrypted returns whether an option value is encrypted.
+func isEncrypted(v interface{}) bool {
+	s, ok := v.(string)
+	return ok && strings.HasPrefix(s, SecretPrefix)
+}
+
+// encryptSecret encrypts a value with the secret key.
+func encryptSecret(s string) (string, error) {
+	secretMutex.RLock()
+	aead := secretAEAD
+	secretMutex.RUnlock()
+	if aead == nil {
+		return "", errors.New("No secret key set")
+	}
+
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+
+	return SecretPrefix + base64.StdEncoding.EncodeToString(aead.Seal(nonce, nonce, []byte(s), nil)), nil
+}
+
+// decryptSecret decrypts a value encrypted by encryptSecret.
+func decryptSecret(s string) (string, error) {
+	secretMutex.RLock()
+	aead := secretAEAD
+	secretMutex.RUnlock()
+	if aead == nil {
+		return "", errors.New("Encrypted value, but no secret key set")
+	}
+
+	b, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(s, SecretPrefix))
+	if err != nil || len(b) < aead.NonceSize() {
+		return "", errors.New("Malformed encrypted value")
+	}
+	plain, err := aead.Open(nil, b[:aead.NonceSize()], b[aead.NonceSize():], nil)
+	if err != nil {
+		return "", errors.New("Can't decrypt value, wrong secret key?")
+	}
+
+	return string(plain), nil
+}
+
+// EncryptOptions returns a copy of the options of a bee of the given class,
+// with the values of its password options encrypted. Without a secret key,
+// see SetSecretKey, the options are returned unchanged.
+func EncryptOptions(class string, options BeeOptions) (BeeOptions, error) {
+	secretMutex.RLock()
+	enabled := secretAEAD != nil
+	secretMutex.RUnlock()
+	if !enabled {
+		return options, nil
+	}
+
+	secrets := secretOptions(class)
+	encrypted := BeeOptions{}
+	for _, opt := range options {
+		if s, ok := opt.Value.(string); ok && secrets[opt.Name] && s != "" && !isEncrypted(s) {
+			if _, ref := reference(s); !ref {
+				v, err := encryptSecret(s)
+				if err != nil {
+					return nil, fmt.Errorf("Option %s: %v", opt.Name, err)
+				}
+				opt.Value = v
+			}
+		}
+		encrypted = append(encrypted, opt)
+	}
+
+	return encrypted, nil
+}
+
+// hasSecrets returns whether any of the options are encrypted.
+func hasSecrets(options BeeOptions) bool {
+	for _, opt := range options {
+		if isEncrypted(opt.Value) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// setBeeSecrets remembers the values of a bee's password and encrypted
+// options, so they can be redacted from its log messages. options are the
+// bee's unresolved options, resolved the ones handed to the bee.
+func setBeeSecrets(bee, class string, options, resolved BeeOptions) {
+	names := secretOptions(class)
+	for _, opt := range options {
+		if isEncrypted(opt.Value) {
+			names[opt.Name] = true
+		}
+	}
+
+	var values []string
+	for _, opt := range resolved {
+		if s, ok := opt.Value.(string); ok && names[opt.Name] && len(s) >= minRedactedLength {
+			values = append(values, s)
+		}
+	}
+
+	secretMutex.Lock()
+	defer secretMutex.Unlock()
+	if len(values) > 0 {
+		beeSecrets[bee] = values
+	} else {
+		delete(beeSecrets, bee)
+	}
+}
+
+// redactSecrets replaces the secret option values of a bee in a log message
+// with PasswordMask.
+func redactSecrets(bee, message string) string {
+	secretMutex.RLock()
+	defer secretMutex.RUnlock()
+
+	for _, s := range beeSecrets[bee] {
+		message = strings.Replace(message, s, PasswordMask, -1)
+	}
+	return message
+}
+
+// renameBeeSecrets moves the secrets of a renamed bee to its new name.
+func renameBeeSecrets(old, name string) {
+	secretMutex.Lock()
+	defer secretMutex.Unlock()
+
+	if values, ok := beeSecrets[old]; ok {
+		beeSecrets[name] = values
+		delete(beeSecrets, old)
+	}
+}
+
+// deleteBeeSecrets forgets the secrets of a deleted bee.
+func deleteBeeSecrets(name string) {
+	secretMutex.Lock()
+	defer secretMutex.Unlock()
+
+	delete(beeSecrets, name)
+}
@@ -0,0 +1,62 @@
+package bees
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSecretOptions(t *testing.T) {
+	if err := SetSecretKey("correct horse battery staple"); err != nil {
+		t.Fatal(err)
+	}
+	defer SetSecretKey("")
+	oldActions := GetActions()
+	defer SetActions(oldActions)
+	oldChains := chains
+	defer func() { chains = oldChains }()
+	defer StopBees()
+
+	errs := StartBees([]BeeConfig{
+		{Name: "secret-irc", Class: "typedbee", Options: BeeOptions{
+			{Name: "server", Value: "irc://irc.example.com"},
+			{Name: "password", Value: "s3cr3t-token"},
+		}},
+	})
+	if len(errs) > 0 {
+		t.Fatal(errs)
+	}
+
+	c, err := ExportConfig(false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	encrypted, _ := c.Bees[0].Options.Value("password").(string)
+	if !strings.HasPrefix(encrypted, SecretPrefix) {
+		t.Fatalf("Expected an encrypted password, got %v", c.Bees[0].Options.Value("password"))
+	}
+
+	if err := ImportConfig(c); err != nil {
+		t.Fatal(err)
+	}
+	if v := (*GetBee("secret-irc")).Options().Value("password"); v != "s3cr3t-token" {
+		t.Errorf("Expected password to be decrypted, got %v", v)
+	}
+	if v := BeeConfigs()[0].Options.Value("password"); v != encrypted {
+		t.Errorf("Expected the encrypted password to be kept, got %v", v)
+	}
+
+	logBee("secret-irc", "typedbee", LogInfo, "Logging in with s3cr3t-token")
+	logs := GetLogs("secret-irc")
+	if msg := logs[len(logs)-1].Message; strings.Contains(msg, "s3cr3t-token") {
+		t.Errorf("Expected password to be redacted from logs, got %q", msg)
+	}
+
+	SetSecretKey("wrong key")
+	if _, err := ResolveOptions(c.Bees[0].Options); err == nil {
+		t.Error("Expected decrypting with the wrong key to fail")
+	}
+	SetSecretKey("")
+	if _, err := ResolveOptions(c.Bees[0].Options); err == nil {
+		t.Error("Expected decrypting without a key to fail")
+	}
+}
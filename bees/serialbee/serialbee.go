@@ -23,6 +23,7 @@ package serialbee
 
 import (
 	"bytes"
+	"context"
 	"encoding/binary"
 	"io"
 
@@ -42,7 +43,7 @@ type SerialBee struct {
 }
 
 // Action triggers the action passed to it.
-func (mod *SerialBee) Action(action bees.Action) []bees.Placeholder {
+func (mod *SerialBee) Action(ctx context.Context, action bees.Action) []bees.Placeholder {
 	outs := []bees.Placeholder{}
 	text := ""
 
@@ -107,7 +108,7 @@ func (mod *SerialBee) handleEvents(eventChan chan bees.Event) error {
 }
 
 // Run executes the Bee's event loop.
-func (mod *SerialBee) Run(eventChan chan bees.Event) {
+func (mod *SerialBee) Run(ctx context.Context, eventChan chan bees.Event) {
 	if mod.baudrate == 0 || mod.device == "" {
 		return
 	}
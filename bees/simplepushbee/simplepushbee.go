@@ -22,6 +22,8 @@
 package simplepushbee
 
 import (
+	"context"
+
 	"github.com/muesli/beehive/bees"
 	"github.com/simplepush/simplepush-go"
 )
@@ -36,7 +38,7 @@ type SimplepushBee struct {
 }
 
 // Action triggers the action passed to it.
-func (mod *SimplepushBee) Action(action bees.Action) []bees.Placeholder {
+func (mod *SimplepushBee) Action(ctx context.Context, action bees.Action) []bees.Placeholder {
 	outs := []bees.Placeholder{}
 
 	switch action.Name {
@@ -24,9 +24,14 @@
 package slackbee
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
 	"io/ioutil"
 	"os"
 	"strings"
+	"sync"
 
 	"github.com/nlopes/slack"
 
@@ -40,44 +45,138 @@ type SlackBee struct {
 	client   *slack.Client
 	channels map[string]string
 	apiKey   string
+
+	// names caches the names of users and channels, keyed by their IDs
+	names      map[string]string
+	namesMutex sync.Mutex
 }
 
-// Action triggers the action passed to it.
-func (mod *SlackBee) Action(action bees.Action) []bees.Placeholder {
+// ActionE triggers the action passed to it.
+func (mod *SlackBee) ActionE(ctx context.Context, action bees.Action) ([]bees.Placeholder, error) {
 	outs := []bees.Placeholder{}
 
 	switch action.Name {
 	case "send":
 		tos := []string{}
-		text := ""
-		action.Options.Bind("text", &text)
-
 		for _, opt := range action.Options {
 			if opt.Name == "channel" {
-				cid := mod.findChannelID(opt.Value.(string), false)
-				if cid != "" {
-					tos = append(tos, cid)
-				} else {
-					mod.Logf("Error: channel ID for %s not found", opt.Value.(string))
+				name := fmt.Sprint(opt.Value)
+				cid := mod.findChannelID(name, false)
+				if cid == "" {
+					return outs, fmt.Errorf("Channel ID for %s not found", name)
 				}
+				tos = append(tos, cid)
 			}
 		}
 
-		msgParams := slack.NewPostMessageParameters()
-		msgParams.LinkNames = 1
+		msgOpts, err := messageOptions(action.Options)
+		if err != nil {
+			return outs, err
+		}
 		for _, to := range tos {
-			_, _, err := mod.client.PostMessage(to, slack.MsgOptionText(text, false), slack.MsgOptionPostMessageParameters(msgParams))
+			channel, ts, err := mod.client.PostMessage(to, msgOpts...)
 			if err != nil {
-				mod.Logf("Error posting message to the slack channel %s: %s", to, err)
+				return outs, fmt.Errorf("Error posting message to the slack channel %s: %s", to, err)
 			}
+			outs = append(outs, bees.Placeholder{Name: "channel", Type: "string", Value: channel},
+				bees.Placeholder{Name: "ts", Type: "string", Value: ts})
 		}
+
+	case "dm":
+		var user string
+		action.Options.Bind("user", &user)
+
+		uid, err := mod.findUserID(user)
+		if err != nil {
+			return outs, err
+		}
+		_, _, cid, err := mod.client.OpenIMChannel(uid)
+		if err != nil {
+			return outs, fmt.Errorf("Can't open a direct message channel to %s: %s", user, err)
+		}
+
+		msgOpts, err := messageOptions(action.Options)
+		if err != nil {
+			return outs, err
+		}
+		channel, ts, err := mod.client.PostMessage(cid, msgOpts...)
+		if err != nil {
+			return outs, fmt.Errorf("Error sending a direct message to %s: %s", user, err)
+		}
+		outs = append(outs, bees.Placeholder{Name: "channel", Type: "string", Value: channel},
+			bees.Placeholder{Name: "ts", Type: "string", Value: ts})
+
+	case "react":
+		var channel, ts, reaction string
+		action.Options.Bind("channel", &channel)
+		action.Options.Bind("ts", &ts)
+		action.Options.Bind("reaction", &reaction)
+
+		cid := mod.findChannelID(channel, false)
+		if cid == "" {
+			return outs, fmt.Errorf("Channel ID for %s not found", channel)
+		}
+		reaction = strings.Trim(reaction, ":")
+		if err := mod.client.AddReaction(reaction, slack.NewRefToMessage(cid, ts)); err != nil {
+			return outs, fmt.Errorf("Error adding reaction %s: %s", reaction, err)
+		}
+
 	default:
 		panic("Unknown action triggered in " + mod.Name() + ": " + action.Name)
 	}
 
+	return outs, nil
+}
+
+// Action triggers the action passed to it.
+func (mod *SlackBee) Action(ctx context.Context, action bees.Action) []bees.Placeholder {
+	outs, err := mod.ActionE(ctx, action)
+	if err != nil {
+		mod.LogErrorf("Error: %v", err)
+	}
 	return outs
 }
 
+// messageOptions returns the message options of a send or dm action. Blocks
+// and attachments are expected in the JSON format of Slack's API.
+func messageOptions(opts bees.Placeholders) ([]slack.MsgOption, error) {
+	var text, blocks, attachments, threadTS string
+	opts.Bind("text", &text)
+	opts.Bind("blocks", &blocks)
+	opts.Bind("attachments", &attachments)
+	opts.Bind("thread_ts", &threadTS)
+
+	msgParams := slack.NewPostMessageParameters()
+	msgParams.LinkNames = 1
+	msgOpts := []slack.MsgOption{
+		slack.MsgOptionText(text, false),
+		slack.MsgOptionPostMessageParameters(msgParams),
+	}
+
+	if len(blocks) > 0 {
+		var b slack.Blocks
+		if err := json.Unmarshal([]byte(blocks), &b); err != nil {
+			return nil, fmt.Errorf("Invalid blocks: %s", err)
+		}
+		msgOpts = append(msgOpts, slack.MsgOptionBlocks(b.BlockSet...))
+	}
+	if len(attachments) > 0 {
+		var a []slack.Attachment
+		if err := json.Unmarshal([]byte(attachments), &a); err != nil {
+			return nil, fmt.Errorf("Invalid attachments: %s", err)
+		}
+		msgOpts = append(msgOpts, slack.MsgOptionAttachments(a...))
+	}
+	if len(threadTS) > 0 {
+		msgOpts = append(msgOpts, slack.MsgOptionTS(threadTS))
+	}
+	if len(text) == 0 && len(blocks) == 0 && len(attachments) == 0 {
+		return nil, errors.New("Message needs a text, blocks or attachments")
+	}
+
+	return msgOpts, nil
+}
+
 func stringInMap(a string, list map[string]string) bool {
 	for _, v := range list {
 		if v == a {
@@ -87,12 +186,23 @@ func stringInMap(a string, list map[string]string) bool {
 	return false
 }
 
+// listening returns whether the bee emits events for a channel: one of the
+// configured channels or a direct message channel.
+func (mod *SlackBee) listening(channel string) bool {
+	return strings.HasPrefix(channel, "D") || stringInMap(channel, mod.channels)
+}
+
 func (mod *SlackBee) findChannelID(name string, cache bool) string {
+	name = strings.TrimPrefix(name, "#")
 	cid := mod.channels[name]
 
 	if cid != "" {
 		return cid
 	}
+	if stringInMap(name, mod.channels) || strings.HasPrefix(name, "D") && strings.ToUpper(name) == name {
+		// already an ID, e.g. from the channel placeholder of an event
+		return name
+	}
 
 	channels, err := mod.client.GetChannels(true)
 	if err != nil {
@@ -100,7 +210,7 @@ func (mod *SlackBee) findChannelID(name string, cache bool) string {
 	}
 
 	for _, ch := range channels {
-		if ch.Name == name {
+		if ch.Name == name || ch.ID == name {
 			cid = ch.ID
 		}
 	}
@@ -111,7 +221,7 @@ func (mod *SlackBee) findChannelID(name string, cache bool) string {
 		panic(err)
 	}
 	for _, grp := range groups {
-		if grp.Name == name {
+		if grp.Name == name || grp.ID == name {
 			cid = grp.ID
 		}
 	}
@@ -124,33 +234,118 @@ func (mod *SlackBee) findChannelID(name string, cache bool) string {
 	return cid
 }
 
-func sendEvent(bee string, channel string, user string, text string, eventChan chan bees.Event) {
+// findUserID returns the ID of a user, given either the user's ID or name.
+func (mod *SlackBee) findUserID(user string) (string, error) {
+	user = strings.TrimPrefix(user, "@")
+	if u, err := mod.client.GetUserInfo(user); err == nil {
+		return u.ID, nil
+	}
+
+	users, err := mod.client.GetUsers()
+	if err != nil {
+		return "", err
+	}
+	for _, u := range users {
+		if u.Name == user || u.Profile.DisplayName == user {
+			return u.ID, nil
+		}
+	}
+	return "", fmt.Errorf("User %s not found", user)
+}
+
+// userName returns the name of the user with a specific ID.
+func (mod *SlackBee) userName(id string) string {
+	return mod.cachedName(id, func() (string, error) {
+		u, err := mod.client.GetUserInfo(id)
+		if err != nil {
+			return "", err
+		}
+		return u.Name, nil
+	})
+}
+
+// channelName returns the name of the channel with a specific ID.
+func (mod *SlackBee) channelName(id string) string {
+	for name, cid := range mod.channels {
+		if cid == id {
+			return name
+		}
+	}
+
+	return mod.cachedName(id, func() (string, error) {
+		ch, err := mod.client.GetConversationInfo(id, false)
+		if err != nil {
+			return "", err
+		}
+		return ch.Name, nil
+	})
+}
+
+func (mod *SlackBee) cachedName(id string, lookup func() (string, error)) string {
+	if len(id) == 0 {
+		return ""
+	}
+
+	mod.namesMutex.Lock()
+	defer mod.namesMutex.Unlock()
+	if name, ok := mod.names[id]; ok {
+		return name
+	}
+
+	// failed lookups get cached too, e.g. for bots posting under a username
+	name, err := lookup()
+	if err != nil {
+		mod.LogDebugf("Can't look up name of %s: %s", id, err)
+	}
+	mod.names[id] = name
+	return name
+}
+
+func (mod *SlackBee) sendEvent(name string, channel string, user string, eventChan chan bees.Event, opts ...bees.Placeholder) {
 	event := bees.Event{
-		Bee:  bee,
-		Name: "message",
+		Bee:  mod.Name(),
+		Name: name,
 		Options: []bees.Placeholder{
 			{
 				Name:  "channel",
 				Type:  "string",
 				Value: channel,
 			},
+			{
+				Name:  "channel_name",
+				Type:  "string",
+				Value: mod.channelName(channel),
+			},
 			{
 				Name:  "user",
 				Type:  "string",
 				Value: user,
 			},
 			{
-				Name:  "text",
+				Name:  "user_name",
 				Type:  "string",
-				Value: text,
+				Value: mod.userName(user),
 			},
 		},
 	}
-	eventChan <- event
+	event.Options = append(event.Options, opts...)
+	select {
+	case eventChan <- event:
+	case <-mod.Context().Done():
+	}
+}
+
+func (mod *SlackBee) sendMessageEvent(ev *slack.MessageEvent, text string, eventChan chan bees.Event) {
+	mod.sendEvent("message", ev.Channel, ev.Msg.User, eventChan,
+		bees.Placeholder{Name: "text", Type: "string", Value: text},
+		bees.Placeholder{Name: "ts", Type: "string", Value: ev.Msg.Timestamp},
+		bees.Placeholder{Name: "thread_ts", Type: "string", Value: ev.Msg.ThreadTimestamp},
+		bees.Placeholder{Name: "direct", Type: "bool", Value: strings.HasPrefix(ev.Channel, "D")},
+	)
 }
 
 // Run executes the Bee's event loop.
-func (mod *SlackBee) Run(eventChan chan bees.Event) {
+func (mod *SlackBee) Run(ctx context.Context, eventChan chan bees.Event) {
 	rtm := mod.client.NewRTM()
 	defer rtm.Disconnect()
 
@@ -169,20 +364,34 @@ func (mod *SlackBee) Run(eventChan chan bees.Event) {
 		case msg := <-rtm.IncomingEvents:
 			switch ev := msg.Data.(type) {
 			case *slack.MessageEvent:
-				if stringInMap(ev.Channel, mod.channels) {
-					u := ev.Msg.User
-					if u == "" {
-						u = ev.Msg.Username
+				if mod.listening(ev.Channel) {
+					if ev.Msg.User == "" {
+						// bots post under a username instead of a user ID
+						ev.Msg.User = ev.Msg.Username
 					}
 					t := ev.Msg.Text
 					if t == "" {
 						for _, v := range ev.Msg.Attachments {
-							sendEvent(mod.Name(), ev.Channel, u, v.Text, eventChan)
+							mod.sendMessageEvent(ev, v.Text, eventChan)
 						}
 					} else {
-						sendEvent(mod.Name(), ev.Channel, u, t, eventChan)
+						mod.sendMessageEvent(ev, t, eventChan)
 					}
 				}
+			case *slack.ReactionAddedEvent:
+				if mod.listening(ev.Item.Channel) {
+					mod.sendEvent("reaction", ev.Item.Channel, ev.User, eventChan,
+						bees.Placeholder{Name: "reaction", Type: "string", Value: ev.Reaction},
+						bees.Placeholder{Name: "item_user", Type: "string", Value: ev.ItemUser},
+						bees.Placeholder{Name: "ts", Type: "string", Value: ev.Item.Timestamp},
+					)
+				}
+			case *slack.MemberJoinedChannelEvent:
+				if mod.listening(ev.Channel) {
+					mod.sendEvent("channel_join", ev.Channel, ev.User, eventChan,
+						bees.Placeholder{Name: "inviter", Type: "string", Value: ev.Inviter},
+					)
+				}
 			case *slack.RTMError:
 				mod.LogErrorf("Error: %s", ev.Error())
 			case *slack.InvalidAuthEvent:
@@ -215,6 +424,7 @@ func (mod *SlackBee) ReloadOptions(options bees.BeeOptions) {
 		}
 	}
 
+	mod.names = map[string]string{}
 	mod.apiKey = apiKey
 	mod.client = client
 }
@@ -75,7 +75,7 @@ func (factory *SlackBeeFactory) Options() []bees.BeeOptionDescriptor {
 		},
 		{
 			Name:        "channels",
-			Description: "Slack channels to listen on",
+			Description: "Slack channels to listen on, direct messages are always received",
 			Type:        "[]string",
 			Mandatory:   false,
 		},
@@ -98,12 +98,111 @@ func (factory *SlackBeeFactory) Events() []bees.EventDescriptor {
 				},
 				{
 					Name:        "channel",
-					Description: "The channel the message was received in",
+					Description: "ID of the channel the message was received in",
+					Type:        "string",
+				},
+				{
+					Name:        "channel_name",
+					Description: "Name of the channel",
+					Type:        "string",
+				},
+				{
+					Name:        "user",
+					Description: "ID of the user that sent the message",
+					Type:        "string",
+				},
+				{
+					Name:        "user_name",
+					Description: "Name of the user",
+					Type:        "string",
+				},
+				{
+					Name:        "ts",
+					Description: "Timestamp identifying the message",
+					Type:        "string",
+				},
+				{
+					Name:        "thread_ts",
+					Description: "Timestamp of the thread's parent message, if the message is a reply",
+					Type:        "string",
+				},
+				{
+					Name:        "direct",
+					Description: "Whether the message is a direct message",
+					Type:        "bool",
+				},
+			},
+		},
+		{
+			Namespace:   factory.Name(),
+			Name:        "reaction",
+			Description: "A reaction was added to a message",
+			Options: []bees.PlaceholderDescriptor{
+				{
+					Name:        "channel",
+					Description: "ID of the channel",
+					Type:        "string",
+				},
+				{
+					Name:        "channel_name",
+					Description: "Name of the channel",
+					Type:        "string",
+				},
+				{
+					Name:        "user",
+					Description: "ID of the user that reacted",
+					Type:        "string",
+				},
+				{
+					Name:        "user_name",
+					Description: "Name of the user",
+					Type:        "string",
+				},
+				{
+					Name:        "reaction",
+					Description: "Name of the reaction's emoji",
+					Type:        "string",
+				},
+				{
+					Name:        "item_user",
+					Description: "ID of the user that posted the message",
+					Type:        "string",
+				},
+				{
+					Name:        "ts",
+					Description: "Timestamp identifying the message",
+					Type:        "string",
+				},
+			},
+		},
+		{
+			Namespace:   factory.Name(),
+			Name:        "channel_join",
+			Description: "A user joined a channel",
+			Options: []bees.PlaceholderDescriptor{
+				{
+					Name:        "channel",
+					Description: "ID of the channel",
+					Type:        "string",
+				},
+				{
+					Name:        "channel_name",
+					Description: "Name of the channel",
 					Type:        "string",
 				},
 				{
 					Name:        "user",
-					Description: "The user that sent the message",
+					Description: "ID of the user that joined",
+					Type:        "string",
+				},
+				{
+					Name:        "user_name",
+					Description: "Name of the user",
+					Type:        "string",
+				},
+				{
+					Name:        "inviter",
+					Description: "ID of the user that invited the user, if any",
 					Type:        "string",
 				},
 			},
@@ -130,6 +229,78 @@ func (factory *SlackBeeFactory) Actions() []bees.ActionDescriptor {
 					Name:        "text",
 					Description: "Content of the message",
 					Type:        "string",
+				},
+				{
+					Name:        "blocks",
+					Description: "Layout blocks of the message, in JSON format",
+					Type:        "string",
+				},
+				{
+					Name:        "attachments",
+					Description: "Attachments of the message, in JSON format",
+					Type:        "string",
+				},
+				{
+					Name:        "thread_ts",
+					Description: "Timestamp of the message to reply to in its thread",
+					Type:        "string",
+				},
+			},
+		},
+		{
+			Namespace:   factory.Name(),
+			Name:        "dm",
+			Description: "Sends a direct message to a user",
+			Options: []bees.PlaceholderDescriptor{
+				{
+					Name:        "user",
+					Description: "ID or name of the user to send the message to",
+					Type:        "string",
+					Mandatory:   true,
+				},
+				{
+					Name:        "text",
+					Description: "Content of the message",
+					Type:        "string",
+				},
+				{
+					Name:        "blocks",
+					Description: "Layout blocks of the message, in JSON format",
+					Type:        "string",
+				},
+				{
+					Name:        "attachments",
+					Description: "Attachments of the message, in JSON format",
+					Type:        "string",
+				},
+				{
+					Name:        "thread_ts",
+					Description: "Timestamp of the message to reply to in its thread",
+					Type:        "string",
+				},
+			},
+		},
+		{
+			Namespace:   factory.Name(),
+			Name:        "react",
+			Description: "Adds a reaction to a message",
+			Options: []bees.PlaceholderDescriptor{
+				{
+					Name:        "channel",
+					Description: "The channel the message was posted in",
+					Type:        "string",
+					Mandatory:   true,
+				},
+				{
+					Name:        "ts",
+					Description: "Timestamp identifying the message",
+					Type:        "string",
+					Mandatory:   true,
+				},
+				{
+					Name:        "reaction",
+					Description: "Name of the reaction's emoji, e.g. thumbsup",
+					Type:        "string",
 					Mandatory:   true,
 				},
 			},
@@ -22,7 +22,7 @@
 package socketbee
 
 import (
-	"log"
+	"context"
 	"net"
 	"strconv"
 
@@ -37,7 +37,7 @@ type SocketBee struct {
 }
 
 // Run executes the Bee's event loop.
-func (mod *SocketBee) Run(cin chan bees.Event) {
+func (mod *SocketBee) Run(ctx context.Context, cin chan bees.Event) {
 	mod.eventChan = cin
 
 	select {
@@ -47,7 +47,7 @@ func (mod *SocketBee) Run(cin chan bees.Event) {
 }
 
 // Action triggers the action passed to it.
-func (mod *SocketBee) Action(action bees.Action) []bees.Placeholder {
+func (mod *SocketBee) Action(ctx context.Context, action bees.Action) []bees.Placeholder {
 	outs := []bees.Placeholder{}
 
 	var data string
@@ -64,18 +64,20 @@ func (mod *SocketBee) Action(action bees.Action) []bees.Placeholder {
 
 		sa, err := net.ResolveUDPAddr("udp", addr+":"+strconv.Itoa(port))
 		if err != nil {
-			log.Panicln(err)
+			mod.LogErrorf("Error resolving %s:%d: %v", addr, port, err)
+			return outs
 		}
 
 		conn, err := net.DialUDP("udp", nil, sa)
 		if err != nil {
-			log.Panicln(err)
+			mod.LogErrorf("Error connecting to %s:%d: %v", addr, port, err)
+			return outs
 		}
 
 		defer conn.Close()
 		_, err = conn.Write([]byte(data))
 		if err != nil {
-			log.Panicln(err)
+			mod.LogErrorf("Error sending to %s:%d: %v", addr, port, err)
 		}
 
 	default:
@@ -23,6 +23,7 @@
 package spaceapibee
 
 import (
+	"context"
 	"encoding/json"
 	"io/ioutil"
 	"net/http"
@@ -40,7 +41,7 @@ type SpaceAPIBee struct {
 }
 
 // Action triggers the action passed to it.
-func (mod *SpaceAPIBee) Action(action bees.Action) []bees.Placeholder {
+func (mod *SpaceAPIBee) Action(ctx context.Context, action bees.Action) []bees.Placeholder {
 	outs := []bees.Placeholder{}
 
 	switch action.Name {
@@ -88,7 +89,7 @@ func (mod *SpaceAPIBee) Action(action bees.Action) []bees.Placeholder {
 }
 
 // Run executes the Bee's event loop.
-func (mod *SpaceAPIBee) Run(eventChan chan bees.Event) {
+func (mod *SpaceAPIBee) Run(ctx context.Context, eventChan chan bees.Event) {
 	mod.evchan = eventChan
 }
 
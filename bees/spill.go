@@ -0,0 +1,160 @@
+/*
+ *    Copyright (C) 2026 Christian Muehlhaeuser
+ *
+ *    This program is free software: you can redistribute it and/or modify
+ *    it under the terms of the GNU Affero General Public License as published
+ *    by the Free Software Foundation, either version 3 of the License, or
+ *    (at your option) any later version.
+ *
+ *    This program is distributed in the hope that it will be useful,
+ *    but WITHOUT ANY WARRANTY; without even the implied warranty of
+ *    MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ *    GNU Affero General Public License for more details.
+ *
+ *    You should have received a copy of the GNU Affero General Public License
+ *    along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ *    Authors:
+ *      Christian Muehlhaeuser <muesli@gmail.com>
+ */
+
+// Package bees is Beehive's central module system.
+package bees
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"sync"
+	"sync/atomic"
+)
+
+var (
+	spillPath      string
+	spilledEvents  int64
+	spillReplaying int32
+	spillMutex     sync.Mutex
+)
+
+// SetChainSpillFile sets the file events get spilled to while the chain queue
+// is full, see SetChainQueue and OverflowSpill. Events still found in an
+// existing spill file get handled once the chain queue drained. An empty path
+// disables spilling, making OverflowSpill block like OverflowBlock.
+func SetChainSpillFile(path string) error {
+	spillMutex.Lock()
+	defer spillMutex.Unlock()
+
+	spillPath = path
+	atomic.StoreInt64(&spilledEvents, 0)
+	if path == "" {
+		return nil
+	}
+
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	var n int64
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(nil, 16*1024*1024)
+	for scanner.Scan() {
+		n++
+	}
+	atomic.StoreInt64(&spilledEvents, n)
+	return scanner.Err()
+}
+
+// spillEvent appends event to the spill file. Returns false if spilling is
+// disabled or failed, in which case the event needs to be handled now.
+func spillEvent(event *Event) bool {
+	spillMutex.Lock()
+	defer spillMutex.Unlock()
+
+	if spillPath == "" {
+		return false
+	}
+	b, err := json.Marshal(event)
+	if err != nil {
+		logger.Errorf("Failed to spill event %v / %v: %v", event.Bee, event.Name, err)
+		return false
+	}
+
+	f, err := os.OpenFile(spillPath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		logger.Errorf("Failed to spill event %v / %v: %v", event.Bee, event.Name, err)
+		return false
+	}
+	_, err = f.Write(append(b, '\n'))
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		logger.Errorf("Failed to spill event %v / %v: %v", event.Bee, event.Name, err)
+		return false
+	}
+
+	logger.Debugf("Chain queue is full, spilled event %v / %v", event.Bee, event.Name)
+	atomic.AddInt64(&spilledEvents, 1)
+	return true
+}
+
+// replaySpilledEvents re-injects all spilled events, which empties the spill
+// file. Events spilled again meanwhile are left for the next replay.
+func replaySpilledEvents() {
+	if atomic.LoadInt64(&spilledEvents) == 0 || !atomic.CompareAndSwapInt32(&spillReplaying, 0, 1) {
+		return
+	}
+
+	spillMutex.Lock()
+	events, err := readSpilledEvents()
+	if err == nil {
+		err = os.Remove(spillPath)
+	}
+	if err != nil {
+		logger.Errorf("Failed to read spilled events: %v", err)
+		events = nil
+	} else {
+		atomic.StoreInt64(&spilledEvents, 0)
+	}
+	spillMutex.Unlock()
+
+	go func() {
+		defer atomic.StoreInt32(&spillReplaying, 0)
+
+		for _, event := range events {
+			event.journaled = true
+			if !injectEventSafely(event) {
+				logger.Errorf("Failed to replay spilled event %v: the event handler is not running", event.ID)
+			}
+		}
+	}()
+}
+
+// readSpilledEvents reads all events from the spill file. The caller must
+// hold spillMutex.
+func readSpilledEvents() ([]Event, error) {
+	f, err := os.Open(spillPath)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var events []Event
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(nil, 16*1024*1024)
+	for scanner.Scan() {
+		var event Event
+		if err := json.Unmarshal(scanner.Bytes(), &event); err != nil {
+			logger.Errorf("Skipping malformed spilled event: %v", err)
+			continue
+		}
+		events = append(events, event)
+	}
+
+	return events, scanner.Err()
+}
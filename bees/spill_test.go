@@ -0,0 +1,52 @@
+package bees
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSpillEvents(t *testing.T) {
+	dir, err := ioutil.TempDir("", "beehive-spill")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	defer SetChainSpillFile("")
+
+	if spillEvent(&Event{Bee: "spillbee", Name: "first"}) {
+		t.Error("Expected no spilling without a spill file")
+	}
+
+	path := filepath.Join(dir, "spill")
+	if err := SetChainSpillFile(path); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"first", "second"} {
+		if !spillEvent(&Event{Bee: "spillbee", Name: name, Options: Placeholders{{Name: "n", Type: "string", Value: name}}}) {
+			t.Fatalf("Expected event %s to be spilled", name)
+		}
+	}
+	if s := ConcurrencyStats(); s.SpilledChains != 2 {
+		t.Errorf("Expected 2 spilled events, got %+v", s)
+	}
+
+	// spilled events survive restarts
+	if err := SetChainSpillFile(path); err != nil {
+		t.Fatal(err)
+	}
+	if s := ConcurrencyStats(); s.SpilledChains != 2 {
+		t.Errorf("Expected 2 spilled events after reopening the spill file, got %+v", s)
+	}
+
+	spillMutex.Lock()
+	events, err := readSpilledEvents()
+	spillMutex.Unlock()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(events) != 2 || events[0].Name != "first" || events[1].Options.Value("n") != "second" {
+		t.Errorf("Expected the spilled events in order, got %+v", events)
+	}
+}
@@ -0,0 +1,93 @@
+/*
+ *    Copyright (C) 2026 Christian Muehlhaeuser
+ *
+ *    This program is free software: you can redistribute it and/or modify
+ *    it under the terms of the GNU Affero General Public License as published
+ *    by the Free Software Foundation, either version 3 of the License, or
+ *    (at your option) any later version.
+ *
+ *    This program is distributed in the hope that it will be useful,
+ *    but WITHOUT ANY WARRANTY; without even the implied warranty of
+ *    MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ *    GNU Affero General Public License for more details.
+ *
+ *    You should have received a copy of the GNU Affero General Public License
+ *    along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ *    Authors:
+ *      Christian Muehlhaeuser <muesli@gmail.com>
+ */
+// Package bees is Beehive's central module system.
+package bees
+
+import (
+	"fmt"
+	"sync/atomic"
+	"time"
+)
+
+// DefaultStartupTimeout is the default time StartBees waits for a bee to be
+// running.
+const DefaultStartupTimeout = 30 * time.Second
+
+// maxStartupPoll is the longest interval between two IsRunning checks.
+const maxStartupPoll = 250 * time.Millisecond
+
+var startupTimeout = int64(DefaultStartupTimeout)
+
+// SetStartupTimeout sets the time StartBees waits for each bee to be running.
+// A timeout of zero disables waiting.
+func SetStartupTimeout(d time.Duration) {
+	atomic.StoreInt64(&startupTimeout, int64(d))
+}
+
+// WaitForRunning polls bee until it reports to be running, backing off
+// exponentially between attempts. Returns an error if the bee isn't running
+// after timeout.
+func WaitForRunning(bee *BeeInterface, timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+	wait := time.Millisecond
+	for !(*bee).IsRunning() {
+		left := time.Until(deadline)
+		if left <= 0 {
+			return fmt.Errorf("Bee %s: not running after %v", (*bee).Name(), timeout)
+		}
+		if wait > left {
+			wait = left
+		}
+		time.Sleep(wait)
+
+		wait *= 2
+		if wait > maxStartupPoll {
+			wait = maxStartupPoll
+		}
+	}
+
+	return nil
+}
+
+// waitForStartup waits for the registered bees of beeList to be running.
+// Bees that were skipped by startBees aren't registered and get ignored. All
+// bees share the same deadline, so StartBees returns within the startup
+// timeout no matter how many bees are slow to start.
+func waitForStartup(beeList []BeeConfig) []error {
+	timeout := time.Duration(atomic.LoadInt64(&startupTimeout))
+	if timeout <= 0 {
+		return nil
+	}
+
+	errs := []error{}
+	deadline := time.Now().Add(timeout)
+	for _, c := range beeList {
+		bee := registry.Bee(qualifyBee(c).Name)
+		if bee == nil {
+			continue
+		}
+		if err := WaitForRunning(bee, time.Until(deadline)); err != nil {
+			logger.Errorf("Bee %s failed to start within %v", c.Name, timeout)
+			errs = append(errs, fmt.Errorf("Bee %s: not running after %v", c.Name, timeout))
+		}
+	}
+
+	return errs
+}
@@ -0,0 +1,62 @@
+package bees
+
+import (
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// slowBee only reports to be running once ready is set.
+type slowBee struct {
+	recordingBee
+	ready int32
+}
+
+func (bee *slowBee) IsRunning() bool {
+	return atomic.LoadInt32(&bee.ready) == 1 && bee.recordingBee.IsRunning()
+}
+
+func init() {
+	RegisterFactory(&testFactory{
+		id: "slowbee",
+		newBee: func(name, description string, options BeeOptions) BeeInterface {
+			return &slowBee{recordingBee: recordingBee{Bee: NewBee(name, "slowbee", description, options)}}
+		},
+	})
+}
+
+func TestWaitForRunning(t *testing.T) {
+	var bee BeeInterface = &slowBee{recordingBee: recordingBee{Bee: NewBee("slow-wait", "slowbee", "", BeeOptions{})}}
+	bee.Start()
+
+	if err := WaitForRunning(&bee, 20*time.Millisecond); err == nil {
+		t.Error("Expected an error for a bee that isn't running")
+	}
+
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		atomic.StoreInt32(&bee.(*slowBee).ready, 1)
+	}()
+	if err := WaitForRunning(&bee, time.Second); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestStartupTimeout(t *testing.T) {
+	SetStartupTimeout(50 * time.Millisecond)
+	defer SetStartupTimeout(DefaultStartupTimeout)
+	defer StopBees()
+
+	start := time.Now()
+	errs := StartBees([]BeeConfig{
+		{Name: "slow-start", Class: "slowbee"},
+		{Name: "fast-start", Class: "recordingbee"},
+	})
+	if len(errs) != 1 || !strings.Contains(errs[0].Error(), "slow-start") {
+		t.Fatalf("Expected a startup error for slow-start only, got %v", errs)
+	}
+	if d := time.Since(start); d > time.Second {
+		t.Errorf("Expected StartBees to give up after the startup timeout, took %v", d)
+	}
+}
@@ -0,0 +1,478 @@
+/*
+ *    Copyright (C) 2026 Christian Muehlhaeuser
+ *
+ *    This program is free software: you can redistribute it and/or modify
+ *    it under the terms of the GNU Affero General Public License as published
+ *    by the Free Software Foundation, either version 3 of the License, or
+ *    (at your option) any later version.
+ *
+ *    This program is distributed in the hope that it will be useful,
+ *    but WITHOUT ANY WARRANTY; without even the implied warranty of
+ *    MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ *    GNU Affero General Public License for more details.
+ *
+ *    You should have received a copy of the GNU Affero General Public License
+ *    along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ *    Authors:
+ *      Christian Muehlhaeuser <muesli@gmail.com>
+ */
+
+// Package bees is Beehive's central module system.
+package bees
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// ErrNoSuchKey is returned by ContextGet when no value is stored for a key.
+var ErrNoSuchKey = errors.New("No value stored for that key")
+
+// ErrStateNotDeletable is returned by DeleteState if the StateStore can't
+// delete values, see EnumerableStateStore.
+var ErrStateNotDeletable = errors.New("The state store can't delete values")
+
+// StateStore persists the values bees store in their context. Values are
+// JSON-encoded and stored per bee and key.
+type StateStore interface {
+	// Save stores the value of a bee's key
+	Save(beeName, key string, value []byte) error
+	// Load returns the value stored for a bee's key, and false if there is
+	// none
+	Load(beeName, key string) ([]byte, bool, error)
+}
+
+// EnumerableStateStore is a StateStore which can also list and delete the
+// values of a bee. The hive needs it to preload the state of new bees, to
+// list their keys with StateKeys and to delete values, as well as to purge
+// and move the state of deleted and renamed bees. Values of other stores
+// only get loaded on access, and stay where they are otherwise.
+type EnumerableStateStore interface {
+	StateStore
+
+	// Keys returns the keys values are stored for, for a bee
+	Keys(beeName string) ([]string, error)
+	// Delete removes the value stored for a bee's key
+	Delete(beeName, key string) error
+}
+
+// FileStateStore is an EnumerableStateStore keeping the values of each bee in
+// a JSON file named after the bee, in the directory Dir. The scope separator
+// of scoped bees gets escaped, so "team/rss" is kept in team%2Frss.json. Only
+// JSON-encoded values can be stored, like the ones the hive saves.
+type FileStateStore struct {
+	Dir string
+
+	mutex sync.Mutex
+}
+
+// NewFileStateStore returns a FileStateStore using dir as data directory.
+func NewFileStateStore(dir string) *FileStateStore {
+	return &FileStateStore{Dir: dir}
+}
+
+func (s *FileStateStore) path(bee string) string {
+	name := strings.Replace(bee, "%", "%25", -1)
+	name = strings.Replace(name, BeeSeparator, "%2F", -1)
+	return filepath.Join(s.Dir, filepath.Base(name)+".json")
+}
+
+// read returns the values stored in the file of a bee. Must be called with
+// the mutex held.
+func (s *FileStateStore) read(bee string) (map[string]json.RawMessage, error) {
+	values := make(map[string]json.RawMessage)
+	b, err := ioutil.ReadFile(s.path(bee))
+	if os.IsNotExist(err) {
+		return values, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	err = json.Unmarshal(b, &values)
+	return values, err
+}
+
+// write replaces the file of a bee atomically, so a crash doesn't leave a
+// partially written file behind. Files without values get removed. Must be
+// called with the mutex held.
+func (s *FileStateStore) write(bee string, values map[string]json.RawMessage) error {
+	if len(values) == 0 {
+		err := os.Remove(s.path(bee))
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+
+	b, err := json.Marshal(values)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(s.Dir, 0700); err != nil {
+		return err
+	}
+
+	tmp := s.path(bee) + ".tmp"
+	if err := ioutil.WriteFile(tmp, b, 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, s.path(bee))
+}
+
+// Save stores the value of a bee's key in the bee's file. An unreadable file
+// gets replaced.
+func (s *FileStateStore) Save(bee, key string, value []byte) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	values, err := s.read(bee)
+	if err != nil {
+		values = make(map[string]json.RawMessage)
+	}
+	values[key] = value
+	return s.write(bee, values)
+}
+
+// Load reads the value of a bee's key from the bee's file.
+func (s *FileStateStore) Load(bee, key string) ([]byte, bool, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	values, err := s.read(bee)
+	if err != nil {
+		return nil, false, err
+	}
+	v, ok := values[key]
+	return v, ok, nil
+}
+
+// Keys returns the sorted keys stored in the file of a bee.
+func (s *FileStateStore) Keys(bee string) ([]string, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	values, err := s.read(bee)
+	if err != nil {
+		return nil, err
+	}
+	keys := make([]string, 0, len(values))
+	for k := range values {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys, nil
+}
+
+// Delete removes a key from the file of a bee. The file gets removed along
+// with its last key.
+func (s *FileStateStore) Delete(bee, key string) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	values, err := s.read(bee)
+	if err != nil {
+		return err
+	}
+	if _, ok := values[key]; !ok {
+		return nil
+	}
+	delete(values, key)
+	return s.write(bee, values)
+}
+
+// NopStateStore is a StateStore which doesn't persist anything.
+type NopStateStore struct{}
+
+// Save discards the value.
+func (NopStateStore) Save(bee, key string, value []byte) error {
+	return nil
+}
+
+// Load returns no value.
+func (NopStateStore) Load(bee, key string) ([]byte, bool, error) {
+	return nil, false, nil
+}
+
+// Keys returns no keys.
+func (NopStateStore) Keys(bee string) ([]string, error) {
+	return nil, nil
+}
+
+// Delete does nothing.
+func (NopStateStore) Delete(bee, key string) error {
+	return nil
+}
+
+var (
+	stateStore      StateStore
+	stateStoreMutex sync.RWMutex
+
+	purgeStateOnDelete int32 = 1
+)
+
+// SetStateStore sets the store the values bees keep in their context get
+// persisted in. Passing nil, the default, keeps them in memory only.
+func SetStateStore(s StateStore) {
+	stateStoreMutex.Lock()
+	stateStore = s
+	stateStoreMutex.Unlock()
+
+	resetVariables()
+}
+
+// SetDataDir persists the values bees keep in their context as JSON files in
+// dir. It's a shortcut for SetStateStore(NewFileStateStore(dir)).
+func SetDataDir(dir string) {
+	SetStateStore(NewFileStateStore(dir))
+}
+
+func getStateStore() StateStore {
+	stateStoreMutex.RLock()
+	defer stateStoreMutex.RUnlock()
+	return stateStore
+}
+
+// SetPurgeStateOnDelete controls whether DeleteBee removes the persisted
+// values of the deleted bee from the StateStore. Enabled by default.
+func SetPurgeStateOnDelete(enabled bool) {
+	var v int32
+	if enabled {
+		v = 1
+	}
+	atomic.StoreInt32(&purgeStateOnDelete, v)
+}
+
+// purgeState removes the persisted values of a bee, if enabled and supported
+// by the StateStore.
+func purgeState(bee string) {
+	s, ok := getStateStore().(EnumerableStateStore)
+	if !ok || atomic.LoadInt32(&purgeStateOnDelete) == 0 {
+		return
+	}
+	keys, err := s.Keys(bee)
+	if err != nil {
+		logger.Errorf("Failed to purge state of bee %v: %v", bee, err)
+		return
+	}
+	for _, k := range keys {
+		if err := s.Delete(bee, k); err != nil {
+			logger.Errorf("Failed to purge state of bee %v: %v", bee, err)
+			return
+		}
+	}
+}
+
+// beeStorage holds the values a bee stored in its context, as JSON, along
+// with the values loaded from the StateStore.
+type beeStorage struct {
+	mutex  sync.Mutex
+	values map[string]json.RawMessage
+	// dirty holds the keys whose values haven't been persisted yet
+	dirty map[string]struct{}
+	// loaded gets set once all persisted values have been loaded
+	loaded bool
+}
+
+// init sets up the maps of a new beeStorage. Must be called with the mutex
+// held.
+func (s *beeStorage) init() {
+	if s.values == nil {
+		s.values = make(map[string]json.RawMessage)
+		s.dirty = make(map[string]struct{})
+	}
+}
+
+// load reads all persisted values, unless they have been loaded already or
+// the StateStore can't list them. Values set in the meantime take
+// precedence. Unreadable state gets logged and discarded. Must be called
+// with the mutex held.
+func (s *beeStorage) load(bee string) {
+	s.init()
+	store, ok := getStateStore().(EnumerableStateStore)
+	if s.loaded || !ok {
+		return
+	}
+	s.loaded = true
+
+	keys, err := store.Keys(bee)
+	if err != nil {
+		logger.Errorf("Discarding unreadable state of bee %v: %v", bee, err)
+		return
+	}
+	for _, k := range keys {
+		if _, ok := s.values[k]; !ok {
+			s.lookup(store, bee, k)
+		}
+	}
+}
+
+// lookup loads the persisted value of a key, returning false if there is
+// none. Unreadable values get logged and discarded. Must be called with the
+// mutex held.
+func (s *beeStorage) lookup(store StateStore, bee, key string) bool {
+	b, ok, err := store.Load(bee, key)
+	if err != nil {
+		logger.Errorf("Discarding unreadable state %v of bee %v: %v", key, bee, err)
+		return false
+	}
+	if ok {
+		s.values[key] = b
+	}
+	return ok
+}
+
+func (s *beeStorage) set(bee, key string, value interface{}) {
+	b, err := json.Marshal(value)
+	if err != nil {
+		logger.Errorf("Can't store value %v of bee %v: %v", key, bee, err)
+		return
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.init()
+	s.values[key] = b
+	s.dirty[key] = struct{}{}
+}
+
+// save stores a value and persists it right away.
+func (s *beeStorage) save(bee, key string, value interface{}) error {
+	b, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.init()
+	s.values[key] = b
+	store := getStateStore()
+	if store == nil {
+		s.dirty[key] = struct{}{}
+		return nil
+	}
+	if err := store.Save(bee, key, b); err != nil {
+		s.dirty[key] = struct{}{}
+		return err
+	}
+	delete(s.dirty, key)
+	return nil
+}
+
+// preload loads the persisted values of a bee ahead of their first access.
+func (s *beeStorage) preload(bee string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.load(bee)
+}
+
+// remove deletes the value stored for key and persists the change right
+// away. It fails with ErrStateNotDeletable if the StateStore isn't an
+// EnumerableStateStore.
+func (s *beeStorage) remove(bee, key string) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.init()
+	delete(s.values, key)
+	delete(s.dirty, key)
+	store := getStateStore()
+	if store == nil {
+		return nil
+	}
+	es, ok := store.(EnumerableStateStore)
+	if !ok {
+		return ErrStateNotDeletable
+	}
+	return es.Delete(bee, key)
+}
+
+// keys returns the sorted keys values are stored for.
+func (s *beeStorage) keys(bee string) []string {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.load(bee)
+	keys := make([]string, 0, len(s.values))
+	for k := range s.values {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func (s *beeStorage) get(bee, key string, out interface{}) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.init()
+	b, ok := s.values[key]
+	if !ok {
+		store := getStateStore()
+		if s.loaded || store == nil || !s.lookup(store, bee, key) {
+			return ErrNoSuchKey
+		}
+		b = s.values[key]
+	}
+
+	return json.Unmarshal(b, out)
+}
+
+// rename moves the persisted values of a bee to its new name. Only the values
+// known to the bee get moved, unless the StateStore is an
+// EnumerableStateStore.
+func (s *beeStorage) rename(old, name string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	store := getStateStore()
+	if store == nil {
+		return
+	}
+	s.load(old)
+	for k, v := range s.values {
+		if err := store.Save(name, k, v); err != nil {
+			logger.Errorf("Failed to persist state of bee %v: %v", name, err)
+			return
+		}
+		delete(s.dirty, k)
+	}
+	if es, ok := store.(EnumerableStateStore); ok {
+		for k := range s.values {
+			if err := es.Delete(old, k); err != nil {
+				logger.Errorf("Failed to remove state of bee %v: %v", old, err)
+				return
+			}
+		}
+	}
+}
+
+// flush persists changed values and makes the next access load them again.
+func (s *beeStorage) flush(bee string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	store := getStateStore()
+	if store != nil {
+		for k := range s.dirty {
+			if err := store.Save(bee, k, s.values[k]); err != nil {
+				logger.Errorf("Failed to persist state of bee %v: %v", bee, err)
+				return
+			}
+			delete(s.dirty, k)
+		}
+	}
+	s.loaded = false
+}
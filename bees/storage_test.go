@@ -0,0 +1,285 @@
+package bees
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+type feedState struct {
+	LastGUID string
+	Seen     []string
+}
+
+func TestContextPersistence(t *testing.T) {
+	dir, err := ioutil.TempDir("", "beehive-state")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	SetDataDir(dir)
+	defer SetStateStore(nil)
+
+	bee := NewBee("feedbee", "recordingbee", "", BeeOptions{})
+	bee.Start()
+	bee.ContextSet("feed", feedState{LastGUID: "42", Seen: []string{"41", "42"}})
+	bee.Stop()
+
+	// a new instance of the same bee, e.g. after restarting beehive
+	bee = NewBee("feedbee", "recordingbee", "", BeeOptions{})
+	bee.Start()
+	var state feedState
+	if err := bee.ContextGet("feed", &state); err != nil {
+		t.Fatal(err)
+	}
+	if state.LastGUID != "42" || len(state.Seen) != 2 {
+		t.Errorf("Unexpected restored state: %+v", state)
+	}
+	if err := bee.ContextGet("unknown", &state); err != ErrNoSuchKey {
+		t.Errorf("Expected ErrNoSuchKey, got %v", err)
+	}
+	bee.Stop()
+}
+
+func TestCorruptContextState(t *testing.T) {
+	dir, err := ioutil.TempDir("", "beehive-state")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	if err := ioutil.WriteFile(filepath.Join(dir, "brokenbee.json"), []byte("{not json"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	SetDataDir(dir)
+	defer SetStateStore(nil)
+
+	bee := NewBee("brokenbee", "recordingbee", "", BeeOptions{})
+	bee.Start()
+	var v string
+	if err := bee.ContextGet("anything", &v); err != ErrNoSuchKey {
+		t.Errorf("Expected corrupt state to be discarded, got %v", err)
+	}
+
+	bee.ContextSet("fresh", "start")
+	bee.Stop()
+	bee.SetSigChan(make(chan bool))
+	bee.Start()
+	if err := bee.ContextGet("fresh", &v); err != nil || v != "start" {
+		t.Errorf("Expected fresh state to be persisted, got %q, %v", v, err)
+	}
+	bee.Stop()
+}
+
+func TestPurgeStateOnDelete(t *testing.T) {
+	dir, err := ioutil.TempDir("", "beehive-state")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	SetDataDir(dir)
+	defer SetStateStore(nil)
+
+	mod := newRecordingBee("purgebee")
+	mod.ContextSet("key", 1)
+	DeleteBee(GetBee("purgebee"))
+
+	if _, err := os.Stat(filepath.Join(dir, "purgebee.json")); !os.IsNotExist(err) {
+		t.Errorf("Expected state file to be purged, got %v", err)
+	}
+
+	SetPurgeStateOnDelete(false)
+	defer SetPurgeStateOnDelete(true)
+
+	mod = newRecordingBee("keepbee")
+	if err := mod.SaveState("key", 1); err != nil {
+		t.Fatal(err)
+	}
+	DeleteBee(GetBee("keepbee"))
+
+	if _, err := os.Stat(filepath.Join(dir, "keepbee.json")); err != nil {
+		t.Errorf("Expected state file to be kept, got %v", err)
+	}
+}
+
+func TestScopedBeeState(t *testing.T) {
+	dir, err := ioutil.TempDir("", "beehive-state")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	SetDataDir(dir)
+	defer SetStateStore(nil)
+
+	scoped := NewBee("team"+BeeSeparator+"feed", "recordingbee", "", BeeOptions{})
+	plain := NewBee("feed", "recordingbee", "", BeeOptions{})
+	if err := scoped.SaveState("cursor", 42); err != nil {
+		t.Fatal(err)
+	}
+	if err := plain.SaveState("cursor", 7); err != nil {
+		t.Fatal(err)
+	}
+
+	scoped = NewBee("team"+BeeSeparator+"feed", "recordingbee", "", BeeOptions{})
+	var cursor int
+	if ok, err := scoped.LoadState("cursor", &cursor); !ok || err != nil || cursor != 42 {
+		t.Errorf("Expected the scoped bee's own state, got %v (%v, %v)", cursor, ok, err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "team%2Ffeed.json")); err != nil {
+		t.Errorf("Expected an escaped state file: %v", err)
+	}
+}
+
+func TestDeleteState(t *testing.T) {
+	dir, err := ioutil.TempDir("", "beehive-state")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	SetDataDir(dir)
+	defer SetStateStore(nil)
+
+	bee := NewBee("keysbee", "recordingbee", "", BeeOptions{})
+	bee.SaveState("b", 2)
+	bee.ContextSet("a", 1)
+	if keys := bee.StateKeys(); len(keys) != 2 || keys[0] != "a" || keys[1] != "b" {
+		t.Errorf("Expected keys [a b], got %v", keys)
+	}
+
+	if err := bee.DeleteState("a"); err != nil {
+		t.Fatal(err)
+	}
+	if bee.ContextValue("a") != nil {
+		t.Error("Expected deleted value to be removed from the context")
+	}
+
+	bee = NewBee("keysbee", "recordingbee", "", BeeOptions{})
+	var v int
+	if ok, _ := bee.LoadState("a", &v); ok {
+		t.Error("Expected deleted value not to be restored")
+	}
+	if keys := bee.StateKeys(); len(keys) != 1 || keys[0] != "b" {
+		t.Errorf("Expected keys [b], got %v", keys)
+	}
+}
+
+func TestSaveLoadState(t *testing.T) {
+	dir, err := ioutil.TempDir("", "beehive-state")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	SetDataDir(dir)
+	defer SetStateStore(nil)
+
+	bee := NewBee("statebee", "recordingbee", "", BeeOptions{})
+	if err := bee.SaveState("nicks", []string{"muesli", "beehive"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "statebee.json")); err != nil {
+		t.Errorf("Expected state to be persisted right away: %v", err)
+	}
+	if err := bee.SaveState("broken", func() {}); err == nil {
+		t.Error("Expected saving an unencodable value to fail")
+	}
+
+	// simulates a crash: the bee never got stopped
+	bee = NewBee("statebee", "recordingbee", "", BeeOptions{})
+	var nicks []string
+	if ok, err := bee.LoadState("nicks", &nicks); !ok || err != nil || len(nicks) != 2 {
+		t.Errorf("Expected restored state, got %v (%v, %v)", nicks, ok, err)
+	}
+	if ok, err := bee.LoadState("unknown", &nicks); ok || err != nil {
+		t.Errorf("Expected no state for an unknown key, got %v, %v", ok, err)
+	}
+
+	SetStateStore(NopStateStore{})
+	bee = NewBee("nopbee", "recordingbee", "", BeeOptions{})
+	if err := bee.SaveState("nicks", nicks); err != nil {
+		t.Fatal(err)
+	}
+	bee = NewBee("nopbee", "recordingbee", "", BeeOptions{})
+	if ok, _ := bee.LoadState("nicks", &nicks); ok {
+		t.Error("Expected the no-op store not to persist anything")
+	}
+}
+
+func TestFileStateStore(t *testing.T) {
+	dir, err := ioutil.TempDir("", "beehive-state")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	s := NewFileStateStore(dir)
+	if v, ok, err := s.Load("storebee", "cursor"); ok || err != nil || v != nil {
+		t.Errorf("Expected no value for a bee without state, got %q, %v, %v", v, ok, err)
+	}
+	if err := s.Save("storebee", "cursor", []byte("42")); err != nil {
+		t.Fatal(err)
+	}
+	if v, ok, err := s.Load("storebee", "cursor"); !ok || err != nil || string(v) != "42" {
+		t.Errorf("Expected the saved value, got %q, %v, %v", v, ok, err)
+	}
+	if v, ok, err := s.Load("storebee", "missing"); ok || err != nil || v != nil {
+		t.Errorf("Expected no value for a missing key, got %q, %v, %v", v, ok, err)
+	}
+	if keys, err := s.Keys("storebee"); err != nil || len(keys) != 1 || keys[0] != "cursor" {
+		t.Errorf("Expected keys [cursor], got %v, %v", keys, err)
+	}
+
+	if err := s.Delete("storebee", "cursor"); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok, _ := s.Load("storebee", "cursor"); ok {
+		t.Error("Expected the deleted value to be gone")
+	}
+	if _, err := os.Stat(filepath.Join(dir, "storebee.json")); !os.IsNotExist(err) {
+		t.Errorf("Expected the file to be removed along with its last key, got %v", err)
+	}
+}
+
+// mapStateStore is a StateStore which can't list or delete values.
+type mapStateStore map[string][]byte
+
+func (s mapStateStore) Save(bee, key string, value []byte) error {
+	s[bee+"/"+key] = value
+	return nil
+}
+
+func (s mapStateStore) Load(bee, key string) ([]byte, bool, error) {
+	v, ok := s[bee+"/"+key]
+	return v, ok, nil
+}
+
+func TestPerKeyStateStore(t *testing.T) {
+	s := mapStateStore{}
+	SetStateStore(s)
+	defer SetStateStore(nil)
+
+	bee := NewBee("kvbee", "recordingbee", "", BeeOptions{})
+	if err := bee.SaveState("cursor", 42); err != nil {
+		t.Fatal(err)
+	}
+	if string(s["kvbee/cursor"]) != "42" {
+		t.Errorf("Expected the value to be saved under its key, got %v", s)
+	}
+
+	bee = NewBee("kvbee", "recordingbee", "", BeeOptions{})
+	var cursor int
+	if ok, err := bee.LoadState("cursor", &cursor); !ok || err != nil || cursor != 42 {
+		t.Errorf("Expected the value to be loaded on access, got %v (%v, %v)", cursor, ok, err)
+	}
+	if ok, err := bee.LoadState("missing", &cursor); ok || err != nil {
+		t.Errorf("Expected no state for a missing key, got %v, %v", ok, err)
+	}
+	if err := bee.DeleteState("cursor"); err != ErrStateNotDeletable {
+		t.Errorf("Expected ErrStateNotDeletable, got %v", err)
+	}
+}
@@ -23,6 +23,7 @@
 package sunbee
 
 import (
+	"context"
 	"fmt"
 	"math"
 	"strconv"
@@ -45,7 +46,7 @@ type SunBee struct {
 }
 
 // Run executes the Bee's event loop.
-func (mod *SunBee) Run(eventChan chan bees.Event) {
+func (mod *SunBee) Run(ctx context.Context, eventChan chan bees.Event) {
 	gominatim.SetServer("https://nominatim.openstreetmap.org/")
 	qry := gominatim.SearchQuery{
 		Q: mod.query,
@@ -75,7 +76,7 @@ func (mod *SunBee) Run(eventChan chan bees.Event) {
 }
 
 // Action triggers the action passed to it.
-func (mod *SunBee) Action(action bees.Action) []bees.Placeholder {
+func (mod *SunBee) Action(ctx context.Context, action bees.Action) []bees.Placeholder {
 	return []bees.Placeholder{}
 }
 
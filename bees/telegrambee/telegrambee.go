@@ -24,6 +24,7 @@
 package telegrambee
 
 import (
+	"context"
 	"io/ioutil"
 	"os"
 	"strconv"
@@ -52,7 +53,7 @@ type TelegramBee struct {
 }
 
 // Action triggers the action passed to it.
-func (mod *TelegramBee) Action(action bees.Action) []bees.Placeholder {
+func (mod *TelegramBee) Action(ctx context.Context, action bees.Action) []bees.Placeholder {
 	outs := []bees.Placeholder{}
 
 	switch action.Name {
@@ -89,7 +90,7 @@ func (mod *TelegramBee) Action(action bees.Action) []bees.Placeholder {
 }
 
 // Run executes the Bee's event loop.
-func (mod *TelegramBee) Run(eventChan chan bees.Event) {
+func (mod *TelegramBee) Run(ctx context.Context, eventChan chan bees.Event) {
 	var err error
 	mod.bot, err = telegram.NewBotAPI(mod.apiKey)
 	if err != nil {
@@ -0,0 +1,116 @@
+/*
+ *    Copyright (C) 2026 Christian Muehlhaeuser
+ *
+ *    This program is free software: you can redistribute it and/or modify
+ *    it under the terms of the GNU Affero General Public License as published
+ *    by the Free Software Foundation, either version 3 of the License, or
+ *    (at your option) any later version.
+ *
+ *    This program is distributed in the hope that it will be useful,
+ *    but WITHOUT ANY WARRANTY; without even the implied warranty of
+ *    MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ *    GNU Affero General Public License for more details.
+ *
+ *    You should have received a copy of the GNU Affero General Public License
+ *    along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ *    Authors:
+ *      Christian Muehlhaeuser <muesli@gmail.com>
+ */
+
+// Package bees is Beehive's central module system.
+package bees
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+	"text/template"
+
+	"github.com/muesli/beehive/templatehelper"
+)
+
+// templateFuncs contains the helpers available in templates rendered by
+// beehive: the ones from templatehelper, plus a few taking the piped value
+// last, e.g. {{.text | trim | upper}}.
+var templateFuncs = func() template.FuncMap {
+	funcs := template.FuncMap{
+		"default": defaultValue,
+		"trim":    func(v interface{}) string { return strings.TrimSpace(fmt.Sprint(v)) },
+		"upper":   func(v interface{}) string { return strings.ToUpper(fmt.Sprint(v)) },
+		"lower":   func(v interface{}) string { return strings.ToLower(fmt.Sprint(v)) },
+		"json":    jsonValue,
+		"replace": replaceRegexp,
+	}
+	for k, f := range templatehelper.FuncMap {
+		funcs[k] = f
+	}
+	return funcs
+}()
+
+// defaultValue returns value, or fallback if value is missing or empty. Used
+// as {{.key | default "fallback"}}.
+func defaultValue(fallback interface{}, value ...interface{}) interface{} {
+	if len(value) == 0 || value[0] == nil {
+		return fallback
+	}
+	if s, ok := value[0].(string); ok && s == "" {
+		return fallback
+	}
+	return value[0]
+}
+
+// jsonValue returns the JSON encoding of v. Used as {{.user | json}}.
+func jsonValue(v interface{}) (string, error) {
+	b, err := json.Marshal(v)
+	return string(b), err
+}
+
+// replaceRegexp replaces all matches of the regular expression pattern in v
+// with repl, which may refer to submatches like $1. Used as
+// {{.text | replace "\\s+" " "}}.
+func replaceRegexp(pattern, repl string, v interface{}) (string, error) {
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return "", err
+	}
+	return re.ReplaceAllString(fmt.Sprint(v), repl), nil
+}
+
+// RenderTemplate executes the text/template tmpl with the placeholders'
+// values, accessible by their names, e.g. "Hello, {{.sender}}". Nested values
+// like maps can be accessed as usual, e.g. {{.user.name}}. Missing values
+// render as empty strings.
+func RenderTemplate(tmpl string, placeholders Placeholders) (string, error) {
+	return renderTemplate("_", tmpl, placeholderMap(placeholders))
+}
+
+// MustRenderTemplate is like RenderTemplate, but panics if the template can't
+// be rendered.
+func MustRenderTemplate(tmpl string, placeholders Placeholders) string {
+	s, err := RenderTemplate(tmpl, placeholders)
+	if err != nil {
+		panic(err)
+	}
+	return s
+}
+
+// noValue is what text/template renders for missing map entries.
+const noValue = "<no value>"
+
+// renderTemplate executes the text/template tmpl, called name, with data.
+// Missing values render as empty strings.
+func renderTemplate(name, tmpl string, data map[string]interface{}) (string, error) {
+	t, err := template.New(name).Funcs(templateFuncs).Parse(tmpl)
+	if err != nil {
+		return "", err
+	}
+
+	var value bytes.Buffer
+	if err := t.Execute(&value, data); err != nil {
+		return "", err
+	}
+	return strings.Replace(value.String(), noValue, "", -1), nil
+}
@@ -0,0 +1,50 @@
+package bees
+
+import (
+	"testing"
+)
+
+func TestRenderTemplate(t *testing.T) {
+	ph := Placeholders{
+		{Name: "sender", Type: "string", Value: "alice"},
+		{Name: "message", Type: "string", Value: "hi"},
+		{Name: "user", Type: "map", Value: map[string]interface{}{"name": "Alice"}},
+		{Name: "empty", Type: "string", Value: ""},
+		{Name: "padded", Type: "string", Value: "  Some   Text "},
+	}
+
+	tests := map[string]string{
+		"Hello, {{.sender}}, you said: {{.message}}": "Hello, alice, you said: hi",
+		"{{.user.name}}":                               "Alice",
+		`{{.missing | default "nobody"}}`:              "nobody",
+		`{{.empty | default "nothing"}}`:               "nothing",
+		`{{.sender | default "nobody"}}`:               "alice",
+		`{{.sender | ToUpper}}`:                        "ALICE",
+		`{{.padded | trim | upper}}`:                   "SOME   TEXT",
+		`{{.padded | trim | lower}}`:                   "some   text",
+		`{{.user | json}}`:                             `{"name":"Alice"}`,
+		`{{.padded | trim | replace "\\s+" " "}}`:      "Some Text",
+		`{{.sender | replace "^(a)(.*)$" "${2}${1}"}}`: "licea",
+	}
+	for tmpl, expected := range tests {
+		s, err := RenderTemplate(tmpl, ph)
+		if err != nil {
+			t.Errorf("Rendering %q failed: %v", tmpl, err)
+		} else if s != expected {
+			t.Errorf("Expected %q to render as %q, got %q", tmpl, expected, s)
+		}
+	}
+
+	if _, err := RenderTemplate("{{.sender", ph); err == nil {
+		t.Error("Expected an error for a malformed template")
+	}
+}
+
+func TestMustRenderTemplate(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("Expected MustRenderTemplate to panic")
+		}
+	}()
+	MustRenderTemplate("{{.sender", Placeholders{})
+}
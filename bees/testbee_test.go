@@ -0,0 +1,128 @@
+package bees
+
+import (
+	"context"
+	"sync"
+)
+
+// recordingBee records the names of the actions it executes. It fails actions
+// named "fail", blocks actions named "hang" until they get cancelled and
+// returns a "shortened_url" from actions named "shorten".
+type recordingBee struct {
+	Bee
+
+	mutex   sync.Mutex
+	actions []string
+	options []Placeholders
+	ctxs    []context.Context
+}
+
+func (mod *recordingBee) Action(ctx context.Context, action Action) []Placeholder {
+	mod.mutex.Lock()
+	mod.actions = append(mod.actions, action.Name)
+	mod.options = append(mod.options, action.Options)
+	mod.ctxs = append(mod.ctxs, ctx)
+	mod.mutex.Unlock()
+
+	switch action.Name {
+	case "fail":
+		panic("action failed")
+	case "hang":
+		<-ctx.Done()
+	case "shorten":
+		return []Placeholder{{Name: "shortened_url", Type: "url", Value: "https://sho.rt/1"}}
+	}
+	return []Placeholder{}
+}
+
+func (mod *recordingBee) ReloadOptions(options BeeOptions) {
+	mod.SetOptions(options)
+}
+
+func (mod *recordingBee) executed() []string {
+	mod.mutex.Lock()
+	defer mod.mutex.Unlock()
+
+	return append([]string{}, mod.actions...)
+}
+
+func init() {
+	RegisterFactory(&testFactory{
+		id:          "recordingbee",
+		name:        "Recording",
+		description: "Records executed actions",
+	})
+}
+
+// testFactory is a configurable bee factory for tests that need a bee class
+// of their own, like beestest.Factory, which can't be used from within this
+// package. Its bees are recordingBees unless newBee is set.
+type testFactory struct {
+	BeeFactory
+
+	id          string
+	name        string
+	description string
+	doc         string
+	events      []EventDescriptor
+	actions     []ActionDescriptor
+	options     []BeeOptionDescriptor
+	examples    []ConfigExample
+	validate    func(options BeeOptions) []error
+	newBee      func(name, description string, options BeeOptions) BeeInterface
+}
+
+func (f *testFactory) ID() string { return f.id }
+
+func (f *testFactory) Name() string {
+	if f.name == "" {
+		return f.id
+	}
+	return f.name
+}
+
+func (f *testFactory) Description() string {
+	if f.description == "" {
+		return "Test factory " + f.id
+	}
+	return f.description
+}
+
+func (f *testFactory) Doc() string                    { return f.doc }
+func (f *testFactory) Events() []EventDescriptor      { return f.events }
+func (f *testFactory) Actions() []ActionDescriptor    { return f.actions }
+func (f *testFactory) Options() []BeeOptionDescriptor { return f.options }
+func (f *testFactory) Examples() []ConfigExample      { return f.examples }
+
+func (f *testFactory) ValidateOptions(options BeeOptions) []error {
+	if f.validate == nil {
+		return nil
+	}
+	return f.validate(options)
+}
+
+func (f *testFactory) New(name, description string, options BeeOptions) BeeInterface {
+	if f.newBee != nil {
+		return f.newBee(name, description, options)
+	}
+	return &recordingBee{Bee: NewBee(name, f.id, description, options)}
+}
+
+// newRecordingBee registers and starts a new recordingBee.
+func newRecordingBee(name string) *recordingBee {
+	mod, _ := NewBeeInstance(BeeConfig{Name: name, Class: "recordingbee"})
+	(*mod).Start()
+
+	return (*mod).(*recordingBee)
+}
+
+// useEventChannel makes the hive hand events to in, e.g. to inspect the
+// events bees emit, and returns a function restoring the previous channels.
+func useEventChannel(in chan Event) func() {
+	oldIn, oldStopped := eventChannel()
+	setEventChannel(in, make(chan struct{}))
+
+	return func() {
+		setEventChannel(oldIn, oldStopped)
+	}
+}
@@ -24,6 +24,7 @@
 package timebee
 
 import (
+	"context"
 	"time"
 
 	"github.com/muesli/beehive/bees"
@@ -89,7 +90,7 @@ func (mod *TimeBee) timer() {
 }
 
 // Run executes the Bee's event loop.
-func (mod *TimeBee) Run(eventChan chan bees.Event) {
+func (mod *TimeBee) Run(ctx context.Context, eventChan chan bees.Event) {
 	mod.eventChan = eventChan
 	for {
 		select {
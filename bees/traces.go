@@ -0,0 +1,278 @@
+/*
+ *    Copyright (C) 2026 Christian Muehlhaeuser
+ *
+ *    This program is free software: you can redistribute it and/or modify
+ *    it under the terms of the GNU Affero General Public License as published
+ *    by the Free Software Foundation, either version 3 of the License, or
+ *    (at your option) any later version.
+ *
+ *    This program is distributed in the hope that it will be useful,
+ *    but WITHOUT ANY WARRANTY; without even the implied warranty of
+ *    MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ *    GNU Affero General Public License for more details.
+ *
+ *    You should have received a copy of the GNU Affero General Public License
+ *    along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ *    Authors:
+ *      Christian Muehlhaeuser <muesli@gmail.com>
+ */
+
+// Package bees is Beehive's central module system.
+package bees
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// DefaultChainTraceSize is the default number of traces kept for each chain.
+const DefaultChainTraceSize = 20
+
+// Outcomes of a traced chain evaluation, see ChainTrace.
+const (
+	TraceSucceeded = "succeeded"
+	TraceFailed    = "failed"
+	TraceFiltered  = "filtered"
+	TraceSkipped   = "skipped"
+	TraceDeferred  = "deferred"
+)
+
+// A ChainTrace records how a chain handled an event: the results of its
+// filters and the actions it executed, with their rendered options, results
+// and timing.
+type ChainTrace struct {
+	ChainName string
+	Event     Event
+	StartedAt time.Time
+	Duration  time.Duration
+	Filters   []FilterTrace `json:",omitempty"`
+	Actions   []ActionTrace `json:",omitempty"`
+
+	// Outcome is one of the Trace* constants. Reason explains why a chain
+	// got skipped or deferred, e.g. due to rate-limiting or batching.
+	Outcome string
+	Reason  string `json:",omitempty"`
+	Error   string `json:",omitempty"`
+
+	// ExecutionID identifies the execution in the chain history, if the
+	// chain's actions got executed.
+	ExecutionID string `json:",omitempty"`
+}
+
+// chainTrace is a ChainTrace still being recorded.
+type chainTrace struct {
+	ChainTrace
+	mutex sync.Mutex
+}
+
+// A FilterTrace is the result of a single filter expression.
+type FilterTrace struct {
+	Filter   string
+	Passed   bool
+	Error    string `json:",omitempty"`
+	Duration time.Duration
+}
+
+// An ActionTrace describes an action executed by a chain.
+type ActionTrace struct {
+	Bee       string
+	Name      string
+	Options   Placeholders
+	Results   []Placeholder `json:",omitempty"`
+	StartedAt time.Time
+	Duration  time.Duration
+	Error     string `json:",omitempty"`
+	DryRun    bool   `json:",omitempty"`
+}
+
+var (
+	traces         = make(map[string][]*chainTrace)
+	chainTraceSize = DefaultChainTraceSize
+	tracesMutex    sync.RWMutex
+)
+
+type chainTraceKey struct{}
+
+// SetChainTraceSize sets the number of traces kept for each chain.
+func SetChainTraceSize(n int) {
+	if n < 1 {
+		n = 1
+	}
+
+	tracesMutex.Lock()
+	defer tracesMutex.Unlock()
+
+	chainTraceSize = n
+	for name, ts := range traces {
+		if len(ts) > n {
+			traces[name] = append([]*chainTrace(nil), ts[len(ts)-n:]...)
+		}
+	}
+}
+
+// ChainTraces returns up to limit of the most recent traces of a chain,
+// newest first. A limit of 0 returns all traces kept.
+func ChainTraces(chainName string, limit int) []ChainTrace {
+	tracesMutex.RLock()
+	defer tracesMutex.RUnlock()
+
+	ts := traces[chainName]
+	if limit <= 0 || limit > len(ts) {
+		limit = len(ts)
+	}
+
+	r := make([]ChainTrace, 0, limit)
+	for i := len(ts) - 1; i >= len(ts)-limit; i-- {
+		r = append(r, ts[i].snapshot())
+	}
+	return r
+}
+
+// deleteChainTraces drops the traces of a chain.
+func deleteChainTraces(chainName string) {
+	tracesMutex.Lock()
+	defer tracesMutex.Unlock()
+
+	delete(traces, chainName)
+}
+
+// pruneChainTraces drops the traces of all chains not in cs.
+func pruneChainTraces(cs []Chain) {
+	names := make(map[string]struct{})
+	for _, c := range cs {
+		names[c.Name] = struct{}{}
+	}
+
+	tracesMutex.Lock()
+	defer tracesMutex.Unlock()
+	for name := range traces {
+		if _, ok := names[name]; !ok {
+			delete(traces, name)
+		}
+	}
+}
+
+// beginChainTrace starts tracing a chain's handling of event. The trace gets
+// stored once the returned function got called.
+func beginChainTrace(ctx context.Context, c Chain, event *Event) (context.Context, *chainTrace, func()) {
+	t := &chainTrace{ChainTrace: ChainTrace{
+		ChainName: c.Name,
+		Event:     *event,
+		StartedAt: clock.Now(),
+	}}
+
+	return context.WithValue(ctx, chainTraceKey{}, t), t, func() {
+		t.mutex.Lock()
+		t.Duration = clock.Now().Sub(t.StartedAt)
+		t.mutex.Unlock()
+
+		tracesMutex.Lock()
+		defer tracesMutex.Unlock()
+		ts := append(traces[c.Name], t)
+		if len(ts) > chainTraceSize {
+			ts = ts[len(ts)-chainTraceSize:]
+		}
+		traces[c.Name] = ts
+	}
+}
+
+// chainTraceOf returns the trace recorded in ctx, if any.
+func chainTraceOf(ctx context.Context) *chainTrace {
+	t, _ := ctx.Value(chainTraceKey{}).(*chainTrace)
+	return t
+}
+
+// conclude sets the outcome of a trace. It is a no-op for nil traces.
+func (t *chainTrace) conclude(outcome, reason string, err error) {
+	if t == nil {
+		return
+	}
+
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	t.Outcome = outcome
+	t.Reason = reason
+	if err != nil {
+		t.Error = err.Error()
+	}
+}
+
+// executed records the outcome of a chain execution in a trace. It is a no-op
+// for nil traces.
+func (t *chainTrace) executed(exec ChainExecution) {
+	if t == nil {
+		return
+	}
+
+	t.mutex.Lock()
+	t.ExecutionID = exec.ID
+	t.mutex.Unlock()
+	if exec.Err != nil {
+		t.conclude(TraceFailed, "", exec.Err)
+	} else {
+		t.conclude(TraceSucceeded, "", nil)
+	}
+}
+
+// addAction adds an executed action to a trace. It is a no-op for nil
+// traces.
+func (t *chainTrace) addAction(a ActionTrace) {
+	if t == nil {
+		return
+	}
+
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	t.Actions = append(t.Actions, a)
+}
+
+// addFilter adds the result of a filter expression to a trace. It is a no-op
+// for nil traces.
+func (t *chainTrace) addFilter(f FilterTrace) {
+	if t == nil {
+		return
+	}
+
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	t.Filters = append(t.Filters, f)
+}
+
+// traceAction traces the execution of a resolved action. The returned
+// function has to be called once the action completed, with its results or
+// the value it panicked with.
+func traceAction(ctx context.Context, a Action) func(res []Placeholder, failure interface{}) {
+	t := chainTraceOf(ctx)
+	if t == nil {
+		return func([]Placeholder, interface{}) {}
+	}
+
+	step := ActionTrace{
+		Bee:       a.Bee,
+		Name:      a.Name,
+		Options:   append(Placeholders(nil), a.Options...),
+		StartedAt: clock.Now(),
+	}
+	return func(res []Placeholder, failure interface{}) {
+		step.Duration = clock.Now().Sub(step.StartedAt)
+		step.Results = res
+		if failure != nil {
+			step.Error = fmt.Sprint(failure)
+		}
+		t.addAction(step)
+	}
+}
+
+// snapshot returns a copy of a trace, which may still be recorded.
+func (t *chainTrace) snapshot() ChainTrace {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	r := t.ChainTrace
+	r.Filters = append([]FilterTrace(nil), t.Filters...)
+	r.Actions = append([]ActionTrace(nil), t.Actions...)
+	return r
+}
@@ -0,0 +1,73 @@
+package bees
+
+import (
+	"context"
+	"testing"
+)
+
+func TestChainTraces(t *testing.T) {
+	bee := newRecordingBee("tracebee")
+	defer DeleteBee(GetBee("tracebee"))
+
+	oldActions := GetActions()
+	defer SetActions(oldActions)
+	SetActions([]Action{
+		{ID: "greet", Bee: "tracebee", Name: "greet", Options: Placeholders{
+			{Name: "text", Type: "string", Value: "Hello {{.user}}"},
+		}},
+	})
+	defer deleteChainTraces("traced")
+
+	c := Chain{
+		Name:    "traced",
+		Event:   &Event{Bee: "tracebee", Name: "message"},
+		Filters: []string{`{{test Contains .user "a"}}`},
+		Actions: []string{"greet"},
+	}
+	for _, user := range []string{"alice", "bob", "carol"} {
+		execChain(context.Background(), c, &Event{Bee: "tracebee", Name: "message", Options: Placeholders{{Name: "user", Type: "string", Value: user}}}, nil, false)
+	}
+	if got := bee.executed(); len(got) != 2 {
+		t.Fatalf("Expected two executed actions, got %v", got)
+	}
+
+	ts := ChainTraces("traced", 0)
+	if len(ts) != 3 {
+		t.Fatalf("Expected 3 traces, got %d", len(ts))
+	}
+	if user := ts[0].Event.Options.Value("user"); user != "carol" {
+		t.Errorf("Expected newest trace first, got event for %v", user)
+	}
+
+	filtered := ts[1]
+	if filtered.Outcome != TraceFiltered || len(filtered.Actions) != 0 {
+		t.Errorf("Expected a filtered trace without actions, got %+v", filtered)
+	}
+	if len(filtered.Filters) != 1 || filtered.Filters[0].Passed {
+		t.Errorf("Expected a failed filter in the trace, got %+v", filtered.Filters)
+	}
+
+	tr := ts[0]
+	if tr.Outcome != TraceSucceeded || len(tr.ExecutionID) == 0 {
+		t.Errorf("Expected a succeeded trace with an execution ID, got %+v", tr)
+	}
+	if len(tr.Filters) != 1 || !tr.Filters[0].Passed || tr.Filters[0].Filter != c.Filters[0] {
+		t.Errorf("Expected a passed filter in the trace, got %+v", tr.Filters)
+	}
+	if len(tr.Actions) != 1 {
+		t.Fatalf("Expected one traced action, got %+v", tr.Actions)
+	}
+	if text := tr.Actions[0].Options.Value("text"); text != "Hello carol" {
+		t.Errorf("Expected rendered action options, got %v", text)
+	}
+
+	if ts := ChainTraces("traced", 1); len(ts) != 1 || ts[0].Event.Options.Value("user") != "carol" {
+		t.Errorf("Expected only the newest trace, got %+v", ts)
+	}
+
+	SetChainTraceSize(2)
+	defer SetChainTraceSize(DefaultChainTraceSize)
+	if ts := ChainTraces("traced", 0); len(ts) != 2 {
+		t.Errorf("Expected traces to be trimmed to 2, got %d", len(ts))
+	}
+}
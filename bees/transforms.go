@@ -0,0 +1,93 @@
+/*
+ *    Copyright (C) 2026 Christian Muehlhaeuser
+ *
+ *    This program is free software: you can redistribute it and/or modify
+ *    it under the terms of the GNU Affero General Public License as published
+ *    by the Free Software Foundation, either version 3 of the License, or
+ *    (at your option) any later version.
+ *
+ *    This program is distributed in the hope that it will be useful,
+ *    but WITHOUT ANY WARRANTY; without even the implied warranty of
+ *    MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ *    GNU Affero General Public License for more details.
+ *
+ *    You should have received a copy of the GNU Affero General Public License
+ *    along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ *    Authors:
+ *      Christian Muehlhaeuser <muesli@gmail.com>
+ */
+
+// Package bees is Beehive's central module system.
+package bees
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/muesli/beehive/transformers"
+)
+
+// DefaultTransformer is the transformer evaluating a Transformation's
+// expression, unless it names another one.
+const DefaultTransformer = "jq"
+
+// A Transformation computes a placeholder for a chain's actions from the
+// placeholders of the event that triggered it, e.g. the second word of a
+// message:
+//
+//	{"Name": "word", "Expr": ".text | split(\" \") | .[1]"}
+type Transformation struct {
+	// Name is the placeholder getting set, replacing any existing one.
+	Name string
+	// Expr is the expression computing the placeholder's value.
+	Expr string
+	// Transformer is the name of the transformer evaluating Expr, see
+	// transformers.RegisterTransformer. Defaults to DefaultTransformer.
+	Transformer string `json:",omitempty"`
+}
+
+// transformer returns the transformer evaluating t's expression.
+func (t Transformation) transformer() *transformers.TransformerInterface {
+	if len(t.Transformer) == 0 {
+		return transformers.GetTransformer(DefaultTransformer)
+	}
+	return transformers.GetTransformer(t.Transformer)
+}
+
+// ValidateTransformation checks that a transformation's transformer exists
+// and its expression is valid.
+func ValidateTransformation(t Transformation) error {
+	if len(t.Name) == 0 {
+		return errors.New("Transformations need a placeholder name")
+	}
+	transformer := t.transformer()
+	if transformer == nil {
+		return fmt.Errorf("Unknown transformer %s", t.Transformer)
+	}
+	if err := (*transformer).Validate(t.Expr); err != nil {
+		return fmt.Errorf("Invalid transformation %s: %v", t.Name, err)
+	}
+	return nil
+}
+
+// applyTransforms evaluates the transformations of chain c in order, adding
+// their results to m. Each transformation sees the results of the previous
+// ones. Expressions not producing a value leave m as it is.
+func applyTransforms(c Chain, m map[string]interface{}) error {
+	for _, t := range c.Transform {
+		transformer := t.transformer()
+		if transformer == nil {
+			return fmt.Errorf("Unknown transformer %s", t.Transformer)
+		}
+
+		v, err := (*transformer).Transform(m, t.Expr)
+		if err != nil {
+			return fmt.Errorf("Transformation %s failed: %v", t.Name, err)
+		}
+		if v != nil {
+			m[t.Name] = v
+		}
+	}
+	return nil
+}
@@ -0,0 +1,63 @@
+package bees
+
+import (
+	"context"
+	"testing"
+
+	_ "github.com/muesli/beehive/transformers/jq"
+)
+
+func TestChainTransform(t *testing.T) {
+	bee := newRecordingBee("transformbee")
+	defer DeleteBee(GetBee("transformbee"))
+
+	oldActions := GetActions()
+	defer SetActions(oldActions)
+	SetActions([]Action{
+		{ID: "reply", Bee: "transformbee", Name: "reply", Options: Placeholders{
+			{Name: "text", Type: "string", Value: "Deploying {{.target}} ({{.count}} tags, first: {{.first}})"},
+		}},
+	})
+
+	c := Chain{
+		Name:  "transformed",
+		Event: &Event{Bee: "transformbee", Name: "message"},
+		Transform: []Transformation{
+			{Name: "target", Expr: `.text | split(" ") | .[1:] | join(" ")`},
+			{Name: "count", Expr: `.tags | length`},
+			{Name: "first", Expr: `.tags[0] | ascii_upcase`},
+			{Name: "skipped", Expr: `select(.count > 5)`},
+		},
+		Actions: []string{"reply"},
+	}
+	if errs := ValidateChain(c); len(errs) > 0 {
+		t.Fatalf("Expected a valid chain, got %v", errs)
+	}
+
+	ev := &Event{Bee: "transformbee", Name: "message", Options: Placeholders{
+		{Name: "text", Type: "string", Value: "!deploy beehive prod"},
+		{Name: "tags", Type: "[]string", Value: []string{"release", "hotfix"}},
+	}}
+	if exec := execChain(context.Background(), c, ev, filterCache{}, false); exec == nil || exec.Err != nil {
+		t.Fatalf("Expected the chain to execute, got %+v", exec)
+	}
+	if text := bee.options[0].Value("text"); text != "Deploying beehive prod (2 tags, first: RELEASE)" {
+		t.Errorf("Expected the transformed placeholders in the action, got %v", text)
+	}
+
+	c.Transform = []Transformation{{Name: "broken", Expr: `.text - 1`}}
+	if exec := execChain(context.Background(), c, ev, filterCache{}, false); exec == nil || exec.Err == nil {
+		t.Errorf("Expected a failing transformation to fail the chain, got %+v", exec)
+	}
+	if n := len(bee.executed()); n != 1 {
+		t.Errorf("Expected no actions to run after a failing transformation, got %d", n)
+	}
+
+	c.Transform = []Transformation{
+		{Name: "a", Expr: `.text |`},
+		{Name: "b", Expr: `.text`, Transformer: "nosuchtransformer"},
+	}
+	if errs := ValidateChain(c); len(errs) != 2 {
+		t.Errorf("Expected invalid transformations to be reported, got %v", errs)
+	}
+}
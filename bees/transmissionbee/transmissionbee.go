@@ -24,6 +24,8 @@
 package transmissionbee
 
 import (
+	"context"
+
 	"github.com/kr/pretty"
 	"github.com/odwrtw/transmission"
 
@@ -38,7 +40,7 @@ type TransmissionBee struct {
 }
 
 // Action triggers the action passed to it.
-func (mod *TransmissionBee) Action(action bees.Action) []bees.Placeholder {
+func (mod *TransmissionBee) Action(ctx context.Context, action bees.Action) []bees.Placeholder {
 	outs := []bees.Placeholder{}
 	switch action.Name {
 	case "add_torrent":
@@ -52,7 +52,7 @@ type BuildTracker struct {
 }
 
 // Run executes the Bee's event loop.
-func (mod *TravisBee) Run(eventChan chan bees.Event) {
+func (mod *TravisBee) Run(ctx context.Context, eventChan chan bees.Event) {
 
 	mod.eventChan = eventChan
 	mod.client = travis.NewClient(travis.ApiOrgUrl, mod.apiToken)
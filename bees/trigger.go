@@ -0,0 +1,67 @@
+/*
+ *    Copyright (C) 2026 Christian Muehlhaeuser
+ *
+ *    This program is free software: you can redistribute it and/or modify
+ *    it under the terms of the GNU Affero General Public License as published
+ *    by the Free Software Foundation, either version 3 of the License, or
+ *    (at your option) any later version.
+ *
+ *    This program is distributed in the hope that it will be useful,
+ *    but WITHOUT ANY WARRANTY; without even the implied warranty of
+ *    MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ *    GNU Affero General Public License for more details.
+ *
+ *    You should have received a copy of the GNU Affero General Public License
+ *    along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ *    Authors:
+ *      Christian Muehlhaeuser <muesli@gmail.com>
+ */
+
+// Package bees is Beehive's central module system.
+package bees
+
+import "errors"
+
+// TriggerableBee is an optional interface for bees that can be poked from
+// the outside, e.g. to make a polling bee fetch right away instead of
+// waiting for its next poll interval. Bees opt in deliberately by
+// implementing it; the embedded Bee doesn't.
+type TriggerableBee interface {
+	Trigger(eventChannel chan Event) error
+}
+
+var (
+	// ErrUnknownBee is returned when no bee with the requested name exists
+	ErrUnknownBee = errors.New("No bee with that name exists")
+	// ErrBeeNotRunning is returned when a bee needs to be running, but isn't
+	ErrBeeNotRunning = errors.New("Bee is not running")
+	// ErrNotTriggerable is returned when triggering a bee that doesn't
+	// implement TriggerableBee
+	ErrNotTriggerable = errors.New("Bee can't be triggered")
+)
+
+// TriggerBee asynchronously triggers the running bee with a specific name.
+// Errors returned by the bee's Trigger get logged.
+func TriggerBee(name string) error {
+	bee := GetBee(name)
+	if bee == nil {
+		return ErrUnknownBee
+	}
+	tb, ok := (*bee).(TriggerableBee)
+	if !ok {
+		return ErrNotTriggerable
+	}
+	if !(*bee).IsRunning() {
+		return ErrBeeNotRunning
+	}
+
+	in, _ := eventChannel()
+	go func() {
+		if err := tb.Trigger(in); err != nil {
+			logger.Errorf("Triggering bee %v failed: %v", name, err)
+		}
+	}()
+
+	return nil
+}
@@ -0,0 +1,36 @@
+package bees
+
+import "testing"
+
+type triggerBee struct {
+	recordingBee
+}
+
+func (mod *triggerBee) Trigger(eventChan chan Event) error {
+	return nil
+}
+
+func TestTriggerBee(t *testing.T) {
+	mod := &triggerBee{recordingBee: recordingBee{Bee: NewBee("triggerbee", "recordingbee", "", BeeOptions{})}}
+	RegisterBee(mod)
+	defer DeleteBee(GetBee("triggerbee"))
+
+	if err := TriggerBee("triggerbee"); err != ErrBeeNotRunning {
+		t.Errorf("Expected ErrBeeNotRunning for stopped bee, got %v", err)
+	}
+
+	mod.Start()
+	if err := TriggerBee("triggerbee"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := TriggerBee("nosuchbee"); err != ErrUnknownBee {
+		t.Errorf("Expected ErrUnknownBee, got %v", err)
+	}
+
+	newRecordingBee("plainbee")
+	defer DeleteBee(GetBee("plainbee"))
+	if err := TriggerBee("plainbee"); err != ErrNotTriggerable {
+		t.Errorf("Expected ErrNotTriggerable, got %v", err)
+	}
+}
@@ -22,6 +22,8 @@
 package tumblrbee
 
 import (
+	"context"
+
 	"github.com/MariaTerzieva/gotumblr"
 
 	"github.com/muesli/beehive/bees"
@@ -45,7 +47,7 @@ type TumblrBee struct {
 }
 
 // Action triggers the action passed to it.
-func (mod *TumblrBee) Action(action bees.Action) []bees.Placeholder {
+func (mod *TumblrBee) Action(ctx context.Context, action bees.Action) []bees.Placeholder {
 	outs := []bees.Placeholder{}
 
 	switch action.Name {
@@ -116,7 +118,7 @@ func (mod *TumblrBee) Action(action bees.Action) []bees.Placeholder {
 }
 
 // Run executes the Bee's event loop.
-func (mod *TumblrBee) Run(eventChan chan bees.Event) {
+func (mod *TumblrBee) Run(ctx context.Context, eventChan chan bees.Event) {
 	mod.evchan = eventChan
 
 	select {
@@ -18,15 +18,19 @@
  *      James Vaughan <james@jamesbvaughan.com>
  */
 
-// Package twiliobee is a Bee that is able to send SMS messages.
+// Package twiliobee is a Bee that is able to send and receive SMS messages.
 package twiliobee
 
 import (
+	"context"
+	"errors"
+	"fmt"
+
 	twilio "github.com/carlosdp/twiliogo"
 	"github.com/muesli/beehive/bees"
 )
 
-// TwilioBee is a Bee that is able to send SMS messages.
+// TwilioBee is a Bee that is able to send and receive SMS messages.
 type TwilioBee struct {
 	bees.Bee
 
@@ -35,32 +39,80 @@ type TwilioBee struct {
 	authtoken  string
 	fromNumber string
 	toNumber   string
+
+	addr       string
+	path       string
+	webhookURL string
+	insecure   bool
+
+	eventChan chan bees.Event
 }
 
-// Action triggers the action passed to it.
-func (mod *TwilioBee) Action(action bees.Action) []bees.Placeholder {
+// ActionE triggers the action passed to it.
+func (mod *TwilioBee) ActionE(ctx context.Context, action bees.Action) ([]bees.Placeholder, error) {
 	outs := []bees.Placeholder{}
 
 	switch action.Name {
 	case "send":
 		body := ""
+		to := mod.toNumber
+		var mediaURLs []string
 		action.Options.Bind("body", &body)
+		action.Options.Bind("to", &to)
+		action.Options.Bind("media_url", &mediaURLs)
+
+		if to == "" {
+			return outs, errors.New("No phone number to send the SMS to")
+		}
 
-		_, err := twilio.NewMessage(mod.client, mod.fromNumber, mod.toNumber, twilio.Body(body))
+		content := []twilio.Optional{twilio.Body(body)}
+		for _, u := range mediaURLs {
+			content = append(content, twilio.MediaUrl(u))
+		}
+		msg, err := twilio.NewMessage(mod.client, mod.fromNumber, to, content...)
 		if err != nil {
-			mod.LogErrorf("Error sending twilio SMS: %s", err)
+			return outs, fmt.Errorf("Error sending twilio SMS: %s", err)
 		}
+		outs = append(outs, bees.Placeholder{Name: "sid", Type: "string", Value: msg.Sid},
+			bees.Placeholder{Name: "status", Type: "string", Value: msg.Status})
 
 	default:
 		panic("Unknown action triggered in " + mod.Name() + ": " + action.Name)
 	}
 
+	return outs, nil
+}
+
+// Action triggers the action passed to it.
+func (mod *TwilioBee) Action(ctx context.Context, action bees.Action) []bees.Placeholder {
+	outs, err := mod.ActionE(ctx, action)
+	if err != nil {
+		mod.LogErrorf("Error: %v", err)
+	}
 	return outs
 }
 
-// Run executes the Bee's event loop.
-func (mod *TwilioBee) Run(eventChan chan bees.Event) {
-	mod.client = twilio.NewClient(mod.accountsid, mod.authtoken)
+// Run executes the Bee's event loop. Inbound messages only get received if
+// the bee was configured with an address to listen on, and with the webhook
+// URL required to verify them, unless verification was explicitly disabled.
+func (mod *TwilioBee) Run(ctx context.Context, eventChan chan bees.Event) {
+	mod.eventChan = eventChan
+
+	if mod.addr != "" && mod.webhookURL == "" && !mod.insecure {
+		mod.LogErrorf("Not receiving messages on %s: webhook_url is required to verify them", mod.addr)
+	} else if mod.addr != "" {
+		srv, err := mod.listen()
+		if err != nil {
+			mod.LogErrorf("Can't listen on %s: %v", mod.addr, err)
+			return
+		}
+		defer srv.Close()
+	}
+
+	select {
+	case <-mod.SigChan:
+	case <-ctx.Done():
+	}
 }
 
 // ReloadOptions parses the config options and initializes the Bee.
@@ -71,4 +123,10 @@ func (mod *TwilioBee) ReloadOptions(options bees.BeeOptions) {
 	options.Bind("auth_token", &mod.authtoken)
 	options.Bind("from_number", &mod.fromNumber)
 	options.Bind("to_number", &mod.toNumber)
+	options.Bind("address", &mod.addr)
+	options.Bind("path", &mod.path)
+	options.Bind("webhook_url", &mod.webhookURL)
+	options.Bind("insecure", &mod.insecure)
+
+	mod.client = twilio.NewClient(mod.accountsid, mod.authtoken)
 }
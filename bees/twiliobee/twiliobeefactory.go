@@ -51,7 +51,7 @@ func (factory *TwilioBeeFactory) Name() string {
 
 // Description returns the description of this Bee.
 func (factory *TwilioBeeFactory) Description() string {
-	return "Sends SMS messages"
+	return "Sends and receives SMS messages"
 }
 
 // Image returns the filename of an image for this Bee.
@@ -87,14 +87,84 @@ func (factory *TwilioBeeFactory) Options() []bees.BeeOptionDescriptor {
 		},
 		{
 			Name:        "to_number",
-			Description: "Phone number to send SMS messages to (ex: \"+15554815162\")",
+			Description: "Default phone number to send SMS messages to (ex: \"+15554815162\")",
 			Type:        "string",
-			Mandatory:   true,
+		},
+		{
+			Name:        "address",
+			Description: "Address to receive Twilio's webhook requests on for inbound messages, e.g. 0.0.0.0:12346",
+			Type:        "address",
+		},
+		{
+			Name:        "path",
+			Description: "Path to receive Twilio's webhook requests on",
+			Type:        "string",
+			Default:     "/",
+		},
+		{
+			Name:        "webhook_url",
+			Description: "Public URL configured as the webhook of your Twilio number, used to verify requests stem from Twilio",
+			Type:        "url",
+		},
+		{
+			Name:        "insecure",
+			Description: "Accept inbound messages without verifying they stem from Twilio",
+			Type:        "bool",
+			Default:     false,
 		},
 	}
 	return opts
 }
 
+// Events describes the available events provided by this Bee.
+func (factory *TwilioBeeFactory) Events() []bees.EventDescriptor {
+	events := []bees.EventDescriptor{
+		{
+			Namespace:   factory.Name(),
+			Name:        "message",
+			Description: "An SMS message was received",
+			Options: []bees.PlaceholderDescriptor{
+				{
+					Name:        "from",
+					Description: "Phone number of the sender",
+					Type:        "string",
+				},
+				{
+					Name:        "to",
+					Description: "Your Twilio phone number the message was sent to",
+					Type:        "string",
+				},
+				{
+					Name:        "body",
+					Description: "Message body",
+					Type:        "string",
+				},
+				{
+					Name:        "sid",
+					Description: "Twilio's ID of the message",
+					Type:        "string",
+				},
+				{
+					Name:        "media_urls",
+					Description: "URLs of the media attached to an MMS message",
+					Type:        "[]string",
+				},
+				{
+					Name:        "from_city",
+					Description: "City of the sender, if known",
+					Type:        "string",
+				},
+				{
+					Name:        "from_country",
+					Description: "Country of the sender, if known",
+					Type:        "string",
+				},
+			},
+		},
+	}
+	return events
+}
+
 // Actions describes the available actions provided by this Bee.
 func (factory *TwilioBeeFactory) Actions() []bees.ActionDescriptor {
 	actions := []bees.ActionDescriptor{
@@ -109,6 +179,16 @@ func (factory *TwilioBeeFactory) Actions() []bees.ActionDescriptor {
 					Type:        "string",
 					Mandatory:   true,
 				},
+				{
+					Name:        "to",
+					Description: "Phone number to send the message to, instead of the bee's to_number",
+					Type:        "string",
+				},
+				{
+					Name:        "media_url",
+					Description: "URLs of media to attach, sending an MMS message",
+					Type:        "[]string",
+				},
 			},
 		},
 	}
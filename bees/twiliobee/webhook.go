@@ -0,0 +1,162 @@
+/*
+ *    Copyright (C) 2026 Christian Muehlhaeuser
+ *
+ *    This program is free software: you can redistribute it and/or modify
+ *    it under the terms of the GNU Affero General Public License as published
+ *    by the Free Software Foundation, either version 3 of the License, or
+ *    (at your option) any later version.
+ *
+ *    This program is distributed in the hope that it will be useful,
+ *    but WITHOUT ANY WARRANTY; without even the implied warranty of
+ *    MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ *    GNU Affero General Public License for more details.
+ *
+ *    You should have received a copy of the GNU Affero General Public License
+ *    along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ *    Authors:
+ *      Christian Muehlhaeuser <muesli@gmail.com>
+ */
+
+package twiliobee
+
+import (
+	"crypto/hmac"
+	"crypto/sha1"
+	"encoding/base64"
+	"net"
+	"net/http"
+	"sort"
+	"strconv"
+
+	"github.com/muesli/beehive/bees"
+)
+
+// listen starts the HTTP server receiving Twilio's webhook requests.
+func (mod *TwilioBee) listen() (*http.Server, error) {
+	mux := http.NewServeMux()
+	mux.Handle(mod.path, mod)
+	srv := &http.Server{Addr: mod.addr, Handler: mux}
+
+	l, err := net.Listen("tcp", mod.addr)
+	if err != nil {
+		return nil, err
+	}
+
+	go func() {
+		err := srv.Serve(l)
+		if err != nil && err != http.ErrServerClosed {
+			mod.LogErrorf("Server error: %v", err)
+		}
+	}()
+	return srv, nil
+}
+
+func (mod *TwilioBee) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if err := req.ParseForm(); err != nil {
+		http.Error(w, "Invalid payload", http.StatusBadRequest)
+		return
+	}
+
+	if !mod.insecure && !validSignature(mod.authtoken, mod.webhookURL, req.PostForm, req.Header.Get("X-Twilio-Signature")) {
+		mod.LogErrorf("Rejected message from %s: invalid signature", req.RemoteAddr)
+		http.Error(w, "Forbidden", http.StatusForbidden)
+		return
+	}
+
+	select {
+	case mod.eventChan <- mod.messageEvent(req.PostForm):
+	case <-mod.Context().Done():
+		http.Error(w, "Bee is stopping", http.StatusServiceUnavailable)
+		return
+	case <-req.Context().Done():
+		return
+	}
+
+	// an empty TwiML response, Twilio doesn't reply to the sender then
+	w.Header().Set("Content-Type", "text/xml")
+	w.Write([]byte(`<?xml version="1.0" encoding="UTF-8"?><Response></Response>`))
+}
+
+// validSignature checks the signature Twilio computed for a request, see
+// https://www.twilio.com/docs/usage/security#validating-requests
+func validSignature(authToken, url string, form map[string][]string, signature string) bool {
+	keys := []string{}
+	for k := range form {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	mac := hmac.New(sha1.New, []byte(authToken))
+	mac.Write([]byte(url))
+	for _, k := range keys {
+		for _, v := range form[k] {
+			mac.Write([]byte(k + v))
+		}
+	}
+
+	expected := base64.StdEncoding.EncodeToString(mac.Sum(nil))
+	return hmac.Equal([]byte(expected), []byte(signature))
+}
+
+// messageEvent maps an inbound message to a message event.
+func (mod *TwilioBee) messageEvent(form map[string][]string) bees.Event {
+	value := func(k string) string {
+		if v := form[k]; len(v) > 0 {
+			return v[0]
+		}
+		return ""
+	}
+
+	n, _ := strconv.Atoi(value("NumMedia"))
+	media := []string{}
+	for i := 0; i < n; i++ {
+		media = append(media, value("MediaUrl"+strconv.Itoa(i)))
+	}
+
+	return bees.Event{
+		Bee:  mod.Name(),
+		Name: "message",
+		Options: []bees.Placeholder{
+			{
+				Name:  "from",
+				Type:  "string",
+				Value: value("From"),
+			},
+			{
+				Name:  "to",
+				Type:  "string",
+				Value: value("To"),
+			},
+			{
+				Name:  "body",
+				Type:  "string",
+				Value: value("Body"),
+			},
+			{
+				Name:  "sid",
+				Type:  "string",
+				Value: value("MessageSid"),
+			},
+			{
+				Name:  "media_urls",
+				Type:  "[]string",
+				Value: media,
+			},
+			{
+				Name:  "from_city",
+				Type:  "string",
+				Value: value("FromCity"),
+			},
+			{
+				Name:  "from_country",
+				Type:  "string",
+				Value: value("FromCountry"),
+			},
+		},
+	}
+}
@@ -0,0 +1,110 @@
+package twiliobee
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/muesli/beehive/bees"
+)
+
+// Twilio's example request, see
+// https://www.twilio.com/docs/usage/security#validating-requests
+var (
+	exampleToken = "12345"
+	exampleURL   = "https://mycompany.com/myapp.php?foo=1&bar=2"
+	exampleForm  = url.Values{
+		"CallSid": {"CA1234567890ABCDE"},
+		"Caller":  {"+12349013030"},
+		"Digits":  {"1234"},
+		"From":    {"+12349013030"},
+		"To":      {"+18005551212"},
+	}
+	exampleSignature = "0/KCTR6DLpKmkAf8muzZqo1nDgQ="
+)
+
+func TestValidSignature(t *testing.T) {
+	tampered := url.Values{}
+	for k, v := range exampleForm {
+		tampered[k] = v
+	}
+	tampered.Set("Digits", "4321")
+
+	for _, tt := range []struct {
+		name      string
+		token     string
+		url       string
+		form      url.Values
+		signature string
+		valid     bool
+	}{
+		{"example", exampleToken, exampleURL, exampleForm, exampleSignature, true},
+		{"wrong token", "54321", exampleURL, exampleForm, exampleSignature, false},
+		{"wrong url", exampleToken, "https://mycompany.com/myapp.php", exampleForm, exampleSignature, false},
+		{"tampered form", exampleToken, exampleURL, tampered, exampleSignature, false},
+		{"no signature", exampleToken, exampleURL, exampleForm, "", false},
+		{"no url", exampleToken, "", exampleForm, exampleSignature, false},
+	} {
+		if valid := validSignature(tt.token, tt.url, tt.form, tt.signature); valid != tt.valid {
+			t.Errorf("%s: expected signature to be valid: %v, got %v", tt.name, tt.valid, valid)
+		}
+	}
+}
+
+func TestServeHTTP(t *testing.T) {
+	for _, tt := range []struct {
+		name       string
+		webhookURL string
+		insecure   bool
+		signature  string
+		status     int
+	}{
+		{"signed", exampleURL, false, exampleSignature, http.StatusOK},
+		{"unsigned", exampleURL, false, "", http.StatusForbidden},
+		{"no webhook url", "", false, exampleSignature, http.StatusForbidden},
+		{"insecure", "", true, "", http.StatusOK},
+	} {
+		events := make(chan bees.Event, 1)
+		mod := &TwilioBee{
+			Bee:        bees.NewBee("twilio", "twiliobee", "", bees.BeeOptions{}),
+			authtoken:  exampleToken,
+			webhookURL: tt.webhookURL,
+			insecure:   tt.insecure,
+			eventChan:  events,
+		}
+
+		req := httptest.NewRequest("POST", "/", strings.NewReader(exampleForm.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		req.Header.Set("X-Twilio-Signature", tt.signature)
+		rec := httptest.NewRecorder()
+		mod.ServeHTTP(rec, req)
+
+		if rec.Code != tt.status {
+			t.Errorf("%s: expected status %d, got %d", tt.name, tt.status, rec.Code)
+		}
+		if received := len(events) == 1; received != (tt.status == http.StatusOK) {
+			t.Errorf("%s: expected a message event only for accepted requests", tt.name)
+		}
+	}
+}
+
+func TestServeHTTPStopped(t *testing.T) {
+	mod := &TwilioBee{
+		Bee:       bees.NewBee("twilio", "twiliobee", "", bees.BeeOptions{}),
+		insecure:  true,
+		eventChan: make(chan bees.Event),
+	}
+	mod.Start()
+	mod.Stop()
+
+	req := httptest.NewRequest("POST", "/", strings.NewReader(exampleForm.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	rec := httptest.NewRecorder()
+	mod.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("Expected a stopped bee to reject messages, got status %d", rec.Code)
+	}
+}
@@ -22,6 +22,7 @@
 package twitchbee
 
 import (
+	"context"
 	"strings"
 	"time"
 
@@ -50,7 +51,7 @@ type TwitchBee struct {
 }
 
 // Action triggers the action passed to it.
-func (mod *TwitchBee) Action(action bees.Action) []bees.Placeholder {
+func (mod *TwitchBee) Action(ctx context.Context, action bees.Action) []bees.Placeholder {
 	outs := []bees.Placeholder{}
 
 	switch action.Name {
@@ -188,7 +189,7 @@ func (mod *TwitchBee) monitorFollows(eventChan chan bees.Event) {
 }
 
 // Run executes the Bee's event loop.
-func (mod *TwitchBee) Run(eventChan chan bees.Event) {
+func (mod *TwitchBee) Run(ctx context.Context, eventChan chan bees.Event) {
 	// channel signaling Twitch connection status
 	mod.connectedState = make(chan bool)
 
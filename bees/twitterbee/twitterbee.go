@@ -23,6 +23,7 @@
 package twitterbee
 
 import (
+	"context"
 	"net/url"
 	"time"
 
@@ -70,7 +71,7 @@ func (mod *TwitterBee) handleAnacondaError(err error, msg string) {
 }
 
 // Action triggers the action passed to it.
-func (mod *TwitterBee) Action(action bees.Action) []bees.Placeholder {
+func (mod *TwitterBee) Action(ctx context.Context, action bees.Action) []bees.Placeholder {
 	outs := []bees.Placeholder{}
 	switch action.Name {
 	case "tweet":
@@ -114,7 +115,7 @@ func (mod *TwitterBee) Action(action bees.Action) []bees.Placeholder {
 }
 
 // Run executes the Bee's event loop.
-func (mod *TwitterBee) Run(eventChan chan bees.Event) {
+func (mod *TwitterBee) Run(ctx context.Context, eventChan chan bees.Event) {
 	mod.evchan = eventChan
 
 	anaconda.SetConsumerKey(mod.consumerKey)
@@ -0,0 +1,177 @@
+/*
+ *    Copyright (C) 2026 Christian Muehlhaeuser
+ *
+ *    This program is free software: you can redistribute it and/or modify
+ *    it under the terms of the GNU Affero General Public License as published
+ *    by the Free Software Foundation, either version 3 of the License, or
+ *    (at your option) any later version.
+ *
+ *    This program is distributed in the hope that it will be useful,
+ *    but WITHOUT ANY WARRANTY; without even the implied warranty of
+ *    MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ *    GNU Affero General Public License for more details.
+ *
+ *    You should have received a copy of the GNU Affero General Public License
+ *    along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ *    Authors:
+ *      Christian Muehlhaeuser <muesli@gmail.com>
+ */
+
+// Package bees is Beehive's central module system.
+package bees
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+)
+
+// variablesKey is the name chain variables get persisted under in the
+// StateStore, as both the bee name and the key.
+const variablesKey = "_variables"
+
+var (
+	variables       = make(map[string]interface{})
+	variablesLoaded bool
+	variablesMutex  sync.Mutex
+)
+
+// loadVariables reads the persisted chain variables, unless they have been
+// loaded already. Must be called with variablesMutex held.
+func loadVariables() {
+	store := getStateStore()
+	if variablesLoaded || store == nil {
+		return
+	}
+	variablesLoaded = true
+
+	b, ok, err := store.Load(variablesKey, variablesKey)
+	if !ok && err == nil {
+		return
+	}
+	values := make(map[string]json.RawMessage)
+	if err == nil {
+		err = json.Unmarshal(b, &values)
+	}
+	if err != nil {
+		logger.Errorf("Discarding unreadable chain variables: %v", err)
+		return
+	}
+	for k, b := range values {
+		if _, ok := variables[k]; ok {
+			continue
+		}
+		var v interface{}
+		if err := json.Unmarshal(b, &v); err != nil {
+			logger.Errorf("Discarding unreadable chain variable %v: %v", k, err)
+			continue
+		}
+		variables[k] = v
+	}
+}
+
+// saveVariables persists the chain variables. Must be called with
+// variablesMutex held.
+func saveVariables() error {
+	store := getStateStore()
+	if store == nil {
+		return nil
+	}
+
+	values := make(map[string]json.RawMessage, len(variables))
+	for k, v := range variables {
+		b, err := json.Marshal(v)
+		if err != nil {
+			return err
+		}
+		values[k] = b
+	}
+	b, err := json.Marshal(values)
+	if err != nil {
+		return err
+	}
+	return store.Save(variablesKey, variablesKey, b)
+}
+
+// resetVariables discards the chain variables kept in memory, so they get
+// loaded from the StateStore again on next access.
+func resetVariables() {
+	variablesMutex.Lock()
+	defer variablesMutex.Unlock()
+
+	variables = make(map[string]interface{})
+	variablesLoaded = false
+}
+
+// SetVariable sets a chain variable. Chain variables are shared by all
+// chains, available to filters and templates as "vars", and persisted in
+// the StateStore, if one is configured.
+func SetVariable(name string, value interface{}) error {
+	variablesMutex.Lock()
+	defer variablesMutex.Unlock()
+
+	loadVariables()
+	variables[name] = value
+	return saveVariables()
+}
+
+// IncrementVariable adds delta to a numeric chain variable and returns its
+// new value. Unset variables count as 0.
+func IncrementVariable(name string, delta float64) (float64, error) {
+	variablesMutex.Lock()
+	defer variablesMutex.Unlock()
+
+	loadVariables()
+	var n float64
+	switch v := variables[name].(type) {
+	case nil:
+	case float64:
+		n = v
+	case int:
+		n = float64(v)
+	case int64:
+		n = float64(v)
+	default:
+		return 0, fmt.Errorf("Chain variable %s is not a number: %v", name, v)
+	}
+
+	n += delta
+	variables[name] = n
+	return n, saveVariables()
+}
+
+// DeleteVariable removes a chain variable.
+func DeleteVariable(name string) error {
+	variablesMutex.Lock()
+	defer variablesMutex.Unlock()
+
+	loadVariables()
+	if _, ok := variables[name]; !ok {
+		return nil
+	}
+	delete(variables, name)
+	return saveVariables()
+}
+
+// GetVariable returns the value of a chain variable, or nil if it isn't set.
+func GetVariable(name string) interface{} {
+	variablesMutex.Lock()
+	defer variablesMutex.Unlock()
+
+	loadVariables()
+	return variables[name]
+}
+
+// GetVariables returns a copy of all chain variables.
+func GetVariables() map[string]interface{} {
+	variablesMutex.Lock()
+	defer variablesMutex.Unlock()
+
+	loadVariables()
+	vars := make(map[string]interface{}, len(variables))
+	for k, v := range variables {
+		vars[k] = v
+	}
+	return vars
+}
@@ -0,0 +1,51 @@
+package bees
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+)
+
+func TestVariables(t *testing.T) {
+	dir, err := ioutil.TempDir("", "beehive-variables")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	SetDataDir(dir)
+	defer SetStateStore(nil)
+
+	for i := 0; i < 3; i++ {
+		if _, err := IncrementVariable("failures", 1); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := SetVariable("host", "example.org"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := IncrementVariable("host", 1); err == nil {
+		t.Error("Expected incrementing a string variable to fail")
+	}
+
+	// variables survive restarts
+	resetVariables()
+	if v := GetVariable("failures"); v != float64(3) {
+		t.Errorf("Expected persisted counter to be 3, got %v", v)
+	}
+
+	m := eventMap(&Event{})
+	vars, ok := m["vars"].(map[string]interface{})
+	if !ok || vars["host"] != "example.org" {
+		t.Errorf("Expected variables in the event map, got %v", m["vars"])
+	}
+
+	if err := DeleteVariable("failures"); err != nil {
+		t.Fatal(err)
+	}
+	resetVariables()
+	if v := GetVariable("failures"); v != nil {
+		t.Errorf("Expected deleted variable to be unset, got %v", v)
+	}
+	DeleteVariable("host")
+}
@@ -0,0 +1,99 @@
+/*
+ *    Copyright (C) 2026 Christian Muehlhaeuser
+ *
+ *    This program is free software: you can redistribute it and/or modify
+ *    it under the terms of the GNU Affero General Public License as published
+ *    by the Free Software Foundation, either version 3 of the License, or
+ *    (at your option) any later version.
+ *
+ *    This program is distributed in the hope that it will be useful,
+ *    but WITHOUT ANY WARRANTY; without even the implied warranty of
+ *    MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ *    GNU Affero General Public License for more details.
+ *
+ *    You should have received a copy of the GNU Affero General Public License
+ *    along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ *    Authors:
+ *      Christian Muehlhaeuser <muesli@gmail.com>
+ */
+
+// Package variablesbee is a Bee that lets chains set and increment chain
+// variables.
+package variablesbee
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/muesli/beehive/bees"
+)
+
+// VariablesBee is a Bee that lets chains set and increment chain variables.
+type VariablesBee struct {
+	bees.Bee
+}
+
+// Run executes the Bee's event loop.
+func (mod *VariablesBee) Run(ctx context.Context, eventChan chan bees.Event) {
+	select {
+	case <-mod.SigChan:
+	case <-ctx.Done():
+	}
+}
+
+// ActionE triggers the action passed to it.
+func (mod *VariablesBee) ActionE(ctx context.Context, action bees.Action) ([]bees.Placeholder, error) {
+	outs := []bees.Placeholder{}
+
+	var name string
+	action.Options.Bind("name", &name)
+
+	switch action.Name {
+	case "set":
+		v := action.Options.Value("value")
+		// templated values are strings, keep numbers comparable in filters
+		if s, ok := v.(string); ok {
+			if n, err := strconv.ParseFloat(s, 64); err == nil {
+				v = n
+			}
+		}
+		if err := bees.SetVariable(name, v); err != nil {
+			return outs, err
+		}
+		outs = append(outs, bees.Placeholder{Name: "value", Type: "string", Value: v})
+
+	case "increment":
+		by := 1.0
+		action.Options.Bind("by", &by)
+		v, err := bees.IncrementVariable(name, by)
+		if err != nil {
+			return outs, err
+		}
+		outs = append(outs, bees.Placeholder{Name: "value", Type: "float64", Value: v})
+
+	case "delete":
+		if err := bees.DeleteVariable(name); err != nil {
+			return outs, err
+		}
+
+	default:
+		panic("Unknown action triggered in " + mod.Name() + ": " + action.Name)
+	}
+
+	return outs, nil
+}
+
+// Action triggers the action passed to it.
+func (mod *VariablesBee) Action(ctx context.Context, action bees.Action) []bees.Placeholder {
+	outs, err := mod.ActionE(ctx, action)
+	if err != nil {
+		mod.LogErrorf("Error: %v", err)
+	}
+	return outs
+}
+
+// ReloadOptions parses the config options and initializes the Bee.
+func (mod *VariablesBee) ReloadOptions(options bees.BeeOptions) {
+	mod.SetOptions(options)
+}
@@ -0,0 +1,127 @@
+/*
+ *    Copyright (C) 2026 Christian Muehlhaeuser
+ *
+ *    This program is free software: you can redistribute it and/or modify
+ *    it under the terms of the GNU Affero General Public License as published
+ *    by the Free Software Foundation, either version 3 of the License, or
+ *    (at your option) any later version.
+ *
+ *    This program is distributed in the hope that it will be useful,
+ *    but WITHOUT ANY WARRANTY; without even the implied warranty of
+ *    MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ *    GNU Affero General Public License for more details.
+ *
+ *    You should have received a copy of the GNU Affero General Public License
+ *    along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ *    Authors:
+ *      Christian Muehlhaeuser <muesli@gmail.com>
+ */
+
+package variablesbee
+
+import (
+	"github.com/muesli/beehive/bees"
+)
+
+// VariablesBeeFactory is a factory for VariablesBees.
+type VariablesBeeFactory struct {
+	bees.BeeFactory
+}
+
+// New returns a new Bee instance configured with the supplied options.
+func (factory *VariablesBeeFactory) New(name, description string, options bees.BeeOptions) bees.BeeInterface {
+	bee := VariablesBee{
+		Bee: bees.NewBee(name, factory.ID(), description, options),
+	}
+	bee.ReloadOptions(options)
+
+	return &bee
+}
+
+// ID returns the ID of this Bee.
+func (factory *VariablesBeeFactory) ID() string {
+	return "variablesbee"
+}
+
+// Name returns the name of this Bee.
+func (factory *VariablesBeeFactory) Name() string {
+	return "Chain Variables"
+}
+
+// Description returns the description of this Bee.
+func (factory *VariablesBeeFactory) Description() string {
+	return "Sets and increments variables, which filters can access as vars, e.g. vars.failures > 3"
+}
+
+// Image returns the filename of an image for this Bee.
+func (factory *VariablesBeeFactory) Image() string {
+	return "redis.png"
+}
+
+// LogoColor returns the preferred logo background color (used by the admin interface).
+func (factory *VariablesBeeFactory) LogoColor() string {
+	return "#4b7bbe"
+}
+
+// Actions describes the available actions provided by this Bee.
+func (factory *VariablesBeeFactory) Actions() []bees.ActionDescriptor {
+	actions := []bees.ActionDescriptor{
+		{
+			Namespace:   factory.Name(),
+			Name:        "set",
+			Description: "Sets a variable",
+			Options: []bees.PlaceholderDescriptor{
+				{
+					Name:        "name",
+					Description: "Name of the variable",
+					Type:        "string",
+					Mandatory:   true,
+				},
+				{
+					Name:        "value",
+					Description: "New value of the variable",
+					Type:        "string",
+					Mandatory:   true,
+				},
+			},
+		},
+		{
+			Namespace:   factory.Name(),
+			Name:        "increment",
+			Description: "Increments a numeric variable, unset variables start at 0",
+			Options: []bees.PlaceholderDescriptor{
+				{
+					Name:        "name",
+					Description: "Name of the variable",
+					Type:        "string",
+					Mandatory:   true,
+				},
+				{
+					Name:        "by",
+					Description: "Amount to add, defaults to 1. Use negative values to decrement",
+					Type:        "float64",
+				},
+			},
+		},
+		{
+			Namespace:   factory.Name(),
+			Name:        "delete",
+			Description: "Removes a variable, e.g. to reset a counter",
+			Options: []bees.PlaceholderDescriptor{
+				{
+					Name:        "name",
+					Description: "Name of the variable",
+					Type:        "string",
+					Mandatory:   true,
+				},
+			},
+		},
+	}
+	return actions
+}
+
+func init() {
+	f := VariablesBeeFactory{}
+	bees.RegisterFactory(&f)
+}
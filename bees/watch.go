@@ -0,0 +1,103 @@
+/*
+ *    Copyright (C) 2026 Christian Muehlhaeuser
+ *
+ *    This program is free software: you can redistribute it and/or modify
+ *    it under the terms of the GNU Affero General Public License as published
+ *    by the Free Software Foundation, either version 3 of the License, or
+ *    (at your option) any later version.
+ *
+ *    This program is distributed in the hope that it will be useful,
+ *    but WITHOUT ANY WARRANTY; without even the implied warranty of
+ *    MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ *    GNU Affero General Public License for more details.
+ *
+ *    You should have received a copy of the GNU Affero General Public License
+ *    along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ *    Authors:
+ *      Christian Muehlhaeuser <muesli@gmail.com>
+ */
+
+// Package bees is Beehive's central module system.
+package bees
+
+import (
+	"path"
+	"strings"
+	"sync"
+)
+
+// watchBufferSize is the number of events buffered for each watcher.
+const watchBufferSize = 64
+
+type watcher struct {
+	pattern string
+	ch      chan Event
+}
+
+var (
+	watchers     []*watcher
+	watcherMutex sync.RWMutex
+)
+
+// Watch returns a channel delivering all events whose name matches pattern.
+// The pattern may contain shell-style wildcards (see path.Match) and can be
+// prefixed with a bee name, e.g. "ircbee/message" or "*/time".
+//
+// The channel is buffered. Events that arrive while the buffer is full are
+// dropped for this watcher, so a slow consumer can never stall the hive.
+// Call Unwatch to unsubscribe and close the channel.
+func Watch(pattern string) <-chan Event {
+	w := &watcher{
+		pattern: pattern,
+		ch:      make(chan Event, watchBufferSize),
+	}
+
+	watcherMutex.Lock()
+	defer watcherMutex.Unlock()
+	watchers = append(watchers, w)
+
+	return w.ch
+}
+
+// Unwatch unsubscribes a channel returned by Watch and closes it.
+func Unwatch(ch <-chan Event) {
+	watcherMutex.Lock()
+	defer watcherMutex.Unlock()
+
+	for i, w := range watchers {
+		if w.ch == ch {
+			watchers = append(watchers[:i], watchers[i+1:]...)
+			close(w.ch)
+			return
+		}
+	}
+}
+
+// matches returns whether an event matches the watcher's pattern.
+func (w *watcher) matches(event *Event) bool {
+	name := event.Name
+	if strings.Contains(w.pattern, "/") {
+		name = event.Bee + "/" + event.Name
+	}
+
+	ok, _ := path.Match(w.pattern, name)
+	return ok
+}
+
+// notifyWatchers delivers an event to all matching watchers.
+func notifyWatchers(event Event) {
+	watcherMutex.RLock()
+	defer watcherMutex.RUnlock()
+
+	for _, w := range watchers {
+		if !w.matches(&event) {
+			continue
+		}
+
+		select {
+		case w.ch <- event:
+		default:
+		}
+	}
+}
@@ -0,0 +1,29 @@
+package bees
+
+import "testing"
+
+func TestWatch(t *testing.T) {
+	all := Watch("*")
+	msgs := Watch("ircbee/message")
+	defer Unwatch(all)
+
+	notifyWatchers(Event{Bee: "ircbee", Name: "message"})
+	notifyWatchers(Event{Bee: "ircbee", Name: "join"})
+	notifyWatchers(Event{Bee: "otherbee", Name: "message"})
+
+	if len(all) != 3 {
+		t.Errorf("Expected 3 events for wildcard watcher, got %d", len(all))
+	}
+	if len(msgs) != 1 {
+		t.Errorf("Expected 1 event for bee/event watcher, got %d", len(msgs))
+	}
+	if ev := <-msgs; ev.Bee != "ircbee" || ev.Name != "message" {
+		t.Errorf("Unexpected event %+v", ev)
+	}
+
+	Unwatch(msgs)
+	if _, ok := <-msgs; ok {
+		t.Error("Expected channel to be closed after Unwatch")
+	}
+	notifyWatchers(Event{Bee: "ircbee", Name: "message"})
+}
@@ -23,6 +23,7 @@
 package webbee
 
 import (
+	"context"
 	"encoding/json"
 	"io/ioutil"
 	"net"
@@ -43,7 +44,7 @@ type WebBee struct {
 }
 
 // Run executes the Bee's event loop.
-func (mod *WebBee) Run(cin chan bees.Event) {
+func (mod *WebBee) Run(ctx context.Context, cin chan bees.Event) {
 	mod.eventChan = cin
 
 	srv := &http.Server{Addr: mod.addr, Handler: mod}
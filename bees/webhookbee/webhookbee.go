@@ -0,0 +1,295 @@
+/*
+ *    Copyright (C) 2026 Christian Muehlhaeuser
+ *
+ *    This program is free software: you can redistribute it and/or modify
+ *    it under the terms of the GNU Affero General Public License as published
+ *    by the Free Software Foundation, either version 3 of the License, or
+ *    (at your option) any later version.
+ *
+ *    This program is distributed in the hope that it will be useful,
+ *    but WITHOUT ANY WARRANTY; without even the implied warranty of
+ *    MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ *    GNU Affero General Public License for more details.
+ *
+ *    You should have received a copy of the GNU Affero General Public License
+ *    along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ *    Authors:
+ *      Christian Muehlhaeuser <muesli@gmail.com>
+ */
+
+// Package webhookbee is a Bee that exposes an HTTP endpoint for webhooks and
+// extracts values from the posted JSON payloads.
+package webhookbee
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"hash"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/muesli/beehive/bees"
+)
+
+// WebhookBee is a Bee that exposes an HTTP endpoint for webhooks.
+type WebhookBee struct {
+	bees.Bee
+
+	addr               string
+	path               string
+	secret             string
+	signatureHeader    string
+	signatureAlgorithm string
+	maxSize            int64
+	fields             []field
+
+	eventChan chan bees.Event
+}
+
+// A field extracts a single value of the payload into a placeholder.
+type field struct {
+	name string
+	path []string
+}
+
+// Run executes the Bee's event loop.
+func (mod *WebhookBee) Run(ctx context.Context, cin chan bees.Event) {
+	mod.eventChan = cin
+
+	mux := http.NewServeMux()
+	mux.Handle(mod.path, mod)
+	srv := &http.Server{Addr: mod.addr, Handler: mux}
+
+	l, err := net.Listen("tcp", mod.addr)
+	if err != nil {
+		mod.LogErrorf("Can't listen on %s", mod.addr)
+		return
+	}
+
+	go func() {
+		err := srv.Serve(l)
+		if err != nil && err != http.ErrServerClosed {
+			mod.LogErrorf("Server error: %v", err)
+		}
+	}()
+
+	select {
+	case <-mod.SigChan:
+	case <-ctx.Done():
+	}
+	srv.Close()
+}
+
+func (mod *WebhookBee) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodPost && req.Method != http.MethodPut {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	defer req.Body.Close()
+	b, err := ioutil.ReadAll(http.MaxBytesReader(w, req.Body, mod.maxSize))
+	if err != nil {
+		http.Error(w, "Request body too large", http.StatusRequestEntityTooLarge)
+		return
+	}
+
+	if mod.secret != "" && !mod.validSignature(req.Header.Get(mod.signatureHeader), b) {
+		mod.LogErrorf("Rejected webhook from %s: invalid signature", req.RemoteAddr)
+		http.Error(w, "Invalid signature", http.StatusUnauthorized)
+		return
+	}
+
+	headers := make(map[string]interface{})
+	for k := range req.Header {
+		headers[k] = req.Header.Get(k)
+	}
+
+	ev := bees.Event{
+		Bee:  mod.Name(),
+		Name: "webhook",
+		Options: []bees.Placeholder{
+			{
+				Name:  "remote_addr",
+				Type:  "address",
+				Value: req.RemoteAddr,
+			},
+			{
+				Name:  "path",
+				Type:  "string",
+				Value: req.URL.Path,
+			},
+			{
+				Name:  "headers",
+				Type:  "map",
+				Value: headers,
+			},
+			{
+				Name:  "data",
+				Type:  "string",
+				Value: string(b),
+			},
+		},
+	}
+
+	var payload interface{}
+	if err := json.Unmarshal(b, &payload); err == nil {
+		ev.Options.SetValue("json", "map", payload)
+
+		for _, f := range mod.fields {
+			v, ok := extract(payload, f.path)
+			if !ok {
+				continue
+			}
+			ev.Options.SetValue(f.name, placeholderType(v), v)
+		}
+	}
+
+	select {
+	case mod.eventChan <- ev:
+	case <-mod.Context().Done():
+		http.Error(w, "Bee is stopping", http.StatusServiceUnavailable)
+		return
+	case <-req.Context().Done():
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// validSignature checks the HMAC signature of a payload, using the bee's
+// signature algorithm. Signatures may be prefixed with the algorithm, e.g.
+// "sha256=..." as sent by GitHub, which then has to match the bee's.
+func (mod *WebhookBee) validSignature(signature string, payload []byte) bool {
+	var h func() hash.Hash
+	switch mod.signatureAlgorithm {
+	case "sha1":
+		h = sha1.New
+	case "sha256":
+		h = sha256.New
+	default:
+		return false
+	}
+	if i := strings.Index(signature, "="); i >= 0 {
+		if !strings.EqualFold(signature[:i], mod.signatureAlgorithm) {
+			return false
+		}
+		signature = signature[i+1:]
+	}
+
+	sig, err := hex.DecodeString(signature)
+	if err != nil {
+		return false
+	}
+
+	return hmac.Equal(sig, sign(h, mod.secret, payload))
+}
+
+func sign(h func() hash.Hash, secret string, payload []byte) []byte {
+	mac := hmac.New(h, []byte(secret))
+	mac.Write(payload)
+	return mac.Sum(nil)
+}
+
+// parseField parses an extraction rule of the form "name=$.path.to[0].value".
+func parseField(rule string) (field, error) {
+	parts := strings.SplitN(rule, "=", 2)
+	if len(parts) != 2 || strings.TrimSpace(parts[0]) == "" {
+		return field{}, errors.New("Invalid field rule " + rule + ", expected name=path")
+	}
+
+	p := strings.TrimSpace(parts[1])
+	p = strings.TrimPrefix(strings.TrimPrefix(p, "$"), ".")
+	p = strings.Replace(p, "[", ".", -1)
+	p = strings.Replace(p, "]", "", -1)
+
+	f := field{name: strings.TrimSpace(parts[0])}
+	if p != "" {
+		f.path = strings.Split(p, ".")
+	}
+	return f, nil
+}
+
+// extract walks the decoded JSON payload along path.
+func extract(v interface{}, path []string) (interface{}, bool) {
+	for _, elem := range path {
+		switch vt := v.(type) {
+		case map[string]interface{}:
+			var ok bool
+			if v, ok = vt[elem]; !ok {
+				return nil, false
+			}
+		case []interface{}:
+			i, err := strconv.Atoi(elem)
+			if err != nil || i < 0 || i >= len(vt) {
+				return nil, false
+			}
+			v = vt[i]
+		default:
+			return nil, false
+		}
+	}
+
+	return v, true
+}
+
+func placeholderType(v interface{}) string {
+	switch v.(type) {
+	case string:
+		return "string"
+	case bool:
+		return "bool"
+	case float64:
+		return "float64"
+	case []interface{}:
+		return "[]interface{}"
+	default:
+		return "map"
+	}
+}
+
+// ReloadOptions parses the config options and initializes the Bee.
+func (mod *WebhookBee) ReloadOptions(options bees.BeeOptions) {
+	mod.SetOptions(options)
+
+	options.Bind("address", &mod.addr)
+
+	mod.path = "/"
+	options.Bind("path", &mod.path)
+	if !strings.HasPrefix(mod.path, "/") {
+		mod.path = "/" + mod.path
+	}
+
+	mod.secret = ""
+	options.Bind("secret", &mod.secret)
+	mod.signatureHeader = "X-Hub-Signature-256"
+	options.Bind("signature_header", &mod.signatureHeader)
+	mod.signatureAlgorithm = "sha256"
+	options.Bind("signature_algorithm", &mod.signatureAlgorithm)
+
+	size := 1024 * 1024
+	options.Bind("max_size", &size)
+	mod.maxSize = int64(size)
+
+	var rules []string
+	options.Bind("fields", &rules)
+	mod.fields = nil
+	for _, rule := range rules {
+		if strings.TrimSpace(rule) == "" {
+			continue
+		}
+		f, err := parseField(rule)
+		if err != nil {
+			mod.LogErrorf("%v", err)
+			continue
+		}
+		mod.fields = append(mod.fields, f)
+	}
+}
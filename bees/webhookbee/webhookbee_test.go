@@ -0,0 +1,110 @@
+package webhookbee
+
+import (
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestValidSignature(t *testing.T) {
+	payload := []byte(`{"zen":"Keep it logically awesome."}`)
+	sha256Sig := hex.EncodeToString(sign(sha256.New, "secret", payload))
+	sha1Sig := hex.EncodeToString(sign(sha1.New, "secret", payload))
+	otherSig := hex.EncodeToString(sign(sha256.New, "other", payload))
+
+	tests := []struct {
+		algorithm string
+		signature string
+		valid     bool
+	}{
+		{"sha256", "sha256=" + sha256Sig, true},
+		{"sha256", "SHA256=" + sha256Sig, true},
+		{"sha256", sha256Sig, true},
+		{"sha256", "sha256=" + otherSig, false},
+		{"sha256", "sha1=" + sha1Sig, false},
+		{"sha256", sha1Sig, false},
+		{"sha256", "md5=" + sha256Sig, false},
+		{"sha256", "sha256=nothex", false},
+		{"sha256", "", false},
+		{"sha1", "sha1=" + sha1Sig, true},
+		{"sha1", sha1Sig, true},
+		{"sha1", "sha256=" + sha256Sig, false},
+		{"md5", sha256Sig, false},
+	}
+	for _, tt := range tests {
+		mod := &WebhookBee{secret: "secret", signatureAlgorithm: tt.algorithm}
+		if v := mod.validSignature(tt.signature, payload); v != tt.valid {
+			t.Errorf("Expected %s signature %q to be valid: %v, got %v", tt.algorithm, tt.signature, tt.valid, v)
+		}
+	}
+}
+
+func TestParseField(t *testing.T) {
+	tests := []struct {
+		rule  string
+		field field
+		valid bool
+	}{
+		{"repo=$.repository.name", field{name: "repo", path: []string{"repository", "name"}}, true},
+		{" author = $.commits[0].author.name ", field{name: "author", path: []string{"commits", "0", "author", "name"}}, true},
+		{"ref=ref", field{name: "ref", path: []string{"ref"}}, true},
+		{"all=$", field{name: "all"}, true},
+		{"repo", field{}, false},
+		{"=$.repository.name", field{}, false},
+	}
+	for _, tt := range tests {
+		f, err := parseField(tt.rule)
+		if (err == nil) != tt.valid {
+			t.Errorf("Expected rule %q to be valid: %v, got %v", tt.rule, tt.valid, err)
+			continue
+		}
+		if !reflect.DeepEqual(f, tt.field) {
+			t.Errorf("Expected rule %q to parse as %+v, got %+v", tt.rule, tt.field, f)
+		}
+	}
+}
+
+func TestExtract(t *testing.T) {
+	var payload interface{}
+	err := json.Unmarshal([]byte(`{
+		"repository": {"name": "beehive", "private": false},
+		"commits": [{"id": "a1"}, {"id": "b2", "tags": ["v1"]}]
+	}`), &payload)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		rule  string
+		value interface{}
+		found bool
+	}{
+		{"v=$.repository.name", "beehive", true},
+		{"v=$.repository.private", false, true},
+		{"v=$.commits[1].id", "b2", true},
+		{"v=$.commits[1].tags[0]", "v1", true},
+		{"v=$.repository.owner", nil, false},
+		{"v=$.repository.name.first", nil, false},
+		{"v=$.commits[2].id", nil, false},
+		{"v=$.commits[-1].id", nil, false},
+		{"v=$.commits.first", nil, false},
+		{"v=$.missing[0]", nil, false},
+	}
+	for _, tt := range tests {
+		f, err := parseField(tt.rule)
+		if err != nil {
+			t.Fatal(err)
+		}
+		v, ok := extract(payload, f.path)
+		if ok != tt.found || !reflect.DeepEqual(v, tt.value) {
+			t.Errorf("Expected %s to extract %v (%v), got %v (%v)", tt.rule, tt.value, tt.found, v, ok)
+		}
+	}
+
+	if v, ok := extract(payload, nil); !ok || !reflect.DeepEqual(v, payload) {
+		t.Errorf("Expected an empty path to extract the whole payload, got %v", v)
+	}
+}
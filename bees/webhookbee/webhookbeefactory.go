@@ -0,0 +1,157 @@
+/*
+ *    Copyright (C) 2026 Christian Muehlhaeuser
+ *
+ *    This program is free software: you can redistribute it and/or modify
+ *    it under the terms of the GNU Affero General Public License as published
+ *    by the Free Software Foundation, either version 3 of the License, or
+ *    (at your option) any later version.
+ *
+ *    This program is distributed in the hope that it will be useful,
+ *    but WITHOUT ANY WARRANTY; without even the implied warranty of
+ *    MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ *    GNU Affero General Public License for more details.
+ *
+ *    You should have received a copy of the GNU Affero General Public License
+ *    along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ *    Authors:
+ *      Christian Muehlhaeuser <muesli@gmail.com>
+ */
+
+package webhookbee
+
+import (
+	"github.com/muesli/beehive/bees"
+)
+
+// WebhookBeeFactory is a factory for WebhookBees.
+type WebhookBeeFactory struct {
+	bees.BeeFactory
+}
+
+// New returns a new Bee instance configured with the supplied options.
+func (factory *WebhookBeeFactory) New(name, description string, options bees.BeeOptions) bees.BeeInterface {
+	bee := WebhookBee{
+		Bee: bees.NewBee(name, factory.ID(), description, options),
+	}
+	bee.ReloadOptions(options)
+
+	return &bee
+}
+
+// ID returns the ID of this Bee.
+func (factory *WebhookBeeFactory) ID() string {
+	return "webhookbee"
+}
+
+// Name returns the name of this Bee.
+func (factory *WebhookBeeFactory) Name() string {
+	return "Webhook"
+}
+
+// Description returns the description of this Bee.
+func (factory *WebhookBeeFactory) Description() string {
+	return "Receives webhooks and extracts values from their JSON payloads"
+}
+
+// Image returns the filename of an image for this Bee.
+func (factory *WebhookBeeFactory) Image() string {
+	return "webbee.png"
+}
+
+// LogoColor returns the preferred logo background color (used by the admin interface).
+func (factory *WebhookBeeFactory) LogoColor() string {
+	return "#223f5e"
+}
+
+// Options returns the options available to configure this Bee.
+func (factory *WebhookBeeFactory) Options() []bees.BeeOptionDescriptor {
+	opts := []bees.BeeOptionDescriptor{
+		{
+			Name:        "address",
+			Description: "Which addr to listen on, eg: 0.0.0.0:12345",
+			Type:        "address",
+			Mandatory:   true,
+		},
+		{
+			Name:        "path",
+			Description: "Path of the webhook endpoint, eg: /github",
+			Type:        "string",
+			Default:     "/",
+		},
+		{
+			Name:        "secret",
+			Description: "Secret used to validate the HMAC signature of payloads (optional)",
+			Type:        "password",
+		},
+		{
+			Name:        "signature_header",
+			Description: "Header carrying the HMAC signature, eg: X-Hub-Signature-256",
+			Type:        "string",
+			Default:     "X-Hub-Signature-256",
+		},
+		{
+			Name:        "signature_algorithm",
+			Description: "Hash algorithm of the HMAC signature, eg: sha256 for X-Hub-Signature-256 or sha1 for X-Hub-Signature",
+			Type:        "string",
+			Default:     "sha256",
+			Choices:     []interface{}{"sha256", "sha1"},
+		},
+		{
+			Name:        "max_size",
+			Description: "Maximum size of a payload in bytes",
+			Type:        "int",
+			Default:     1024 * 1024,
+		},
+		{
+			Name:        "fields",
+			Description: "Values to extract into placeholders, eg: repo=$.repository.name",
+			Type:        "[]string",
+		},
+	}
+	return opts
+}
+
+// Events describes the available events provided by this Bee.
+func (factory *WebhookBeeFactory) Events() []bees.EventDescriptor {
+	events := []bees.EventDescriptor{
+		{
+			Namespace:   factory.Name(),
+			Name:        "webhook",
+			Description: "A webhook was received. Values extracted by the fields option are added as placeholders",
+			Options: []bees.PlaceholderDescriptor{
+				{
+					Name:        "remote_addr",
+					Description: "IP of the caller",
+					Type:        "address",
+				},
+				{
+					Name:        "path",
+					Description: "Request path",
+					Type:        "string",
+				},
+				{
+					Name:        "headers",
+					Description: "Map of request headers",
+					Type:        "map",
+				},
+				{
+					Name:        "data",
+					Description: "Raw request data",
+					Type:        "string",
+				},
+				{
+					Name:        "json",
+					Description: "JSON payload received from caller",
+					Type:        "map",
+				},
+			},
+		},
+	}
+	return events
+}
+
+func init() {
+	f := WebhookBeeFactory{}
+	bees.RegisterFactory(&f)
+}
@@ -0,0 +1,325 @@
+/*
+ *    Copyright (C) 2026 Christian Muehlhaeuser
+ *
+ *    This program is free software: you can redistribute it and/or modify
+ *    it under the terms of the GNU Affero General Public License as published
+ *    by the Free Software Foundation, either version 3 of the License, or
+ *    (at your option) any later version.
+ *
+ *    This program is distributed in the hope that it will be useful,
+ *    but WITHOUT ANY WARRANTY; without even the implied warranty of
+ *    MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ *    GNU Affero General Public License for more details.
+ *
+ *    You should have received a copy of the GNU Affero General Public License
+ *    along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ *    Authors:
+ *      Christian Muehlhaeuser <muesli@gmail.com>
+ */
+
+// Package bees is Beehive's central module system.
+package bees
+
+import (
+	"context"
+	"fmt"
+	"runtime/debug"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// What happens to events matching a chain while it's inactive, see
+// Chain.OutsideWindow.
+const (
+	DropOutsideWindow  = "drop"
+	DeferOutsideWindow = "defer"
+)
+
+// maxDeferral is how far ahead an event gets held back for a chain to become
+// active again. Events of chains staying inactive for longer get dropped.
+const maxDeferral = 14 * 24 * time.Hour
+
+// ActiveWindow is a recurring time span a chain is active in, e.g. weekdays
+// from 09:00 to 17:00.
+type ActiveWindow struct {
+	// Days the window applies to, e.g. "Mon", "Mon-Fri", "weekdays" or
+	// "weekends". Empty means every day
+	Days []string `json:"Days,omitempty"`
+	// From and To are the times of day the window opens and closes at, as
+	// "15:04". Empty means the start and the end of the day. Windows closing
+	// before they open span midnight
+	From string `json:"From,omitempty"`
+	To   string `json:"To,omitempty"`
+	// Location is the time zone of the window, e.g. "Europe/Berlin". Empty
+	// means local time
+	Location string `json:"Location,omitempty"`
+}
+
+var weekdays = map[string]time.Weekday{
+	"sun": time.Sunday,
+	"mon": time.Monday,
+	"tue": time.Tuesday,
+	"wed": time.Wednesday,
+	"thu": time.Thursday,
+	"fri": time.Friday,
+	"sat": time.Saturday,
+}
+
+// parseWeekday parses a day name like "Mon" or "Monday".
+func parseWeekday(s string) (time.Weekday, error) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	if len(s) >= 3 {
+		if d, ok := weekdays[s[:3]]; ok && strings.HasPrefix(strings.ToLower(d.String()), s) {
+			return d, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown day %q", s)
+}
+
+// days returns the days the window applies to.
+func (w ActiveWindow) days() (map[time.Weekday]bool, error) {
+	days := make(map[time.Weekday]bool)
+	if len(w.Days) == 0 {
+		for d := time.Sunday; d <= time.Saturday; d++ {
+			days[d] = true
+		}
+		return days, nil
+	}
+
+	for _, s := range w.Days {
+		switch strings.ToLower(strings.TrimSpace(s)) {
+		case "weekdays":
+			s = "mon-fri"
+		case "weekends":
+			s = "sat-sun"
+		}
+
+		bounds := strings.SplitN(s, "-", 2)
+		first, err := parseWeekday(bounds[0])
+		if err != nil {
+			return nil, err
+		}
+		last := first
+		if len(bounds) == 2 {
+			if last, err = parseWeekday(bounds[1]); err != nil {
+				return nil, err
+			}
+		}
+		for d := first; ; d = (d + 1) % 7 {
+			days[d] = true
+			if d == last {
+				break
+			}
+		}
+	}
+	return days, nil
+}
+
+// timeOfDay parses a time of day like "09:00" into the time since midnight.
+// Empty strings result in def.
+func timeOfDay(s string, def time.Duration) (time.Duration, error) {
+	if len(s) == 0 {
+		return def, nil
+	}
+	if s == "24:00" {
+		return 24 * time.Hour, nil
+	}
+	t, err := time.Parse("15:04", s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid time of day %q", s)
+	}
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
+
+// contains returns whether t lies within the window.
+func (w ActiveWindow) contains(t time.Time) (bool, error) {
+	if len(w.Location) > 0 {
+		loc, err := time.LoadLocation(w.Location)
+		if err != nil {
+			return false, err
+		}
+		t = t.In(loc)
+	}
+	days, err := w.days()
+	if err != nil {
+		return false, err
+	}
+	from, err := timeOfDay(w.From, 0)
+	if err != nil {
+		return false, err
+	}
+	to, err := timeOfDay(w.To, 24*time.Hour)
+	if err != nil {
+		return false, err
+	}
+
+	day := t.Weekday()
+	tod := time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute + time.Duration(t.Second())*time.Second
+	if from <= to {
+		return days[day] && tod >= from && tod < to, nil
+	}
+	// the part after midnight belongs to the previous day's window
+	if tod >= from {
+		return days[day], nil
+	}
+	return tod < to && days[(day+6)%7], nil
+}
+
+// validate checks the window's days, times and location.
+func (w ActiveWindow) validate() error {
+	_, err := w.contains(time.Now())
+	return err
+}
+
+// inWindows returns whether t lies within one of the chain's windows, or
+// whether the chain has no windows at all.
+func (c *Chain) inWindows(t time.Time) (bool, error) {
+	if len(c.Windows) == 0 {
+		return true, nil
+	}
+
+	for _, w := range c.Windows {
+		ok, err := w.contains(t)
+		if err != nil || ok {
+			return ok, err
+		}
+	}
+	return false, nil
+}
+
+// onHolidays returns whether t lies within an event of one of the chain's
+// holiday calendars.
+func (c *Chain) onHolidays(t time.Time) bool {
+	for _, src := range c.Holidays {
+		if onHoliday(src, t) {
+			return true
+		}
+	}
+	return false
+}
+
+// nextActive returns the first minute after t the chain is active in, within
+// maxDeferral.
+func (c *Chain) nextActive(t time.Time) (time.Time, bool) {
+	t = t.Truncate(time.Minute)
+	for end := t.Add(maxDeferral); t.Before(end); {
+		t = t.Add(time.Minute)
+		if active, err := c.activeAt(t); err == nil && active {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// deferredEvent is an event held back until its chain becomes active.
+type deferredEvent struct {
+	chain string
+	event Event
+	at    time.Time
+	timer *time.Timer
+}
+
+var (
+	deferredEvents      = make(map[*deferredEvent]struct{})
+	deferredEventsMutex sync.Mutex
+)
+
+// deferEvent holds back event until c becomes active, or drops it if the
+// chain doesn't become active within maxDeferral.
+func deferEvent(c Chain, event Event) {
+	now := clock.Now()
+	next, ok := c.nextActive(now)
+	if !ok {
+		logger.Warnf("Dropping event %v / %v, chain %v doesn't become active within %v", event.Bee, event.Name, c.Name, maxDeferral)
+		return
+	}
+	logger.Debugf("Deferring event %v / %v for chain %v until %v", event.Bee, event.Name, c.Name, next)
+
+	d := &deferredEvent{chain: c.Name, event: event, at: next}
+
+	deferredEventsMutex.Lock()
+	defer deferredEventsMutex.Unlock()
+
+	deferredEvents[d] = struct{}{}
+	atomic.AddInt64(&scheduledTimers, 1)
+	d.timer = time.AfterFunc(next.Sub(now), func() {
+		fireDeferredEvent(d)
+	})
+}
+
+// undeferEvent removes a deferred event, returning false if it already fired
+// or got cancelled.
+func undeferEvent(d *deferredEvent) bool {
+	deferredEventsMutex.Lock()
+	defer deferredEventsMutex.Unlock()
+
+	if _, ok := deferredEvents[d]; !ok {
+		return false
+	}
+	delete(deferredEvents, d)
+	d.timer.Stop()
+	atomic.AddInt64(&scheduledTimers, -1)
+
+	return true
+}
+
+// fireDeferredEvent executes the chain a deferred event has been waiting for.
+// The chain's current config applies, so events of chains that got removed or
+// disabled meanwhile get dropped, and events of chains that are still
+// inactive, e.g. because a holiday calendar changed, get deferred again.
+func fireDeferredEvent(d *deferredEvent) {
+	if !undeferEvent(d) {
+		return
+	}
+	c := GetChain(d.chain)
+	if c == nil || !c.IsEnabled() {
+		logger.Debugf("Dropping deferred event of removed or disabled chain: %v", d.chain)
+		return
+	}
+
+	active, err := c.activeAt(clock.Now())
+	if err != nil {
+		logger.Errorf("Invalid schedule for chain %v: %v", c.Name, err)
+		return
+	}
+	if !active {
+		if c.OutsideWindow == DeferOutsideWindow {
+			deferEvent(*c, d.event)
+		}
+		return
+	}
+
+	defer func() {
+		if e := recover(); e != nil {
+			logger.Errorf("Fatal deferred event: %s %s", e, debug.Stack())
+		}
+	}()
+	evalChain(context.Background(), *c, &d.event, nil, false)
+}
+
+// CancelDeferredEvents drops all events waiting for their chains to become
+// active.
+func CancelDeferredEvents() {
+	deferredEventsMutex.Lock()
+	ds := []*deferredEvent{}
+	for d := range deferredEvents {
+		ds = append(ds, d)
+	}
+	deferredEventsMutex.Unlock()
+
+	for _, d := range ds {
+		undeferEvent(d)
+	}
+}
+
+// DeferredEvents returns the number of events waiting for their chains to
+// become active.
+func DeferredEvents() int {
+	deferredEventsMutex.Lock()
+	defer deferredEventsMutex.Unlock()
+
+	return len(deferredEvents)
+}
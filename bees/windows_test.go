@@ -0,0 +1,111 @@
+package bees
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestActiveWindows(t *testing.T) {
+	office := ActiveWindow{Days: []string{"weekdays"}, From: "09:00", To: "17:00"}
+	night := ActiveWindow{Days: []string{"Fri"}, From: "22:00", To: "06:00"}
+	tests := []struct {
+		window ActiveWindow
+		now    time.Time
+		active bool
+	}{
+		{office, time.Date(2026, 10, 14, 9, 0, 0, 0, time.Local), true},    // Wednesday
+		{office, time.Date(2026, 10, 14, 16, 59, 59, 0, time.Local), true}, // Wednesday
+		{office, time.Date(2026, 10, 14, 17, 0, 0, 0, time.Local), false},  // Wednesday
+		{office, time.Date(2026, 10, 17, 10, 0, 0, 0, time.Local), false},  // Saturday
+		{night, time.Date(2026, 10, 16, 23, 0, 0, 0, time.Local), true},    // Friday
+		{night, time.Date(2026, 10, 17, 5, 0, 0, 0, time.Local), true},     // Saturday morning
+		{night, time.Date(2026, 10, 16, 5, 0, 0, 0, time.Local), false},    // Friday morning
+		{ActiveWindow{Days: []string{"Sat-Mon"}}, time.Date(2026, 10, 19, 12, 0, 0, 0, time.Local), true},
+		{ActiveWindow{Days: []string{"Sat-Mon"}}, time.Date(2026, 10, 20, 12, 0, 0, 0, time.Local), false},
+	}
+
+	for _, tt := range tests {
+		active, err := tt.window.contains(tt.now)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if active != tt.active {
+			t.Errorf("Expected window %+v active=%v at %s", tt.window, tt.active, tt.now)
+		}
+	}
+
+	for _, w := range []ActiveWindow{
+		{Days: []string{"Someday"}},
+		{From: "9am"},
+		{Location: "Nowhere/Atlantis"},
+	} {
+		if err := w.validate(); err == nil {
+			t.Errorf("Expected window %+v to be invalid", w)
+		}
+	}
+
+	c := Chain{Name: "office", Windows: []ActiveWindow{{Days: []string{"Funday"}}}, OutsideWindow: "queue"}
+	if errs := ValidateChain(c); len(errs) != 2 {
+		t.Errorf("Expected an invalid window and behavior, got %v", errs)
+	}
+}
+
+func TestDeferredEvents(t *testing.T) {
+	fc := &fakeClock{now: time.Date(2026, 10, 17, 10, 0, 0, 0, time.Local)} // Saturday
+	SetClock(fc)
+	defer SetClock(nil)
+
+	bee := newRecordingBee("deferbee")
+	defer DeleteBee(GetBee("deferbee"))
+
+	oldActions := GetActions()
+	defer SetActions(oldActions)
+	SetActions([]Action{{ID: "defer-page", Bee: "deferbee", Name: "page"}})
+	oldChains := chains
+	defer func() { chains = oldChains }()
+	ev := &Event{Bee: "deferbee", Name: "alert"}
+	office := []ActiveWindow{{Days: []string{"Mon-Fri"}, From: "09:00", To: "17:00"}}
+	SetChains([]Chain{
+		{Name: "dropped", Event: ev, Windows: office, Actions: []string{"defer-page"}},
+		{Name: "deferred", Event: ev, Windows: office, OutsideWindow: DeferOutsideWindow, Actions: []string{"defer-page"}},
+	})
+	defer CancelDeferredEvents()
+
+	if matched := execChains(context.Background(), ev); len(matched) != 0 {
+		t.Errorf("Expected inactive chains not to fire, got %v", matched)
+	}
+	if n := DeferredEvents(); n != 1 {
+		t.Fatalf("Expected one deferred event, got %d", n)
+	}
+
+	deferred := func() *deferredEvent {
+		deferredEventsMutex.Lock()
+		defer deferredEventsMutex.Unlock()
+		for d := range deferredEvents {
+			return d
+		}
+		return nil
+	}
+
+	// firing early, e.g. after the clock changed, defers the event again
+	d := deferred()
+	fireDeferredEvent(d)
+	d = deferred()
+	if monday := time.Date(2026, 10, 19, 9, 0, 0, 0, time.Local); d == nil || !d.at.Equal(monday) {
+		t.Fatalf("Expected the event to be deferred until %v, got %+v", monday, d)
+	}
+	if got := bee.executed(); len(got) != 0 {
+		t.Errorf("Expected no actions outside of the window, got %v", got)
+	}
+
+	fc.now = d.at
+	fireDeferredEvent(d)
+	if got := strings.Join(bee.executed(), ","); got != "page" {
+		t.Errorf("Expected the deferred chain to fire once its window opened, got %v", got)
+	}
+	if n := DeferredEvents(); n != 0 {
+		t.Errorf("Expected no more deferred events, got %d", n)
+	}
+}
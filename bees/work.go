@@ -0,0 +1,156 @@
+/*
+ *    Copyright (C) 2026 Christian Muehlhaeuser
+ *
+ *    This program is free software: you can redistribute it and/or modify
+ *    it under the terms of the GNU Affero General Public License as published
+ *    by the Free Software Foundation, either version 3 of the License, or
+ *    (at your option) any later version.
+ *
+ *    This program is distributed in the hope that it will be useful,
+ *    but WITHOUT ANY WARRANTY; without even the implied warranty of
+ *    MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ *    GNU Affero General Public License for more details.
+ *
+ *    You should have received a copy of the GNU Affero General Public License
+ *    along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ *    Authors:
+ *      Christian Muehlhaeuser <muesli@gmail.com>
+ */
+
+// Package bees is Beehive's central module system.
+package bees
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+var (
+	pendingWork      = make(map[string]int)
+	drainingBees     = make(map[string]bool)
+	pendingWorkMutex sync.Mutex
+)
+
+// eventWork is the work added by an event of a bee, which lasts until the
+// event got handled or dropped, see Event.finish.
+type eventWork struct {
+	once sync.Once
+	bee  string
+}
+
+// end ends the work of the event, once.
+func (w *eventWork) end() {
+	w.once.Do(func() { endWork(w.bee) })
+}
+
+// admitEvent marks the arrival of an event at the hive, counting it as work
+// of its bee until it got handled or dropped. Returns false if the event has
+// to be dropped, because its bee is being drained, see DeleteBeeGracefully.
+// Injected events and events admitted before always pass.
+func admitEvent(event *Event) bool {
+	if event.work != nil || event.done != nil {
+		return true
+	}
+
+	pendingWorkMutex.Lock()
+	defer pendingWorkMutex.Unlock()
+
+	if drainingBees[event.Bee] {
+		return false
+	}
+	pendingWork[event.Bee]++
+	event.work = &eventWork{bee: event.Bee}
+	return true
+}
+
+// setBeeDraining controls whether events emitted by a bee get dropped on
+// arrival, see admitEvent.
+func setBeeDraining(bee string, draining bool) {
+	pendingWorkMutex.Lock()
+	defer pendingWorkMutex.Unlock()
+
+	if draining {
+		drainingBees[bee] = true
+	} else {
+		delete(drainingBees, bee)
+	}
+}
+
+// beeDraining returns whether a bee is being drained.
+func beeDraining(bee string) bool {
+	pendingWorkMutex.Lock()
+	defer pendingWorkMutex.Unlock()
+
+	return drainingBees[bee]
+}
+
+// beginWork marks the start of work related to a bee, i.e. executing the
+// chains for one of its events, or executing one of its actions.
+func beginWork(bee string) {
+	pendingWorkMutex.Lock()
+	defer pendingWorkMutex.Unlock()
+
+	pendingWork[bee]++
+}
+
+// endWork marks the end of work started with beginWork.
+func endWork(bee string) {
+	pendingWorkMutex.Lock()
+	defer pendingWorkMutex.Unlock()
+
+	pendingWork[bee]--
+	if pendingWork[bee] <= 0 {
+		delete(pendingWork, bee)
+	}
+}
+
+// waitForWork waits for all pending work related to a bee to finish. It
+// returns false if the work didn't finish within timeout.
+func waitForWork(bee string, timeout time.Duration) bool {
+	return waitForWorkUntil(bee, time.Now().Add(timeout))
+}
+
+// waitForWorkUntil waits for all pending work related to a bee to finish. It
+// returns false if the work didn't finish before deadline. A zero deadline
+// waits indefinitely.
+func waitForWorkUntil(bee string, deadline time.Time) bool {
+	for {
+		pendingWorkMutex.Lock()
+		n := pendingWork[bee]
+		pendingWorkMutex.Unlock()
+
+		if n == 0 {
+			return true
+		}
+		if !deadline.IsZero() && time.Now().After(deadline) {
+			return false
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+// DeleteBeeGracefully drops all further events of a bee, waits for the events
+// it already emitted to be handled, for the chains they triggered and for the
+// actions targeting the bee to finish, then stops and removes it. If the
+// pending work doesn't finish within timeout, the bee is left stopped but
+// registered and an error is returned, so the caller can decide to force its
+// removal with DeleteBee.
+func DeleteBeeGracefully(name string, timeout time.Duration) error {
+	bee := GetBee(name)
+	if bee == nil {
+		return ErrUnknownBee
+	}
+	name = (*bee).Name()
+
+	setBeeDraining(name, true)
+	defer setBeeDraining(name, false)
+	if !waitForWork(name, timeout) {
+		StopBee(bee)
+		return fmt.Errorf("Timed out waiting for pending work of bee %s", name)
+	}
+
+	DeleteBee(bee)
+	return nil
+}
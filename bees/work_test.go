@@ -0,0 +1,170 @@
+package bees
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// gatedBee blocks its actions until gate gets closed.
+type gatedBee struct {
+	recordingBee
+
+	gate chan struct{}
+}
+
+func (mod *gatedBee) Action(ctx context.Context, action Action) []Placeholder {
+	<-mod.gate
+	return mod.recordingBee.Action(ctx, action)
+}
+
+// newGatedBee registers and starts a new gatedBee.
+func newGatedBee(name string) *gatedBee {
+	mod := &gatedBee{
+		recordingBee: recordingBee{Bee: NewBee(name, "recordingbee", "", BeeOptions{})},
+		gate:         make(chan struct{}),
+	}
+	RegisterBee(mod)
+	mod.Start()
+
+	return mod
+}
+
+func TestAdmitEvent(t *testing.T) {
+	setBeeDraining("drainedbee", true)
+	if admitEvent(&Event{Bee: "drainedbee", Name: "tick"}) {
+		t.Error("Expected events of a draining bee to be dropped")
+	}
+	injected := Event{Bee: "drainedbee", Name: "tick", done: &eventDone{ch: make(chan struct{})}}
+	if !admitEvent(&injected) {
+		t.Error("Expected injected events of a draining bee to pass")
+	}
+	setBeeDraining("drainedbee", false)
+
+	event := Event{Bee: "admittedbee", Name: "tick"}
+	if !admitEvent(&event) {
+		t.Fatal("Expected event to be admitted")
+	}
+	if waitForWork("admittedbee", 20*time.Millisecond) {
+		t.Error("Expected the admitted event to be pending work")
+	}
+	event.finish()
+	event.finish()
+	if !waitForWork("admittedbee", 0) {
+		t.Error("Expected the finished event to end its work")
+	}
+
+	// events dropped by the event queue end their work, too
+	q := newEventLanes(1, OverflowDropNewest)
+	for i := 0; i < 2; i++ {
+		e := Event{Bee: "queuedbee", Name: "tick"}
+		admitEvent(&e)
+		q.push(e)
+	}
+	if _, ok := pendingWorkOf("queuedbee"); !ok {
+		t.Fatal("Expected the queued event to be pending work")
+	}
+	e, _ := q.pop()
+	e.finish()
+	if n, ok := pendingWorkOf("queuedbee"); ok {
+		t.Errorf("Expected the dropped event to end its work, got %d pending", n)
+	}
+}
+
+// pendingWorkOf returns the pending work of a bee, if there is any.
+func pendingWorkOf(bee string) (int, bool) {
+	pendingWorkMutex.Lock()
+	defer pendingWorkMutex.Unlock()
+
+	n, ok := pendingWork[bee]
+	return n, ok
+}
+
+func TestDeleteBeeGracefully(t *testing.T) {
+	events := make(chan Event)
+	defer useEventChannel(events)()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go runEventLoop(ctx, events)
+
+	sink := newGatedBee("gracefulsink")
+	newRecordingBee("gracefulsource")
+	defer DeleteBee(GetBee("gracefulsource"))
+
+	oldActions := GetActions()
+	defer SetActions(oldActions)
+	SetActions([]Action{{ID: "graceful-post", Bee: "gracefulsink", Name: "post"}})
+	oldChains := GetChains()
+	defer SetChains(oldChains)
+	SetChains([]Chain{{Name: "graceful", Event: &Event{Bee: "gracefulsource", Name: "tick"}, Actions: []string{"graceful-post"}}})
+
+	if err := DeleteBeeGracefully("nosuchbee", time.Second); err == nil {
+		t.Error("Expected an error deleting an unknown bee")
+	}
+
+	events <- Event{Bee: "gracefulsource", Name: "tick"}
+	deadline := time.Now().Add(time.Second)
+	for waitForWork("gracefulsink", 0) && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	deleted := make(chan error)
+	go func() {
+		deleted <- DeleteBeeGracefully("gracefulsink", 5*time.Second)
+	}()
+
+	// the sink's action is pending, so it keeps running
+	select {
+	case err := <-deleted:
+		t.Fatalf("Expected the deletion to wait for the pending action, got %v", err)
+	case <-time.After(50 * time.Millisecond):
+	}
+	if GetBee("gracefulsink") == nil || !sink.IsRunning() {
+		t.Fatal("Expected the bee to keep running while its actions drain")
+	}
+
+	close(sink.gate)
+	if err := <-deleted; err != nil {
+		t.Fatal(err)
+	}
+	if got := sink.executed(); len(got) != 1 || got[0] != "post" {
+		t.Errorf("Expected the pending action to be executed, got %v", got)
+	}
+	if GetBee("gracefulsink") != nil || sink.IsRunning() {
+		t.Error("Expected the bee to be stopped and removed")
+	}
+}
+
+func TestDeleteBeeGracefullyTimeout(t *testing.T) {
+	events := make(chan Event)
+	defer useEventChannel(events)()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go runEventLoop(ctx, events)
+
+	sink := newGatedBee("timeoutsink")
+	defer DeleteBee(GetBee("timeoutsink"))
+	defer close(sink.gate)
+
+	oldActions := GetActions()
+	defer SetActions(oldActions)
+	SetActions([]Action{{ID: "timeout-post", Bee: "timeoutsink", Name: "post"}})
+	oldChains := GetChains()
+	defer SetChains(oldChains)
+	SetChains([]Chain{{Name: "timeout", Event: &Event{Bee: "timeoutsource", Name: "tick"}, Actions: []string{"timeout-post"}}})
+
+	events <- Event{Bee: "timeoutsource", Name: "tick"}
+	deadline := time.Now().Add(time.Second)
+	for waitForWork("timeoutsink", 0) && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+
+	if err := DeleteBeeGracefully("timeoutsink", 20*time.Millisecond); err == nil {
+		t.Fatal("Expected the deletion to time out")
+	}
+	if GetBee("timeoutsink") == nil {
+		t.Error("Expected the bee to stay registered after timing out")
+	}
+	if beeDraining("timeoutsink") {
+		t.Error("Expected the bee not to be drained anymore")
+	}
+}
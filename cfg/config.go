@@ -6,6 +6,7 @@ import (
 	"os"
 	"path/filepath"
 
+	"github.com/muesli/beehive/api/context"
 	"github.com/muesli/beehive/bees"
 	gap "github.com/muesli/go-app-paths"
 	log "github.com/sirupsen/logrus"
@@ -20,8 +21,25 @@ type Config struct {
 	Bees    []bees.BeeConfig
 	Actions []bees.Action
 	Chains  []bees.Chain
-	backend ConfigBackend
-	url     *url.URL
+
+	// References holds shared values bee options can refer to
+	References map[string]interface{} `json:",omitempty" yaml:",omitempty"`
+
+	// Profiles hosted by the hive, see bees.Profile. The bees, actions and
+	// chains of profiles with their own configuration get loaded from and
+	// saved to it.
+	Profiles []bees.Profile `json:",omitempty" yaml:",omitempty"`
+
+	// Enrichments add placeholders to all events, see bees.Enrichment
+	Enrichments []bees.Enrichment `json:",omitempty" yaml:",omitempty"`
+
+	// Credentials grant access to the API, see context.Credential. Without
+	// any, the API is accessible without authentication.
+	Credentials []context.Credential `json:",omitempty" yaml:",omitempty"`
+
+	backend  ConfigBackend
+	url      *url.URL
+	profiles map[string]*Config
 }
 
 // ConfigBackend is the interface implemented by the configuration backends.
@@ -38,7 +56,11 @@ type ConfigBackend interface {
 // The backend loaded will be responsible for saving it
 // to the given URL
 func (c *Config) Save() error {
-	return c.backend.Save(c)
+	main, err := c.saveProfiles()
+	if err != nil {
+		return err
+	}
+	return c.backend.Save(main)
 }
 
 // Load the configuration.
@@ -53,7 +75,11 @@ func (c *Config) Load() error {
 	c.Bees = config.Bees
 	c.Actions = config.Actions
 	c.Chains = config.Chains
-	return nil
+	c.References = config.References
+	c.Profiles = config.Profiles
+	c.Credentials = config.Credentials
+	c.Enrichments = config.Enrichments
+	return c.loadProfiles()
 }
 
 // Backend currently being used.
@@ -0,0 +1,133 @@
+package cfg
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/muesli/beehive/bees"
+)
+
+// profileURL returns the URL of a profile's own configuration. Relative paths
+// are relative to the directory of the configuration defining the profile.
+func (c *Config) profileURL(p bees.Profile) string {
+	if strings.Contains(p.Config, ":") || filepath.IsAbs(p.Config) || c.url == nil {
+		return p.Config
+	}
+	if c.url.Scheme != "" && c.url.Scheme != "file" {
+		return p.Config
+	}
+	return filepath.Join(filepath.Dir(c.url.Path), p.Config)
+}
+
+// loadProfiles merges the configurations of all profiles with their own into
+// c. Their bees and chains get put into the profile's scope.
+func (c *Config) loadProfiles() error {
+	c.profiles = make(map[string]*Config)
+	for _, p := range c.Profiles {
+		if len(p.Config) == 0 {
+			continue
+		}
+
+		pc, err := New(c.profileURL(p))
+		if err != nil {
+			return fmt.Errorf("Error creating the configuration of profile %s: %v", p.Name, err)
+		}
+		if err := pc.Load(); err != nil {
+			return fmt.Errorf("Error loading the configuration of profile %s: %v", p.Name, err)
+		}
+		if len(pc.Profiles) > 0 {
+			return fmt.Errorf("The configuration of profile %s must not define profiles", p.Name)
+		}
+
+		for _, b := range pc.Bees {
+			b.Scope = p.Name
+			c.Bees = append(c.Bees, b)
+		}
+		for _, ch := range pc.Chains {
+			ch.Scope = p.Name
+			c.Chains = append(c.Chains, ch)
+		}
+		c.Actions = append(c.Actions, pc.Actions...)
+		c.profiles[p.Name] = pc
+	}
+
+	return nil
+}
+
+// saveProfiles saves the bees, actions and chains of all profiles with their
+// own configuration to it and returns everything else, which gets saved to
+// c itself.
+func (c *Config) saveProfiles() (*Config, error) {
+	main := *c
+	split := make(map[string]struct{})
+	claimed := make(map[string]struct{})
+	if c.profiles == nil {
+		c.profiles = make(map[string]*Config)
+	}
+
+	for _, p := range c.Profiles {
+		if len(p.Config) == 0 {
+			continue
+		}
+
+		pc, ok := c.profiles[p.Name]
+		if !ok {
+			var err error
+			if pc, err = New(c.profileURL(p)); err != nil {
+				return nil, fmt.Errorf("Error creating the configuration of profile %s: %v", p.Name, err)
+			}
+		}
+
+		out := *pc
+		out.Bees = []bees.BeeConfig{}
+		out.Chains = []bees.Chain{}
+		out.Actions = []bees.Action{}
+		for _, b := range c.Bees {
+			if b.Scope == p.Name {
+				out.Bees = append(out.Bees, b)
+			}
+		}
+		for _, ch := range c.Chains {
+			if ch.Scope == p.Name {
+				out.Chains = append(out.Chains, ch)
+			}
+		}
+		for _, a := range bees.ProfileActions(p.Name, c.Actions, c.Bees) {
+			if _, ok := claimed[a.ID]; !ok {
+				claimed[a.ID] = struct{}{}
+				out.Actions = append(out.Actions, a)
+			}
+		}
+
+		if err := pc.backend.Save(&out); err != nil {
+			return nil, fmt.Errorf("Error saving the configuration of profile %s: %v", p.Name, err)
+		}
+		c.profiles[p.Name] = pc
+		split[p.Name] = struct{}{}
+	}
+
+	if len(split) == 0 {
+		return c, nil
+	}
+
+	main.Bees = []bees.BeeConfig{}
+	main.Chains = []bees.Chain{}
+	main.Actions = []bees.Action{}
+	for _, b := range c.Bees {
+		if _, ok := split[b.Scope]; !ok {
+			main.Bees = append(main.Bees, b)
+		}
+	}
+	for _, ch := range c.Chains {
+		if _, ok := split[ch.Scope]; !ok {
+			main.Chains = append(main.Chains, ch)
+		}
+	}
+	for _, a := range c.Actions {
+		if _, ok := claimed[a.ID]; !ok {
+			main.Actions = append(main.Actions, a)
+		}
+	}
+	return &main, nil
+}
@@ -0,0 +1,63 @@
+package cfg
+
+import (
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/muesli/beehive/bees"
+)
+
+func TestProfiles(t *testing.T) {
+	dir, err := ioutil.TempDir("", "beehive-profiles")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	main := `{"Bees": [{"Name": "rss", "Class": "rssbee"}], "Profiles": [{"Name": "work", "Config": "work.conf"}]}`
+	work := `{"Bees": [{"Name": "mailer", "Class": "emailbee"}], "Actions": [{"ID": "mail", "Bee": "mailer", "Name": "send"}], "Chains": [{"Name": "digest"}]}`
+	if err := ioutil.WriteFile(filepath.Join(dir, "beehive.conf"), []byte(main), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(dir, "work.conf"), []byte(work), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	conf, err := New(filepath.Join(dir, "beehive.conf"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := conf.Load(); err != nil {
+		t.Fatal(err)
+	}
+	if len(conf.Bees) != 2 || conf.Bees[1].Scope != "work" {
+		t.Fatalf("Expected the profile's bee to be merged into its scope, got %+v", conf.Bees)
+	}
+	if len(conf.Chains) != 1 || conf.Chains[0].Scope != "work" || len(conf.Actions) != 1 {
+		t.Fatalf("Expected the profile's chains and actions to be merged, got %+v %+v", conf.Chains, conf.Actions)
+	}
+
+	conf.Chains = append(conf.Chains, bees.Chain{Name: "news"}, bees.Chain{Name: "standup", Scope: "work"})
+	if err := conf.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	m, err := NewFileBackend().Load(conf.URL())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(m.Bees) != 1 || len(m.Actions) != 0 || len(m.Chains) != 1 || m.Chains[0].Name != "news" {
+		t.Errorf("Expected only global bees and chains in the main configuration, got %+v", m)
+	}
+
+	w, err := NewFileBackend().Load(&url.URL{Path: filepath.Join(dir, "work.conf")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(w.Bees) != 1 || len(w.Actions) != 1 || len(w.Chains) != 2 {
+		t.Errorf("Expected the profile's bees, actions and chains in its configuration, got %+v", w)
+	}
+}
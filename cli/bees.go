@@ -0,0 +1,245 @@
+/*
+ *    Copyright (C) 2026 Christian Muehlhaeuser
+ *
+ *    This program is free software: you can redistribute it and/or modify
+ *    it under the terms of the GNU Affero General Public License as published
+ *    by the Free Software Foundation, either version 3 of the License, or
+ *    (at your option) any later version.
+ *
+ *    This program is distributed in the hope that it will be useful,
+ *    but WITHOUT ANY WARRANTY; without even the implied warranty of
+ *    MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ *    GNU Affero General Public License for more details.
+ *
+ *    You should have received a copy of the GNU Affero General Public License
+ *    along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ *    Authors:
+ *      Christian Muehlhaeuser <muesli@gmail.com>
+ */
+
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+type beeInfo struct {
+	Name        string        `json:"name"`
+	Namespace   string        `json:"namespace"`
+	Description string        `json:"description"`
+	Active      bool          `json:"active"`
+	Paused      bool          `json:"paused"`
+	State       string        `json:"state"`
+	LastEvent   time.Time     `json:"lastevent"`
+	LastAction  time.Time     `json:"lastaction"`
+	Options     []interface{} `json:"options"`
+}
+
+type beesResponse struct {
+	Bees []beeInfo `json:"bees"`
+}
+
+func init() {
+	register("bees",
+		command{name: "list", usage: "List all bees", run: listBees},
+		command{name: "show", args: "<name>", usage: "Show a bee", run: showBee},
+		command{name: "create", args: "-f <file>", usage: "Create a bee described by a JSON file", run: createBee},
+		command{name: "start", args: "<name>", usage: "Start a bee", run: func(c *client, args []string) error {
+			return setBeeActive(c, args, true)
+		}},
+		command{name: "stop", args: "<name>", usage: "Stop a bee", run: func(c *client, args []string) error {
+			return setBeeActive(c, args, false)
+		}},
+		command{name: "pause", args: "<name>", usage: "Pause a bee, dropping its events and skipping its actions", run: func(c *client, args []string) error {
+			return setBeePaused(c, args, true)
+		}},
+		command{name: "resume", args: "<name>", usage: "Resume a paused bee", run: func(c *client, args []string) error {
+			return setBeePaused(c, args, false)
+		}},
+		command{name: "delete", args: "<name>", usage: "Delete a bee", run: deleteBee},
+	)
+	register("hives",
+		command{name: "list", usage: "List the available hives", run: listHives},
+	)
+	register("status",
+		command{name: "list", usage: "Show the health of all bees", run: listStatus},
+	)
+}
+
+// parseFlags parses the flags of a command, which may be mixed with its
+// positional arguments. It returns the positional arguments.
+func parseFlags(fs *flag.FlagSet, args []string) ([]string, error) {
+	var pos []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, err
+		}
+		if fs.NArg() == 0 {
+			return pos, nil
+		}
+		pos = append(pos, fs.Arg(0))
+		args = fs.Args()[1:]
+	}
+}
+
+func fileFlag(c *client, name string) (*flag.FlagSet, *string) {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(c.out)
+	f := fs.String("f", "", "JSON file to read, - for stdin")
+	return fs, f
+}
+
+func getBee(c *client, name string) (beeInfo, error) {
+	var res beesResponse
+	if err := c.do("GET", "bees/"+name, nil, &res); err != nil {
+		return beeInfo{}, err
+	}
+	if len(res.Bees) == 0 {
+		return beeInfo{}, fmt.Errorf("No bee named %s", name)
+	}
+	return res.Bees[0], nil
+}
+
+func listBees(c *client, args []string) error {
+	var res beesResponse
+	if err := c.do("GET", "bees", nil, &res); err != nil {
+		return err
+	}
+
+	rows := [][]string{{"NAME", "HIVE", "STATE", "LAST EVENT", "LAST ACTION"}}
+	for _, b := range res.Bees {
+		state := b.State
+		if b.Paused {
+			state += " (paused)"
+		}
+		rows = append(rows, []string{b.Name, b.Namespace, state, formatTime(b.LastEvent), formatTime(b.LastAction)})
+	}
+	c.table(rows)
+	return nil
+}
+
+func showBee(c *client, args []string) error {
+	if err := needArgs(args, 1, "<name>"); err != nil {
+		return err
+	}
+
+	var res map[string]interface{}
+	if err := c.do("GET", "bees/"+args[0], nil, &res); err != nil {
+		return err
+	}
+	return c.print(res["bees"])
+}
+
+func createBee(c *client, args []string) error {
+	fs, file := fileFlag(c, "bees create")
+	if _, err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if *file == "" {
+		return fmt.Errorf("Expected arguments: -f <file>")
+	}
+
+	doc, err := readInput(*file, "bee")
+	if err != nil {
+		return err
+	}
+	if bee, ok := doc["bee"].(map[string]interface{}); ok {
+		if _, ok := bee["active"]; !ok {
+			bee["active"] = true
+		}
+	}
+
+	var res beesResponse
+	if err := c.do("POST", "bees", doc, &res); err != nil {
+		return err
+	}
+	for _, b := range res.Bees {
+		fmt.Fprintf(c.out, "Created bee %s\n", b.Name)
+	}
+	return nil
+}
+
+func setBeeActive(c *client, args []string, active bool) error {
+	if err := needArgs(args, 1, "<name>"); err != nil {
+		return err
+	}
+	b, err := getBee(c, args[0])
+	if err != nil {
+		return err
+	}
+
+	b.Active = active
+	return c.do("PUT", "bees/"+b.Name, map[string]interface{}{"bee": b}, nil)
+}
+
+func setBeePaused(c *client, args []string, paused bool) error {
+	if err := needArgs(args, 1, "<name>"); err != nil {
+		return err
+	}
+	b, err := getBee(c, args[0])
+	if err != nil {
+		return err
+	}
+
+	b.Paused = paused
+	return c.do("PUT", "bees/"+b.Name, map[string]interface{}{"bee": b}, nil)
+}
+
+func deleteBee(c *client, args []string) error {
+	if err := needArgs(args, 1, "<name>"); err != nil {
+		return err
+	}
+	return c.do("DELETE", "bees/"+args[0], nil, nil)
+}
+
+func listHives(c *client, args []string) error {
+	var res struct {
+		Hives []struct {
+			ID          string `json:"id"`
+			Name        string `json:"name"`
+			Description string `json:"description"`
+		} `json:"hives"`
+	}
+	if err := c.do("GET", "hives", nil, &res); err != nil {
+		return err
+	}
+
+	rows := [][]string{{"ID", "NAME", "DESCRIPTION"}}
+	for _, h := range res.Hives {
+		rows = append(rows, []string{h.ID, h.Name, h.Description})
+	}
+	c.table(rows)
+	return nil
+}
+
+func listStatus(c *client, args []string) error {
+	var res struct {
+		Status []struct {
+			ID          string `json:"id"`
+			State       string `json:"state"`
+			Healthy     bool   `json:"healthy"`
+			HealthError string `json:"healtherror"`
+			Restarts    int64  `json:"restarts"`
+			Uptime      string `json:"uptime"`
+			LastError   string `json:"lasterror"`
+		} `json:"status"`
+	}
+	if err := c.do("GET", "status", nil, &res); err != nil {
+		return err
+	}
+
+	rows := [][]string{{"BEE", "STATE", "HEALTHY", "RESTARTS", "UPTIME", "LAST ERROR"}}
+	for _, s := range res.Status {
+		healthy := strconv.FormatBool(s.Healthy)
+		if s.HealthError != "" {
+			healthy += " (" + s.HealthError + ")"
+		}
+		rows = append(rows, []string{s.ID, s.State, healthy, strconv.FormatInt(s.Restarts, 10), s.Uptime, s.LastError})
+	}
+	c.table(rows)
+	return nil
+}
@@ -0,0 +1,191 @@
+/*
+ *    Copyright (C) 2026 Christian Muehlhaeuser
+ *
+ *    This program is free software: you can redistribute it and/or modify
+ *    it under the terms of the GNU Affero General Public License as published
+ *    by the Free Software Foundation, either version 3 of the License, or
+ *    (at your option) any later version.
+ *
+ *    This program is distributed in the hope that it will be useful,
+ *    but WITHOUT ANY WARRANTY; without even the implied warranty of
+ *    MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ *    GNU Affero General Public License for more details.
+ *
+ *    You should have received a copy of the GNU Affero General Public License
+ *    along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ *    Authors:
+ *      Christian Muehlhaeuser <muesli@gmail.com>
+ */
+
+package cli
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+type chainInfo struct {
+	Name    string `json:"name"`
+	Enabled bool   `json:"enabled"`
+	Event   *struct {
+		Bee  string
+		Name string
+	} `json:"event"`
+	Actions []string `json:"actions"`
+	Stats   *struct {
+		Triggered int64
+	} `json:"stats"`
+}
+
+type actionInfo struct {
+	ID   string `json:"id"`
+	Bee  string `json:"bee"`
+	Name string `json:"name"`
+}
+
+func init() {
+	register("chains",
+		command{name: "list", usage: "List all chains", run: listChains},
+		command{name: "show", args: "<name>", usage: "Show a chain", run: showChain},
+		command{name: "create", args: "-f <file>", usage: "Create a chain described by a JSON file", run: func(c *client, args []string) error {
+			return saveChain(c, "chains create", args)
+		}},
+		command{name: "update", args: "<name> -f <file>", usage: "Replace a chain with the one described by a JSON file", run: func(c *client, args []string) error {
+			return saveChain(c, "chains update", args)
+		}},
+		command{name: "delete", args: "<name>", usage: "Delete a chain", run: deleteChain},
+	)
+	register("actions",
+		command{name: "list", usage: "List all actions", run: listActions},
+	)
+}
+
+func listChains(c *client, args []string) error {
+	var res struct {
+		Chains []chainInfo `json:"chains"`
+	}
+	if err := c.do("GET", "chains", nil, &res); err != nil {
+		return err
+	}
+
+	rows := [][]string{{"NAME", "TRIGGER", "ACTIONS", "TRIGGERED", "ENABLED"}}
+	for _, ch := range res.Chains {
+		trigger := ""
+		if ch.Event != nil {
+			trigger = ch.Event.Bee + "/" + ch.Event.Name
+		}
+		var triggered int64
+		if ch.Stats != nil {
+			triggered = ch.Stats.Triggered
+		}
+		rows = append(rows, []string{ch.Name, trigger, strconv.Itoa(len(ch.Actions)), strconv.FormatInt(triggered, 10), strconv.FormatBool(ch.Enabled)})
+	}
+	c.table(rows)
+	return nil
+}
+
+func showChain(c *client, args []string) error {
+	if err := needArgs(args, 1, "<name>"); err != nil {
+		return err
+	}
+
+	var res map[string]interface{}
+	if err := c.do("GET", "chains/"+args[0], nil, &res); err != nil {
+		return err
+	}
+	return c.print(res["chains"])
+}
+
+// saveChain creates or updates a chain. Its actions may either be IDs of
+// existing actions, or action objects which get created first.
+func saveChain(c *client, name string, args []string) error {
+	fs, file := fileFlag(c, name)
+	pos, err := parseFlags(fs, args)
+	if err != nil {
+		return err
+	}
+	update := strings.HasSuffix(name, "update")
+	if *file == "" || (update && len(pos) != 1) || (!update && len(pos) != 0) {
+		if update {
+			return fmt.Errorf("Expected arguments: <name> -f <file>")
+		}
+		return fmt.Errorf("Expected arguments: -f <file>")
+	}
+
+	doc, err := readInput(*file, "chain")
+	if err != nil {
+		return err
+	}
+	chain, ok := doc["chain"].(map[string]interface{})
+	if !ok {
+		return fmt.Errorf("Can't parse %s: expected a chain object", *file)
+	}
+	if update {
+		if _, ok := chain["name"]; !ok {
+			chain["name"] = pos[0]
+		}
+	}
+
+	list, _ := chain["actions"].([]interface{})
+	ids := []interface{}{}
+	for _, a := range list {
+		action, ok := a.(map[string]interface{})
+		if !ok {
+			ids = append(ids, a)
+			continue
+		}
+
+		var res struct {
+			Actions []actionInfo `json:"actions"`
+		}
+		if err := c.do("POST", "actions", map[string]interface{}{"action": action}, &res); err != nil {
+			return fmt.Errorf("Can't create action: %v", err)
+		}
+		if len(res.Actions) == 0 {
+			return fmt.Errorf("Can't create action: empty response")
+		}
+		ids = append(ids, res.Actions[0].ID)
+	}
+	chain["actions"] = ids
+
+	if update {
+		err = c.do("PUT", "chains/"+pos[0], doc, nil)
+	} else {
+		err = c.do("POST", "chains", doc, nil)
+	}
+	if err != nil {
+		return err
+	}
+
+	verb := "Created"
+	if update {
+		verb = "Updated"
+	}
+	fmt.Fprintf(c.out, "%s chain %v\n", verb, chain["name"])
+	return nil
+}
+
+func deleteChain(c *client, args []string) error {
+	if err := needArgs(args, 1, "<name>"); err != nil {
+		return err
+	}
+	return c.do("DELETE", "chains/"+args[0], nil, nil)
+}
+
+func listActions(c *client, args []string) error {
+	var res struct {
+		Actions []actionInfo `json:"actions"`
+	}
+	if err := c.do("GET", "actions", nil, &res); err != nil {
+		return err
+	}
+
+	rows := [][]string{{"ID", "BEE", "ACTION"}}
+	for _, a := range res.Actions {
+		rows = append(rows, []string{a.ID, a.Bee, a.Name})
+	}
+	c.table(rows)
+	return nil
+}
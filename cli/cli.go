@@ -0,0 +1,276 @@
+/*
+ *    Copyright (C) 2026 Christian Muehlhaeuser
+ *
+ *    This program is free software: you can redistribute it and/or modify
+ *    it under the terms of the GNU Affero General Public License as published
+ *    by the Free Software Foundation, either version 3 of the License, or
+ *    (at your option) any later version.
+ *
+ *    This program is distributed in the hope that it will be useful,
+ *    but WITHOUT ANY WARRANTY; without even the implied warranty of
+ *    MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ *    GNU Affero General Public License for more details.
+ *
+ *    You should have received a copy of the GNU Affero General Public License
+ *    along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ *    Authors:
+ *      Christian Muehlhaeuser <muesli@gmail.com>
+ */
+
+// Package cli implements Beehive's command-line client, which manages a
+// running hive through its API.
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+// A command is a single subcommand, e.g. "bees list".
+type command struct {
+	name  string
+	args  string
+	usage string
+	run   func(c *client, args []string) error
+}
+
+// resources maps the resources to the commands available for them.
+var resources = map[string][]command{}
+
+func register(resource string, cmds ...command) {
+	resources[resource] = append(resources[resource], cmds...)
+}
+
+// Run executes the subcommand described by args, e.g. []string{"bees",
+// "list"}, against the API of the hive running at base. Output gets written
+// to out.
+func Run(base *url.URL, args []string, out io.Writer) error {
+	if len(args) == 0 || args[0] == "help" {
+		usage(out)
+		return nil
+	}
+
+	resource := args[0]
+	cmds, ok := resources[resource]
+	if !ok {
+		usage(out)
+		return fmt.Errorf("Unknown command %s", resource)
+	}
+	name := "list"
+	if len(args) > 1 {
+		name = args[1]
+		args = args[2:]
+	} else {
+		args = nil
+	}
+
+	for _, cmd := range cmds {
+		if cmd.name == name {
+			return cmd.run(newClient(base, out), args)
+		}
+	}
+
+	usage(out)
+	return fmt.Errorf("Unknown command %s %s", resource, name)
+}
+
+func usage(out io.Writer) {
+	fmt.Fprintln(out, "Usage: beehive [flags] <command> [arguments]")
+	fmt.Fprintln(out)
+	fmt.Fprintln(out, "Commands manage the hive running at -canonicalurl:")
+
+	names := []string{}
+	for r := range resources {
+		names = append(names, r)
+	}
+	sort.Strings(names)
+
+	w := tabwriter.NewWriter(out, 0, 8, 2, ' ', 0)
+	for _, r := range names {
+		for _, cmd := range resources[r] {
+			fmt.Fprintf(w, "  %s %s %s\t%s\n", r, cmd.name, cmd.args, cmd.usage)
+		}
+	}
+	w.Flush()
+}
+
+// client talks to the API of a hive.
+type client struct {
+	base *url.URL
+	http *http.Client
+	out  io.Writer
+}
+
+// TokenEnvVar is the environment variable holding the access token sent to
+// the API. Alternatively, credentials for basic auth can be part of
+// -canonicalurl.
+const TokenEnvVar = "BEEHIVE_TOKEN"
+
+func newClient(base *url.URL, out io.Writer) *client {
+	c := &http.Client{}
+	if token := os.Getenv(TokenEnvVar); len(token) > 0 {
+		c.Transport = tokenTransport{token: token, next: http.DefaultTransport}
+	}
+
+	return &client{
+		base: base,
+		http: c,
+		out:  out,
+	}
+}
+
+// tokenTransport sends an access token as bearer token with all requests.
+type tokenTransport struct {
+	token string
+	next  http.RoundTripper
+}
+
+func (t tokenTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.Header.Set("Authorization", "Bearer "+t.token)
+	return t.next.RoundTrip(req)
+}
+
+// url returns the URL of an API path, which may include a query.
+func (c *client) url(path string) string {
+	u := *c.base
+	if i := strings.Index(path, "?"); i >= 0 {
+		u.RawQuery = path[i+1:]
+		path = path[:i]
+	}
+	u.Path = strings.TrimSuffix(u.Path, "/") + "/" + path
+	return u.String()
+}
+
+// do sends a request to the API and decodes its JSON response into res,
+// unless it is nil.
+func (c *client) do(method, path string, body interface{}, res interface{}) error {
+	var r io.Reader
+	if body != nil {
+		b, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		r = bytes.NewReader(b)
+	}
+
+	req, err := http.NewRequest(method, c.url("v1/"+path), r)
+	if err != nil {
+		return err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	b, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode >= 300 {
+		return apiError(resp, b)
+	}
+	if res == nil || len(b) == 0 {
+		return nil
+	}
+	return json.Unmarshal(b, res)
+}
+
+// apiError extracts the error messages of a failed API request.
+func apiError(resp *http.Response, b []byte) error {
+	var e struct {
+		Errors []struct {
+			Detail string `json:"detail"`
+			Source struct {
+				Pointer string `json:"pointer"`
+			} `json:"source"`
+		} `json:"errors"`
+	}
+	if err := json.Unmarshal(b, &e); err != nil || len(e.Errors) == 0 {
+		if resp.StatusCode == http.StatusNotFound {
+			return errors.New("Not found")
+		}
+		return errors.New(resp.Status)
+	}
+
+	msgs := []string{}
+	for _, err := range e.Errors {
+		msgs = append(msgs, err.Detail)
+	}
+	return errors.New(strings.Join(msgs, "; "))
+}
+
+// readInput decodes a JSON file, or stdin if path is "-". Documents get
+// wrapped in an object with the key wrap, unless they are already.
+func readInput(path, wrap string) (map[string]interface{}, error) {
+	var b []byte
+	var err error
+	if path == "-" {
+		b, err = ioutil.ReadAll(os.Stdin)
+	} else {
+		b, err = ioutil.ReadFile(path)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var doc map[string]interface{}
+	if err := json.Unmarshal(b, &doc); err != nil {
+		return nil, fmt.Errorf("Can't parse %s: %v", path, err)
+	}
+	if _, ok := doc[wrap]; ok && len(doc) == 1 {
+		return doc, nil
+	}
+	return map[string]interface{}{wrap: doc}, nil
+}
+
+// table writes rows as aligned columns, the first row being the header.
+func (c *client) table(rows [][]string) {
+	w := tabwriter.NewWriter(c.out, 0, 8, 2, ' ', 0)
+	for _, row := range rows {
+		fmt.Fprintln(w, strings.Join(row, "\t"))
+	}
+	w.Flush()
+}
+
+// print writes v as indented JSON.
+func (c *client) print(v interface{}) error {
+	b, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(c.out, string(b))
+	return err
+}
+
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return "never"
+	}
+	return t.Local().Format("2006-01-02 15:04:05")
+}
+
+// needArgs makes sure a command got exactly n arguments.
+func needArgs(args []string, n int, names string) error {
+	if len(args) != n {
+		return fmt.Errorf("Expected arguments: %s", names)
+	}
+	return nil
+}
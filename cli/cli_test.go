@@ -0,0 +1,122 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestCreateChain(t *testing.T) {
+	var posted []map[string]interface{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var doc map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&doc)
+		posted = append(posted, doc)
+
+		switch r.URL.Path {
+		case "/v1/actions":
+			w.Write([]byte(`{"actions":[{"id":"a1"}]}`))
+		case "/v1/chains":
+			w.Write([]byte(`{"chains":[]}`))
+		}
+	}))
+	defer srv.Close()
+
+	f, err := ioutil.TempFile("", "chain")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(f.Name())
+	f.WriteString(`{"name": "notify", "event": {"Bee": "rss", "Name": "new_item"}, "actions": ["existing", {"bee": "mail", "name": "send"}]}`)
+	f.Close()
+
+	u, _ := url.Parse(srv.URL)
+	var out bytes.Buffer
+	if err := Run(u, []string{"chains", "create", "-f", f.Name()}, &out); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(posted) != 2 {
+		t.Fatalf("Expected an action and a chain to be posted, got %v", posted)
+	}
+	chain := posted[1]["chain"].(map[string]interface{})
+	if actions := chain["actions"].([]interface{}); len(actions) != 2 || actions[0] != "existing" || actions[1] != "a1" {
+		t.Errorf("Expected inline actions to be replaced by their IDs, got %v", actions)
+	}
+	if !strings.Contains(out.String(), "Created chain notify") {
+		t.Errorf("Unexpected output %q", out.String())
+	}
+}
+
+func TestAPIErrors(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(422)
+		w.Write([]byte(`{"errors":[{"detail":"Missing mandatory option url"},{"detail":"Invalid value for option interval"}]}`))
+	}))
+	defer srv.Close()
+
+	u, _ := url.Parse(srv.URL)
+	err := Run(u, []string{"bees", "delete", "rss"}, ioutil.Discard)
+	if err == nil || err.Error() != "Missing mandatory option url; Invalid value for option interval" {
+		t.Errorf("Expected the API's errors, got %v", err)
+	}
+
+	if err := Run(u, []string{"bees", "delete"}, ioutil.Discard); err == nil {
+		t.Error("Expected a missing argument to fail")
+	}
+	if err := Run(u, []string{"nosuchresource"}, ioutil.Discard); err == nil {
+		t.Error("Expected an unknown command to fail")
+	}
+}
+
+func TestListSchema(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/schema" || r.URL.Query().Get("format") != "json" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte(`{"Hives":[{"ID":"rssbee","Events":[{"Name":"new_item","Options":[{"Name":"title"},{"Name":"url"}]}]}],` +
+			`"SystemEvents":[{"Name":"action_failed","Options":[{"Name":"error"}]}]}`))
+	}))
+	defer srv.Close()
+
+	u, _ := url.Parse(srv.URL)
+	var out bytes.Buffer
+	if err := Run(u, []string{"schema"}, &out); err != nil {
+		t.Fatal(err)
+	}
+	got := strings.Join(strings.Fields(out.String()), " ")
+	for _, s := range []string{"rssbee event new_item title, url", "beehive event action_failed error"} {
+		if !strings.Contains(got, s) {
+			t.Errorf("Expected %q in output:\n%s", s, out.String())
+		}
+	}
+}
+
+func TestAccessToken(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer s3cret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Write([]byte(`{"bees":[]}`))
+	}))
+	defer srv.Close()
+
+	u, _ := url.Parse(srv.URL)
+	if err := Run(u, []string{"bees", "list"}, ioutil.Discard); err == nil {
+		t.Error("Expected requests without a token to fail")
+	}
+
+	os.Setenv(TokenEnvVar, "s3cret")
+	defer os.Unsetenv(TokenEnvVar)
+	if err := Run(u, []string{"bees", "list"}, ioutil.Discard); err != nil {
+		t.Errorf("Expected the token to get sent, got %v", err)
+	}
+}
@@ -0,0 +1,220 @@
+/*
+ *    Copyright (C) 2026 Christian Muehlhaeuser
+ *
+ *    This program is free software: you can redistribute it and/or modify
+ *    it under the terms of the GNU Affero General Public License as published
+ *    by the Free Software Foundation, either version 3 of the License, or
+ *    (at your option) any later version.
+ *
+ *    This program is distributed in the hope that it will be useful,
+ *    but WITHOUT ANY WARRANTY; without even the implied warranty of
+ *    MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ *    GNU Affero General Public License for more details.
+ *
+ *    You should have received a copy of the GNU Affero General Public License
+ *    along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ *    Authors:
+ *      Christian Muehlhaeuser <muesli@gmail.com>
+ */
+
+package cli
+
+import (
+	"bufio"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type placeholder struct {
+	Name  string
+	Type  string
+	Value interface{}
+}
+
+type event struct {
+	Bee     string
+	Name    string
+	Options []placeholder
+}
+
+func init() {
+	register("events",
+		command{name: "list", args: "[-bee <name>] [-limit <n>]", usage: "List recent events", run: listEvents},
+		command{name: "tail", args: "[-types event,chain,action] [-json]", usage: "Follow the live activity of the hive", run: tailEvents},
+		command{name: "fire", args: "-f <file> [-dryrun]", usage: "Inject the event described by a JSON file", run: fireEvent},
+	)
+}
+
+func formatOptions(opts []placeholder) string {
+	s := []string{}
+	for _, o := range opts {
+		s = append(s, fmt.Sprintf("%s=%v", o.Name, o.Value))
+	}
+	return strings.Join(s, " ")
+}
+
+func listEvents(c *client, args []string) error {
+	fs := flag.NewFlagSet("events list", flag.ContinueOnError)
+	fs.SetOutput(c.out)
+	bee := fs.String("bee", "", "Only list events of this bee")
+	limit := fs.Int("limit", 20, "Maximum number of events")
+	if _, err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	q := url.Values{}
+	q.Set("limit", strconv.Itoa(*limit))
+	if *bee != "" {
+		q.Set("bee", *bee)
+	}
+
+	var res struct {
+		Events []struct {
+			ID       string        `json:"id"`
+			Bee      string        `json:"bee"`
+			Name     string        `json:"name"`
+			Options  []placeholder `json:"options"`
+			Received time.Time     `json:"received"`
+			Chains   []string      `json:"chains"`
+		} `json:"events"`
+	}
+	if err := c.do("GET", "events?"+q.Encode(), nil, &res); err != nil {
+		return err
+	}
+
+	rows := [][]string{{"RECEIVED", "BEE", "EVENT", "CHAINS", "OPTIONS"}}
+	for _, ev := range res.Events {
+		rows = append(rows, []string{formatTime(ev.Received), ev.Bee, ev.Name, strings.Join(ev.Chains, ","), formatOptions(ev.Options)})
+	}
+	c.table(rows)
+	return nil
+}
+
+// streamMessage is a message of the hive's activity stream.
+type streamMessage struct {
+	Type     string        `json:"type"`
+	Time     time.Time     `json:"time"`
+	Event    *event        `json:"event"`
+	Chain    string        `json:"chain"`
+	Action   *event        `json:"action"`
+	Duration time.Duration `json:"duration"`
+	Error    string        `json:"error"`
+}
+
+func (msg streamMessage) String() string {
+	var s string
+	switch msg.Type {
+	case "event":
+		if msg.Event != nil {
+			s = fmt.Sprintf("%s/%s %s", msg.Event.Bee, msg.Event.Name, formatOptions(msg.Event.Options))
+		}
+	case "chain":
+		s = fmt.Sprintf("%s executed in %v", msg.Chain, msg.Duration)
+	case "action":
+		if msg.Action != nil {
+			s = fmt.Sprintf("%s/%s", msg.Action.Bee, msg.Action.Name)
+			if msg.Chain != "" {
+				s += " of chain " + msg.Chain
+			}
+		}
+	}
+	if msg.Error != "" {
+		s += " failed: " + msg.Error
+	}
+
+	return fmt.Sprintf("%s %-6s %s", msg.Time.Local().Format("15:04:05"), msg.Type, s)
+}
+
+func tailEvents(c *client, args []string) error {
+	fs := flag.NewFlagSet("events tail", flag.ContinueOnError)
+	fs.SetOutput(c.out)
+	types := fs.String("types", "", "Comma-separated list of event, chain and action")
+	raw := fs.Bool("json", false, "Print the messages as JSON")
+	if _, err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	path := "stream"
+	if *types != "" {
+		path += "?types=" + url.QueryEscape(*types)
+	}
+	resp, err := c.http.Get(c.url(path))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("Can't follow the stream: %s", resp.Status)
+	}
+
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(make([]byte, 64*1024), 4*1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if !strings.HasPrefix(line, "data: ") {
+			continue
+		}
+		data := strings.TrimPrefix(line, "data: ")
+		if *raw {
+			fmt.Fprintln(c.out, data)
+			continue
+		}
+
+		var msg streamMessage
+		if err := json.Unmarshal([]byte(data), &msg); err != nil {
+			continue
+		}
+		fmt.Fprintln(c.out, msg)
+	}
+	return scanner.Err()
+}
+
+func fireEvent(c *client, args []string) error {
+	fs, file := fileFlag(c, "events fire")
+	dryRun := fs.Bool("dryrun", false, "Only report the actions chains would execute")
+	if _, err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if *file == "" {
+		return fmt.Errorf("Expected arguments: -f <file>")
+	}
+
+	doc, err := readInput(*file, "event")
+	if err != nil {
+		return err
+	}
+	doc["dryrun"] = *dryRun
+
+	var res struct {
+		Injected struct {
+			ID      string   `json:"id"`
+			Chains  []string `json:"chains"`
+			Actions []struct {
+				Chain   string        `json:"chain"`
+				Bee     string        `json:"bee"`
+				Name    string        `json:"name"`
+				Options []placeholder `json:"options"`
+			} `json:"actions"`
+		} `json:"injected"`
+	}
+	if err := c.do("POST", "events", doc, &res); err != nil {
+		return err
+	}
+
+	if len(res.Injected.Chains) == 0 {
+		fmt.Fprintf(c.out, "Event %s matched no chain\n", res.Injected.ID)
+		return nil
+	}
+	fmt.Fprintf(c.out, "Event %s matched chains: %s\n", res.Injected.ID, strings.Join(res.Injected.Chains, ", "))
+	for _, a := range res.Injected.Actions {
+		fmt.Fprintf(c.out, "  %s would execute %s/%s %s\n", a.Chain, a.Bee, a.Name, formatOptions(a.Options))
+	}
+	return nil
+}
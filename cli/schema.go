@@ -0,0 +1,116 @@
+/*
+ *    Copyright (C) 2026 Christian Muehlhaeuser
+ *
+ *    This program is free software: you can redistribute it and/or modify
+ *    it under the terms of the GNU Affero General Public License as published
+ *    by the Free Software Foundation, either version 3 of the License, or
+ *    (at your option) any later version.
+ *
+ *    This program is distributed in the hope that it will be useful,
+ *    but WITHOUT ANY WARRANTY; without even the implied warranty of
+ *    MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ *    GNU Affero General Public License for more details.
+ *
+ *    You should have received a copy of the GNU Affero General Public License
+ *    along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ *    Authors:
+ *      Christian Muehlhaeuser <muesli@gmail.com>
+ */
+
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// hiveSchema mirrors the hives of the catalog served at /schema.
+type hiveSchema struct {
+	ID      string
+	Events  []descriptorSchema
+	Actions []descriptorSchema
+}
+
+type descriptorSchema struct {
+	Name        string
+	Description string
+	Options     []struct {
+		Name string
+	}
+}
+
+func init() {
+	register("schema",
+		command{name: "list", usage: "List the events and actions of all hives", run: listSchema},
+		command{name: "json", usage: "Print the catalog of all hives as JSON", run: func(c *client, args []string) error {
+			return copySchema(c, "json")
+		}},
+		command{name: "markdown", usage: "Print the documentation of all hives as Markdown", run: func(c *client, args []string) error {
+			return copySchema(c, "markdown")
+		}},
+	)
+}
+
+func getSchema(c *client, format string) (*http.Response, error) {
+	resp, err := c.http.Get(c.url("schema?format=" + format))
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, fmt.Errorf("Can't fetch the schema: %s", resp.Status)
+	}
+	return resp, nil
+}
+
+func copySchema(c *client, format string) error {
+	resp, err := getSchema(c, format)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	_, err = io.Copy(c.out, resp.Body)
+	return err
+}
+
+func listSchema(c *client, args []string) error {
+	resp, err := getSchema(c, "json")
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	var catalog struct {
+		Hives        []hiveSchema
+		SystemEvents []descriptorSchema
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&catalog); err != nil {
+		return err
+	}
+	catalog.Hives = append(catalog.Hives, hiveSchema{ID: "beehive", Events: catalog.SystemEvents})
+
+	rows := [][]string{{"HIVE", "KIND", "NAME", "PLACEHOLDERS"}}
+	for _, h := range catalog.Hives {
+		for _, e := range h.Events {
+			rows = append(rows, []string{h.ID, "event", e.Name, e.placeholders()})
+		}
+		for _, a := range h.Actions {
+			rows = append(rows, []string{h.ID, "action", a.Name, a.placeholders()})
+		}
+	}
+	c.table(rows)
+	return nil
+}
+
+func (d descriptorSchema) placeholders() string {
+	names := []string{}
+	for _, o := range d.Options {
+		names = append(names, o.Name)
+	}
+	return strings.Join(names, ", ")
+}
@@ -0,0 +1,71 @@
+/*
+ *    Copyright (C) 2026 Christian Muehlhaeuser
+ *
+ *    This program is free software: you can redistribute it and/or modify
+ *    it under the terms of the GNU Affero General Public License as published
+ *    by the Free Software Foundation, either version 3 of the License, or
+ *    (at your option) any later version.
+ *
+ *    This program is distributed in the hope that it will be useful,
+ *    but WITHOUT ANY WARRANTY; without even the implied warranty of
+ *    MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ *    GNU Affero General Public License for more details.
+ *
+ *    You should have received a copy of the GNU Affero General Public License
+ *    along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ *    Authors:
+ *      Christian Muehlhaeuser <muesli@gmail.com>
+ */
+
+// Package dnsenricher provides an enricher resolving IP addresses to host
+// names.
+package dnsenricher
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/muesli/beehive/enrichers"
+)
+
+// DNSEnricher looks up the host name of an IP address.
+type DNSEnricher struct {
+	lookupAddr func(ctx context.Context, addr string) ([]string, error)
+}
+
+// Name returns the name of this Enricher.
+func (enricher *DNSEnricher) Name() string {
+	return "reverse_dns"
+}
+
+// Description returns the description of this Enricher.
+func (enricher *DNSEnricher) Description() string {
+	return "Looks up the host name of an IP address"
+}
+
+// Enrich returns the first host name the IP address value resolves to.
+func (enricher *DNSEnricher) Enrich(ctx context.Context, value interface{}, options map[string]interface{}) (interface{}, error) {
+	addr := strings.TrimSpace(fmt.Sprint(value))
+	if host, _, err := net.SplitHostPort(addr); err == nil {
+		addr = host
+	}
+	if net.ParseIP(addr) == nil {
+		return nil, fmt.Errorf("Invalid IP address %q", addr)
+	}
+
+	names, err := enricher.lookupAddr(ctx, addr)
+	if err != nil {
+		return nil, err
+	}
+	if len(names) == 0 {
+		return nil, fmt.Errorf("No host name found for %s", addr)
+	}
+	return strings.TrimSuffix(names[0], "."), nil
+}
+
+func init() {
+	enrichers.RegisterEnricher(&DNSEnricher{lookupAddr: net.DefaultResolver.LookupAddr})
+}
@@ -0,0 +1,87 @@
+/*
+ *    Copyright (C) 2026 Christian Muehlhaeuser
+ *
+ *    This program is free software: you can redistribute it and/or modify
+ *    it under the terms of the GNU Affero General Public License as published
+ *    by the Free Software Foundation, either version 3 of the License, or
+ *    (at your option) any later version.
+ *
+ *    This program is distributed in the hope that it will be useful,
+ *    but WITHOUT ANY WARRANTY; without even the implied warranty of
+ *    MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ *    GNU Affero General Public License for more details.
+ *
+ *    You should have received a copy of the GNU Affero General Public License
+ *    along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ *    Authors:
+ *      Christian Muehlhaeuser <muesli@gmail.com>
+ */
+
+// Package enrichers contains Beehive's event enrichment system.
+package enrichers
+
+import (
+	"context"
+	"sort"
+	"sync"
+)
+
+// EnricherInterface is an interface all Enrichers implement. Enrichers get
+// registered with RegisterEnricher, usually from an init function, and add
+// placeholders to events before chains get matched, e.g. the host name
+// belonging to an IP address.
+type EnricherInterface interface {
+	// Name of the enricher
+	Name() string
+	// Description of the enricher
+	Description() string
+
+	// Enrich looks up value and returns the value of the placeholder to add,
+	// configured by options. ctx gets cancelled once the lookup took too
+	// long.
+	Enrich(ctx context.Context, value interface{}, options map[string]interface{}) (interface{}, error)
+}
+
+var (
+	enrichers     = make(map[string]*EnricherInterface)
+	enrichersLock sync.RWMutex
+)
+
+// RegisterEnricher gets called by Enrichers to register themselves. An
+// enricher replaces any enricher previously registered with the same name.
+func RegisterEnricher(enricher EnricherInterface) {
+	enrichersLock.Lock()
+	defer enrichersLock.Unlock()
+
+	enrichers[enricher.Name()] = &enricher
+}
+
+// GetEnricher returns an enricher with a specific name
+func GetEnricher(identifier string) *EnricherInterface {
+	enrichersLock.RLock()
+	defer enrichersLock.RUnlock()
+
+	enricher, ok := enrichers[identifier]
+	if ok {
+		return enricher
+	}
+
+	return nil
+}
+
+// GetEnrichers returns all registered enrichers, sorted by name
+func GetEnrichers() []*EnricherInterface {
+	enrichersLock.RLock()
+	r := make([]*EnricherInterface, 0, len(enrichers))
+	for _, enricher := range enrichers {
+		r = append(r, enricher)
+	}
+	enrichersLock.RUnlock()
+
+	sort.Slice(r, func(i, j int) bool {
+		return (*r[i]).Name() < (*r[j]).Name()
+	})
+
+	return r
+}
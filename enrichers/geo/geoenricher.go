@@ -0,0 +1,138 @@
+/*
+ *    Copyright (C) 2026 Christian Muehlhaeuser
+ *
+ *    This program is free software: you can redistribute it and/or modify
+ *    it under the terms of the GNU Affero General Public License as published
+ *    by the Free Software Foundation, either version 3 of the License, or
+ *    (at your option) any later version.
+ *
+ *    This program is distributed in the hope that it will be useful,
+ *    but WITHOUT ANY WARRANTY; without even the implied warranty of
+ *    MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ *    GNU Affero General Public License for more details.
+ *
+ *    You should have received a copy of the GNU Affero General Public License
+ *    along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ *    Authors:
+ *      Christian Muehlhaeuser <muesli@gmail.com>
+ */
+
+// Package geoenricher provides an enricher resolving coordinates to
+// addresses.
+package geoenricher
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/muesli/beehive/enrichers"
+)
+
+// DefaultURL is the Nominatim instance used for reverse geocoding, unless the
+// "url" option names another one.
+const DefaultURL = "https://nominatim.openstreetmap.org/reverse"
+
+// GeoEnricher looks up the address of a location using a Nominatim server.
+type GeoEnricher struct {
+	client *http.Client
+}
+
+// Name returns the name of this Enricher.
+func (enricher *GeoEnricher) Name() string {
+	return "reverse_geocode"
+}
+
+// Description returns the description of this Enricher.
+func (enricher *GeoEnricher) Description() string {
+	return "Looks up the address of a latitude & longitude"
+}
+
+// Enrich returns the address of the coordinates value, given as "lat,lon" or
+// as a map of "lat" and "lon". The address is a map of its parts, e.g. "city"
+// and "country", and "display_name" holds all of it.
+func (enricher *GeoEnricher) Enrich(ctx context.Context, value interface{}, options map[string]interface{}) (interface{}, error) {
+	lat, lon, err := coordinates(value)
+	if err != nil {
+		return nil, err
+	}
+
+	u := DefaultURL
+	if s, ok := options["url"].(string); ok && len(s) > 0 {
+		u = s
+	}
+	q := url.Values{}
+	q.Set("format", "jsonv2")
+	q.Set("lat", strconv.FormatFloat(lat, 'f', -1, 64))
+	q.Set("lon", strconv.FormatFloat(lon, 'f', -1, 64))
+
+	req, err := http.NewRequest(http.MethodGet, u+"?"+q.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+	// Nominatim's usage policy requires identifying the application
+	req.Header.Set("User-Agent", "Beehive")
+	resp, err := enricher.client.Do(req.WithContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("Reverse geocoding failed: %s", resp.Status)
+	}
+
+	var res struct {
+		DisplayName string                 `json:"display_name"`
+		Address     map[string]interface{} `json:"address"`
+		Error       string                 `json:"error"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&res); err != nil {
+		return nil, err
+	}
+	if len(res.Error) > 0 {
+		return nil, fmt.Errorf("Reverse geocoding failed: %s", res.Error)
+	}
+
+	address := map[string]interface{}{"display_name": res.DisplayName}
+	for k, v := range res.Address {
+		address[k] = v
+	}
+	return address, nil
+}
+
+// coordinates parses a location given as "lat,lon" or as a map of "lat" and
+// "lon".
+func coordinates(value interface{}) (float64, float64, error) {
+	var lat, lon interface{}
+	switch v := value.(type) {
+	case map[string]interface{}:
+		lat, lon = v["lat"], v["lon"]
+	case string:
+		parts := strings.Split(v, ",")
+		if len(parts) != 2 {
+			return 0, 0, fmt.Errorf("Invalid coordinates %q", v)
+		}
+		lat, lon = parts[0], parts[1]
+	default:
+		return 0, 0, fmt.Errorf("Invalid coordinates %v", value)
+	}
+
+	la, err := strconv.ParseFloat(strings.TrimSpace(fmt.Sprint(lat)), 64)
+	if err != nil {
+		return 0, 0, fmt.Errorf("Invalid latitude %v", lat)
+	}
+	lo, err := strconv.ParseFloat(strings.TrimSpace(fmt.Sprint(lon)), 64)
+	if err != nil {
+		return 0, 0, fmt.Errorf("Invalid longitude %v", lon)
+	}
+	return la, lo, nil
+}
+
+func init() {
+	enrichers.RegisterEnricher(&GeoEnricher{client: &http.Client{}})
+}
@@ -0,0 +1,83 @@
+/*
+ *    Copyright (C) 2026 Christian Muehlhaeuser
+ *
+ *    This program is free software: you can redistribute it and/or modify
+ *    it under the terms of the GNU Affero General Public License as published
+ *    by the Free Software Foundation, either version 3 of the License, or
+ *    (at your option) any later version.
+ *
+ *    This program is distributed in the hope that it will be useful,
+ *    but WITHOUT ANY WARRANTY; without even the implied warranty of
+ *    MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ *    GNU Affero General Public License for more details.
+ *
+ *    You should have received a copy of the GNU Affero General Public License
+ *    along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ *    Authors:
+ *      Christian Muehlhaeuser <muesli@gmail.com>
+ */
+
+package geoenricher
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestCoordinates(t *testing.T) {
+	tests := []struct {
+		value    interface{}
+		lat, lon float64
+		valid    bool
+	}{
+		{"52.52,13.405", 52.52, 13.405, true},
+		{" 52.52 , 13.405 ", 52.52, 13.405, true},
+		{map[string]interface{}{"lat": 52.52, "lon": "13.405"}, 52.52, 13.405, true},
+		{"52.52", 0, 0, false},
+		{"north,east", 0, 0, false},
+		{42, 0, 0, false},
+	}
+
+	for _, test := range tests {
+		lat, lon, err := coordinates(test.value)
+		if (err == nil) != test.valid {
+			t.Errorf("Expected valid to be %v for %v, got %v", test.valid, test.value, err)
+			continue
+		}
+		if lat != test.lat || lon != test.lon {
+			t.Errorf("Expected %v,%v for %v, got %v,%v", test.lat, test.lon, test.value, lat, lon)
+		}
+	}
+}
+
+func TestGeoEnricher(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if q.Get("lat") != "52.52" || q.Get("lon") != "13.405" {
+			fmt.Fprint(w, `{"error": "Unable to geocode"}`)
+			return
+		}
+		fmt.Fprint(w, `{"display_name": "Berlin, Germany", "address": {"city": "Berlin", "country": "Germany"}}`)
+	}))
+	defer ts.Close()
+
+	e := GeoEnricher{client: ts.Client()}
+	options := map[string]interface{}{"url": ts.URL}
+
+	r, err := e.Enrich(context.Background(), "52.52,13.405", options)
+	if err != nil {
+		t.Fatal(err)
+	}
+	address := r.(map[string]interface{})
+	if address["city"] != "Berlin" || address["display_name"] != "Berlin, Germany" {
+		t.Errorf("Unexpected address %v", address)
+	}
+
+	if _, err := e.Enrich(context.Background(), "0,0", options); err == nil {
+		t.Error("Expected an error for an unknown location")
+	}
+}
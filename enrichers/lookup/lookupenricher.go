@@ -0,0 +1,95 @@
+/*
+ *    Copyright (C) 2026 Christian Muehlhaeuser
+ *
+ *    This program is free software: you can redistribute it and/or modify
+ *    it under the terms of the GNU Affero General Public License as published
+ *    by the Free Software Foundation, either version 3 of the License, or
+ *    (at your option) any later version.
+ *
+ *    This program is distributed in the hope that it will be useful,
+ *    but WITHOUT ANY WARRANTY; without even the implied warranty of
+ *    MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ *    GNU Affero General Public License for more details.
+ *
+ *    You should have received a copy of the GNU Affero General Public License
+ *    along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ *    Authors:
+ *      Christian Muehlhaeuser <muesli@gmail.com>
+ */
+
+// Package lookupenricher provides an enricher looking up values in a table
+// or a JSON API, e.g. users by their ID.
+package lookupenricher
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/muesli/beehive/enrichers"
+)
+
+// LookupEnricher looks up values in a table or a JSON API.
+type LookupEnricher struct {
+	client *http.Client
+}
+
+// Name returns the name of this Enricher.
+func (enricher *LookupEnricher) Name() string {
+	return "lookup"
+}
+
+// Description returns the description of this Enricher.
+func (enricher *LookupEnricher) Description() string {
+	return "Looks up a value in a table or a JSON API"
+}
+
+// Enrich looks up value in the "table" option, which maps values to the
+// results, or fetches the result from the "url" option, which refers to the
+// value as "{value}", e.g. "https://example.com/users/{value}".
+func (enricher *LookupEnricher) Enrich(ctx context.Context, value interface{}, options map[string]interface{}) (interface{}, error) {
+	key := fmt.Sprint(value)
+
+	if table, ok := options["table"].(map[string]interface{}); ok {
+		if r, ok := table[key]; ok {
+			return r, nil
+		}
+		if r, ok := options["default"]; ok {
+			return r, nil
+		}
+		return nil, fmt.Errorf("No entry for %q", key)
+	}
+
+	u, ok := options["url"].(string)
+	if !ok || len(u) == 0 {
+		return nil, errors.New("The lookup enricher needs either a table or a url")
+	}
+	u = strings.Replace(u, "{value}", url.PathEscape(key), -1)
+
+	req, err := http.NewRequest(http.MethodGet, u, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	resp, err := enricher.client.Do(req.WithContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("Looking up %q failed: %s", key, resp.Status)
+	}
+
+	var r interface{}
+	err = json.NewDecoder(resp.Body).Decode(&r)
+	return r, err
+}
+
+func init() {
+	enrichers.RegisterEnricher(&LookupEnricher{client: &http.Client{}})
+}
@@ -0,0 +1,78 @@
+/*
+ *    Copyright (C) 2026 Christian Muehlhaeuser
+ *
+ *    This program is free software: you can redistribute it and/or modify
+ *    it under the terms of the GNU Affero General Public License as published
+ *    by the Free Software Foundation, either version 3 of the License, or
+ *    (at your option) any later version.
+ *
+ *    This program is distributed in the hope that it will be useful,
+ *    but WITHOUT ANY WARRANTY; without even the implied warranty of
+ *    MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ *    GNU Affero General Public License for more details.
+ *
+ *    You should have received a copy of the GNU Affero General Public License
+ *    along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ *    Authors:
+ *      Christian Muehlhaeuser <muesli@gmail.com>
+ */
+
+package lookupenricher
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestLookupTable(t *testing.T) {
+	e := LookupEnricher{client: &http.Client{}}
+	options := map[string]interface{}{
+		"table": map[string]interface{}{"42": "alice"},
+	}
+
+	r, err := e.Enrich(context.Background(), 42, options)
+	if err != nil || r != "alice" {
+		t.Errorf("Expected alice, got %v (%v)", r, err)
+	}
+	if _, err := e.Enrich(context.Background(), 23, options); err == nil {
+		t.Error("Expected an error for a missing entry")
+	}
+
+	options["default"] = "nobody"
+	if r, _ := e.Enrich(context.Background(), 23, options); r != "nobody" {
+		t.Errorf("Expected the default, got %v", r)
+	}
+
+	if _, err := e.Enrich(context.Background(), 42, nil); err == nil {
+		t.Error("Expected an error without a table or a url")
+	}
+}
+
+func TestLookupURL(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/users/42" {
+			http.NotFound(w, r)
+			return
+		}
+		fmt.Fprint(w, `{"name": "alice"}`)
+	}))
+	defer ts.Close()
+
+	e := LookupEnricher{client: ts.Client()}
+	options := map[string]interface{}{"url": ts.URL + "/users/{value}"}
+
+	r, err := e.Enrich(context.Background(), "42", options)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m, ok := r.(map[string]interface{}); !ok || m["name"] != "alice" {
+		t.Errorf("Expected the decoded response, got %v", r)
+	}
+	if _, err := e.Enrich(context.Background(), "23", options); err == nil {
+		t.Error("Expected an error for a failed request")
+	}
+}
@@ -0,0 +1,313 @@
+/*
+ *    Copyright (C) 2026 Christian Muehlhaeuser
+ *
+ *    This program is free software: you can redistribute it and/or modify
+ *    it under the terms of the GNU Affero General Public License as published
+ *    by the Free Software Foundation, either version 3 of the License, or
+ *    (at your option) any later version.
+ *
+ *    This program is distributed in the hope that it will be useful,
+ *    but WITHOUT ANY WARRANTY; without even the implied warranty of
+ *    MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ *    GNU Affero General Public License for more details.
+ *
+ *    You should have received a copy of the GNU Affero General Public License
+ *    along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ *    Authors:
+ *      Christian Muehlhaeuser <muesli@gmail.com>
+ */
+
+package exprfilter
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// node is a node of a parsed expression.
+type node interface {
+	eval(data map[string]interface{}) (interface{}, error)
+}
+
+type literalNode struct {
+	value interface{}
+}
+
+func (n *literalNode) eval(data map[string]interface{}) (interface{}, error) {
+	return n.value, nil
+}
+
+// identNode references a placeholder, or a value nested in one.
+type identNode struct {
+	path []string
+}
+
+func (n *identNode) eval(data map[string]interface{}) (interface{}, error) {
+	var v interface{} = data
+	for _, name := range n.path {
+		m, ok := v.(map[string]interface{})
+		if !ok {
+			return nil, nil
+		}
+		v = m[name]
+	}
+	return v, nil
+}
+
+type notNode struct {
+	n node
+}
+
+func (n *notNode) eval(data map[string]interface{}) (interface{}, error) {
+	b, err := evalBool(n.n, data)
+	if err != nil {
+		return nil, err
+	}
+	return !b, nil
+}
+
+// logicalNode is a short-circuiting && or ||.
+type logicalNode struct {
+	op          string
+	left, right node
+}
+
+func (n *logicalNode) eval(data map[string]interface{}) (interface{}, error) {
+	left, err := evalBool(n.left, data)
+	if err != nil {
+		return nil, err
+	}
+	if (n.op == "&&" && !left) || (n.op == "||" && left) {
+		return left, nil
+	}
+	return evalBool(n.right, data)
+}
+
+type compareNode struct {
+	op          string
+	left, right node
+}
+
+func (n *compareNode) eval(data map[string]interface{}) (interface{}, error) {
+	left, err := n.left.eval(data)
+	if err != nil {
+		return nil, err
+	}
+	right, err := n.right.eval(data)
+	if err != nil {
+		return nil, err
+	}
+
+	switch n.op {
+	case "==":
+		return equal(left, right), nil
+	case "!=":
+		return !equal(left, right), nil
+	case "=~", "!~":
+		matched, err := match(left, right)
+		if err != nil {
+			return nil, err
+		}
+		return matched == (n.op == "=~"), nil
+	}
+
+	c, err := compare(left, right)
+	if err != nil {
+		return nil, err
+	}
+	switch n.op {
+	case "<":
+		return c < 0, nil
+	case "<=":
+		return c <= 0, nil
+	case ">":
+		return c > 0, nil
+	default:
+		return c >= 0, nil
+	}
+}
+
+type callNode struct {
+	name string
+	fn   func(args []interface{}) (interface{}, error)
+	args []node
+}
+
+func (n *callNode) eval(data map[string]interface{}) (interface{}, error) {
+	args := make([]interface{}, len(n.args))
+	for i, arg := range n.args {
+		v, err := arg.eval(data)
+		if err != nil {
+			return nil, err
+		}
+		args[i] = v
+	}
+
+	v, err := n.fn(args)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", n.name, err)
+	}
+	return v, nil
+}
+
+// evalBool evaluates n, which has to result in a boolean. Null counts as
+// false.
+func evalBool(n node, data map[string]interface{}) (bool, error) {
+	v, err := n.eval(data)
+	if err != nil {
+		return false, err
+	}
+	switch v := v.(type) {
+	case bool:
+		return v, nil
+	case nil:
+		return false, nil
+	}
+	return false, fmt.Errorf("Expected a boolean, got %v", v)
+}
+
+// number converts numeric values to float64.
+func number(v interface{}) (float64, bool) {
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(rv.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(rv.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return rv.Float(), true
+	}
+	return 0, false
+}
+
+// equal compares two values, treating all numeric types alike.
+func equal(a, b interface{}) bool {
+	if x, ok := number(a); ok {
+		if y, ok := number(b); ok {
+			return x == y
+		}
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+// compare orders two numbers or two strings.
+func compare(a, b interface{}) (int, error) {
+	if x, ok := number(a); ok {
+		if y, ok := number(b); ok {
+			switch {
+			case x < y:
+				return -1, nil
+			case x > y:
+				return 1, nil
+			}
+			return 0, nil
+		}
+	}
+	if x, ok := a.(string); ok {
+		if y, ok := b.(string); ok {
+			return strings.Compare(x, y), nil
+		}
+	}
+	return 0, fmt.Errorf("Can't compare %v and %v", a, b)
+}
+
+var (
+	regexps      = make(map[string]*regexp.Regexp)
+	regexpsMutex sync.Mutex
+)
+
+// match reports whether the string s matches the regular expression re.
+// Compiled expressions get cached.
+func match(s, re interface{}) (bool, error) {
+	str, ok := s.(string)
+	if !ok {
+		if s == nil {
+			return false, nil
+		}
+		str = fmt.Sprint(s)
+	}
+	pattern, ok := re.(string)
+	if !ok {
+		return false, fmt.Errorf("Regular expression must be a string, got %v", re)
+	}
+
+	regexpsMutex.Lock()
+	r, ok := regexps[pattern]
+	if !ok {
+		var err error
+		if r, err = regexp.Compile(pattern); err != nil {
+			regexpsMutex.Unlock()
+			return false, err
+		}
+		regexps[pattern] = r
+	}
+	regexpsMutex.Unlock()
+
+	return r.MatchString(str), nil
+}
+
+// str converts a value to a string, null being the empty string.
+func str(v interface{}) string {
+	if v == nil {
+		return ""
+	}
+	if s, ok := v.(string); ok {
+		return s
+	}
+	return fmt.Sprint(v)
+}
+
+type function struct {
+	args int
+	fn   func(args []interface{}) (interface{}, error)
+}
+
+var functions = map[string]function{
+	// contains checks whether a string contains a substring, or a list
+	// contains an element
+	"contains": {2, func(args []interface{}) (interface{}, error) {
+		if args[0] == nil {
+			return false, nil
+		}
+		rv := reflect.ValueOf(args[0])
+		if rv.Kind() == reflect.Slice || rv.Kind() == reflect.Array {
+			for i := 0; i < rv.Len(); i++ {
+				if equal(rv.Index(i).Interface(), args[1]) {
+					return true, nil
+				}
+			}
+			return false, nil
+		}
+		return strings.Contains(str(args[0]), str(args[1])), nil
+	}},
+	"startsWith": {2, func(args []interface{}) (interface{}, error) {
+		return strings.HasPrefix(str(args[0]), str(args[1])), nil
+	}},
+	"endsWith": {2, func(args []interface{}) (interface{}, error) {
+		return strings.HasSuffix(str(args[0]), str(args[1])), nil
+	}},
+	"matches": {2, func(args []interface{}) (interface{}, error) {
+		return match(args[0], args[1])
+	}},
+	"lower": {1, func(args []interface{}) (interface{}, error) {
+		return strings.ToLower(str(args[0])), nil
+	}},
+	"upper": {1, func(args []interface{}) (interface{}, error) {
+		return strings.ToUpper(str(args[0])), nil
+	}},
+	"len": {1, func(args []interface{}) (interface{}, error) {
+		if args[0] == nil {
+			return 0, nil
+		}
+		rv := reflect.ValueOf(args[0])
+		switch rv.Kind() {
+		case reflect.String, reflect.Slice, reflect.Array, reflect.Map:
+			return rv.Len(), nil
+		}
+		return nil, fmt.Errorf("Can't get the length of %v", args[0])
+	}},
+}
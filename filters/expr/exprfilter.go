@@ -0,0 +1,103 @@
+/*
+ *    Copyright (C) 2026 Christian Muehlhaeuser
+ *
+ *    This program is free software: you can redistribute it and/or modify
+ *    it under the terms of the GNU Affero General Public License as published
+ *    by the Free Software Foundation, either version 3 of the License, or
+ *    (at your option) any later version.
+ *
+ *    This program is distributed in the hope that it will be useful,
+ *    but WITHOUT ANY WARRANTY; without even the implied warranty of
+ *    MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ *    GNU Affero General Public License for more details.
+ *
+ *    You should have received a copy of the GNU Affero General Public License
+ *    along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ *    Authors:
+ *      Christian Muehlhaeuser <muesli@gmail.com>
+ */
+
+// Package exprfilter provides a filter evaluating boolean expressions.
+package exprfilter
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/muesli/beehive/filters"
+)
+
+// ExprFilter is a filter evaluating expressions, like
+//
+//	contains(text, "deploy") && channel == "#ops"
+//
+// against an event's placeholders. Placeholders are referenced by name,
+// nested values with a dot, e.g. "user.name". Unknown placeholders evaluate
+// to null. The expression has to evaluate to a boolean.
+//
+// Supported are string, number, boolean and null literals, the comparison
+// operators ==, !=, <, <=, > and >=, the boolean operators &&, || and !, the
+// regular expression operators =~ and !~, parentheses and the functions
+// contains, startsWith, endsWith, matches, lower, upper and len.
+type ExprFilter struct {
+	cache sync.Map
+}
+
+// Name returns the name of this Filter.
+func (filter *ExprFilter) Name() string {
+	return "expr"
+}
+
+// Description returns the description of this Filter.
+func (filter *ExprFilter) Description() string {
+	return "This filter passes when a boolean expression evaluates to true"
+}
+
+// Passes returns true when the Filter matched the data. It panics if the
+// expression is invalid or can't be evaluated, e.g. due to mismatching types.
+func (filter *ExprFilter) Passes(data map[string]interface{}, v string) bool {
+	n, err := filter.compile(v)
+	if err != nil {
+		panic(err)
+	}
+
+	res, err := n.eval(data)
+	if err != nil {
+		panic(err)
+	}
+	switch res := res.(type) {
+	case bool:
+		return res
+	case nil:
+		return false
+	}
+	panic(fmt.Errorf("Expression %q evaluates to %v, not a boolean", strings.TrimSpace(v), res))
+}
+
+// Validate checks an expression for syntax errors, without evaluating it.
+func (filter *ExprFilter) Validate(v string) error {
+	_, err := filter.compile(v)
+	return err
+}
+
+// compile parses an expression, memoizing the result.
+func (filter *ExprFilter) compile(v string) (node, error) {
+	if n, ok := filter.cache.Load(v); ok {
+		return n.(node), nil
+	}
+
+	n, err := parse(v)
+	if err != nil {
+		return nil, err
+	}
+	filter.cache.Store(v, n)
+	return n, nil
+}
+
+func init() {
+	f := ExprFilter{}
+
+	filters.RegisterFilter(&f)
+}
@@ -0,0 +1,93 @@
+/*
+ *    Copyright (C) 2026 Christian Muehlhaeuser
+ *
+ *    This program is free software: you can redistribute it and/or modify
+ *    it under the terms of the GNU Affero General Public License as published
+ *    by the Free Software Foundation, either version 3 of the License, or
+ *    (at your option) any later version.
+ *
+ *    This program is distributed in the hope that it will be useful,
+ *    but WITHOUT ANY WARRANTY; without even the implied warranty of
+ *    MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ *    GNU Affero General Public License for more details.
+ *
+ *    You should have received a copy of the GNU Affero General Public License
+ *    along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ *    Authors:
+ *      Christian Muehlhaeuser <muesli@gmail.com>
+ */
+
+package exprfilter
+
+import (
+	"testing"
+)
+
+func TestExprFilter(t *testing.T) {
+	f := ExprFilter{}
+
+	o := map[string]interface{}{
+		"text":    "Deploying beehive v2",
+		"channel": "#ops",
+		"count":   3,
+		"ratio":   0.5,
+		"urgent":  true,
+		"tags":    []interface{}{"release", "prod"},
+		"user":    map[string]interface{}{"name": "muesli"},
+	}
+
+	tests := []struct {
+		expr   string
+		passed bool
+	}{
+		{`contains(text, "beehive") && channel == "#ops"`, true},
+		{`contains(text, "beehive") && channel == '#dev'`, false},
+		{`channel == "#dev" || urgent`, true},
+		{`!urgent`, false},
+		{`!(count > 2 && ratio < 1)`, false},
+		{`count == 3 && count >= 3 && count <= 3 && count != 4`, true},
+		{`ratio > 0.25`, true},
+		{`text =~ "(?i)^deploying"`, true},
+		{`text !~ "rollback"`, true},
+		{`matches(channel, "^#o")`, true},
+		{`startsWith(lower(text), "deploy") && endsWith(upper(text), "V2")`, true},
+		{`contains(tags, "prod")`, true},
+		{`len(tags) == 2 && len(text) > 10`, true},
+		{`user.name == "muesli"`, true},
+		{`missing == null`, true},
+		{`missing`, false},
+		{`"a" < "b"`, true},
+	}
+	for _, test := range tests {
+		if passed := f.Passes(o, test.expr); passed != test.passed {
+			t.Errorf("Expected %s to evaluate to %v, got %v", test.expr, test.passed, passed)
+		}
+	}
+}
+
+func TestExprFilterErrors(t *testing.T) {
+	f := ExprFilter{}
+	o := map[string]interface{}{"text": "hello", "count": 1}
+
+	for _, expr := range []string{
+		`text ==`,
+		`(text == "hello"`,
+		`text == "hello`,
+		`unknown(text)`,
+		`contains(text)`,
+		`text`,
+		`count < "a"`,
+		`text =~ "("`,
+		`text == "a" "b"`,
+	} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("Expected %s to fail", expr)
+				}
+			}()
+			f.Passes(o, expr)
+		}()
+	}
+}
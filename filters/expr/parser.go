@@ -0,0 +1,320 @@
+/*
+ *    Copyright (C) 2026 Christian Muehlhaeuser
+ *
+ *    This program is free software: you can redistribute it and/or modify
+ *    it under the terms of the GNU Affero General Public License as published
+ *    by the Free Software Foundation, either version 3 of the License, or
+ *    (at your option) any later version.
+ *
+ *    This program is distributed in the hope that it will be useful,
+ *    but WITHOUT ANY WARRANTY; without even the implied warranty of
+ *    MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ *    GNU Affero General Public License for more details.
+ *
+ *    You should have received a copy of the GNU Affero General Public License
+ *    along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ *    Authors:
+ *      Christian Muehlhaeuser <muesli@gmail.com>
+ */
+
+package exprfilter
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokIdent
+	tokString
+	tokNumber
+	tokOp
+	tokLParen
+	tokRParen
+	tokComma
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	pos  int
+}
+
+// operators lists all operators, longest first.
+var operators = []string{"&&", "||", "==", "!=", "<=", ">=", "=~", "!~", "<", ">", "!"}
+
+// lex splits an expression into tokens.
+func lex(s string) ([]token, error) {
+	var tokens []token
+	for i := 0; i < len(s); {
+		c := rune(s[i])
+		switch {
+		case unicode.IsSpace(c):
+			i++
+
+		case c == '(':
+			tokens = append(tokens, token{tokLParen, "(", i})
+			i++
+		case c == ')':
+			tokens = append(tokens, token{tokRParen, ")", i})
+			i++
+		case c == ',':
+			tokens = append(tokens, token{tokComma, ",", i})
+			i++
+
+		case c == '"' || c == '\'':
+			j := i + 1
+			for ; j < len(s) && rune(s[j]) != c; j++ {
+				if s[j] == '\\' {
+					j++
+				}
+			}
+			if j >= len(s) {
+				return nil, fmt.Errorf("Unterminated string at position %d", i)
+			}
+			text := s[i : j+1]
+			if c == '\'' {
+				text = `"` + strings.Replace(strings.Replace(text[1:len(text)-1], `\'`, `'`, -1), `"`, `\"`, -1) + `"`
+			}
+			str, err := strconv.Unquote(text)
+			if err != nil {
+				return nil, fmt.Errorf("Invalid string at position %d: %v", i, err)
+			}
+			tokens = append(tokens, token{tokString, str, i})
+			i = j + 1
+
+		case unicode.IsDigit(c):
+			j := i
+			for j < len(s) && (unicode.IsDigit(rune(s[j])) || s[j] == '.') {
+				j++
+			}
+			tokens = append(tokens, token{tokNumber, s[i:j], i})
+			i = j
+
+		case unicode.IsLetter(c) || c == '_':
+			j := i
+			for j < len(s) && (unicode.IsLetter(rune(s[j])) || unicode.IsDigit(rune(s[j])) || s[j] == '_' || s[j] == '.') {
+				j++
+			}
+			tokens = append(tokens, token{tokIdent, s[i:j], i})
+			i = j
+
+		default:
+			op := ""
+			for _, o := range operators {
+				if strings.HasPrefix(s[i:], o) {
+					op = o
+					break
+				}
+			}
+			if op == "" {
+				return nil, fmt.Errorf("Unexpected character %q at position %d", c, i)
+			}
+			tokens = append(tokens, token{tokOp, op, i})
+			i += len(op)
+		}
+	}
+
+	return append(tokens, token{tokEOF, "", len(s)}), nil
+}
+
+// parser is a recursive descent parser for expressions. In order of
+// increasing precedence:
+//
+//	or         = and { "||" and }
+//	and        = comparison { "&&" comparison }
+//	comparison = unary [ ( "==" | "!=" | "<" | "<=" | ">" | ">=" | "=~" | "!~" ) unary ]
+//	unary      = "!" unary | primary
+//	primary    = literal | ident | ident "(" [ or { "," or } ] ")" | "(" or ")"
+type parser struct {
+	tokens []token
+	pos    int
+}
+
+// parse parses an expression.
+func parse(s string) (node, error) {
+	tokens, err := lex(s)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &parser{tokens: tokens}
+	n, err := p.or()
+	if err != nil {
+		return nil, err
+	}
+	if t := p.peek(); t.kind != tokEOF {
+		return nil, fmt.Errorf("Unexpected %q at position %d", t.text, t.pos)
+	}
+	return n, nil
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	t := p.tokens[p.pos]
+	if t.kind != tokEOF {
+		p.pos++
+	}
+	return t
+}
+
+// accept consumes the next token if it's one of the given operators.
+func (p *parser) accept(ops ...string) (string, bool) {
+	t := p.peek()
+	if t.kind != tokOp {
+		return "", false
+	}
+	for _, op := range ops {
+		if t.text == op {
+			p.pos++
+			return op, true
+		}
+	}
+	return "", false
+}
+
+func (p *parser) or() (node, error) {
+	left, err := p.and()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		if _, ok := p.accept("||"); !ok {
+			return left, nil
+		}
+		right, err := p.and()
+		if err != nil {
+			return nil, err
+		}
+		left = &logicalNode{op: "||", left: left, right: right}
+	}
+}
+
+func (p *parser) and() (node, error) {
+	left, err := p.comparison()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		if _, ok := p.accept("&&"); !ok {
+			return left, nil
+		}
+		right, err := p.comparison()
+		if err != nil {
+			return nil, err
+		}
+		left = &logicalNode{op: "&&", left: left, right: right}
+	}
+}
+
+func (p *parser) comparison() (node, error) {
+	left, err := p.unary()
+	if err != nil {
+		return nil, err
+	}
+	op, ok := p.accept("==", "!=", "<=", ">=", "<", ">", "=~", "!~")
+	if !ok {
+		return left, nil
+	}
+	right, err := p.unary()
+	if err != nil {
+		return nil, err
+	}
+	return &compareNode{op: op, left: left, right: right}, nil
+}
+
+func (p *parser) unary() (node, error) {
+	if _, ok := p.accept("!"); ok {
+		n, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+		return &notNode{n}, nil
+	}
+	return p.primary()
+}
+
+func (p *parser) primary() (node, error) {
+	t := p.next()
+	switch t.kind {
+	case tokString:
+		return &literalNode{t.text}, nil
+
+	case tokNumber:
+		f, err := strconv.ParseFloat(t.text, 64)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid number %q at position %d", t.text, t.pos)
+		}
+		return &literalNode{f}, nil
+
+	case tokLParen:
+		n, err := p.or()
+		if err != nil {
+			return nil, err
+		}
+		if t := p.next(); t.kind != tokRParen {
+			return nil, fmt.Errorf("Expected ) at position %d", t.pos)
+		}
+		return n, nil
+
+	case tokIdent:
+		switch t.text {
+		case "true":
+			return &literalNode{true}, nil
+		case "false":
+			return &literalNode{false}, nil
+		case "null", "nil":
+			return &literalNode{nil}, nil
+		}
+		if p.peek().kind != tokLParen {
+			return &identNode{path: strings.Split(t.text, ".")}, nil
+		}
+		return p.call(t)
+	}
+
+	if t.kind == tokEOF {
+		return nil, fmt.Errorf("Unexpected end of expression")
+	}
+	return nil, fmt.Errorf("Unexpected %q at position %d", t.text, t.pos)
+}
+
+// call parses the arguments of a function call.
+func (p *parser) call(name token) (node, error) {
+	f, ok := functions[name.text]
+	if !ok {
+		return nil, fmt.Errorf("Unknown function %s at position %d", name.text, name.pos)
+	}
+	p.next()
+
+	var args []node
+	if p.peek().kind != tokRParen {
+		for {
+			arg, err := p.or()
+			if err != nil {
+				return nil, err
+			}
+			args = append(args, arg)
+			if p.peek().kind != tokComma {
+				break
+			}
+			p.next()
+		}
+	}
+	if t := p.next(); t.kind != tokRParen {
+		return nil, fmt.Errorf("Expected ) at position %d", t.pos)
+	}
+	if len(args) != f.args {
+		return nil, fmt.Errorf("Function %s expects %d arguments, got %d", name.text, f.args, len(args))
+	}
+
+	return &callNode{name: name.text, fn: f.fn, args: args}, nil
+}
@@ -21,7 +21,15 @@
 // Package filters contains Beehive's filter system.
 package filters
 
-// FilterInterface is an interface all Filters implement.
+import (
+	"sort"
+	"sync"
+)
+
+// FilterInterface is an interface all Filters implement. Filters get
+// registered with RegisterFilter, usually from an init function, and chains
+// select them by prefixing their filter expressions with the filter's name,
+// e.g. "expr: channel == \"#ops\"".
 type FilterInterface interface {
 	// Name of the filter
 	Name() string
@@ -32,18 +40,44 @@ type FilterInterface interface {
 	Passes(data map[string]interface{}, value string) bool
 }
 
+// FallibleFilter is implemented by filters that can fail, e.g. because they
+// rely on an external service. When it's implemented, PassesE gets used
+// instead of Passes and a returned error fails the chain like a panicking
+// filter does.
+type FallibleFilter interface {
+	FilterInterface
+
+	PassesE(data map[string]interface{}, value string) (bool, error)
+}
+
+// ValidatingFilter is implemented by filters that can check their filter
+// expressions up front, e.g. for syntax errors. Chains using an invalid
+// expression get reported when they are validated.
+type ValidatingFilter interface {
+	FilterInterface
+
+	Validate(value string) error
+}
+
 var (
-	filters = make(map[string]*FilterInterface)
+	filters     = make(map[string]*FilterInterface)
+	filtersLock sync.RWMutex
 )
 
-// RegisterFilter gets called by Filters to register themselves.
+// RegisterFilter gets called by Filters to register themselves. A filter
+// replaces any filter previously registered with the same name.
 func RegisterFilter(filter FilterInterface) {
-	// log.Println("Filter bee ready:", filter.Name(), "-", filter.Description())
+	filtersLock.Lock()
+	defer filtersLock.Unlock()
+
 	filters[filter.Name()] = &filter
 }
 
 // GetFilter returns a filter with a specific name
 func GetFilter(identifier string) *FilterInterface {
+	filtersLock.RLock()
+	defer filtersLock.RUnlock()
+
 	filter, ok := filters[identifier]
 	if ok {
 		return filter
@@ -51,3 +85,19 @@ func GetFilter(identifier string) *FilterInterface {
 
 	return nil
 }
+
+// GetFilters returns all registered filters, sorted by name
+func GetFilters() []*FilterInterface {
+	filtersLock.RLock()
+	r := make([]*FilterInterface, 0, len(filters))
+	for _, filter := range filters {
+		r = append(r, filter)
+	}
+	filtersLock.RUnlock()
+
+	sort.Slice(r, func(i, j int) bool {
+		return (*r[i]).Name() < (*r[j]).Name()
+	})
+
+	return r
+}
@@ -21,6 +21,7 @@
 package main
 
 import (
+	_ "github.com/muesli/beehive/bees/alertmanagerbee"
 	_ "github.com/muesli/beehive/bees/alertoverbee"
 	_ "github.com/muesli/beehive/bees/anelpowerctrlbee"
 	_ "github.com/muesli/beehive/bees/cfddnsbee"
@@ -38,6 +39,7 @@ import (
 	_ "github.com/muesli/beehive/bees/githubbee"
 	_ "github.com/muesli/beehive/bees/gitterbee"
 	_ "github.com/muesli/beehive/bees/gotifybee"
+	_ "github.com/muesli/beehive/bees/homeassistantbee"
 	_ "github.com/muesli/beehive/bees/horizonboxbee"
 	_ "github.com/muesli/beehive/bees/htmlextractbee"
 	_ "github.com/muesli/beehive/bees/httpbee"
@@ -48,8 +50,10 @@ import (
 	_ "github.com/muesli/beehive/bees/jabberbee"
 	_ "github.com/muesli/beehive/bees/jenkinsbee"
 	_ "github.com/muesli/beehive/bees/jirabee"
+	_ "github.com/muesli/beehive/bees/logwatchbee"
 	_ "github.com/muesli/beehive/bees/mastodonbee"
 	_ "github.com/muesli/beehive/bees/mixcloudbee"
+	_ "github.com/muesli/beehive/bees/mqttbee"
 	_ "github.com/muesli/beehive/bees/mumblebee"
 	_ "github.com/muesli/beehive/bees/nagiosbee"
 	_ "github.com/muesli/beehive/bees/openweathermapbee"
@@ -73,5 +77,7 @@ import (
 	_ "github.com/muesli/beehive/bees/twiliobee"
 	_ "github.com/muesli/beehive/bees/twitchbee"
 	_ "github.com/muesli/beehive/bees/twitterbee"
+	_ "github.com/muesli/beehive/bees/variablesbee"
 	_ "github.com/muesli/beehive/bees/webbee"
+	_ "github.com/muesli/beehive/bees/webhookbee"
 )
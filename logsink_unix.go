@@ -0,0 +1,147 @@
+//go:build !windows && !plan9
+// +build !windows,!plan9
+
+/*
+ *    Copyright (C) 2026 Christian Muehlhaeuser
+ *
+ *    This program is free software: you can redistribute it and/or modify
+ *    it under the terms of the GNU Affero General Public License as published
+ *    by the Free Software Foundation, either version 3 of the License, or
+ *    (at your option) any later version.
+ *
+ *    This program is distributed in the hope that it will be useful,
+ *    but WITHOUT ANY WARRANTY; without even the implied warranty of
+ *    MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ *    GNU Affero General Public License for more details.
+ *
+ *    You should have received a copy of the GNU Affero General Public License
+ *    along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ *    Authors:
+ *      Christian Muehlhaeuser <muesli@gmail.com>
+ */
+
+package main
+
+import (
+	"fmt"
+	"log/syslog"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/coreos/go-systemd/journal"
+	log "github.com/sirupsen/logrus"
+)
+
+// newLogSinkHook returns a hook forwarding log messages to sink: "syslog"
+// for the local syslog daemon, "syslog://host:port" for a remote one
+// (over UDP) or "journald" for the systemd journal.
+func newLogSinkHook(sink string) (log.Hook, error) {
+	if sink == "journald" {
+		if !journal.Enabled() {
+			return nil, fmt.Errorf("The systemd journal isn't available")
+		}
+		return &journalHook{}, nil
+	}
+
+	network, addr := "", ""
+	if sink != "syslog" {
+		u, err := url.Parse(sink)
+		if err != nil || u.Scheme != "syslog" || len(u.Host) == 0 {
+			return nil, fmt.Errorf("Unknown log sink %s", sink)
+		}
+		network, addr = "udp", u.Host
+	}
+
+	w, err := syslog.Dial(network, addr, syslog.LOG_INFO|syslog.LOG_DAEMON, "beehive")
+	if err != nil {
+		return nil, err
+	}
+	return &syslogHook{w: w}, nil
+}
+
+// sinkMessage returns an entry's message followed by its fields as sorted
+// key=value pairs. Sinks add their own timestamps and levels.
+func sinkMessage(entry *log.Entry) string {
+	keys := make([]string, 0, len(entry.Data))
+	for k := range entry.Data {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	msg := strings.TrimSpace(entry.Message)
+	for _, k := range keys {
+		msg += fmt.Sprintf(" %s=%v", k, entry.Data[k])
+	}
+	return msg
+}
+
+// syslogHook forwards log messages to syslog.
+type syslogHook struct {
+	w *syslog.Writer
+}
+
+func (hook *syslogHook) Levels() []log.Level {
+	return log.AllLevels
+}
+
+func (hook *syslogHook) Fire(entry *log.Entry) error {
+	msg := sinkMessage(entry)
+	switch entry.Level {
+	case log.PanicLevel, log.FatalLevel:
+		return hook.w.Crit(msg)
+	case log.ErrorLevel:
+		return hook.w.Err(msg)
+	case log.WarnLevel:
+		return hook.w.Warning(msg)
+	case log.InfoLevel:
+		return hook.w.Info(msg)
+	}
+	return hook.w.Debug(msg)
+}
+
+// journalHook forwards log messages to the systemd journal, with their
+// fields as journal fields, e.g. a bee's name as BEE.
+type journalHook struct{}
+
+func (hook *journalHook) Levels() []log.Level {
+	return log.AllLevels
+}
+
+func (hook *journalHook) Fire(entry *log.Entry) error {
+	priority := journal.PriDebug
+	switch entry.Level {
+	case log.PanicLevel, log.FatalLevel:
+		priority = journal.PriCrit
+	case log.ErrorLevel:
+		priority = journal.PriErr
+	case log.WarnLevel:
+		priority = journal.PriWarning
+	case log.InfoLevel:
+		priority = journal.PriInfo
+	}
+
+	vars := map[string]string{"SYSLOG_IDENTIFIER": "beehive"}
+	for k, v := range entry.Data {
+		if name := journalField(k); len(name) > 0 {
+			vars[name] = fmt.Sprint(v)
+		}
+	}
+	return journal.Send(strings.TrimSpace(entry.Message), priority, vars)
+}
+
+// journalField turns k into a valid journal field name: upper case letters,
+// digits and underscores, not starting with an underscore.
+func journalField(k string) string {
+	name := strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z':
+			return r - 'a' + 'A'
+		case r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+			return r
+		}
+		return '_'
+	}, k)
+	return strings.TrimLeft(name, "_0123456789")
+}
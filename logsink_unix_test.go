@@ -0,0 +1,88 @@
+//go:build !windows && !plan9
+// +build !windows,!plan9
+
+package main
+
+import (
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/coreos/go-systemd/journal"
+	log "github.com/sirupsen/logrus"
+)
+
+func TestNewLogSinkHook(t *testing.T) {
+	for _, sink := range []string{"file", "syslog://", "http://localhost:514"} {
+		if _, err := newLogSinkHook(sink); err == nil {
+			t.Errorf("Expected an error for log sink %s", sink)
+		}
+	}
+
+	_, err := newLogSinkHook("journald")
+	if journal.Enabled() != (err == nil) {
+		t.Errorf("Expected the journald sink to be available only with a journal, got %v", err)
+	}
+}
+
+func TestSyslogHook(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	hook, err := newLogSinkHook("syslog://" + conn.LocalAddr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tt := range []struct {
+		level    log.Level
+		priority string
+	}{
+		{log.ErrorLevel, "<27>"},
+		{log.WarnLevel, "<28>"},
+		{log.InfoLevel, "<30>"},
+		{log.DebugLevel, "<31>"},
+	} {
+		entry := &log.Entry{
+			Level:   tt.level,
+			Message: "Stopped gracefully!\n",
+			Data:    log.Fields{"namespace": "ircbee", "bee": "irc"},
+		}
+		if err := hook.Fire(entry); err != nil {
+			t.Fatal(err)
+		}
+
+		buf := make([]byte, 1024)
+		conn.SetReadDeadline(time.Now().Add(time.Second))
+		n, _, err := conn.ReadFrom(buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		msg := string(buf[:n])
+		if !strings.HasPrefix(msg, tt.priority) {
+			t.Errorf("Expected %v messages to have priority %s, got %q", tt.level, tt.priority, msg)
+		}
+		if !strings.Contains(msg, "beehive") || !strings.HasSuffix(strings.TrimSpace(msg), "Stopped gracefully! bee=irc namespace=ircbee") {
+			t.Errorf("Expected the message with its sorted fields, got %q", msg)
+		}
+	}
+}
+
+func TestJournalField(t *testing.T) {
+	for k, expected := range map[string]string{
+		"bee":         "BEE",
+		"Namespace":   "NAMESPACE",
+		"action-id":   "ACTION_ID",
+		"_private":    "PRIVATE",
+		"2fa.enabled": "FA_ENABLED",
+		"_1":          "",
+	} {
+		if name := journalField(k); name != expected {
+			t.Errorf("Expected field %s to become %s, got %s", k, expected, name)
+		}
+	}
+}
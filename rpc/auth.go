@@ -0,0 +1,130 @@
+/*
+ *    Copyright (C) 2026 Christian Muehlhaeuser
+ *
+ *    This program is free software: you can redistribute it and/or modify
+ *    it under the terms of the GNU Affero General Public License as published
+ *    by the Free Software Foundation, either version 3 of the License, or
+ *    (at your option) any later version.
+ *
+ *    This program is distributed in the hope that it will be useful,
+ *    but WITHOUT ANY WARRANTY; without even the implied warranty of
+ *    MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ *    GNU Affero General Public License for more details.
+ *
+ *    You should have received a copy of the GNU Affero General Public License
+ *    along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ *    Authors:
+ *      Christian Muehlhaeuser <muesli@gmail.com>
+ */
+
+package rpc
+
+import (
+	"context"
+	"encoding/base64"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	apicontext "github.com/muesli/beehive/api/context"
+)
+
+// methodPrefix is the prefix of the full names of the service's methods.
+const methodPrefix = "/beehive.Beehive/"
+
+// readOnlyMethods are the methods the monitor role may call.
+var readOnlyMethods = map[string]bool{
+	"ListBees":     true,
+	"ListChains":   true,
+	"ListActions":  true,
+	"StreamEvents": true,
+}
+
+type credentialKey struct{}
+
+// credentialOf returns the credential a call got authenticated with, or nil
+// if authentication is disabled.
+func credentialOf(ctx context.Context) *apicontext.Credential {
+	c, _ := ctx.Value(credentialKey{}).(*apicontext.Credential)
+	return c
+}
+
+// authenticate checks the credentials sent along with a call to method, the
+// same ones the HTTP API accepts: either a bearer token or basic auth in the
+// "authorization" metadata. Returns the context to handle the call with.
+func authenticate(ctx context.Context, method string) (context.Context, error) {
+	if !apicontext.AuthEnabled() {
+		return ctx, nil
+	}
+
+	var c *apicontext.Credential
+	md, _ := metadata.FromIncomingContext(ctx)
+	for _, auth := range md.Get("authorization") {
+		if strings.HasPrefix(auth, "Bearer ") {
+			c = apicontext.AuthenticateToken(strings.TrimPrefix(auth, "Bearer "))
+		} else if strings.HasPrefix(auth, "Basic ") {
+			b, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(auth, "Basic "))
+			if err != nil {
+				continue
+			}
+			if i := strings.IndexByte(string(b), ':'); i >= 0 {
+				c = apicontext.AuthenticateUser(string(b[:i]), string(b[i+1:]))
+			}
+		}
+		if c != nil {
+			break
+		}
+	}
+	if c == nil {
+		return nil, status.Error(codes.Unauthenticated, "Authentication required")
+	}
+	if !allows(c, strings.TrimPrefix(method, methodPrefix)) {
+		return nil, status.Error(codes.PermissionDenied, "Access denied")
+	}
+
+	return context.WithValue(ctx, credentialKey{}, c), nil
+}
+
+// allows returns whether a credential may call a method, mirroring
+// Credential.Allows.
+func allows(c *apicontext.Credential, method string) bool {
+	switch c.Role {
+	case apicontext.RoleAdmin:
+		return true
+	case apicontext.RoleMonitor:
+		return readOnlyMethods[method]
+	case apicontext.RoleInject:
+		return method == "InjectEvent"
+	}
+	return false
+}
+
+func unaryAuthInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	ctx, err := authenticate(ctx, info.FullMethod)
+	if err != nil {
+		return nil, err
+	}
+	return handler(ctx, req)
+}
+
+func streamAuthInterceptor(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	ctx, err := authenticate(ss.Context(), info.FullMethod)
+	if err != nil {
+		return err
+	}
+	return handler(srv, &authenticatedStream{ServerStream: ss, ctx: ctx})
+}
+
+// authenticatedStream carries the credential of a stream in its context.
+type authenticatedStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *authenticatedStream) Context() context.Context {
+	return s.ctx
+}
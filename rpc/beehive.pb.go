@@ -0,0 +1,1725 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// source: rpc/beehive.proto
+
+package rpc
+
+import (
+	context "context"
+	fmt "fmt"
+	proto "github.com/golang/protobuf/proto"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+	math "math"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
+
+// Placeholder is a named value of an event, an action or a bee's options.
+type Placeholder struct {
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Type string `protobuf:"bytes,2,opt,name=type,proto3" json:"type,omitempty"`
+	// value is JSON encoded
+	Value                string   `protobuf:"bytes,3,opt,name=value,proto3" json:"value,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Placeholder) Reset()         { *m = Placeholder{} }
+func (m *Placeholder) String() string { return proto.CompactTextString(m) }
+func (*Placeholder) ProtoMessage()    {}
+func (*Placeholder) Descriptor() ([]byte, []int) {
+	return fileDescriptor_a6e085f7dfa34cd8, []int{0}
+}
+
+func (m *Placeholder) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Placeholder.Unmarshal(m, b)
+}
+func (m *Placeholder) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Placeholder.Marshal(b, m, deterministic)
+}
+func (m *Placeholder) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Placeholder.Merge(m, src)
+}
+func (m *Placeholder) XXX_Size() int {
+	return xxx_messageInfo_Placeholder.Size(m)
+}
+func (m *Placeholder) XXX_DiscardUnknown() {
+	xxx_messageInfo_Placeholder.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Placeholder proto.InternalMessageInfo
+
+func (m *Placeholder) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *Placeholder) GetType() string {
+	if m != nil {
+		return m.Type
+	}
+	return ""
+}
+
+func (m *Placeholder) GetValue() string {
+	if m != nil {
+		return m.Value
+	}
+	return ""
+}
+
+type Bee struct {
+	Name                 string         `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Class                string         `protobuf:"bytes,2,opt,name=class,proto3" json:"class,omitempty"`
+	Description          string         `protobuf:"bytes,3,opt,name=description,proto3" json:"description,omitempty"`
+	Options              []*Placeholder `protobuf:"bytes,4,rep,name=options,proto3" json:"options,omitempty"`
+	Paused               bool           `protobuf:"varint,5,opt,name=paused,proto3" json:"paused,omitempty"`
+	Running              bool           `protobuf:"varint,6,opt,name=running,proto3" json:"running,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}       `json:"-"`
+	XXX_unrecognized     []byte         `json:"-"`
+	XXX_sizecache        int32          `json:"-"`
+}
+
+func (m *Bee) Reset()         { *m = Bee{} }
+func (m *Bee) String() string { return proto.CompactTextString(m) }
+func (*Bee) ProtoMessage()    {}
+func (*Bee) Descriptor() ([]byte, []int) {
+	return fileDescriptor_a6e085f7dfa34cd8, []int{1}
+}
+
+func (m *Bee) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Bee.Unmarshal(m, b)
+}
+func (m *Bee) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Bee.Marshal(b, m, deterministic)
+}
+func (m *Bee) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Bee.Merge(m, src)
+}
+func (m *Bee) XXX_Size() int {
+	return xxx_messageInfo_Bee.Size(m)
+}
+func (m *Bee) XXX_DiscardUnknown() {
+	xxx_messageInfo_Bee.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Bee proto.InternalMessageInfo
+
+func (m *Bee) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *Bee) GetClass() string {
+	if m != nil {
+		return m.Class
+	}
+	return ""
+}
+
+func (m *Bee) GetDescription() string {
+	if m != nil {
+		return m.Description
+	}
+	return ""
+}
+
+func (m *Bee) GetOptions() []*Placeholder {
+	if m != nil {
+		return m.Options
+	}
+	return nil
+}
+
+func (m *Bee) GetPaused() bool {
+	if m != nil {
+		return m.Paused
+	}
+	return false
+}
+
+func (m *Bee) GetRunning() bool {
+	if m != nil {
+		return m.Running
+	}
+	return false
+}
+
+// Chain carries a chain's definition as JSON, in the same format as the
+// chains of Beehive's configuration file.
+type Chain struct {
+	Name                 string   `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Definition           []byte   `protobuf:"bytes,2,opt,name=definition,proto3" json:"definition,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Chain) Reset()         { *m = Chain{} }
+func (m *Chain) String() string { return proto.CompactTextString(m) }
+func (*Chain) ProtoMessage()    {}
+func (*Chain) Descriptor() ([]byte, []int) {
+	return fileDescriptor_a6e085f7dfa34cd8, []int{2}
+}
+
+func (m *Chain) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Chain.Unmarshal(m, b)
+}
+func (m *Chain) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Chain.Marshal(b, m, deterministic)
+}
+func (m *Chain) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Chain.Merge(m, src)
+}
+func (m *Chain) XXX_Size() int {
+	return xxx_messageInfo_Chain.Size(m)
+}
+func (m *Chain) XXX_DiscardUnknown() {
+	xxx_messageInfo_Chain.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Chain proto.InternalMessageInfo
+
+func (m *Chain) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *Chain) GetDefinition() []byte {
+	if m != nil {
+		return m.Definition
+	}
+	return nil
+}
+
+type Action struct {
+	Id                   string         `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Bee                  string         `protobuf:"bytes,2,opt,name=bee,proto3" json:"bee,omitempty"`
+	Name                 string         `protobuf:"bytes,3,opt,name=name,proto3" json:"name,omitempty"`
+	Options              []*Placeholder `protobuf:"bytes,4,rep,name=options,proto3" json:"options,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}       `json:"-"`
+	XXX_unrecognized     []byte         `json:"-"`
+	XXX_sizecache        int32          `json:"-"`
+}
+
+func (m *Action) Reset()         { *m = Action{} }
+func (m *Action) String() string { return proto.CompactTextString(m) }
+func (*Action) ProtoMessage()    {}
+func (*Action) Descriptor() ([]byte, []int) {
+	return fileDescriptor_a6e085f7dfa34cd8, []int{3}
+}
+
+func (m *Action) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Action.Unmarshal(m, b)
+}
+func (m *Action) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Action.Marshal(b, m, deterministic)
+}
+func (m *Action) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Action.Merge(m, src)
+}
+func (m *Action) XXX_Size() int {
+	return xxx_messageInfo_Action.Size(m)
+}
+func (m *Action) XXX_DiscardUnknown() {
+	xxx_messageInfo_Action.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Action proto.InternalMessageInfo
+
+func (m *Action) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *Action) GetBee() string {
+	if m != nil {
+		return m.Bee
+	}
+	return ""
+}
+
+func (m *Action) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *Action) GetOptions() []*Placeholder {
+	if m != nil {
+		return m.Options
+	}
+	return nil
+}
+
+type Event struct {
+	Id                   string         `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Bee                  string         `protobuf:"bytes,2,opt,name=bee,proto3" json:"bee,omitempty"`
+	Name                 string         `protobuf:"bytes,3,opt,name=name,proto3" json:"name,omitempty"`
+	Options              []*Placeholder `protobuf:"bytes,4,rep,name=options,proto3" json:"options,omitempty"`
+	CorrelationId        string         `protobuf:"bytes,5,opt,name=correlation_id,json=correlationId,proto3" json:"correlation_id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}       `json:"-"`
+	XXX_unrecognized     []byte         `json:"-"`
+	XXX_sizecache        int32          `json:"-"`
+}
+
+func (m *Event) Reset()         { *m = Event{} }
+func (m *Event) String() string { return proto.CompactTextString(m) }
+func (*Event) ProtoMessage()    {}
+func (*Event) Descriptor() ([]byte, []int) {
+	return fileDescriptor_a6e085f7dfa34cd8, []int{4}
+}
+
+func (m *Event) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Event.Unmarshal(m, b)
+}
+func (m *Event) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Event.Marshal(b, m, deterministic)
+}
+func (m *Event) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Event.Merge(m, src)
+}
+func (m *Event) XXX_Size() int {
+	return xxx_messageInfo_Event.Size(m)
+}
+func (m *Event) XXX_DiscardUnknown() {
+	xxx_messageInfo_Event.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Event proto.InternalMessageInfo
+
+func (m *Event) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *Event) GetBee() string {
+	if m != nil {
+		return m.Bee
+	}
+	return ""
+}
+
+func (m *Event) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *Event) GetOptions() []*Placeholder {
+	if m != nil {
+		return m.Options
+	}
+	return nil
+}
+
+func (m *Event) GetCorrelationId() string {
+	if m != nil {
+		return m.CorrelationId
+	}
+	return ""
+}
+
+type ListBeesRequest struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ListBeesRequest) Reset()         { *m = ListBeesRequest{} }
+func (m *ListBeesRequest) String() string { return proto.CompactTextString(m) }
+func (*ListBeesRequest) ProtoMessage()    {}
+func (*ListBeesRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_a6e085f7dfa34cd8, []int{5}
+}
+
+func (m *ListBeesRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListBeesRequest.Unmarshal(m, b)
+}
+func (m *ListBeesRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListBeesRequest.Marshal(b, m, deterministic)
+}
+func (m *ListBeesRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListBeesRequest.Merge(m, src)
+}
+func (m *ListBeesRequest) XXX_Size() int {
+	return xxx_messageInfo_ListBeesRequest.Size(m)
+}
+func (m *ListBeesRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListBeesRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListBeesRequest proto.InternalMessageInfo
+
+type ListBeesResponse struct {
+	Bees                 []*Bee   `protobuf:"bytes,1,rep,name=bees,proto3" json:"bees,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ListBeesResponse) Reset()         { *m = ListBeesResponse{} }
+func (m *ListBeesResponse) String() string { return proto.CompactTextString(m) }
+func (*ListBeesResponse) ProtoMessage()    {}
+func (*ListBeesResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_a6e085f7dfa34cd8, []int{6}
+}
+
+func (m *ListBeesResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListBeesResponse.Unmarshal(m, b)
+}
+func (m *ListBeesResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListBeesResponse.Marshal(b, m, deterministic)
+}
+func (m *ListBeesResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListBeesResponse.Merge(m, src)
+}
+func (m *ListBeesResponse) XXX_Size() int {
+	return xxx_messageInfo_ListBeesResponse.Size(m)
+}
+func (m *ListBeesResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListBeesResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListBeesResponse proto.InternalMessageInfo
+
+func (m *ListBeesResponse) GetBees() []*Bee {
+	if m != nil {
+		return m.Bees
+	}
+	return nil
+}
+
+type CreateBeeRequest struct {
+	Bee                  *Bee     `protobuf:"bytes,1,opt,name=bee,proto3" json:"bee,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *CreateBeeRequest) Reset()         { *m = CreateBeeRequest{} }
+func (m *CreateBeeRequest) String() string { return proto.CompactTextString(m) }
+func (*CreateBeeRequest) ProtoMessage()    {}
+func (*CreateBeeRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_a6e085f7dfa34cd8, []int{7}
+}
+
+func (m *CreateBeeRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CreateBeeRequest.Unmarshal(m, b)
+}
+func (m *CreateBeeRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CreateBeeRequest.Marshal(b, m, deterministic)
+}
+func (m *CreateBeeRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CreateBeeRequest.Merge(m, src)
+}
+func (m *CreateBeeRequest) XXX_Size() int {
+	return xxx_messageInfo_CreateBeeRequest.Size(m)
+}
+func (m *CreateBeeRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_CreateBeeRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CreateBeeRequest proto.InternalMessageInfo
+
+func (m *CreateBeeRequest) GetBee() *Bee {
+	if m != nil {
+		return m.Bee
+	}
+	return nil
+}
+
+type DeleteBeeRequest struct {
+	Name                 string   `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *DeleteBeeRequest) Reset()         { *m = DeleteBeeRequest{} }
+func (m *DeleteBeeRequest) String() string { return proto.CompactTextString(m) }
+func (*DeleteBeeRequest) ProtoMessage()    {}
+func (*DeleteBeeRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_a6e085f7dfa34cd8, []int{8}
+}
+
+func (m *DeleteBeeRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DeleteBeeRequest.Unmarshal(m, b)
+}
+func (m *DeleteBeeRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DeleteBeeRequest.Marshal(b, m, deterministic)
+}
+func (m *DeleteBeeRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DeleteBeeRequest.Merge(m, src)
+}
+func (m *DeleteBeeRequest) XXX_Size() int {
+	return xxx_messageInfo_DeleteBeeRequest.Size(m)
+}
+func (m *DeleteBeeRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_DeleteBeeRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DeleteBeeRequest proto.InternalMessageInfo
+
+func (m *DeleteBeeRequest) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+type DeleteBeeResponse struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *DeleteBeeResponse) Reset()         { *m = DeleteBeeResponse{} }
+func (m *DeleteBeeResponse) String() string { return proto.CompactTextString(m) }
+func (*DeleteBeeResponse) ProtoMessage()    {}
+func (*DeleteBeeResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_a6e085f7dfa34cd8, []int{9}
+}
+
+func (m *DeleteBeeResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DeleteBeeResponse.Unmarshal(m, b)
+}
+func (m *DeleteBeeResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DeleteBeeResponse.Marshal(b, m, deterministic)
+}
+func (m *DeleteBeeResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DeleteBeeResponse.Merge(m, src)
+}
+func (m *DeleteBeeResponse) XXX_Size() int {
+	return xxx_messageInfo_DeleteBeeResponse.Size(m)
+}
+func (m *DeleteBeeResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_DeleteBeeResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DeleteBeeResponse proto.InternalMessageInfo
+
+type PauseBeeRequest struct {
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// paused resumes the bee when false
+	Paused               bool     `protobuf:"varint,2,opt,name=paused,proto3" json:"paused,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *PauseBeeRequest) Reset()         { *m = PauseBeeRequest{} }
+func (m *PauseBeeRequest) String() string { return proto.CompactTextString(m) }
+func (*PauseBeeRequest) ProtoMessage()    {}
+func (*PauseBeeRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_a6e085f7dfa34cd8, []int{10}
+}
+
+func (m *PauseBeeRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PauseBeeRequest.Unmarshal(m, b)
+}
+func (m *PauseBeeRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PauseBeeRequest.Marshal(b, m, deterministic)
+}
+func (m *PauseBeeRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PauseBeeRequest.Merge(m, src)
+}
+func (m *PauseBeeRequest) XXX_Size() int {
+	return xxx_messageInfo_PauseBeeRequest.Size(m)
+}
+func (m *PauseBeeRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_PauseBeeRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PauseBeeRequest proto.InternalMessageInfo
+
+func (m *PauseBeeRequest) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *PauseBeeRequest) GetPaused() bool {
+	if m != nil {
+		return m.Paused
+	}
+	return false
+}
+
+type ListChainsRequest struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ListChainsRequest) Reset()         { *m = ListChainsRequest{} }
+func (m *ListChainsRequest) String() string { return proto.CompactTextString(m) }
+func (*ListChainsRequest) ProtoMessage()    {}
+func (*ListChainsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_a6e085f7dfa34cd8, []int{11}
+}
+
+func (m *ListChainsRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListChainsRequest.Unmarshal(m, b)
+}
+func (m *ListChainsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListChainsRequest.Marshal(b, m, deterministic)
+}
+func (m *ListChainsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListChainsRequest.Merge(m, src)
+}
+func (m *ListChainsRequest) XXX_Size() int {
+	return xxx_messageInfo_ListChainsRequest.Size(m)
+}
+func (m *ListChainsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListChainsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListChainsRequest proto.InternalMessageInfo
+
+type ListChainsResponse struct {
+	Chains               []*Chain `protobuf:"bytes,1,rep,name=chains,proto3" json:"chains,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ListChainsResponse) Reset()         { *m = ListChainsResponse{} }
+func (m *ListChainsResponse) String() string { return proto.CompactTextString(m) }
+func (*ListChainsResponse) ProtoMessage()    {}
+func (*ListChainsResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_a6e085f7dfa34cd8, []int{12}
+}
+
+func (m *ListChainsResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListChainsResponse.Unmarshal(m, b)
+}
+func (m *ListChainsResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListChainsResponse.Marshal(b, m, deterministic)
+}
+func (m *ListChainsResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListChainsResponse.Merge(m, src)
+}
+func (m *ListChainsResponse) XXX_Size() int {
+	return xxx_messageInfo_ListChainsResponse.Size(m)
+}
+func (m *ListChainsResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListChainsResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListChainsResponse proto.InternalMessageInfo
+
+func (m *ListChainsResponse) GetChains() []*Chain {
+	if m != nil {
+		return m.Chains
+	}
+	return nil
+}
+
+type PutChainRequest struct {
+	Chain                *Chain   `protobuf:"bytes,1,opt,name=chain,proto3" json:"chain,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *PutChainRequest) Reset()         { *m = PutChainRequest{} }
+func (m *PutChainRequest) String() string { return proto.CompactTextString(m) }
+func (*PutChainRequest) ProtoMessage()    {}
+func (*PutChainRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_a6e085f7dfa34cd8, []int{13}
+}
+
+func (m *PutChainRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PutChainRequest.Unmarshal(m, b)
+}
+func (m *PutChainRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PutChainRequest.Marshal(b, m, deterministic)
+}
+func (m *PutChainRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PutChainRequest.Merge(m, src)
+}
+func (m *PutChainRequest) XXX_Size() int {
+	return xxx_messageInfo_PutChainRequest.Size(m)
+}
+func (m *PutChainRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_PutChainRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PutChainRequest proto.InternalMessageInfo
+
+func (m *PutChainRequest) GetChain() *Chain {
+	if m != nil {
+		return m.Chain
+	}
+	return nil
+}
+
+type DeleteChainRequest struct {
+	Name                 string   `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *DeleteChainRequest) Reset()         { *m = DeleteChainRequest{} }
+func (m *DeleteChainRequest) String() string { return proto.CompactTextString(m) }
+func (*DeleteChainRequest) ProtoMessage()    {}
+func (*DeleteChainRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_a6e085f7dfa34cd8, []int{14}
+}
+
+func (m *DeleteChainRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DeleteChainRequest.Unmarshal(m, b)
+}
+func (m *DeleteChainRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DeleteChainRequest.Marshal(b, m, deterministic)
+}
+func (m *DeleteChainRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DeleteChainRequest.Merge(m, src)
+}
+func (m *DeleteChainRequest) XXX_Size() int {
+	return xxx_messageInfo_DeleteChainRequest.Size(m)
+}
+func (m *DeleteChainRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_DeleteChainRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DeleteChainRequest proto.InternalMessageInfo
+
+func (m *DeleteChainRequest) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+type DeleteChainResponse struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *DeleteChainResponse) Reset()         { *m = DeleteChainResponse{} }
+func (m *DeleteChainResponse) String() string { return proto.CompactTextString(m) }
+func (*DeleteChainResponse) ProtoMessage()    {}
+func (*DeleteChainResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_a6e085f7dfa34cd8, []int{15}
+}
+
+func (m *DeleteChainResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DeleteChainResponse.Unmarshal(m, b)
+}
+func (m *DeleteChainResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DeleteChainResponse.Marshal(b, m, deterministic)
+}
+func (m *DeleteChainResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DeleteChainResponse.Merge(m, src)
+}
+func (m *DeleteChainResponse) XXX_Size() int {
+	return xxx_messageInfo_DeleteChainResponse.Size(m)
+}
+func (m *DeleteChainResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_DeleteChainResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DeleteChainResponse proto.InternalMessageInfo
+
+type ListActionsRequest struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ListActionsRequest) Reset()         { *m = ListActionsRequest{} }
+func (m *ListActionsRequest) String() string { return proto.CompactTextString(m) }
+func (*ListActionsRequest) ProtoMessage()    {}
+func (*ListActionsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_a6e085f7dfa34cd8, []int{16}
+}
+
+func (m *ListActionsRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListActionsRequest.Unmarshal(m, b)
+}
+func (m *ListActionsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListActionsRequest.Marshal(b, m, deterministic)
+}
+func (m *ListActionsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListActionsRequest.Merge(m, src)
+}
+func (m *ListActionsRequest) XXX_Size() int {
+	return xxx_messageInfo_ListActionsRequest.Size(m)
+}
+func (m *ListActionsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListActionsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListActionsRequest proto.InternalMessageInfo
+
+type ListActionsResponse struct {
+	Actions              []*Action `protobuf:"bytes,1,rep,name=actions,proto3" json:"actions,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}  `json:"-"`
+	XXX_unrecognized     []byte    `json:"-"`
+	XXX_sizecache        int32     `json:"-"`
+}
+
+func (m *ListActionsResponse) Reset()         { *m = ListActionsResponse{} }
+func (m *ListActionsResponse) String() string { return proto.CompactTextString(m) }
+func (*ListActionsResponse) ProtoMessage()    {}
+func (*ListActionsResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_a6e085f7dfa34cd8, []int{17}
+}
+
+func (m *ListActionsResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListActionsResponse.Unmarshal(m, b)
+}
+func (m *ListActionsResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListActionsResponse.Marshal(b, m, deterministic)
+}
+func (m *ListActionsResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListActionsResponse.Merge(m, src)
+}
+func (m *ListActionsResponse) XXX_Size() int {
+	return xxx_messageInfo_ListActionsResponse.Size(m)
+}
+func (m *ListActionsResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListActionsResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListActionsResponse proto.InternalMessageInfo
+
+func (m *ListActionsResponse) GetActions() []*Action {
+	if m != nil {
+		return m.Actions
+	}
+	return nil
+}
+
+type PutActionRequest struct {
+	Action               *Action  `protobuf:"bytes,1,opt,name=action,proto3" json:"action,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *PutActionRequest) Reset()         { *m = PutActionRequest{} }
+func (m *PutActionRequest) String() string { return proto.CompactTextString(m) }
+func (*PutActionRequest) ProtoMessage()    {}
+func (*PutActionRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_a6e085f7dfa34cd8, []int{18}
+}
+
+func (m *PutActionRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PutActionRequest.Unmarshal(m, b)
+}
+func (m *PutActionRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PutActionRequest.Marshal(b, m, deterministic)
+}
+func (m *PutActionRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PutActionRequest.Merge(m, src)
+}
+func (m *PutActionRequest) XXX_Size() int {
+	return xxx_messageInfo_PutActionRequest.Size(m)
+}
+func (m *PutActionRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_PutActionRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PutActionRequest proto.InternalMessageInfo
+
+func (m *PutActionRequest) GetAction() *Action {
+	if m != nil {
+		return m.Action
+	}
+	return nil
+}
+
+type ExecuteActionRequest struct {
+	Action               *Action  `protobuf:"bytes,1,opt,name=action,proto3" json:"action,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ExecuteActionRequest) Reset()         { *m = ExecuteActionRequest{} }
+func (m *ExecuteActionRequest) String() string { return proto.CompactTextString(m) }
+func (*ExecuteActionRequest) ProtoMessage()    {}
+func (*ExecuteActionRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_a6e085f7dfa34cd8, []int{19}
+}
+
+func (m *ExecuteActionRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ExecuteActionRequest.Unmarshal(m, b)
+}
+func (m *ExecuteActionRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ExecuteActionRequest.Marshal(b, m, deterministic)
+}
+func (m *ExecuteActionRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ExecuteActionRequest.Merge(m, src)
+}
+func (m *ExecuteActionRequest) XXX_Size() int {
+	return xxx_messageInfo_ExecuteActionRequest.Size(m)
+}
+func (m *ExecuteActionRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ExecuteActionRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ExecuteActionRequest proto.InternalMessageInfo
+
+func (m *ExecuteActionRequest) GetAction() *Action {
+	if m != nil {
+		return m.Action
+	}
+	return nil
+}
+
+type ExecuteActionResponse struct {
+	Results              []*Placeholder `protobuf:"bytes,1,rep,name=results,proto3" json:"results,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}       `json:"-"`
+	XXX_unrecognized     []byte         `json:"-"`
+	XXX_sizecache        int32          `json:"-"`
+}
+
+func (m *ExecuteActionResponse) Reset()         { *m = ExecuteActionResponse{} }
+func (m *ExecuteActionResponse) String() string { return proto.CompactTextString(m) }
+func (*ExecuteActionResponse) ProtoMessage()    {}
+func (*ExecuteActionResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_a6e085f7dfa34cd8, []int{20}
+}
+
+func (m *ExecuteActionResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ExecuteActionResponse.Unmarshal(m, b)
+}
+func (m *ExecuteActionResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ExecuteActionResponse.Marshal(b, m, deterministic)
+}
+func (m *ExecuteActionResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ExecuteActionResponse.Merge(m, src)
+}
+func (m *ExecuteActionResponse) XXX_Size() int {
+	return xxx_messageInfo_ExecuteActionResponse.Size(m)
+}
+func (m *ExecuteActionResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ExecuteActionResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ExecuteActionResponse proto.InternalMessageInfo
+
+func (m *ExecuteActionResponse) GetResults() []*Placeholder {
+	if m != nil {
+		return m.Results
+	}
+	return nil
+}
+
+type StreamEventsRequest struct {
+	// pattern selects events like bees.Watch, e.g. "ircbee/*". Defaults to
+	// all events.
+	Pattern              string   `protobuf:"bytes,1,opt,name=pattern,proto3" json:"pattern,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *StreamEventsRequest) Reset()         { *m = StreamEventsRequest{} }
+func (m *StreamEventsRequest) String() string { return proto.CompactTextString(m) }
+func (*StreamEventsRequest) ProtoMessage()    {}
+func (*StreamEventsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_a6e085f7dfa34cd8, []int{21}
+}
+
+func (m *StreamEventsRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_StreamEventsRequest.Unmarshal(m, b)
+}
+func (m *StreamEventsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_StreamEventsRequest.Marshal(b, m, deterministic)
+}
+func (m *StreamEventsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_StreamEventsRequest.Merge(m, src)
+}
+func (m *StreamEventsRequest) XXX_Size() int {
+	return xxx_messageInfo_StreamEventsRequest.Size(m)
+}
+func (m *StreamEventsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_StreamEventsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_StreamEventsRequest proto.InternalMessageInfo
+
+func (m *StreamEventsRequest) GetPattern() string {
+	if m != nil {
+		return m.Pattern
+	}
+	return ""
+}
+
+type InjectEventRequest struct {
+	Event *Event `protobuf:"bytes,1,opt,name=event,proto3" json:"event,omitempty"`
+	// dry_run only renders the actions the event would trigger
+	DryRun               bool     `protobuf:"varint,2,opt,name=dry_run,json=dryRun,proto3" json:"dry_run,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *InjectEventRequest) Reset()         { *m = InjectEventRequest{} }
+func (m *InjectEventRequest) String() string { return proto.CompactTextString(m) }
+func (*InjectEventRequest) ProtoMessage()    {}
+func (*InjectEventRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_a6e085f7dfa34cd8, []int{22}
+}
+
+func (m *InjectEventRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_InjectEventRequest.Unmarshal(m, b)
+}
+func (m *InjectEventRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_InjectEventRequest.Marshal(b, m, deterministic)
+}
+func (m *InjectEventRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_InjectEventRequest.Merge(m, src)
+}
+func (m *InjectEventRequest) XXX_Size() int {
+	return xxx_messageInfo_InjectEventRequest.Size(m)
+}
+func (m *InjectEventRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_InjectEventRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_InjectEventRequest proto.InternalMessageInfo
+
+func (m *InjectEventRequest) GetEvent() *Event {
+	if m != nil {
+		return m.Event
+	}
+	return nil
+}
+
+func (m *InjectEventRequest) GetDryRun() bool {
+	if m != nil {
+		return m.DryRun
+	}
+	return false
+}
+
+type DryRunAction struct {
+	Chain                string   `protobuf:"bytes,1,opt,name=chain,proto3" json:"chain,omitempty"`
+	Action               *Action  `protobuf:"bytes,2,opt,name=action,proto3" json:"action,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *DryRunAction) Reset()         { *m = DryRunAction{} }
+func (m *DryRunAction) String() string { return proto.CompactTextString(m) }
+func (*DryRunAction) ProtoMessage()    {}
+func (*DryRunAction) Descriptor() ([]byte, []int) {
+	return fileDescriptor_a6e085f7dfa34cd8, []int{23}
+}
+
+func (m *DryRunAction) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DryRunAction.Unmarshal(m, b)
+}
+func (m *DryRunAction) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DryRunAction.Marshal(b, m, deterministic)
+}
+func (m *DryRunAction) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DryRunAction.Merge(m, src)
+}
+func (m *DryRunAction) XXX_Size() int {
+	return xxx_messageInfo_DryRunAction.Size(m)
+}
+func (m *DryRunAction) XXX_DiscardUnknown() {
+	xxx_messageInfo_DryRunAction.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DryRunAction proto.InternalMessageInfo
+
+func (m *DryRunAction) GetChain() string {
+	if m != nil {
+		return m.Chain
+	}
+	return ""
+}
+
+func (m *DryRunAction) GetAction() *Action {
+	if m != nil {
+		return m.Action
+	}
+	return nil
+}
+
+type InjectEventResponse struct {
+	EventId              string          `protobuf:"bytes,1,opt,name=event_id,json=eventId,proto3" json:"event_id,omitempty"`
+	Chains               []string        `protobuf:"bytes,2,rep,name=chains,proto3" json:"chains,omitempty"`
+	Actions              []*DryRunAction `protobuf:"bytes,3,rep,name=actions,proto3" json:"actions,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}        `json:"-"`
+	XXX_unrecognized     []byte          `json:"-"`
+	XXX_sizecache        int32           `json:"-"`
+}
+
+func (m *InjectEventResponse) Reset()         { *m = InjectEventResponse{} }
+func (m *InjectEventResponse) String() string { return proto.CompactTextString(m) }
+func (*InjectEventResponse) ProtoMessage()    {}
+func (*InjectEventResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_a6e085f7dfa34cd8, []int{24}
+}
+
+func (m *InjectEventResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_InjectEventResponse.Unmarshal(m, b)
+}
+func (m *InjectEventResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_InjectEventResponse.Marshal(b, m, deterministic)
+}
+func (m *InjectEventResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_InjectEventResponse.Merge(m, src)
+}
+func (m *InjectEventResponse) XXX_Size() int {
+	return xxx_messageInfo_InjectEventResponse.Size(m)
+}
+func (m *InjectEventResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_InjectEventResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_InjectEventResponse proto.InternalMessageInfo
+
+func (m *InjectEventResponse) GetEventId() string {
+	if m != nil {
+		return m.EventId
+	}
+	return ""
+}
+
+func (m *InjectEventResponse) GetChains() []string {
+	if m != nil {
+		return m.Chains
+	}
+	return nil
+}
+
+func (m *InjectEventResponse) GetActions() []*DryRunAction {
+	if m != nil {
+		return m.Actions
+	}
+	return nil
+}
+
+func init() {
+	proto.RegisterType((*Placeholder)(nil), "beehive.Placeholder")
+	proto.RegisterType((*Bee)(nil), "beehive.Bee")
+	proto.RegisterType((*Chain)(nil), "beehive.Chain")
+	proto.RegisterType((*Action)(nil), "beehive.Action")
+	proto.RegisterType((*Event)(nil), "beehive.Event")
+	proto.RegisterType((*ListBeesRequest)(nil), "beehive.ListBeesRequest")
+	proto.RegisterType((*ListBeesResponse)(nil), "beehive.ListBeesResponse")
+	proto.RegisterType((*CreateBeeRequest)(nil), "beehive.CreateBeeRequest")
+	proto.RegisterType((*DeleteBeeRequest)(nil), "beehive.DeleteBeeRequest")
+	proto.RegisterType((*DeleteBeeResponse)(nil), "beehive.DeleteBeeResponse")
+	proto.RegisterType((*PauseBeeRequest)(nil), "beehive.PauseBeeRequest")
+	proto.RegisterType((*ListChainsRequest)(nil), "beehive.ListChainsRequest")
+	proto.RegisterType((*ListChainsResponse)(nil), "beehive.ListChainsResponse")
+	proto.RegisterType((*PutChainRequest)(nil), "beehive.PutChainRequest")
+	proto.RegisterType((*DeleteChainRequest)(nil), "beehive.DeleteChainRequest")
+	proto.RegisterType((*DeleteChainResponse)(nil), "beehive.DeleteChainResponse")
+	proto.RegisterType((*ListActionsRequest)(nil), "beehive.ListActionsRequest")
+	proto.RegisterType((*ListActionsResponse)(nil), "beehive.ListActionsResponse")
+	proto.RegisterType((*PutActionRequest)(nil), "beehive.PutActionRequest")
+	proto.RegisterType((*ExecuteActionRequest)(nil), "beehive.ExecuteActionRequest")
+	proto.RegisterType((*ExecuteActionResponse)(nil), "beehive.ExecuteActionResponse")
+	proto.RegisterType((*StreamEventsRequest)(nil), "beehive.StreamEventsRequest")
+	proto.RegisterType((*InjectEventRequest)(nil), "beehive.InjectEventRequest")
+	proto.RegisterType((*DryRunAction)(nil), "beehive.DryRunAction")
+	proto.RegisterType((*InjectEventResponse)(nil), "beehive.InjectEventResponse")
+}
+
+func init() { proto.RegisterFile("rpc/beehive.proto", fileDescriptor_a6e085f7dfa34cd8) }
+
+var fileDescriptor_a6e085f7dfa34cd8 = []byte{
+	// 872 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xbc, 0x56, 0xdd, 0x6e, 0xe3, 0x44,
+	0x14, 0x56, 0x92, 0x26, 0x4e, 0x4e, 0xb2, 0x4d, 0x3a, 0x49, 0xc1, 0xeb, 0x2d, 0x55, 0x18, 0x60,
+	0x09, 0x37, 0x09, 0x0a, 0x2b, 0xed, 0x45, 0x81, 0x85, 0xec, 0x56, 0xb4, 0xe2, 0x47, 0x91, 0x7b,
+	0xc7, 0x4d, 0xe5, 0xd8, 0x87, 0xc6, 0xc8, 0xb1, 0xcd, 0xd8, 0xae, 0xc8, 0x7b, 0x70, 0xc9, 0x5b,
+	0xf0, 0x82, 0xc8, 0xf3, 0xe3, 0x8c, 0x5d, 0xb7, 0x12, 0x5c, 0xec, 0xdd, 0xcc, 0xf9, 0x9b, 0xef,
+	0x3b, 0x3e, 0xe7, 0x93, 0xe1, 0x84, 0xc5, 0xee, 0x62, 0x83, 0xb8, 0xf5, 0xef, 0x71, 0x1e, 0xb3,
+	0x28, 0x8d, 0x88, 0x21, 0xaf, 0xf4, 0x47, 0xe8, 0xaf, 0x03, 0xc7, 0xc5, 0x6d, 0x14, 0x78, 0xc8,
+	0x08, 0x81, 0xa3, 0xd0, 0xd9, 0xa1, 0xd9, 0x98, 0x36, 0x66, 0x3d, 0x9b, 0x9f, 0x73, 0x5b, 0xba,
+	0x8f, 0xd1, 0x6c, 0x0a, 0x5b, 0x7e, 0x26, 0x13, 0x68, 0xdf, 0x3b, 0x41, 0x86, 0x66, 0x8b, 0x1b,
+	0xc5, 0x85, 0xfe, 0xd3, 0x80, 0xd6, 0x0a, 0xb1, 0xb6, 0xca, 0x04, 0xda, 0x6e, 0xe0, 0x24, 0x89,
+	0x2c, 0x23, 0x2e, 0x64, 0x0a, 0x7d, 0x0f, 0x13, 0x97, 0xf9, 0x71, 0xea, 0x47, 0xa1, 0xac, 0xa6,
+	0x9b, 0xc8, 0x1c, 0x8c, 0x88, 0x9f, 0x12, 0xf3, 0x68, 0xda, 0x9a, 0xf5, 0x97, 0x93, 0xb9, 0xa2,
+	0xa2, 0x01, 0xb7, 0x55, 0x10, 0xf9, 0x00, 0x3a, 0xb1, 0x93, 0x25, 0xe8, 0x99, 0xed, 0x69, 0x63,
+	0xd6, 0xb5, 0xe5, 0x8d, 0x98, 0x60, 0xb0, 0x2c, 0x0c, 0xfd, 0xf0, 0xce, 0xec, 0x70, 0x87, 0xba,
+	0xd2, 0x0b, 0x68, 0xbf, 0xdd, 0x3a, 0x7e, 0x58, 0x0b, 0xfb, 0x1c, 0xc0, 0xc3, 0xdf, 0xfc, 0xd0,
+	0xe7, 0xf8, 0x72, 0xec, 0x03, 0x5b, 0xb3, 0xd0, 0x10, 0x3a, 0xdf, 0xbb, 0x1c, 0xe8, 0x31, 0x34,
+	0x7d, 0x4f, 0xe6, 0x36, 0x7d, 0x8f, 0x8c, 0xa0, 0xb5, 0x41, 0xd5, 0xb5, 0xfc, 0x58, 0xd4, 0x6f,
+	0x69, 0xf5, 0xff, 0x23, 0x3d, 0xfa, 0x57, 0x03, 0xda, 0x97, 0xf7, 0x18, 0xa6, 0xef, 0xe7, 0x3d,
+	0xf2, 0x19, 0x1c, 0xbb, 0x11, 0x63, 0x18, 0x38, 0xf9, 0xfd, 0xd6, 0x17, 0x6d, 0xed, 0xd9, 0xcf,
+	0x34, 0xeb, 0xb5, 0x47, 0x4f, 0x60, 0xf8, 0x93, 0x9f, 0xa4, 0x2b, 0xc4, 0xc4, 0xc6, 0x3f, 0x32,
+	0x4c, 0x52, 0xfa, 0x0a, 0x46, 0x07, 0x53, 0x12, 0x47, 0x61, 0x82, 0x64, 0x0a, 0x47, 0x1b, 0xc4,
+	0xc4, 0x6c, 0xf0, 0xa7, 0x07, 0xc5, 0xd3, 0x2b, 0x44, 0x9b, 0x7b, 0xe8, 0x12, 0x46, 0x6f, 0x19,
+	0x3a, 0x29, 0xe6, 0x26, 0x51, 0x89, 0x9c, 0x0b, 0x66, 0x39, 0xd5, 0x6a, 0x52, 0xee, 0xa0, 0x2f,
+	0x61, 0xf4, 0x0e, 0x03, 0x2c, 0xe5, 0xd4, 0x7c, 0x4b, 0x3a, 0x86, 0x13, 0x2d, 0x4e, 0x40, 0xa2,
+	0xdf, 0xc0, 0x70, 0x9d, 0x4f, 0xc8, 0xd3, 0xb9, 0xda, 0x58, 0x35, 0xf5, 0xb1, 0xca, 0x6b, 0xe6,
+	0x2c, 0xf9, 0x00, 0x15, 0xd4, 0xbf, 0x06, 0xa2, 0x1b, 0x25, 0xf9, 0x97, 0xd0, 0x71, 0xb9, 0x45,
+	0xd2, 0x3f, 0x2e, 0x98, 0xf0, 0x40, 0x5b, 0x7a, 0xe9, 0x6b, 0x18, 0xae, 0x33, 0x91, 0xac, 0x10,
+	0x7d, 0x0a, 0x6d, 0xee, 0x94, 0x3d, 0xa8, 0x66, 0x0a, 0x27, 0x9d, 0x01, 0x11, 0xfc, 0x4a, 0xb9,
+	0x75, 0x9d, 0x38, 0x85, 0x71, 0x29, 0x52, 0xf6, 0x62, 0x22, 0x70, 0x8b, 0x81, 0x2e, 0xd8, 0x7c,
+	0x07, 0xe3, 0x92, 0x55, 0xd2, 0xf9, 0x02, 0x0c, 0x47, 0x98, 0x24, 0x9f, 0x61, 0x81, 0x4a, 0x84,
+	0xda, 0xca, 0x4f, 0x2f, 0x60, 0xb4, 0xce, 0x64, 0x01, 0x05, 0xeb, 0x73, 0xe8, 0x08, 0xb7, 0xe4,
+	0xf4, 0x20, 0x5b, 0xba, 0xe9, 0x1b, 0x98, 0x5c, 0xfe, 0x89, 0x6e, 0x96, 0xe2, 0xff, 0x2c, 0xf0,
+	0x03, 0x9c, 0x56, 0x0a, 0x48, 0x06, 0x73, 0x30, 0x18, 0x26, 0x59, 0x90, 0x2a, 0x06, 0x8f, 0xec,
+	0x82, 0x0c, 0xa2, 0x0b, 0x18, 0xdf, 0xa4, 0x0c, 0x9d, 0x1d, 0x5f, 0x40, 0xd5, 0x9f, 0x5c, 0x59,
+	0x62, 0x27, 0x4d, 0x91, 0x85, 0xb2, 0xc7, 0xea, 0x4a, 0x6f, 0x80, 0x5c, 0x87, 0xbf, 0xa3, 0x9b,
+	0xf2, 0x04, 0xed, 0x63, 0x62, 0x7e, 0x7f, 0xf0, 0x31, 0x45, 0x94, 0x70, 0x92, 0x0f, 0xc1, 0xf0,
+	0xd8, 0xfe, 0x96, 0x65, 0xa1, 0x9a, 0x38, 0x8f, 0xed, 0xed, 0x2c, 0xa4, 0x3f, 0xc3, 0xe0, 0x1d,
+	0x3f, 0x49, 0xdd, 0x99, 0xe8, 0xb3, 0xd1, 0x93, 0xb3, 0xa0, 0x75, 0xa7, 0xf9, 0x74, 0x77, 0xf6,
+	0x30, 0x2e, 0x61, 0x94, 0xbd, 0x79, 0x0e, 0x5d, 0x8e, 0xe3, 0xb6, 0xd0, 0x18, 0x83, 0xdf, 0xaf,
+	0xbd, 0x7c, 0x15, 0xe4, 0x1c, 0x37, 0xa7, 0xad, 0x59, 0x4f, 0xcd, 0x2d, 0x59, 0x1c, 0x06, 0xa2,
+	0xc5, 0xdb, 0x79, 0x5a, 0xbc, 0xa9, 0x03, 0x2e, 0xc6, 0x62, 0xf9, 0x77, 0x07, 0x8c, 0x95, 0x88,
+	0x20, 0x6f, 0xa0, 0xab, 0xd4, 0x82, 0x98, 0x45, 0x5e, 0x45, 0x53, 0xac, 0xe7, 0x35, 0x1e, 0x09,
+	0xf8, 0x15, 0xf4, 0x0a, 0xe1, 0x20, 0x87, 0xb8, 0xaa, 0x98, 0x58, 0x25, 0xfd, 0x20, 0x2b, 0xe8,
+	0x15, 0x92, 0xa0, 0x65, 0x55, 0xe5, 0xc4, 0xb2, 0xea, 0x5c, 0xf2, 0xe5, 0x25, 0x74, 0x95, 0x82,
+	0x68, 0xd0, 0x2b, 0xa2, 0x52, 0x79, 0xf7, 0x12, 0xe0, 0xa0, 0x10, 0xc4, 0x2a, 0xd1, 0x2a, 0x69,
+	0x89, 0xf5, 0xa2, 0xd6, 0x57, 0x90, 0xee, 0x2a, 0xa9, 0xd0, 0x9f, 0x2e, 0xab, 0x87, 0x55, 0x91,
+	0x0b, 0x72, 0x05, 0x7d, 0x6d, 0xfb, 0xc9, 0x8b, 0x0a, 0xb7, 0x52, 0xee, 0x59, 0xbd, 0x53, 0xbe,
+	0x7f, 0x05, 0x7d, 0x4d, 0x1a, 0x48, 0x19, 0x6b, 0x59, 0x46, 0xac, 0xb3, 0x7a, 0xa7, 0xac, 0xf4,
+	0x1a, 0x7a, 0x85, 0x44, 0x68, 0x1f, 0xa2, 0x2a, 0x1b, 0x56, 0x75, 0x8e, 0xc9, 0x2f, 0xf0, 0xac,
+	0xb4, 0xdd, 0xe4, 0xa3, 0xc3, 0x3e, 0xd5, 0xc8, 0x86, 0x75, 0xfe, 0x98, 0x5b, 0x02, 0xf9, 0x16,
+	0x06, 0xfa, 0x92, 0x93, 0x03, 0xec, 0x9a, 0xdd, 0xb7, 0x2a, 0xcb, 0xfb, 0x65, 0x23, 0x6f, 0x89,
+	0xb6, 0x4f, 0x5a, 0x4b, 0x1e, 0x2a, 0x81, 0x75, 0x56, 0xef, 0x14, 0x48, 0x56, 0x9f, 0xfc, 0xfa,
+	0xf1, 0x9d, 0x9f, 0x6e, 0xb3, 0xcd, 0xdc, 0x8d, 0x76, 0x8b, 0x5d, 0x86, 0x49, 0xe0, 0xab, 0xdf,
+	0xb8, 0x05, 0x8b, 0xdd, 0x0b, 0x16, 0xbb, 0x9b, 0x0e, 0xff, 0x9f, 0xfb, 0xea, 0xdf, 0x01, 0x00,
+	0x92, 0xfb, 0x2d, 0x37, 0xe4, 0x09, 0x00, 0x00,
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConn
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion4
+
+// BeehiveClient is the client API for Beehive service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type BeehiveClient interface {
+	ListBees(ctx context.Context, in *ListBeesRequest, opts ...grpc.CallOption) (*ListBeesResponse, error)
+	CreateBee(ctx context.Context, in *CreateBeeRequest, opts ...grpc.CallOption) (*Bee, error)
+	DeleteBee(ctx context.Context, in *DeleteBeeRequest, opts ...grpc.CallOption) (*DeleteBeeResponse, error)
+	PauseBee(ctx context.Context, in *PauseBeeRequest, opts ...grpc.CallOption) (*Bee, error)
+	ListChains(ctx context.Context, in *ListChainsRequest, opts ...grpc.CallOption) (*ListChainsResponse, error)
+	PutChain(ctx context.Context, in *PutChainRequest, opts ...grpc.CallOption) (*Chain, error)
+	DeleteChain(ctx context.Context, in *DeleteChainRequest, opts ...grpc.CallOption) (*DeleteChainResponse, error)
+	ListActions(ctx context.Context, in *ListActionsRequest, opts ...grpc.CallOption) (*ListActionsResponse, error)
+	PutAction(ctx context.Context, in *PutActionRequest, opts ...grpc.CallOption) (*Action, error)
+	ExecuteAction(ctx context.Context, in *ExecuteActionRequest, opts ...grpc.CallOption) (*ExecuteActionResponse, error)
+	// StreamEvents delivers the hive's events until the client cancels the
+	// call. Events arriving faster than the client reads them get dropped.
+	StreamEvents(ctx context.Context, in *StreamEventsRequest, opts ...grpc.CallOption) (Beehive_StreamEventsClient, error)
+	InjectEvent(ctx context.Context, in *InjectEventRequest, opts ...grpc.CallOption) (*InjectEventResponse, error)
+}
+
+type beehiveClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewBeehiveClient(cc *grpc.ClientConn) BeehiveClient {
+	return &beehiveClient{cc}
+}
+
+func (c *beehiveClient) ListBees(ctx context.Context, in *ListBeesRequest, opts ...grpc.CallOption) (*ListBeesResponse, error) {
+	out := new(ListBeesResponse)
+	err := c.cc.Invoke(ctx, "/beehive.Beehive/ListBees", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *beehiveClient) CreateBee(ctx context.Context, in *CreateBeeRequest, opts ...grpc.CallOption) (*Bee, error) {
+	out := new(Bee)
+	err := c.cc.Invoke(ctx, "/beehive.Beehive/CreateBee", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *beehiveClient) DeleteBee(ctx context.Context, in *DeleteBeeRequest, opts ...grpc.CallOption) (*DeleteBeeResponse, error) {
+	out := new(DeleteBeeResponse)
+	err := c.cc.Invoke(ctx, "/beehive.Beehive/DeleteBee", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *beehiveClient) PauseBee(ctx context.Context, in *PauseBeeRequest, opts ...grpc.CallOption) (*Bee, error) {
+	out := new(Bee)
+	err := c.cc.Invoke(ctx, "/beehive.Beehive/PauseBee", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *beehiveClient) ListChains(ctx context.Context, in *ListChainsRequest, opts ...grpc.CallOption) (*ListChainsResponse, error) {
+	out := new(ListChainsResponse)
+	err := c.cc.Invoke(ctx, "/beehive.Beehive/ListChains", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *beehiveClient) PutChain(ctx context.Context, in *PutChainRequest, opts ...grpc.CallOption) (*Chain, error) {
+	out := new(Chain)
+	err := c.cc.Invoke(ctx, "/beehive.Beehive/PutChain", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *beehiveClient) DeleteChain(ctx context.Context, in *DeleteChainRequest, opts ...grpc.CallOption) (*DeleteChainResponse, error) {
+	out := new(DeleteChainResponse)
+	err := c.cc.Invoke(ctx, "/beehive.Beehive/DeleteChain", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *beehiveClient) ListActions(ctx context.Context, in *ListActionsRequest, opts ...grpc.CallOption) (*ListActionsResponse, error) {
+	out := new(ListActionsResponse)
+	err := c.cc.Invoke(ctx, "/beehive.Beehive/ListActions", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *beehiveClient) PutAction(ctx context.Context, in *PutActionRequest, opts ...grpc.CallOption) (*Action, error) {
+	out := new(Action)
+	err := c.cc.Invoke(ctx, "/beehive.Beehive/PutAction", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *beehiveClient) ExecuteAction(ctx context.Context, in *ExecuteActionRequest, opts ...grpc.CallOption) (*ExecuteActionResponse, error) {
+	out := new(ExecuteActionResponse)
+	err := c.cc.Invoke(ctx, "/beehive.Beehive/ExecuteAction", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *beehiveClient) StreamEvents(ctx context.Context, in *StreamEventsRequest, opts ...grpc.CallOption) (Beehive_StreamEventsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_Beehive_serviceDesc.Streams[0], "/beehive.Beehive/StreamEvents", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &beehiveStreamEventsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Beehive_StreamEventsClient interface {
+	Recv() (*Event, error)
+	grpc.ClientStream
+}
+
+type beehiveStreamEventsClient struct {
+	grpc.ClientStream
+}
+
+func (x *beehiveStreamEventsClient) Recv() (*Event, error) {
+	m := new(Event)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *beehiveClient) InjectEvent(ctx context.Context, in *InjectEventRequest, opts ...grpc.CallOption) (*InjectEventResponse, error) {
+	out := new(InjectEventResponse)
+	err := c.cc.Invoke(ctx, "/beehive.Beehive/InjectEvent", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// BeehiveServer is the server API for Beehive service.
+type BeehiveServer interface {
+	ListBees(context.Context, *ListBeesRequest) (*ListBeesResponse, error)
+	CreateBee(context.Context, *CreateBeeRequest) (*Bee, error)
+	DeleteBee(context.Context, *DeleteBeeRequest) (*DeleteBeeResponse, error)
+	PauseBee(context.Context, *PauseBeeRequest) (*Bee, error)
+	ListChains(context.Context, *ListChainsRequest) (*ListChainsResponse, error)
+	PutChain(context.Context, *PutChainRequest) (*Chain, error)
+	DeleteChain(context.Context, *DeleteChainRequest) (*DeleteChainResponse, error)
+	ListActions(context.Context, *ListActionsRequest) (*ListActionsResponse, error)
+	PutAction(context.Context, *PutActionRequest) (*Action, error)
+	ExecuteAction(context.Context, *ExecuteActionRequest) (*ExecuteActionResponse, error)
+	// StreamEvents delivers the hive's events until the client cancels the
+	// call. Events arriving faster than the client reads them get dropped.
+	StreamEvents(*StreamEventsRequest, Beehive_StreamEventsServer) error
+	InjectEvent(context.Context, *InjectEventRequest) (*InjectEventResponse, error)
+}
+
+// UnimplementedBeehiveServer can be embedded to have forward compatible implementations.
+type UnimplementedBeehiveServer struct {
+}
+
+func (*UnimplementedBeehiveServer) ListBees(ctx context.Context, req *ListBeesRequest) (*ListBeesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListBees not implemented")
+}
+func (*UnimplementedBeehiveServer) CreateBee(ctx context.Context, req *CreateBeeRequest) (*Bee, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateBee not implemented")
+}
+func (*UnimplementedBeehiveServer) DeleteBee(ctx context.Context, req *DeleteBeeRequest) (*DeleteBeeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteBee not implemented")
+}
+func (*UnimplementedBeehiveServer) PauseBee(ctx context.Context, req *PauseBeeRequest) (*Bee, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PauseBee not implemented")
+}
+func (*UnimplementedBeehiveServer) ListChains(ctx context.Context, req *ListChainsRequest) (*ListChainsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListChains not implemented")
+}
+func (*UnimplementedBeehiveServer) PutChain(ctx context.Context, req *PutChainRequest) (*Chain, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PutChain not implemented")
+}
+func (*UnimplementedBeehiveServer) DeleteChain(ctx context.Context, req *DeleteChainRequest) (*DeleteChainResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteChain not implemented")
+}
+func (*UnimplementedBeehiveServer) ListActions(ctx context.Context, req *ListActionsRequest) (*ListActionsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListActions not implemented")
+}
+func (*UnimplementedBeehiveServer) PutAction(ctx context.Context, req *PutActionRequest) (*Action, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PutAction not implemented")
+}
+func (*UnimplementedBeehiveServer) ExecuteAction(ctx context.Context, req *ExecuteActionRequest) (*ExecuteActionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ExecuteAction not implemented")
+}
+func (*UnimplementedBeehiveServer) StreamEvents(req *StreamEventsRequest, srv Beehive_StreamEventsServer) error {
+	return status.Errorf(codes.Unimplemented, "method StreamEvents not implemented")
+}
+func (*UnimplementedBeehiveServer) InjectEvent(ctx context.Context, req *InjectEventRequest) (*InjectEventResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method InjectEvent not implemented")
+}
+
+func RegisterBeehiveServer(s *grpc.Server, srv BeehiveServer) {
+	s.RegisterService(&_Beehive_serviceDesc, srv)
+}
+
+func _Beehive_ListBees_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListBeesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BeehiveServer).ListBees(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/beehive.Beehive/ListBees",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BeehiveServer).ListBees(ctx, req.(*ListBeesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Beehive_CreateBee_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateBeeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BeehiveServer).CreateBee(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/beehive.Beehive/CreateBee",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BeehiveServer).CreateBee(ctx, req.(*CreateBeeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Beehive_DeleteBee_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteBeeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BeehiveServer).DeleteBee(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/beehive.Beehive/DeleteBee",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BeehiveServer).DeleteBee(ctx, req.(*DeleteBeeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Beehive_PauseBee_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PauseBeeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BeehiveServer).PauseBee(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/beehive.Beehive/PauseBee",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BeehiveServer).PauseBee(ctx, req.(*PauseBeeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Beehive_ListChains_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListChainsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BeehiveServer).ListChains(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/beehive.Beehive/ListChains",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BeehiveServer).ListChains(ctx, req.(*ListChainsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Beehive_PutChain_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PutChainRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BeehiveServer).PutChain(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/beehive.Beehive/PutChain",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BeehiveServer).PutChain(ctx, req.(*PutChainRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Beehive_DeleteChain_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteChainRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BeehiveServer).DeleteChain(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/beehive.Beehive/DeleteChain",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BeehiveServer).DeleteChain(ctx, req.(*DeleteChainRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Beehive_ListActions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListActionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BeehiveServer).ListActions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/beehive.Beehive/ListActions",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BeehiveServer).ListActions(ctx, req.(*ListActionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Beehive_PutAction_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PutActionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BeehiveServer).PutAction(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/beehive.Beehive/PutAction",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BeehiveServer).PutAction(ctx, req.(*PutActionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Beehive_ExecuteAction_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ExecuteActionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BeehiveServer).ExecuteAction(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/beehive.Beehive/ExecuteAction",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BeehiveServer).ExecuteAction(ctx, req.(*ExecuteActionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Beehive_StreamEvents_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(StreamEventsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(BeehiveServer).StreamEvents(m, &beehiveStreamEventsServer{stream})
+}
+
+type Beehive_StreamEventsServer interface {
+	Send(*Event) error
+	grpc.ServerStream
+}
+
+type beehiveStreamEventsServer struct {
+	grpc.ServerStream
+}
+
+func (x *beehiveStreamEventsServer) Send(m *Event) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _Beehive_InjectEvent_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(InjectEventRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BeehiveServer).InjectEvent(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/beehive.Beehive/InjectEvent",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BeehiveServer).InjectEvent(ctx, req.(*InjectEventRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _Beehive_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "beehive.Beehive",
+	HandlerType: (*BeehiveServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ListBees",
+			Handler:    _Beehive_ListBees_Handler,
+		},
+		{
+			MethodName: "CreateBee",
+			Handler:    _Beehive_CreateBee_Handler,
+		},
+		{
+			MethodName: "DeleteBee",
+			Handler:    _Beehive_DeleteBee_Handler,
+		},
+		{
+			MethodName: "PauseBee",
+			Handler:    _Beehive_PauseBee_Handler,
+		},
+		{
+			MethodName: "ListChains",
+			Handler:    _Beehive_ListChains_Handler,
+		},
+		{
+			MethodName: "PutChain",
+			Handler:    _Beehive_PutChain_Handler,
+		},
+		{
+			MethodName: "DeleteChain",
+			Handler:    _Beehive_DeleteChain_Handler,
+		},
+		{
+			MethodName: "ListActions",
+			Handler:    _Beehive_ListActions_Handler,
+		},
+		{
+			MethodName: "PutAction",
+			Handler:    _Beehive_PutAction_Handler,
+		},
+		{
+			MethodName: "ExecuteAction",
+			Handler:    _Beehive_ExecuteAction_Handler,
+		},
+		{
+			MethodName: "InjectEvent",
+			Handler:    _Beehive_InjectEvent_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "StreamEvents",
+			Handler:       _Beehive_StreamEvents_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "rpc/beehive.proto",
+}
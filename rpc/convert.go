@@ -0,0 +1,159 @@
+/*
+ *    Copyright (C) 2026 Christian Muehlhaeuser
+ *
+ *    This program is free software: you can redistribute it and/or modify
+ *    it under the terms of the GNU Affero General Public License as published
+ *    by the Free Software Foundation, either version 3 of the License, or
+ *    (at your option) any later version.
+ *
+ *    This program is distributed in the hope that it will be useful,
+ *    but WITHOUT ANY WARRANTY; without even the implied warranty of
+ *    MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ *    GNU Affero General Public License for more details.
+ *
+ *    You should have received a copy of the GNU Affero General Public License
+ *    along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ *    Authors:
+ *      Christian Muehlhaeuser <muesli@gmail.com>
+ */
+
+package rpc
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/muesli/beehive/bees"
+)
+
+// jsonValue encodes a value for a Placeholder message.
+func jsonValue(v interface{}) (string, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+// parseValue decodes the value of a Placeholder message. Empty values are nil.
+func parseValue(name, s string) (interface{}, error) {
+	if len(s) == 0 {
+		return nil, nil
+	}
+
+	var v interface{}
+	if err := json.Unmarshal([]byte(s), &v); err != nil {
+		return nil, fmt.Errorf("Invalid JSON value of %s: %v", name, err)
+	}
+	return v, nil
+}
+
+// placeholderMessages converts placeholders to messages.
+func placeholderMessages(ps []bees.Placeholder) ([]*Placeholder, error) {
+	var ms []*Placeholder
+	for _, p := range ps {
+		v, err := jsonValue(p.Value)
+		if err != nil {
+			return nil, fmt.Errorf("Placeholder %s: %v", p.Name, err)
+		}
+		ms = append(ms, &Placeholder{Name: p.Name, Type: p.Type, Value: v})
+	}
+	return ms, nil
+}
+
+// placeholders converts messages to placeholders.
+func placeholders(ms []*Placeholder) (bees.Placeholders, error) {
+	ps := bees.Placeholders{}
+	for _, m := range ms {
+		v, err := parseValue(m.Name, m.Value)
+		if err != nil {
+			return nil, err
+		}
+		ps = append(ps, bees.Placeholder{Name: m.Name, Type: m.Type, Value: v})
+	}
+	return ps, nil
+}
+
+// beeOptions converts messages to the options of a bee.
+func beeOptions(ms []*Placeholder) (bees.BeeOptions, error) {
+	opts := bees.BeeOptions{}
+	for _, m := range ms {
+		v, err := parseValue(m.Name, m.Value)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, bees.BeeOption{Name: m.Name, Value: v})
+	}
+	return opts, nil
+}
+
+// beeMessage converts a bee's config to a message, with its passwords masked.
+func beeMessage(c bees.BeeConfig) *Bee {
+	m := &Bee{
+		Name:        c.Name,
+		Class:       c.Class,
+		Description: c.Description,
+		Paused:      bees.BeePaused(c.Name),
+	}
+	if bee := bees.GetBee(c.Name); bee != nil {
+		m.Running = (*bee).IsRunning()
+	}
+
+	for _, opt := range bees.MaskOptions(c.Class, c.Options) {
+		// options come from a config, so they always encode
+		v, _ := jsonValue(opt.Value)
+		m.Options = append(m.Options, &Placeholder{Name: opt.Name, Value: v})
+	}
+	return m
+}
+
+// chainMessage converts a chain to a message.
+func chainMessage(c bees.Chain) (*Chain, error) {
+	b, err := json.Marshal(c)
+	if err != nil {
+		return nil, fmt.Errorf("Chain %s: %v", c.Name, err)
+	}
+	return &Chain{Name: c.Name, Definition: b}, nil
+}
+
+// actionMessage converts an action to a message.
+func actionMessage(a bees.Action) (*Action, error) {
+	options, err := placeholderMessages(a.Options)
+	if err != nil {
+		return nil, fmt.Errorf("Action %s: %v", a.ID, err)
+	}
+	return &Action{Id: a.ID, Bee: a.Bee, Name: a.Name, Options: options}, nil
+}
+
+// beeAction converts a message to an action.
+func beeAction(m *Action) (bees.Action, error) {
+	if m == nil {
+		return bees.Action{}, errors.New("An action is required")
+	}
+	if len(m.Bee) == 0 || len(m.Name) == 0 {
+		return bees.Action{}, errors.New("Actions need a bee and a name")
+	}
+
+	options, err := placeholders(m.Options)
+	if err != nil {
+		return bees.Action{}, err
+	}
+	return bees.Action{ID: m.Id, Bee: m.Bee, Name: m.Name, Options: options}, nil
+}
+
+// eventMessage converts an event to a message.
+func eventMessage(ev bees.Event) (*Event, error) {
+	options, err := placeholderMessages(ev.Options)
+	if err != nil {
+		return nil, fmt.Errorf("Event %s/%s: %v", ev.Bee, ev.Name, err)
+	}
+	return &Event{
+		Id:            ev.ID,
+		Bee:           ev.Bee,
+		Name:          ev.Name,
+		Options:       options,
+		CorrelationId: ev.CorrelationID,
+	}, nil
+}
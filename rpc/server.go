@@ -0,0 +1,297 @@
+/*
+ *    Copyright (C) 2026 Christian Muehlhaeuser
+ *
+ *    This program is free software: you can redistribute it and/or modify
+ *    it under the terms of the GNU Affero General Public License as published
+ *    by the Free Software Foundation, either version 3 of the License, or
+ *    (at your option) any later version.
+ *
+ *    This program is distributed in the hope that it will be useful,
+ *    but WITHOUT ANY WARRANTY; without even the implied warranty of
+ *    MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ *    GNU Affero General Public License for more details.
+ *
+ *    You should have received a copy of the GNU Affero General Public License
+ *    along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ *    Authors:
+ *      Christian Muehlhaeuser <muesli@gmail.com>
+ */
+
+// Package rpc serves Beehive's gRPC API, see beehive.proto. It lets other
+// programs, including those embedding a bees.Hive, manage bees and chains,
+// follow the hive's events, inject events and execute actions.
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/muesli/beehive/bees"
+)
+
+// service implements BeehiveServer on top of a hive.
+type service struct {
+	hive *bees.Hive
+}
+
+// NewServer returns a gRPC server serving the Beehive service for hive.
+// Requests need to be authenticated once the API has credentials, see
+// authenticate.
+func NewServer(hive *bees.Hive, opts ...grpc.ServerOption) *grpc.Server {
+	opts = append(opts,
+		grpc.UnaryInterceptor(unaryAuthInterceptor),
+		grpc.StreamInterceptor(streamAuthInterceptor))
+
+	s := grpc.NewServer(opts...)
+	RegisterBeehiveServer(s, &service{hive: hive})
+	return s
+}
+
+// ListBees returns all bees.
+func (s *service) ListBees(ctx context.Context, req *ListBeesRequest) (*ListBeesResponse, error) {
+	resp := &ListBeesResponse{}
+	for _, c := range s.hive.Bees() {
+		resp.Bees = append(resp.Bees, beeMessage(c))
+	}
+	return resp, nil
+}
+
+// CreateBee creates and starts a bee.
+func (s *service) CreateBee(ctx context.Context, req *CreateBeeRequest) (*Bee, error) {
+	if req.Bee == nil {
+		return nil, status.Error(codes.InvalidArgument, "A bee is required")
+	}
+	options, err := beeOptions(req.Bee.Options)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	c, err := bees.NewBeeConfig(req.Bee.Name, req.Bee.Class, req.Bee.Description, options)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	c.Paused = req.Bee.Paused
+
+	bee, err := s.hive.CreateBee(c)
+	if err != nil {
+		return nil, statusError(err)
+	}
+	return beeMessage((*bee).Config()), nil
+}
+
+// DeleteBee stops and removes a bee.
+func (s *service) DeleteBee(ctx context.Context, req *DeleteBeeRequest) (*DeleteBeeResponse, error) {
+	if bees.GetBee(req.Name) == nil {
+		return nil, status.Errorf(codes.NotFound, "Bee %s does not exist", req.Name)
+	}
+	if err := s.hive.DeleteBee(req.Name); err != nil {
+		return nil, statusError(err)
+	}
+	return &DeleteBeeResponse{}, nil
+}
+
+// PauseBee pauses or resumes a bee.
+func (s *service) PauseBee(ctx context.Context, req *PauseBeeRequest) (*Bee, error) {
+	bee := bees.GetBee(req.Name)
+	if bee == nil {
+		return nil, status.Errorf(codes.NotFound, "Bee %s does not exist", req.Name)
+	}
+
+	var err error
+	if req.Paused {
+		err = s.hive.PauseBee(req.Name)
+	} else {
+		err = s.hive.ResumeBee(req.Name)
+	}
+	if err != nil {
+		return nil, statusError(err)
+	}
+	for _, c := range s.hive.Bees() {
+		if c.Name == req.Name {
+			return beeMessage(c), nil
+		}
+	}
+	return nil, status.Errorf(codes.NotFound, "Bee %s does not exist", req.Name)
+}
+
+// ListChains returns all chains.
+func (s *service) ListChains(ctx context.Context, req *ListChainsRequest) (*ListChainsResponse, error) {
+	resp := &ListChainsResponse{}
+	for _, c := range s.hive.Chains() {
+		m, err := chainMessage(c)
+		if err != nil {
+			return nil, status.Error(codes.Internal, err.Error())
+		}
+		resp.Chains = append(resp.Chains, m)
+	}
+	return resp, nil
+}
+
+// PutChain creates or replaces a chain.
+func (s *service) PutChain(ctx context.Context, req *PutChainRequest) (*Chain, error) {
+	if req.Chain == nil {
+		return nil, status.Error(codes.InvalidArgument, "A chain is required")
+	}
+	var c bees.Chain
+	if err := json.Unmarshal(req.Chain.Definition, &c); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "Invalid chain definition: %v", err)
+	}
+	if len(c.Name) == 0 {
+		c.Name = req.Chain.Name
+	}
+	if len(c.Name) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "Chains need a name")
+	}
+
+	if err := s.hive.PutChain(c); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	m, err := chainMessage(c)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	return m, nil
+}
+
+// DeleteChain removes a chain.
+func (s *service) DeleteChain(ctx context.Context, req *DeleteChainRequest) (*DeleteChainResponse, error) {
+	if !s.hive.RemoveChain(req.Name) {
+		return nil, status.Errorf(codes.NotFound, "Chain %s does not exist", req.Name)
+	}
+	return &DeleteChainResponse{}, nil
+}
+
+// ListActions returns all configured actions.
+func (s *service) ListActions(ctx context.Context, req *ListActionsRequest) (*ListActionsResponse, error) {
+	resp := &ListActionsResponse{}
+	for _, a := range s.hive.Actions() {
+		m, err := actionMessage(a)
+		if err != nil {
+			return nil, status.Error(codes.Internal, err.Error())
+		}
+		resp.Actions = append(resp.Actions, m)
+	}
+	return resp, nil
+}
+
+// PutAction creates or replaces an action, so chains can refer to it.
+func (s *service) PutAction(ctx context.Context, req *PutActionRequest) (*Action, error) {
+	a, err := beeAction(req.Action)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if errs := bees.ValidateAction(a); len(errs) > 0 {
+		return nil, status.Error(codes.InvalidArgument, (&bees.ValidationError{Errors: errs}).Error())
+	}
+
+	m, err := actionMessage(s.hive.PutAction(a))
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	return m, nil
+}
+
+// ExecuteAction executes an action right away.
+func (s *service) ExecuteAction(ctx context.Context, req *ExecuteActionRequest) (*ExecuteActionResponse, error) {
+	a, err := beeAction(req.Action)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if bees.GetBee(a.Bee) == nil {
+		return nil, status.Errorf(codes.NotFound, "Bee %s does not exist", a.Bee)
+	}
+
+	res, err := s.hive.ExecuteAction(ctx, a)
+	if _, ok := err.(*bees.ValidationError); ok {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	} else if err != nil {
+		return nil, status.Error(codes.Aborted, err.Error())
+	}
+	results, err := placeholderMessages(res)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	return &ExecuteActionResponse{Results: results}, nil
+}
+
+// StreamEvents sends the hive's events to the client, until it cancels the
+// call.
+func (s *service) StreamEvents(req *StreamEventsRequest, stream Beehive_StreamEventsServer) error {
+	pattern := req.Pattern
+	if len(pattern) == 0 {
+		pattern = "*"
+	}
+
+	ch := s.hive.Watch(pattern)
+	defer s.hive.Unwatch(ch)
+
+	for {
+		select {
+		case <-stream.Context().Done():
+			return nil
+		case ev, ok := <-ch:
+			if !ok {
+				return nil
+			}
+			m, err := eventMessage(ev)
+			if err != nil {
+				return status.Error(codes.Internal, err.Error())
+			}
+			if err := stream.Send(m); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// InjectEvent pushes an event through the hive's chains and waits until it
+// got handled.
+func (s *service) InjectEvent(ctx context.Context, req *InjectEventRequest) (*InjectEventResponse, error) {
+	if req.Event == nil {
+		return nil, status.Error(codes.InvalidArgument, "An event is required")
+	}
+	if c := credentialOf(ctx); c != nil && !c.AllowsBee(req.Event.Bee) {
+		return nil, status.Errorf(codes.PermissionDenied, "Not allowed to inject events of bee %s", req.Event.Bee)
+	}
+	options, err := placeholders(req.Event.Options)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	r, err := s.hive.InjectEvent(ctx, bees.Event{
+		Bee:     req.Event.Bee,
+		Name:    req.Event.Name,
+		Options: options,
+		DryRun:  req.DryRun,
+	})
+	if err != nil {
+		return nil, statusError(err)
+	}
+
+	resp := &InjectEventResponse{EventId: r.EventID, Chains: r.Chains}
+	for _, da := range r.Actions {
+		m, err := actionMessage(da.Action)
+		if err != nil {
+			return nil, status.Error(codes.Internal, err.Error())
+		}
+		resp.Actions = append(resp.Actions, &DryRunAction{Chain: da.Chain, Action: m})
+	}
+	return resp, nil
+}
+
+// statusError maps errors of the hive to gRPC status errors.
+func statusError(err error) error {
+	switch err {
+	case bees.ErrNotHandling:
+		return status.Error(codes.Unavailable, err.Error())
+	case bees.ErrDuplicateBee:
+		return status.Error(codes.AlreadyExists, err.Error())
+	case context.Canceled, context.DeadlineExceeded:
+		return status.FromContextError(err).Err()
+	}
+	return status.Error(codes.InvalidArgument, err.Error())
+}
@@ -0,0 +1,204 @@
+package rpc
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	apicontext "github.com/muesli/beehive/api/context"
+	"github.com/muesli/beehive/bees"
+)
+
+// startServer runs a hive with a single chain and serves it over an
+// in-memory connection. It returns a client and a function shutting both
+// down.
+func startServer(t *testing.T) (BeehiveClient, func()) {
+	hive := bees.NewHive(bees.HiveOptions{
+		Actions: []bees.Action{{ID: "rpc-post", Bee: "rpcsink", Name: "post"}},
+		Chains: []bees.Chain{{
+			Name:    "rpc-chain",
+			Event:   &bees.Event{Bee: "rpcfeed", Name: "link"},
+			Actions: []string{"rpc-post"},
+		}},
+		StopTimeout: time.Second,
+	})
+	if err := hive.Start(); err != nil {
+		t.Fatal(err)
+	}
+
+	lis := bufconn.Listen(1 << 20)
+	s := NewServer(hive)
+	go s.Serve(lis)
+
+	conn, err := grpc.Dial("bufnet", grpc.WithInsecure(), grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+		return lis.Dial()
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return NewBeehiveClient(conn), func() {
+		conn.Close()
+		s.Stop()
+		hive.Stop()
+	}
+}
+
+// withAuth returns a context sending auth as authorization metadata.
+func withAuth(auth string) context.Context {
+	if len(auth) == 0 {
+		return context.Background()
+	}
+	return metadata.AppendToOutgoingContext(context.Background(), "authorization", auth)
+}
+
+func TestAllows(t *testing.T) {
+	for _, tt := range []struct {
+		role    string
+		method  string
+		allowed bool
+	}{
+		{apicontext.RoleAdmin, "DeleteBee", true},
+		{apicontext.RoleAdmin, "InjectEvent", true},
+		{apicontext.RoleMonitor, "ListBees", true},
+		{apicontext.RoleMonitor, "StreamEvents", true},
+		{apicontext.RoleMonitor, "PutChain", false},
+		{apicontext.RoleMonitor, "InjectEvent", false},
+		{apicontext.RoleInject, "InjectEvent", true},
+		{apicontext.RoleInject, "ListBees", false},
+		{apicontext.RoleInject, "ExecuteAction", false},
+		{"unknown", "ListBees", false},
+	} {
+		if allowed := allows(&apicontext.Credential{Role: tt.role}, tt.method); allowed != tt.allowed {
+			t.Errorf("Expected role %s to be allowed to call %s: %v, got %v", tt.role, tt.method, tt.allowed, allowed)
+		}
+	}
+}
+
+func TestAuthenticate(t *testing.T) {
+	defer apicontext.SetCredentials(nil)
+	if err := apicontext.SetCredentials([]apicontext.Credential{
+		{Name: "admin", Token: "admintoken", Role: apicontext.RoleAdmin},
+		{Name: "monitor", Token: "monitortoken", Role: apicontext.RoleMonitor},
+		{Name: "webhook", Username: "hook", Password: "secret", Role: apicontext.RoleInject, Bees: []string{"rpcfeed"}},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	client, stop := startServer(t)
+	defer stop()
+
+	listBees := func(ctx context.Context) error {
+		_, err := client.ListBees(ctx, &ListBeesRequest{})
+		return err
+	}
+	inject := func(bee string) func(context.Context) error {
+		return func(ctx context.Context) error {
+			_, err := client.InjectEvent(ctx, &InjectEventRequest{Event: &Event{Bee: bee, Name: "link"}, DryRun: true})
+			return err
+		}
+	}
+
+	for _, tt := range []struct {
+		name string
+		auth string
+		call func(context.Context) error
+		code codes.Code
+	}{
+		{"anonymous", "", listBees, codes.Unauthenticated},
+		{"unknown token", "Bearer nosuchtoken", listBees, codes.Unauthenticated},
+		{"wrong password", "Basic aG9vazp3cm9uZw==", inject("rpcfeed"), codes.Unauthenticated},
+		{"admin", "Bearer admintoken", listBees, codes.OK},
+		{"monitor listing", "Bearer monitortoken", listBees, codes.OK},
+		{"monitor injecting", "Bearer monitortoken", inject("rpcfeed"), codes.PermissionDenied},
+		{"webhook injecting", "Basic aG9vazpzZWNyZXQ=", inject("rpcfeed"), codes.OK},
+		{"webhook injecting another bee", "Basic aG9vazpzZWNyZXQ=", inject("rpcother"), codes.PermissionDenied},
+		{"webhook listing", "Basic aG9vazpzZWNyZXQ=", listBees, codes.PermissionDenied},
+	} {
+		if code := status.Code(tt.call(withAuth(tt.auth))); code != tt.code {
+			t.Errorf("%s: expected %v, got %v", tt.name, tt.code, code)
+		}
+	}
+}
+
+func TestInjectEvent(t *testing.T) {
+	client, stop := startServer(t)
+	defer stop()
+
+	if _, err := client.InjectEvent(context.Background(), &InjectEventRequest{}); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("Expected a missing event to be rejected, got %v", err)
+	}
+
+	resp, err := client.InjectEvent(context.Background(), &InjectEventRequest{
+		Event: &Event{Bee: "rpcfeed", Name: "link", Options: []*Placeholder{
+			{Name: "url", Type: "url", Value: `"https://example.com"`},
+		}},
+		DryRun: true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.EventId) == 0 {
+		t.Error("Expected the injected event to get an ID")
+	}
+	if len(resp.Chains) != 1 || resp.Chains[0] != "rpc-chain" {
+		t.Errorf("Expected the event to trigger rpc-chain, got %v", resp.Chains)
+	}
+	if len(resp.Actions) != 1 || resp.Actions[0].Chain != "rpc-chain" || resp.Actions[0].Action.Bee != "rpcsink" {
+		t.Errorf("Expected the chain's action in the dry run, got %v", resp.Actions)
+	}
+}
+
+func TestStreamEvents(t *testing.T) {
+	client, stop := startServer(t)
+	defer stop()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	stream, err := client.StreamEvents(ctx, &StreamEventsRequest{Pattern: "rpcfeed/*"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	received := make(chan *Event)
+	go func() {
+		for {
+			ev, err := stream.Recv()
+			if err != nil {
+				close(received)
+				return
+			}
+			received <- ev
+		}
+	}()
+
+	// the server only watches the hive once the stream got set up, so keep
+	// injecting until the first event arrives
+	for i := 0; ; i++ {
+		if i == 50 {
+			t.Fatal("Expected the injected event to be streamed")
+		}
+		if _, err := client.InjectEvent(context.Background(), &InjectEventRequest{Event: &Event{Bee: "rpcother", Name: "link"}, DryRun: true}); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := client.InjectEvent(context.Background(), &InjectEventRequest{Event: &Event{Bee: "rpcfeed", Name: "link"}, DryRun: true}); err != nil {
+			t.Fatal(err)
+		}
+
+		select {
+		case ev := <-received:
+			if ev == nil || ev.Bee != "rpcfeed" || ev.Name != "link" {
+				t.Fatalf("Expected only events matching the pattern, got %v", ev)
+			}
+			cancel()
+			return
+		case <-time.After(20 * time.Millisecond):
+		}
+	}
+}
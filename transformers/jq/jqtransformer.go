@@ -0,0 +1,210 @@
+/*
+ *    Copyright (C) 2026 Christian Muehlhaeuser
+ *
+ *    This program is free software: you can redistribute it and/or modify
+ *    it under the terms of the GNU Affero General Public License as published
+ *    by the Free Software Foundation, either version 3 of the License, or
+ *    (at your option) any later version.
+ *
+ *    This program is distributed in the hope that it will be useful,
+ *    but WITHOUT ANY WARRANTY; without even the implied warranty of
+ *    MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ *    GNU Affero General Public License for more details.
+ *
+ *    You should have received a copy of the GNU Affero General Public License
+ *    along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ *    Authors:
+ *      Christian Muehlhaeuser <muesli@gmail.com>
+ */
+
+// Package jqtransformer provides a transformer evaluating jq expressions.
+package jqtransformer
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/itchyny/gojq"
+
+	"github.com/muesli/beehive/transformers"
+)
+
+// evalTimeout is the time after which the evaluation of an expression gets
+// aborted, e.g. for runaway expressions like "repeat(.)".
+const evalTimeout = time.Second
+
+// JQTransformer is a transformer evaluating jq expressions, like
+//
+//	.text | split(" ") | .[1:] | join(" ")
+//
+// against an event's placeholders, which form the input object ".". It
+// supports the entire jq language, see https://jqlang.github.io/jq/manual/.
+// Expressions producing several values, like ".first, .last", yield an array
+// of them. Numbers are always returned as float64.
+type JQTransformer struct {
+	cache sync.Map
+}
+
+// Name returns the name of this Transformer.
+func (transformer *JQTransformer) Name() string {
+	return "jq"
+}
+
+// Description returns the description of this Transformer.
+func (transformer *JQTransformer) Description() string {
+	return "This transformer reshapes placeholders with jq expressions"
+}
+
+// Transform evaluates expr against data.
+func (transformer *JQTransformer) Transform(data map[string]interface{}, expr string) (interface{}, error) {
+	code, err := transformer.compile(expr)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), evalTimeout)
+	defer cancel()
+
+	var values []interface{}
+	iter := code.RunWithContext(ctx, normalize(data))
+	for {
+		v, ok := iter.Next()
+		if !ok {
+			break
+		}
+		if err, ok := v.(error); ok {
+			return nil, err
+		}
+		values = append(values, denormalize(v))
+	}
+
+	switch len(values) {
+	case 0:
+		return nil, nil
+	case 1:
+		return values[0], nil
+	}
+	return values, nil
+}
+
+// Validate checks an expression for syntax errors, without evaluating it.
+func (transformer *JQTransformer) Validate(expr string) error {
+	_, err := transformer.compile(expr)
+	return err
+}
+
+// compile parses and compiles an expression, memoizing the result.
+func (transformer *JQTransformer) compile(expr string) (*gojq.Code, error) {
+	if code, ok := transformer.cache.Load(expr); ok {
+		return code.(*gojq.Code), nil
+	}
+
+	q, err := gojq.Parse(expr)
+	if err != nil {
+		return nil, err
+	}
+	code, err := gojq.Compile(q)
+	if err != nil {
+		return nil, err
+	}
+	transformer.cache.Store(expr, code)
+	return code, nil
+}
+
+// normalize converts a placeholder value to the JSON types jq operates on.
+func normalize(v interface{}) interface{} {
+	switch v := v.(type) {
+	case nil, bool, float64, string:
+		return v
+	case map[string]interface{}:
+		r := make(map[string]interface{}, len(v))
+		for k, el := range v {
+			r[k] = normalize(el)
+		}
+		return r
+	case []interface{}:
+		r := make([]interface{}, len(v))
+		for i, el := range v {
+			r[i] = normalize(el)
+		}
+		return r
+	case []byte:
+		return string(v)
+	}
+
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Bool:
+		return rv.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(rv.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return float64(rv.Uint())
+	case reflect.Float32, reflect.Float64:
+		return rv.Float()
+	case reflect.String:
+		return rv.String()
+	case reflect.Slice, reflect.Array:
+		r := make([]interface{}, rv.Len())
+		for i := range r {
+			r[i] = normalize(rv.Index(i).Interface())
+		}
+		return r
+	case reflect.Map:
+		if rv.Type().Key().Kind() == reflect.String {
+			r := make(map[string]interface{}, rv.Len())
+			iter := rv.MapRange()
+			for iter.Next() {
+				r[iter.Key().String()] = normalize(iter.Value().Interface())
+			}
+			return r
+		}
+	case reflect.Ptr, reflect.Interface:
+		if rv.IsNil() {
+			return nil
+		}
+	}
+
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	var r interface{}
+	if err := json.Unmarshal(b, &r); err != nil {
+		return fmt.Sprint(v)
+	}
+	return r
+}
+
+// denormalize converts the integers jq results may contain to float64, the
+// type of all other numbers.
+func denormalize(v interface{}) interface{} {
+	switch v := v.(type) {
+	case int:
+		return float64(v)
+	case *big.Int:
+		f, _ := new(big.Float).SetInt(v).Float64()
+		return f
+	case map[string]interface{}:
+		for k, el := range v {
+			v[k] = denormalize(el)
+		}
+	case []interface{}:
+		for i, el := range v {
+			v[i] = denormalize(el)
+		}
+	}
+	return v
+}
+
+func init() {
+	t := JQTransformer{}
+
+	transformers.RegisterTransformer(&t)
+}
@@ -0,0 +1,131 @@
+/*
+ *    Copyright (C) 2026 Christian Muehlhaeuser
+ *
+ *    This program is free software: you can redistribute it and/or modify
+ *    it under the terms of the GNU Affero General Public License as published
+ *    by the Free Software Foundation, either version 3 of the License, or
+ *    (at your option) any later version.
+ *
+ *    This program is distributed in the hope that it will be useful,
+ *    but WITHOUT ANY WARRANTY; without even the implied warranty of
+ *    MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ *    GNU Affero General Public License for more details.
+ *
+ *    You should have received a copy of the GNU Affero General Public License
+ *    along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ *    Authors:
+ *      Christian Muehlhaeuser <muesli@gmail.com>
+ */
+
+package jqtransformer
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestJQTransformer(t *testing.T) {
+	tr := JQTransformer{}
+
+	o := map[string]interface{}{
+		"text":  "deploy beehive to prod",
+		"count": 3,
+		"tags":  []string{"release", "prod", "release"},
+		"user":  map[string]interface{}{"name": "muesli", "id": 42},
+		"items": []interface{}{
+			map[string]interface{}{"name": "a", "price": 1.5},
+			map[string]interface{}{"name": "b", "price": 3},
+		},
+		"time": time.Date(2026, 10, 14, 12, 0, 0, 0, time.UTC),
+	}
+
+	tests := []struct {
+		expr  string
+		value interface{}
+	}{
+		{`.text`, "deploy beehive to prod"},
+		{`.user.name`, "muesli"},
+		{`."user"["id"]`, float64(42)},
+		{`.missing.name`, nil},
+		{`.tags[0]`, "release"},
+		{`.tags[-1]`, "release"},
+		{`.tags[1:]`, []interface{}{"prod", "release"}},
+		{`.text[0:6]`, "deploy"},
+		{`.text | split(" ") | .[1]`, "beehive"},
+		{`.text | split(" ") | .[2:] | join(" ")`, "to prod"},
+		{`.tags | unique | length`, float64(2)},
+		{`.items | map(.price) | add`, 4.5},
+		{`.items | map(select(.price > 2) | .name)`, []interface{}{"b"}},
+		{`.count * 2 + 1`, float64(7)},
+		{`.count % 2 == 1 and (.count > 2 or false)`, true},
+		{`-.count`, float64(-3)},
+		{`.missing // "default"`, "default"},
+		{`if .count > 5 then "many" elif .count > 1 then "some" else "one" end`, "some"},
+		{`{name: .user.name, n: .count, "tag": .tags[1], user}`, map[string]interface{}{
+			"name": "muesli", "n": float64(3), "tag": "prod",
+			"user": map[string]interface{}{"name": "muesli", "id": float64(42)},
+		}},
+		{`[.user.name, .count | tostring] | join("-")`, "muesli-3"},
+		{`"v" + (.count | tostring) | ascii_upcase`, "V3"},
+		{`.text | sub("(?<a>\\w+) (?<b>\\w+)"; "\(.b) \(.a)")`, "beehive deploy to prod"},
+		{`.text | gsub("e"; "E")`, "dEploy bEEhivE to prod"},
+		{`.text | test("^deploy")`, true},
+		{`"42" | tonumber`, float64(42)},
+		{`.user | keys`, []interface{}{"id", "name"}},
+		{`.user | has("id")`, true},
+		{`.tags | contains(["prod"])`, true},
+		{`.user | tojson`, `{"id":42,"name":"muesli"}`},
+		{`"[1,2]" | fromjson | reverse`, []interface{}{float64(2), float64(1)}},
+		{`.time`, "2026-10-14T12:00:00Z"},
+		{`select(.count > 5)`, nil},
+		{`.tags | first, last`, []interface{}{"release", "release"}},
+		{`[.items[] | .price] | max`, float64(3)},
+		{`.items | map({(.name): .price}) | add`, map[string]interface{}{"a": 1.5, "b": float64(3)}},
+		{`reduce .tags[] as $t ({}; .[$t] += 1)`, map[string]interface{}{"release": float64(2), "prod": float64(1)}},
+	}
+	for _, test := range tests {
+		v, err := tr.Transform(o, test.expr)
+		if err != nil {
+			t.Errorf("Unexpected error evaluating %s: %v", test.expr, err)
+			continue
+		}
+		if !reflect.DeepEqual(v, test.value) {
+			t.Errorf("Expected %s to evaluate to %#v, got %#v", test.expr, test.value, v)
+		}
+	}
+}
+
+func TestJQTransformerErrors(t *testing.T) {
+	tr := JQTransformer{}
+	o := map[string]interface{}{"text": "hello", "count": 1}
+
+	for _, expr := range []string{
+		`.text |`,
+		`(.text`,
+		`.text == "hello`,
+		`unknown(.text)`,
+		`split`,
+		`if .count then 1`,
+		`.text.[`,
+		`{1: 2}`,
+	} {
+		if err := tr.Validate(expr); err == nil {
+			t.Errorf("Expected %s to be invalid", expr)
+		}
+	}
+
+	for _, expr := range []string{
+		`.text - 1`,
+		`.count | split(",")`,
+		`.text | keys`,
+		`.count / 0`,
+		`.text | tonumber`,
+		`.text | test("(")`,
+	} {
+		if _, err := tr.Transform(o, expr); err == nil {
+			t.Errorf("Expected %s to fail", expr)
+		}
+	}
+}
@@ -0,0 +1,88 @@
+/*
+ *    Copyright (C) 2026 Christian Muehlhaeuser
+ *
+ *    This program is free software: you can redistribute it and/or modify
+ *    it under the terms of the GNU Affero General Public License as published
+ *    by the Free Software Foundation, either version 3 of the License, or
+ *    (at your option) any later version.
+ *
+ *    This program is distributed in the hope that it will be useful,
+ *    but WITHOUT ANY WARRANTY; without even the implied warranty of
+ *    MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ *    GNU Affero General Public License for more details.
+ *
+ *    You should have received a copy of the GNU Affero General Public License
+ *    along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ *    Authors:
+ *      Christian Muehlhaeuser <muesli@gmail.com>
+ */
+
+// Package transformers contains Beehive's event transformation system.
+package transformers
+
+import (
+	"sort"
+	"sync"
+)
+
+// TransformerInterface is an interface all Transformers implement.
+// Transformers get registered with RegisterTransformer, usually from an init
+// function, and compute placeholders for a chain's actions from an event's
+// placeholders, e.g. by splitting a string or picking an array element.
+type TransformerInterface interface {
+	// Name of the transformer
+	Name() string
+	// Description of the transformer
+	Description() string
+
+	// Transform evaluates expr against data, the placeholders of an event,
+	// and returns the resulting value. A nil value without an error means
+	// the expression didn't produce any value
+	Transform(data map[string]interface{}, expr string) (interface{}, error)
+	// Validate checks expr for syntax errors, without evaluating it
+	Validate(expr string) error
+}
+
+var (
+	transformers     = make(map[string]*TransformerInterface)
+	transformersLock sync.RWMutex
+)
+
+// RegisterTransformer gets called by Transformers to register themselves. A
+// transformer replaces any transformer previously registered with the same
+// name.
+func RegisterTransformer(transformer TransformerInterface) {
+	transformersLock.Lock()
+	defer transformersLock.Unlock()
+
+	transformers[transformer.Name()] = &transformer
+}
+
+// GetTransformer returns a transformer with a specific name
+func GetTransformer(identifier string) *TransformerInterface {
+	transformersLock.RLock()
+	defer transformersLock.RUnlock()
+
+	transformer, ok := transformers[identifier]
+	if ok {
+		return transformer
+	}
+
+	return nil
+}
+
+// GetTransformers returns all registered transformers, sorted by name
+func GetTransformers() []*TransformerInterface {
+	transformersLock.RLock()
+	r := make([]*TransformerInterface, 0, len(transformers))
+	for _, transformer := range transformers {
+		r = append(r, transformer)
+	}
+	transformersLock.RUnlock()
+
+	sort.Slice(r, func(i, j int) bool {
+		return (*r[i]).Name() < (*r[j]).Name()
+	})
+	return r
+}
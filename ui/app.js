@@ -0,0 +1,499 @@
+/*
+ * Beehive's built-in admin interface. It talks to the REST API under /v1
+ * and follows the activity stream for live events. Paths are relative, so
+ * the interface also works when served behind a reverse proxy.
+ */
+'use strict';
+
+const state = {
+	hives: {},
+	bees: [],
+	chains: [],
+	actions: {},
+	editing: null
+};
+
+const streamLimit = 200;
+
+// el creates a DOM element. Children may be strings, nodes or arrays of
+// both; strings are always inserted as text, never as HTML.
+function el(tag, attrs, ...children) {
+	const e = document.createElement(tag);
+	for (const [k, v] of Object.entries(attrs || {})) {
+		if (k.startsWith('on')) {
+			e.addEventListener(k.slice(2), v);
+		} else if (v === true) {
+			e.setAttribute(k, '');
+		} else if (v !== false && v !== null && v !== undefined) {
+			e.setAttribute(k, v);
+		}
+	}
+	for (const c of children.flat()) {
+		if (c !== null && c !== undefined) {
+			e.append(c instanceof Node ? c : String(c));
+		}
+	}
+	return e;
+}
+
+async function api(method, path, body) {
+	const opts = {method: method, headers: {}};
+	if (body !== undefined) {
+		opts.headers['Content-Type'] = 'application/json';
+		opts.body = JSON.stringify(body);
+	}
+
+	const r = await fetch('../v1/' + path, opts);
+	const data = await r.json().catch(() => ({}));
+	if (!r.ok) {
+		const msg = data.errors ? data.errors.map(e => e.detail).join('; ') : r.statusText;
+		throw new Error(msg);
+	}
+	return data;
+}
+
+function showError(err) {
+	const box = document.getElementById('error');
+	box.textContent = err ? err.message || String(err) : '';
+	box.hidden = !err;
+}
+
+function formatTime(t) {
+	if (!t || t.startsWith('0001-')) {
+		return 'never';
+	}
+	return new Date(t).toLocaleString();
+}
+
+function beeByName(name) {
+	return state.bees.find(b => b.name === name);
+}
+
+function hiveOf(bee) {
+	return bee ? state.hives[bee.namespace] : null;
+}
+
+async function load() {
+	try {
+		const [hives, bees, chains, actions] = await Promise.all([
+			api('GET', 'hives'),
+			api('GET', 'bees'),
+			api('GET', 'chains'),
+			api('GET', 'actions')
+		]);
+
+		state.hives = {};
+		for (const h of hives.hives || []) {
+			state.hives[h.id] = h;
+		}
+		state.bees = bees.bees || [];
+		state.chains = chains.chains || [];
+		state.actions = {};
+		for (const a of actions.actions || []) {
+			state.actions[a.id] = a;
+		}
+
+		renderBees();
+		renderChains();
+		fillBeeSelect(document.querySelector('#fire-form select[name=bee]'), 'events');
+		showError(null);
+	} catch (err) {
+		showError(err);
+	}
+}
+
+/* Bees */
+
+function renderBees() {
+	const list = document.getElementById('bee-list');
+	list.replaceChildren(...state.bees.map(bee => {
+		const hive = hiveOf(bee);
+		return el('tr', {},
+			el('td', {}, hive ? el('img', {src: '../images/' + hive.image, alt: '', style: 'background:' + hive.logocolor}) : null),
+			el('td', {title: bee.description}, bee.name),
+			el('td', {}, hive ? hive.name : bee.namespace),
+			el('td', {}, bee.paused
+				? el('span', {class: 'state state-paused'}, 'paused')
+				: el('span', {class: 'state state-' + bee.state}, bee.state)),
+			el('td', {}, formatTime(bee.lastevent)),
+			el('td', {}, formatTime(bee.lastaction)),
+			el('td', {},
+				el('button', {type: 'button', onclick: () => toggleBee(bee)}, bee.active ? 'Stop' : 'Start'),
+				el('button', {type: 'button', onclick: () => pauseBee(bee)}, bee.paused ? 'Resume' : 'Pause'))
+		);
+	}));
+}
+
+function toggleBee(bee) {
+	return updateBee(bee, {active: !bee.active});
+}
+
+function pauseBee(bee) {
+	return updateBee(bee, {paused: !bee.paused});
+}
+
+async function updateBee(bee, changes) {
+	try {
+		await api('PUT', 'bees/' + encodeURIComponent(bee.name), {
+			bee: Object.assign({
+				name: bee.name,
+				namespace: bee.namespace,
+				description: bee.description,
+				active: bee.active,
+				options: bee.options
+			}, changes)
+		});
+		await load();
+	} catch (err) {
+		showError(err);
+	}
+}
+
+/* Descriptor driven forms */
+
+function fillBeeSelect(select, kind) {
+	const current = select.value;
+	select.replaceChildren(el('option', {value: ''}, '-'), ...state.bees
+		.filter(b => (b[kind] || []).length > 0)
+		.map(b => el('option', {value: b.name}, b.name)));
+	select.value = current;
+}
+
+function fillDescriptorSelect(select, bee, kind) {
+	const current = select.value;
+	const descs = bee ? bee[kind] || [] : [];
+	select.replaceChildren(el('option', {value: ''}, '-'),
+		...descs.map(d => el('option', {value: d.Name, title: d.Description}, d.Name)));
+	select.value = current;
+}
+
+function descriptor(bee, kind, name) {
+	return bee ? (bee[kind] || []).find(d => d.Name === name) : null;
+}
+
+// optionInputs renders an input for every placeholder of a descriptor,
+// pre-filled with values.
+function optionInputs(desc, values) {
+	return (desc ? desc.Options || [] : []).map(o => {
+		const v = values ? values[o.Name] : undefined;
+		let input;
+		if (o.Type === 'bool') {
+			input = el('input', {type: 'checkbox', name: o.Name, 'data-type': o.Type, checked: v === true || v === 'true'});
+		} else if (o.Type === 'string' && o.Name.match(/text|body|message|html/)) {
+			input = el('textarea', {name: o.Name, 'data-type': o.Type, rows: 2, required: o.Mandatory});
+			input.value = v === undefined ? '' : v;
+		} else {
+			input = el('input', {name: o.Name, 'data-type': o.Type, required: o.Mandatory});
+			input.value = v === undefined ? '' : Array.isArray(v) ? v.join(',') : v;
+		}
+		return el('label', {class: 'option', title: o.Type},
+			o.Name + (o.Mandatory ? ' *' : ''), input, el('small', {}, o.Description));
+	});
+}
+
+// optionValues collects the values of the inputs created by optionInputs
+// as placeholders. Empty values get skipped.
+function optionValues(container) {
+	const opts = [];
+	for (const input of container.querySelectorAll('[data-type]')) {
+		const type = input.dataset.type;
+		let value;
+		if (type === 'bool') {
+			value = input.checked;
+		} else if (input.value === '') {
+			continue;
+		} else if ((type === 'int' || type === 'float64') && !isNaN(Number(input.value))) {
+			value = Number(input.value);
+		} else {
+			value = input.value;
+		}
+		opts.push({Name: input.name, Type: type, Value: value});
+	}
+	return opts;
+}
+
+/* Chains */
+
+function renderChains() {
+	const list = document.getElementById('chain-list');
+	list.replaceChildren(...state.chains.map(c => el('tr', {},
+		el('td', {title: c.description}, c.name),
+		el('td', {}, c.event ? c.event.Bee + ' / ' + c.event.Name : ''),
+		el('td', {}, (c.actions || []).map(id => {
+			const a = state.actions[id];
+			return el('div', {}, a ? a.bee + ' / ' + a.name : id);
+		})),
+		el('td', {}, c.stats ? c.stats.Triggered : 0),
+		el('td', {}, c.enabled ? 'yes' : 'no'),
+		el('td', {},
+			el('button', {type: 'button', onclick: () => editChain(c)}, 'Edit'),
+			el('button', {type: 'button', onclick: () => deleteChain(c)}, 'Delete'))
+	)));
+}
+
+function updatePlaceholderHint(bee, event) {
+	const desc = descriptor(bee, 'events', event);
+	const names = desc ? (desc.Options || []).map(o => '{{.' + o.Name + '}}') : [];
+	document.getElementById('chain-placeholders').textContent =
+		names.length ? 'Available in filters and actions: ' + names.join(' ') : '';
+}
+
+function actionRow(action) {
+	const beeSelect = el('select', {name: 'bee', required: true});
+	const actionSelect = el('select', {name: 'action', required: true});
+	const options = el('div', {class: 'options'});
+	const row = el('div', {class: 'action'},
+		el('div', {class: 'row'},
+			el('label', {}, 'Bee ', beeSelect),
+			el('label', {}, 'Action ', actionSelect),
+			el('button', {type: 'button', onclick: () => row.remove()}, 'Remove')),
+		options);
+	row.dataset.id = action ? action.id : '';
+
+	const values = {};
+	for (const o of action ? action.options || [] : []) {
+		values[o.Name] = o.Value;
+	}
+
+	fillBeeSelect(beeSelect, 'actions');
+	beeSelect.value = action ? action.bee : '';
+	const update = () => {
+		const bee = beeByName(beeSelect.value);
+		fillDescriptorSelect(actionSelect, bee, 'actions');
+		options.replaceChildren(...optionInputs(descriptor(bee, 'actions', actionSelect.value), values));
+	};
+	beeSelect.addEventListener('change', () => {
+		actionSelect.value = '';
+		update();
+	});
+	actionSelect.addEventListener('change', update);
+	update();
+	actionSelect.value = action ? action.name : '';
+	update();
+
+	return row;
+}
+
+function editChain(chain) {
+	const form = document.getElementById('chain-form');
+	form.reset();
+	state.editing = chain ? chain.name : null;
+	document.getElementById('chain-form-title').textContent = chain ? 'Edit chain ' + chain.name : 'New chain';
+
+	form.elements.name.value = chain ? chain.name : '';
+	form.elements.description.value = chain ? chain.description : '';
+	form.elements.filters.value = chain ? (chain.filters || []).join('\n') : '';
+
+	fillBeeSelect(form.elements.bee, 'events');
+	form.elements.bee.value = chain && chain.event ? chain.event.Bee : '';
+	fillDescriptorSelect(form.elements.event, beeByName(form.elements.bee.value), 'events');
+	form.elements.event.value = chain && chain.event ? chain.event.Name : '';
+	updatePlaceholderHint(beeByName(form.elements.bee.value), form.elements.event.value);
+
+	document.getElementById('chain-actions').replaceChildren(
+		...(chain ? chain.actions || [] : []).map(id => actionRow(state.actions[id] || {id: id})));
+
+	form.hidden = false;
+	form.scrollIntoView();
+}
+
+async function saveChain(ev) {
+	ev.preventDefault();
+	const form = ev.target;
+
+	try {
+		const ids = [];
+		for (const row of document.querySelectorAll('#chain-actions .action')) {
+			const body = {
+				action: {
+					bee: row.querySelector('select[name=bee]').value,
+					name: row.querySelector('select[name=action]').value,
+					options: optionValues(row.querySelector('.options'))
+				}
+			};
+			const res = row.dataset.id ?
+				await api('PUT', 'actions/' + encodeURIComponent(row.dataset.id), body) :
+				await api('POST', 'actions', body);
+			row.dataset.id = res.actions[0].id;
+			ids.push(row.dataset.id);
+		}
+
+		const body = {
+			chain: {
+				name: form.elements.name.value,
+				description: form.elements.description.value,
+				event: {Bee: form.elements.bee.value, Name: form.elements.event.value},
+				filters: form.elements.filters.value.split('\n').map(f => f.trim()).filter(f => f),
+				actions: ids
+			}
+		};
+		if (state.editing) {
+			await api('PUT', 'chains/' + encodeURIComponent(state.editing), body);
+		} else {
+			await api('POST', 'chains', body);
+		}
+
+		form.hidden = true;
+		await load();
+	} catch (err) {
+		showError(err);
+	}
+}
+
+async function deleteChain(chain) {
+	if (!confirm('Delete chain ' + chain.name + '?')) {
+		return;
+	}
+	try {
+		await api('DELETE', 'chains/' + encodeURIComponent(chain.name));
+		await load();
+	} catch (err) {
+		showError(err);
+	}
+}
+
+/* Live events */
+
+let stream = null;
+
+function streamEntry(type, msg) {
+	let summary;
+	switch (type) {
+	case 'event':
+		summary = msg.event.Bee + ' / ' + msg.event.Name;
+		break;
+	case 'chain':
+		summary = 'chain ' + msg.chain + ' executed in ' + (msg.duration / 1e6).toFixed(1) + 'ms';
+		break;
+	case 'action':
+		summary = 'action ' + msg.action.Bee + ' / ' + msg.action.Name + (msg.chain ? ' of chain ' + msg.chain : '');
+		break;
+	}
+
+	return el('li', {class: 'stream-' + type + (msg.error ? ' failed' : '')},
+		el('details', {},
+			el('summary', {},
+				el('time', {}, new Date(msg.time).toLocaleTimeString()), ' ',
+				el('strong', {}, type), ' ', summary,
+				msg.error ? el('span', {class: 'error'}, ' ' + msg.error) : null),
+			el('pre', {}, JSON.stringify(msg, null, 2))));
+}
+
+function startStream() {
+	if (stream) {
+		return;
+	}
+
+	const status = document.getElementById('stream-status');
+	const list = document.getElementById('stream');
+	stream = new EventSource('../stream');
+	stream.onopen = () => status.textContent = 'connected';
+	stream.onerror = () => status.textContent = 'reconnecting...';
+
+	for (const type of ['event', 'chain', 'action']) {
+		stream.addEventListener(type, e => {
+			if (document.getElementById('stream-pause').checked) {
+				return;
+			}
+			list.prepend(streamEntry(type, JSON.parse(e.data)));
+			while (list.children.length > streamLimit) {
+				list.lastChild.remove();
+			}
+		});
+	}
+}
+
+/* Test events */
+
+function updateFireOptions() {
+	const form = document.getElementById('fire-form');
+	const bee = beeByName(form.elements.bee.value);
+	fillDescriptorSelect(form.elements.event, bee, 'events');
+	document.getElementById('fire-options').replaceChildren(
+		...optionInputs(descriptor(bee, 'events', form.elements.event.value)));
+}
+
+async function fireEvent(ev) {
+	ev.preventDefault();
+	const form = ev.target;
+	const result = document.getElementById('fire-result');
+
+	try {
+		const res = await api('POST', 'events', {
+			event: {
+				bee: form.elements.bee.value,
+				name: form.elements.event.value,
+				options: optionValues(document.getElementById('fire-options'))
+			},
+			dryrun: form.elements.dryrun.checked
+		});
+
+		const injected = res.injected || {};
+		const chains = injected.chains || [];
+		result.replaceChildren(
+			el('h3', {}, 'Event ' + injected.id),
+			el('p', {}, chains.length ? 'Matched chains: ' + chains.join(', ') : 'No chain matched the event'),
+			(injected.actions || []).length ? el('table', {},
+				el('thead', {}, el('tr', {}, el('th', {}, 'Chain'), el('th', {}, 'Action'), el('th', {}, 'Options'))),
+				el('tbody', {}, injected.actions.map(a => el('tr', {},
+					el('td', {}, a.chain),
+					el('td', {}, a.bee + ' / ' + a.name),
+					el('td', {}, el('pre', {}, (a.options || []).map(o => o.Name + ': ' + JSON.stringify(o.Value)).join('\n'))))))
+			) : null);
+		showError(null);
+	} catch (err) {
+		showError(err);
+	}
+}
+
+/* Navigation */
+
+function showView() {
+	const view = (location.hash || '#bees').slice(1);
+	for (const section of document.querySelectorAll('.view')) {
+		section.hidden = section.id !== view;
+	}
+	for (const a of document.querySelectorAll('nav a')) {
+		a.classList.toggle('active', a.dataset.view === view);
+	}
+	if (view === 'events') {
+		startStream();
+	}
+}
+
+document.addEventListener('DOMContentLoaded', () => {
+	const chainForm = document.getElementById('chain-form');
+	chainForm.addEventListener('submit', saveChain);
+	chainForm.elements.bee.addEventListener('change', () => {
+		const bee = beeByName(chainForm.elements.bee.value);
+		fillDescriptorSelect(chainForm.elements.event, bee, 'events');
+		updatePlaceholderHint(bee, chainForm.elements.event.value);
+	});
+	chainForm.elements.event.addEventListener('change', () => {
+		updatePlaceholderHint(beeByName(chainForm.elements.bee.value), chainForm.elements.event.value);
+	});
+	document.getElementById('chain-new').addEventListener('click', () => editChain(null));
+	document.getElementById('chain-cancel').addEventListener('click', () => chainForm.hidden = true);
+	document.getElementById('chain-add-action').addEventListener('click', () => {
+		document.getElementById('chain-actions').append(actionRow(null));
+	});
+
+	const fireForm = document.getElementById('fire-form');
+	fireForm.addEventListener('submit', fireEvent);
+	fireForm.elements.bee.addEventListener('change', () => {
+		fireForm.elements.event.value = '';
+		updateFireOptions();
+	});
+	fireForm.elements.event.addEventListener('change', updateFireOptions);
+
+	document.getElementById('stream-clear').addEventListener('click', () => {
+		document.getElementById('stream').replaceChildren();
+	});
+	for (const button of document.querySelectorAll('.reload')) {
+		button.addEventListener('click', load);
+	}
+
+	window.addEventListener('hashchange', showView);
+	showView();
+	load();
+});